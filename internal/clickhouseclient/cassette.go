@@ -0,0 +1,332 @@
+package clickhouseclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/pingcap/errors"
+)
+
+const (
+	// CassetteModeEnv selects the cassette mode, either CassetteModeRecord or CassetteModeReplay.
+	// When unset, queries are run against the server as usual.
+	CassetteModeEnv = "CLICKHOUSEDBOPS_CASSETTE_MODE"
+	// CassettePathEnv is the path of the cassette file to write when recording, or to read when replaying.
+	CassettePathEnv = "CLICKHOUSEDBOPS_CASSETTE"
+
+	CassetteModeRecord = "record"
+	CassetteModeReplay = "replay"
+)
+
+// CassetteConfig tells whether queries are recorded to or replayed from a cassette file.
+type CassetteConfig struct {
+	Mode string
+	Path string
+}
+
+// CassetteConfigFromEnv returns the cassette configuration set in the environment, or nil if there is none.
+func CassetteConfigFromEnv() (*CassetteConfig, error) {
+	mode := os.Getenv(CassetteModeEnv)
+	if mode == "" {
+		return nil, nil
+	}
+
+	if mode != CassetteModeRecord && mode != CassetteModeReplay {
+		return nil, errors.New(fmt.Sprintf("invalid %s %q, must be either %q or %q", CassetteModeEnv, mode, CassetteModeRecord, CassetteModeReplay))
+	}
+
+	path := os.Getenv(CassettePathEnv)
+	if path == "" {
+		return nil, errors.New(fmt.Sprintf("%s is required when %s is set", CassettePathEnv, CassetteModeEnv))
+	}
+
+	return &CassetteConfig{Mode: mode, Path: path}, nil
+}
+
+// cassette holds the queries run against the server and what they returned, in the order they were run.
+type cassette struct {
+	Interactions []interaction `json:"interactions"`
+}
+
+type interaction struct {
+	// Exec tells if the query was run with Exec rather than Select.
+	Exec       bool                     `json:"exec,omitempty"`
+	Query      string                   `json:"query"`
+	Parameters map[string]string        `json:"parameters,omitempty"`
+	Rows       []map[string]cassetteVal `json:"rows,omitempty"`
+	Error      string                   `json:"error,omitempty"`
+}
+
+// cassetteVal is a Row field along with its type, so that replayed rows are read back the same way.
+type cassetteVal struct {
+	Type  string  `json:"type"`
+	Value *string `json:"value"`
+}
+
+func (i interaction) key() string {
+	// json.Marshal sorts map keys, so that the same parameters always give the same key.
+	params, _ := json.Marshal(i.Parameters)
+	return fmt.Sprintf("%t\x00%s\x00%s", i.Exec, i.Query, params)
+}
+
+func encodeRow(row Row) (map[string]cassetteVal, error) {
+	ret := make(map[string]cassetteVal, len(row.data))
+	for name, val := range row.data {
+		var v cassetteVal
+		switch val := val.(type) {
+		case string:
+			v = cassetteVal{Type: "String", Value: &val}
+		case *string:
+			v = cassetteVal{Type: "Nullable(String)", Value: val}
+		case bool:
+			s := strconv.FormatBool(val)
+			v = cassetteVal{Type: "Bool", Value: &s}
+		case uint8:
+			s := strconv.FormatUint(uint64(val), 10)
+			v = cassetteVal{Type: "UInt8", Value: &s}
+		case uint64:
+			s := strconv.FormatUint(val, 10)
+			v = cassetteVal{Type: "UInt64", Value: &s}
+		default:
+			return nil, errors.New(fmt.Sprintf("unsupported type %T of field %s", val, name))
+		}
+		ret[name] = v
+	}
+
+	return ret, nil
+}
+
+func decodeRow(fields map[string]cassetteVal) (Row, error) {
+	ret := Row{}
+	for name, v := range fields {
+		if v.Type == "Nullable(String)" {
+			ret.Set(name, v.Value)
+			continue
+		}
+
+		if v.Value == nil {
+			return Row{}, errors.New(fmt.Sprintf("field %s of type %s has no value", name, v.Type))
+		}
+
+		switch v.Type {
+		case "String":
+			ret.Set(name, *v.Value)
+		case "Bool":
+			val, err := strconv.ParseBool(*v.Value)
+			if err != nil {
+				return Row{}, errors.WithMessage(err, fmt.Sprintf("invalid value of field %s", name))
+			}
+			ret.Set(name, val)
+		case "UInt8":
+			val, err := strconv.ParseUint(*v.Value, 10, 8)
+			if err != nil {
+				return Row{}, errors.WithMessage(err, fmt.Sprintf("invalid value of field %s", name))
+			}
+			ret.Set(name, uint8(val))
+		case "UInt64":
+			val, err := strconv.ParseUint(*v.Value, 10, 64)
+			if err != nil {
+				return Row{}, errors.WithMessage(err, fmt.Sprintf("invalid value of field %s", name))
+			}
+			ret.Set(name, val)
+		default:
+			return Row{}, errors.New(fmt.Sprintf("unsupported type %s of field %s", v.Type, name))
+		}
+	}
+
+	return ret, nil
+}
+
+// NewRecordingClient wraps client so that every query and its result are written to the cassette file at path.
+// The file is overwritten, and saved after every query as the provider is never told when terraform is done.
+func NewRecordingClient(client ClickhouseClient, path string) (ClickhouseClient, error) {
+	c := &recordingClient{
+		client: client,
+		path:   path,
+	}
+
+	// Fail early rather than on the first query if the file can't be written.
+	if err := c.save(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+type recordingClient struct {
+	client ClickhouseClient
+	path   string
+
+	mu       sync.Mutex
+	cassette cassette
+}
+
+func (c *recordingClient) Select(ctx context.Context, qry string, callback func(Row) error) error {
+	rec := interaction{
+		Query:      RedactSecrets(qry),
+		Parameters: maps.Clone(parametersFromContext(ctx)),
+		Rows:       make([]map[string]cassetteVal, 0),
+	}
+
+	err := c.client.Select(ctx, qry, func(row Row) error {
+		fields, err := encodeRow(row)
+		if err != nil {
+			return errors.WithMessage(err, "error recording row")
+		}
+		rec.Rows = append(rec.Rows, fields)
+
+		return callback(row)
+	})
+	if err != nil {
+		rec.Error = err.Error()
+	}
+
+	if saveErr := c.record(rec); saveErr != nil {
+		return saveErr
+	}
+
+	return err
+}
+
+func (c *recordingClient) Exec(ctx context.Context, qry string) error {
+	rec := interaction{
+		Exec:       true,
+		Query:      RedactSecrets(qry),
+		Parameters: maps.Clone(parametersFromContext(ctx)),
+	}
+
+	err := c.client.Exec(ctx, qry)
+	if err != nil {
+		rec.Error = err.Error()
+	}
+
+	if saveErr := c.record(rec); saveErr != nil {
+		return saveErr
+	}
+
+	return err
+}
+
+func (c *recordingClient) record(rec interaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cassette.Interactions = append(c.cassette.Interactions, rec)
+
+	return c.save()
+}
+
+// save writes the cassette file, c.mu must be held unless c is not shared yet.
+func (c *recordingClient) save() error {
+	if c.cassette.Interactions == nil {
+		c.cassette.Interactions = make([]interaction, 0)
+	}
+
+	data, err := json.MarshalIndent(c.cassette, "", "  ")
+	if err != nil {
+		return errors.WithMessage(err, "error encoding cassette")
+	}
+
+	if err := os.WriteFile(c.path, data, 0o600); err != nil {
+		return errors.WithMessage(err, "error writing cassette")
+	}
+
+	return nil
+}
+
+// NewReplayClient returns a client answering queries with the results recorded in the cassette file at path,
+// without connecting to any server.
+// Terraform runs resources in parallel, so queries are not matched by position in the cassette: every run of a
+// query gets the next result recorded for the same query and parameters, and the last one once they are all used.
+func NewReplayClient(path string) (ClickhouseClient, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error reading cassette")
+	}
+
+	var cas cassette
+	if err := json.Unmarshal(data, &cas); err != nil {
+		return nil, errors.WithMessage(err, "error decoding cassette")
+	}
+
+	c := &replayClient{
+		interactions: make(map[string][]interaction),
+		next:         make(map[string]int),
+	}
+	for _, rec := range cas.Interactions {
+		c.interactions[rec.key()] = append(c.interactions[rec.key()], rec)
+	}
+
+	return c, nil
+}
+
+type replayClient struct {
+	mu           sync.Mutex
+	interactions map[string][]interaction
+	next         map[string]int
+}
+
+func (c *replayClient) Select(ctx context.Context, qry string, callback func(Row) error) error {
+	rec, err := c.replay(ctx, false, qry)
+	if err != nil {
+		return err
+	}
+
+	for _, fields := range rec.Rows {
+		row, err := decodeRow(fields)
+		if err != nil {
+			return errors.WithMessage(err, "error replaying row")
+		}
+
+		err = callback(row)
+		if err != nil {
+			return errors.WithMessage(err, "error populating Row from query result")
+		}
+	}
+
+	if rec.Error != "" {
+		return errors.New(rec.Error)
+	}
+
+	return nil
+}
+
+func (c *replayClient) Exec(ctx context.Context, qry string) error {
+	rec, err := c.replay(ctx, true, qry)
+	if err != nil {
+		return err
+	}
+
+	if rec.Error != "" {
+		return errors.New(rec.Error)
+	}
+
+	return nil
+}
+
+func (c *replayClient) replay(ctx context.Context, exec bool, qry string) (*interaction, error) {
+	ctx = tflog.SetField(ctx, "Query", RedactSecrets(qry))
+	tflog.Debug(ctx, "Replaying Query")
+
+	// Secrets are left out of the recorded queries.
+	key := interaction{Exec: exec, Query: RedactSecrets(qry), Parameters: parametersFromContext(ctx)}.key()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	recs := c.interactions[key]
+	if len(recs) == 0 {
+		return nil, errors.New(fmt.Sprintf("query not found in cassette: %s", qry))
+	}
+
+	idx := min(c.next[key], len(recs)-1)
+	c.next[key] = idx + 1
+
+	return &recs[idx], nil
+}
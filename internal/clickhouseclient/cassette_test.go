@@ -0,0 +1,131 @@
+package clickhouseclient
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pingcap/errors"
+)
+
+// fakeClient answers every Select with rows and fails every Exec of failingQuery.
+type fakeClient struct {
+	rows         []Row
+	failingQuery string
+}
+
+func (f *fakeClient) Select(_ context.Context, _ string, callback func(Row) error) error {
+	for _, row := range f.rows {
+		if err := callback(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeClient) Exec(_ context.Context, qry string) error {
+	if qry == f.failingQuery {
+		return errors.New("code: 57, table already exists")
+	}
+
+	return nil
+}
+
+func Test_cassette_recordAndReplay(t *testing.T) {
+	engine := "Atomic"
+	row := Row{}
+	row.Set("name", "db1")
+	row.Set("engine", &engine)
+	row.Set("comment", (*string)(nil))
+	row.Set("is_temporary", uint8(1))
+	row.Set("total_rows", uint64(42))
+	row.Set("readonly", true)
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	ctx := WithParameters(context.Background(), map[string]string{"name": "db1"})
+
+	recorder, err := NewRecordingClient(&fakeClient{rows: []Row{row}, failingQuery: "CREATE TABLE t"}, path)
+	if err != nil {
+		t.Fatalf("NewRecordingClient() error = %v", err)
+	}
+
+	var recorded []Row
+	if err := recorder.Select(ctx, "SELECT 1", func(r Row) error { recorded = append(recorded, r); return nil }); err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	if err := recorder.Exec(context.Background(), "CREATE DATABASE db1"); err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+	if err := recorder.Exec(context.Background(), "CREATE TABLE t"); err == nil {
+		t.Fatalf("Exec() expected error")
+	}
+
+	replayer, err := NewReplayClient(path)
+	if err != nil {
+		t.Fatalf("NewReplayClient() error = %v", err)
+	}
+
+	var replayed []Row
+	if err := replayer.Select(ctx, "SELECT 1", func(r Row) error { replayed = append(replayed, r); return nil }); err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	if !reflect.DeepEqual(replayed, recorded) {
+		t.Errorf("Select() replayed = %v, want %v", replayed, recorded)
+	}
+
+	if err := replayer.Exec(context.Background(), "CREATE DATABASE db1"); err != nil {
+		t.Errorf("Exec() error = %v", err)
+	}
+	if err := replayer.Exec(context.Background(), "CREATE TABLE t"); err == nil || err.Error() != "code: 57, table already exists" {
+		t.Errorf("Exec() error = %v, want recorded error", err)
+	}
+
+	if err := replayer.Select(context.Background(), "SELECT 1", func(Row) error { return nil }); err == nil {
+		t.Errorf("Select() with different parameters expected error")
+	}
+	if err := replayer.Exec(context.Background(), "DROP DATABASE db1"); err == nil {
+		t.Errorf("Exec() of unrecorded query expected error")
+	}
+}
+
+func Test_replayClient_repeatsLastResult(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	client := &fakeClient{}
+	recorder, err := NewRecordingClient(client, path)
+	if err != nil {
+		t.Fatalf("NewRecordingClient() error = %v", err)
+	}
+
+	for _, name := range []string{"first", "second"} {
+		row := Row{}
+		row.Set("name", name)
+		client.rows = []Row{row}
+		if err := recorder.Select(context.Background(), "SELECT name", func(Row) error { return nil }); err != nil {
+			t.Fatalf("Select() error = %v", err)
+		}
+	}
+
+	replayer, err := NewReplayClient(path)
+	if err != nil {
+		t.Fatalf("NewReplayClient() error = %v", err)
+	}
+
+	var got []string
+	for range 3 {
+		err := replayer.Select(context.Background(), "SELECT name", func(r Row) error {
+			name, err := r.GetString("name")
+			got = append(got, name)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("Select() error = %v", err)
+		}
+	}
+
+	if want := []string{"first", "second", "second"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("replayed names = %v, want %v", got, want)
+	}
+}
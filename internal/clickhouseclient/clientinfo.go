@@ -0,0 +1,28 @@
+package clickhouseclient
+
+import (
+	"strings"
+)
+
+// ClientProduct is a name and version pair identifying the application running the queries. Products show up in
+// the client_name (native protocol) or http_user_agent (http protocol) columns of system.query_log and
+// system.processes, the most specific one first.
+type ClientProduct struct {
+	Name string
+	// Version is optional.
+	Version string
+}
+
+// userAgent renders products the way the User-Agent header expects them, e.g. "workspace terraform-provider/1.0".
+func userAgent(products []ClientProduct) string {
+	parts := make([]string, 0, len(products))
+	for _, p := range products {
+		if p.Version == "" {
+			parts = append(parts, p.Name)
+		} else {
+			parts = append(parts, p.Name+"/"+p.Version)
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
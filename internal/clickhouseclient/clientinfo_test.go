@@ -0,0 +1,34 @@
+package clickhouseclient
+
+import (
+	"testing"
+)
+
+func Test_userAgent(t *testing.T) {
+	tests := []struct {
+		name     string
+		products []ClientProduct
+		want     string
+	}{
+		{
+			name:     "No products",
+			products: nil,
+			want:     "",
+		},
+		{
+			name: "Products with and without version",
+			products: []ClientProduct{
+				{Name: "analytics-prod"},
+				{Name: "terraform-provider-clickhousedbops", Version: "1.2.0"},
+			},
+			want: "analytics-prod terraform-provider-clickhousedbops/1.2.0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := userAgent(tt.products); got != tt.want {
+				t.Errorf("userAgent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
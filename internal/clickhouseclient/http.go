@@ -16,16 +16,19 @@ import (
 )
 
 type httpClient struct {
-	client  *http.Client
-	baseUrl url.URL
+	client    *http.Client
+	baseUrl   url.URL
+	userAgent string
 }
 
 type HTTPClientConfig struct {
-	Protocol  string
-	Host      string
-	Port      uint16
-	BasicAuth *BasicAuth
-	TLSConfig *tls.Config
+	Protocol       string
+	Host           string
+	Port           uint16
+	BasicAuth      *BasicAuth
+	TLSConfig      *tls.Config
+	ConnectionPool ConnectionPoolConfig
+	ClientProducts []ClientProduct
 }
 
 func NewHTTPClient(config HTTPClientConfig) (ClickhouseClient, error) {
@@ -64,35 +67,38 @@ func NewHTTPClient(config HTTPClientConfig) (ClickhouseClient, error) {
 		}
 	}
 
+	transport := &http.Transport{
+		TLSClientConfig:     config.TLSConfig,
+		MaxConnsPerHost:     config.ConnectionPool.MaxOpenConns,
+		MaxIdleConnsPerHost: config.ConnectionPool.MaxIdleConns,
+	}
+
 	return &httpClient{
 		baseUrl: *baseUrl,
 		client: &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: config.TLSConfig,
-			},
+			Transport: transport,
 		},
+		userAgent: userAgent(config.ClientProducts),
 	}, nil
 }
 
 func (i *httpClient) Select(ctx context.Context, qry string, callback func(Row) error) error {
-	body, err := i.runQuery(ctx, qry)
+	ctx = tflog.SetField(ctx, "Query", RedactSecrets(qry))
+
+	// Rows are parsed while they are being received, the response is never buffered as a whole.
+	resp, err := i.do(ctx, qry, "JSONCompactStringsEachRowWithNamesAndTypes")
 	if err != nil {
 		return errors.WithMessage(err, "error running query")
 	}
+	defer resp.Body.Close()
 
-	parsed := jsonCompatStrings{}
-
-	err = json.Unmarshal([]byte(body), &parsed)
+	count, err := readJSONCompactStringsEachRow(resp.Body, callback)
 	if err != nil {
 		return errors.WithMessage(err, "error parsing response")
 	}
 
-	for _, row := range parsed.Rows() {
-		err = callback(row)
-		if err != nil {
-			return errors.WithMessage(err, "error calling callback function")
-		}
-	}
+	ctx = tflog.SetField(ctx, "RowCount", count)
+	tflog.Debug(ctx, "Run Query")
 
 	return nil
 }
@@ -107,18 +113,11 @@ func (i *httpClient) Exec(ctx context.Context, qry string) error {
 }
 
 func (i *httpClient) runQuery(ctx context.Context, qry string) (string, error) {
-	ctx = tflog.SetField(ctx, "Query", qry)
+	ctx = tflog.SetField(ctx, "Query", RedactSecrets(qry))
 
-	req, err := http.NewRequest(http.MethodPost, i.baseUrl.String(), strings.NewReader(qry))
+	resp, err := i.do(ctx, qry, "JSONCompactStrings")
 	if err != nil {
-		return "", errors.WithMessage(err, "error prepary HTTP request")
-	}
-
-	req.Header.Add("X-ClickHouse-Format", "JSONCompactStrings")
-
-	resp, err := i.client.Do(req)
-	if err != nil {
-		return "", errors.WithMessage(err, "error executing query")
+		return "", err
 	}
 
 	defer resp.Body.Close()
@@ -136,11 +135,52 @@ func (i *httpClient) runQuery(ctx context.Context, qry string) (string, error) {
 
 	ctx = tflog.SetField(ctx, "QueryResult", string(body))
 
-	if resp.StatusCode != http.StatusOK {
-		return "", errors.New(string(body))
-	}
-
 	tflog.Debug(ctx, "Run Query")
 
 	return string(body), nil
 }
+
+// do sends the query and returns the response with the output in the given format.
+// Responses with a non-OK status are turned into an error carrying the server's message.
+func (i *httpClient) do(ctx context.Context, qry string, format string) (*http.Response, error) {
+	u := i.baseUrl
+	{
+		params := u.Query()
+		if comment := logComment(ctx); comment != "" {
+			params.Set("log_comment", comment)
+		}
+		for name, value := range settingsFromContext(ctx) {
+			params.Set(name, value)
+		}
+		for name, value := range parametersFromContext(ctx) {
+			params.Set("param_"+name, value)
+		}
+		u.RawQuery = params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), strings.NewReader(qry))
+	if err != nil {
+		return nil, errors.WithMessage(err, "error prepary HTTP request")
+	}
+
+	req.Header.Add("X-ClickHouse-Format", format)
+	if i.userAgent != "" {
+		req.Header.Set("User-Agent", i.userAgent)
+	}
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error executing query")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error reading response")
+		}
+		return nil, errors.New(strings.TrimSpace(string(body)))
+	}
+
+	return resp, nil
+}
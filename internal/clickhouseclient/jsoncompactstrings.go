@@ -1,7 +1,12 @@
 package clickhouseclient
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"strconv"
+
+	"github.com/pingcap/errors"
 )
 
 const nullString = "ᴺᵁᴸᴸ"
@@ -28,45 +33,98 @@ func (j jsonCompatStrings) Rows() []Row {
 
 	// Create a slice of Rows associating the value to each column name.
 	for _, row := range j.Data {
-		data := Row{}
+		ret = append(ret, newStringsRow(colNames, colTypes, row))
+	}
+
+	return ret
+}
+
+// readJSONCompactStringsEachRow parses clickhouse query output in 'JSONCompactStringsEachRowWithNamesAndTypes' format,
+// calling callback for each row as soon as it is read so that large results are never held in memory at once.
+// It returns the number of rows read.
+func readJSONCompactStringsEachRow(r io.Reader, callback func(Row) error) (int, error) {
+	decoder := json.NewDecoder(r)
+
+	var colNames, colTypes []string
+	if err := decoder.Decode(&colNames); err != nil {
+		if err == io.EOF {
+			// Empty result.
+			return 0, nil
+		}
+		return 0, errors.WithMessage(err, "error reading column names")
+	}
+	if err := decoder.Decode(&colTypes); err != nil {
+		return 0, errors.WithMessage(err, "error reading column types")
+	}
+
+	count := 0
+	for {
+		var values []*string
+		err := decoder.Decode(&values)
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, errors.WithMessage(err, "error reading row")
+		}
+		if len(values) != len(colNames) {
+			return count, errors.New(fmt.Sprintf("row has %d fields, expected %d", len(values), len(colNames)))
+		}
+
+		fields := make([]string, len(values))
+		for i, v := range values {
+			if v == nil {
+				fields[i] = nullString
+			} else {
+				fields[i] = *v
+			}
+		}
 
-		for i, field := range row {
-			switch colTypes[i] {
-			case "String":
+		count++
+		if err := callback(newStringsRow(colNames, colTypes, fields)); err != nil {
+			return count, err
+		}
+	}
+}
+
+// newStringsRow creates a Row associating each value, as returned by the JSONCompactStrings formats, to its column name.
+func newStringsRow(colNames []string, colTypes []string, row []string) Row {
+	data := Row{}
+
+	for i, field := range row {
+		switch colTypes[i] {
+		case "String":
+			data.Set(colNames[i], field)
+		case "Nullable(String)":
+			if field == nullString {
+				data.Set(colNames[i], nilPtr[string]())
+			} else {
+				data.Set(colNames[i], &field)
+			}
+		case "UInt8":
+			val, err := strconv.ParseUint(field, 10, 8)
+			if err != nil {
+				// Failed parsing as number, return value as-is.
 				data.Set(colNames[i], field)
-			case "Nullable(String)":
-				if field == nullString {
-					data.Set(colNames[i], nilPtr[string]())
-				} else {
-					data.Set(colNames[i], &field)
-				}
-			case "UInt8":
-				val, err := strconv.ParseUint(field, 10, 8)
-				if err != nil {
-					// Failed parsing as number, return value as-is.
-					data.Set(colNames[i], field)
-					break
-				} else {
-					data.Set(colNames[i], uint8(val))
-				}
-			case "UInt64":
-				val, err := strconv.ParseUint(field, 10, 64)
-				if err != nil {
-					// Failed parsing as number, return value as-is.
-					data.Set(colNames[i], field)
-					break
-				} else {
-					data.Set(colNames[i], val)
-				}
-			default:
+				break
+			} else {
+				data.Set(colNames[i], uint8(val))
+			}
+		case "UInt64":
+			val, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				// Failed parsing as number, return value as-is.
 				data.Set(colNames[i], field)
+				break
+			} else {
+				data.Set(colNames[i], val)
 			}
+		default:
+			data.Set(colNames[i], field)
 		}
-
-		ret = append(ret, data)
 	}
 
-	return ret
+	return data
 }
 
 func nilPtr[T any]() *T {
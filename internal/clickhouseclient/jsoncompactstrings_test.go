@@ -2,6 +2,7 @@ package clickhouseclient
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -53,6 +54,71 @@ func Test_jsonCompatStrings_Rows(t *testing.T) {
 	}
 }
 
+func Test_readJSONCompactStringsEachRow(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    []Row
+		wantErr bool
+	}{
+		{
+			name: "Rows with types",
+			body: "[\"name\", \"precedence\", \"comment\"]\n" +
+				"[\"String\", \"UInt64\", \"Nullable(String)\"]\n" +
+				"[\"john\", \"1\", null]\n" +
+				"[\"frank\", \"2\", \"hi\"]\n",
+			want: func() []Row {
+				comment := "hi"
+				john, frank := Row{}, Row{}
+				john.Set("name", "john")
+				john.Set("precedence", uint64(1))
+				john.Set("comment", nilPtr[string]())
+				frank.Set("name", "frank")
+				frank.Set("precedence", uint64(2))
+				frank.Set("comment", &comment)
+				return []Row{john, frank}
+			}(),
+		},
+		{
+			name: "Empty result",
+			body: "",
+			want: []Row{},
+		},
+		{
+			name: "No rows",
+			body: "[\"name\"]\n[\"String\"]\n",
+			want: []Row{},
+		},
+		{
+			name:    "Exception in the middle of the stream",
+			body:    "[\"name\"]\n[\"String\"]\n[\"john\"]\nCode: 241. DB::Exception: Memory limit exceeded",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]Row, 0)
+			count, err := readJSONCompactStringsEachRow(strings.NewReader(tt.body), func(row Row) error {
+				got = append(got, row)
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("readJSONCompactStringsEachRow() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if count != len(tt.want) {
+				t.Errorf("readJSONCompactStringsEachRow() count = %d, want %d", count, len(tt.want))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readJSONCompactStringsEachRow() want = %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
 func rowFromMap(data map[string]string) Row {
 	row := Row{}
 
@@ -9,6 +9,7 @@ import (
 
 	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/proto"
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/pingcap/errors"
@@ -18,6 +19,9 @@ const defaultDatabase = "default"
 
 type nativeClient struct {
 	connection driver.Conn
+	// inlineParameters is set for the servers older than 22.8, to which the driver silently doesn't send the query
+	// parameters.
+	inlineParameters bool
 }
 
 type NativeClientConfig struct {
@@ -25,6 +29,8 @@ type NativeClientConfig struct {
 	Port             uint16
 	UserPasswordAuth *UserPasswordAuth
 	EnableTLS        bool
+	ConnectionPool   ConnectionPoolConfig
+	ClientProducts   []ClientProduct
 }
 
 func NewNativeClient(config NativeClientConfig) (ClickhouseClient, error) {
@@ -39,7 +45,10 @@ func NewNativeClient(config NativeClientConfig) (ClickhouseClient, error) {
 	}
 
 	options := clickhouse.Options{
-		Addr: []string{fmt.Sprintf("%s:%d", config.Host, config.Port)},
+		Addr:            []string{fmt.Sprintf("%s:%d", config.Host, config.Port)},
+		MaxOpenConns:    config.ConnectionPool.MaxOpenConns,
+		MaxIdleConns:    config.ConnectionPool.MaxIdleConns,
+		ConnMaxLifetime: config.ConnectionPool.ConnMaxLifetime,
 	}
 
 	if config.UserPasswordAuth != nil {
@@ -59,6 +68,13 @@ func NewNativeClient(config NativeClientConfig) (ClickhouseClient, error) {
 		options.TLS = &tls.Config{} //nolint:gosec
 	}
 
+	for _, p := range config.ClientProducts {
+		options.ClientInfo.Products = append(options.ClientInfo.Products, struct {
+			Name    string
+			Version string
+		}{Name: p.Name, Version: p.Version})
+	}
+
 	conn, err := clickhouse.Open(&options)
 	if err != nil {
 		return nil, err
@@ -73,19 +89,28 @@ func NewNativeClient(config NativeClientConfig) (ClickhouseClient, error) {
 		return nil, err
 	}
 
+	serverVersion, err := conn.ServerVersion()
+	if err != nil {
+		return nil, err
+	}
+
 	return &nativeClient{
-		connection: conn,
+		connection:       conn,
+		inlineParameters: serverVersion.Revision < proto.DBMS_MIN_PROTOCOL_VERSION_WITH_PARAMETERS,
 	}, nil
 }
 
 func (i *nativeClient) Select(ctx context.Context, qry string, callback func(Row) error) error {
-	ctx = tflog.SetField(ctx, "Query", qry)
+	ctx, qry = i.bindParameters(ctx, qry)
+	ctx = tflog.SetField(ctx, "Query", RedactSecrets(qry))
 	tflog.Debug(ctx, "Running Query")
 
-	rows, err := i.connection.Query(ctx, qry)
+	rows, err := i.connection.Query(queryContext(ctx), qry)
 	if err != nil {
 		return errors.WithMessage(err, "error executing query")
 	}
+	// Release the connection to the pool, otherwise parallel operations eventually fail acquiring one.
+	defer rows.Close()
 
 	// Prepare a slice of variable pointers dynamically typed based on the query result's column types.
 	columnTypes := rows.ColumnTypes()
@@ -128,17 +153,52 @@ func (i *nativeClient) Select(ctx context.Context, qry string, callback func(Row
 		}
 	}
 
+	if err := rows.Err(); err != nil {
+		return errors.WithMessage(err, "error reading query result")
+	}
+
 	return nil
 }
 
 func (i *nativeClient) Exec(ctx context.Context, qry string) error {
-	ctx = tflog.SetField(ctx, "Query", qry)
+	ctx, qry = i.bindParameters(ctx, qry)
+	ctx = tflog.SetField(ctx, "Query", RedactSecrets(qry))
 	tflog.Debug(ctx, "Running Query")
 
-	err := i.connection.Exec(ctx, qry)
+	err := i.connection.Exec(queryContext(ctx), qry)
 	if err != nil {
 		return errors.WithMessage(err, "error executing query")
 	}
 
 	return nil
 }
+
+// bindParameters inlines the query parameters carried by ctx in qry when the server can't receive them, and returns
+// the context and query to run.
+func (i *nativeClient) bindParameters(ctx context.Context, qry string) (context.Context, string) {
+	if !i.inlineParameters {
+		return ctx, qry
+	}
+
+	return context.WithValue(ctx, parametersKey{}, nil), inlineParameters(qry, parametersFromContext(ctx))
+}
+
+// queryContext sets the query parameters and the settings carried by ctx, including log_comment, if any.
+func queryContext(ctx context.Context) context.Context {
+	if params := parametersFromContext(ctx); len(params) > 0 {
+		ctx = clickhouse.Context(ctx, clickhouse.WithParameters(params))
+	}
+
+	settings := clickhouse.Settings{}
+	for name, value := range settingsFromContext(ctx) {
+		settings[name] = value
+	}
+	if comment := logComment(ctx); comment != "" {
+		settings["log_comment"] = comment
+	}
+	if len(settings) > 0 {
+		ctx = clickhouse.Context(ctx, clickhouse.WithSettings(settings))
+	}
+
+	return ctx
+}
@@ -0,0 +1,48 @@
+package clickhouseclient
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type parametersKey struct{}
+
+// parameterPlaceholder matches the {name:Type} placeholders of query parameters.
+var parameterPlaceholder = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*):\s*([^{}]+)\}`)
+
+// WithParameters returns a context binding the values of the {name:Type} query parameters of the queries run
+// with it. Values are sent separately from the query and parsed by the server, they are never interpolated.
+func WithParameters(ctx context.Context, params map[string]string) context.Context {
+	if len(params) == 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, parametersKey{}, params)
+}
+
+func parametersFromContext(ctx context.Context) map[string]string {
+	params, _ := ctx.Value(parametersKey{}).(map[string]string)
+	return params
+}
+
+// inlineParameters replaces the {name:Type} placeholders of params in qry with their value, quoted and cast to the
+// type of the parameter, which the server parses just like a bound value. It is meant for the servers that don't
+// receive query parameters over the native protocol.
+func inlineParameters(qry string, params map[string]string) string {
+	if len(params) == 0 {
+		return qry
+	}
+
+	return parameterPlaceholder.ReplaceAllStringFunc(qry, func(placeholder string) string {
+		match := parameterPlaceholder.FindStringSubmatch(placeholder)
+		value, ok := params[match[1]]
+		if !ok {
+			return placeholder
+		}
+
+		quoted := strings.ReplaceAll(strings.ReplaceAll(value, `\`, `\\`), `'`, `\'`)
+		return fmt.Sprintf("CAST('%s' AS %s)", quoted, strings.TrimSpace(match[2]))
+	})
+}
@@ -0,0 +1,50 @@
+package clickhouseclient
+
+import "testing"
+
+func Test_inlineParameters(t *testing.T) {
+	tests := []struct {
+		name   string
+		qry    string
+		params map[string]string
+		want   string
+	}{
+		{
+			name:   "No parameters",
+			qry:    "SELECT `name` FROM `system`.`users`;",
+			params: nil,
+			want:   "SELECT `name` FROM `system`.`users`;",
+		},
+		{
+			name:   "String",
+			qry:    "SELECT `name` FROM `system`.`users` WHERE (`name` = {name:String});",
+			params: map[string]string{"name": `o'brien\`},
+			want:   "SELECT `name` FROM `system`.`users` WHERE (`name` = CAST('o\\'brien\\\\' AS String));",
+		},
+		{
+			name:   "Array",
+			qry:    "SELECT `uuid` FROM `system`.`tables` WHERE (has({uuids:Array(UUID)}, `uuid`));",
+			params: map[string]string{"uuids": "['a','b']"},
+			want:   "SELECT `uuid` FROM `system`.`tables` WHERE (has(CAST('[\\'a\\',\\'b\\']' AS Array(UUID)), `uuid`));",
+		},
+		{
+			name:   "Braces that are not parameters",
+			qry:    "SELECT `name` FROM `system`.`replicas` WHERE (`zookeeper_path` = '/clickhouse/tables/{shard}/t' AND `name` = {name:String});",
+			params: map[string]string{"name": "t"},
+			want:   "SELECT `name` FROM `system`.`replicas` WHERE (`zookeeper_path` = '/clickhouse/tables/{shard}/t' AND `name` = CAST('t' AS String));",
+		},
+		{
+			name:   "Unknown parameter",
+			qry:    "SELECT {other:String};",
+			params: map[string]string{"name": "a"},
+			want:   "SELECT {other:String};",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inlineParameters(tt.qry, tt.params); got != tt.want {
+				t.Errorf("inlineParameters() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package clickhouseclient
+
+import (
+	"time"
+)
+
+// ConnectionPoolConfig controls the connections a client keeps open to the ClickHouse server.
+// Zero values keep the defaults of the underlying client.
+type ConnectionPoolConfig struct {
+	// MaxOpenConns is the maximum number of connections open at the same time.
+	MaxOpenConns int
+	// MaxIdleConns is the maximum number of idle connections kept for reuse.
+	MaxIdleConns int
+	// ConnMaxLifetime is how long a connection is kept before being closed. Only the native client supports it, the
+	// HTTP client cannot close connections after a lifetime and ignores it.
+	ConnMaxLifetime time.Duration
+}
@@ -0,0 +1,99 @@
+package clickhouseclient
+
+import (
+	"context"
+	"encoding/json"
+)
+
+type queryTagKey struct{}
+
+// QueryTag identifies what queries are run for. It is sent as the log_comment setting of every query, so that
+// entries of system.query_log can be attributed to a terraform run and resource.
+type QueryTag struct {
+	RunID     string `json:"terraform_run_id,omitempty"`
+	Resource  string `json:"resource,omitempty"`
+	Operation string `json:"operation,omitempty"`
+}
+
+// WithQueryTag returns a context tagging the queries run with it. Empty fields of tag keep the value set by
+// the parent context, if any.
+func WithQueryTag(ctx context.Context, tag QueryTag) context.Context {
+	merged := QueryTagFromContext(ctx)
+	if tag.RunID != "" {
+		merged.RunID = tag.RunID
+	}
+	if tag.Resource != "" {
+		merged.Resource = tag.Resource
+	}
+	if tag.Operation != "" {
+		merged.Operation = tag.Operation
+	}
+
+	return context.WithValue(ctx, queryTagKey{}, merged)
+}
+
+// WithSharedQueryTag returns a context tagging the queries run with it on behalf of the callers holding ctxs: the
+// fields of the tag that differ between them are left empty. The tag of ctx itself is replaced.
+func WithSharedQueryTag(ctx context.Context, ctxs []context.Context) context.Context {
+	var shared QueryTag
+	for n, c := range ctxs {
+		tag := QueryTagFromContext(c)
+		if n == 0 {
+			shared = tag
+			continue
+		}
+		if tag.RunID != shared.RunID {
+			shared.RunID = ""
+		}
+		if tag.Resource != shared.Resource {
+			shared.Resource = ""
+		}
+		if tag.Operation != shared.Operation {
+			shared.Operation = ""
+		}
+	}
+
+	return context.WithValue(ctx, queryTagKey{}, shared)
+}
+
+// QueryTagFromContext returns the tag of the queries run with ctx, empty if there is none.
+func QueryTagFromContext(ctx context.Context) QueryTag {
+	tag, _ := ctx.Value(queryTagKey{}).(QueryTag)
+	return tag
+}
+
+// logComment returns the value of the log_comment setting for the queries run with ctx, or an empty string.
+func logComment(ctx context.Context) string {
+	tag := QueryTagFromContext(ctx)
+	if tag == (QueryTag{}) {
+		return ""
+	}
+
+	comment, err := json.Marshal(tag)
+	if err != nil {
+		return ""
+	}
+
+	return string(comment)
+}
+
+// NewRunTaggingClient wraps client so that every query is tagged with the given terraform run id.
+func NewRunTaggingClient(client ClickhouseClient, runID string) ClickhouseClient {
+	return &runTaggingClient{
+		client: client,
+		runID:  runID,
+	}
+}
+
+type runTaggingClient struct {
+	client ClickhouseClient
+	runID  string
+}
+
+func (c *runTaggingClient) Select(ctx context.Context, qry string, callback func(Row) error) error {
+	return c.client.Select(WithQueryTag(ctx, QueryTag{RunID: c.runID}), qry, callback)
+}
+
+func (c *runTaggingClient) Exec(ctx context.Context, qry string) error {
+	return c.client.Exec(WithQueryTag(ctx, QueryTag{RunID: c.runID}), qry)
+}
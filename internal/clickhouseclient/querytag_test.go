@@ -0,0 +1,40 @@
+package clickhouseclient
+
+import (
+	"context"
+	"testing"
+)
+
+func Test_logComment(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{
+			name: "No tag",
+			ctx:  context.Background(),
+			want: "",
+		},
+		{
+			name: "Full tag",
+			ctx:  WithQueryTag(context.Background(), QueryTag{RunID: "run1", Resource: "clickhousedbops_table", Operation: "apply"}),
+			want: `{"terraform_run_id":"run1","resource":"clickhousedbops_table","operation":"apply"}`,
+		},
+		{
+			name: "Nested tags are merged",
+			ctx: WithQueryTag(
+				WithQueryTag(context.Background(), QueryTag{Resource: "clickhousedbops_user", Operation: "read"}),
+				QueryTag{RunID: "run1"},
+			),
+			want: `{"terraform_run_id":"run1","resource":"clickhousedbops_user","operation":"read"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := logComment(tt.ctx); got != tt.want {
+				t.Errorf("logComment() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
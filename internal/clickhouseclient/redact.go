@@ -0,0 +1,31 @@
+package clickhouseclient
+
+import (
+	"regexp"
+)
+
+// redactedSecret replaces the secrets of queries written to logs, cassettes and error messages.
+const redactedSecret = "'[HIDDEN]'"
+
+// secretRegexps match the secrets of the statements the provider runs: the first group is kept and the quoted
+// secret following it is replaced.
+var secretRegexps = []*regexp.Regexp{
+	// CREATE USER ... IDENTIFIED WITH sha256_hash BY '...'
+	regexp.MustCompile(`(?i)(\bIDENTIFIED\s+WITH\s+\w+\s+BY\s+)'(?:[^'\\]|\\.)*'`),
+	// CREATE USER ... IDENTIFIED BY '...'
+	regexp.MustCompile(`(?i)(\bIDENTIFIED\s+BY\s+)'(?:[^'\\]|\\.)*'`),
+	// ssh keys, e.g. IDENTIFIED WITH ssh_key BY KEY '...' TYPE 'ssh-ed25519'
+	regexp.MustCompile(`(?i)(\bBY\s+KEY\s+)'(?:[^'\\]|\\.)*'`),
+	// Table functions, engines and named collections: password = '...', secret_access_key = '...'
+	regexp.MustCompile(`(?i)(\b(?:password|secret|secret_access_key|access_key_id|token)\s*=\s*)'(?:[^'\\]|\\.)*'`),
+}
+
+// RedactSecrets returns the query with the passwords, password hashes, keys and other credentials it holds replaced,
+// so that it can be logged or reported.
+func RedactSecrets(qry string) string {
+	for _, re := range secretRegexps {
+		qry = re.ReplaceAllString(qry, "${1}"+redactedSecret)
+	}
+
+	return qry
+}
@@ -0,0 +1,46 @@
+package clickhouseclient
+
+import (
+	"testing"
+)
+
+func TestRedactSecrets(t *testing.T) {
+	tests := []struct {
+		name string
+		qry  string
+		want string
+	}{
+		{
+			name: "Password hash",
+			qry:  "CREATE USER `alice` IDENTIFIED WITH sha256_hash BY 'f0e4c2f76c58916ec258f246851bea091d14d4247a2fc3e18694461b1816e13b';",
+			want: "CREATE USER `alice` IDENTIFIED WITH sha256_hash BY '[HIDDEN]';",
+		},
+		{
+			name: "Plain text password with escaped quote",
+			qry:  "ALTER USER `alice` IDENTIFIED BY 'it\\'s secret';",
+			want: "ALTER USER `alice` IDENTIFIED BY '[HIDDEN]';",
+		},
+		{
+			name: "SSH key",
+			qry:  "CREATE USER `alice` IDENTIFIED WITH ssh_key BY KEY 'AAAAC3NzaC1lZDI1NTE5' TYPE 'ssh-ed25519';",
+			want: "CREATE USER `alice` IDENTIFIED WITH ssh_key BY KEY '[HIDDEN]' TYPE 'ssh-ed25519';",
+		},
+		{
+			name: "Named collection keys",
+			qry:  "CREATE NAMED COLLECTION `s3` AS url = 'https://bucket', access_key_id = 'AKIA', secret_access_key = 'abc';",
+			want: "CREATE NAMED COLLECTION `s3` AS url = 'https://bucket', access_key_id = '[HIDDEN]', secret_access_key = '[HIDDEN]';",
+		},
+		{
+			name: "Nothing to hide",
+			qry:  "SELECT `name` FROM `system`.`users` WHERE `name` = 'password';",
+			want: "SELECT `name` FROM `system`.`users` WHERE `name` = 'password';",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RedactSecrets(tt.qry); got != tt.want {
+				t.Errorf("RedactSecrets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
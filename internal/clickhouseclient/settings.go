@@ -0,0 +1,30 @@
+package clickhouseclient
+
+import (
+	"context"
+	"maps"
+)
+
+type settingsKey struct{}
+
+// WithSettings returns a context setting the given query level settings for the queries run with it, e.g. to enable
+// an experimental feature for a single DDL. Settings of the parent context are kept unless overridden.
+// Unlike a SETTINGS clause, they can't be mistaken for table settings in CREATE TABLE queries.
+func WithSettings(ctx context.Context, settings map[string]string) context.Context {
+	if len(settings) == 0 {
+		return ctx
+	}
+
+	merged := maps.Clone(settingsFromContext(ctx))
+	if merged == nil {
+		merged = make(map[string]string, len(settings))
+	}
+	maps.Copy(merged, settings)
+
+	return context.WithValue(ctx, settingsKey{}, merged)
+}
+
+func settingsFromContext(ctx context.Context) map[string]string {
+	settings, _ := ctx.Value(settingsKey{}).(map[string]string)
+	return settings
+}
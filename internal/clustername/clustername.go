@@ -0,0 +1,49 @@
+// Package clustername holds the checks of the cluster_name attribute shared by most resources.
+package clustername
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+// ValidatePlan reports an error on cluster_name when the planned cluster is not defined on the server, so that typos
+// fail the plan instead of the ON CLUSTER statements at apply time.
+// Nothing is checked when the provider is not configured yet, the cluster name is not known yet or holds macros
+// (e.g. `{cluster}`), which are only expanded by the server.
+func ValidatePlan(ctx context.Context, client dbops.Client, plan tfsdk.Plan, diags *diag.Diagnostics) {
+	if client == nil || plan.Raw.IsNull() {
+		return
+	}
+
+	var clusterName types.String
+	diags.Append(plan.GetAttribute(ctx, path.Root("cluster_name"), &clusterName)...)
+	if diags.HasError() || clusterName.IsNull() || clusterName.IsUnknown() || strings.Contains(clusterName.ValueString(), "{") {
+		return
+	}
+
+	exists, err := client.ClusterExists(ctx, clusterName.ValueString())
+	if err != nil {
+		// The apply fails anyway if the cluster does not exist, don't prevent planning when system.clusters can't be read.
+		diags.AddWarning(
+			"Unable to Check ClickHouse Cluster",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if !exists {
+		diags.AddAttributeError(
+			path.Root("cluster_name"),
+			"Unknown ClickHouse Cluster",
+			fmt.Sprintf("Cluster %q is not defined on the ClickHouse server, check the name against system.clusters.", clusterName.ValueString()),
+		)
+	}
+}
@@ -0,0 +1,27 @@
+// Package comment holds the checks of the comment attributes of databases, tables and columns.
+package comment
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+// Validate reports an error on the attribute at p when the comment exceeds the max_comment_bytes limit of the
+// provider. ClickHouse itself does not limit the size of comments, the limit is a policy of the configuration.
+// Nothing is checked when the provider is not configured yet or the comment is not known yet.
+func Validate(client dbops.Client, p path.Path, comment types.String, diags *diag.Diagnostics) {
+	if client == nil || comment.IsNull() || comment.IsUnknown() {
+		return
+	}
+
+	if err := client.CheckComment(comment.ValueString()); err != nil {
+		diags.AddAttributeError(
+			p,
+			"Comment Too Long",
+			err.Error(),
+		)
+	}
+}
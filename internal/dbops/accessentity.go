@@ -0,0 +1,111 @@
+package dbops
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// Types of AccessEntity.
+const (
+	AccessEntityUser            = "user"
+	AccessEntityRole            = "role"
+	AccessEntityRowPolicy       = "row_policy"
+	AccessEntityQuota           = "quota"
+	AccessEntitySettingsProfile = "settings_profile"
+)
+
+// accessEntityTables maps every type of access entity to the system table listing them.
+var accessEntityTables = []struct {
+	entityType  string
+	systemTable string
+}{
+	{AccessEntityUser, "system.users"},
+	{AccessEntityRole, "system.roles"},
+	{AccessEntityRowPolicy, "system.row_policies"},
+	{AccessEntityQuota, "system.quotas"},
+	{AccessEntitySettingsProfile, "system.settings_profiles"},
+}
+
+// AccessEntity is a user, role, row policy, quota or settings profile.
+type AccessEntity struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Storage is the access storage holding the entity, see IsReadOnlyStorage.
+	Storage string `json:"storage"`
+}
+
+// GetAccessEntities returns every access entity known to the server, sorted by type and name. With a cluster,
+// entities are read from every replica and the ones found on several replicas are returned once.
+func (i *impl) GetAccessEntities(ctx context.Context, clusterName *string) ([]AccessEntity, error) {
+	selects := make([]querybuilder.SelectQueryBuilder, 0, len(accessEntityTables))
+	for _, t := range accessEntityTables {
+		selects = append(selects, querybuilder.NewSelect(
+			[]querybuilder.Field{
+				querybuilder.NewExpressionField(fmt.Sprintf("'%s'", t.entityType), "type"),
+				querybuilder.NewExpressionField("toString(id)", "id"),
+				querybuilder.NewField("name"),
+				querybuilder.NewField("storage"),
+			},
+			t.systemTable,
+		).WithCluster(i.readCluster(clusterName)))
+	}
+
+	sql, err := querybuilder.NewUnionAll(selects...).Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	ret := make([]AccessEntity, 0)
+	seen := make(map[[2]string]bool)
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		entityType, err := data.GetString("type")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'type' field")
+		}
+		id, err := data.GetString("id")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'id' field")
+		}
+		name, err := data.GetString("name")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'name' field")
+		}
+		storage, err := data.GetString("storage")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'storage' field")
+		}
+
+		key := [2]string{entityType, id}
+		if seen[key] {
+			// Same entity seen on another replica.
+			return nil
+		}
+		seen[key] = true
+
+		ret = append(ret, AccessEntity{
+			Type:    entityType,
+			ID:      id,
+			Name:    name,
+			Storage: storage,
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	slices.SortFunc(ret, func(a, b AccessEntity) int {
+		return cmp.Or(cmp.Compare(a.Type, b.Type), cmp.Compare(a.Name, b.Name))
+	})
+
+	return ret, nil
+}
@@ -0,0 +1,69 @@
+package dbops
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// readOnlyStorages lists the access storages backed by the server configuration or an external directory, whose
+// users, roles and quotas can't be changed with SQL statements. The users.xml storage is named users_xml since 22.x.
+var readOnlyStorages = []string{"users.xml", "users_xml", "ldap"}
+
+// IsReadOnlyStorage tells if the entities of the given access storage (the `storage` column of system.users,
+// system.roles and system.quotas) are defined outside of SQL and can't be altered or dropped.
+func IsReadOnlyStorage(storage string) bool {
+	return slices.Contains(readOnlyStorages, storage)
+}
+
+// checkWritable returns an error when the access entity with the given name in the given system table (users, roles
+// or quotas) comes from a read only storage. Entities that don't exist are left for the statement to report.
+func (i *impl) checkWritable(ctx context.Context, kind string, systemTable string, name string, clusterName *string) error {
+	query := querybuilder.
+		NewSelect([]querybuilder.Field{querybuilder.NewField("storage")}, systemTable).
+		WithCluster(i.readCluster(clusterName)).
+		Where(querybuilder.WhereEquals("name", querybuilder.NewParameter("name", "String", name)))
+	sql, err := query.Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
+	}
+
+	var storage string
+
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
+		s, err := data.GetString("storage")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'storage' field")
+		}
+		if IsReadOnlyStorage(s) {
+			storage = s
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
+	}
+
+	if storage != "" {
+		return errors.New(fmt.Sprintf("%s %q is defined in the %s storage and can't be changed with SQL statements, change it in the server configuration instead", kind, name, storage))
+	}
+
+	return nil
+}
+
+// checkWritableGrantee runs checkWritable on the user or role receiving a grant, profile or quota.
+func (i *impl) checkWritableGrantee(ctx context.Context, granteeUserName *string, granteeRoleName *string, clusterName *string) error {
+	if granteeUserName != nil {
+		return i.checkWritable(ctx, "user", "system.users", *granteeUserName, clusterName)
+	}
+	if granteeRoleName != nil {
+		return i.checkWritable(ctx, "role", "system.roles", *granteeRoleName, clusterName)
+	}
+
+	return nil
+}
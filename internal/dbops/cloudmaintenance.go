@@ -0,0 +1,162 @@
+package dbops
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// ClickHouse error codes returned while a ClickHouse Cloud service is in a maintenance window: the service is read
+// only during upgrades, and rejects queries while it scales or restarts its replicas.
+// errorCodeReadonly is also what a user restricted by the readonly setting of their profile gets, see
+// maintenanceClient.isMaintenanceError.
+const (
+	errorCodeReadonly                   = 164
+	errorCodeTooManySimultaneousQueries = 202
+)
+
+const (
+	// CloudMaintenanceModeFail makes statements failing because of a maintenance window fail right away with a
+	// MaintenanceError.
+	CloudMaintenanceModeFail = "fail"
+	// CloudMaintenanceModeWait makes statements failing because of a maintenance window wait for it to end and run
+	// again, up to CloudMaintenance.WaitTimeout.
+	CloudMaintenanceModeWait = "wait"
+)
+
+var CloudMaintenanceModes = []string{CloudMaintenanceModeFail, CloudMaintenanceModeWait}
+
+// CloudMaintenance configures how queries failing because the service is in a maintenance window are handled.
+type CloudMaintenance struct {
+	// Mode is one of CloudMaintenanceModes.
+	Mode string
+	// WaitTimeout is how long CloudMaintenanceModeWait waits for the maintenance window to end.
+	WaitTimeout time.Duration
+}
+
+// maintenanceRetryDelay is the wait between two attempts while waiting for a maintenance window to end.
+var maintenanceRetryDelay = 15 * time.Second
+
+// MaintenanceError is returned when a query failed because the service is in a maintenance window.
+type MaintenanceError struct {
+	err    error
+	waited time.Duration
+}
+
+func (e *MaintenanceError) Error() string {
+	if e.waited > 0 {
+		return fmt.Sprintf("the ClickHouse service was still in a maintenance window or read only after waiting %s: %s", e.waited, e.err.Error())
+	}
+
+	return fmt.Sprintf("the ClickHouse service is in a maintenance window or read only, retry once it is over: %s", e.err.Error())
+}
+
+// IsMaintenance tells if err comes from a query rejected because the service is in a maintenance window. Such errors
+// are only returned when the client was created with CloudMaintenance, plain errors are returned otherwise.
+func IsMaintenance(err error) bool {
+	_, ok := errors.Cause(err).(*MaintenanceError)
+	return ok
+}
+
+// maintenanceClient detects the queries rejected because the service is in a maintenance window, and either fails
+// them with a MaintenanceError or runs them again once the window is over.
+type maintenanceClient struct {
+	clickhouseclient.ClickhouseClient
+	config CloudMaintenance
+}
+
+func (c *maintenanceClient) Select(ctx context.Context, qry string, callback func(clickhouseclient.Row) error) error {
+	// The service rejects queries before returning any row, the callback is not called twice for the same row.
+	return c.run(ctx, func() error {
+		return c.ClickhouseClient.Select(ctx, qry, callback)
+	})
+}
+
+func (c *maintenanceClient) Exec(ctx context.Context, qry string) error {
+	return c.run(ctx, func() error {
+		return c.ClickhouseClient.Exec(ctx, qry)
+	})
+}
+
+func (c *maintenanceClient) run(ctx context.Context, query func() error) error {
+	start := time.Now()
+
+	for {
+		err := query()
+		if err == nil || !c.isMaintenanceError(ctx, err) {
+			return err
+		}
+
+		if c.config.Mode != CloudMaintenanceModeWait {
+			return &MaintenanceError{err: err}
+		}
+
+		waited := time.Since(start)
+		if waited+maintenanceRetryDelay > c.config.WaitTimeout {
+			return &MaintenanceError{err: err, waited: waited}
+		}
+
+		tflog.Warn(ctx, "Waiting for the ClickHouse service maintenance window to end", map[string]interface{}{
+			"waited": waited.String(),
+			"error":  err.Error(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return &MaintenanceError{err: err, waited: time.Since(start)}
+		case <-time.After(maintenanceRetryDelay):
+		}
+	}
+}
+
+// isMaintenanceError tells if err comes from a query rejected because the service is in a maintenance window.
+// A READONLY error only does when the session itself is allowed to write: otherwise it comes from the readonly setting
+// of the user, which no maintenance window is going to lift.
+func (c *maintenanceClient) isMaintenanceError(ctx context.Context, err error) bool {
+	code, ok := errorCode(err)
+	if !ok {
+		return false
+	}
+
+	switch code {
+	case errorCodeTooManySimultaneousQueries:
+		return true
+	case errorCodeReadonly:
+		return !c.sessionIsReadonly(ctx)
+	default:
+		return false
+	}
+}
+
+// sessionIsReadonly tells if the readonly setting of the session is set, usually by the profile of the user. It returns
+// true when the setting can't be read, so that the original error is returned as is.
+func (c *maintenanceClient) sessionIsReadonly(ctx context.Context) bool {
+	sql, err := querybuilder.NewSelect(
+		[]querybuilder.Field{querybuilder.NewField("name")},
+		"system.settings",
+	).Where(
+		querybuilder.WhereEquals("name", "readonly"),
+		querybuilder.WhereDiffers("value", "0"),
+	).Build()
+	if err != nil {
+		return true
+	}
+
+	readonly := false
+	err = c.ClickhouseClient.Select(ctx, sql, func(clickhouseclient.Row) error {
+		readonly = true
+		return nil
+	})
+	if err != nil {
+		tflog.Debug(ctx, "Cannot tell if the session is read only", map[string]interface{}{"error": err.Error()})
+		return true
+	}
+
+	return readonly
+}
@@ -0,0 +1,93 @@
+package dbops
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+)
+
+func Test_maintenanceClient_Exec(t *testing.T) {
+	maintenanceRetryDelay = time.Millisecond
+
+	readonly := errors.New("Code: 164. DB::Exception: Cannot execute query in readonly mode. (READONLY)")
+	busy := errors.New("code: 202, message: Too many simultaneous queries. Maximum: 100")
+	syntax := errors.New("Code: 62. DB::Exception: Syntax error. (SYNTAX_ERROR)")
+
+	tests := []struct {
+		name            string
+		config          CloudMaintenance
+		errs            []error
+		readonlyUser    bool
+		wantCalls       int
+		wantErr         bool
+		wantMaintenance bool
+	}{
+		{
+			name:      "Success",
+			config:    CloudMaintenance{Mode: CloudMaintenanceModeFail},
+			errs:      nil,
+			wantCalls: 1,
+			wantErr:   false,
+		},
+		{
+			name:            "Fail mode fails right away",
+			config:          CloudMaintenance{Mode: CloudMaintenanceModeFail},
+			errs:            []error{readonly},
+			wantCalls:       1,
+			wantErr:         true,
+			wantMaintenance: true,
+		},
+		{
+			name:         "Readonly user fails right away",
+			config:       CloudMaintenance{Mode: CloudMaintenanceModeWait, WaitTimeout: time.Minute},
+			errs:         []error{readonly},
+			readonlyUser: true,
+			wantCalls:    1,
+			wantErr:      true,
+		},
+		{
+			name:      "Wait mode runs the statement again",
+			config:    CloudMaintenance{Mode: CloudMaintenanceModeWait, WaitTimeout: time.Minute},
+			errs:      []error{readonly, busy},
+			wantCalls: 3,
+			wantErr:   false,
+		},
+		{
+			name:            "Wait mode gives up after the timeout",
+			config:          CloudMaintenance{Mode: CloudMaintenanceModeWait, WaitTimeout: 0},
+			errs:            []error{busy},
+			wantCalls:       1,
+			wantErr:         true,
+			wantMaintenance: true,
+		},
+		{
+			name:      "Other errors are returned as is",
+			config:    CloudMaintenance{Mode: CloudMaintenanceModeWait, WaitTimeout: time.Minute},
+			errs:      []error{syntax},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &execClient{errs: tt.errs}
+			if tt.readonlyUser {
+				client.selectRows = 1
+			}
+			c := &maintenanceClient{ClickhouseClient: client, config: tt.config}
+
+			err := c.Exec(context.Background(), "CREATE DATABASE db")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Exec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := IsMaintenance(err); got != tt.wantMaintenance {
+				t.Errorf("IsMaintenance() = %v, want %v", got, tt.wantMaintenance)
+			}
+			if client.calls != tt.wantCalls {
+				t.Errorf("Exec() calls = %d, want %d", client.calls, tt.wantCalls)
+			}
+		})
+	}
+}
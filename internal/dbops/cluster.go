@@ -0,0 +1,45 @@
+package dbops
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// ClusterExists tells if the given cluster is defined in system.clusters of the replica hit by the query. Clusters
+// are not expected to go away during a run, so the ones found are remembered.
+func (i *impl) ClusterExists(ctx context.Context, clusterName string) (bool, error) {
+	i.clustersMu.Lock()
+	defer i.clustersMu.Unlock()
+
+	if i.clusters[clusterName] {
+		return true, nil
+	}
+
+	query := querybuilder.NewSelect(
+		[]querybuilder.Field{querybuilder.NewField("cluster")},
+		"system.clusters",
+	).Where(querybuilder.WhereEquals("cluster", querybuilder.NewParameter("cluster", "String", clusterName)))
+	sql, err := query.Build()
+	if err != nil {
+		return false, errors.WithMessage(err, "error building query")
+	}
+
+	exists := false
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
+		exists = true
+		return nil
+	})
+	if err != nil {
+		return false, errors.WithMessage(err, "error running query")
+	}
+
+	if exists {
+		i.clusters[clusterName] = true
+	}
+
+	return exists, nil
+}
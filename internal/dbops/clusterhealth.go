@@ -0,0 +1,145 @@
+package dbops
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// ClusterHealthCheck enables checking the health of the cluster before running ON CLUSTER DDL on databases and
+// tables, so that schema changes are not left half applied on an unhealthy cluster.
+type ClusterHealthCheck struct {
+	// MaxDDLQueueBacklog is the number of distributed DDL queries of the cluster not finished yet above which the
+	// cluster is considered unhealthy.
+	MaxDDLQueueBacklog uint64
+}
+
+// maxReportedReplicas caps the number of unhealthy replicas listed in errors.
+const maxReportedReplicas = 5
+
+// checkClusterHealth returns an error when the cluster the DDL is about to run on can't reach Keeper, has read only
+// replicas or too many distributed DDL queries pending. It does nothing unless enabled in the Config.
+func (i *impl) checkClusterHealth(ctx context.Context, clusterName *string) error {
+	if i.config.ClusterHealthCheck == nil || clusterName == nil {
+		return nil
+	}
+
+	if err := i.checkKeeper(ctx); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("refusing to run DDL on cluster %q, Keeper is not reachable", *clusterName))
+	}
+
+	replicas, err := i.getReadOnlyReplicas(ctx, *clusterName)
+	if err != nil {
+		return err
+	}
+	if len(replicas) > 0 {
+		return errors.New(fmt.Sprintf("refusing to run DDL on cluster %q, some replicas are read only: %s", *clusterName, strings.Join(replicas, ", ")))
+	}
+
+	backlog, err := i.getDDLQueueBacklog(ctx, *clusterName)
+	if err != nil {
+		return err
+	}
+	if backlog > i.config.ClusterHealthCheck.MaxDDLQueueBacklog {
+		return errors.New(fmt.Sprintf("refusing to run DDL on cluster %q, %d distributed DDL queries are not finished (at most %d allowed)", *clusterName, backlog, i.config.ClusterHealthCheck.MaxDDLQueueBacklog))
+	}
+
+	return nil
+}
+
+// checkKeeper lists the root of Keeper, which fails when the server lost its connection.
+func (i *impl) checkKeeper(ctx context.Context) error {
+	sql, err := querybuilder.NewSelect(
+		[]querybuilder.Field{querybuilder.NewField("name")},
+		"system.zookeeper",
+	).Where(querybuilder.WhereEquals("path", "/")).Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Select(ctx, sql, func(clickhouseclient.Row) error {
+		return nil
+	})
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
+	}
+
+	return nil
+}
+
+// getReadOnlyReplicas returns the replicated tables that are read only on some replica of the cluster, usually
+// because the replica lost its Keeper session, as `database`.`table` on host.
+func (i *impl) getReadOnlyReplicas(ctx context.Context, clusterName string) ([]string, error) {
+	sql, err := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewField("database"),
+			querybuilder.NewField("table"),
+			querybuilder.NewExpressionField("hostName()", "host"),
+		},
+		"system.replicas",
+	).WithClusterAllReplicas(&clusterName).
+		Where(querybuilder.WhereEquals("is_readonly", 1)).
+		Limit(maxReportedReplicas, 0).
+		Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	ret := make([]string, 0)
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		database, err := data.GetString("database")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'database' field")
+		}
+		table, err := data.GetString("table")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'table' field")
+		}
+		host, err := data.GetString("host")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'host' field")
+		}
+
+		ret = append(ret, fmt.Sprintf("`%s`.`%s` on %s", database, table, host))
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return ret, nil
+}
+
+// getDDLQueueBacklog returns the number of distributed DDL queries of the cluster that are not finished on every host.
+func (i *impl) getDDLQueueBacklog(ctx context.Context, clusterName string) (uint64, error) {
+	query := querybuilder.NewSelect(
+		[]querybuilder.Field{querybuilder.NewExpressionField("toUInt64(uniqExact(entry))", "backlog")},
+		"system.distributed_ddl_queue",
+	).Where(
+		querybuilder.WhereEquals("cluster", querybuilder.NewParameter("cluster", "String", clusterName)),
+		querybuilder.WhereDiffers("status", "Finished"),
+	)
+	sql, err := query.Build()
+	if err != nil {
+		return 0, errors.WithMessage(err, "error building query")
+	}
+
+	var backlog uint64
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
+		backlog, err = data.GetUInt64("backlog")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'backlog' field")
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, errors.WithMessage(err, "error running query")
+	}
+
+	return backlog, nil
+}
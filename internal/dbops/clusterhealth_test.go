@@ -0,0 +1,60 @@
+package dbops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/errors"
+)
+
+func Test_impl_checkClusterHealth(t *testing.T) {
+	cluster := "cluster1"
+	keeperDown := errors.New("code: 999, message: Coordination::Exception: No node")
+
+	tests := []struct {
+		name        string
+		config      Config
+		clusterName *string
+		selectErr   error
+		wantErr     bool
+	}{
+		{
+			name:        "Disabled",
+			config:      Config{},
+			clusterName: &cluster,
+			selectErr:   keeperDown,
+			wantErr:     false,
+		},
+		{
+			name:        "No cluster",
+			config:      Config{ClusterHealthCheck: &ClusterHealthCheck{}},
+			clusterName: nil,
+			selectErr:   keeperDown,
+			wantErr:     false,
+		},
+		{
+			name:        "Keeper not reachable",
+			config:      Config{ClusterHealthCheck: &ClusterHealthCheck{}},
+			clusterName: &cluster,
+			selectErr:   keeperDown,
+			wantErr:     true,
+		},
+		{
+			name:        "Healthy",
+			config:      Config{ClusterHealthCheck: &ClusterHealthCheck{}},
+			clusterName: &cluster,
+			selectErr:   nil,
+			wantErr:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &impl{clickhouseClient: &execClient{selectErr: tt.selectErr}, config: tt.config}
+
+			err := i.checkClusterHealth(context.Background(), tt.clusterName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkClusterHealth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
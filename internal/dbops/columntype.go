@@ -0,0 +1,52 @@
+package dbops
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+var (
+	// Type names are matched as whole words, so that e.g. Object('json') or a JSON path named dynamic don't match.
+	jsonTypeRegexp    = regexp.MustCompile(`(?i)(^|[\s(,])json($|[\s(,)])`)
+	dynamicTypeRegexp = regexp.MustCompile(`(^|[\s(,])Dynamic($|[\s(,)])`)
+)
+
+// columnTypeSettings returns the query settings needed to create the given columns. JSON and Dynamic columns are
+// behind experimental settings before ClickHouse 25.3, and fail with an explicit error on servers lacking them.
+func (i *impl) columnTypeSettings(ctx context.Context, columns []querybuilder.TableColumn) (map[string]string, error) {
+	var usesJSON, usesDynamic bool
+	for _, col := range columns {
+		usesJSON = usesJSON || jsonTypeRegexp.MatchString(col.Type)
+		usesDynamic = usesDynamic || dynamicTypeRegexp.MatchString(col.Type)
+	}
+	if !usesJSON && !usesDynamic {
+		return nil, nil
+	}
+
+	if usesJSON {
+		if err := i.requires(ctx, featureJSONType); err != nil {
+			return nil, err
+		}
+	}
+	if usesDynamic {
+		if err := i.requires(ctx, featureDynamicType); err != nil {
+			return nil, err
+		}
+	}
+
+	if ok, err := i.supports(ctx, featureStableSemiStructuredTypes); err != nil || ok {
+		return nil, err
+	}
+
+	settings := make(map[string]string)
+	if usesJSON {
+		settings["allow_experimental_json_type"] = "1"
+	}
+	if usesDynamic {
+		settings["allow_experimental_dynamic_type"] = "1"
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,74 @@
+package dbops
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+func Test_impl_columnTypeSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		version ServerVersion
+		types   []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:    "No semi-structured column",
+			version: ServerVersion{24, 3},
+			types:   []string{"String", "Object('json')", "Map(String, String)"},
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name:    "Experimental JSON and Dynamic",
+			version: ServerVersion{24, 8},
+			types:   []string{"JSON(max_dynamic_paths=64, a.b UInt32)", "Array(Dynamic(max_types=8))"},
+			want:    map[string]string{"allow_experimental_json_type": "1", "allow_experimental_dynamic_type": "1"},
+			wantErr: false,
+		},
+		{
+			name:    "Lowercase json",
+			version: ServerVersion{25, 1},
+			types:   []string{"json"},
+			want:    map[string]string{"allow_experimental_json_type": "1"},
+			wantErr: false,
+		},
+		{
+			name:    "Stable types need no settings",
+			version: ServerVersion{25, 3},
+			types:   []string{"JSON", "Dynamic"},
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name:    "JSON not supported",
+			version: ServerVersion{24, 5},
+			types:   []string{"JSON"},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &impl{serverVersion: &tt.version}
+
+			columns := make([]querybuilder.TableColumn, 0, len(tt.types))
+			for _, typ := range tt.types {
+				columns = append(columns, querybuilder.TableColumn{Name: "c", Type: typ})
+			}
+
+			got, err := i.columnTypeSettings(context.Background(), columns)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("columnTypeSettings() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("columnTypeSettings() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package dbops
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/pingcap/errors"
+)
+
+// CheckComment returns an error when the comment is longer than the MaxCommentBytes limit of the provider. The size
+// is counted in bytes, like ClickHouse does for strings, so a comment with multi-byte characters reaches the limit
+// with fewer characters.
+func (i *impl) CheckComment(comment string) error {
+	if i.config.MaxCommentBytes <= 0 || len(comment) <= i.config.MaxCommentBytes {
+		return nil
+	}
+
+	return errors.New(fmt.Sprintf("comment is %d bytes (%d characters) long, more than the %d bytes allowed by the provider's max_comment_bytes", len(comment), utf8.RuneCountInString(comment), i.config.MaxCommentBytes))
+}
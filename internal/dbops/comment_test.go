@@ -0,0 +1,48 @@
+package dbops
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_impl_CheckComment(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxBytes int
+		comment  string
+		wantErr  bool
+	}{
+		{
+			name:     "No limit",
+			maxBytes: 0,
+			comment:  strings.Repeat("a", 10000),
+			wantErr:  false,
+		},
+		{
+			name:     "Within limit",
+			maxBytes: 10,
+			comment:  "0123456789",
+			wantErr:  false,
+		},
+		{
+			name:     "Over limit",
+			maxBytes: 10,
+			comment:  "0123456789a",
+			wantErr:  true,
+		},
+		{
+			name:     "Multi-byte characters are counted in bytes",
+			maxBytes: 10,
+			comment:  "événements",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &impl{config: Config{MaxCommentBytes: tt.maxBytes}}
+			if err := i.CheckComment(tt.comment); (err != nil) != tt.wantErr {
+				t.Errorf("CheckComment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,565 @@
+package dbops
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// Keywords that can start a table-level clause after the column list in a CREATE TABLE statement.
+var tableClauseKeywords = []string{
+	"ENGINE",
+	"PARTITION BY",
+	"PRIMARY KEY",
+	"ORDER BY",
+	"SAMPLE BY",
+	"TTL",
+	"SETTINGS",
+	"COMMENT",
+	"AS",
+}
+
+// Keywords that can follow the data type in a column declaration.
+var columnClauseKeywords = []string{
+	querybuilder.ColumnDefaultKindDefault,
+	querybuilder.ColumnDefaultKindMaterialized,
+	querybuilder.ColumnDefaultKindAlias,
+	querybuilder.ColumnDefaultKindEphemeral,
+	"COMMENT",
+	"CODEC",
+	"STATISTICS",
+	"TTL",
+	"PRIMARY KEY",
+	"SETTINGS",
+}
+
+// Column clauses the table resource can't model, a table using them is only adopted read-only.
+var unsupportedColumnClauseKeywords = []string{"STATISTICS", "TTL", "SETTINGS"}
+
+// Keywords that start a non-column element of the column list.
+var elementKeywords = []string{"INDEX", "PROJECTION", "CONSTRAINT"}
+
+// ParseCreateTableQuery parses the statement returned by SHOW CREATE TABLE (or system.tables.create_table_query)
+// into a Table. UUID is not part of the statement and is left empty.
+func ParseCreateTableQuery(query string) (*Table, error) {
+	query = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(query), ";"))
+
+	// Skip the statement prefix up to the table name.
+	rest, ok := trimKeywords(query, "CREATE")
+	if !ok {
+		return nil, errors.New("statement does not start with CREATE")
+	}
+	rest, _ = trimKeywords(rest, "OR REPLACE")
+	rest, _ = trimKeywords(rest, "TEMPORARY")
+	if rest, ok = trimKeywords(rest, "TABLE"); !ok {
+		return nil, errors.New("statement is not a CREATE TABLE statement")
+	}
+	rest, _ = trimKeywords(rest, "IF NOT EXISTS")
+
+	table := &Table{}
+
+	var name string
+	name, rest = readIdentifier(rest)
+	if rest, ok = strings.CutPrefix(rest, "."); ok {
+		table.DatabaseName = name
+		name, rest = readIdentifier(rest)
+	}
+	if name == "" {
+		return nil, errors.New("cannot find table name in statement")
+	}
+	table.Name = name
+
+	if after, ok := trimKeywords(rest, "UUID"); ok {
+		_, rest = readIdentifier(after)
+	}
+	if after, ok := trimKeywords(rest, "ON CLUSTER"); ok {
+		_, rest = readIdentifier(after)
+	}
+
+	// Column list.
+	rest = strings.TrimSpace(rest)
+	if strings.HasPrefix(rest, "(") {
+		end := matchingParen(rest, 0)
+		if end == -1 {
+			return nil, errors.New("unbalanced parentheses in column list")
+		}
+
+		for _, element := range splitTopLevel(rest[1:end], ',') {
+			if definition, ok := trimKeywords(element, "INDEX"); ok {
+				index, err := parseIndexDeclaration(definition)
+				if err != nil {
+					return nil, errors.WithMessage(err, fmt.Sprintf("cannot parse index declaration %q", element))
+				}
+				table.Indexes = append(table.Indexes, *index)
+				continue
+			}
+			if definition, ok := trimKeywords(element, "PROJECTION"); ok {
+				projection, err := parseProjectionDeclaration(definition)
+				if err != nil {
+					return nil, errors.WithMessage(err, fmt.Sprintf("cannot parse projection declaration %q", element))
+				}
+				table.Projections = append(table.Projections, *projection)
+				continue
+			}
+			if definition, ok := trimKeywords(element, "CONSTRAINT"); ok {
+				name, _ := readIdentifier(definition)
+				table.Unsupported = append(table.Unsupported, fmt.Sprintf("constraint %q", name))
+				continue
+			}
+			if element == "" || startsWithAnyKeyword(element, elementKeywords) {
+				continue
+			}
+
+			col, unsupported, err := parseColumnDeclaration(element)
+			if err != nil {
+				return nil, errors.WithMessage(err, fmt.Sprintf("cannot parse column declaration %q", element))
+			}
+			table.Columns = append(table.Columns, *col)
+			for _, clause := range unsupported {
+				table.Unsupported = append(table.Unsupported, fmt.Sprintf("%s of column %q", clause, col.Name))
+			}
+		}
+
+		rest = rest[end+1:]
+	}
+
+	// Table level clauses.
+	for keyword, value := range splitClauses(rest, tableClauseKeywords) {
+		switch keyword {
+		case "ENGINE":
+			table.Engine = strings.TrimSpace(strings.TrimPrefix(value, "="))
+			if strings.Contains(table.Engine, "[HIDDEN]") {
+				table.Unsupported = append(table.Unsupported, "engine parameters hidden by ClickHouse")
+			}
+		case "PARTITION BY":
+			table.PartitionBy = &value
+		case "PRIMARY KEY":
+			table.PrimaryKey = parseExpressionList(value)
+		case "ORDER BY":
+			table.OrderBy = parseExpressionList(value)
+		case "SAMPLE BY":
+			table.SampleBy = &value
+		case "TTL":
+			table.TTL = &value
+		case "SETTINGS":
+			table.Settings = parseSettingsList(value)
+		case "COMMENT":
+			table.Comment = unquote(value)
+		case "AS":
+			table.SourceFunction = parseTableFunction(value)
+		}
+	}
+
+	return table, nil
+}
+
+// parseIndexDeclaration parses the `name expr TYPE type GRANULARITY n` declaration of a data skipping index.
+func parseIndexDeclaration(definition string) (*querybuilder.TableIndex, error) {
+	name, rest := readIdentifier(definition)
+	if name == "" {
+		return nil, errors.New("missing index name")
+	}
+
+	index := &querybuilder.TableIndex{Name: name}
+
+	positions := findTopLevelKeywords(rest, []string{"TYPE", "GRANULARITY"})
+	if len(positions) == 0 || positions[0].keyword != "TYPE" {
+		return nil, errors.New("missing index type")
+	}
+	index.Expression = strings.TrimSpace(rest[:positions[0].start])
+
+	typeEnd := len(rest)
+	if len(positions) > 1 {
+		typeEnd = positions[1].start
+		granularity, err := strconv.ParseUint(strings.TrimSpace(rest[positions[1].start+len("GRANULARITY"):]), 10, 64)
+		if err != nil {
+			return nil, errors.WithMessage(err, "invalid index granularity")
+		}
+		index.Granularity = granularity
+	}
+	index.Type = strings.TrimSpace(rest[positions[0].start+len("TYPE") : typeEnd])
+
+	if index.Expression == "" || index.Type == "" {
+		return nil, errors.New("missing index expression or type")
+	}
+
+	return index, nil
+}
+
+// parseProjectionDeclaration parses the `name (query)` declaration of a projection.
+func parseProjectionDeclaration(definition string) (*querybuilder.TableProjection, error) {
+	name, rest := readIdentifier(definition)
+	if name == "" {
+		return nil, errors.New("missing projection name")
+	}
+
+	rest = strings.TrimSpace(rest)
+	if !strings.HasPrefix(rest, "(") || matchingParen(rest, 0) != len(rest)-1 {
+		return nil, errors.New("missing projection query")
+	}
+
+	query := strings.TrimSpace(rest[1 : len(rest)-1])
+	if query == "" {
+		return nil, errors.New("missing projection query")
+	}
+
+	return &querybuilder.TableProjection{Name: name, Query: query}, nil
+}
+
+// parseTableFunction parses a table function call using a named collection, like `s3(coll, url = 'x')`.
+// It returns nil when expr is not such a call.
+func parseTableFunction(expr string) *querybuilder.TableFunction {
+	name, rest := readIdentifier(expr)
+	rest = strings.TrimSpace(rest)
+	if name == "" || !strings.HasPrefix(rest, "(") || matchingParen(rest, 0) != len(rest)-1 {
+		return nil
+	}
+
+	args := splitTopLevel(rest[1:len(rest)-1], ',')
+	if len(args) == 0 || strings.Contains(args[0], "=") {
+		// The first argument is not a named collection.
+		return nil
+	}
+
+	fn := &querybuilder.TableFunction{
+		Name:            name,
+		NamedCollection: unbacktick(args[0]),
+		Arguments:       make(map[string]string),
+	}
+
+	for _, arg := range args[1:] {
+		k, v, found := strings.Cut(arg, "=")
+		if !found {
+			return nil
+		}
+		fn.Arguments[unbacktick(strings.TrimSpace(k))] = unquote(v)
+	}
+
+	return fn
+}
+
+func parseColumnDeclaration(definition string) (*querybuilder.TableColumn, []string, error) {
+	name, rest := readIdentifier(definition)
+	if name == "" {
+		return nil, nil, errors.New("missing column name")
+	}
+
+	col := &querybuilder.TableColumn{Name: name}
+
+	// The type ends at the first modifier, including the NULL / NOT NULL modifiers that can only come right after it.
+	positions := findTopLevelKeywords(rest, append([]string{"NOT NULL", "NULL"}, columnClauseKeywords...))
+	typeEnd := len(rest)
+	if len(positions) > 0 {
+		typeEnd = positions[0].start
+	}
+	col.Type = strings.TrimSpace(rest[:typeEnd])
+	rest = rest[typeEnd:]
+
+	if after, ok := trimKeywords(rest, "NOT NULL"); ok {
+		rest = after
+	} else if after, ok := trimKeywords(rest, "NULL"); ok {
+		col.Type = "Nullable(" + col.Type + ")"
+		rest = after
+	}
+
+	var unsupported []string
+	for keyword, value := range splitClauses(rest, columnClauseKeywords) {
+		if slices.Contains(unsupportedColumnClauseKeywords, keyword) {
+			unsupported = append(unsupported, keyword)
+		}
+
+		switch keyword {
+		case querybuilder.ColumnDefaultKindDefault, querybuilder.ColumnDefaultKindMaterialized, querybuilder.ColumnDefaultKindAlias, querybuilder.ColumnDefaultKindEphemeral:
+			kind := keyword
+			if kind != querybuilder.ColumnDefaultKindDefault {
+				col.DefaultKind = &kind
+			}
+			if value != "" {
+				col.Default = &value
+			}
+		case "COMMENT":
+			comment := unquote(value)
+			col.Comment = &comment
+		case "CODEC":
+			codec := strings.TrimSpace(value)
+			codec = strings.TrimSuffix(strings.TrimPrefix(codec, "("), ")")
+			col.Codec = &codec
+		}
+	}
+	// The clauses are returned by a map, sort them for a stable result.
+	slices.Sort(unsupported)
+
+	return col, unsupported, nil
+}
+
+// splitClauses splits s on the given top-level keywords, returning the trimmed text following each keyword.
+func splitClauses(s string, keywords []string) map[string]string {
+	ret := make(map[string]string)
+
+	positions := findTopLevelKeywords(s, keywords)
+	for i, p := range positions {
+		end := len(s)
+		if i+1 < len(positions) {
+			end = positions[i+1].start
+		}
+		ret[p.keyword] = strings.TrimSpace(s[p.start+len(p.keyword) : end])
+	}
+
+	return ret
+}
+
+// parseExpressionList turns an ORDER BY or PRIMARY KEY expression like `(a, b)`, `a` or `tuple()` into a list of expressions.
+func parseExpressionList(expr string) []string {
+	expr = strings.TrimSpace(expr)
+	if expr == "" || expr == "tuple()" {
+		return nil
+	}
+
+	if strings.HasPrefix(expr, "(") && matchingParen(expr, 0) == len(expr)-1 {
+		expr = expr[1 : len(expr)-1]
+	}
+
+	ret := make([]string, 0)
+	for _, e := range splitTopLevel(expr, ',') {
+		if e != "" {
+			ret = append(ret, unbacktick(e))
+		}
+	}
+
+	return ret
+}
+
+func parseSettingsList(value string) map[string]string {
+	ret := make(map[string]string)
+
+	for _, pair := range splitTopLevel(value, ',') {
+		k, v, found := strings.Cut(pair, "=")
+		if !found {
+			continue
+		}
+		ret[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+
+	return ret
+}
+
+type keywordPosition struct {
+	keyword string
+	start   int
+}
+
+// findTopLevelKeywords returns the positions of keywords appearing outside quotes and parentheses, in order.
+func findTopLevelKeywords(s string, keywords []string) []keywordPosition {
+	mask := topLevelMask(s)
+	ret := make([]keywordPosition, 0)
+
+	for i := 0; i < len(s); i++ {
+		if !mask[i] || (i > 0 && isIdentifierChar(s[i-1])) {
+			continue
+		}
+
+		for _, k := range keywords {
+			end := i + len(k)
+			if end > len(s) || s[i:end] != k || !mask[end-1] {
+				continue
+			}
+			if end < len(s) && isIdentifierChar(s[end]) {
+				continue
+			}
+
+			ret = append(ret, keywordPosition{keyword: k, start: i})
+			i = end - 1
+			break
+		}
+	}
+
+	return ret
+}
+
+// topLevelMask reports, for every byte of s, whether it is outside of any quoted string and parentheses.
+func topLevelMask(s string) []bool {
+	mask := make([]bool, len(s))
+	depth := 0
+	var quoteChar byte
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		if quoteChar != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quoteChar {
+				quoteChar = 0
+			}
+			continue
+		}
+
+		switch c {
+		case '\'', '`', '"':
+			quoteChar = c
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		default:
+			mask[i] = depth == 0
+		}
+	}
+
+	return mask
+}
+
+// splitTopLevel splits s on sep occurrences that are outside quotes and parentheses. Elements are trimmed.
+func splitTopLevel(s string, sep byte) []string {
+	mask := topLevelMask(s)
+	ret := make([]string, 0)
+
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == sep && mask[i] {
+			ret = append(ret, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	ret = append(ret, strings.TrimSpace(s[start:]))
+
+	return ret
+}
+
+// matchingParen returns the index of the parenthesis closing the one at index open, or -1.
+func matchingParen(s string, open int) int {
+	depth := 0
+	var quoteChar byte
+
+	for i := open; i < len(s); i++ {
+		c := s[i]
+
+		if quoteChar != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quoteChar {
+				quoteChar = 0
+			}
+			continue
+		}
+
+		switch c {
+		case '\'', '`', '"':
+			quoteChar = c
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// readIdentifier reads a plain, backticked, double-quoted or single-quoted identifier at the start of s.
+func readIdentifier(s string) (string, string) {
+	s = strings.TrimLeft(s, " \t\n")
+	if s == "" {
+		return "", s
+	}
+
+	switch s[0] {
+	case '`', '"', '\'':
+		for i := 1; i < len(s); i++ {
+			if s[i] == '\\' {
+				i++
+				continue
+			}
+			if s[i] == s[0] {
+				return unquote(s[:i+1]), s[i+1:]
+			}
+		}
+		return unquote(s), ""
+	}
+
+	i := 0
+	for i < len(s) && isIdentifierChar(s[i]) {
+		i++
+	}
+
+	return s[:i], s[i:]
+}
+
+// trimKeywords removes the given (possibly multi-word) keyword from the beginning of s.
+func trimKeywords(s string, keyword string) (string, bool) {
+	s = strings.TrimLeft(s, " \t\n")
+	if !strings.HasPrefix(s, keyword) {
+		return s, false
+	}
+	if len(s) > len(keyword) && isIdentifierChar(s[len(keyword)]) {
+		return s, false
+	}
+
+	return s[len(keyword):], true
+}
+
+func startsWithAnyKeyword(s string, keywords []string) bool {
+	for _, k := range keywords {
+		if _, ok := trimKeywords(s, k); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unquote removes surrounding quotes or backticks and resolves backslash escapes.
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 {
+		return s
+	}
+
+	first, last := s[0], s[len(s)-1]
+	if first != last || (first != '\'' && first != '`' && first != '"') {
+		return s
+	}
+
+	var sb strings.Builder
+	inner := s[1 : len(s)-1]
+	for i := 0; i < len(inner); i++ {
+		if inner[i] == '\\' && i+1 < len(inner) {
+			i++
+			switch inner[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			default:
+				sb.WriteByte(inner[i])
+			}
+			continue
+		}
+		sb.WriteByte(inner[i])
+	}
+
+	return sb.String()
+}
+
+// unbacktick removes backticks only when the whole expression is a quoted identifier.
+func unbacktick(s string) string {
+	if strings.HasPrefix(s, "`") {
+		if name, rest := readIdentifier(s); strings.TrimSpace(rest) == "" {
+			return name
+		}
+	}
+
+	return s
+}
+
+func isIdentifierChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,162 @@
+package dbops
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+func TestParseCreateTableQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    *Table
+		wantErr bool
+	}{
+		{
+			name:  "simple MergeTree table",
+			query: "CREATE TABLE mydb.mytable (`id` UInt64, `name` String) ENGINE = MergeTree ORDER BY id SETTINGS index_granularity = 8192",
+			want: &Table{
+				DatabaseName: "mydb",
+				Name:         "mytable",
+				Engine:       "MergeTree",
+				Columns: []querybuilder.TableColumn{
+					{Name: "id", Type: "UInt64"},
+					{Name: "name", Type: "String"},
+				},
+				OrderBy:  []string{"id"},
+				Settings: map[string]string{"index_granularity": "8192"},
+			},
+		},
+		{
+			name: "all clauses",
+			query: "CREATE TABLE `my-db`.`events` (`ts` DateTime CODEC(Delta(4), ZSTD(1)), `user_id` UInt64 COMMENT 'the user, really', " +
+				"`day` Date MATERIALIZED toDate(ts), `level` Enum8('DEBUG' = 1, 'INFO' = 2) DEFAULT 'INFO', `raw` String EPHEMERAL, " +
+				"`x` Nullable(String) ALIAS concat('a', 'b'), INDEX idx user_id TYPE minmax GRANULARITY 1, " +
+				"INDEX raw_tokens lower(raw) TYPE tokenbf_v1(512, 3, 0) GRANULARITY 4, " +
+				"PROJECTION by_day (SELECT day, count() GROUP BY day)) " +
+				"ENGINE = ReplicatedMergeTree('/clickhouse/tables/{shard}/events', '{replica}') PARTITION BY toYYYYMM(ts) " +
+				"PRIMARY KEY (user_id, ts) ORDER BY (user_id, ts, `day`) SAMPLE BY user_id TTL ts + toIntervalDay(30) " +
+				"SETTINGS index_granularity = 8192, merge_with_ttl_timeout = 86400 COMMENT 'It\\'s a table'",
+			want: &Table{
+				DatabaseName: "my-db",
+				Name:         "events",
+				Engine:       "ReplicatedMergeTree('/clickhouse/tables/{shard}/events', '{replica}')",
+				Columns: []querybuilder.TableColumn{
+					{Name: "ts", Type: "DateTime", Codec: strPtr("Delta(4), ZSTD(1)")},
+					{Name: "user_id", Type: "UInt64", Comment: strPtr("the user, really")},
+					{Name: "day", Type: "Date", Default: strPtr("toDate(ts)"), DefaultKind: strPtr("MATERIALIZED")},
+					{Name: "level", Type: "Enum8('DEBUG' = 1, 'INFO' = 2)", Default: strPtr("'INFO'")},
+					{Name: "raw", Type: "String", DefaultKind: strPtr("EPHEMERAL")},
+					{Name: "x", Type: "Nullable(String)", Default: strPtr("concat('a', 'b')"), DefaultKind: strPtr("ALIAS")},
+				},
+				Indexes: []querybuilder.TableIndex{
+					{Name: "idx", Expression: "user_id", Type: "minmax", Granularity: 1},
+					{Name: "raw_tokens", Expression: "lower(raw)", Type: "tokenbf_v1(512, 3, 0)", Granularity: 4},
+				},
+				Projections: []querybuilder.TableProjection{
+					{Name: "by_day", Query: "SELECT day, count() GROUP BY day"},
+				},
+				PartitionBy: strPtr("toYYYYMM(ts)"),
+				PrimaryKey:  []string{"user_id", "ts"},
+				OrderBy:     []string{"user_id", "ts", "day"},
+				SampleBy:    strPtr("user_id"),
+				TTL:         strPtr("ts + toIntervalDay(30)"),
+				Settings:    map[string]string{"index_granularity": "8192", "merge_with_ttl_timeout": "86400"},
+				Comment:     "It's a table",
+			},
+		},
+		{
+			name: "unsupported features",
+			query: "CREATE TABLE db.t (`id` UInt64, `v` String TTL ts + toIntervalDay(1) STATISTICS(tdigest), `ts` DateTime, " +
+				"CONSTRAINT positive CHECK id > 0) ENGINE = MySQL('host:3306', 'db', 't', 'user', '[HIDDEN]')",
+			want: &Table{
+				DatabaseName: "db",
+				Name:         "t",
+				Engine:       "MySQL('host:3306', 'db', 't', 'user', '[HIDDEN]')",
+				Columns: []querybuilder.TableColumn{
+					{Name: "id", Type: "UInt64"},
+					{Name: "v", Type: "String"},
+					{Name: "ts", Type: "DateTime"},
+				},
+				Unsupported: []string{
+					`STATISTICS of column "v"`,
+					`TTL of column "v"`,
+					`constraint "positive"`,
+					"engine parameters hidden by ClickHouse",
+				},
+			},
+		},
+		{
+			name:  "null modifier and default null",
+			query: "CREATE TABLE db.t (`a` String NULL DEFAULT NULL, `b` UInt8 NOT NULL) ENGINE = Memory",
+			want: &Table{
+				DatabaseName: "db",
+				Name:         "t",
+				Engine:       "Memory",
+				Columns: []querybuilder.TableColumn{
+					{Name: "a", Type: "Nullable(String)", Default: strPtr("NULL")},
+					{Name: "b", Type: "UInt8"},
+				},
+			},
+		},
+		{
+			name:  "empty sorting key",
+			query: "CREATE TABLE db.t (`a` String) ENGINE = MergeTree ORDER BY tuple() SETTINGS index_granularity = 8192;",
+			want: &Table{
+				DatabaseName: "db",
+				Name:         "t",
+				Engine:       "MergeTree",
+				Columns: []querybuilder.TableColumn{
+					{Name: "a", Type: "String"},
+				},
+				Settings: map[string]string{"index_granularity": "8192"},
+			},
+		},
+		{
+			name:  "table from source function",
+			query: "CREATE TABLE db.t (`a` String) AS s3(events_bucket, format = 'Parquet', `url` = 'https://bucket/it\\'s/*.parquet') COMMENT 'snapshot'",
+			want: &Table{
+				DatabaseName: "db",
+				Name:         "t",
+				Columns: []querybuilder.TableColumn{
+					{Name: "a", Type: "String"},
+				},
+				SourceFunction: &querybuilder.TableFunction{
+					Name:            "s3",
+					NamedCollection: "events_bucket",
+					Arguments:       map[string]string{"format": "Parquet", "url": "https://bucket/it's/*.parquet"},
+				},
+				Comment: "snapshot",
+			},
+		},
+		{
+			name:    "not a table",
+			query:   "CREATE VIEW db.v AS SELECT 1",
+			wantErr: true,
+		},
+		{
+			name:    "unbalanced column list",
+			query:   "CREATE TABLE db.t (`a` String ENGINE = Memory",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCreateTableQuery(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCreateTableQuery() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCreateTableQuery() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,68 @@
+package dbops
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// CurrentUser is the user the provider is connected as.
+type CurrentUser struct {
+	Name string
+	// EnabledRoles holds the roles active in the session, including the ones inherited through other roles.
+	EnabledRoles []string
+}
+
+// GetCurrentUser returns the user the client is connected as, on the replica hit by the query.
+func (i *impl) GetCurrentUser(ctx context.Context) (*CurrentUser, error) {
+	sql, err := querybuilder.NewSelect(
+		[]querybuilder.Field{querybuilder.NewExpressionField("currentUser()", "name")},
+		"system.one",
+	).Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	user := &CurrentUser{
+		EnabledRoles: make([]string, 0),
+	}
+
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		user.Name, err = data.GetString("name")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'name' field")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	sql, err = querybuilder.NewSelect(
+		[]querybuilder.Field{querybuilder.NewField("role_name")},
+		"system.enabled_roles",
+	).OrderBy("role_name").Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		roleName, err := data.GetString("role_name")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'role_name' field")
+		}
+
+		user.EnabledRoles = append(user.EnabledRoles, roleName)
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return user, nil
+}
@@ -2,6 +2,9 @@ package dbops
 
 import (
 	"context"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/pingcap/errors"
 
@@ -13,51 +16,91 @@ type Database struct {
 	UUID    string `json:"uuid"`
 	Name    string `json:"name"`
 	Comment string `json:"comment" ch:"comment"`
+	// Engine is read back with its parameters, or only its name on servers not reporting them.
+	Engine string `json:"engine,omitempty"`
+	// Settings are only used on creation, they are not read back.
+	Settings map[string]string `json:"settings,omitempty"`
 }
 
 func (i *impl) CreateDatabase(ctx context.Context, database Database, clusterName *string) (*Database, error) {
-	builder := querybuilder.NewCreateDatabase(database.Name).WithCluster(clusterName)
+	if err := i.CheckDatabaseManageable(database.Name); err != nil {
+		return nil, err
+	}
+
 	if database.Comment != "" {
-		builder.WithComment(database.Comment)
+		if err := i.requires(ctx, featureDatabaseComment); err != nil {
+			return nil, err
+		}
 	}
-	sql, err := builder.Build()
+	sql, err := CreateDatabaseStatement(database, clusterName)
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, sql)
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return nil, err
+	}
+
+	err = i.execWithRetry(ctx, sql, func(ctx context.Context) (bool, error) {
+		db, err := i.FindDatabaseByName(ctx, database.Name, clusterName)
+		return db != nil, err
+	})
 	if err != nil {
 		return nil, errors.WithMessage(err, "error running query")
 	}
 
-	return i.FindDatabaseByName(ctx, database.Name, clusterName)
+	created, err := readAfterCreate(ctx, i, clusterName, func(ctx context.Context) (*Database, error) {
+		return i.findDatabase(ctx, database.Name, clusterName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if created == nil {
+		return nil, errors.New("database with such name not found")
+	}
+
+	return created, nil
+}
+
+// CreateDatabaseStatement returns the CREATE DATABASE statement CreateDatabase runs for the given database.
+func CreateDatabaseStatement(database Database, clusterName *string) (string, error) {
+	builder := querybuilder.NewCreateDatabase(database.Name).WithCluster(clusterName)
+	if database.Engine != "" {
+		builder.WithEngine(database.Engine)
+	}
+	if len(database.Settings) > 0 {
+		builder.WithSettings(database.Settings)
+	}
+	if database.Comment != "" {
+		builder.WithComment(database.Comment)
+	}
+
+	return builder.Build()
 }
 
 func (i *impl) GetDatabase(ctx context.Context, uuid string, clusterName *string) (*Database, error) {
-	sql, err := querybuilder.NewSelect(
-		[]querybuilder.Field{querybuilder.NewField("name"), querybuilder.NewField("comment")},
+	fields, err := i.databaseFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := querybuilder.NewSelect(
+		fields,
 		"system.databases",
-	).WithCluster(clusterName).Where(querybuilder.WhereEquals("uuid", uuid)).Build()
+	).WithCluster(i.readCluster(clusterName)).Where(querybuilder.WhereEquals("uuid", querybuilder.NewParameter("uuid", "UUID", uuid)))
+	sql, err := query.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
 	var database *Database
 
-	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
-		n, err := data.GetString("name")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'name' field")
-		}
-		c, err := data.GetString("comment")
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
+		database, err = databaseFromRow(data)
 		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'comment' field")
-		}
-		database = &Database{
-			UUID:    uuid,
-			Name:    n,
-			Comment: c,
+			return err
 		}
+		database.UUID = uuid
 		return nil
 	})
 	if err != nil {
@@ -72,6 +115,96 @@ func (i *impl) GetDatabase(ctx context.Context, uuid string, clusterName *string
 	return database, nil
 }
 
+// ListDatabases returns every database of the server, sorted by name. With a cluster, databases existing on only some
+// of the shards are listed as well.
+func (i *impl) ListDatabases(ctx context.Context, clusterName *string) ([]*Database, error) {
+	fields, err := i.databaseFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sql, err := querybuilder.NewSelect(
+		append(fields, querybuilder.NewField("uuid")),
+		"system.databases",
+	).WithCluster(i.readCluster(clusterName)).Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	databases := make(map[string]*Database)
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		database, err := databaseFromRow(data)
+		if err != nil {
+			return err
+		}
+		database.UUID, err = data.GetString("uuid")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'uuid' field")
+		}
+
+		// Each shard reports its own copy of the databases created ON CLUSTER.
+		databases[database.Name] = database
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	ret := slices.Collect(maps.Values(databases))
+	slices.SortFunc(ret, func(a, b *Database) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return ret, nil
+}
+
+// databaseFields returns the fields to select from system.databases to build a Database with databaseFromRow.
+func (i *impl) databaseFields(ctx context.Context) ([]querybuilder.Field, error) {
+	commentField := querybuilder.NewField("comment")
+	if ok, err := i.supports(ctx, featureDatabaseComment); err != nil {
+		return nil, err
+	} else if !ok {
+		commentField = querybuilder.NewExpressionField("''", "comment")
+	}
+
+	engineField := querybuilder.NewField("engine_full")
+	if ok, err := i.supports(ctx, featureDatabaseEngineFull); err != nil {
+		return nil, err
+	} else if !ok {
+		engineField = querybuilder.NewExpressionField("engine", "engine_full")
+	}
+
+	return []querybuilder.Field{querybuilder.NewField("name"), commentField, querybuilder.NewField("engine"), engineField}, nil
+}
+
+func databaseFromRow(data clickhouseclient.Row) (*Database, error) {
+	n, err := data.GetString("name")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'name' field")
+	}
+	c, err := data.GetString("comment")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'comment' field")
+	}
+	e, err := data.GetString("engine_full")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'engine_full' field")
+	}
+	if e == "" {
+		// Some engines don't report their full definition.
+		e, err = data.GetString("engine")
+		if err != nil {
+			return nil, errors.WithMessage(err, "error scanning query result, missing 'engine' field")
+		}
+	}
+
+	return &Database{
+		Name:    n,
+		Comment: c,
+		Engine:  e,
+	}, nil
+}
+
 func (i *impl) DeleteDatabase(ctx context.Context, uuid string, clusterName *string) error {
 	database, err := i.GetDatabase(ctx, uuid, clusterName)
 	if err != nil {
@@ -83,12 +216,23 @@ func (i *impl) DeleteDatabase(ctx context.Context, uuid string, clusterName *str
 		return nil
 	}
 
+	if err := i.CheckDatabaseManageable(database.Name); err != nil {
+		return err
+	}
+
 	sql, err := querybuilder.NewDropDatabase(database.Name).WithCluster(clusterName).Build()
 	if err != nil {
 		return errors.WithMessage(err, "error building query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, sql)
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return err
+	}
+
+	err = i.execWithRetry(ctx, sql, func(ctx context.Context) (bool, error) {
+		db, err := i.GetDatabase(ctx, uuid, clusterName)
+		return db == nil, err
+	})
 	if err != nil {
 		return errors.WithMessage(err, "error running query")
 	}
@@ -96,18 +240,105 @@ func (i *impl) DeleteDatabase(ctx context.Context, uuid string, clusterName *str
 	return nil
 }
 
+// UpdateDatabaseSettings changes engine settings of the database, values being SQL literals.
+func (i *impl) UpdateDatabaseSettings(ctx context.Context, uuid string, settings map[string]string, clusterName *string) error {
+	database, err := i.GetDatabase(ctx, uuid, clusterName)
+	if err != nil {
+		return errors.WithMessage(err, "error getting database name")
+	}
+	if database == nil {
+		return errors.New("database not found")
+	}
+
+	sql, err := querybuilder.NewAlterDatabaseModifySetting(database.Name, settings).WithCluster(clusterName).Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
+	}
+
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return err
+	}
+
+	// MODIFY SETTING is idempotent, it can be retried as is.
+	err = i.execWithRetry(ctx, sql, nil)
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
+	}
+
+	return nil
+}
+
+// AttachDatabaseTables attaches tables to the database, e.g. to start replicating more tables in a
+// MaterializedPostgreSQL database.
+func (i *impl) AttachDatabaseTables(ctx context.Context, uuid string, tableNames []string, clusterName *string) error {
+	return i.execDatabaseTables(ctx, uuid, tableNames, clusterName, func(databaseName string, tableName string) (string, error) {
+		return querybuilder.NewAttachTable(databaseName, tableName).WithCluster(clusterName).Build()
+	})
+}
+
+// DetachDatabaseTables permanently detaches tables from the database, e.g. to stop replicating them in a
+// MaterializedPostgreSQL database.
+func (i *impl) DetachDatabaseTables(ctx context.Context, uuid string, tableNames []string, clusterName *string) error {
+	return i.execDatabaseTables(ctx, uuid, tableNames, clusterName, func(databaseName string, tableName string) (string, error) {
+		return querybuilder.NewDetachTable(databaseName, tableName).WithCluster(clusterName).WithPermanently(true).Build()
+	})
+}
+
+func (i *impl) execDatabaseTables(ctx context.Context, uuid string, tableNames []string, clusterName *string, build func(databaseName string, tableName string) (string, error)) error {
+	database, err := i.GetDatabase(ctx, uuid, clusterName)
+	if err != nil {
+		return errors.WithMessage(err, "error getting database name")
+	}
+	if database == nil {
+		return errors.New("database not found")
+	}
+
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return err
+	}
+
+	for _, tableName := range tableNames {
+		sql, err := build(database.Name, tableName)
+		if err != nil {
+			return errors.WithMessage(err, "error building query")
+		}
+
+		err = i.clickhouseClient.Exec(ctx, sql)
+		if err != nil {
+			return errors.WithMessage(err, "error running query")
+		}
+	}
+
+	return nil
+}
+
 func (i *impl) FindDatabaseByName(ctx context.Context, name string, clusterName *string) (*Database, error) {
-	sql, err := querybuilder.NewSelect(
+	database, err := i.findDatabase(ctx, name, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	if database == nil {
+		return nil, errors.New("database with such name not found")
+	}
+
+	return database, nil
+}
+
+// findDatabase returns the database with the given name, or nil if it does not exist.
+func (i *impl) findDatabase(ctx context.Context, name string, clusterName *string) (*Database, error) {
+	query := querybuilder.NewSelect(
 		[]querybuilder.Field{querybuilder.NewField("uuid")},
 		"system.databases",
-	).WithCluster(clusterName).Where(querybuilder.WhereEquals("name", name)).Build()
+	).WithCluster(i.readCluster(clusterName)).Where(querybuilder.WhereEquals("name", querybuilder.NewParameter("name", "String", name)))
+	sql, err := query.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
 	var uuid string
 
-	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
 		uuid, err = data.GetString("uuid")
 		if err != nil {
 			return errors.WithMessage(err, "error scanning query result, missing 'uuid' field")
@@ -120,7 +351,7 @@ func (i *impl) FindDatabaseByName(ctx context.Context, name string, clusterName
 	}
 
 	if uuid == "" {
-		return nil, errors.New("database with such name not found")
+		return nil, nil
 	}
 
 	return i.GetDatabase(ctx, uuid, clusterName)
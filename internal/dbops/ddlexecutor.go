@@ -0,0 +1,106 @@
+package dbops
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// DDLStep creates one object of a group of dependent objects, e.g. the local tables of a Distributed table and the
+// Distributed table itself.
+type DDLStep struct {
+	// Name identifies the step in the DependsOn lists of the other steps and in errors.
+	Name string
+	// DependsOn are the names of the steps creating the objects this one relies on, which are applied before it.
+	DependsOn []string
+	// Apply creates the object.
+	Apply func(ctx context.Context) error
+	// Rollback drops the object created by Apply. It is called when a later step fails, and can be nil when there
+	// is nothing to undo.
+	Rollback func(ctx context.Context) error
+}
+
+// ExecuteDDL applies the steps in dependency order, keeping the given order between independent steps. When a step
+// fails, the steps already applied are rolled back in reverse order so that no half-created group of objects is left
+// behind, and the returned error names the objects that could not be rolled back.
+func ExecuteDDL(ctx context.Context, steps []DDLStep) error {
+	ordered, err := orderDDLSteps(steps)
+	if err != nil {
+		return err
+	}
+
+	for n, step := range ordered {
+		err := step.Apply(ctx)
+		if err == nil {
+			continue
+		}
+
+		leftovers := make([]string, 0)
+		for i := n - 1; i >= 0; i-- {
+			if ordered[i].Rollback == nil {
+				continue
+			}
+			if rollbackErr := ordered[i].Rollback(ctx); rollbackErr != nil {
+				leftovers = append(leftovers, fmt.Sprintf("%s (%v)", ordered[i].Name, rollbackErr))
+			}
+		}
+
+		if len(leftovers) > 0 {
+			return errors.WithMessage(err, fmt.Sprintf("error applying %s, rolling back %s failed and must be cleaned up manually", step.Name, strings.Join(leftovers, ", ")))
+		}
+		return errors.WithMessage(err, fmt.Sprintf("error applying %s", step.Name))
+	}
+
+	return nil
+}
+
+// orderDDLSteps sorts the steps so that every step comes after the ones it depends on.
+func orderDDLSteps(steps []DDLStep) ([]DDLStep, error) {
+	byName := make(map[string]bool)
+	for _, step := range steps {
+		if byName[step.Name] {
+			return nil, errors.New(fmt.Sprintf("duplicate DDL step %q", step.Name))
+		}
+		byName[step.Name] = true
+	}
+	for _, step := range steps {
+		for _, dep := range step.DependsOn {
+			if !byName[dep] {
+				return nil, errors.New(fmt.Sprintf("DDL step %q depends on unknown step %q", step.Name, dep))
+			}
+		}
+	}
+
+	ordered := make([]DDLStep, 0, len(steps))
+	done := make(map[string]bool)
+	for len(ordered) < len(steps) {
+		progress := false
+		for _, step := range steps {
+			if done[step.Name] || !allDone(step.DependsOn, done) {
+				continue
+			}
+			ordered = append(ordered, step)
+			done[step.Name] = true
+			progress = true
+			// Start over, so that independent steps keep their order.
+			break
+		}
+		if !progress {
+			return nil, errors.New("DDL steps have circular dependencies")
+		}
+	}
+
+	return ordered, nil
+}
+
+func allDone(names []string, done map[string]bool) bool {
+	for _, name := range names {
+		if !done[name] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,103 @@
+package dbops
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pingcap/errors"
+)
+
+func TestExecuteDDL(t *testing.T) {
+	var log []string
+	step := func(name string, fail bool, dependsOn ...string) DDLStep {
+		return DDLStep{
+			Name:      name,
+			DependsOn: dependsOn,
+			Apply: func(context.Context) error {
+				if fail {
+					return errors.New("boom")
+				}
+				log = append(log, "create "+name)
+				return nil
+			},
+			Rollback: func(context.Context) error {
+				log = append(log, "drop "+name)
+				return nil
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		steps   []DDLStep
+		want    []string
+		wantErr string
+	}{
+		{
+			name:  "Dependencies first",
+			steps: []DDLStep{step("distributed", false, "local"), step("local", false), step("view", false, "distributed")},
+			want:  []string{"create local", "create distributed", "create view"},
+		},
+		{
+			name:  "Independent steps keep their order",
+			steps: []DDLStep{step("a", false), step("b", false), step("c", false, "a")},
+			want:  []string{"create a", "create b", "create c"},
+		},
+		{
+			name:    "Rollback in reverse order",
+			steps:   []DDLStep{step("local", false), step("inner", false, "local"), step("distributed", true, "inner")},
+			want:    []string{"create local", "create inner", "drop inner", "drop local"},
+			wantErr: "error applying distributed: boom",
+		},
+		{
+			name:    "Circular dependencies",
+			steps:   []DDLStep{step("a", false, "b"), step("b", false, "a")},
+			want:    nil,
+			wantErr: "circular dependencies",
+		},
+		{
+			name:    "Unknown dependency",
+			steps:   []DDLStep{step("a", false, "b")},
+			want:    nil,
+			wantErr: `depends on unknown step "b"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log = nil
+
+			err := ExecuteDDL(context.Background(), tt.steps)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("ExecuteDDL() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("ExecuteDDL() error = %v, want %q", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(log, tt.want) {
+				t.Errorf("ExecuteDDL() ran %v, want %v", log, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteDDL_rollbackFailure(t *testing.T) {
+	steps := []DDLStep{
+		{
+			Name:     "local",
+			Apply:    func(context.Context) error { return nil },
+			Rollback: func(context.Context) error { return errors.New("replica down") },
+		},
+		{
+			Name:      "distributed",
+			DependsOn: []string{"local"},
+			Apply:     func(context.Context) error { return errors.New("boom") },
+		},
+	}
+
+	err := ExecuteDDL(context.Background(), steps)
+	if err == nil || !strings.Contains(err.Error(), "rolling back local (replica down) failed") {
+		t.Errorf("ExecuteDDL() error = %v, want the rollback failure reported", err)
+	}
+}
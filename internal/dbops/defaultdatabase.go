@@ -0,0 +1,7 @@
+package dbops
+
+// DefaultDatabase returns the database resources use when their configuration leaves database_name out, empty when
+// the provider has none.
+func (i *impl) DefaultDatabase() string {
+	return i.config.DefaultDatabase
+}
@@ -0,0 +1,218 @@
+package dbops
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// DetachedPart is a part in the detached directory of a table, either detached on purpose or by ClickHouse because
+// it was broken or unexpected.
+type DetachedPart struct {
+	Host             string `json:"host"`
+	DatabaseName     string `json:"database"`
+	TableName        string `json:"table"`
+	Name             string `json:"name"`
+	PartitionID      string `json:"partition_id"`
+	DiskName         string `json:"disk"`
+	Reason           string `json:"reason"`
+	BytesOnDisk      uint64 `json:"bytes_on_disk"`
+	ModificationTime string `json:"modification_time"`
+}
+
+// GetDetachedParts returns the detached parts of the tables of the given database, or of one table when tableName is
+// set. Detached parts are local to each replica, so with a cluster every replica is read. The size and modification
+// time are left empty on servers too old to report them.
+func (i *impl) GetDetachedParts(ctx context.Context, databaseName string, tableName *string, clusterName *string) ([]DetachedPart, error) {
+	details, err := i.supports(ctx, featureDetachedPartDetails)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := []querybuilder.Field{
+		querybuilder.NewExpressionField("hostName()", "host"),
+		querybuilder.NewField("database"),
+		querybuilder.NewField("table"),
+		querybuilder.NewField("name"),
+		querybuilder.NewExpressionField("ifNull(partition_id, '')", "partition"),
+		querybuilder.NewField("disk"),
+		querybuilder.NewExpressionField("ifNull(reason, '')", "detach_reason"),
+	}
+	if details {
+		fields = append(fields,
+			querybuilder.NewExpressionField("toUInt64(bytes_on_disk)", "size"),
+			querybuilder.NewExpressionField("toString(modification_time)", "modified_at"),
+		)
+	} else {
+		fields = append(fields,
+			querybuilder.NewExpressionField("toUInt64(0)", "size"),
+			querybuilder.NewExpressionField("''", "modified_at"),
+		)
+	}
+
+	where := []querybuilder.Where{querybuilder.WhereEquals("database", querybuilder.NewParameter("database", "String", databaseName))}
+	if tableName != nil {
+		where = append(where, querybuilder.WhereEquals("table", querybuilder.NewParameter("table", "String", *tableName)))
+	}
+
+	query := querybuilder.NewSelect(fields, "system.detached_parts").
+		WithClusterAllReplicas(i.readCluster(clusterName)).
+		Where(where...).
+		OrderBy("database", "table", "name", "host")
+	sql, err := query.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	ret := make([]DetachedPart, 0)
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
+		p, err := detachedPartFromRow(data)
+		if err != nil {
+			return err
+		}
+
+		ret = append(ret, *p)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return ret, nil
+}
+
+// GetExpiredDetachedPartitions returns the IDs of the partitions of the table whose detached parts were all modified
+// before the olderThan SQL expression, e.g. `now() - INTERVAL 30 DAY`. With a cluster, the detached parts of every
+// replica are considered. Parts whose name doesn't tell their partition are never returned.
+func (i *impl) GetExpiredDetachedPartitions(ctx context.Context, databaseName string, tableName string, olderThan string, clusterName *string) ([]string, error) {
+	if olderThan == "" {
+		return nil, errors.New("olderThan expression cannot be empty")
+	}
+	// olderThan is embedded in the query as is.
+	if err := querybuilder.CheckExpression(olderThan); err != nil {
+		return nil, errors.WithMessage(err, "invalid olderThan expression")
+	}
+
+	if err := i.requires(ctx, featureDetachedPartDetails); err != nil {
+		return nil, err
+	}
+
+	query := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewField("partition_id"),
+			querybuilder.NewExpressionField(fmt.Sprintf("toUInt8(max(modification_time) < (%s))", olderThan), "expired"),
+		},
+		"system.detached_parts",
+	).WithClusterAllReplicas(i.readCluster(clusterName)).
+		Where(
+			querybuilder.WhereEquals("database", querybuilder.NewParameter("database", "String", databaseName)),
+			querybuilder.WhereEquals("table", querybuilder.NewParameter("table", "String", tableName)),
+			querybuilder.IsNotNull("partition_id"),
+		).
+		GroupBy("partition_id").
+		OrderBy("partition_id")
+	sql, err := query.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	ret := make([]string, 0)
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
+		partitionID, err := data.GetString("partition_id")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'partition_id' field")
+		}
+		expired, err := data.GetBool("expired")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'expired' field")
+		}
+
+		if expired {
+			ret = append(ret, partitionID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return ret, nil
+}
+
+// DropDetachedPartitions deletes the detached parts of the given partitions of the table from disk, on every replica
+// of the cluster when set.
+func (i *impl) DropDetachedPartitions(ctx context.Context, databaseName string, tableName string, partitionIDs []string, clusterName *string) error {
+	if err := i.CheckDatabaseManageable(databaseName); err != nil {
+		return err
+	}
+
+	sql, err := querybuilder.WithStatementSettings(
+		querybuilder.NewAlterTableDropDetachedPartition(databaseName, tableName, partitionIDs).WithCluster(clusterName),
+		map[string]string{"allow_drop_detached": "1"},
+	).Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building ALTER TABLE DROP DETACHED PARTITION query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		return errors.WithMessage(err, "error dropping detached partitions")
+	}
+
+	return nil
+}
+
+func detachedPartFromRow(data clickhouseclient.Row) (*DetachedPart, error) {
+	host, err := data.GetString("host")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'host' field")
+	}
+	databaseName, err := data.GetString("database")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'database' field")
+	}
+	tableName, err := data.GetString("table")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'table' field")
+	}
+	name, err := data.GetString("name")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'name' field")
+	}
+	partitionID, err := data.GetString("partition")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'partition' field")
+	}
+	diskName, err := data.GetString("disk")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'disk' field")
+	}
+	reason, err := data.GetString("detach_reason")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'detach_reason' field")
+	}
+	size, err := data.GetUInt64("size")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'size' field")
+	}
+	modifiedAt, err := data.GetString("modified_at")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'modified_at' field")
+	}
+
+	return &DetachedPart{
+		Host:             host,
+		DatabaseName:     databaseName,
+		TableName:        tableName,
+		Name:             name,
+		PartitionID:      partitionID,
+		DiskName:         diskName,
+		Reason:           reason,
+		BytesOnDisk:      size,
+		ModificationTime: modifiedAt,
+	}, nil
+}
@@ -0,0 +1,286 @@
+package dbops
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// Dictionary is a dictionary created with CREATE DICTIONARY.
+type Dictionary struct {
+	UUID         string `json:"uuid"`
+	DatabaseName string `json:"database_name"`
+	Name         string `json:"name"`
+	// Attributes are the columns of the dictionary, key columns included. When read back, key columns come first and
+	// only names and types are known.
+	Attributes []DictionaryAttribute `json:"attributes"`
+	PrimaryKey []string              `json:"primary_key"`
+	// Source is only used on creation, system.dictionaries only describes it.
+	Source DictionaryFunction `json:"source"`
+	// Layout is read back without parameters, with the name ClickHouse gives it, e.g. ComplexKeyHashed.
+	Layout      DictionaryFunction `json:"layout"`
+	LifetimeMin uint64             `json:"lifetime_min"`
+	LifetimeMax uint64             `json:"lifetime_max"`
+	Comment     string             `json:"comment"`
+}
+
+type DictionaryAttribute struct {
+	Name         string  `json:"name"`
+	Type         string  `json:"type"`
+	Default      *string `json:"default"`
+	Expression   *string `json:"expression"`
+	Hierarchical bool    `json:"hierarchical"`
+	Injective    bool    `json:"injective"`
+}
+
+// DictionaryFunction is the source or the layout of a dictionary.
+type DictionaryFunction struct {
+	Name       string            `json:"name"`
+	Parameters map[string]string `json:"parameters"`
+}
+
+func (i *impl) CreateDictionary(ctx context.Context, dictionary Dictionary, clusterName *string) (*Dictionary, error) {
+	if err := i.CheckDatabaseManageable(dictionary.DatabaseName); err != nil {
+		return nil, err
+	}
+
+	if dictionary.Comment != "" {
+		if err := i.requires(ctx, featureTableComment); err != nil {
+			return nil, err
+		}
+	}
+
+	sql, err := createDictionaryStatement(dictionary, clusterName)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return nil, err
+	}
+
+	err = i.execWithRetry(ctx, sql, func(ctx context.Context) (bool, error) {
+		d, err := i.findDictionary(ctx, dictionary.DatabaseName, dictionary.Name, clusterName)
+		return d != nil, err
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	created, err := readAfterCreate(ctx, i, clusterName, func(ctx context.Context) (*Dictionary, error) {
+		return i.findDictionary(ctx, dictionary.DatabaseName, dictionary.Name, clusterName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if created == nil {
+		return nil, errors.New("dictionary with such name not found")
+	}
+
+	return created, nil
+}
+
+// createDictionaryStatement returns the CREATE DICTIONARY statement for the given dictionary. It is not exposed
+// because it holds the password of the source.
+func createDictionaryStatement(dictionary Dictionary, clusterName *string) (string, error) {
+	attributes := make([]querybuilder.DictionaryAttribute, 0, len(dictionary.Attributes))
+	for _, attribute := range dictionary.Attributes {
+		attributes = append(attributes, querybuilder.DictionaryAttribute{
+			Name:         attribute.Name,
+			Type:         attribute.Type,
+			Default:      attribute.Default,
+			Expression:   attribute.Expression,
+			Hierarchical: attribute.Hierarchical,
+			Injective:    attribute.Injective,
+		})
+	}
+
+	builder := querybuilder.NewCreateDictionary(dictionary.DatabaseName, dictionary.Name, attributes, dictionary.PrimaryKey).
+		WithSource(querybuilder.DictionaryFunction{Name: dictionary.Source.Name, Parameters: dictionary.Source.Parameters}).
+		WithLayout(querybuilder.DictionaryFunction{Name: dictionary.Layout.Name, Parameters: dictionary.Layout.Parameters}).
+		WithCluster(clusterName)
+	if dictionary.LifetimeMax > 0 {
+		builder.WithLifetime(dictionary.LifetimeMin, dictionary.LifetimeMax)
+	}
+	if dictionary.Comment != "" {
+		builder.WithComment(dictionary.Comment)
+	}
+
+	return builder.Build()
+}
+
+// GetDictionary returns the dictionary with the given UUID, or nil if it does not exist.
+func (i *impl) GetDictionary(ctx context.Context, uuid string, clusterName *string) (*Dictionary, error) {
+	return i.selectDictionary(ctx, clusterName, querybuilder.WhereEquals("uuid", querybuilder.NewParameter("uuid", "UUID", uuid)))
+}
+
+func (i *impl) FindDictionaryByName(ctx context.Context, databaseName, dictionaryName string, clusterName *string) (*Dictionary, error) {
+	dictionary, err := i.findDictionary(ctx, databaseName, dictionaryName, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	if dictionary == nil {
+		return nil, errors.New("dictionary with such name not found")
+	}
+
+	return dictionary, nil
+}
+
+// findDictionary returns the dictionary with the given name, or nil if it does not exist.
+func (i *impl) findDictionary(ctx context.Context, databaseName, dictionaryName string, clusterName *string) (*Dictionary, error) {
+	return i.selectDictionary(
+		ctx,
+		clusterName,
+		querybuilder.WhereEquals("database", querybuilder.NewParameter("database", "String", databaseName)),
+		querybuilder.WhereEquals("name", querybuilder.NewParameter("name", "String", dictionaryName)),
+	)
+}
+
+func (i *impl) selectDictionary(ctx context.Context, clusterName *string, where ...querybuilder.Where) (*Dictionary, error) {
+	commentField := querybuilder.NewField("comment")
+	if ok, err := i.supports(ctx, featureTableComment); err != nil {
+		return nil, err
+	} else if !ok {
+		commentField = querybuilder.NewExpressionField("''", "comment")
+	}
+
+	// Arrays are joined with new lines, which can't be part of names nor types.
+	query := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewExpressionField("toString(uuid)", "uuid"),
+			querybuilder.NewField("database"),
+			querybuilder.NewField("name"),
+			querybuilder.NewField("type"),
+			querybuilder.NewExpressionField("arrayStringConcat(`key.names`, '\\n')", "key_names"),
+			querybuilder.NewExpressionField("arrayStringConcat(`key.types`, '\\n')", "key_types"),
+			querybuilder.NewExpressionField("arrayStringConcat(`attribute.names`, '\\n')", "attribute_names"),
+			querybuilder.NewExpressionField("arrayStringConcat(`attribute.types`, '\\n')", "attribute_types"),
+			querybuilder.NewExpressionField("toUInt64(lifetime_min)", "lifetime_min"),
+			querybuilder.NewExpressionField("toUInt64(lifetime_max)", "lifetime_max"),
+			commentField,
+		},
+		"system.dictionaries",
+	).WithCluster(i.readCluster(clusterName)).Where(where...)
+	sql, err := query.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	var dictionary *Dictionary
+
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
+		if dictionary != nil {
+			// With a cluster, every replica returns its own copy of the dictionary.
+			return nil
+		}
+
+		fields := make(map[string]string)
+		for _, name := range []string{"uuid", "database", "name", "type", "key_names", "key_types", "attribute_names", "attribute_types", "comment"} {
+			value, err := data.GetString(name)
+			if err != nil {
+				return errors.WithMessage(err, fmt.Sprintf("error scanning query result, missing '%s' field", name))
+			}
+			fields[name] = value
+		}
+		lifetimeMin, err := data.GetUInt64("lifetime_min")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'lifetime_min' field")
+		}
+		lifetimeMax, err := data.GetUInt64("lifetime_max")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'lifetime_max' field")
+		}
+
+		keys := splitLines(fields["key_names"])
+		attributes, err := dictionaryAttributes(keys, splitLines(fields["key_types"]))
+		if err != nil {
+			return err
+		}
+		others, err := dictionaryAttributes(splitLines(fields["attribute_names"]), splitLines(fields["attribute_types"]))
+		if err != nil {
+			return err
+		}
+
+		dictionary = &Dictionary{
+			UUID:         fields["uuid"],
+			DatabaseName: fields["database"],
+			Name:         fields["name"],
+			Attributes:   append(attributes, others...),
+			PrimaryKey:   keys,
+			Layout:       DictionaryFunction{Name: fields["type"]},
+			LifetimeMin:  lifetimeMin,
+			LifetimeMax:  lifetimeMax,
+			Comment:      fields["comment"],
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return dictionary, nil
+}
+
+// dictionaryAttributes pairs the names and types read from system.dictionaries.
+func dictionaryAttributes(names, types []string) ([]DictionaryAttribute, error) {
+	if len(names) != len(types) {
+		return nil, errors.New("dictionary attribute names and types do not match")
+	}
+
+	attributes := make([]DictionaryAttribute, 0, len(names))
+	for idx := range names {
+		attributes = append(attributes, DictionaryAttribute{Name: names[idx], Type: types[idx]})
+	}
+
+	return attributes, nil
+}
+
+// splitLines splits a string joined with new lines, an empty string being an empty list.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(s, "\n")
+}
+
+func (i *impl) DeleteDictionary(ctx context.Context, uuid string, clusterName *string) error {
+	dictionary, err := i.GetDictionary(ctx, uuid, clusterName)
+	if err != nil {
+		return errors.WithMessage(err, "error getting dictionary")
+	}
+
+	if dictionary == nil {
+		// This is desired state.
+		return nil
+	}
+
+	if err := i.CheckDatabaseManageable(dictionary.DatabaseName); err != nil {
+		return err
+	}
+
+	sql, err := querybuilder.NewDropDictionary(dictionary.DatabaseName, dictionary.Name).WithCluster(clusterName).Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
+	}
+
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return err
+	}
+
+	err = i.execWithRetry(ctx, sql, func(ctx context.Context) (bool, error) {
+		d, err := i.GetDictionary(ctx, uuid, clusterName)
+		return d == nil, err
+	})
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
+	}
+
+	return nil
+}
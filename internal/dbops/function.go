@@ -0,0 +1,168 @@
+package dbops
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// functionOrigin is the origin of the functions created with CREATE FUNCTION in system.functions.
+const functionOrigin = "SQLUserDefined"
+
+// Function is a SQL user defined function, i.e. a lambda expression of its arguments.
+type Function struct {
+	Name      string   `json:"name"`
+	Arguments []string `json:"arguments"`
+	// Expression is the body of the lambda. When read back, it is the expression as normalized by the server.
+	Expression string `json:"expression"`
+}
+
+func (i *impl) CreateFunction(ctx context.Context, function Function, clusterName *string) (*Function, error) {
+	sql, err := querybuilder.NewCreateFunction(function.Name, function.Arguments, function.Expression).WithCluster(clusterName).Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	created, err := readAfterCreate(ctx, i, clusterName, func(ctx context.Context) (*Function, error) {
+		return i.GetFunction(ctx, function.Name, clusterName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if created == nil {
+		return nil, errors.New("function with such name not found")
+	}
+
+	return created, nil
+}
+
+// ReplaceFunction changes the arguments and the expression of the function with CREATE OR REPLACE FUNCTION.
+func (i *impl) ReplaceFunction(ctx context.Context, function Function, clusterName *string) (*Function, error) {
+	sql, err := querybuilder.NewCreateFunction(function.Name, function.Arguments, function.Expression).WithOrReplace().WithCluster(clusterName).Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return i.GetFunction(ctx, function.Name, clusterName)
+}
+
+// GetFunction returns the SQL user defined function with the given name, or nil if it does not exist.
+func (i *impl) GetFunction(ctx context.Context, name string, clusterName *string) (*Function, error) {
+	query := querybuilder.NewSelect(
+		[]querybuilder.Field{querybuilder.NewField("create_query")},
+		"system.functions",
+	).WithCluster(i.readCluster(clusterName)).Where(
+		querybuilder.WhereEquals("name", querybuilder.NewParameter("name", "String", name)),
+		querybuilder.WhereEquals("origin", querybuilder.NewParameter("origin", "String", functionOrigin)),
+	)
+	sql, err := query.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	var function *Function
+
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
+		q, err := data.GetString("create_query")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'create_query' field")
+		}
+
+		// With a cluster, every replica returns its own copy of the function.
+		if function != nil {
+			return nil
+		}
+
+		function, err = parseCreateFunctionQuery(q)
+		if err != nil {
+			return errors.WithMessage(err, "error parsing function definition")
+		}
+		function.Name = name
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return function, nil
+}
+
+func (i *impl) DeleteFunction(ctx context.Context, name string, clusterName *string) error {
+	function, err := i.GetFunction(ctx, name, clusterName)
+	if err != nil {
+		return errors.WithMessage(err, "error getting function")
+	}
+
+	if function == nil {
+		// This is desired state.
+		return nil
+	}
+
+	sql, err := querybuilder.NewDropFunction(name).WithCluster(clusterName).Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
+	}
+
+	return nil
+}
+
+// parseCreateFunctionQuery reads the arguments and the expression of a `CREATE FUNCTION f AS (x, y) -> expr`
+// statement. The name is left empty.
+func parseCreateFunctionQuery(query string) (*Function, error) {
+	positions := findTopLevelKeywords(query, []string{"AS"})
+	if len(positions) == 0 {
+		return nil, errors.New("statement is not a CREATE FUNCTION statement")
+	}
+	lambda := strings.TrimSpace(query[positions[0].start+len("AS"):])
+
+	arguments := lambda
+	body := ""
+	if strings.HasPrefix(lambda, "(") {
+		closing := matchingParen(lambda, 0)
+		if closing < 0 {
+			return nil, errors.New("unbalanced parentheses in function arguments")
+		}
+		arguments = lambda[1:closing]
+		body = lambda[closing+1:]
+	} else if idx := strings.Index(lambda, "->"); idx >= 0 {
+		// A single argument is formatted without parentheses.
+		arguments = lambda[:idx]
+		body = lambda[idx:]
+	}
+
+	body, ok := strings.CutPrefix(strings.TrimSpace(body), "->")
+	if !ok {
+		return nil, errors.New("cannot find the expression of the function")
+	}
+
+	function := &Function{
+		Arguments:  make([]string, 0),
+		Expression: strings.TrimSpace(body),
+	}
+	if strings.TrimSpace(arguments) != "" {
+		for _, argument := range splitTopLevel(arguments, ',') {
+			function.Arguments = append(function.Arguments, unbacktick(argument))
+		}
+	}
+
+	return function, nil
+}
@@ -0,0 +1,54 @@
+package dbops
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCreateFunctionQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    *Function
+		wantErr bool
+	}{
+		{
+			name:  "several arguments",
+			query: "CREATE FUNCTION linear_equation AS (x, k, b) -> ((k * x) + b)",
+			want:  &Function{Arguments: []string{"x", "k", "b"}, Expression: "((k * x) + b)"},
+		},
+		{
+			name:  "backticked names",
+			query: "CREATE FUNCTION `my fn` AS (`a b`, c) -> concat(`a b`, ' AS ', c)",
+			want:  &Function{Arguments: []string{"a b", "c"}, Expression: "concat(`a b`, ' AS ', c)"},
+		},
+		{
+			name:  "single argument without parentheses",
+			query: "CREATE FUNCTION parity AS n -> if(n % 2, 'odd', 'even')",
+			want:  &Function{Arguments: []string{"n"}, Expression: "if(n % 2, 'odd', 'even')"},
+		},
+		{
+			name:  "no argument",
+			query: "CREATE FUNCTION answer AS () -> 42",
+			want:  &Function{Arguments: []string{}, Expression: "42"},
+		},
+		{
+			name:    "not a function",
+			query:   "CREATE TABLE t (id UInt64) ENGINE = Memory",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCreateFunctionQuery(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseCreateFunctionQuery() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCreateFunctionQuery() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package dbops
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// GranteeGrants holds all privileges and roles granted to a single user or role.
+type GranteeGrants struct {
+	Privileges []GrantPrivilege
+	Roles      []GrantRole
+}
+
+// GetGranteeGrants returns all privileges and roles granted to the given user or role, read from system.grants and
+// system.role_grants with a single query.
+// Results are shared by all resources of the provider: concurrent calls for the same grantee run the query once,
+// and the result is kept for granteeGrantsCacheTTL, or until the client grants or revokes anything.
+func (i *impl) GetGranteeGrants(ctx context.Context, granteeUserName *string, granteeRoleName *string, clusterName *string) (*GranteeGrants, error) {
+	var key string
+	{
+		if granteeUserName != nil {
+			key = "user:" + *granteeUserName
+		} else if granteeRoleName != nil {
+			key = "role:" + *granteeRoleName
+		} else {
+			return nil, errors.New("either GranteeUserName or GranteeRoleName must be set")
+		}
+
+		if clusterName != nil {
+			key = *clusterName + ":" + key
+		}
+	}
+
+	return i.granteeGrants.get(ctx, key, func(ctx context.Context) (*GranteeGrants, error) {
+		return i.selectGranteeGrants(ctx, granteeUserName, granteeRoleName, clusterName)
+	})
+}
+
+func (i *impl) selectGranteeGrants(ctx context.Context, granteeUserName *string, granteeRoleName *string, clusterName *string) (*GranteeGrants, error) {
+	sql, err := granteeGrantsQuery(granteeUserName, granteeRoleName, func(q querybuilder.SelectQueryBuilder) querybuilder.SelectQueryBuilder {
+		return q.WithCluster(i.readCluster(clusterName))
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	ret := &GranteeGrants{
+		Privileges: make([]GrantPrivilege, 0),
+		Roles:      make([]GrantRole, 0),
+	}
+
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		return scanGranteeGrant(data, ret)
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return ret, nil
+}
+
+// granteeGrantsQuery builds the query reading the grants of a grantee from system.grants and system.role_grants,
+// withCluster sets the cluster both sides of the union read from. The replica field holds the host each grant was
+// read from.
+func granteeGrantsQuery(granteeUserName *string, granteeRoleName *string, withCluster func(querybuilder.SelectQueryBuilder) querybuilder.SelectQueryBuilder) (string, error) {
+	var granteeWhere querybuilder.Where
+	{
+		if granteeUserName != nil {
+			granteeWhere = querybuilder.WhereEquals("user_name", *granteeUserName)
+		} else {
+			granteeWhere = querybuilder.WhereEquals("role_name", *granteeRoleName)
+		}
+	}
+
+	// Both sides of the union return the same fields with the same types, the granted_role_name field tells them apart.
+	return querybuilder.NewUnionAll(
+		withCluster(querybuilder.NewSelect(
+			[]querybuilder.Field{
+				querybuilder.NewExpressionField("toString(access_type)", "access_type"),
+				querybuilder.NewField("database"),
+				querybuilder.NewField("table"),
+				querybuilder.NewField("column"),
+				querybuilder.NewField("user_name"),
+				querybuilder.NewField("role_name"),
+				querybuilder.NewExpressionField("toUInt8(grant_option)", "grant_option"),
+				querybuilder.NewExpressionField("CAST(NULL, 'Nullable(String)')", "granted_role_name"),
+				querybuilder.NewExpressionField("hostName()", "replica"),
+			},
+			"system.grants",
+		)).Where(granteeWhere),
+		withCluster(querybuilder.NewSelect(
+			[]querybuilder.Field{
+				querybuilder.NewExpressionField("''", "access_type"),
+				querybuilder.NewExpressionField("CAST(NULL, 'Nullable(String)')", "database"),
+				querybuilder.NewExpressionField("CAST(NULL, 'Nullable(String)')", "table"),
+				querybuilder.NewExpressionField("CAST(NULL, 'Nullable(String)')", "column"),
+				querybuilder.NewField("user_name"),
+				querybuilder.NewField("role_name"),
+				querybuilder.NewExpressionField("toUInt8(with_admin_option)", "grant_option"),
+				querybuilder.NewExpressionField("toNullable(granted_role_name)", "granted_role_name"),
+				querybuilder.NewExpressionField("hostName()", "replica"),
+			},
+			"system.role_grants",
+		)).Where(granteeWhere),
+	).Build()
+}
+
+// scanGranteeGrant adds the privilege or role of a row returned by granteeGrantsQuery to grants.
+func scanGranteeGrant(data clickhouseclient.Row, grants *GranteeGrants) error {
+	accessType, err := data.GetString("access_type")
+	if err != nil {
+		return errors.WithMessage(err, "error scanning query result, missing 'access_type' field")
+	}
+	database, err := data.GetNullableString("database")
+	if err != nil {
+		return errors.WithMessage(err, "error scanning query result, missing 'database' field")
+	}
+	table, err := data.GetNullableString("table")
+	if err != nil {
+		return errors.WithMessage(err, "error scanning query result, missing 'table' field")
+	}
+	column, err := data.GetNullableString("column")
+	if err != nil {
+		return errors.WithMessage(err, "error scanning query result, missing 'column' field")
+	}
+	granteeUserName, err := data.GetNullableString("user_name")
+	if err != nil {
+		return errors.WithMessage(err, "error scanning query result, missing 'user_name' field")
+	}
+	granteeRoleName, err := data.GetNullableString("role_name")
+	if err != nil {
+		return errors.WithMessage(err, "error scanning query result, missing 'role_name' field")
+	}
+	grantOption, err := data.GetBool("grant_option")
+	if err != nil {
+		return errors.WithMessage(err, "error scanning query result, missing 'grant_option' field")
+	}
+	grantedRoleName, err := data.GetNullableString("granted_role_name")
+	if err != nil {
+		return errors.WithMessage(err, "error scanning query result, missing 'granted_role_name' field")
+	}
+
+	if grantedRoleName != nil {
+		grants.Roles = append(grants.Roles, GrantRole{
+			RoleName:        *grantedRoleName,
+			GranteeUserName: granteeUserName,
+			GranteeRoleName: granteeRoleName,
+			AdminOption:     grantOption,
+		})
+		return nil
+	}
+
+	grants.Privileges = append(grants.Privileges, GrantPrivilege{
+		AccessType:      accessType,
+		DatabaseName:    database,
+		TableName:       table,
+		ColumnName:      column,
+		GranteeUserName: granteeUserName,
+		GranteeRoleName: granteeRoleName,
+		GrantOption:     grantOption,
+	})
+
+	return nil
+}
+
+// granteeGrantsCacheTTL is how long the grants of a grantee are kept once read. It is meant to cover the reads of a
+// single plan or apply, grants changed from outside the provider are seen again once it has passed.
+var granteeGrantsCacheTTL = 30 * time.Second
+
+// granteeGrantsCache coalesces concurrent reads of the same grantee's grants and keeps their results for
+// granteeGrantsCacheTTL, or until invalidated.
+type granteeGrantsCache struct {
+	mu      sync.Mutex
+	entries map[string]*granteeGrantsEntry
+}
+
+type granteeGrantsEntry struct {
+	done   chan struct{}
+	grants *GranteeGrants
+	err    error
+	// expires is when the result stops being served, set once the load returned.
+	expires time.Time
+}
+
+func newGranteeGrantsCache() *granteeGrantsCache {
+	return &granteeGrantsCache{
+		entries: make(map[string]*granteeGrantsEntry),
+	}
+}
+
+func (c *granteeGrantsCache) get(ctx context.Context, key string, load func(ctx context.Context) (*GranteeGrants, error)) (*GranteeGrants, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok && !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		ok = false
+	}
+	if !ok {
+		entry = &granteeGrantsEntry{
+			done: make(chan struct{}),
+		}
+		c.entries[key] = entry
+
+		// Other callers may be waiting on this load, it must not be cancelled by this caller's context alone.
+		go func() {
+			grants, err := load(context.WithoutCancel(ctx))
+
+			c.mu.Lock()
+			entry.grants, entry.err = grants, err
+			entry.expires = time.Now().Add(granteeGrantsCacheTTL)
+			if err != nil && c.entries[key] == entry {
+				// Do not keep errors around, next call will try again.
+				delete(c.entries, key)
+			}
+			c.mu.Unlock()
+			close(entry.done)
+		}()
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-entry.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	return entry.grants, entry.err
+}
+
+// invalidate drops all cached results. Loads in flight still return to their callers but are not kept.
+func (c *granteeGrantsCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*granteeGrantsEntry)
+}
@@ -0,0 +1,40 @@
+package dbops
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGranteeGrantsCache_get(t *testing.T) {
+	defer func(ttl time.Duration) { granteeGrantsCacheTTL = ttl }(granteeGrantsCacheTTL)
+	granteeGrantsCacheTTL = time.Hour
+
+	loads := 0
+	load := func(context.Context) (*GranteeGrants, error) {
+		loads++
+		return &GranteeGrants{}, nil
+	}
+
+	c := newGranteeGrantsCache()
+	for range 2 {
+		if _, err := c.get(context.Background(), "user:alice", load); err != nil {
+			t.Fatalf("get() error = %v", err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("load called %d times within the TTL, want 1", loads)
+	}
+
+	granteeGrantsCacheTTL = 0
+	c.invalidate()
+	for range 2 {
+		if _, err := c.get(context.Background(), "user:alice", load); err != nil {
+			t.Fatalf("get() error = %v", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if loads != 3 {
+		t.Errorf("load called %d times, want 3 once the results expired", loads)
+	}
+}
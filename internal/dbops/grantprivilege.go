@@ -5,7 +5,6 @@ import (
 
 	"github.com/pingcap/errors"
 
-	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
 	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
 )
 
@@ -31,6 +30,10 @@ func (i *impl) GrantPrivilege(ctx context.Context, grantPrivilege GrantPrivilege
 		}
 	}
 
+	if err := i.checkWritableGrantee(ctx, grantPrivilege.GranteeUserName, grantPrivilege.GranteeRoleName, clusterName); err != nil {
+		return nil, err
+	}
+
 	sql, err := querybuilder.GrantPrivilege(grantPrivilege.AccessType, to).
 		WithDatabase(grantPrivilege.DatabaseName).
 		WithTable(grantPrivilege.TableName).
@@ -47,110 +50,53 @@ func (i *impl) GrantPrivilege(ctx context.Context, grantPrivilege GrantPrivilege
 		return nil, errors.WithMessage(err, "error running query")
 	}
 
-	return i.GetGrantPrivilege(ctx, grantPrivilege.AccessType, grantPrivilege.DatabaseName, grantPrivilege.TableName, grantPrivilege.ColumnName, grantPrivilege.GranteeUserName, grantPrivilege.GranteeRoleName, clusterName)
+	return readAfterCreate(ctx, i, clusterName, func(ctx context.Context) (*GrantPrivilege, error) {
+		// The grants of the grantee cached before the statement, or by the previous attempt, are outdated.
+		i.granteeGrants.invalidate()
+		return i.GetGrantPrivilege(ctx, grantPrivilege.AccessType, grantPrivilege.DatabaseName, grantPrivilege.TableName, grantPrivilege.ColumnName, grantPrivilege.GranteeUserName, grantPrivilege.GranteeRoleName, clusterName)
+	})
 }
 
-func (i *impl) GetGrantPrivilege(ctx context.Context, accessType string, database *string, table *string, column *string, granteeUserName *string, granteeRoleName *string, clusterName *string) (*GrantPrivilege, error) {
-	where := make([]querybuilder.Where, 0)
-
-	{
-		where = append(where, querybuilder.WhereEquals("access_type", accessType))
-		if database != nil {
-			where = append(where, querybuilder.WhereEquals("database", *database))
-		} else {
-			where = append(where, querybuilder.IsNull("database"))
-		}
-
-		if table != nil {
-			where = append(where, querybuilder.WhereEquals("table", *table))
-		} else {
-			where = append(where, querybuilder.IsNull("table"))
-		}
-
-		if column != nil {
-			where = append(where, querybuilder.WhereEquals("column", *column))
-		} else {
-			where = append(where, querybuilder.IsNull("column"))
-		}
+// GrantCurrentGrants grants every privilege of the user running the statement to the given user, with the grant
+// option, e.g. to give a temporary user the same rights as the provider.
+func (i *impl) GrantCurrentGrants(ctx context.Context, userName string, clusterName *string) error {
+	if err := i.requires(ctx, featureUserValidUntil); err != nil {
+		return err
+	}
 
-		if granteeUserName != nil {
-			where = append(where, querybuilder.WhereEquals("user_name", *granteeUserName))
-		} else if granteeRoleName != nil {
-			where = append(where, querybuilder.WhereEquals("role_name", *granteeRoleName))
-		} else {
-			return nil, errors.New("either GranteeUserName or GranteeRoleName must be set")
-		}
+	sql, err := querybuilder.GrantPrivilege("CURRENT GRANTS", userName).WithGrantOption(true).WithCluster(clusterName).Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
 	}
 
-	sql, err := querybuilder.NewSelect(
-		[]querybuilder.Field{
-			querybuilder.NewField("access_type"),
-			querybuilder.NewField("database"),
-			querybuilder.NewField("table"),
-			querybuilder.NewField("column"),
-			querybuilder.NewField("user_name"),
-			querybuilder.NewField("role_name"),
-			querybuilder.NewField("grant_option"),
-		},
-		"system.grants",
-	).WithCluster(clusterName).Where(where...).Build()
+	err = i.clickhouseClient.Exec(ctx, sql)
 	if err != nil {
-		return nil, errors.WithMessage(err, "error building query")
+		return errors.WithMessage(err, "error running query")
 	}
 
-	var grantPrivilege *GrantPrivilege
+	i.granteeGrants.invalidate()
 
-	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
-		accessType, err := data.GetString("access_type")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'access_type' field")
-		}
-		database, err := data.GetNullableString("database")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'database' field")
-		}
-		table, err := data.GetNullableString("table")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'table' field")
-		}
-		column, err := data.GetNullableString("column")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'column' field")
-		}
-		granteeUserName, err := data.GetNullableString("user_name")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'user_name' field")
-		}
-		granteeRoleName, err := data.GetNullableString("role_name")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'role_name' field")
-		}
-		grantOption, err := data.GetBool("grant_option")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'grant_option' field")
-		}
-		grantPrivilege = &GrantPrivilege{
-			AccessType:      accessType,
-			DatabaseName:    database,
-			TableName:       table,
-			ColumnName:      column,
-			GranteeUserName: granteeUserName,
-			GranteeRoleName: granteeRoleName,
-			GrantOption:     grantOption,
-		}
+	return nil
+}
 
-		return nil
-	})
+func (i *impl) GetGrantPrivilege(ctx context.Context, accessType string, database *string, table *string, column *string, granteeUserName *string, granteeRoleName *string, clusterName *string) (*GrantPrivilege, error) {
+	grants, err := i.GetGranteeGrants(ctx, granteeUserName, granteeRoleName, clusterName)
 	if err != nil {
-		return nil, errors.WithMessage(err, "error running query")
+		return nil, err
 	}
 
-	if grantPrivilege == nil {
-		// Grant not found
-		return nil, nil
+	return findGrantPrivilege(grants, accessType, database, table, column), nil
+}
+
+// findGrantPrivilege returns the privilege of grants matching the given access type and scope, nil if there is none.
+func findGrantPrivilege(grants *GranteeGrants, accessType string, database *string, table *string, column *string) *GrantPrivilege {
+	for _, g := range grants.Privileges {
+		if g.AccessType == accessType && equalPtr(g.DatabaseName, database) && equalPtr(g.TableName, table) && equalPtr(g.ColumnName, column) {
+			return &g
+		}
 	}
 
-	return grantPrivilege, nil
+	return nil
 }
 
 func (i *impl) RevokeGrantPrivilege(ctx context.Context, accessType string, database *string, table *string, column *string, granteeUserName *string, granteeRoleName *string, clusterName *string) error {
@@ -180,82 +126,63 @@ func (i *impl) RevokeGrantPrivilege(ctx context.Context, accessType string, data
 		return errors.WithMessage(err, "error running query")
 	}
 
+	i.granteeGrants.invalidate()
+
 	return nil
 }
 
-func (i *impl) GetAllGrantsForGrantee(ctx context.Context, granteeUsername *string, granteeRoleName *string, clusterName *string) ([]GrantPrivilege, error) {
-	// Get all grants for the same grantee.
-	var to querybuilder.Where
+// RevokeGrantOption takes the grant option of the privilege back from the grantee, who keeps the privilege itself.
+func (i *impl) RevokeGrantOption(ctx context.Context, accessType string, database *string, table *string, column *string, granteeUserName *string, granteeRoleName *string, clusterName *string) error {
+	var from string
 	{
-		if granteeUsername != nil {
-			to = querybuilder.WhereEquals("user_name", *granteeUsername)
+		if granteeUserName != nil {
+			from = *granteeUserName
 		} else if granteeRoleName != nil {
-			to = querybuilder.WhereEquals("role_name", *granteeRoleName)
+			from = *granteeRoleName
 		} else {
-			return nil, errors.New("either granteeUsername or GranteeRoleName must be set")
+			return errors.New("either GranteeUserName or GranteeRoleName must be set")
 		}
 	}
 
-	sql, err := querybuilder.NewSelect([]querybuilder.Field{
-		querybuilder.NewField("access_type"),
-		querybuilder.NewField("database"),
-		querybuilder.NewField("table"),
-		querybuilder.NewField("column"),
-		querybuilder.NewField("user_name"),
-		querybuilder.NewField("role_name"),
-		querybuilder.NewField("grant_option"),
-	}, "system.grants").WithCluster(clusterName).Where(to).Build()
+	if err := i.checkWritableGrantee(ctx, granteeUserName, granteeRoleName, clusterName); err != nil {
+		return err
+	}
+
+	sql, err := querybuilder.RevokePrivilege(accessType, from).
+		WithDatabase(database).
+		WithTable(table).
+		WithColumn(column).
+		WithCluster(clusterName).
+		GrantOptionOnly(true).
+		Build()
 	if err != nil {
-		return nil, errors.WithMessage(err, "error building query")
+		return errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
 	}
 
-	ret := make([]GrantPrivilege, 0)
+	i.granteeGrants.invalidate()
 
-	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
-		accessType, err := data.GetString("access_type")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'access_type' field")
-		}
-		database, err := data.GetNullableString("database")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'database' field")
-		}
-		table, err := data.GetNullableString("table")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'table' field")
-		}
-		column, err := data.GetNullableString("column")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'column' field")
-		}
-		granteeUserName, err := data.GetNullableString("user_name")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'user_name' field")
-		}
-		granteeRoleName, err := data.GetNullableString("role_name")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'role_name' field")
-		}
-		grantOption, err := data.GetBool("grant_option")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'grant_option' field")
-		}
+	return nil
+}
 
-		ret = append(ret, GrantPrivilege{
-			AccessType:      accessType,
-			DatabaseName:    database,
-			TableName:       table,
-			ColumnName:      column,
-			GranteeUserName: granteeUserName,
-			GranteeRoleName: granteeRoleName,
-			GrantOption:     grantOption,
-		})
-
-		return nil
-	})
+func (i *impl) GetAllGrantsForGrantee(ctx context.Context, granteeUsername *string, granteeRoleName *string, clusterName *string) ([]GrantPrivilege, error) {
+	grants, err := i.GetGranteeGrants(ctx, granteeUsername, granteeRoleName, clusterName)
 	if err != nil {
-		return nil, errors.WithMessage(err, "error running query")
+		return nil, err
+	}
+
+	return grants.Privileges, nil
+}
+
+// equalPtr reports whether a and b are both nil or point to equal values.
+func equalPtr(a *string, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
 	}
 
-	return ret, nil
+	return *a == *b
 }
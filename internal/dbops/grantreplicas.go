@@ -0,0 +1,113 @@
+package dbops
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// GetGrantPrivilegeMissingReplicas returns the host names of the replicas of the cluster where the privilege is not
+// granted, sorted. With local access storage, an ON CLUSTER statement failing on some replicas leaves them diverging.
+func (i *impl) GetGrantPrivilegeMissingReplicas(ctx context.Context, accessType string, database *string, table *string, column *string, granteeUserName *string, granteeRoleName *string, clusterName string) ([]string, error) {
+	grants, err := i.getGranteeGrantsByReplica(ctx, granteeUserName, granteeRoleName, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := make([]string, 0)
+	for replica, g := range grants {
+		if findGrantPrivilege(g, accessType, database, table, column) == nil {
+			missing = append(missing, replica)
+		}
+	}
+	sort.Strings(missing)
+
+	return missing, nil
+}
+
+// GetGrantRoleMissingReplicas returns the host names of the replicas of the cluster where the role is not granted,
+// sorted.
+func (i *impl) GetGrantRoleMissingReplicas(ctx context.Context, grantedRoleName string, granteeUserName *string, granteeRoleName *string, clusterName string) ([]string, error) {
+	grants, err := i.getGranteeGrantsByReplica(ctx, granteeUserName, granteeRoleName, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := make([]string, 0)
+	for replica, g := range grants {
+		if findGrantRole(g, grantedRoleName) == nil {
+			missing = append(missing, replica)
+		}
+	}
+	sort.Strings(missing)
+
+	return missing, nil
+}
+
+// getGranteeGrantsByReplica returns the grants of the given user or role on every replica of the cluster, keyed by
+// host name. Replicas where the grantee has no grant at all are included with no grants.
+// Results are not cached: they are used to find what the cached, one replica per shard reads can't see.
+func (i *impl) getGranteeGrantsByReplica(ctx context.Context, granteeUserName *string, granteeRoleName *string, clusterName string) (map[string]*GranteeGrants, error) {
+	if granteeUserName == nil && granteeRoleName == nil {
+		return nil, errors.New("either GranteeUserName or GranteeRoleName must be set")
+	}
+
+	ret := make(map[string]*GranteeGrants)
+
+	sql, err := querybuilder.
+		NewSelect([]querybuilder.Field{querybuilder.NewExpressionField("hostName()", "replica")}, "system.one").
+		WithClusterAllReplicas(&clusterName).
+		Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		replica, err := data.GetString("replica")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'replica' field")
+		}
+
+		ret[replica] = &GranteeGrants{
+			Privileges: make([]GrantPrivilege, 0),
+			Roles:      make([]GrantRole, 0),
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	sql, err = granteeGrantsQuery(granteeUserName, granteeRoleName, func(q querybuilder.SelectQueryBuilder) querybuilder.SelectQueryBuilder {
+		return q.WithClusterAllReplicas(&clusterName)
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		replica, err := data.GetString("replica")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'replica' field")
+		}
+
+		grants, ok := ret[replica]
+		if !ok {
+			// The replica was added between both queries.
+			grants = &GranteeGrants{}
+			ret[replica] = grants
+		}
+
+		return scanGranteeGrant(data, grants)
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return ret, nil
+}
@@ -5,7 +5,6 @@ import (
 
 	"github.com/pingcap/errors"
 
-	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
 	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
 )
 
@@ -28,6 +27,10 @@ func (i *impl) GrantRole(ctx context.Context, grantRole GrantRole, clusterName *
 		}
 	}
 
+	if err := i.checkWritableGrantee(ctx, grantRole.GranteeUserName, grantRole.GranteeRoleName, clusterName); err != nil {
+		return nil, err
+	}
+
 	sql, err := querybuilder.GrantRole(grantRole.RoleName, to).WithCluster(clusterName).WithAdminOption(grantRole.AdminOption).Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
@@ -38,73 +41,31 @@ func (i *impl) GrantRole(ctx context.Context, grantRole GrantRole, clusterName *
 		return nil, errors.WithMessage(err, "error running query")
 	}
 
-	return i.GetGrantRole(ctx, grantRole.RoleName, grantRole.GranteeUserName, grantRole.GranteeRoleName, clusterName)
+	return readAfterCreate(ctx, i, clusterName, func(ctx context.Context) (*GrantRole, error) {
+		// The grants of the grantee cached before the statement, or by the previous attempt, are outdated.
+		i.granteeGrants.invalidate()
+		return i.GetGrantRole(ctx, grantRole.RoleName, grantRole.GranteeUserName, grantRole.GranteeRoleName, clusterName)
+	})
 }
 
 func (i *impl) GetGrantRole(ctx context.Context, grantedRoleName string, granteeUserName *string, granteeRoleName *string, clusterName *string) (*GrantRole, error) {
-	var granteeWhere querybuilder.Where
-	{
-		if granteeUserName != nil {
-			granteeWhere = querybuilder.WhereEquals("user_name", *granteeUserName)
-		} else if granteeRoleName != nil {
-			granteeWhere = querybuilder.WhereEquals("role_name", *granteeRoleName)
-		} else {
-			return nil, errors.New("either GranteeUserName or GranteeRoleName must be set")
-		}
-	}
-
-	sql, err := querybuilder.NewSelect(
-		[]querybuilder.Field{
-			querybuilder.NewField("granted_role_name"),
-			querybuilder.NewField("user_name"),
-			querybuilder.NewField("role_name"),
-			querybuilder.NewField("with_admin_option"),
-		},
-		"system.role_grants").
-		WithCluster(clusterName).
-		Where(querybuilder.WhereEquals("granted_role_name", grantedRoleName), granteeWhere).
-		Build()
+	grants, err := i.GetGranteeGrants(ctx, granteeUserName, granteeRoleName, clusterName)
 	if err != nil {
-		return nil, errors.WithMessage(err, "error building query")
+		return nil, err
 	}
 
-	var grantRole *GrantRole
+	return findGrantRole(grants, grantedRoleName), nil
+}
 
-	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
-		roleName, err := data.GetString("granted_role_name")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'name' field")
-		}
-		granteeUserName, err := data.GetNullableString("user_name")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'user_name' field")
-		}
-		granteeRoleName, err := data.GetNullableString("role_name")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'role_name' field")
+// findGrantRole returns the grant of grantedRoleName among grants, nil if there is none.
+func findGrantRole(grants *GranteeGrants, grantedRoleName string) *GrantRole {
+	for _, g := range grants.Roles {
+		if g.RoleName == grantedRoleName {
+			return &g
 		}
-		adminOption, err := data.GetBool("with_admin_option")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'with_admin_option' field")
-		}
-		grantRole = &GrantRole{
-			RoleName:        roleName,
-			GranteeUserName: granteeUserName,
-			GranteeRoleName: granteeRoleName,
-			AdminOption:     adminOption,
-		}
-		return nil
-	})
-	if err != nil {
-		return nil, errors.WithMessage(err, "error running query")
-	}
-
-	if grantRole == nil {
-		// Grant not found
-		return nil, nil
 	}
 
-	return grantRole, nil
+	return nil
 }
 
 func (i *impl) RevokeGrantRole(ctx context.Context, grantedRoleName string, granteeUserName *string, granteeRoleName *string, clusterName *string) error {
@@ -128,5 +89,7 @@ func (i *impl) RevokeGrantRole(ctx context.Context, grantedRoleName string, gran
 		return errors.WithMessage(err, "error running query")
 	}
 
+	i.granteeGrants.invalidate()
+
 	return nil
 }
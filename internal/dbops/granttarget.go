@@ -0,0 +1,85 @@
+package dbops
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// grantTargetInterval is the delay between two lookups of the database or table a privilege is about to be granted
+// on, while it doesn't exist.
+var grantTargetInterval = 2 * time.Second
+
+// WaitForGrantTarget waits up to timeout for the database, or the table of the database when tableName is set, to
+// exist, e.g. when it is created by another workspace. ClickHouse grants privileges on objects that don't exist yet:
+// waiting avoids granting them before the object they are meant for, e.g. on a misspelled name.
+func (i *impl) WaitForGrantTarget(ctx context.Context, databaseName string, tableName *string, clusterName *string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		exists, err := i.grantTargetExists(ctx, databaseName, tableName, clusterName)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return nil
+		}
+
+		if !time.Now().Before(deadline) {
+			target := fmt.Sprintf("database %q", databaseName)
+			if tableName != nil {
+				target = fmt.Sprintf("table %q of database %q", *tableName, databaseName)
+			}
+			return errors.New(fmt.Sprintf("%s does not exist after waiting %s", target, timeout))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(grantTargetInterval):
+		}
+	}
+}
+
+// grantTargetExists tells if the database, or the table of the database when tableName is set, exists. With a
+// cluster, it is enough for the object to exist on one replica.
+func (i *impl) grantTargetExists(ctx context.Context, databaseName string, tableName *string, clusterName *string) (bool, error) {
+	var query querybuilder.SelectQueryBuilder
+	if tableName != nil {
+		query = querybuilder.NewSelect(
+			[]querybuilder.Field{querybuilder.NewField("name")},
+			"system.tables",
+		).Where(
+			querybuilder.WhereEquals("database", querybuilder.NewParameter("database", "String", databaseName)),
+			querybuilder.WhereEquals("name", querybuilder.NewParameter("name", "String", *tableName)),
+		)
+	} else {
+		query = querybuilder.NewSelect(
+			[]querybuilder.Field{querybuilder.NewField("name")},
+			"system.databases",
+		).Where(querybuilder.WhereEquals("name", querybuilder.NewParameter("name", "String", databaseName)))
+	}
+	query = query.WithCluster(i.readCluster(clusterName)).Limit(1, 0)
+
+	sql, err := query.Build()
+	if err != nil {
+		return false, errors.WithMessage(err, "error building query")
+	}
+
+	exists := false
+
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
+		exists = true
+		return nil
+	})
+	if err != nil {
+		return false, errors.WithMessage(err, "error running query")
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,77 @@
+package dbops
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+)
+
+// existsAfterClient returns a row from the SELECT queries from the foundAfter-th one on.
+type existsAfterClient struct {
+	execClient
+	foundAfter int
+	selects    int
+}
+
+func (c *existsAfterClient) Select(_ context.Context, _ string, callback func(clickhouseclient.Row) error) error {
+	c.selects++
+	if c.foundAfter > 0 && c.selects >= c.foundAfter {
+		return callback(clickhouseclient.Row{})
+	}
+	return nil
+}
+
+func Test_impl_WaitForGrantTarget(t *testing.T) {
+	grantTargetInterval = 0
+
+	tableName := "events"
+
+	tests := []struct {
+		name        string
+		tableName   *string
+		timeout     time.Duration
+		foundAfter  int
+		wantErr     bool
+		wantSelects int
+	}{
+		{
+			name:        "Database exists",
+			timeout:     time.Minute,
+			foundAfter:  1,
+			wantErr:     false,
+			wantSelects: 1,
+		},
+		{
+			name:        "Table created while waiting",
+			tableName:   &tableName,
+			timeout:     time.Minute,
+			foundAfter:  3,
+			wantErr:     false,
+			wantSelects: 3,
+		},
+		{
+			name:        "Missing after the timeout",
+			tableName:   &tableName,
+			timeout:     0,
+			foundAfter:  0,
+			wantErr:     true,
+			wantSelects: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &existsAfterClient{foundAfter: tt.foundAfter}
+			i := &impl{clickhouseClient: client}
+
+			err := i.WaitForGrantTarget(context.Background(), "analytics", tt.tableName, nil, tt.timeout)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WaitForGrantTarget() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if client.selects != tt.wantSelects {
+				t.Errorf("WaitForGrantTarget() selects = %d, want %d", client.selects, tt.wantSelects)
+			}
+		})
+	}
+}
@@ -1,15 +1,109 @@
 package dbops
 
 import (
+	"sync"
+	"time"
+
 	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
 )
 
+// Config holds the options of the dbops client.
+type Config struct {
+	// LocalReplicaReads makes reads only query the replica the client is connected to instead of every replica of
+	// the cluster given to an operation. DDL statements still run ON CLUSTER.
+	LocalReplicaReads bool
+	// ReadOnly makes every statement changing the server fail with the statement in the error, reads still work.
+	ReadOnly bool
+	// AllowPartialReads makes reads failing for lack of grants return a RestrictedReadError, so that callers can
+	// keep what they knew before instead of failing.
+	AllowPartialReads bool
+	// ClusterHealthCheck, when set, makes ON CLUSTER DDL on databases and tables fail without running anything when
+	// the cluster looks unhealthy.
+	ClusterHealthCheck *ClusterHealthCheck
+	// CloudMaintenance, when set, makes queries rejected because the service is in a maintenance window fail with a
+	// MaintenanceError, or wait for the window to end.
+	CloudMaintenance *CloudMaintenance
+	// AccessStorageMode is the default AccessStorageMode* for users, roles and grants, AccessStorageModeAuto when
+	// empty.
+	AccessStorageMode string
+	// MaxCommentBytes is the maximum size of database, table and column comments, 0 for no limit.
+	MaxCommentBytes int
+	// ReadAfterCreateTimeout is how long objects created ON CLUSTER are looked for before concluding they don't
+	// exist, for reads hitting replicas that didn't apply the creation yet.
+	ReadAfterCreateTimeout time.Duration
+	// ProtectedDatabases are the databases that can't be created or dropped, and whose tables can't be, see
+	// DefaultProtectedDatabases.
+	ProtectedDatabases []string
+	// DefaultDatabase is the database of the tables and views whose configuration leaves database_name out, none
+	// when empty.
+	DefaultDatabase string
+	// Notification, when set, sends a notification after every DROP, TRUNCATE, DETACH, DELETE or ALTER TABLE
+	// dropping or deleting data that ran successfully.
+	Notification *Notification
+	// HostClient opens a connection to the given host with the provider's settings, used to run the queries of
+	// operations targeting a shard (see WithShardTarget). Shard targeting fails when nil.
+	HostClient func(host string) (clickhouseclient.ClickhouseClient, error)
+	// RunID identifies the terraform run in the tag of every query, on the provider's connection and on the ones
+	// opened with HostClient alike. Queries are not tagged with a run when empty.
+	RunID string
+}
+
+// impl is shared by all resources, which Terraform operates on in parallel: any state it holds must be safe for
+// concurrent use. Per-operation state belongs in the context (see WithTableCache).
 type impl struct {
 	clickhouseClient clickhouseclient.ClickhouseClient
+	config           Config
+	tableBatcher     *tableBatcher
+	granteeGrants    *granteeGrantsCache
+
+	replicatedStorageMu sync.Mutex
+	replicatedStorage   *bool
+
+	serverVersionMu sync.Mutex
+	serverVersion   *ServerVersion
+
+	// quotaAssignmentMu serializes the read-modify-write of quota grantees.
+	quotaAssignmentMu sync.Mutex
+
+	clustersMu sync.Mutex
+	clusters   map[string]bool
 }
 
-func NewClient(clickhouseClient clickhouseclient.ClickhouseClient) (Client, error) {
-	return &impl{
+func NewClient(clickhouseClient clickhouseclient.ClickhouseClient, config Config) (Client, error) {
+	clickhouseClient = newShardTargetClient(clickhouseClient, config.HostClient)
+	if config.RunID != "" {
+		// Tagging the context before shard targets are resolved tags the queries of every connection.
+		clickhouseClient = clickhouseclient.NewRunTaggingClient(clickhouseClient, config.RunID)
+	}
+	if config.CloudMaintenance != nil {
+		clickhouseClient = &maintenanceClient{ClickhouseClient: clickhouseClient, config: *config.CloudMaintenance}
+	}
+	if config.ReadOnly {
+		clickhouseClient = &readOnlyClient{ClickhouseClient: clickhouseClient}
+	}
+	if config.AllowPartialReads {
+		clickhouseClient = &partialReadsClient{ClickhouseClient: clickhouseClient}
+	}
+	if config.Notification != nil {
+		clickhouseClient = &notifyingClient{ClickhouseClient: clickhouseClient, notification: *config.Notification}
+	}
+
+	i := &impl{
 		clickhouseClient: clickhouseClient,
-	}, nil
+		config:           config,
+		granteeGrants:    newGranteeGrantsCache(),
+		clusters:         make(map[string]bool),
+	}
+	i.tableBatcher = newTableBatcher(i.GetTables)
+
+	return i, nil
+}
+
+// readCluster returns the cluster name to use for SELECT queries, if any.
+func (i *impl) readCluster(clusterName *string) *string {
+	if i.config.LocalReplicaReads {
+		return nil
+	}
+
+	return clusterName
 }
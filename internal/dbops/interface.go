@@ -2,6 +2,7 @@ package dbops
 
 import (
 	"context"
+	"time"
 
 	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
 )
@@ -11,32 +12,134 @@ type Client interface {
 	GetDatabase(ctx context.Context, uuid string, clusterName *string) (*Database, error)
 	DeleteDatabase(ctx context.Context, uuid string, clusterName *string) error
 	FindDatabaseByName(ctx context.Context, name string, clusterName *string) (*Database, error)
+	ListDatabases(ctx context.Context, clusterName *string) ([]*Database, error)
+	UpdateDatabaseSettings(ctx context.Context, uuid string, settings map[string]string, clusterName *string) error
+	AttachDatabaseTables(ctx context.Context, uuid string, tableNames []string, clusterName *string) error
+	DetachDatabaseTables(ctx context.Context, uuid string, tableNames []string, clusterName *string) error
 
 	CreateRole(ctx context.Context, role Role, clusterName *string) (*Role, error)
 	GetRole(ctx context.Context, id string, clusterName *string) (*Role, error)
 	DeleteRole(ctx context.Context, id string, clusterName *string) error
 	FindRoleByName(ctx context.Context, name string, clusterName *string) (*Role, error)
 
+	CreateRowPolicy(ctx context.Context, policy RowPolicy, clusterName *string) (*RowPolicy, error)
+	GetRowPolicy(ctx context.Context, id string, clusterName *string) (*RowPolicy, error)
+	UpdateRowPolicy(ctx context.Context, policy RowPolicy, clusterName *string) (*RowPolicy, error)
+	DeleteRowPolicy(ctx context.Context, id string, clusterName *string) error
+	FindRowPolicyByName(ctx context.Context, name, databaseName, tableName string, clusterName *string) (*RowPolicy, error)
+
+	CreateSettingsProfile(ctx context.Context, profile SettingsProfile, clusterName *string) (*SettingsProfile, error)
+	GetSettingsProfile(ctx context.Context, id string, clusterName *string) (*SettingsProfile, error)
+	UpdateSettingsProfile(ctx context.Context, profile SettingsProfile, clusterName *string) (*SettingsProfile, error)
+	DeleteSettingsProfile(ctx context.Context, id string, clusterName *string) error
+	FindSettingsProfileByName(ctx context.Context, name string, clusterName *string) (*SettingsProfile, error)
+
+	CreateQuota(ctx context.Context, quota Quota, clusterName *string) (*Quota, error)
+	GetQuota(ctx context.Context, id string, clusterName *string) (*Quota, error)
+	UpdateQuota(ctx context.Context, quota Quota, clusterName *string) (*Quota, error)
+	DeleteQuota(ctx context.Context, id string, clusterName *string) error
+	FindQuotaByName(ctx context.Context, name string, clusterName *string) (*Quota, error)
+
 	CreateUser(ctx context.Context, user User, clusterName *string) (*User, error)
 	GetUser(ctx context.Context, id string, clusterName *string) (*User, error)
 	DeleteUser(ctx context.Context, id string, clusterName *string) error
 	FindUserByName(ctx context.Context, name string, clusterName *string) (*User, error)
+	GetCurrentUser(ctx context.Context) (*CurrentUser, error)
 
 	GrantRole(ctx context.Context, grantRole GrantRole, clusterName *string) (*GrantRole, error)
 	GetGrantRole(ctx context.Context, grantedRoleName string, granteeUserName *string, granteeRoleName *string, clusterName *string) (*GrantRole, error)
 	RevokeGrantRole(ctx context.Context, grantedRoleName string, granteeUserName *string, granteeRoleName *string, clusterName *string) error
+	GetGrantRoleMissingReplicas(ctx context.Context, grantedRoleName string, granteeUserName *string, granteeRoleName *string, clusterName string) ([]string, error)
 
 	GrantPrivilege(ctx context.Context, grantPrivilege GrantPrivilege, clusterName *string) (*GrantPrivilege, error)
+	GrantCurrentGrants(ctx context.Context, userName string, clusterName *string) error
 	GetGrantPrivilege(ctx context.Context, accessType string, database *string, table *string, column *string, granteeUserName *string, granteeRoleName *string, clusterName *string) (*GrantPrivilege, error)
 	RevokeGrantPrivilege(ctx context.Context, accessType string, database *string, table *string, column *string, granteeUserName *string, granteeRoleName *string, clusterName *string) error
+	RevokeGrantOption(ctx context.Context, accessType string, database *string, table *string, column *string, granteeUserName *string, granteeRoleName *string, clusterName *string) error
 	GetAllGrantsForGrantee(ctx context.Context, granteeUsername *string, granteeRoleName *string, clusterName *string) ([]GrantPrivilege, error)
+	GetGranteeGrants(ctx context.Context, granteeUserName *string, granteeRoleName *string, clusterName *string) (*GranteeGrants, error)
+	GetGrantPrivilegeMissingReplicas(ctx context.Context, accessType string, database *string, table *string, column *string, granteeUserName *string, granteeRoleName *string, clusterName string) ([]string, error)
+	WaitForGrantTarget(ctx context.Context, databaseName string, tableName *string, clusterName *string, timeout time.Duration) error
+
+	AssignSettingsProfile(ctx context.Context, assignment SettingsProfileAssignment, clusterName *string) (*SettingsProfileAssignment, error)
+	GetSettingsProfileAssignment(ctx context.Context, profileName string, granteeUserName *string, granteeRoleName *string, clusterName *string) (*SettingsProfileAssignment, error)
+	UnassignSettingsProfile(ctx context.Context, profileName string, granteeUserName *string, granteeRoleName *string, clusterName *string) error
+	SetSettingsProfiles(ctx context.Context, profileNames []string, granteeUserName *string, granteeRoleName *string, clusterName *string) error
+	GetSettingsProfiles(ctx context.Context, granteeUserName *string, granteeRoleName *string, clusterName *string) ([]string, error)
+
+	AssignQuota(ctx context.Context, assignment QuotaAssignment, clusterName *string) (*QuotaAssignment, error)
+	GetQuotaAssignment(ctx context.Context, quotaName string, granteeUserName *string, granteeRoleName *string, clusterName *string) (*QuotaAssignment, error)
+	UnassignQuota(ctx context.Context, quotaName string, granteeUserName *string, granteeRoleName *string, clusterName *string) error
+
+	GetAccessEntities(ctx context.Context, clusterName *string) ([]AccessEntity, error)
 
 	IsReplicatedStorage(ctx context.Context) (bool, error)
+	AccessCluster(ctx context.Context, clusterName *string, mode string) (*string, error)
+	ClusterExists(ctx context.Context, clusterName string) (bool, error)
+	GetServerVersion(ctx context.Context) (*ServerVersion, error)
+	GetTableEngines(ctx context.Context) ([]TableEngine, error)
+	CheckComment(comment string) error
+	CheckDatabaseManageable(databaseName string) error
+	DefaultDatabase() string
 
 	CreateTable(ctx context.Context, table Table, clusterName *string) (*Table, error)
 	GetTable(ctx context.Context, uuid string, clusterName *string) (*Table, error)
+	GetTables(ctx context.Context, uuids []string, clusterName *string) (map[string]*Table, error)
 	DeleteTable(ctx context.Context, uuid string, clusterName *string) error
 	FindTableByName(ctx context.Context, databaseName, tableName string, clusterName *string) (*Table, error)
+	GetTableByName(ctx context.Context, databaseName, tableName string, clusterName *string) (*Table, error)
+	ListTables(ctx context.Context, databaseName string, clusterName *string) ([]*Table, error)
 	AddTableColumns(ctx context.Context, databaseName, tableName string, columns []querybuilder.TableColumn, clusterName *string) error
+	ModifyTableColumns(ctx context.Context, databaseName, tableName string, columns []querybuilder.TableColumn, clusterName *string) error
+	RemoveTableColumnCodecs(ctx context.Context, databaseName, tableName string, columnNames []string, clusterName *string) error
 	DropTableColumns(ctx context.Context, databaseName, tableName string, columnNames []string, clusterName *string) error
+	RenameTableColumns(ctx context.Context, databaseName, tableName string, renames map[string]string, clusterName *string) error
+	ModifyTableTTL(ctx context.Context, databaseName, tableName string, ttl *string, clusterName *string) error
+	ModifyTableSettings(ctx context.Context, databaseName, tableName string, settings map[string]string, reset []string, clusterName *string) error
+	RenameTable(ctx context.Context, databaseName string, tableName string, newName string, clusterName *string) error
+	ExchangeTables(ctx context.Context, databaseName string, tableName string, otherName string, clusterName *string) error
+	CopyTableData(ctx context.Context, databaseName string, sourceName string, tableName string, columns []string) error
+	AddTableIndex(ctx context.Context, databaseName, tableName string, index querybuilder.TableIndex, clusterName *string) error
+	GetTableIndex(ctx context.Context, databaseName, tableName, indexName string, clusterName *string) (*TableIndex, error)
+	MaterializeTableIndex(ctx context.Context, databaseName, tableName, indexName string, clusterName *string) error
+	DropTableIndex(ctx context.Context, databaseName, tableName, indexName string, clusterName *string) error
+	AddTableProjection(ctx context.Context, databaseName, tableName string, projection querybuilder.TableProjection, clusterName *string) error
+	MaterializeTableProjection(ctx context.Context, databaseName, tableName, projectionName string, clusterName *string) error
+	DropTableProjection(ctx context.Context, databaseName, tableName, projectionName string, clusterName *string) error
+
+	CreateView(ctx context.Context, view View, clusterName *string) (*View, error)
+	GetView(ctx context.Context, uuid string, clusterName *string) (*View, error)
+	FindViewByName(ctx context.Context, databaseName, viewName string, clusterName *string) (*View, error)
+	DeleteView(ctx context.Context, uuid string, clusterName *string) error
+
+	CreateMaterializedView(ctx context.Context, view MaterializedView, clusterName *string) (*MaterializedView, error)
+	GetMaterializedView(ctx context.Context, uuid string, clusterName *string) (*MaterializedView, error)
+	FindMaterializedViewByName(ctx context.Context, databaseName, viewName string, clusterName *string) (*MaterializedView, error)
+	DeleteMaterializedView(ctx context.Context, uuid string, clusterName *string) error
+
+	CreateDictionary(ctx context.Context, dictionary Dictionary, clusterName *string) (*Dictionary, error)
+	GetDictionary(ctx context.Context, uuid string, clusterName *string) (*Dictionary, error)
+	FindDictionaryByName(ctx context.Context, databaseName, dictionaryName string, clusterName *string) (*Dictionary, error)
+	DeleteDictionary(ctx context.Context, uuid string, clusterName *string) error
+
+	CreateFunction(ctx context.Context, function Function, clusterName *string) (*Function, error)
+	ReplaceFunction(ctx context.Context, function Function, clusterName *string) (*Function, error)
+	GetFunction(ctx context.Context, name string, clusterName *string) (*Function, error)
+	DeleteFunction(ctx context.Context, name string, clusterName *string) error
+
+	OptimizeTable(ctx context.Context, databaseName string, tableName string, partition *string, final bool, deduplicate bool, waitForMerge bool, clusterName *string) error
+	SyncReplica(ctx context.Context, databaseName string, tableName string, clusterName *string) error
+	ReloadDictionary(ctx context.Context, databaseName string, dictionaryName *string, clusterName *string) error
+
+	GetExpiredPartitions(ctx context.Context, databaseName string, tableName string, olderThan string, clusterName *string) ([]string, error)
+	DropPartitions(ctx context.Context, databaseName string, tableName string, partitionIDs []string, clusterName *string) error
+	GetExpiredDetachedPartitions(ctx context.Context, databaseName string, tableName string, olderThan string, clusterName *string) ([]string, error)
+	DropDetachedPartitions(ctx context.Context, databaseName string, tableName string, partitionIDs []string, clusterName *string) error
+	FreezeTable(ctx context.Context, databaseName string, tableName string, partition *string, snapshotName string, clusterName *string) error
+	GetTableParts(ctx context.Context, databaseName string, tableName string, clusterName *string) ([]TablePartsSummary, error)
+	GetTableStats(ctx context.Context, databaseName string, tableName string, clusterName *string) (*TableStats, error)
+	GetDetachedParts(ctx context.Context, databaseName string, tableName *string, clusterName *string) ([]DetachedPart, error)
+
+	GetMutations(ctx context.Context, databaseName *string, tableName *string, onlyFailed bool, clusterName *string) ([]Mutation, error)
+	KillMutation(ctx context.Context, databaseName string, tableName string, mutationID string, clusterName *string) error
 }
@@ -0,0 +1,77 @@
+package dbops
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// OptimizeTable runs OPTIMIZE TABLE, optionally restricted to a single partition.
+// When waitForMerge is true the query only returns once the merge is done on every replica.
+func (i *impl) OptimizeTable(ctx context.Context, databaseName string, tableName string, partition *string, final bool, deduplicate bool, waitForMerge bool, clusterName *string) error {
+	var builder querybuilder.QueryBuilder = querybuilder.NewOptimizeTable(databaseName, tableName).
+		WithCluster(clusterName).
+		WithPartition(partition).
+		WithFinal(final).
+		WithDeduplicate(deduplicate)
+
+	if waitForMerge {
+		builder = querybuilder.WithStatementSettings(builder, map[string]string{"alter_sync": "2"})
+	}
+
+	sql, err := builder.Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
+	}
+
+	return nil
+}
+
+// SyncReplica runs SYSTEM SYNC REPLICA, waiting for the replicated table to process its replication queue.
+// The query can take long on busy replicas, callers should bound ctx with a deadline.
+func (i *impl) SyncReplica(ctx context.Context, databaseName string, tableName string, clusterName *string) error {
+	sql, err := querybuilder.NewSystemSyncReplica(databaseName, tableName).WithCluster(clusterName).Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return errors.New("timed out waiting for replicas to sync")
+		}
+		return errors.WithMessage(err, "error running query")
+	}
+
+	return nil
+}
+
+// ReloadDictionary runs SYSTEM RELOAD DICTIONARY for the given dictionary, or SYSTEM RELOAD DICTIONARIES when
+// dictionaryName is nil. databaseName can be empty for dictionaries defined in the server configuration.
+func (i *impl) ReloadDictionary(ctx context.Context, databaseName string, dictionaryName *string, clusterName *string) error {
+	var builder querybuilder.SystemQueryBuilder
+	if dictionaryName != nil {
+		builder = querybuilder.NewSystemReloadDictionary(databaseName, *dictionaryName)
+	} else {
+		builder = querybuilder.NewSystemReloadDictionaries()
+	}
+
+	sql, err := builder.WithCluster(clusterName).Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
+	}
+
+	return nil
+}
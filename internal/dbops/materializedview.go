@@ -0,0 +1,359 @@
+package dbops
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// MaterializedView is a materialized view, writing either into a table (ToTableName) or into an inner table created
+// with Engine.
+type MaterializedView struct {
+	UUID         string `json:"uuid"`
+	DatabaseName string `json:"database_name"`
+	Name         string `json:"name"`
+	// Query is the SELECT query of the view. When read back, it is the query as normalized by the server.
+	Query          string `json:"query"`
+	ToDatabaseName string `json:"to_database_name"`
+	ToTableName    string `json:"to_table_name"`
+	// Engine is the engine of the inner table with its clauses, e.g. `MergeTree ORDER BY id`.
+	Engine string `json:"engine"`
+	// Refresh is the clause following REFRESH for refreshable views, e.g. `EVERY 1 HOUR`.
+	Refresh string `json:"refresh"`
+	Comment string `json:"comment"`
+	// Populate is only used on creation, it is not read back.
+	Populate bool `json:"populate"`
+	// RefreshStatus is the status of refreshable views in system.view_refreshes, e.g. `Scheduled`. It is only read.
+	RefreshStatus string `json:"refresh_status"`
+}
+
+// Keywords that start a clause between the name of a materialized view and its SELECT query.
+var materializedViewClauseKeywords = []string{"REFRESH", "APPEND", "TO"}
+
+func (i *impl) CreateMaterializedView(ctx context.Context, view MaterializedView, clusterName *string) (*MaterializedView, error) {
+	if err := i.CheckDatabaseManageable(view.DatabaseName); err != nil {
+		return nil, err
+	}
+
+	if view.Comment != "" {
+		if err := i.requires(ctx, featureTableComment); err != nil {
+			return nil, err
+		}
+	}
+
+	sql, err := CreateMaterializedViewStatement(view, clusterName)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return nil, err
+	}
+
+	err = i.execWithRetry(ctx, sql, func(ctx context.Context) (bool, error) {
+		v, err := i.findMaterializedView(ctx, view.DatabaseName, view.Name, clusterName)
+		return v != nil, err
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	created, err := readAfterCreate(ctx, i, clusterName, func(ctx context.Context) (*MaterializedView, error) {
+		return i.findMaterializedView(ctx, view.DatabaseName, view.Name, clusterName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if created == nil {
+		return nil, errors.New("materialized view with such name not found")
+	}
+
+	return created, nil
+}
+
+// CreateMaterializedViewStatement returns the CREATE MATERIALIZED VIEW statement CreateMaterializedView runs for the
+// given view.
+func CreateMaterializedViewStatement(view MaterializedView, clusterName *string) (string, error) {
+	builder := querybuilder.NewCreateMaterializedView(view.DatabaseName, view.Name, view.Query).WithCluster(clusterName)
+	if view.ToTableName != "" {
+		builder.WithTo(view.ToDatabaseName, view.ToTableName)
+	}
+	if view.Engine != "" {
+		builder.WithEngine(view.Engine)
+	}
+	if view.Refresh != "" {
+		builder.WithRefresh(view.Refresh)
+	}
+	if view.Populate {
+		builder.WithPopulate()
+	}
+	if view.Comment != "" {
+		builder.WithComment(view.Comment)
+	}
+
+	return builder.Build()
+}
+
+// GetMaterializedView returns the materialized view with the given UUID, or nil if it does not exist.
+func (i *impl) GetMaterializedView(ctx context.Context, uuid string, clusterName *string) (*MaterializedView, error) {
+	return i.selectMaterializedView(ctx, clusterName, querybuilder.WhereEquals("uuid", querybuilder.NewParameter("uuid", "UUID", uuid)))
+}
+
+func (i *impl) FindMaterializedViewByName(ctx context.Context, databaseName, viewName string, clusterName *string) (*MaterializedView, error) {
+	view, err := i.findMaterializedView(ctx, databaseName, viewName, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	if view == nil {
+		return nil, errors.New("materialized view with such name not found")
+	}
+
+	return view, nil
+}
+
+// findMaterializedView returns the materialized view with the given name, or nil if it does not exist.
+func (i *impl) findMaterializedView(ctx context.Context, databaseName, viewName string, clusterName *string) (*MaterializedView, error) {
+	return i.selectMaterializedView(
+		ctx,
+		clusterName,
+		querybuilder.WhereEquals("database", querybuilder.NewParameter("database", "String", databaseName)),
+		querybuilder.WhereEquals("name", querybuilder.NewParameter("name", "String", viewName)),
+	)
+}
+
+func (i *impl) selectMaterializedView(ctx context.Context, clusterName *string, where ...querybuilder.Where) (*MaterializedView, error) {
+	commentField := querybuilder.NewField("comment")
+	if ok, err := i.supports(ctx, featureTableComment); err != nil {
+		return nil, err
+	} else if !ok {
+		commentField = querybuilder.NewExpressionField("''", "comment")
+	}
+
+	query := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewField("uuid"),
+			querybuilder.NewField("database"),
+			querybuilder.NewField("name"),
+			querybuilder.NewField("as_select"),
+			querybuilder.NewField("create_table_query"),
+			commentField,
+		},
+		"system.tables",
+	).WithCluster(i.readCluster(clusterName)).
+		Where(append(where, querybuilder.WhereEquals("engine", querybuilder.NewParameter("engine", "String", "MaterializedView")))...)
+	sql, err := query.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	var view *MaterializedView
+
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
+		if view != nil {
+			// With a cluster, every replica returns its own copy of the view.
+			return nil
+		}
+
+		u, err := data.GetString("uuid")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'uuid' field")
+		}
+		d, err := data.GetString("database")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'database' field")
+		}
+		n, err := data.GetString("name")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'name' field")
+		}
+		q, err := data.GetString("as_select")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'as_select' field")
+		}
+		createQuery, err := data.GetString("create_table_query")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'create_table_query' field")
+		}
+		c, err := data.GetString("comment")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'comment' field")
+		}
+
+		view, err = parseCreateMaterializedViewQuery(createQuery)
+		if err != nil {
+			return errors.WithMessage(err, "error parsing materialized view definition")
+		}
+		view.UUID = u
+		view.DatabaseName = d
+		view.Name = n
+		view.Query = q
+		view.Comment = c
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	if view != nil && view.Refresh != "" {
+		view.RefreshStatus, err = i.getViewRefreshStatus(ctx, view.DatabaseName, view.Name, clusterName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return view, nil
+}
+
+// getViewRefreshStatus returns the status of a refreshable materialized view in system.view_refreshes.
+func (i *impl) getViewRefreshStatus(ctx context.Context, databaseName, viewName string, clusterName *string) (string, error) {
+	query := querybuilder.NewSelect(
+		[]querybuilder.Field{querybuilder.NewField("status")},
+		"system.view_refreshes",
+	).WithCluster(i.readCluster(clusterName)).Where(
+		querybuilder.WhereEquals("database", querybuilder.NewParameter("database", "String", databaseName)),
+		querybuilder.WhereEquals("view", querybuilder.NewParameter("view", "String", viewName)),
+	)
+	sql, err := query.Build()
+	if err != nil {
+		return "", errors.WithMessage(err, "error building query")
+	}
+
+	var status string
+
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
+		status, err = data.GetString("status")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'status' field")
+		}
+		return nil
+	})
+	if err != nil {
+		return "", errors.WithMessage(err, "error running query")
+	}
+
+	return status, nil
+}
+
+func (i *impl) DeleteMaterializedView(ctx context.Context, uuid string, clusterName *string) error {
+	view, err := i.GetMaterializedView(ctx, uuid, clusterName)
+	if err != nil {
+		return errors.WithMessage(err, "error getting materialized view")
+	}
+
+	if view == nil {
+		// This is desired state.
+		return nil
+	}
+
+	if err := i.CheckDatabaseManageable(view.DatabaseName); err != nil {
+		return err
+	}
+
+	sql, err := querybuilder.NewDropTable(view.DatabaseName, view.Name).WithCluster(clusterName).Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
+	}
+
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return err
+	}
+
+	err = i.execWithRetry(ctx, sql, func(ctx context.Context) (bool, error) {
+		v, err := i.GetMaterializedView(ctx, uuid, clusterName)
+		return v == nil, err
+	})
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
+	}
+
+	invalidateTableCache(ctx)
+
+	return nil
+}
+
+// parseCreateMaterializedViewQuery parses the REFRESH, TO and ENGINE clauses of the statement in
+// system.tables.create_table_query of a materialized view. The query, name and comment are read from other columns
+// and left empty.
+func parseCreateMaterializedViewQuery(query string) (*MaterializedView, error) {
+	rest, ok := trimKeywords(strings.TrimSpace(query), "CREATE")
+	if !ok {
+		return nil, errors.New("statement does not start with CREATE")
+	}
+	if rest, ok = trimKeywords(rest, "MATERIALIZED VIEW"); !ok {
+		return nil, errors.New("statement is not a CREATE MATERIALIZED VIEW statement")
+	}
+	rest, _ = trimKeywords(rest, "IF NOT EXISTS")
+
+	name, rest := readIdentifier(rest)
+	if after, ok := strings.CutPrefix(rest, "."); ok {
+		name, rest = readIdentifier(after)
+	}
+	if name == "" {
+		return nil, errors.New("cannot find view name in statement")
+	}
+	if after, ok := trimKeywords(rest, "UUID"); ok {
+		_, rest = readIdentifier(after)
+	}
+	if after, ok := trimKeywords(rest, "ON CLUSTER"); ok {
+		_, rest = readIdentifier(after)
+	}
+
+	// Only keep the clauses before the SELECT query, and the definer clauses that come last.
+	if positions := findTopLevelKeywords(rest, []string{"AS"}); len(positions) > 0 {
+		rest = rest[:positions[0].start]
+	}
+	if positions := findTopLevelKeywords(rest, []string{"DEFINER", "SQL SECURITY"}); len(positions) > 0 {
+		rest = rest[:positions[0].start]
+	}
+
+	view := &MaterializedView{}
+
+	if positions := findTopLevelKeywords(rest, []string{"ENGINE"}); len(positions) > 0 {
+		engine := rest[positions[0].start+len("ENGINE"):]
+		view.Engine = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(engine), "="))
+		rest = rest[:positions[0].start]
+	}
+
+	clauses := splitClauses(rest, materializedViewClauseKeywords)
+	if to, ok := clauses["TO"]; ok {
+		target, after := readIdentifier(to)
+		if after, ok := strings.CutPrefix(after, "."); ok {
+			view.ToDatabaseName = target
+			target, _ = readIdentifier(after)
+		}
+		view.ToTableName = target
+	}
+	if refresh, ok := clauses["REFRESH"]; ok {
+		view.Refresh = trimColumnList(refresh)
+		if _, ok := clauses["APPEND"]; ok {
+			view.Refresh += " APPEND"
+		}
+	}
+
+	return view, nil
+}
+
+// trimColumnList removes the column list ending a clause, e.g. `EVERY 1 HOUR (id UInt64)`.
+func trimColumnList(clause string) string {
+	clause = strings.TrimSpace(clause)
+	if !strings.HasSuffix(clause, ")") {
+		return clause
+	}
+
+	for i := 0; i < len(clause); i++ {
+		if clause[i] == '(' && matchingParen(clause, i) == len(clause)-1 {
+			if i > 0 && isIdentifierChar(clause[i-1]) {
+				// A function call, e.g. the table of DEPENDS ON.
+				return clause
+			}
+			return strings.TrimSpace(clause[:i])
+		}
+	}
+
+	return clause
+}
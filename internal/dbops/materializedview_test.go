@@ -0,0 +1,53 @@
+package dbops
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCreateMaterializedViewQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    *MaterializedView
+		wantErr bool
+	}{
+		{
+			name:  "view writing into a table",
+			query: "CREATE MATERIALIZED VIEW mydb.mv TO mydb.target (`id` UInt64, `total` UInt64) AS SELECT id, sum(v) AS total FROM mydb.events GROUP BY id",
+			want:  &MaterializedView{ToDatabaseName: "mydb", ToTableName: "target"},
+		},
+		{
+			name:  "view with inner table",
+			query: "CREATE MATERIALIZED VIEW `my-db`.`mv` (`id` UInt64) ENGINE = MergeTree ORDER BY id SETTINGS index_granularity = 8192 AS SELECT id FROM `my-db`.events COMMENT 'counts'",
+			want:  &MaterializedView{Engine: "MergeTree ORDER BY id SETTINGS index_granularity = 8192"},
+		},
+		{
+			name:  "refreshable view",
+			query: "CREATE MATERIALIZED VIEW mydb.mv REFRESH EVERY 1 HOUR OFFSET 5 MINUTE DEPENDS ON mydb.other APPEND TO mydb.target (`c` UInt64) AS SELECT count() AS c FROM mydb.events",
+			want:  &MaterializedView{ToDatabaseName: "mydb", ToTableName: "target", Refresh: "EVERY 1 HOUR OFFSET 5 MINUTE DEPENDS ON mydb.other APPEND"},
+		},
+		{
+			name:  "refreshable view with inner table and definer",
+			query: "CREATE MATERIALIZED VIEW mydb.mv REFRESH AFTER 30 SECOND (`c` UInt64) ENGINE = Memory DEFINER = admin SQL SECURITY DEFINER AS SELECT count() AS c FROM mydb.events",
+			want:  &MaterializedView{Engine: "Memory", Refresh: "AFTER 30 SECOND"},
+		},
+		{
+			name:    "not a materialized view",
+			query:   "CREATE VIEW mydb.v AS SELECT 1",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCreateMaterializedViewQuery(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCreateMaterializedViewQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCreateMaterializedViewQuery() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package dbops
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+type Mutation struct {
+	DatabaseName     string `json:"database"`
+	TableName        string `json:"table"`
+	MutationID       string `json:"mutation_id"`
+	Command          string `json:"command"`
+	CreateTime       string `json:"create_time"`
+	PartsToDo        uint64 `json:"parts_to_do"`
+	IsDone           bool   `json:"is_done"`
+	LatestFailTime   string `json:"latest_fail_time"`
+	LatestFailReason string `json:"latest_fail_reason"`
+}
+
+// GetMutations returns the mutations of the given database and table, both optional. When onlyFailed is true only
+// mutations that are not done and failed at least once are returned. With a cluster, the state of a mutation is
+// merged across replicas: it is done only once every replica is done.
+func (i *impl) GetMutations(ctx context.Context, databaseName *string, tableName *string, onlyFailed bool, clusterName *string) ([]Mutation, error) {
+	where := make([]querybuilder.Where, 0)
+	if databaseName != nil {
+		where = append(where, querybuilder.WhereEquals("database", *databaseName))
+	}
+	if tableName != nil {
+		where = append(where, querybuilder.WhereEquals("table", *tableName))
+	}
+	if onlyFailed {
+		where = append(where, querybuilder.WhereEquals("is_done", 0), querybuilder.WhereDiffers("latest_fail_reason", ""))
+	}
+
+	builder := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewField("database"),
+			querybuilder.NewField("table"),
+			querybuilder.NewField("mutation_id"),
+			querybuilder.NewField("command"),
+			querybuilder.NewExpressionField("toString(create_time)", "created_at"),
+			querybuilder.NewExpressionField("toUInt64(greatest(parts_to_do, 0))", "parts_remaining"),
+			querybuilder.NewField("is_done"),
+			querybuilder.NewExpressionField("toString(latest_fail_time)", "latest_failed_at"),
+			querybuilder.NewField("latest_fail_reason"),
+		},
+		"system.mutations",
+	).WithCluster(i.readCluster(clusterName)).
+		OrderBy("database", "table", "created_at", "mutation_id")
+	if len(where) > 0 {
+		builder = builder.Where(where...)
+	}
+
+	sql, err := builder.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	ret := make([]Mutation, 0)
+	index := make(map[[3]string]int)
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		m, err := mutationFromRow(data)
+		if err != nil {
+			return err
+		}
+
+		key := [3]string{m.DatabaseName, m.TableName, m.MutationID}
+		pos, ok := index[key]
+		if !ok {
+			index[key] = len(ret)
+			ret = append(ret, *m)
+			return nil
+		}
+
+		// Same mutation seen on another replica.
+		existing := &ret[pos]
+		existing.IsDone = existing.IsDone && m.IsDone
+		existing.PartsToDo = max(existing.PartsToDo, m.PartsToDo)
+		if existing.LatestFailReason == "" {
+			existing.LatestFailReason = m.LatestFailReason
+			existing.LatestFailTime = m.LatestFailTime
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return ret, nil
+}
+
+// KillMutation cancels the given mutation, waiting for it to be stopped.
+func (i *impl) KillMutation(ctx context.Context, databaseName string, tableName string, mutationID string, clusterName *string) error {
+	sql, err := querybuilder.NewKillMutation(
+		querybuilder.WhereEquals("database", databaseName),
+		querybuilder.WhereEquals("table", tableName),
+		querybuilder.WhereEquals("mutation_id", mutationID),
+	).WithCluster(clusterName).
+		WithSync(true).
+		Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
+	}
+
+	return nil
+}
+
+func mutationFromRow(data clickhouseclient.Row) (*Mutation, error) {
+	databaseName, err := data.GetString("database")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'database' field")
+	}
+	tableName, err := data.GetString("table")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'table' field")
+	}
+	mutationID, err := data.GetString("mutation_id")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'mutation_id' field")
+	}
+	command, err := data.GetString("command")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'command' field")
+	}
+	createTime, err := data.GetString("created_at")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'created_at' field")
+	}
+	partsToDo, err := data.GetUInt64("parts_remaining")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'parts_remaining' field")
+	}
+	isDone, err := data.GetBool("is_done")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'is_done' field")
+	}
+	latestFailTime, err := data.GetString("latest_failed_at")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'latest_failed_at' field")
+	}
+	latestFailReason, err := data.GetString("latest_fail_reason")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'latest_fail_reason' field")
+	}
+
+	return &Mutation{
+		DatabaseName:     databaseName,
+		TableName:        tableName,
+		MutationID:       mutationID,
+		Command:          command,
+		CreateTime:       createTime,
+		PartsToDo:        partsToDo,
+		IsDone:           isDone,
+		LatestFailTime:   latestFailTime,
+		LatestFailReason: latestFailReason,
+	}, nil
+}
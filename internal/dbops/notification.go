@@ -0,0 +1,159 @@
+package dbops
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+)
+
+// notificationTimeout bounds how long a notification can delay the operation that triggered it.
+var notificationTimeout = 10 * time.Second
+
+// Notification configures who is told about the destructive statements run by the provider.
+type Notification struct {
+	// Command is run with the JSON payload on its standard input, as the program followed by its arguments.
+	Command []string
+	// WebhookURL receives the JSON payload in a POST request.
+	WebhookURL string
+	// RunID identifies the terraform run in the payload.
+	RunID string
+}
+
+// NotificationPayload describes a destructive statement that ran successfully.
+type NotificationPayload struct {
+	RunID     string    `json:"terraform_run_id,omitempty"`
+	Resource  string    `json:"resource,omitempty"`
+	Operation string    `json:"operation,omitempty"`
+	Kind      string    `json:"kind"`
+	Statement string    `json:"statement"`
+	Time      time.Time `json:"time"`
+}
+
+// destructiveAlterKeywords are the ALTER TABLE clauses that drop or delete data.
+var destructiveAlterKeywords = []string{
+	"DROP DETACHED PARTITION",
+	"DROP DETACHED PART",
+	"DROP PARTITION",
+	"DROP PART",
+	"DROP COLUMN",
+	"DROP INDEX",
+	"DROP PROJECTION",
+	"DROP CONSTRAINT",
+	"CLEAR COLUMN",
+	"CLEAR INDEX",
+	"CLEAR PROJECTION",
+	"DELETE WHERE",
+	"REMOVE TTL",
+}
+
+// destructiveStatementKind returns what makes the statement destructive, e.g. DROP or ALTER ... DROP COLUMN, or an
+// empty string when it isn't.
+func destructiveStatementKind(qry string) string {
+	qry = strings.TrimSpace(qry)
+
+	for _, keyword := range []string{"DROP", "TRUNCATE", "DETACH"} {
+		if _, ok := trimKeywords(qry, keyword); ok {
+			return keyword
+		}
+	}
+	if _, ok := trimKeywords(qry, "DELETE FROM"); ok {
+		return "DELETE"
+	}
+	if _, ok := trimKeywords(qry, "ALTER"); ok {
+		if positions := findTopLevelKeywords(qry, destructiveAlterKeywords); len(positions) > 0 {
+			return "ALTER " + positions[0].keyword
+		}
+	}
+
+	return ""
+}
+
+// notifyingClient sends a notification after every destructive statement that ran successfully.
+type notifyingClient struct {
+	clickhouseclient.ClickhouseClient
+	notification Notification
+}
+
+func (c *notifyingClient) Exec(ctx context.Context, qry string) error {
+	err := c.ClickhouseClient.Exec(ctx, qry)
+	if err != nil {
+		return err
+	}
+
+	kind := destructiveStatementKind(qry)
+	if kind == "" {
+		return nil
+	}
+
+	tag := clickhouseclient.QueryTagFromContext(ctx)
+	payload := NotificationPayload{
+		RunID:     c.notification.RunID,
+		Resource:  tag.Resource,
+		Operation: tag.Operation,
+		Kind:      kind,
+		Statement: clickhouseclient.RedactSecrets(qry),
+		Time:      time.Now().UTC(),
+	}
+
+	// The statement already ran, failing to notify must not fail the operation.
+	if err := c.notify(ctx, payload); err != nil {
+		tflog.Warn(ctx, "Failed to send the notification of a destructive statement", map[string]interface{}{
+			"statement": payload.Statement,
+			"error":     err.Error(),
+		})
+	}
+
+	return nil
+}
+
+func (c *notifyingClient) notify(ctx context.Context, payload NotificationPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.WithMessage(err, "error encoding notification")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notificationTimeout)
+	defer cancel()
+
+	var errs []string
+	if len(c.notification.Command) > 0 {
+		cmd := exec.CommandContext(ctx, c.notification.Command[0], c.notification.Command[1:]...) //nolint:gosec
+		cmd.Stdin = bytes.NewReader(body)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			errs = append(errs, "notify command failed: "+err.Error()+": "+strings.TrimSpace(string(output)))
+		}
+	}
+
+	if c.notification.WebhookURL != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.notification.WebhookURL, bytes.NewReader(body))
+		if err != nil {
+			return errors.WithMessage(err, "error building webhook request")
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			errs = append(errs, "webhook request failed: "+err.Error())
+		} else {
+			_ = resp.Body.Close()
+			if resp.StatusCode >= 300 {
+				errs = append(errs, "webhook responded with "+resp.Status)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package dbops
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+)
+
+func Test_destructiveStatementKind(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: "DROP TABLE `db`.`t` SYNC", want: "DROP"},
+		{query: "TRUNCATE TABLE `db`.`t`", want: "TRUNCATE"},
+		{query: "ALTER TABLE `db`.`t` ON CLUSTER 'c' DROP COLUMN `a`", want: "ALTER DROP COLUMN"},
+		{query: "ALTER TABLE `db`.`t` DROP PARTITION '2024-01'", want: "ALTER DROP PARTITION"},
+		{query: "ALTER TABLE `db`.`t` REMOVE TTL", want: "ALTER REMOVE TTL"},
+		{query: "ALTER TABLE `db`.`t` ADD COLUMN `a` String DEFAULT 'DROP COLUMN'", want: ""},
+		{query: "ALTER TABLE `db`.`t` ADD INDEX `i` a TYPE minmax", want: ""},
+		{query: "CREATE TABLE `db`.`dropped` (`id` UInt64) ENGINE = Memory", want: ""},
+		{query: "DROPPED", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := destructiveStatementKind(tt.query); got != tt.want {
+				t.Errorf("destructiveStatementKind() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotifyingClient_Exec(t *testing.T) {
+	var payloads []NotificationPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload NotificationPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("cannot decode payload: %v", err)
+		}
+		payloads = append(payloads, payload)
+	}))
+	defer server.Close()
+
+	client := &execClient{errs: []error{nil, nil, errors.New("Code: 60. DB::Exception: Table does not exist")}}
+	c := &notifyingClient{ClickhouseClient: client, notification: Notification{WebhookURL: server.URL, RunID: "run"}}
+	ctx := clickhouseclient.WithQueryTag(context.Background(), clickhouseclient.QueryTag{Resource: "clickhousedbops_table", Operation: "apply"})
+
+	if err := c.Exec(ctx, "CREATE DATABASE db"); err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+	if err := c.Exec(ctx, "DROP TABLE `db`.`t`"); err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+	if err := c.Exec(ctx, "DROP TABLE `db`.`missing`"); err == nil {
+		t.Fatalf("Exec() error = nil, want the error of the statement")
+	}
+
+	if len(payloads) != 1 {
+		t.Fatalf("got %d notifications, want 1", len(payloads))
+	}
+	got := payloads[0]
+	if got.Statement != "DROP TABLE `db`.`t`" || got.Kind != "DROP" || got.RunID != "run" || got.Resource != "clickhousedbops_table" || got.Operation != "apply" {
+		t.Errorf("notification = %+v", got)
+	}
+}
@@ -0,0 +1,47 @@
+package dbops
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+)
+
+// errorCodeAccessDenied is returned when the user lacks the grant to read a table, e.g. system.grants on ClickHouse
+// Cloud.
+const errorCodeAccessDenied = 497
+
+// RestrictedReadError is returned when a read failed because the user is not allowed to query some system table.
+type RestrictedReadError struct {
+	err error
+}
+
+func (e *RestrictedReadError) Error() string {
+	return e.err.Error()
+}
+
+// IsRestrictedRead tells if err comes from a read the user is not allowed to run. Such errors are only returned
+// when the client was created with AllowPartialReads, plain errors are returned otherwise.
+func IsRestrictedRead(err error) bool {
+	_, ok := errors.Cause(err).(*RestrictedReadError)
+	return ok
+}
+
+// partialReadsClient marks the SELECT queries failing for lack of grants with RestrictedReadError.
+type partialReadsClient struct {
+	clickhouseclient.ClickhouseClient
+}
+
+func (c *partialReadsClient) Select(ctx context.Context, qry string, callback func(clickhouseclient.Row) error) error {
+	err := c.ClickhouseClient.Select(ctx, qry, callback)
+	if err == nil {
+		return nil
+	}
+
+	if code, ok := errorCode(err); ok && code == errorCodeAccessDenied {
+		return &RestrictedReadError{err: err}
+	}
+
+	return err
+}
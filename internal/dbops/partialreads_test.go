@@ -0,0 +1,55 @@
+package dbops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/errors"
+)
+
+func TestNewClient_allowPartialReads(t *testing.T) {
+	denied := errors.New("code: 497, message: default: Not enough privileges. To execute this query, it's necessary to have the grant SHOW USERS ON *.*")
+	syntax := errors.New("Code: 62. DB::Exception: Syntax error. (SYNTAX_ERROR)")
+
+	tests := []struct {
+		name              string
+		allowPartialReads bool
+		selectErr         error
+		want              bool
+	}{
+		{
+			name:              "Access denied",
+			allowPartialReads: true,
+			selectErr:         denied,
+			want:              true,
+		},
+		{
+			name:              "Other error",
+			allowPartialReads: true,
+			selectErr:         syntax,
+			want:              false,
+		},
+		{
+			name:              "Partial reads disabled",
+			allowPartialReads: false,
+			selectErr:         denied,
+			want:              false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(&execClient{selectErr: tt.selectErr}, Config{AllowPartialReads: tt.allowPartialReads})
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			_, err = c.GetUser(context.Background(), "8f7a1c2e-4a4b-4d6e-9b0a-2f3c4d5e6f70", nil)
+			if err == nil {
+				t.Fatalf("GetUser() error = nil, want an error")
+			}
+			if got := IsRestrictedRead(err); got != tt.want {
+				t.Errorf("IsRestrictedRead() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package dbops
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// GetExpiredPartitions returns the IDs of the table's partitions whose newest row is older than the olderThan SQL
+// expression, e.g. `now() - INTERVAL 90 DAY`. Only partitions of tables partitioned by a Date or DateTime column
+// can expire: partitions without time bounds are never returned.
+func (i *impl) GetExpiredPartitions(ctx context.Context, databaseName string, tableName string, olderThan string, clusterName *string) ([]string, error) {
+	if olderThan == "" {
+		return nil, errors.New("olderThan expression cannot be empty")
+	}
+	// olderThan is embedded in the query as is.
+	if err := querybuilder.CheckExpression(olderThan); err != nil {
+		return nil, errors.WithMessage(err, "invalid olderThan expression")
+	}
+
+	// max_date is set when partitioning by a Date column, max_time when partitioning by a DateTime column.
+	maxTime := "greatest(toDateTime(max_date), max_time)"
+
+	sql, err := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewField("partition_id"),
+			querybuilder.NewExpressionField(fmt.Sprintf("toUInt8(%s > toDateTime(0) AND %s < (%s))", maxTime, maxTime, olderThan), "expired"),
+		},
+		"system.parts",
+	).WithCluster(i.readCluster(clusterName)).
+		Where(
+			querybuilder.WhereEquals("database", databaseName),
+			querybuilder.WhereEquals("table", tableName),
+			querybuilder.WhereEquals("active", 1),
+		).
+		Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	// A partition is expired only if all of its parts are.
+	expired := make(map[string]bool)
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		partitionID, err := data.GetString("partition_id")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'partition_id' field")
+		}
+		partExpired, err := data.GetBool("expired")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'expired' field")
+		}
+
+		if e, ok := expired[partitionID]; ok {
+			expired[partitionID] = e && partExpired
+		} else {
+			expired[partitionID] = partExpired
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	ret := make([]string, 0)
+	for partitionID, e := range expired {
+		if e {
+			ret = append(ret, partitionID)
+		}
+	}
+	sort.Strings(ret)
+
+	return ret, nil
+}
+
+func (i *impl) DropPartitions(ctx context.Context, databaseName string, tableName string, partitionIDs []string, clusterName *string) error {
+	if err := i.CheckDatabaseManageable(databaseName); err != nil {
+		return err
+	}
+
+	sql, err := querybuilder.NewAlterTableDropPartition(databaseName, tableName, partitionIDs).
+		WithCluster(clusterName).
+		Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building ALTER TABLE DROP PARTITION query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		return errors.WithMessage(err, "error dropping partitions")
+	}
+
+	return nil
+}
+
+// FreezeTable runs ALTER TABLE FREEZE, creating a local snapshot named snapshotName of the whole table, or of a
+// single partition when partition is set.
+func (i *impl) FreezeTable(ctx context.Context, databaseName string, tableName string, partition *string, snapshotName string, clusterName *string) error {
+	sql, err := querybuilder.NewAlterTableFreeze(databaseName, tableName, snapshotName).
+		WithPartition(partition).
+		WithCluster(clusterName).
+		Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building ALTER TABLE FREEZE query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		return errors.WithMessage(err, "error freezing table")
+	}
+
+	return nil
+}
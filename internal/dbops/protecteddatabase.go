@@ -0,0 +1,21 @@
+package dbops
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/pingcap/errors"
+)
+
+// DefaultProtectedDatabases are the databases of ClickHouse itself, whose objects are never managed by default.
+var DefaultProtectedDatabases = []string{"system", "INFORMATION_SCHEMA", "information_schema"}
+
+// CheckDatabaseManageable returns an error when the database is one of the ProtectedDatabases of the provider: it
+// can't be created or dropped, nor can the tables in it. Names are compared case-sensitively, like ClickHouse does.
+func (i *impl) CheckDatabaseManageable(databaseName string) error {
+	if !slices.Contains(i.config.ProtectedDatabases, databaseName) {
+		return nil
+	}
+
+	return errors.New(fmt.Sprintf("database %q is protected, objects in it can't be managed: remove it from the provider's protected_databases to allow it", databaseName))
+}
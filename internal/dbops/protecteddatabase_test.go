@@ -0,0 +1,90 @@
+package dbops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+func Test_impl_CheckDatabaseManageable(t *testing.T) {
+	tests := []struct {
+		name      string
+		protected []string
+		database  string
+		wantErr   bool
+	}{
+		{
+			name:      "System database",
+			protected: DefaultProtectedDatabases,
+			database:  "system",
+			wantErr:   true,
+		},
+		{
+			name:      "Information schema in upper case",
+			protected: DefaultProtectedDatabases,
+			database:  "INFORMATION_SCHEMA",
+			wantErr:   true,
+		},
+		{
+			name:      "Names are case sensitive",
+			protected: DefaultProtectedDatabases,
+			database:  "System",
+			wantErr:   false,
+		},
+		{
+			name:      "Other database",
+			protected: DefaultProtectedDatabases,
+			database:  "analytics",
+			wantErr:   false,
+		},
+		{
+			name:      "Custom list",
+			protected: []string{"billing"},
+			database:  "billing",
+			wantErr:   true,
+		},
+		{
+			name:      "Protection disabled",
+			protected: []string{},
+			database:  "system",
+			wantErr:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &impl{config: Config{ProtectedDatabases: tt.protected}}
+			if err := i.CheckDatabaseManageable(tt.database); (err != nil) != tt.wantErr {
+				t.Errorf("CheckDatabaseManageable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_impl_alterProtectedTable(t *testing.T) {
+	client := &hostsClient{}
+	i := &impl{clickhouseClient: client, config: Config{ProtectedDatabases: DefaultProtectedDatabases}}
+	ctx := context.Background()
+
+	alters := map[string]func() error{
+		"AddTableColumns": func() error {
+			return i.AddTableColumns(ctx, "system", "query_log", []querybuilder.TableColumn{{Name: "a", Type: "String"}}, nil)
+		},
+		"DropTableColumns": func() error { return i.DropTableColumns(ctx, "system", "query_log", []string{"a"}, nil) },
+		"RenameTable":      func() error { return i.RenameTable(ctx, "system", "query_log", "query_log_old", nil) },
+		"ExchangeTables":   func() error { return i.ExchangeTables(ctx, "system", "query_log", "query_log_old", nil) },
+		"DropTableIndex":   func() error { return i.DropTableIndex(ctx, "system", "query_log", "idx", nil) },
+		"ModifyTableTTL":   func() error { return i.ModifyTableTTL(ctx, "system", "query_log", nil, nil) },
+	}
+	for name, alter := range alters {
+		t.Run(name, func(t *testing.T) {
+			if err := alter(); err == nil {
+				t.Errorf("%s() on a protected database succeeded, want an error", name)
+			}
+		})
+	}
+
+	if len(client.execs) > 0 {
+		t.Errorf("ran %v on a protected database", client.execs)
+	}
+}
@@ -0,0 +1,17 @@
+package dbops
+
+import (
+	"context"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+)
+
+// WithQueryTag returns a context tagging the queries run with it with the type of the resource or data source they
+// are run for, e.g. `clickhousedbops_table` or `data.clickhousedbops_tables`, and the operation, e.g. `create`.
+// Terraform doesn't tell providers the address of resources, so the type is the most specific information available.
+func WithQueryTag(ctx context.Context, typeName string, operation string) context.Context {
+	return clickhouseclient.WithQueryTag(ctx, clickhouseclient.QueryTag{
+		Resource:  typeName,
+		Operation: operation,
+	})
+}
@@ -0,0 +1,377 @@
+package dbops
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// Quota limits the resources users and roles consume over intervals of time.
+type Quota struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// KeyedBy is how the consumption is tracked, e.g. `user_name` or `client_key,ip_address`. Nil when it is shared by
+	// every user of the quota.
+	KeyedBy   *string         `json:"keyed_by"`
+	Intervals []QuotaInterval `json:"intervals"`
+	// ApplyTo lists the users and roles the quota applies to, or the ones it doesn't apply to when ApplyToAll is set.
+	// When creating or updating a quota, nil with ApplyToAll unset leaves them as they are, e.g. for quotas assigned
+	// with AssignQuota.
+	ApplyTo    []string `json:"apply_to"`
+	ApplyToAll bool     `json:"apply_to_all"`
+	// Storage is the access storage holding the quota, see IsReadOnlyStorage.
+	Storage string `json:"storage"`
+}
+
+// QuotaInterval is an interval of a quota, and the maximum consumption allowed during it. Nil maximums are unlimited.
+type QuotaInterval struct {
+	// Duration is the length of the interval, in seconds.
+	Duration   uint64 `json:"duration"`
+	Randomized bool   `json:"randomized"`
+
+	MaxQueries       *uint64  `json:"max_queries"`
+	MaxQuerySelects  *uint64  `json:"max_query_selects"`
+	MaxQueryInserts  *uint64  `json:"max_query_inserts"`
+	MaxErrors        *uint64  `json:"max_errors"`
+	MaxResultRows    *uint64  `json:"max_result_rows"`
+	MaxResultBytes   *uint64  `json:"max_result_bytes"`
+	MaxReadRows      *uint64  `json:"max_read_rows"`
+	MaxReadBytes     *uint64  `json:"max_read_bytes"`
+	MaxExecutionTime *float64 `json:"max_execution_time"`
+}
+
+// quotaLimitColumns are the system.quota_limits columns of the integer maximums, along with the name of the resource
+// in CREATE QUOTA queries.
+var quotaLimitColumns = []struct {
+	column   string
+	resource string
+	field    func(interval *QuotaInterval) **uint64
+}{
+	{column: "max_queries", resource: "queries", field: func(i *QuotaInterval) **uint64 { return &i.MaxQueries }},
+	{column: "max_query_selects", resource: "query_selects", field: func(i *QuotaInterval) **uint64 { return &i.MaxQuerySelects }},
+	{column: "max_query_inserts", resource: "query_inserts", field: func(i *QuotaInterval) **uint64 { return &i.MaxQueryInserts }},
+	{column: "max_errors", resource: "errors", field: func(i *QuotaInterval) **uint64 { return &i.MaxErrors }},
+	{column: "max_result_rows", resource: "result_rows", field: func(i *QuotaInterval) **uint64 { return &i.MaxResultRows }},
+	{column: "max_result_bytes", resource: "result_bytes", field: func(i *QuotaInterval) **uint64 { return &i.MaxResultBytes }},
+	{column: "max_read_rows", resource: "read_rows", field: func(i *QuotaInterval) **uint64 { return &i.MaxReadRows }},
+	{column: "max_read_bytes", resource: "read_bytes", field: func(i *QuotaInterval) **uint64 { return &i.MaxReadBytes }},
+}
+
+func (i *impl) CreateQuota(ctx context.Context, quota Quota, clusterName *string) (*Quota, error) {
+	sql, err := quotaStatement(querybuilder.NewCreateQuota(quota.Name), quota, nil, clusterName)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	created, err := readAfterCreate(ctx, i, clusterName, func(ctx context.Context) (*Quota, error) {
+		return i.findQuota(ctx, quota.Name, clusterName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if created == nil {
+		return nil, errors.New("quota with such name not found")
+	}
+
+	return created, nil
+}
+
+// UpdateQuota replaces the key and the intervals of the quota with the ones of the given quota, and the users and
+// roles it applies to when set.
+func (i *impl) UpdateQuota(ctx context.Context, quota Quota, clusterName *string) (*Quota, error) {
+	// Serialized with the assignments, which read and rewrite the grantees of the quota.
+	i.quotaAssignmentMu.Lock()
+	defer i.quotaAssignmentMu.Unlock()
+
+	current, err := i.GetQuota(ctx, quota.ID, clusterName)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error getting quota")
+	}
+	if current == nil {
+		return nil, errors.New("quota not found")
+	}
+
+	sql, err := quotaStatement(querybuilder.NewAlterQuota(quota.Name), quota, current.Intervals, clusterName)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return i.GetQuota(ctx, quota.ID, clusterName)
+}
+
+// quotaStatement builds the statement creating or altering the quota. ALTER QUOTA keeps the intervals it doesn't
+// mention: the current intervals missing from the quota are removed explicitly.
+func quotaStatement(builder querybuilder.QuotaQueryBuilder, quota Quota, current []QuotaInterval, clusterName *string) (string, error) {
+	intervals := make([]querybuilder.QuotaInterval, 0, len(quota.Intervals)+len(current))
+	durations := make(map[quotaIntervalKey]bool)
+	for _, interval := range quota.Intervals {
+		durations[quotaIntervalKey{interval.Duration, interval.Randomized}] = true
+		intervals = append(intervals, querybuilder.QuotaInterval{
+			Duration:   interval.Duration,
+			Randomized: interval.Randomized,
+			Limits:     interval.limits(),
+		})
+	}
+	for _, interval := range current {
+		if !durations[quotaIntervalKey{interval.Duration, interval.Randomized}] {
+			intervals = append(intervals, querybuilder.QuotaInterval{
+				Duration:   interval.Duration,
+				Randomized: interval.Randomized,
+				NoLimits:   true,
+			})
+		}
+	}
+
+	builder.WithKeyedBy(quota.KeyedBy).WithIntervals(intervals).WithCluster(clusterName)
+	if quota.ApplyToAll {
+		builder.WithApplyToAll(quota.ApplyTo)
+	} else if quota.ApplyTo != nil {
+		builder.WithApplyTo(quota.ApplyTo)
+	}
+
+	return builder.Build()
+}
+
+// quotaIntervalKey identifies an interval of a quota, ClickHouse keeping a single interval per duration and kind.
+type quotaIntervalKey struct {
+	duration   uint64
+	randomized bool
+}
+
+// limits returns the maximums of the interval keyed by resource name.
+func (q QuotaInterval) limits() map[string]string {
+	limits := make(map[string]string)
+	for _, c := range quotaLimitColumns {
+		if value := *c.field(&q); value != nil {
+			limits[c.resource] = strconv.FormatUint(*value, 10)
+		}
+	}
+	if q.MaxExecutionTime != nil {
+		limits["execution_time"] = strconv.FormatFloat(*q.MaxExecutionTime, 'f', -1, 64)
+	}
+
+	return limits
+}
+
+// GetQuota returns the quota with the given ID, or nil if it does not exist.
+func (i *impl) GetQuota(ctx context.Context, id string, clusterName *string) (*Quota, error) {
+	return i.selectQuota(ctx, clusterName, querybuilder.WhereEquals("id", querybuilder.NewParameter("id", "UUID", id)))
+}
+
+func (i *impl) FindQuotaByName(ctx context.Context, name string, clusterName *string) (*Quota, error) {
+	quota, err := i.findQuota(ctx, name, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	if quota == nil {
+		return nil, errors.New("quota with such name not found")
+	}
+
+	return quota, nil
+}
+
+// findQuota returns the quota with the given name, or nil if it does not exist.
+func (i *impl) findQuota(ctx context.Context, name string, clusterName *string) (*Quota, error) {
+	return i.selectQuota(ctx, clusterName, querybuilder.WhereEquals("name", querybuilder.NewParameter("name", "String", name)))
+}
+
+func (i *impl) selectQuota(ctx context.Context, clusterName *string, where ...querybuilder.Where) (*Quota, error) {
+	query := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewExpressionField("toString(id)", "id"),
+			querybuilder.NewField("name"),
+			querybuilder.NewExpressionField("arrayStringConcat(arrayMap(k -> toString(k), keys), ',')", "keyed_by"),
+			querybuilder.NewExpressionField("toUInt8(apply_to_all)", "apply_to_all"),
+			// Arrays are joined with new lines, which can't be part of user nor role names.
+			querybuilder.NewExpressionField("arrayStringConcat(if(apply_to_all, apply_to_except, apply_to_list), '\\n')", "apply_to"),
+			querybuilder.NewField("storage"),
+		},
+		"system.quotas",
+	).WithCluster(i.readCluster(clusterName)).Where(where...)
+	sql, err := query.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	var quota *Quota
+
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
+		if quota != nil {
+			// With a cluster, every replica returns its own copy of the quota.
+			return nil
+		}
+
+		id, err := data.GetString("id")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'id' field")
+		}
+		n, err := data.GetString("name")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'name' field")
+		}
+		keyedBy, err := data.GetString("keyed_by")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'keyed_by' field")
+		}
+		applyToAll, err := data.GetBool("apply_to_all")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'apply_to_all' field")
+		}
+		applyTo, err := data.GetString("apply_to")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'apply_to' field")
+		}
+		s, err := data.GetString("storage")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'storage' field")
+		}
+
+		quota = &Quota{
+			ID:         id,
+			Name:       n,
+			ApplyTo:    splitLines(applyTo),
+			ApplyToAll: applyToAll,
+			Storage:    s,
+		}
+		if keyedBy != "" {
+			quota.KeyedBy = &keyedBy
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	if quota == nil {
+		return nil, nil
+	}
+
+	quota.Intervals, err = i.getQuotaIntervals(ctx, quota.Name, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	return quota, nil
+}
+
+// getQuotaIntervals returns the intervals of the quota from system.quota_limits, shortest first.
+func (i *impl) getQuotaIntervals(ctx context.Context, quotaName string, clusterName *string) ([]QuotaInterval, error) {
+	fields := []querybuilder.Field{
+		querybuilder.NewExpressionField("toUInt64(duration)", "duration"),
+		querybuilder.NewExpressionField("toUInt8(is_randomized_interval)", "randomized"),
+		// Maximums are read as strings, Row has no getter for nullable numbers.
+		querybuilder.NewExpressionField("toString(max_execution_time)", "max_execution_time"),
+	}
+	for _, c := range quotaLimitColumns {
+		fields = append(fields, querybuilder.NewExpressionField("toString("+c.column+")", c.column))
+	}
+
+	query := querybuilder.NewSelect(fields, "system.quota_limits").
+		WithCluster(i.readCluster(clusterName)).
+		Where(querybuilder.WhereEquals("quota_name", querybuilder.NewParameter("quota_name", "String", quotaName))).
+		OrderBy("duration")
+	sql, err := query.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	intervals := make([]QuotaInterval, 0)
+	seen := make(map[quotaIntervalKey]bool)
+
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
+		duration, err := data.GetUInt64("duration")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'duration' field")
+		}
+		randomized, err := data.GetBool("randomized")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'randomized' field")
+		}
+
+		// Reading from a cluster returns the intervals once per replica.
+		key := quotaIntervalKey{duration, randomized}
+		if seen[key] {
+			return nil
+		}
+		seen[key] = true
+
+		interval := QuotaInterval{
+			Duration:   duration,
+			Randomized: randomized,
+		}
+
+		for _, c := range quotaLimitColumns {
+			value, err := data.GetNullableString(c.column)
+			if err != nil {
+				return errors.WithMessage(err, "error scanning query result, missing '"+c.column+"' field")
+			}
+			if value == nil {
+				continue
+			}
+			n, err := strconv.ParseUint(*value, 10, 64)
+			if err != nil {
+				return errors.WithMessage(err, "error parsing '"+c.column+"' field")
+			}
+			*c.field(&interval) = &n
+		}
+
+		executionTime, err := data.GetNullableString("max_execution_time")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'max_execution_time' field")
+		}
+		if executionTime != nil {
+			seconds, err := strconv.ParseFloat(*executionTime, 64)
+			if err != nil {
+				return errors.WithMessage(err, "error parsing 'max_execution_time' field")
+			}
+			interval.MaxExecutionTime = &seconds
+		}
+
+		intervals = append(intervals, interval)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return intervals, nil
+}
+
+func (i *impl) DeleteQuota(ctx context.Context, id string, clusterName *string) error {
+	quota, err := i.GetQuota(ctx, id, clusterName)
+	if err != nil {
+		return errors.WithMessage(err, "error getting quota")
+	}
+
+	if quota == nil {
+		// That's what we want.
+		return nil
+	}
+
+	sql, err := querybuilder.NewDropQuota(quota.Name).WithCluster(clusterName).Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
+	}
+
+	return nil
+}
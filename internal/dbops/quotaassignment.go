@@ -0,0 +1,186 @@
+package dbops
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+type QuotaAssignment struct {
+	QuotaName       string
+	GranteeUserName *string
+	GranteeRoleName *string
+}
+
+// quotaTargets holds the users and roles a quota applies to.
+type quotaTargets struct {
+	all      bool
+	grantees []string
+}
+
+// AssignQuota adds the grantee to the users and roles the quota applies to, leaving the other ones untouched.
+func (i *impl) AssignQuota(ctx context.Context, assignment QuotaAssignment, clusterName *string) (*QuotaAssignment, error) {
+	grantee, err := quotaGrantee(assignment.GranteeUserName, assignment.GranteeRoleName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := i.checkWritable(ctx, "quota", "system.quotas", assignment.QuotaName, clusterName); err != nil {
+		return nil, err
+	}
+
+	// ClickHouse can only replace the whole list of grantees of a quota, serialize the changes
+	// so that assignments of the same quota running in parallel don't overwrite each other.
+	i.quotaAssignmentMu.Lock()
+	defer i.quotaAssignmentMu.Unlock()
+
+	targets, err := i.getQuotaTargets(ctx, assignment.QuotaName, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if targets == nil {
+		return nil, errors.New(fmt.Sprintf("quota %q not found", assignment.QuotaName))
+	}
+	if targets.all {
+		return nil, errors.New(fmt.Sprintf("quota %q already applies to all users and roles", assignment.QuotaName))
+	}
+
+	if !slices.Contains(targets.grantees, grantee) {
+		err = i.applyQuotaTo(ctx, assignment.QuotaName, append(targets.grantees, grantee), clusterName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return readAfterCreate(ctx, i, clusterName, func(ctx context.Context) (*QuotaAssignment, error) {
+		return i.GetQuotaAssignment(ctx, assignment.QuotaName, assignment.GranteeUserName, assignment.GranteeRoleName, clusterName)
+	})
+}
+
+// GetQuotaAssignment returns the assignment of the quota to the given user or role, or nil if the quota
+// does not list the grantee.
+func (i *impl) GetQuotaAssignment(ctx context.Context, quotaName string, granteeUserName *string, granteeRoleName *string, clusterName *string) (*QuotaAssignment, error) {
+	grantee, err := quotaGrantee(granteeUserName, granteeRoleName)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := i.getQuotaTargets(ctx, quotaName, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	if targets == nil || !slices.Contains(targets.grantees, grantee) {
+		// Assignment not found
+		return nil, nil
+	}
+
+	return &QuotaAssignment{
+		QuotaName:       quotaName,
+		GranteeUserName: granteeUserName,
+		GranteeRoleName: granteeRoleName,
+	}, nil
+}
+
+// UnassignQuota removes the grantee from the users and roles the quota applies to, leaving the other ones untouched.
+func (i *impl) UnassignQuota(ctx context.Context, quotaName string, granteeUserName *string, granteeRoleName *string, clusterName *string) error {
+	grantee, err := quotaGrantee(granteeUserName, granteeRoleName)
+	if err != nil {
+		return err
+	}
+
+	i.quotaAssignmentMu.Lock()
+	defer i.quotaAssignmentMu.Unlock()
+
+	targets, err := i.getQuotaTargets(ctx, quotaName, clusterName)
+	if err != nil {
+		return err
+	}
+
+	if targets == nil || !slices.Contains(targets.grantees, grantee) {
+		// Nothing to remove.
+		return nil
+	}
+
+	return i.applyQuotaTo(ctx, quotaName, slices.DeleteFunc(targets.grantees, func(g string) bool { return g == grantee }), clusterName)
+}
+
+// getQuotaTargets returns the users and roles the quota applies to, or nil if the quota does not exist.
+func (i *impl) getQuotaTargets(ctx context.Context, quotaName string, clusterName *string) (*quotaTargets, error) {
+	sql, err := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewField("apply_to_all"),
+			// One row per grantee, keeping a row for quotas that apply to no one.
+			querybuilder.NewExpressionField("arrayJoin(if(empty(apply_to_list), [''], apply_to_list))", "grantee"),
+		},
+		"system.quotas",
+	).WithCluster(i.readCluster(clusterName)).
+		Where(querybuilder.WhereEquals("name", quotaName)).
+		Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	var targets *quotaTargets
+
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		all, err := data.GetBool("apply_to_all")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'apply_to_all' field")
+		}
+		grantee, err := data.GetString("grantee")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'grantee' field")
+		}
+
+		if targets == nil {
+			targets = &quotaTargets{
+				grantees: make([]string, 0),
+			}
+		}
+
+		targets.all = targets.all || all
+
+		// Replicas of a cluster return the same grantees.
+		if grantee != "" && !slices.Contains(targets.grantees, grantee) {
+			targets.grantees = append(targets.grantees, grantee)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return targets, nil
+}
+
+func (i *impl) applyQuotaTo(ctx context.Context, quotaName string, grantees []string, clusterName *string) error {
+	sql, err := querybuilder.NewAlterQuotaApplyTo(quotaName, grantees).WithCluster(clusterName).Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
+	}
+
+	return nil
+}
+
+// quotaGrantee returns the name of the user or role to apply a quota to. Users and roles share the same list.
+func quotaGrantee(granteeUserName *string, granteeRoleName *string) (string, error) {
+	if granteeUserName != nil {
+		return *granteeUserName, nil
+	} else if granteeRoleName != nil {
+		return *granteeRoleName, nil
+	}
+
+	return "", errors.New("either GranteeUserName or GranteeRoleName must be set")
+}
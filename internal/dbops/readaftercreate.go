@@ -0,0 +1,33 @@
+package dbops
+
+import (
+	"context"
+	"time"
+)
+
+// readAfterCreateInterval is the delay between two reads of an object that was just created and not found yet.
+var readAfterCreateInterval = 500 * time.Millisecond
+
+// readAfterCreate reads an object that was just created with get, which returns nil when the object doesn't exist.
+// Statements run ON CLUSTER are applied by every replica on its own, so a read right after the creation can hit a
+// replica that didn't apply it yet: with a cluster, get is retried until it finds the object or
+// Config.ReadAfterCreateTimeout elapses.
+func readAfterCreate[T any](ctx context.Context, i *impl, clusterName *string, get func(ctx context.Context) (*T, error)) (*T, error) {
+	deadline := time.Now().Add(i.config.ReadAfterCreateTimeout)
+
+	for {
+		obj, err := get(ctx)
+		if err != nil || obj != nil || clusterName == nil || !time.Now().Before(deadline) {
+			return obj, err
+		}
+
+		// Don't serve the missing object from the cache.
+		invalidateTableCache(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(readAfterCreateInterval):
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package dbops
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_readAfterCreate(t *testing.T) {
+	readAfterCreateInterval = 0
+
+	clusterName := "cluster1"
+	found := "object"
+
+	tests := []struct {
+		name        string
+		clusterName *string
+		timeout     time.Duration
+		foundAfter  int
+		wantFound   bool
+		wantCalls   int
+	}{
+		{
+			name:        "Found right away",
+			clusterName: &clusterName,
+			timeout:     time.Minute,
+			foundAfter:  1,
+			wantFound:   true,
+			wantCalls:   1,
+		},
+		{
+			name:        "Found on a later read",
+			clusterName: &clusterName,
+			timeout:     time.Minute,
+			foundAfter:  3,
+			wantFound:   true,
+			wantCalls:   3,
+		},
+		{
+			name:        "Not retried without a cluster",
+			clusterName: nil,
+			timeout:     time.Minute,
+			foundAfter:  3,
+			wantFound:   false,
+			wantCalls:   1,
+		},
+		{
+			name:        "Not retried without a timeout",
+			clusterName: &clusterName,
+			timeout:     0,
+			foundAfter:  3,
+			wantFound:   false,
+			wantCalls:   1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &impl{config: Config{ReadAfterCreateTimeout: tt.timeout}}
+
+			calls := 0
+			got, err := readAfterCreate(context.Background(), i, tt.clusterName, func(context.Context) (*string, error) {
+				calls++
+				if calls >= tt.foundAfter {
+					return &found, nil
+				}
+				return nil, nil
+			})
+			if err != nil {
+				t.Fatalf("readAfterCreate() error = %v", err)
+			}
+			if (got != nil) != tt.wantFound {
+				t.Errorf("readAfterCreate() = %v, want found %v", got, tt.wantFound)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("readAfterCreate() read %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
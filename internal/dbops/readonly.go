@@ -0,0 +1,19 @@
+package dbops
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+)
+
+// readOnlyClient runs SELECT queries as usual and refuses every other statement.
+type readOnlyClient struct {
+	clickhouseclient.ClickhouseClient
+}
+
+func (c *readOnlyClient) Exec(_ context.Context, qry string) error {
+	return errors.New(fmt.Sprintf("the provider is in read_only mode, refusing to run: %s", clickhouseclient.RedactSecrets(qry)))
+}
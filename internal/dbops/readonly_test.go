@@ -0,0 +1,27 @@
+package dbops
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestNewClient_readOnly(t *testing.T) {
+	client := &execClient{}
+
+	c, err := NewClient(client, Config{ReadOnly: true})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	err = c.SyncReplica(context.Background(), "db", "events", nil)
+	if err == nil {
+		t.Fatalf("SyncReplica() error = nil, want an error in read_only mode")
+	}
+	if !strings.Contains(err.Error(), "SYSTEM SYNC REPLICA `db`.`events`") {
+		t.Errorf("SyncReplica() error = %q, want it to contain the statement", err.Error())
+	}
+	if client.calls != 0 {
+		t.Errorf("Exec called %d times, want 0", client.calls)
+	}
+}
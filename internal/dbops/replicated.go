@@ -9,8 +9,77 @@ import (
 	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
 )
 
+const (
+	// AccessStorageModeAuto runs access entity statements ON CLUSTER unless the access storage is replicated.
+	AccessStorageModeAuto = "auto"
+	// AccessStorageModeOnCluster always runs access entity statements ON CLUSTER.
+	AccessStorageModeOnCluster = "on_cluster"
+	// AccessStorageModeReplicated never runs access entity statements ON CLUSTER, relying on the replicated access
+	// storage to propagate the changes.
+	AccessStorageModeReplicated = "replicated"
+)
+
+var AccessStorageModes = []string{AccessStorageModeAuto, AccessStorageModeOnCluster, AccessStorageModeReplicated}
+
+// AccessCluster returns the cluster users, roles and grants with the given cluster name and access storage mode are
+// managed on, nil meaning the replica the client is connected to. An empty mode falls back to the mode of the client
+// config, then to AccessStorageModeAuto.
+func (i *impl) AccessCluster(ctx context.Context, clusterName *string, mode string) (*string, error) {
+	if clusterName == nil {
+		return nil, nil
+	}
+
+	if mode == "" {
+		mode = i.config.AccessStorageMode
+	}
+
+	switch mode {
+	case AccessStorageModeOnCluster:
+		return clusterName, nil
+	case AccessStorageModeReplicated:
+		return nil, nil
+	}
+
+	replicated, err := i.IsReplicatedStorage(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error checking if access storage is replicated")
+	}
+	if replicated {
+		return nil, nil
+	}
+
+	return clusterName, nil
+}
+
 // IsReplicatedStorage queries system tables and checks if the highest priority storage system for users and roles is 'replicated'.
+// The user directories configuration cannot change without a server restart, so the result is computed once per client.
 func (i *impl) IsReplicatedStorage(ctx context.Context) (bool, error) {
+	i.replicatedStorageMu.Lock()
+	defer i.replicatedStorageMu.Unlock()
+
+	if i.replicatedStorage != nil {
+		return *i.replicatedStorage, nil
+	}
+
+	replicated, err := i.isReplicatedStorage(ctx)
+	if err != nil {
+		// Errors are not cached, next call will try again.
+		return false, err
+	}
+
+	i.replicatedStorage = &replicated
+
+	return replicated, nil
+}
+
+func (i *impl) isReplicatedStorage(ctx context.Context) (bool, error) {
+	if ok, err := i.supports(ctx, featureUserDirectories); err != nil {
+		return false, err
+	} else if !ok {
+		// Replicated access storage did not exist either.
+		return false, nil
+	}
+
 	sql, err := querybuilder.
 		NewSelect([]querybuilder.Field{querybuilder.NewField("type"), querybuilder.NewField("precedence")}, "system.user_directories").
 		Where(querybuilder.WhereDiffers("type", "users_xml")).
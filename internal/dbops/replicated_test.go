@@ -0,0 +1,88 @@
+package dbops
+
+import (
+	"context"
+	"testing"
+)
+
+func Test_impl_AccessCluster(t *testing.T) {
+	cluster := "default"
+
+	tests := []struct {
+		name        string
+		clusterName *string
+		mode        string
+		defaultMode string
+		replicated  bool
+		want        *string
+	}{
+		{
+			name:        "No cluster",
+			clusterName: nil,
+			mode:        AccessStorageModeOnCluster,
+			want:        nil,
+		},
+		{
+			name:        "Auto with local storage",
+			clusterName: &cluster,
+			mode:        AccessStorageModeAuto,
+			replicated:  false,
+			want:        &cluster,
+		},
+		{
+			name:        "Auto with replicated storage",
+			clusterName: &cluster,
+			mode:        AccessStorageModeAuto,
+			replicated:  true,
+			want:        nil,
+		},
+		{
+			name:        "On cluster with replicated storage",
+			clusterName: &cluster,
+			mode:        AccessStorageModeOnCluster,
+			replicated:  true,
+			want:        &cluster,
+		},
+		{
+			name:        "Replicated with local storage",
+			clusterName: &cluster,
+			mode:        AccessStorageModeReplicated,
+			replicated:  false,
+			want:        nil,
+		},
+		{
+			name:        "Default mode from config",
+			clusterName: &cluster,
+			mode:        "",
+			defaultMode: AccessStorageModeOnCluster,
+			replicated:  true,
+			want:        &cluster,
+		},
+		{
+			name:        "Resource mode overrides config",
+			clusterName: &cluster,
+			mode:        AccessStorageModeReplicated,
+			defaultMode: AccessStorageModeOnCluster,
+			replicated:  false,
+			want:        nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replicated := tt.replicated
+			i := &impl{
+				clickhouseClient:  &execClient{},
+				config:            Config{AccessStorageMode: tt.defaultMode},
+				replicatedStorage: &replicated,
+			}
+
+			got, err := i.AccessCluster(context.Background(), tt.clusterName, tt.mode)
+			if err != nil {
+				t.Fatalf("AccessCluster() error = %v", err)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("AccessCluster() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
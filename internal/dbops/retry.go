@@ -0,0 +1,95 @@
+package dbops
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// ClickHouse error codes that are expected to go away by themselves on a busy replicated cluster.
+const (
+	errorCodeNoZooKeeper          = 225
+	errorCodeTableIsReadOnly      = 242
+	errorCodeReplicaAlreadyExists = 253
+	errorCodeKeeperException      = 999
+)
+
+var recoverableErrorCodes = []int{
+	errorCodeNoZooKeeper,
+	errorCodeTableIsReadOnly,
+	errorCodeReplicaAlreadyExists,
+	errorCodeKeeperException,
+}
+
+const maxExecAttempts = 5
+
+// execRetryDelay is the wait before the first retry, doubled after every further attempt.
+var execRetryDelay = 2 * time.Second
+
+// errorCodeRegexp matches the error code in both the native ("code: 253, message: ...") and the HTTP
+// ("Code: 253. DB::Exception: ...") client errors.
+var errorCodeRegexp = regexp.MustCompile(`(?i)\bcode: (\d+)[.,]`)
+
+// appliedFunc reports whether a statement that failed did take effect anyway, e.g. on some replicas before the
+// failure. It is used to avoid retrying statements that are not idempotent.
+type appliedFunc func(ctx context.Context) (bool, error)
+
+// execWithRetry runs the statement, retrying with exponential backoff when it fails with a recoverable error.
+// Before every retry applied (if not nil) is called, and the statement is considered successful if it returns true.
+func (i *impl) execWithRetry(ctx context.Context, sql string, applied appliedFunc) error {
+	delay := execRetryDelay
+
+	for attempt := 1; ; attempt++ {
+		err := i.clickhouseClient.Exec(ctx, sql)
+		if err == nil {
+			return nil
+		}
+
+		code, ok := errorCode(err)
+		if !ok || !slices.Contains(recoverableErrorCodes, code) || attempt == maxExecAttempts {
+			return err
+		}
+
+		// The failed statement might have changed the tables on some replicas.
+		invalidateTableCache(ctx)
+
+		if applied != nil {
+			done, checkErr := applied(ctx)
+			if checkErr == nil && done {
+				return nil
+			}
+		}
+
+		tflog.Warn(ctx, "Retrying statement after recoverable error", map[string]interface{}{
+			"attempt": attempt,
+			"code":    code,
+			"error":   err.Error(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// errorCode returns the ClickHouse error code carried by err, if any.
+func errorCode(err error) (int, bool) {
+	match := errorCodeRegexp.FindStringSubmatch(err.Error())
+	if match == nil {
+		return 0, false
+	}
+
+	code, convErr := strconv.Atoi(match[1])
+	if convErr != nil {
+		return 0, false
+	}
+
+	return code, true
+}
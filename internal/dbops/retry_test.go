@@ -0,0 +1,112 @@
+package dbops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+)
+
+// execClient returns the given errors from successive Exec calls, then succeeds. Select always returns selectErr.
+type execClient struct {
+	errs      []error
+	calls     int
+	selectErr error
+	// selectRows is how many (empty) rows Select returns when selectErr is nil.
+	selectRows int
+}
+
+func (c *execClient) Select(_ context.Context, _ string, callback func(clickhouseclient.Row) error) error {
+	if c.selectErr != nil {
+		return c.selectErr
+	}
+	for range c.selectRows {
+		if err := callback(clickhouseclient.Row{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *execClient) Exec(context.Context, string) error {
+	c.calls++
+	if c.calls <= len(c.errs) {
+		return c.errs[c.calls-1]
+	}
+	return nil
+}
+
+func Test_impl_execWithRetry(t *testing.T) {
+	execRetryDelay = 0
+
+	readOnly := errors.New("Code: 242. DB::Exception: Table is in readonly mode. (TABLE_IS_READ_ONLY)")
+	keeper := errors.WithMessage(errors.New("code: 999, message: Session expired"), "error executing query")
+	syntax := errors.New("Code: 62. DB::Exception: Syntax error. (SYNTAX_ERROR)")
+
+	tests := []struct {
+		name      string
+		errs      []error
+		applied   appliedFunc
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			name:      "Success",
+			errs:      nil,
+			wantCalls: 1,
+			wantErr:   false,
+		},
+		{
+			name:      "Recoverable errors are retried",
+			errs:      []error{readOnly, keeper},
+			wantCalls: 3,
+			wantErr:   false,
+		},
+		{
+			name:      "Other errors are not retried",
+			errs:      []error{syntax},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "Gives up after max attempts",
+			errs:      []error{readOnly, readOnly, readOnly, readOnly, readOnly, readOnly},
+			wantCalls: maxExecAttempts,
+			wantErr:   true,
+		},
+		{
+			name: "Applied statement is not retried",
+			errs: []error{keeper},
+			applied: func(context.Context) (bool, error) {
+				return true, nil
+			},
+			wantCalls: 1,
+			wantErr:   false,
+		},
+		{
+			name: "Failed applied check retries",
+			errs: []error{keeper},
+			applied: func(context.Context) (bool, error) {
+				return false, errors.New("cannot check")
+			},
+			wantCalls: 2,
+			wantErr:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &execClient{errs: tt.errs}
+			i := &impl{clickhouseClient: client}
+
+			err := i.execWithRetry(context.Background(), "SELECT 1", tt.applied)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("execWithRetry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if client.calls != tt.wantCalls {
+				t.Errorf("execWithRetry() ran the statement %d times, want %d", client.calls, tt.wantCalls)
+			}
+		})
+	}
+}
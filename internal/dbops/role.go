@@ -12,6 +12,8 @@ import (
 type Role struct {
 	ID   string `json:"id" ch:"id"`
 	Name string `json:"name" ch:"name"`
+	// Storage is the access storage holding the role, see IsReadOnlyStorage.
+	Storage string `json:"storage" ch:"storage"`
 }
 
 func (i *impl) CreateRole(ctx context.Context, role Role, clusterName *string) (*Role, error) {
@@ -25,28 +27,44 @@ func (i *impl) CreateRole(ctx context.Context, role Role, clusterName *string) (
 		return nil, errors.WithMessage(err, "error running query")
 	}
 
-	return i.FindRoleByName(ctx, role.Name, clusterName)
+	created, err := readAfterCreate(ctx, i, clusterName, func(ctx context.Context) (*Role, error) {
+		return i.findRole(ctx, role.Name, clusterName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if created == nil {
+		return nil, errors.New("role with such name not found")
+	}
+
+	return created, nil
 }
 
 func (i *impl) GetRole(ctx context.Context, id string, clusterName *string) (*Role, error) { // nolint:dupl
-	sql, err := querybuilder.NewSelect(
-		[]querybuilder.Field{querybuilder.NewField("name")},
+	query := querybuilder.NewSelect(
+		[]querybuilder.Field{querybuilder.NewField("name"), querybuilder.NewField("storage")},
 		"system.roles",
-	).WithCluster(clusterName).Where(querybuilder.WhereEquals("id", id)).Build()
+	).WithCluster(i.readCluster(clusterName)).Where(querybuilder.WhereEquals("id", querybuilder.NewParameter("id", "UUID", id)))
+	sql, err := query.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
 	var role *Role
 
-	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
 		n, err := data.GetString("name")
 		if err != nil {
 			return errors.WithMessage(err, "error scanning query result, missing 'name' field")
 		}
+		s, err := data.GetString("storage")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'storage' field")
+		}
 		role = &Role{
-			ID:   id,
-			Name: n,
+			ID:      id,
+			Name:    n,
+			Storage: s,
 		}
 		return nil
 	})
@@ -83,21 +101,39 @@ func (i *impl) DeleteRole(ctx context.Context, id string, clusterName *string) e
 		return errors.WithMessage(err, "error running query")
 	}
 
+	// Grants to and of the dropped entity are gone as well.
+	i.granteeGrants.invalidate()
+
 	return nil
 }
 
 func (i *impl) FindRoleByName(ctx context.Context, name string, clusterName *string) (*Role, error) {
-	sql, err := querybuilder.NewSelect(
+	role, err := i.findRole(ctx, name, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	if role == nil {
+		return nil, errors.New("role with such name not found")
+	}
+
+	return role, nil
+}
+
+// findRole returns the role with the given name, or nil if it does not exist.
+func (i *impl) findRole(ctx context.Context, name string, clusterName *string) (*Role, error) {
+	query := querybuilder.NewSelect(
 		[]querybuilder.Field{querybuilder.NewField("id")},
 		"system.roles",
-	).Where(querybuilder.WhereEquals("name", name)).WithCluster(clusterName).Build()
+	).Where(querybuilder.WhereEquals("name", querybuilder.NewParameter("name", "String", name))).WithCluster(i.readCluster(clusterName))
+	sql, err := query.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
 	var uuid string
 
-	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
 		uuid, err = data.GetString("id")
 		if err != nil {
 			return errors.WithMessage(err, "error scanning query result, missing 'id' field")
@@ -109,5 +145,9 @@ func (i *impl) FindRoleByName(ctx context.Context, name string, clusterName *str
 		return nil, errors.WithMessage(err, "error running query")
 	}
 
+	if uuid == "" {
+		return nil, nil
+	}
+
 	return i.GetRole(ctx, uuid, clusterName)
 }
@@ -0,0 +1,215 @@
+package dbops
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// RowPolicy is a row policy filtering the rows SELECT queries return from a table.
+type RowPolicy struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	DatabaseName string `json:"database_name"`
+	TableName    string `json:"table_name"`
+	// Condition is the USING expression, as normalized by the server when read back.
+	Condition   string `json:"condition"`
+	Restrictive bool   `json:"restrictive"`
+	// ApplyTo lists the users and roles the policy applies to, or the ones it doesn't apply to when ApplyToAll is set.
+	ApplyTo    []string `json:"apply_to"`
+	ApplyToAll bool     `json:"apply_to_all"`
+	// Storage is the access storage holding the policy, see IsReadOnlyStorage.
+	Storage string `json:"storage"`
+}
+
+func (i *impl) CreateRowPolicy(ctx context.Context, policy RowPolicy, clusterName *string) (*RowPolicy, error) {
+	sql, err := rowPolicyStatement(querybuilder.NewCreateRowPolicy(policy.Name, policy.DatabaseName, policy.TableName, policy.Condition), policy, clusterName)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	created, err := readAfterCreate(ctx, i, clusterName, func(ctx context.Context) (*RowPolicy, error) {
+		return i.findRowPolicy(ctx, policy.Name, policy.DatabaseName, policy.TableName, clusterName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if created == nil {
+		return nil, errors.New("row policy with such name not found")
+	}
+
+	return created, nil
+}
+
+// UpdateRowPolicy replaces the condition, kind and grantees of the policy with the ones of the given policy.
+func (i *impl) UpdateRowPolicy(ctx context.Context, policy RowPolicy, clusterName *string) (*RowPolicy, error) {
+	sql, err := rowPolicyStatement(querybuilder.NewAlterRowPolicy(policy.Name, policy.DatabaseName, policy.TableName, policy.Condition), policy, clusterName)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return i.GetRowPolicy(ctx, policy.ID, clusterName)
+}
+
+func rowPolicyStatement(builder querybuilder.RowPolicyQueryBuilder, policy RowPolicy, clusterName *string) (string, error) {
+	builder.WithRestrictive(policy.Restrictive).WithCluster(clusterName)
+	if policy.ApplyToAll {
+		builder.WithApplyToAll(policy.ApplyTo)
+	} else {
+		builder.WithApplyTo(policy.ApplyTo)
+	}
+
+	return builder.Build()
+}
+
+// GetRowPolicy returns the row policy with the given ID, or nil if it does not exist.
+func (i *impl) GetRowPolicy(ctx context.Context, id string, clusterName *string) (*RowPolicy, error) {
+	return i.selectRowPolicy(ctx, clusterName, querybuilder.WhereEquals("id", querybuilder.NewParameter("id", "UUID", id)))
+}
+
+func (i *impl) FindRowPolicyByName(ctx context.Context, name, databaseName, tableName string, clusterName *string) (*RowPolicy, error) {
+	policy, err := i.findRowPolicy(ctx, name, databaseName, tableName, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	if policy == nil {
+		return nil, errors.New("row policy with such name not found")
+	}
+
+	return policy, nil
+}
+
+// findRowPolicy returns the row policy with the given name on the given table, or nil if it does not exist.
+func (i *impl) findRowPolicy(ctx context.Context, name, databaseName, tableName string, clusterName *string) (*RowPolicy, error) {
+	return i.selectRowPolicy(
+		ctx,
+		clusterName,
+		querybuilder.WhereEquals("short_name", querybuilder.NewParameter("short_name", "String", name)),
+		querybuilder.WhereEquals("database", querybuilder.NewParameter("database", "String", databaseName)),
+		querybuilder.WhereEquals("table", querybuilder.NewParameter("table", "String", tableName)),
+	)
+}
+
+func (i *impl) selectRowPolicy(ctx context.Context, clusterName *string, where ...querybuilder.Where) (*RowPolicy, error) {
+	query := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewExpressionField("toString(id)", "id"),
+			querybuilder.NewField("short_name"),
+			querybuilder.NewField("database"),
+			querybuilder.NewField("table"),
+			querybuilder.NewExpressionField("ifNull(select_filter, '')", "condition"),
+			querybuilder.NewExpressionField("toUInt8(is_restrictive)", "restrictive"),
+			querybuilder.NewExpressionField("toUInt8(apply_to_all)", "apply_to_all"),
+			// Arrays are joined with new lines, which can't be part of user nor role names.
+			querybuilder.NewExpressionField("arrayStringConcat(if(apply_to_all, apply_to_except, apply_to_list), '\\n')", "apply_to"),
+			querybuilder.NewField("storage"),
+		},
+		"system.row_policies",
+	).WithCluster(i.readCluster(clusterName)).Where(where...)
+	sql, err := query.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	var policy *RowPolicy
+
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
+		if policy != nil {
+			// With a cluster, every replica returns its own copy of the policy.
+			return nil
+		}
+
+		id, err := data.GetString("id")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'id' field")
+		}
+		n, err := data.GetString("short_name")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'short_name' field")
+		}
+		d, err := data.GetString("database")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'database' field")
+		}
+		t, err := data.GetString("table")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'table' field")
+		}
+		c, err := data.GetString("condition")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'condition' field")
+		}
+		restrictive, err := data.GetBool("restrictive")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'restrictive' field")
+		}
+		applyToAll, err := data.GetBool("apply_to_all")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'apply_to_all' field")
+		}
+		applyTo, err := data.GetString("apply_to")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'apply_to' field")
+		}
+		s, err := data.GetString("storage")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'storage' field")
+		}
+
+		policy = &RowPolicy{
+			ID:           id,
+			Name:         n,
+			DatabaseName: d,
+			TableName:    t,
+			Condition:    c,
+			Restrictive:  restrictive,
+			ApplyTo:      splitLines(applyTo),
+			ApplyToAll:   applyToAll,
+			Storage:      s,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return policy, nil
+}
+
+func (i *impl) DeleteRowPolicy(ctx context.Context, id string, clusterName *string) error {
+	policy, err := i.GetRowPolicy(ctx, id, clusterName)
+	if err != nil {
+		return errors.WithMessage(err, "error getting row policy")
+	}
+
+	if policy == nil {
+		// That's what we want.
+		return nil
+	}
+
+	sql, err := querybuilder.NewDropRowPolicy(policy.Name, policy.DatabaseName, policy.TableName).WithCluster(clusterName).Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
+	}
+
+	return nil
+}
@@ -0,0 +1,313 @@
+package dbops
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// SettingsProfile is a named set of settings and constraints that users and roles inherit.
+type SettingsProfile struct {
+	ID       string                   `json:"id"`
+	Name     string                   `json:"name"`
+	Settings []SettingsProfileSetting `json:"settings"`
+	// InheritProfiles lists the profiles this one inherits from, in the order they are applied.
+	InheritProfiles []string `json:"inherit_profiles"`
+	// ApplyTo lists the users and roles the profile applies to, or the ones it doesn't apply to when ApplyToAll is set.
+	ApplyTo    []string `json:"apply_to"`
+	ApplyToAll bool     `json:"apply_to_all"`
+	// Storage is the access storage holding the profile, see IsReadOnlyStorage.
+	Storage string `json:"storage"`
+}
+
+// SettingsProfileSetting is the value and constraints of a setting in a profile. Value, Min and Max are SQL literals
+// when written and the server representation of the value, without quotes, when read back.
+type SettingsProfileSetting struct {
+	Name     string  `json:"name"`
+	Value    *string `json:"value"`
+	Min      *string `json:"min"`
+	Max      *string `json:"max"`
+	Readonly bool    `json:"readonly"`
+}
+
+func (i *impl) CreateSettingsProfile(ctx context.Context, profile SettingsProfile, clusterName *string) (*SettingsProfile, error) {
+	sql, err := settingsProfileStatement(querybuilder.NewCreateSettingsProfile(profile.Name), profile, clusterName)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	created, err := readAfterCreate(ctx, i, clusterName, func(ctx context.Context) (*SettingsProfile, error) {
+		return i.findSettingsProfile(ctx, profile.Name, clusterName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if created == nil {
+		return nil, errors.New("settings profile with such name not found")
+	}
+
+	return created, nil
+}
+
+// UpdateSettingsProfile replaces the settings, inherited profiles and grantees of the profile with the ones of the
+// given profile.
+func (i *impl) UpdateSettingsProfile(ctx context.Context, profile SettingsProfile, clusterName *string) (*SettingsProfile, error) {
+	sql, err := settingsProfileStatement(querybuilder.NewAlterSettingsProfile(profile.Name), profile, clusterName)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return i.GetSettingsProfile(ctx, profile.ID, clusterName)
+}
+
+func settingsProfileStatement(builder querybuilder.SettingsProfileQueryBuilder, profile SettingsProfile, clusterName *string) (string, error) {
+	settings := make([]querybuilder.SettingsProfileSetting, 0, len(profile.Settings))
+	for _, setting := range profile.Settings {
+		settings = append(settings, querybuilder.SettingsProfileSetting{
+			Name:     setting.Name,
+			Value:    setting.Value,
+			Min:      setting.Min,
+			Max:      setting.Max,
+			Readonly: setting.Readonly,
+		})
+	}
+
+	builder.WithSettings(settings).WithInheritProfiles(profile.InheritProfiles).WithCluster(clusterName)
+	if profile.ApplyToAll {
+		builder.WithApplyToAll(profile.ApplyTo)
+	} else {
+		builder.WithApplyTo(profile.ApplyTo)
+	}
+
+	return builder.Build()
+}
+
+// GetSettingsProfile returns the settings profile with the given ID, or nil if it does not exist.
+func (i *impl) GetSettingsProfile(ctx context.Context, id string, clusterName *string) (*SettingsProfile, error) {
+	return i.selectSettingsProfile(ctx, clusterName, querybuilder.WhereEquals("id", querybuilder.NewParameter("id", "UUID", id)))
+}
+
+func (i *impl) FindSettingsProfileByName(ctx context.Context, name string, clusterName *string) (*SettingsProfile, error) {
+	profile, err := i.findSettingsProfile(ctx, name, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile == nil {
+		return nil, errors.New("settings profile with such name not found")
+	}
+
+	return profile, nil
+}
+
+// findSettingsProfile returns the settings profile with the given name, or nil if it does not exist.
+func (i *impl) findSettingsProfile(ctx context.Context, name string, clusterName *string) (*SettingsProfile, error) {
+	return i.selectSettingsProfile(ctx, clusterName, querybuilder.WhereEquals("name", querybuilder.NewParameter("name", "String", name)))
+}
+
+func (i *impl) selectSettingsProfile(ctx context.Context, clusterName *string, where ...querybuilder.Where) (*SettingsProfile, error) {
+	query := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewExpressionField("toString(id)", "id"),
+			querybuilder.NewField("name"),
+			querybuilder.NewExpressionField("toUInt8(apply_to_all)", "apply_to_all"),
+			// Arrays are joined with new lines, which can't be part of user nor role names.
+			querybuilder.NewExpressionField("arrayStringConcat(if(apply_to_all, apply_to_except, apply_to_list), '\\n')", "apply_to"),
+			querybuilder.NewField("storage"),
+		},
+		"system.settings_profiles",
+	).WithCluster(i.readCluster(clusterName)).Where(where...)
+	sql, err := query.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	var profile *SettingsProfile
+
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
+		if profile != nil {
+			// With a cluster, every replica returns its own copy of the profile.
+			return nil
+		}
+
+		id, err := data.GetString("id")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'id' field")
+		}
+		n, err := data.GetString("name")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'name' field")
+		}
+		applyToAll, err := data.GetBool("apply_to_all")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'apply_to_all' field")
+		}
+		applyTo, err := data.GetString("apply_to")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'apply_to' field")
+		}
+		s, err := data.GetString("storage")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'storage' field")
+		}
+
+		profile = &SettingsProfile{
+			ID:         id,
+			Name:       n,
+			ApplyTo:    splitLines(applyTo),
+			ApplyToAll: applyToAll,
+			Storage:    s,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	if profile == nil {
+		return nil, nil
+	}
+
+	err = i.readSettingsProfileElements(ctx, profile, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// readSettingsProfileElements fills the settings and inherited profiles of the profile from
+// system.settings_profile_elements.
+func (i *impl) readSettingsProfileElements(ctx context.Context, profile *SettingsProfile, clusterName *string) error {
+	// Newer servers replaced the readonly flag with a writability enum, CONST being the former readonly.
+	readonly := querybuilder.NewExpressionField("toUInt8(ifNull(readonly, 0))", "readonly")
+	ok, err := i.supports(ctx, featureSettingsWritability)
+	if err != nil {
+		return err
+	}
+	if ok {
+		readonly = querybuilder.NewExpressionField("toUInt8(ifNull(writability = 'CONST', 0))", "readonly")
+	}
+
+	query := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewField("index"),
+			querybuilder.NewField("setting_name"),
+			querybuilder.NewField("value"),
+			querybuilder.NewField("min"),
+			querybuilder.NewField("max"),
+			readonly,
+			querybuilder.NewField("inherit_profile"),
+		},
+		"system.settings_profile_elements",
+	).WithCluster(i.readCluster(clusterName)).
+		Where(querybuilder.WhereEquals("profile_name", querybuilder.NewParameter("profile_name", "String", profile.Name))).
+		OrderBy("index")
+	sql, err := query.Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
+	}
+
+	settings := make([]SettingsProfileSetting, 0)
+	inheritProfiles := make([]string, 0)
+	seen := make(map[uint64]bool)
+
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
+		index, err := data.GetUInt64("index")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'index' field")
+		}
+		// Reading from a cluster returns the elements once per shard.
+		if seen[index] {
+			return nil
+		}
+		seen[index] = true
+
+		inheritProfile, err := data.GetNullableString("inherit_profile")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'inherit_profile' field")
+		}
+		if inheritProfile != nil {
+			inheritProfiles = append(inheritProfiles, *inheritProfile)
+			return nil
+		}
+
+		settingName, err := data.GetNullableString("setting_name")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'setting_name' field")
+		}
+		if settingName == nil {
+			return nil
+		}
+		value, err := data.GetNullableString("value")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'value' field")
+		}
+		minValue, err := data.GetNullableString("min")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'min' field")
+		}
+		maxValue, err := data.GetNullableString("max")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'max' field")
+		}
+		ro, err := data.GetBool("readonly")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'readonly' field")
+		}
+
+		settings = append(settings, SettingsProfileSetting{
+			Name:     *settingName,
+			Value:    value,
+			Min:      minValue,
+			Max:      maxValue,
+			Readonly: ro,
+		})
+		return nil
+	})
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
+	}
+
+	profile.Settings = settings
+	profile.InheritProfiles = inheritProfiles
+
+	return nil
+}
+
+func (i *impl) DeleteSettingsProfile(ctx context.Context, id string, clusterName *string) error {
+	profile, err := i.GetSettingsProfile(ctx, id, clusterName)
+	if err != nil {
+		return errors.WithMessage(err, "error getting settings profile")
+	}
+
+	if profile == nil {
+		// That's what we want.
+		return nil
+	}
+
+	sql, err := querybuilder.NewDropSettingsProfile(profile.Name).WithCluster(clusterName).Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
+	}
+
+	return nil
+}
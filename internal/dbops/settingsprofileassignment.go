@@ -0,0 +1,201 @@
+package dbops
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+type SettingsProfileAssignment struct {
+	ProfileName     string  `json:"inherit_profile"`
+	GranteeUserName *string `json:"user_name"`
+	GranteeRoleName *string `json:"role_name"`
+}
+
+func (i *impl) AssignSettingsProfile(ctx context.Context, assignment SettingsProfileAssignment, clusterName *string) (*SettingsProfileAssignment, error) {
+	builder, err := newAlterGrantee(assignment.GranteeUserName, assignment.GranteeRoleName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := i.checkWritableGrantee(ctx, assignment.GranteeUserName, assignment.GranteeRoleName, clusterName); err != nil {
+		return nil, err
+	}
+
+	sql, err := builder.AddProfile(assignment.ProfileName).WithCluster(clusterName).Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return readAfterCreate(ctx, i, clusterName, func(ctx context.Context) (*SettingsProfileAssignment, error) {
+		return i.GetSettingsProfileAssignment(ctx, assignment.ProfileName, assignment.GranteeUserName, assignment.GranteeRoleName, clusterName)
+	})
+}
+
+// GetSettingsProfileAssignment returns the assignment of the profile to the given user or role, or nil if the
+// grantee does not inherit from the profile.
+func (i *impl) GetSettingsProfileAssignment(ctx context.Context, profileName string, granteeUserName *string, granteeRoleName *string, clusterName *string) (*SettingsProfileAssignment, error) {
+	var granteeWhere querybuilder.Where
+	{
+		if granteeUserName != nil {
+			granteeWhere = querybuilder.WhereEquals("user_name", *granteeUserName)
+		} else if granteeRoleName != nil {
+			granteeWhere = querybuilder.WhereEquals("role_name", *granteeRoleName)
+		} else {
+			return nil, errors.New("either GranteeUserName or GranteeRoleName must be set")
+		}
+	}
+
+	sql, err := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewField("user_name"),
+			querybuilder.NewField("role_name"),
+		},
+		"system.settings_profile_elements",
+	).WithCluster(i.readCluster(clusterName)).
+		Where(granteeWhere, querybuilder.WhereEquals("inherit_profile", profileName)).
+		Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	var assignment *SettingsProfileAssignment
+
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		userName, err := data.GetNullableString("user_name")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'user_name' field")
+		}
+		roleName, err := data.GetNullableString("role_name")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'role_name' field")
+		}
+
+		assignment = &SettingsProfileAssignment{
+			ProfileName:     profileName,
+			GranteeUserName: userName,
+			GranteeRoleName: roleName,
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return assignment, nil
+}
+
+func (i *impl) UnassignSettingsProfile(ctx context.Context, profileName string, granteeUserName *string, granteeRoleName *string, clusterName *string) error {
+	builder, err := newAlterGrantee(granteeUserName, granteeRoleName)
+	if err != nil {
+		return err
+	}
+
+	sql, err := builder.DropProfile(profileName).WithCluster(clusterName).Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
+	}
+
+	return nil
+}
+
+// newAlterGrantee returns an ALTER USER or ALTER ROLE query builder depending on which grantee is set.
+func newAlterGrantee(granteeUserName *string, granteeRoleName *string) (querybuilder.AlterGranteeQueryBuilder, error) {
+	if granteeUserName != nil {
+		return querybuilder.NewAlterUser(*granteeUserName), nil
+	} else if granteeRoleName != nil {
+		return querybuilder.NewAlterRole(*granteeRoleName), nil
+	}
+
+	return nil, errors.New("either GranteeUserName or GranteeRoleName must be set")
+}
+
+// SetSettingsProfiles replaces the settings of the given user or role with the profiles, in order: the settings of a
+// profile override the ones of the profiles before it. Any other setting or profile of the grantee is removed.
+func (i *impl) SetSettingsProfiles(ctx context.Context, profileNames []string, granteeUserName *string, granteeRoleName *string, clusterName *string) error {
+	builder, err := newAlterGrantee(granteeUserName, granteeRoleName)
+	if err != nil {
+		return err
+	}
+
+	if err := i.checkWritableGrantee(ctx, granteeUserName, granteeRoleName, clusterName); err != nil {
+		return err
+	}
+
+	sql, err := builder.SetProfiles(profileNames).WithCluster(clusterName).Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
+	}
+
+	return nil
+}
+
+// GetSettingsProfiles returns the profiles the given user or role inherits from, in the order they are applied.
+func (i *impl) GetSettingsProfiles(ctx context.Context, granteeUserName *string, granteeRoleName *string, clusterName *string) ([]string, error) {
+	var granteeWhere querybuilder.Where
+	{
+		if granteeUserName != nil {
+			granteeWhere = querybuilder.WhereEquals("user_name", *granteeUserName)
+		} else if granteeRoleName != nil {
+			granteeWhere = querybuilder.WhereEquals("role_name", *granteeRoleName)
+		} else {
+			return nil, errors.New("either GranteeUserName or GranteeRoleName must be set")
+		}
+	}
+
+	sql, err := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewField("inherit_profile"),
+			querybuilder.NewField("index"),
+		},
+		"system.settings_profile_elements",
+	).WithCluster(i.readCluster(clusterName)).
+		Where(granteeWhere, querybuilder.IsNotNull("inherit_profile")).
+		OrderBy("index").
+		Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	profileNames := make([]string, 0)
+	seen := make(map[string]bool)
+
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		profileName, err := data.GetNullableString("inherit_profile")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'inherit_profile' field")
+		}
+
+		// Reading from a cluster returns the elements once per shard.
+		if profileName != nil && !seen[*profileName] {
+			seen[*profileName] = true
+			profileNames = append(profileNames, *profileName)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return profileNames, nil
+}
@@ -0,0 +1,151 @@
+package dbops
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// ShardTarget restricts the statements of an operation to the replicas of one shard of a cluster.
+type ShardTarget struct {
+	ClusterName string
+	ShardNum    uint64
+}
+
+type shardTargetKey struct{}
+
+// WithShardTarget returns a context making the queries run with it go to the replicas of the given shard, resolved
+// from system.clusters, instead of the server the provider is connected to: statements run on every replica and
+// SELECT queries on the first one. Statements must not use ON CLUSTER, since each replica runs them itself.
+func WithShardTarget(ctx context.Context, target ShardTarget) context.Context {
+	return context.WithValue(ctx, shardTargetKey{}, &target)
+}
+
+func shardTargetFromContext(ctx context.Context) *ShardTarget {
+	target, _ := ctx.Value(shardTargetKey{}).(*ShardTarget)
+	return target
+}
+
+// shardTargetClient sends the queries run with a ShardTarget in their context to the replicas of the shard, through
+// connections made with hostClient, and the other ones to the wrapped client.
+type shardTargetClient struct {
+	clickhouseclient.ClickhouseClient
+	hostClient func(host string) (clickhouseclient.ClickhouseClient, error)
+
+	mu      sync.Mutex
+	clients map[string]clickhouseclient.ClickhouseClient
+}
+
+func newShardTargetClient(client clickhouseclient.ClickhouseClient, hostClient func(host string) (clickhouseclient.ClickhouseClient, error)) *shardTargetClient {
+	return &shardTargetClient{
+		ClickhouseClient: client,
+		hostClient:       hostClient,
+		clients:          make(map[string]clickhouseclient.ClickhouseClient),
+	}
+}
+
+func (c *shardTargetClient) Select(ctx context.Context, qry string, callback func(clickhouseclient.Row) error) error {
+	target := shardTargetFromContext(ctx)
+	if target == nil {
+		return c.ClickhouseClient.Select(ctx, qry, callback)
+	}
+
+	hosts, err := c.shardHosts(ctx, *target)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client(hosts[0])
+	if err != nil {
+		return err
+	}
+
+	return client.Select(ctx, qry, callback)
+}
+
+func (c *shardTargetClient) Exec(ctx context.Context, qry string) error {
+	target := shardTargetFromContext(ctx)
+	if target == nil {
+		return c.ClickhouseClient.Exec(ctx, qry)
+	}
+
+	hosts, err := c.shardHosts(ctx, *target)
+	if err != nil {
+		return err
+	}
+
+	for n, host := range hosts {
+		client, err := c.client(host)
+		if err != nil {
+			return err
+		}
+
+		err = client.Exec(ctx, qry)
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("error running query on replica %s (%d of %d replicas done)", host, n, len(hosts)))
+		}
+	}
+
+	return nil
+}
+
+// shardHosts returns the host names of the replicas of the shard, in the order of their replica number.
+func (c *shardTargetClient) shardHosts(ctx context.Context, target ShardTarget) ([]string, error) {
+	query := querybuilder.NewSelect(
+		[]querybuilder.Field{querybuilder.NewField("host_name")},
+		"system.clusters",
+	).Where(
+		querybuilder.WhereEquals("cluster", querybuilder.NewParameter("cluster", "String", target.ClusterName)),
+		querybuilder.WhereEquals("shard_num", target.ShardNum),
+	).OrderBy("replica_num")
+	sql, err := query.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	hosts := make([]string, 0)
+	err = c.ClickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
+		host, err := data.GetString("host_name")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'host_name' field")
+		}
+		hosts = append(hosts, host)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	if len(hosts) == 0 {
+		return nil, errors.New(fmt.Sprintf("cluster %q has no shard %d", target.ClusterName, target.ShardNum))
+	}
+
+	return hosts, nil
+}
+
+// client returns the connection to the given host, opening it on first use.
+func (c *shardTargetClient) client(host string) (clickhouseclient.ClickhouseClient, error) {
+	if c.hostClient == nil {
+		return nil, errors.New("connecting to the replicas of a shard is not supported with the current provider configuration")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if client, ok := c.clients[host]; ok {
+		return client, nil
+	}
+
+	client, err := c.hostClient(host)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("error connecting to replica %s", host))
+	}
+	c.clients[host] = client
+
+	return client, nil
+}
@@ -0,0 +1,80 @@
+package dbops
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+)
+
+// hostsClient answers every SELECT with the given host names and records the statements it runs.
+type hostsClient struct {
+	hosts []string
+	execs []string
+}
+
+func (c *hostsClient) Select(_ context.Context, _ string, callback func(clickhouseclient.Row) error) error {
+	for _, host := range c.hosts {
+		row := clickhouseclient.Row{}
+		row.Set("host_name", host)
+		if err := callback(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *hostsClient) Exec(_ context.Context, qry string) error {
+	c.execs = append(c.execs, qry)
+	return nil
+}
+
+func Test_shardTargetClient(t *testing.T) {
+	base := &hostsClient{hosts: []string{"replica-1", "replica-2"}}
+	replicas := make(map[string]*hostsClient)
+	client := newShardTargetClient(base, func(host string) (clickhouseclient.ClickhouseClient, error) {
+		replicas[host] = &hostsClient{}
+		return replicas[host], nil
+	})
+
+	err := client.Exec(context.Background(), "DROP TABLE a;")
+	if err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+	if !reflect.DeepEqual(base.execs, []string{"DROP TABLE a;"}) {
+		t.Errorf("untargeted Exec() ran %v on the connected server", base.execs)
+	}
+
+	ctx := WithShardTarget(context.Background(), ShardTarget{ClusterName: "cluster1", ShardNum: 2})
+	for _, qry := range []string{"DROP TABLE b;", "DROP TABLE c;"} {
+		err = client.Exec(ctx, qry)
+		if err != nil {
+			t.Fatalf("Exec() error = %v", err)
+		}
+	}
+	if len(base.execs) != 1 {
+		t.Errorf("targeted Exec() ran %v on the connected server", base.execs[1:])
+	}
+	if len(replicas) != 2 {
+		t.Fatalf("connected to %d replicas, want 2", len(replicas))
+	}
+	for host, replica := range replicas {
+		if !reflect.DeepEqual(replica.execs, []string{"DROP TABLE b;", "DROP TABLE c;"}) {
+			t.Errorf("replica %s ran %v", host, replica.execs)
+		}
+	}
+}
+
+func Test_shardTargetClient_unknownShard(t *testing.T) {
+	client := newShardTargetClient(&hostsClient{}, func(host string) (clickhouseclient.ClickhouseClient, error) {
+		return &hostsClient{}, nil
+	})
+
+	ctx := WithShardTarget(context.Background(), ShardTarget{ClusterName: "cluster1", ShardNum: 3})
+	err := client.Exec(ctx, "DROP TABLE a;")
+	if err == nil || !strings.Contains(err.Error(), `cluster "cluster1" has no shard 3`) {
+		t.Errorf("Exec() error = %v, want a missing shard error", err)
+	}
+}
@@ -2,6 +2,8 @@ package dbops
 
 import (
 	"context"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/pingcap/errors"
@@ -11,27 +13,96 @@ import (
 )
 
 type Table struct {
-	UUID         string                     `json:"uuid"`
-	DatabaseName string                     `json:"database_name"`
-	Name         string                     `json:"name"`
-	Engine       string                     `json:"engine"`
-	Columns      []querybuilder.TableColumn `json:"columns"`
-	OrderBy      []string                   `json:"order_by"`
-	PartitionBy  *string                    `json:"partition_by,omitempty"`
-	PrimaryKey   []string                   `json:"primary_key,omitempty"`
-	SampleBy     *string                    `json:"sample_by,omitempty"`
-	TTL          *string                    `json:"ttl,omitempty"`
-	Settings     map[string]string          `json:"settings,omitempty"`
-	Comment      string                     `json:"comment"`
+	UUID         string `json:"uuid"`
+	DatabaseName string `json:"database_name"`
+	Name         string `json:"name"`
+	Engine       string `json:"engine"`
+	// SourceFunction is set for tables created AS a table function, Engine is then the storage backing it.
+	SourceFunction *querybuilder.TableFunction    `json:"source_function,omitempty"`
+	Columns        []querybuilder.TableColumn     `json:"columns"`
+	Indexes        []querybuilder.TableIndex      `json:"indexes,omitempty"`
+	Projections    []querybuilder.TableProjection `json:"projections,omitempty"`
+	OrderBy        []string                       `json:"order_by"`
+	PartitionBy    *string                        `json:"partition_by,omitempty"`
+	PrimaryKey     []string                       `json:"primary_key,omitempty"`
+	SampleBy       *string                        `json:"sample_by,omitempty"`
+	TTL            *string                        `json:"ttl,omitempty"`
+	Settings       map[string]string              `json:"settings,omitempty"`
+	Comment        string                         `json:"comment"`
+	// Unsupported lists the parts of the definition the table resource can't model, e.g. constraints or column TTLs.
+	Unsupported []string `json:"-"`
+	// CreateQuery is the CREATE statement of the table as reported by ClickHouse.
+	CreateQuery string `json:"-"`
 }
 
 func (i *impl) CreateTable(ctx context.Context, table Table, clusterName *string) (*Table, error) {
+	if err := i.CheckDatabaseManageable(table.DatabaseName); err != nil {
+		return nil, err
+	}
+
+	if table.Comment != "" {
+		if err := i.requires(ctx, featureTableComment); err != nil {
+			return nil, err
+		}
+	}
+
+	sql, err := CreateTableStatement(table, clusterName)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	settings, err := i.columnTypeSettings(ctx, table.Columns)
+	if err != nil {
+		return nil, err
+	}
+	ctx = clickhouseclient.WithSettings(ctx, settings)
+
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return nil, err
+	}
+
+	err = i.execWithRetry(ctx, sql, func(ctx context.Context) (bool, error) {
+		t, err := i.findTable(ctx, table.DatabaseName, table.Name, clusterName)
+		return t != nil, err
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	invalidateTableCache(ctx)
+
+	created, err := readAfterCreate(ctx, i, clusterName, func(ctx context.Context) (*Table, error) {
+		return i.GetTableByName(ctx, table.DatabaseName, table.Name, clusterName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if created == nil {
+		return nil, errors.New("table with such name not found")
+	}
+
+	return created, nil
+}
+
+// CreateTableStatement returns the CREATE TABLE statement CreateTable runs for the given table.
+func CreateTableStatement(table Table, clusterName *string) (string, error) {
 	builder := querybuilder.NewCreateTable(table.DatabaseName, table.Name, table.Columns).
 		WithCluster(clusterName).
+		WithUUID(table.UUID).
 		WithEngine(table.Engine).
 		WithOrderBy(table.OrderBy).
 		WithComment(table.Comment)
 
+	if table.SourceFunction != nil {
+		builder = builder.WithSourceFunction(*table.SourceFunction)
+	}
+	if len(table.Indexes) > 0 {
+		builder = builder.WithIndexes(table.Indexes)
+	}
+	if len(table.Projections) > 0 {
+		builder = builder.WithProjections(table.Projections)
+	}
+
 	if table.PartitionBy != nil {
 		builder = builder.WithPartitionBy(*table.PartitionBy)
 	}
@@ -48,23 +119,102 @@ func (i *impl) CreateTable(ctx context.Context, table Table, clusterName *string
 		builder = builder.WithSettings(table.Settings)
 	}
 
-	sql, err := builder.Build()
+	return builder.Build()
+}
+
+// GetTable returns the table with the given UUID, or nil if it does not exist.
+// Concurrent calls are coalesced into a single GetTables query, so refreshing many tables at once costs one query
+// per batch instead of one per table. If ctx carries a cache (see WithTableCache) the result is served from it.
+func (i *impl) GetTable(ctx context.Context, uuid string, clusterName *string) (*Table, error) {
+	cache := tableCacheFromContext(ctx)
+	if cache != nil {
+		if table, ok := cache.get(uuid, clusterName); ok {
+			return table, nil
+		}
+	}
+
+	var table *Table
+	if shardTargetFromContext(ctx) != nil {
+		// Batches are shared between operations, which may not target the same shard.
+		tables, err := i.GetTables(ctx, []string{uuid}, clusterName)
+		if err != nil {
+			return nil, err
+		}
+		table = tables[uuid]
+	} else {
+		var err error
+		table, err = i.tableBatcher.get(ctx, uuid, clusterName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cache != nil {
+		cache.set(uuid, clusterName, table)
+	}
+
+	return table, nil
+}
+
+// GetTables returns the tables with the given UUIDs keyed by UUID, using a single query that joins system.tables with
+// system.columns regardless of the number of tables. Tables that do not exist are missing from the returned map.
+func (i *impl) GetTables(ctx context.Context, uuids []string, clusterName *string) (map[string]*Table, error) {
+	if len(uuids) == 0 {
+		return make(map[string]*Table), nil
+	}
+
+	return i.selectTables(ctx, clusterName, querybuilder.WhereInParameter("uuid", querybuilder.NewArrayParameter("uuids", "UUID", uuids)))
+}
+
+// selectTables returns the system.tables rows matching where, along with their columns, keyed by UUID.
+func (i *impl) selectTables(ctx context.Context, clusterName *string, where ...querybuilder.Where) (map[string]*Table, error) {
+	tables := make(map[string]*Table)
+
+	commentField := querybuilder.NewField("comment")
+	if ok, err := i.supports(ctx, featureTableComment); err != nil {
+		return nil, err
+	} else if !ok {
+		commentField = querybuilder.NewExpressionField("''", "comment")
+	}
+
+	// Only read the columns of the requested tables.
+	inDatabases, err := querybuilder.WhereInSelect("database", querybuilder.NewSelect(
+		[]querybuilder.Field{querybuilder.NewField("database")},
+		"system.tables",
+	).WithCluster(i.readCluster(clusterName)).Where(where...))
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
-
-	err = i.clickhouseClient.Exec(ctx, sql)
+	inTables, err := querybuilder.WhereInSelect("table", querybuilder.NewSelect(
+		[]querybuilder.Field{querybuilder.NewField("name")},
+		"system.tables",
+	).WithCluster(i.readCluster(clusterName)).Where(where...))
 	if err != nil {
-		return nil, errors.WithMessage(err, "error running query")
+		return nil, errors.WithMessage(err, "error building query")
 	}
 
-	return i.FindTableByName(ctx, table.DatabaseName, table.Name, clusterName)
-}
+	// When querying a cluster, every replica returns the columns of its tables: keep a single row per column so that
+	// the join doesn't multiply the columns of each replica by the number of replicas.
+	columns := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewAliasedField("database", "column_database"),
+			querybuilder.NewAliasedField("table", "column_table"),
+			querybuilder.NewAliasedField("name", "column_name"),
+			querybuilder.NewExpressionField("any(type)", "column_type"),
+			querybuilder.NewExpressionField("any(default_kind)", "column_default_kind"),
+			querybuilder.NewExpressionField("any(default_expression)", "column_default_expression"),
+			querybuilder.NewExpressionField("any(comment)", "column_comment"),
+			querybuilder.NewExpressionField("any(compression_codec)", "column_compression_codec"),
+			querybuilder.NewExpressionField("any(position)", "column_position"),
+		},
+		"system.columns",
+	).WithCluster(i.readCluster(clusterName)).
+		Where(inDatabases, inTables).
+		GroupBy("column_database", "column_table", "column_name")
 
-func (i *impl) GetTable(ctx context.Context, uuid string, clusterName *string) (*Table, error) {
-	// First get basic table info
-	sql, err := querybuilder.NewSelect(
+	query := querybuilder.NewSelect(
 		[]querybuilder.Field{
+			querybuilder.NewField("uuid"),
 			querybuilder.NewField("database"),
 			querybuilder.NewField("name"),
 			querybuilder.NewField("engine"),
@@ -72,161 +222,219 @@ func (i *impl) GetTable(ctx context.Context, uuid string, clusterName *string) (
 			querybuilder.NewField("sorting_key"),
 			querybuilder.NewField("primary_key"),
 			querybuilder.NewField("sampling_key"),
-			querybuilder.NewField("engine_full"),
-			querybuilder.NewField("comment"),
+			querybuilder.NewField("create_table_query"),
+			commentField,
+			querybuilder.NewField("column_name"),
+			querybuilder.NewField("column_type"),
+			querybuilder.NewField("column_default_kind"),
+			querybuilder.NewField("column_default_expression"),
+			querybuilder.NewField("column_comment"),
+			querybuilder.NewField("column_compression_codec"),
 		},
 		"system.tables",
-	).WithCluster(clusterName).Where(querybuilder.WhereEquals("uuid", uuid)).Build()
+	).WithCluster(i.readCluster(clusterName)).
+		LeftJoinOn(columns,
+			querybuilder.JoinCondition{Left: "database", Right: "column_database"},
+			querybuilder.JoinCondition{Left: "name", Right: "column_table"},
+		).
+		Where(where...).
+		OrderBy("column_position")
+
+	sql, err := query.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
-	var table *Table
-
-	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
-		dbName, err := data.GetString("database")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'database' field")
-		}
-		name, err := data.GetString("name")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'name' field")
-		}
-		engine, err := data.GetString("engine")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'engine' field")
-		}
-		partitionKey, err := data.GetString("partition_key")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'partition_key' field")
-		}
-		sortingKey, err := data.GetString("sorting_key")
+	// Each row holds one column of a table, along with the table's own fields.
+	seenColumns := make(map[string]map[string]bool)
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
+		uuid, err := data.GetString("uuid")
 		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'sorting_key' field")
-		}
-		primaryKey, err := data.GetString("primary_key")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'primary_key' field")
-		}
-		samplingKey, err := data.GetString("sampling_key")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'sampling_key' field")
-		}
-		engineFull, err := data.GetString("engine_full")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'engine_full' field")
-		}
-		comment, err := data.GetString("comment")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'comment' field")
-		}
-
-		table = &Table{
-			UUID:         uuid,
-			DatabaseName: dbName,
-			Name:         name,
-			Engine:       engine,
-			Comment:      comment,
-		}
-
-		// Parse order by from sorting_key
-		if sortingKey != "" {
-			table.OrderBy = parseKeyColumns(sortingKey)
+			return errors.WithMessage(err, "error scanning query result, missing 'uuid' field")
 		}
 
-		// Parse partition by
-		if partitionKey != "" {
-			table.PartitionBy = &partitionKey
+		table, ok := tables[uuid]
+		if !ok {
+			table, err = tableFromRow(data)
+			if err != nil {
+				return err
+			}
+			tables[uuid] = table
 		}
 
-		// Parse primary key
-		if primaryKey != "" {
-			table.PrimaryKey = parseKeyColumns(primaryKey)
+		col, err := columnFromRow(data)
+		if err != nil {
+			return err
 		}
 
-		// Parse sample by
-		if samplingKey != "" {
-			table.SampleBy = &samplingKey
+		// No column matched the table in the join.
+		if col.Name == "" {
+			return nil
 		}
 
-		// Parse TTL and settings from engine_full
-		ttl, settings := parseEngineFullForTTLAndSettings(engineFull)
-		if ttl != "" {
-			table.TTL = &ttl
+		// When querying a cluster, the table and so its columns are returned once per replica.
+		if seenColumns[uuid] == nil {
+			seenColumns[uuid] = make(map[string]bool)
 		}
-		if len(settings) > 0 {
-			table.Settings = settings
+		if seenColumns[uuid][col.Name] {
+			return nil
 		}
+		seenColumns[uuid][col.Name] = true
 
+		table.Columns = append(table.Columns, *col)
 		return nil
 	})
 	if err != nil {
 		return nil, errors.WithMessage(err, "error running query")
 	}
 
-	if table == nil {
-		// Table not found
-		return nil, nil
+	return tables, nil
+}
+
+// tableFromRow builds a Table (without columns) from a system.tables row.
+func tableFromRow(data clickhouseclient.Row) (*Table, error) {
+	uuid, err := data.GetString("uuid")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'uuid' field")
+	}
+	dbName, err := data.GetString("database")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'database' field")
+	}
+	name, err := data.GetString("name")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'name' field")
+	}
+	engine, err := data.GetString("engine")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'engine' field")
+	}
+	partitionKey, err := data.GetString("partition_key")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'partition_key' field")
+	}
+	sortingKey, err := data.GetString("sorting_key")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'sorting_key' field")
+	}
+	primaryKey, err := data.GetString("primary_key")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'primary_key' field")
+	}
+	samplingKey, err := data.GetString("sampling_key")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'sampling_key' field")
+	}
+	createTableQuery, err := data.GetString("create_table_query")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'create_table_query' field")
+	}
+	comment, err := data.GetString("comment")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'comment' field")
 	}
 
-	// Get column information
-	columnsSql, err := querybuilder.NewSelect(
-		[]querybuilder.Field{
-			querybuilder.NewField("name"),
-			querybuilder.NewField("type"),
-			querybuilder.NewField("default_expression"),
-			querybuilder.NewField("comment"),
-		},
-		"system.columns",
-	).WithCluster(clusterName).
-		Where(
-			querybuilder.WhereEquals("database", table.DatabaseName),
-			querybuilder.WhereEquals("table", table.Name),
-		).
-		Build()
+	table := &Table{
+		UUID:         uuid,
+		DatabaseName: dbName,
+		Name:         name,
+		Engine:       engine,
+		Comment:      comment,
+	}
+
+	// Parse order by from sorting_key
+	if sortingKey != "" {
+		table.OrderBy = parseExpressionList(sortingKey)
+	}
+
+	// Parse partition by
+	if partitionKey != "" {
+		table.PartitionBy = &partitionKey
+	}
+
+	// Parse primary key
+	if primaryKey != "" {
+		table.PrimaryKey = parseExpressionList(primaryKey)
+	}
+
+	// Parse sample by
+	if samplingKey != "" {
+		table.SampleBy = &samplingKey
+	}
+
+	// Parse engine parameters, TTL and settings from the full CREATE statement
+	table.CreateQuery = createTableQuery
+	parsed, err := ParseCreateTableQuery(createTableQuery)
 	if err != nil {
-		return nil, errors.WithMessage(err, "error building columns query")
+		// Keep what system.tables reports, the table can still be adopted read-only.
+		table.Unsupported = []string{"definition that cannot be parsed: " + err.Error()}
+		return table, nil
+	}
+	if parsed.Engine != "" {
+		// system.tables.engine is the bare engine name, the statement has its parameters too.
+		table.Engine = parsed.Engine
+	}
+	table.TTL = parsed.TTL
+	table.SourceFunction = parsed.SourceFunction
+	table.Indexes = parsed.Indexes
+	table.Projections = parsed.Projections
+	table.Unsupported = parsed.Unsupported
+	if len(parsed.Settings) > 0 {
+		table.Settings = parsed.Settings
 	}
 
-	var columns []querybuilder.TableColumn
-	err = i.clickhouseClient.Select(ctx, columnsSql, func(data clickhouseclient.Row) error {
-		name, err := data.GetString("name")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning column result, missing 'name' field")
-		}
-		colType, err := data.GetString("type")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning column result, missing 'type' field")
-		}
-		defaultExpr, err := data.GetString("default_expression")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning column result, missing 'default_expression' field")
-		}
-		comment, err := data.GetString("comment")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning column result, missing 'comment' field")
-		}
+	return table, nil
+}
 
-		col := querybuilder.TableColumn{
-			Name: name,
-			Type: colType,
-		}
-		if defaultExpr != "" {
-			col.Default = &defaultExpr
-		}
-		if comment != "" {
-			col.Comment = &comment
-		}
-		columns = append(columns, col)
-		return nil
-	})
+// columnFromRow builds a TableColumn from the system.columns fields of a joined row.
+func columnFromRow(data clickhouseclient.Row) (*querybuilder.TableColumn, error) {
+	name, err := data.GetString("column_name")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning column result, missing 'column_name' field")
+	}
+	colType, err := data.GetString("column_type")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning column result, missing 'column_type' field")
+	}
+	defaultKind, err := data.GetString("column_default_kind")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning column result, missing 'column_default_kind' field")
+	}
+	defaultExpr, err := data.GetString("column_default_expression")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning column result, missing 'column_default_expression' field")
+	}
+	comment, err := data.GetString("column_comment")
 	if err != nil {
-		return nil, errors.WithMessage(err, "error querying columns")
+		return nil, errors.WithMessage(err, "error scanning column result, missing 'column_comment' field")
 	}
 
-	table.Columns = columns
+	codec, err := data.GetString("column_compression_codec")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning column result, missing 'column_compression_codec' field")
+	}
 
-	return table, nil
+	col := &querybuilder.TableColumn{
+		Name: name,
+		Type: colType,
+	}
+	if defaultExpr != "" {
+		col.Default = &defaultExpr
+	}
+	// Like in the CREATE statement, DEFAULT is the kind of the columns without one.
+	if defaultKind != "" && defaultKind != querybuilder.ColumnDefaultKindDefault {
+		col.DefaultKind = &defaultKind
+	}
+	if comment != "" {
+		col.Comment = &comment
+	}
+	if codec != "" {
+		// system.columns reports the chain wrapped in CODEC(), e.g. CODEC(Delta(4), ZSTD(1)).
+		codec = strings.TrimSuffix(strings.TrimPrefix(codec, "CODEC("), ")")
+		col.Codec = &codec
+	}
+
+	return col, nil
 }
 
 func (i *impl) DeleteTable(ctx context.Context, uuid string, clusterName *string) error {
@@ -240,107 +448,112 @@ func (i *impl) DeleteTable(ctx context.Context, uuid string, clusterName *string
 		return nil
 	}
 
+	if err := i.CheckDatabaseManageable(table.DatabaseName); err != nil {
+		return err
+	}
+
 	sql, err := querybuilder.NewDropTable(table.DatabaseName, table.Name).WithCluster(clusterName).Build()
 	if err != nil {
 		return errors.WithMessage(err, "error building query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, sql)
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return err
+	}
+
+	err = i.execWithRetry(ctx, sql, func(ctx context.Context) (bool, error) {
+		t, err := i.GetTable(ctx, uuid, clusterName)
+		return t == nil, err
+	})
 	if err != nil {
 		return errors.WithMessage(err, "error running query")
 	}
 
+	invalidateTableCache(ctx)
+
 	return nil
 }
 
 func (i *impl) FindTableByName(ctx context.Context, databaseName, tableName string, clusterName *string) (*Table, error) {
-	sql, err := querybuilder.NewSelect(
-		[]querybuilder.Field{querybuilder.NewField("uuid")},
-		"system.tables",
-	).WithCluster(clusterName).
-		Where(
-			querybuilder.WhereEquals("database", databaseName),
-			querybuilder.WhereEquals("name", tableName),
-		).
-		Build()
+	table, err := i.GetTableByName(ctx, databaseName, tableName, clusterName)
 	if err != nil {
-		return nil, errors.WithMessage(err, "error building query")
+		return nil, err
 	}
 
-	var uuid string
+	if table == nil {
+		return nil, errors.New("table with such name not found")
+	}
 
-	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
-		uuid, err = data.GetString("uuid")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'uuid' field")
-		}
+	return table, nil
+}
 
-		return nil
-	})
+// GetTableByName returns the table with the given name, or nil if it does not exist.
+func (i *impl) GetTableByName(ctx context.Context, databaseName, tableName string, clusterName *string) (*Table, error) {
+	table, err := i.findTable(ctx, databaseName, tableName, clusterName)
 	if err != nil {
-		return nil, errors.WithMessage(err, "error running query")
+		return nil, err
 	}
 
-	if uuid == "" {
-		return nil, errors.New("table with such name not found")
+	if table != nil {
+		if cache := tableCacheFromContext(ctx); cache != nil {
+			cache.set(table.UUID, clusterName, table)
+		}
 	}
 
-	return i.GetTable(ctx, uuid, clusterName)
+	return table, nil
 }
 
-// parseKeyColumns parses a comma-separated list of columns (possibly with spaces)
-func parseKeyColumns(key string) []string {
-	if key == "" {
-		return nil
+// findTable returns the table with the given name, or nil if it does not exist.
+func (i *impl) findTable(ctx context.Context, databaseName, tableName string, clusterName *string) (*Table, error) {
+	tables, err := i.selectTables(
+		ctx,
+		clusterName,
+		querybuilder.WhereEquals("database", querybuilder.NewParameter("database", "String", databaseName)),
+		querybuilder.WhereEquals("name", querybuilder.NewParameter("name", "String", tableName)),
+	)
+	if err != nil {
+		return nil, err
 	}
-	parts := strings.Split(key, ",")
-	result := make([]string, 0, len(parts))
-	for _, part := range parts {
-		trimmed := strings.TrimSpace(part)
-		if trimmed != "" {
-			result = append(result, trimmed)
-		}
+
+	for _, table := range tables {
+		return table, nil
 	}
-	return result
+
+	return nil, nil
 }
 
-// parseEngineFullForTTLAndSettings attempts to extract TTL and SETTINGS from engine_full string
-// This is a simplified parser and may need to be enhanced for complex cases
-func parseEngineFullForTTLAndSettings(engineFull string) (string, map[string]string) {
-	ttl := ""
-	settings := make(map[string]string)
-
-	// Look for TTL
-	if idx := strings.Index(engineFull, "TTL "); idx != -1 {
-		ttlStart := idx + 4
-		// Find the end of TTL expression (before SETTINGS or end of string)
-		ttlEnd := strings.Index(engineFull[ttlStart:], " SETTINGS")
-		if ttlEnd == -1 {
-			ttl = strings.TrimSpace(engineFull[ttlStart:])
-		} else {
-			ttl = strings.TrimSpace(engineFull[ttlStart : ttlStart+ttlEnd])
-		}
+// nonTableEngines are the engines of system.tables entries that are not tables, and cannot be parsed as such.
+var nonTableEngines = []string{"View", "MaterializedView", "LiveView", "WindowView", "Dictionary"}
+
+// ListTables returns every table of the given database along with its columns, sorted by name.
+// Views and dictionaries are left out.
+func (i *impl) ListTables(ctx context.Context, databaseName string, clusterName *string) ([]*Table, error) {
+	where := []querybuilder.Where{querybuilder.WhereEquals("database", querybuilder.NewParameter("database", "String", databaseName))}
+	for _, engine := range nonTableEngines {
+		where = append(where, querybuilder.WhereDiffers("engine", engine))
 	}
 
-	// Look for SETTINGS
-	if idx := strings.Index(engineFull, "SETTINGS "); idx != -1 {
-		settingsStr := engineFull[idx+9:]
-		// Parse settings (simplified - assumes key = value format)
-		pairs := strings.Split(settingsStr, ",")
-		for _, pair := range pairs {
-			parts := strings.Split(strings.TrimSpace(pair), "=")
-			if len(parts) == 2 {
-				key := strings.TrimSpace(parts[0])
-				value := strings.TrimSpace(parts[1])
-				settings[key] = value
-			}
-		}
+	tables, err := i.selectTables(ctx, clusterName, where...)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*Table, 0, len(tables))
+	for _, table := range tables {
+		ret = append(ret, table)
 	}
+	slices.SortFunc(ret, func(a, b *Table) int {
+		return strings.Compare(a.Name, b.Name)
+	})
 
-	return ttl, settings
+	return ret, nil
 }
 
 func (i *impl) AddTableColumns(ctx context.Context, databaseName, tableName string, columns []querybuilder.TableColumn, clusterName *string) error {
+	if err := i.CheckDatabaseManageable(databaseName); err != nil {
+		return err
+	}
+
 	query, err := querybuilder.NewAlterTableAddColumn(databaseName, tableName, columns).
 		WithCluster(clusterName).
 		Build()
@@ -348,15 +561,146 @@ func (i *impl) AddTableColumns(ctx context.Context, databaseName, tableName stri
 		return errors.WithMessage(err, "error building ALTER TABLE ADD COLUMN query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, query)
+	settings, err := i.columnTypeSettings(ctx, columns)
+	if err != nil {
+		return err
+	}
+	ctx = clickhouseclient.WithSettings(ctx, settings)
+
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return err
+	}
+
+	err = i.execWithRetry(ctx, query, func(ctx context.Context) (bool, error) {
+		names := make([]string, 0, len(columns))
+		for _, col := range columns {
+			names = append(names, col.Name)
+		}
+		present, err := i.tableColumnsPresent(ctx, databaseName, tableName, names, clusterName)
+		return present == len(names), err
+	})
 	if err != nil {
 		return errors.WithMessage(err, "error adding columns to table")
 	}
 
+	invalidateTableCache(ctx)
+
+	return nil
+}
+
+// ModifyTableColumns changes the type of existing columns of the table with ALTER TABLE MODIFY COLUMN, which
+// converts the data already stored.
+func (i *impl) ModifyTableColumns(ctx context.Context, databaseName, tableName string, columns []querybuilder.TableColumn, clusterName *string) error {
+	if err := i.CheckDatabaseManageable(databaseName); err != nil {
+		return err
+	}
+
+	query, err := querybuilder.NewAlterTableModifyColumn(databaseName, tableName, columns).
+		WithCluster(clusterName).
+		Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building ALTER TABLE MODIFY COLUMN query")
+	}
+
+	settings, err := i.columnTypeSettings(ctx, columns)
+	if err != nil {
+		return err
+	}
+	ctx = clickhouseclient.WithSettings(ctx, settings)
+
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return err
+	}
+
+	// Running MODIFY COLUMN again is harmless once the type has changed.
+	err = i.execWithRetry(ctx, query, nil)
+	if err != nil {
+		return errors.WithMessage(err, "error modifying columns of table")
+	}
+
+	invalidateTableCache(ctx)
+
+	return nil
+}
+
+// ModifyTableTTL changes the TTL of the table, or removes it when ttl is nil. Rows already written are only moved or
+// deleted by the new TTL once it is materialized, which ClickHouse does in the background by default.
+func (i *impl) ModifyTableTTL(ctx context.Context, databaseName, tableName string, ttl *string, clusterName *string) error {
+	if err := i.CheckDatabaseManageable(databaseName); err != nil {
+		return err
+	}
+
+	query, err := querybuilder.NewAlterTableModifyTTL(databaseName, tableName, ttl).
+		WithCluster(clusterName).
+		Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building ALTER TABLE MODIFY TTL query")
+	}
+
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return err
+	}
+
+	// Setting the same TTL again is harmless.
+	err = i.execWithRetry(ctx, query, nil)
+	if err != nil {
+		return errors.WithMessage(err, "error modifying TTL of table")
+	}
+
+	invalidateTableCache(ctx)
+
+	return nil
+}
+
+// ModifyTableSettings sets the given settings of the table with MODIFY SETTING and sets the reset ones back to
+// their default value with RESET SETTING.
+func (i *impl) ModifyTableSettings(ctx context.Context, databaseName, tableName string, settings map[string]string, reset []string, clusterName *string) error {
+	if err := i.CheckDatabaseManageable(databaseName); err != nil {
+		return err
+	}
+
+	var queries []string
+	if len(settings) > 0 {
+		query, err := querybuilder.NewAlterTableModifySetting(databaseName, tableName, settings).
+			WithCluster(clusterName).
+			Build()
+		if err != nil {
+			return errors.WithMessage(err, "error building ALTER TABLE MODIFY SETTING query")
+		}
+		queries = append(queries, query)
+	}
+	if len(reset) > 0 {
+		query, err := querybuilder.NewAlterTableResetSetting(databaseName, tableName, reset).
+			WithCluster(clusterName).
+			Build()
+		if err != nil {
+			return errors.WithMessage(err, "error building ALTER TABLE RESET SETTING query")
+		}
+		queries = append(queries, query)
+	}
+
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return err
+	}
+
+	for _, query := range queries {
+		// Setting or resetting the same settings again is harmless.
+		err := i.execWithRetry(ctx, query, nil)
+		if err != nil {
+			return errors.WithMessage(err, "error modifying settings of table")
+		}
+	}
+
+	invalidateTableCache(ctx)
+
 	return nil
 }
 
 func (i *impl) DropTableColumns(ctx context.Context, databaseName, tableName string, columnNames []string, clusterName *string) error {
+	if err := i.CheckDatabaseManageable(databaseName); err != nil {
+		return err
+	}
+
 	query, err := querybuilder.NewAlterTableDropColumn(databaseName, tableName, columnNames).
 		WithCluster(clusterName).
 		Build()
@@ -364,10 +708,94 @@ func (i *impl) DropTableColumns(ctx context.Context, databaseName, tableName str
 		return errors.WithMessage(err, "error building ALTER TABLE DROP COLUMN query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, query)
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return err
+	}
+
+	err = i.execWithRetry(ctx, query, func(ctx context.Context) (bool, error) {
+		present, err := i.tableColumnsPresent(ctx, databaseName, tableName, columnNames, clusterName)
+		return present == 0, err
+	})
 	if err != nil {
 		return errors.WithMessage(err, "error dropping columns from table")
 	}
 
+	invalidateTableCache(ctx)
+
+	return nil
+}
+
+// RenameTableColumns renames each column of renames to the name it maps to.
+func (i *impl) RenameTableColumns(ctx context.Context, databaseName, tableName string, renames map[string]string, clusterName *string) error {
+	if err := i.CheckDatabaseManageable(databaseName); err != nil {
+		return err
+	}
+
+	query, err := querybuilder.NewAlterTableRenameColumn(databaseName, tableName, renames).
+		WithCluster(clusterName).
+		Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building ALTER TABLE RENAME COLUMN query")
+	}
+
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return err
+	}
+
+	err = i.execWithRetry(ctx, query, func(ctx context.Context) (bool, error) {
+		present, err := i.tableColumnsPresent(ctx, databaseName, tableName, slices.Collect(maps.Keys(renames)), clusterName)
+		return present == 0, err
+	})
+	if err != nil {
+		return errors.WithMessage(err, "error renaming columns of table")
+	}
+
+	invalidateTableCache(ctx)
+
 	return nil
 }
+
+// RemoveTableColumnCodecs makes the columns use the default compression of the server again.
+func (i *impl) RemoveTableColumnCodecs(ctx context.Context, databaseName, tableName string, columnNames []string, clusterName *string) error {
+	if err := i.CheckDatabaseManageable(databaseName); err != nil {
+		return err
+	}
+
+	query, err := querybuilder.NewAlterTableRemoveCodec(databaseName, tableName, columnNames).
+		WithCluster(clusterName).
+		Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building ALTER TABLE REMOVE CODEC query")
+	}
+
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return err
+	}
+
+	// Removing the codec of a column without one is harmless.
+	err = i.execWithRetry(ctx, query, nil)
+	if err != nil {
+		return errors.WithMessage(err, "error removing codecs of table columns")
+	}
+
+	invalidateTableCache(ctx)
+
+	return nil
+}
+
+// tableColumnsPresent returns how many of the given columns the table has.
+func (i *impl) tableColumnsPresent(ctx context.Context, databaseName, tableName string, columnNames []string, clusterName *string) (int, error) {
+	table, err := i.findTable(ctx, databaseName, tableName, clusterName)
+	if err != nil || table == nil {
+		return 0, err
+	}
+
+	present := 0
+	for _, col := range table.Columns {
+		if slices.Contains(columnNames, col.Name) {
+			present++
+		}
+	}
+
+	return present, nil
+}
@@ -0,0 +1,110 @@
+package dbops
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+)
+
+// tableBatcher coalesces concurrent single-table reads into one GetTables call. A read runs right away when no other
+// read of the same cluster is in flight, and otherwise joins the batch run as soon as the in-flight one returns, so
+// reads are only grouped when they would have waited anyway.
+// It is safe for concurrent use. Tables returned to callers waiting on the same batch are shared and must not be modified.
+type tableBatcher struct {
+	load func(ctx context.Context, uuids []string, clusterName *string) (map[string]*Table, error)
+
+	mu sync.Mutex
+	// loading tells, by cluster, whether a batch is in flight.
+	loading map[string]bool
+	// pending holds, by cluster, the batch to run once the one in flight returns.
+	pending map[string]*tableBatch
+}
+
+type tableBatch struct {
+	uuids       []string
+	clusterName *string
+	// ctxs are the contexts of the callers waiting on the batch.
+	ctxs   []context.Context
+	done   chan struct{}
+	tables map[string]*Table
+	err    error
+}
+
+func newTableBatcher(load func(ctx context.Context, uuids []string, clusterName *string) (map[string]*Table, error)) *tableBatcher {
+	return &tableBatcher{
+		load:    load,
+		loading: make(map[string]bool),
+		pending: make(map[string]*tableBatch),
+	}
+}
+
+func (b *tableBatcher) get(ctx context.Context, uuid string, clusterName *string) (*Table, error) {
+	key := ""
+	if clusterName != nil {
+		key = *clusterName
+	}
+
+	b.mu.Lock()
+	var batch *tableBatch
+	start := false
+	if b.loading[key] {
+		batch = b.pending[key]
+		if batch == nil {
+			batch = &tableBatch{
+				clusterName: clusterName,
+				done:        make(chan struct{}),
+			}
+			b.pending[key] = batch
+		}
+	} else {
+		batch = &tableBatch{
+			clusterName: clusterName,
+			done:        make(chan struct{}),
+		}
+		b.loading[key] = true
+		start = true
+	}
+	if !slices.Contains(batch.uuids, uuid) {
+		batch.uuids = append(batch.uuids, uuid)
+	}
+	batch.ctxs = append(batch.ctxs, ctx)
+	b.mu.Unlock()
+
+	if start {
+		// The batches are run apart from this caller, whose context must not cancel the queries other callers may
+		// end up waiting for.
+		go b.run(key, batch)
+	}
+
+	select {
+	case <-batch.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if batch.err != nil {
+		return nil, batch.err
+	}
+
+	return batch.tables[uuid], nil
+}
+
+// run loads batch, then the batches that piled up in the meantime, until none is left.
+func (b *tableBatcher) run(key string, batch *tableBatch) {
+	for batch != nil {
+		// The query is run on behalf of every caller, it is only tagged with what they have in common.
+		ctx := context.WithoutCancel(clickhouseclient.WithSharedQueryTag(batch.ctxs[0], batch.ctxs))
+		batch.tables, batch.err = b.load(ctx, batch.uuids, batch.clusterName)
+		close(batch.done)
+
+		b.mu.Lock()
+		batch = b.pending[key]
+		delete(b.pending, key)
+		if batch == nil {
+			delete(b.loading, key)
+		}
+		b.mu.Unlock()
+	}
+}
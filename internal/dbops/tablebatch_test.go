@@ -0,0 +1,124 @@
+package dbops
+
+import (
+	"context"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+)
+
+func TestTableBatcher_readsAloneRightAway(t *testing.T) {
+	var calls atomic.Int32
+	b := newTableBatcher(func(ctx context.Context, uuids []string, clusterName *string) (map[string]*Table, error) {
+		calls.Add(1)
+		return map[string]*Table{uuids[0]: {UUID: uuids[0]}}, nil
+	})
+
+	for _, uuid := range []string{"a", "b"} {
+		table, err := b.get(context.Background(), uuid, nil)
+		if err != nil {
+			t.Fatalf("get(%q) error = %v", uuid, err)
+		}
+		if table == nil || table.UUID != uuid {
+			t.Errorf("get(%q) = %+v, want table with that UUID", uuid, table)
+		}
+	}
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("load called %d times, want 2", got)
+	}
+}
+
+func TestTableBatcher_coalescesReadsWaitingForALoad(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var mu sync.Mutex
+	var batches [][]string
+	var tags []clickhouseclient.QueryTag
+	b := newTableBatcher(func(ctx context.Context, uuids []string, clusterName *string) (map[string]*Table, error) {
+		mu.Lock()
+		first := len(batches) == 0
+		batches = append(batches, slices.Clone(uuids))
+		tags = append(tags, clickhouseclient.QueryTagFromContext(ctx))
+		mu.Unlock()
+		if first {
+			close(started)
+			<-release
+		}
+
+		tables := make(map[string]*Table)
+		for _, uuid := range uuids {
+			if uuid != "missing" {
+				tables[uuid] = &Table{UUID: uuid}
+			}
+		}
+		return tables, nil
+	})
+
+	tagged := func(resource string) context.Context {
+		return clickhouseclient.WithQueryTag(context.Background(), clickhouseclient.QueryTag{Resource: resource, Operation: "read"})
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if _, err := b.get(tagged("clickhousedbops_table"), "first", nil); err != nil {
+			t.Errorf("get(%q) error = %v", "first", err)
+		}
+	}()
+	<-started
+
+	uuids := []string{"a", "b", "missing"}
+	results := make([]*Table, len(uuids))
+	for i, uuid := range uuids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			table, err := b.get(tagged("clickhousedbops_"+uuid), uuid, nil)
+			if err != nil {
+				t.Errorf("get(%q) error = %v", uuid, err)
+			}
+			results[i] = table
+		}()
+	}
+
+	// Let the first load return once the other reads are waiting for the next batch.
+	for {
+		b.mu.Lock()
+		waiting := b.pending[""] != nil && len(b.pending[""].uuids) == len(uuids)
+		b.mu.Unlock()
+		if waiting {
+			break
+		}
+		runtime.Gosched()
+	}
+	close(release)
+	wg.Wait()
+
+	if len(batches) != 2 || len(batches[1]) != len(uuids) {
+		t.Fatalf("loaded batches %v, want [[first] %v]", batches, uuids)
+	}
+	for i, uuid := range uuids {
+		if uuid == "missing" {
+			if results[i] != nil {
+				t.Errorf("get(%q) = %+v, want nil", uuid, results[i])
+			}
+			continue
+		}
+		if results[i] == nil || results[i].UUID != uuid {
+			t.Errorf("get(%q) = %+v, want table with that UUID", uuid, results[i])
+		}
+	}
+
+	if want := (clickhouseclient.QueryTag{Resource: "clickhousedbops_table", Operation: "read"}); tags[0] != want {
+		t.Errorf("first batch tag = %+v, want %+v", tags[0], want)
+	}
+	if want := (clickhouseclient.QueryTag{Operation: "read"}); tags[1] != want {
+		t.Errorf("shared batch tag = %+v, want %+v", tags[1], want)
+	}
+}
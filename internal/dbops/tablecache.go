@@ -0,0 +1,65 @@
+package dbops
+
+import (
+	"context"
+	"sync"
+)
+
+type tableCacheKey struct{}
+
+// tableCache holds the tables read during a single resource operation, keyed by cluster name and UUID.
+type tableCache struct {
+	mu     sync.Mutex
+	tables map[string]*Table
+}
+
+// WithTableCache returns a context that caches GetTable results for as long as it is used.
+// It is meant to wrap the context of a single Create or Update so that repeated reads of the same table
+// (e.g. FindTableByName followed by a state sync) only hit the server once. Any table DDL run through the
+// client with this context invalidates the cache.
+func WithTableCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, tableCacheKey{}, &tableCache{
+		tables: make(map[string]*Table),
+	})
+}
+
+func tableCacheFromContext(ctx context.Context) *tableCache {
+	cache, _ := ctx.Value(tableCacheKey{}).(*tableCache)
+	return cache
+}
+
+func (c *tableCache) key(uuid string, clusterName *string) string {
+	if clusterName == nil {
+		return uuid
+	}
+
+	return *clusterName + ":" + uuid
+}
+
+func (c *tableCache) get(uuid string, clusterName *string) (*Table, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	table, ok := c.tables[c.key(uuid, clusterName)]
+	return table, ok
+}
+
+func (c *tableCache) set(uuid string, clusterName *string, table *Table) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.tables[c.key(uuid, clusterName)] = table
+}
+
+// invalidateTableCache drops every cached table of the context, if any.
+func invalidateTableCache(ctx context.Context) {
+	cache := tableCacheFromContext(ctx)
+	if cache == nil {
+		return
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.tables = make(map[string]*Table)
+}
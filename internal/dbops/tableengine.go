@@ -0,0 +1,74 @@
+package dbops
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+type TableEngine struct {
+	Name                string `json:"name"`
+	SupportsSettings    bool   `json:"supports_settings"`
+	SupportsTTL         bool   `json:"supports_ttl"`
+	SupportsSortOrder   bool   `json:"supports_sort_order"`
+	SupportsReplication bool   `json:"supports_replication"`
+}
+
+// GetTableEngines returns the table engines available on the server, sorted by name.
+func (i *impl) GetTableEngines(ctx context.Context) ([]TableEngine, error) {
+	sql, err := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewField("name"),
+			querybuilder.NewField("supports_settings"),
+			querybuilder.NewField("supports_ttl"),
+			querybuilder.NewField("supports_sort_order"),
+			querybuilder.NewField("supports_replication"),
+		},
+		"system.table_engines",
+	).OrderBy("name").Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	ret := make([]TableEngine, 0)
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		name, err := data.GetString("name")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'name' field")
+		}
+		supportsSettings, err := data.GetBool("supports_settings")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'supports_settings' field")
+		}
+		supportsTTL, err := data.GetBool("supports_ttl")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'supports_ttl' field")
+		}
+		supportsSortOrder, err := data.GetBool("supports_sort_order")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'supports_sort_order' field")
+		}
+		supportsReplication, err := data.GetBool("supports_replication")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'supports_replication' field")
+		}
+
+		ret = append(ret, TableEngine{
+			Name:                name,
+			SupportsSettings:    supportsSettings,
+			SupportsTTL:         supportsTTL,
+			SupportsSortOrder:   supportsSortOrder,
+			SupportsReplication: supportsReplication,
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,83 @@
+package dbops
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// ExchangeTables atomically swaps the names of two tables of the same database.
+// The statement is not retried: running it twice would swap the tables back.
+func (i *impl) ExchangeTables(ctx context.Context, databaseName string, tableName string, otherName string, clusterName *string) error {
+	if err := i.CheckDatabaseManageable(databaseName); err != nil {
+		return err
+	}
+
+	sql, err := querybuilder.NewExchangeTables(databaseName, tableName, otherName).WithCluster(clusterName).Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
+	}
+
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return err
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	invalidateTableCache(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
+	}
+
+	return nil
+}
+
+// RenameTable renames a table, keeping it in the same database.
+func (i *impl) RenameTable(ctx context.Context, databaseName string, tableName string, newName string, clusterName *string) error {
+	if err := i.CheckDatabaseManageable(databaseName); err != nil {
+		return err
+	}
+
+	sql, err := querybuilder.NewRenameTable(databaseName, tableName, newName).WithCluster(clusterName).Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
+	}
+
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return err
+	}
+
+	err = i.execWithRetry(ctx, sql, func(ctx context.Context) (bool, error) {
+		t, err := i.findTable(ctx, databaseName, newName, clusterName)
+		return t != nil, err
+	})
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
+	}
+
+	invalidateTableCache(ctx)
+
+	return nil
+}
+
+// CopyTableData inserts the given columns of every row of sourceName into tableName. The query runs on the replica
+// hit by the connection only: with a cluster, the copy reaches the other replicas through replication, and only the
+// data of that replica's shard is copied.
+func (i *impl) CopyTableData(ctx context.Context, databaseName string, sourceName string, tableName string, columns []string) error {
+	if err := i.CheckDatabaseManageable(databaseName); err != nil {
+		return err
+	}
+
+	sql, err := querybuilder.NewInsertSelect(databaseName, tableName, sourceName, columns).Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, sql)
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package dbops
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// TableIndex is a data skipping index as read from system.data_skipping_indices.
+type TableIndex struct {
+	DatabaseName string
+	TableName    string
+	Name         string
+	Expression   string
+	// Type is the full index type including its parameters, e.g. vector_similarity('hnsw', 'L2Distance', 3).
+	Type        string
+	Granularity uint64
+}
+
+// AddTableIndex adds a data skipping index to the table. The index only covers parts written afterwards until it is
+// materialized with MaterializeTableIndex.
+func (i *impl) AddTableIndex(ctx context.Context, databaseName, tableName string, index querybuilder.TableIndex, clusterName *string) error {
+	if err := i.CheckDatabaseManageable(databaseName); err != nil {
+		return err
+	}
+
+	if strings.HasPrefix(index.Type, "vector_similarity(") {
+		if err := i.requires(ctx, featureVectorIndex); err != nil {
+			return err
+		}
+	}
+
+	query, err := querybuilder.NewAlterTableAddIndex(databaseName, tableName, []querybuilder.TableIndex{index}).
+		WithCluster(clusterName).
+		Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building ALTER TABLE ADD INDEX query")
+	}
+
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return err
+	}
+
+	err = i.execWithRetry(ctx, query, func(ctx context.Context) (bool, error) {
+		idx, err := i.GetTableIndex(ctx, databaseName, tableName, index.Name, clusterName)
+		return idx != nil, err
+	})
+	if err != nil {
+		return errors.WithMessage(err, "error adding index to table")
+	}
+
+	return nil
+}
+
+// GetTableIndex returns the named data skipping index of the table, or nil if there is none.
+func (i *impl) GetTableIndex(ctx context.Context, databaseName, tableName, indexName string, clusterName *string) (*TableIndex, error) {
+	query := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewField("expr"),
+			querybuilder.NewField("type_full"),
+			querybuilder.NewField("granularity"),
+		},
+		"system.data_skipping_indices",
+	).WithCluster(i.readCluster(clusterName)).
+		Where(
+			querybuilder.WhereEquals("database", querybuilder.NewParameter("database", "String", databaseName)),
+			querybuilder.WhereEquals("table", querybuilder.NewParameter("table", "String", tableName)),
+			querybuilder.WhereEquals("name", querybuilder.NewParameter("name", "String", indexName)),
+		)
+	sql, err := query.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	var index *TableIndex
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
+		expr, err := data.GetString("expr")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'expr' field")
+		}
+		typeFull, err := data.GetString("type_full")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'type_full' field")
+		}
+		granularity, err := data.GetUInt64("granularity")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'granularity' field")
+		}
+
+		index = &TableIndex{
+			DatabaseName: databaseName,
+			TableName:    tableName,
+			Name:         indexName,
+			Expression:   expr,
+			Type:         typeFull,
+			Granularity:  granularity,
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return index, nil
+}
+
+// MaterializeTableIndex builds the index for the parts written before it was added. It runs as a mutation in the
+// background.
+func (i *impl) MaterializeTableIndex(ctx context.Context, databaseName, tableName, indexName string, clusterName *string) error {
+	if err := i.CheckDatabaseManageable(databaseName); err != nil {
+		return err
+	}
+
+	query, err := querybuilder.NewAlterTableMaterializeIndex(databaseName, tableName, indexName).
+		WithCluster(clusterName).
+		Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building ALTER TABLE MATERIALIZE INDEX query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, query)
+	if err != nil {
+		return errors.WithMessage(err, "error materializing index")
+	}
+
+	return nil
+}
+
+func (i *impl) DropTableIndex(ctx context.Context, databaseName, tableName, indexName string, clusterName *string) error {
+	if err := i.CheckDatabaseManageable(databaseName); err != nil {
+		return err
+	}
+
+	query, err := querybuilder.NewAlterTableDropIndex(databaseName, tableName, []string{indexName}).
+		WithCluster(clusterName).
+		Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building ALTER TABLE DROP INDEX query")
+	}
+
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return err
+	}
+
+	err = i.execWithRetry(ctx, query, func(ctx context.Context) (bool, error) {
+		idx, err := i.GetTableIndex(ctx, databaseName, tableName, indexName, clusterName)
+		return idx == nil, err
+	})
+	if err != nil {
+		return errors.WithMessage(err, "error dropping index from table")
+	}
+
+	return nil
+}
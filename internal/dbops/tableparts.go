@@ -0,0 +1,132 @@
+package dbops
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// TablePartsSummary is the disk usage of the active parts of a table's partition stored on one disk.
+type TablePartsSummary struct {
+	Partition             string `json:"partition"`
+	PartitionID           string `json:"partition_id"`
+	DiskName              string `json:"disk_name"`
+	Parts                 uint64 `json:"parts"`
+	Rows                  uint64 `json:"rows"`
+	BytesOnDisk           uint64 `json:"bytes_on_disk"`
+	DataCompressedBytes   uint64 `json:"data_compressed_bytes"`
+	DataUncompressedBytes uint64 `json:"data_uncompressed_bytes"`
+	MinTime               string `json:"min_time"`
+	MaxTime               string `json:"max_time"`
+}
+
+// GetTableParts summarizes the active parts of the given table by partition and disk. With a cluster, the parts of
+// one replica per shard are summed up, so that the totals are the size of the data rather than of its copies.
+func (i *impl) GetTableParts(ctx context.Context, databaseName string, tableName string, clusterName *string) ([]TablePartsSummary, error) {
+	// min_date and max_date are set when partitioning by a Date column, min_time and max_time by a DateTime column.
+	minTime := "min(greatest(toDateTime(min_date), min_time))"
+	maxTime := "max(greatest(toDateTime(max_date), max_time))"
+
+	query := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewField("partition"),
+			querybuilder.NewField("partition_id"),
+			querybuilder.NewField("disk_name"),
+			querybuilder.NewExpressionField("toUInt64(count())", "parts_count"),
+			querybuilder.NewExpressionField("toUInt64(sum(rows))", "rows_count"),
+			querybuilder.NewExpressionField("toUInt64(sum(bytes_on_disk))", "total_bytes_on_disk"),
+			querybuilder.NewExpressionField("toUInt64(sum(data_compressed_bytes))", "total_data_compressed_bytes"),
+			querybuilder.NewExpressionField("toUInt64(sum(data_uncompressed_bytes))", "total_data_uncompressed_bytes"),
+			querybuilder.NewExpressionField(fmt.Sprintf("if(%s > toDateTime(0), toString(%s), '')", minTime, minTime), "partition_min_time"),
+			querybuilder.NewExpressionField(fmt.Sprintf("if(%s > toDateTime(0), toString(%s), '')", maxTime, maxTime), "partition_max_time"),
+		},
+		"system.parts",
+	).WithCluster(i.readCluster(clusterName)).
+		Where(
+			querybuilder.WhereEquals("database", querybuilder.NewParameter("database", "String", databaseName)),
+			querybuilder.WhereEquals("table", querybuilder.NewParameter("table", "String", tableName)),
+			querybuilder.WhereEquals("active", 1),
+		).
+		GroupBy("partition", "partition_id", "disk_name").
+		OrderBy("partition_id", "disk_name")
+	sql, err := query.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	ret := make([]TablePartsSummary, 0)
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
+		s, err := tablePartsSummaryFromRow(data)
+		if err != nil {
+			return err
+		}
+
+		ret = append(ret, *s)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return ret, nil
+}
+
+func tablePartsSummaryFromRow(data clickhouseclient.Row) (*TablePartsSummary, error) {
+	partition, err := data.GetString("partition")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'partition' field")
+	}
+	partitionID, err := data.GetString("partition_id")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'partition_id' field")
+	}
+	diskName, err := data.GetString("disk_name")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'disk_name' field")
+	}
+	parts, err := data.GetUInt64("parts_count")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'parts_count' field")
+	}
+	rows, err := data.GetUInt64("rows_count")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'rows_count' field")
+	}
+	bytesOnDisk, err := data.GetUInt64("total_bytes_on_disk")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'total_bytes_on_disk' field")
+	}
+	compressed, err := data.GetUInt64("total_data_compressed_bytes")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'total_data_compressed_bytes' field")
+	}
+	uncompressed, err := data.GetUInt64("total_data_uncompressed_bytes")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'total_data_uncompressed_bytes' field")
+	}
+	minTime, err := data.GetString("partition_min_time")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'partition_min_time' field")
+	}
+	maxTime, err := data.GetString("partition_max_time")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'partition_max_time' field")
+	}
+
+	return &TablePartsSummary{
+		Partition:             partition,
+		PartitionID:           partitionID,
+		DiskName:              diskName,
+		Parts:                 parts,
+		Rows:                  rows,
+		BytesOnDisk:           bytesOnDisk,
+		DataCompressedBytes:   compressed,
+		DataUncompressedBytes: uncompressed,
+		MinTime:               minTime,
+		MaxTime:               maxTime,
+	}, nil
+}
@@ -0,0 +1,105 @@
+package dbops
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// AddTableProjection adds a projection to the table. The projection only covers parts written afterwards until it is
+// materialized with MaterializeTableProjection.
+func (i *impl) AddTableProjection(ctx context.Context, databaseName, tableName string, projection querybuilder.TableProjection, clusterName *string) error {
+	if err := i.CheckDatabaseManageable(databaseName); err != nil {
+		return err
+	}
+
+	query, err := querybuilder.NewAlterTableAddProjection(databaseName, tableName, []querybuilder.TableProjection{projection}).
+		WithCluster(clusterName).
+		Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building ALTER TABLE ADD PROJECTION query")
+	}
+
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return err
+	}
+
+	err = i.execWithRetry(ctx, query, func(ctx context.Context) (bool, error) {
+		return i.hasTableProjection(ctx, databaseName, tableName, projection.Name, clusterName)
+	})
+	if err != nil {
+		return errors.WithMessage(err, "error adding projection to table")
+	}
+
+	return nil
+}
+
+// MaterializeTableProjection builds the projection for the parts written before it was added. It runs as a mutation
+// in the background.
+func (i *impl) MaterializeTableProjection(ctx context.Context, databaseName, tableName, projectionName string, clusterName *string) error {
+	if err := i.CheckDatabaseManageable(databaseName); err != nil {
+		return err
+	}
+
+	query, err := querybuilder.NewAlterTableMaterializeProjection(databaseName, tableName, projectionName).
+		WithCluster(clusterName).
+		Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building ALTER TABLE MATERIALIZE PROJECTION query")
+	}
+
+	err = i.clickhouseClient.Exec(ctx, query)
+	if err != nil {
+		return errors.WithMessage(err, "error materializing projection")
+	}
+
+	return nil
+}
+
+func (i *impl) DropTableProjection(ctx context.Context, databaseName, tableName, projectionName string, clusterName *string) error {
+	if err := i.CheckDatabaseManageable(databaseName); err != nil {
+		return err
+	}
+
+	query, err := querybuilder.NewAlterTableDropProjection(databaseName, tableName, []string{projectionName}).
+		WithCluster(clusterName).
+		Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building ALTER TABLE DROP PROJECTION query")
+	}
+
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return err
+	}
+
+	err = i.execWithRetry(ctx, query, func(ctx context.Context) (bool, error) {
+		exists, err := i.hasTableProjection(ctx, databaseName, tableName, projectionName, clusterName)
+		return !exists, err
+	})
+	if err != nil {
+		return errors.WithMessage(err, "error dropping projection from table")
+	}
+
+	return nil
+}
+
+// hasTableProjection reports whether the definition of the table declares the named projection.
+func (i *impl) hasTableProjection(ctx context.Context, databaseName, tableName, projectionName string, clusterName *string) (bool, error) {
+	table, err := i.findTable(ctx, databaseName, tableName, clusterName)
+	if err != nil {
+		return false, err
+	}
+	if table == nil {
+		return false, errors.New("table with such name not found")
+	}
+
+	for _, projection := range table.Projections {
+		if projection.Name == projectionName {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
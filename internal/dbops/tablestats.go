@@ -0,0 +1,88 @@
+package dbops
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// TableStats is the size of a table as reported by system.tables.
+// The fields are nil when the engine doesn't report them, e.g. for views or tables backed by a remote storage.
+type TableStats struct {
+	TotalRows  *uint64 `json:"total_rows,omitempty"`
+	TotalBytes *uint64 `json:"total_bytes,omitempty"`
+}
+
+// GetTableStats returns the number of rows and bytes of the given table. With a cluster, the figures of one replica
+// per shard are summed up, so that they are the size of the data rather than of its copies.
+func (i *impl) GetTableStats(ctx context.Context, databaseName string, tableName string, clusterName *string) (*TableStats, error) {
+	query := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewExpressionField("toUInt64(sum(ifNull(total_rows, 0)))", "rows_count"),
+			querybuilder.NewExpressionField("countIf(isNull(total_rows)) = 0", "has_total_rows"),
+			querybuilder.NewExpressionField("toUInt64(sum(ifNull(total_bytes, 0)))", "bytes_count"),
+			querybuilder.NewExpressionField("countIf(isNull(total_bytes)) = 0", "has_total_bytes"),
+		},
+		"system.tables",
+	).WithCluster(i.readCluster(clusterName)).
+		Where(
+			querybuilder.WhereEquals("database", querybuilder.NewParameter("database", "String", databaseName)),
+			querybuilder.WhereEquals("name", querybuilder.NewParameter("name", "String", tableName)),
+		)
+	sql, err := query.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	var stats *TableStats
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
+		s, err := tableStatsFromRow(data)
+		if err != nil {
+			return err
+		}
+
+		stats = s
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	if stats == nil {
+		return nil, errors.New("table stats not found")
+	}
+
+	return stats, nil
+}
+
+func tableStatsFromRow(data clickhouseclient.Row) (*TableStats, error) {
+	rows, err := data.GetUInt64("rows_count")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'rows_count' field")
+	}
+	hasRows, err := data.GetBool("has_total_rows")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'has_total_rows' field")
+	}
+	bytes, err := data.GetUInt64("bytes_count")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'bytes_count' field")
+	}
+	hasBytes, err := data.GetBool("has_total_bytes")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'has_total_bytes' field")
+	}
+
+	stats := &TableStats{}
+	if hasRows {
+		stats.TotalRows = &rows
+	}
+	if hasBytes {
+		stats.TotalBytes = &bytes
+	}
+
+	return stats, nil
+}
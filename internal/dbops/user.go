@@ -2,6 +2,7 @@ package dbops
 
 import (
 	"context"
+	"time"
 
 	"github.com/pingcap/errors"
 
@@ -13,14 +14,24 @@ type User struct {
 	ID                 string `json:"id"`
 	Name               string `json:"name"`
 	PasswordSha256Hash string `json:"-"`
+	// Storage is the access storage holding the user, see IsReadOnlyStorage.
+	Storage string `json:"storage"`
+	// ValidUntil is when the user expires. It is only used on creation, and is not read back.
+	ValidUntil *time.Time `json:"-"`
 }
 
 func (i *impl) CreateUser(ctx context.Context, user User, clusterName *string) (*User, error) {
-	sql, err := querybuilder.
+	builder := querybuilder.
 		NewCreateUser(user.Name).
 		Identified(querybuilder.IdentificationSHA256Hash, user.PasswordSha256Hash).
-		WithCluster(clusterName).
-		Build()
+		WithCluster(clusterName)
+	if user.ValidUntil != nil {
+		if err := i.requires(ctx, featureUserValidUntil); err != nil {
+			return nil, err
+		}
+		builder.WithValidUntil(*user.ValidUntil)
+	}
+	sql, err := builder.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
@@ -30,29 +41,44 @@ func (i *impl) CreateUser(ctx context.Context, user User, clusterName *string) (
 		return nil, errors.WithMessage(err, "error running query")
 	}
 
-	return i.FindUserByName(ctx, user.Name, clusterName)
+	created, err := readAfterCreate(ctx, i, clusterName, func(ctx context.Context) (*User, error) {
+		return i.findUser(ctx, user.Name, clusterName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if created == nil {
+		return nil, errors.New("user with such name not found")
+	}
+
+	return created, nil
 }
 
 func (i *impl) GetUser(ctx context.Context, id string, clusterName *string) (*User, error) { // nolint:dupl
-	sql, err := querybuilder.
-		NewSelect([]querybuilder.Field{querybuilder.NewField("name")}, "system.users").
-		WithCluster(clusterName).
-		Where(querybuilder.WhereEquals("id", id)).
-		Build()
+	query := querybuilder.
+		NewSelect([]querybuilder.Field{querybuilder.NewField("name"), querybuilder.NewField("storage")}, "system.users").
+		WithCluster(i.readCluster(clusterName)).
+		Where(querybuilder.WhereEquals("id", querybuilder.NewParameter("id", "UUID", id)))
+	sql, err := query.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
 	var user *User
 
-	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
 		n, err := data.GetString("name")
 		if err != nil {
 			return errors.WithMessage(err, "error scanning query result, missing 'name' field")
 		}
+		s, err := data.GetString("storage")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'storage' field")
+		}
 		user = &User{
-			ID:   id,
-			Name: n,
+			ID:      id,
+			Name:    n,
+			Storage: s,
 		}
 		return nil
 	})
@@ -89,22 +115,39 @@ func (i *impl) DeleteUser(ctx context.Context, id string, clusterName *string) e
 		return errors.WithMessage(err, "error running query")
 	}
 
+	// Grants to and of the dropped entity are gone as well.
+	i.granteeGrants.invalidate()
+
 	return nil
 }
 
 func (i *impl) FindUserByName(ctx context.Context, name string, clusterName *string) (*User, error) {
-	sql, err := querybuilder.
+	user, err := i.findUser(ctx, name, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, errors.New("user with such name not found")
+	}
+
+	return user, nil
+}
+
+// findUser returns the user with the given name, or nil if it does not exist.
+func (i *impl) findUser(ctx context.Context, name string, clusterName *string) (*User, error) {
+	query := querybuilder.
 		NewSelect([]querybuilder.Field{querybuilder.NewField("id")}, "system.users").
-		WithCluster(clusterName).
-		Where(querybuilder.WhereEquals("name", name)).
-		Build()
+		WithCluster(i.readCluster(clusterName)).
+		Where(querybuilder.WhereEquals("name", querybuilder.NewParameter("name", "String", name)))
+	sql, err := query.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
 	var uuid string
 
-	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
 		uuid, err = data.GetString("id")
 		if err != nil {
 			return errors.WithMessage(err, "error scanning query result, missing 'id' field")
@@ -116,5 +159,9 @@ func (i *impl) FindUserByName(ctx context.Context, name string, clusterName *str
 		return nil, errors.WithMessage(err, "error running query")
 	}
 
+	if uuid == "" {
+		return nil, nil
+	}
+
 	return i.GetUser(ctx, uuid, clusterName)
 }
@@ -0,0 +1,163 @@
+package dbops
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// ServerVersion is the major.minor version of the ClickHouse server.
+type ServerVersion struct {
+	Major int
+	Minor int
+}
+
+func (v ServerVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// AtLeast reports whether v is the same as or newer than other.
+func (v ServerVersion) AtLeast(other ServerVersion) bool {
+	if v.Major != other.Major {
+		return v.Major > other.Major
+	}
+
+	return v.Minor >= other.Minor
+}
+
+// feature is a capability of the server that older versions lack.
+type feature string
+
+const (
+	featureTableComment    feature = "table comments"
+	featureDatabaseComment feature = "database comments"
+	featureUserDirectories feature = "system.user_directories"
+	featureVectorIndex     feature = "vector similarity indexes"
+	featureDynamicType     feature = "Dynamic columns"
+	featureJSONType        feature = "JSON columns"
+	// featureDetachedPartDetails is when system.detached_parts started reporting the size and modification time.
+	featureDetachedPartDetails feature = "size and modification time of detached parts"
+	// featureStableSemiStructuredTypes is when JSON and Dynamic columns stopped requiring experimental settings.
+	featureStableSemiStructuredTypes feature = "JSON and Dynamic columns without experimental settings"
+	// featureUserValidUntil is when CREATE USER started accepting an expiration date, and GRANT the CURRENT GRANTS
+	// of the user running it.
+	featureUserValidUntil feature = "users with an expiration date"
+	// featureSettingsWritability is when system.settings_profile_elements replaced the readonly column with writability.
+	featureSettingsWritability feature = "writability of settings profile elements"
+	// featureSQLSecurity is when views started reading their tables with the privileges of their definer.
+	featureSQLSecurity feature = "views with SQL SECURITY DEFINER"
+	// featureDatabaseEngineFull is when system.databases started reporting the engine parameters in engine_full.
+	featureDatabaseEngineFull feature = "engine parameters of databases"
+)
+
+// featureMinVersions lists the first version supporting each feature. Keep the README in sync.
+var featureMinVersions = map[feature]ServerVersion{
+	featureTableComment:    {Major: 21, Minor: 6},
+	featureDatabaseComment: {Major: 22, Minor: 8},
+	featureUserDirectories: {Major: 20, Minor: 8},
+	featureVectorIndex:     {Major: 25, Minor: 8},
+	featureDynamicType:     {Major: 24, Minor: 5},
+	featureJSONType:        {Major: 24, Minor: 8},
+
+	featureDetachedPartDetails: {Major: 23, Minor: 4},
+
+	featureStableSemiStructuredTypes: {Major: 25, Minor: 3},
+
+	featureUserValidUntil: {Major: 23, Minor: 9},
+
+	featureSettingsWritability: {Major: 22, Minor: 7},
+
+	featureSQLSecurity: {Major: 24, Minor: 2},
+
+	featureDatabaseEngineFull: {Major: 23, Minor: 3},
+}
+
+// GetServerVersion returns the version of the ClickHouse server. The result is computed once per client.
+func (i *impl) GetServerVersion(ctx context.Context) (*ServerVersion, error) {
+	i.serverVersionMu.Lock()
+	defer i.serverVersionMu.Unlock()
+
+	if i.serverVersion != nil {
+		return i.serverVersion, nil
+	}
+
+	sql, err := querybuilder.NewSelect(
+		[]querybuilder.Field{querybuilder.NewExpressionField("version()", "version")},
+		"system.one",
+	).Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	var version string
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		version, err = data.GetString("version")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'version' field")
+		}
+
+		return nil
+	})
+	if err != nil {
+		// Errors are not cached, next call will try again.
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	v, err := parseServerVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	i.serverVersion = v
+
+	return v, nil
+}
+
+// supports reports whether the server has the given feature.
+func (i *impl) supports(ctx context.Context, f feature) (bool, error) {
+	v, err := i.GetServerVersion(ctx)
+	if err != nil {
+		return false, errors.WithMessage(err, "error getting server version")
+	}
+
+	return v.AtLeast(featureMinVersions[f]), nil
+}
+
+// requires returns an error if the server lacks the given feature.
+func (i *impl) requires(ctx context.Context, f feature) error {
+	ok, err := i.supports(ctx, f)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return errors.New(fmt.Sprintf("%s require ClickHouse %s or later", f, featureMinVersions[f]))
+	}
+
+	return nil
+}
+
+// parseServerVersion parses the output of version(), e.g. 24.8.4.13 or 25.1.1.1-testing.
+func parseServerVersion(version string) (*ServerVersion, error) {
+	parts := strings.Split(strings.TrimSpace(version), ".")
+	if len(parts) < 2 {
+		return nil, errors.New(fmt.Sprintf("cannot parse server version %q", version))
+	}
+
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("cannot parse major version of %q", version))
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("cannot parse minor version of %q", version))
+	}
+
+	return &ServerVersion{Major: major, Minor: minor}, nil
+}
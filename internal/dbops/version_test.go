@@ -0,0 +1,74 @@
+package dbops
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseServerVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		want    *ServerVersion
+		wantErr bool
+	}{
+		{
+			name:    "Stable release",
+			version: "24.8.4.13",
+			want:    &ServerVersion{Major: 24, Minor: 8},
+			wantErr: false,
+		},
+		{
+			name:    "Cloud release",
+			version: "25.1.1.1-testing",
+			want:    &ServerVersion{Major: 25, Minor: 1},
+			wantErr: false,
+		},
+		{
+			name:    "Major only",
+			version: "24",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "Not a version",
+			version: "a.b",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseServerVersion(tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseServerVersion() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseServerVersion() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServerVersion_AtLeast(t *testing.T) {
+	tests := []struct {
+		name  string
+		v     ServerVersion
+		other ServerVersion
+		want  bool
+	}{
+		{name: "Same version", v: ServerVersion{22, 8}, other: ServerVersion{22, 8}, want: true},
+		{name: "Newer minor", v: ServerVersion{22, 10}, other: ServerVersion{22, 8}, want: true},
+		{name: "Older minor", v: ServerVersion{22, 3}, other: ServerVersion{22, 8}, want: false},
+		{name: "Newer major", v: ServerVersion{23, 1}, other: ServerVersion{22, 8}, want: true},
+		{name: "Older major", v: ServerVersion{21, 12}, other: ServerVersion{22, 8}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.v.AtLeast(tt.other); got != tt.want {
+				t.Errorf("AtLeast() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package dbops
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// View is a standard (non materialized) view.
+type View struct {
+	UUID         string `json:"uuid"`
+	DatabaseName string `json:"database_name"`
+	Name         string `json:"name"`
+	// Query is the SELECT query of the view. When read back, it is the query as normalized by the server.
+	Query   string `json:"query"`
+	Comment string `json:"comment"`
+	// SQLSecurityDefiner makes the view read its tables with the privileges of the user creating it. It is not read
+	// back.
+	SQLSecurityDefiner bool `json:"sql_security_definer"`
+}
+
+func (i *impl) CreateView(ctx context.Context, view View, clusterName *string) (*View, error) {
+	if err := i.CheckDatabaseManageable(view.DatabaseName); err != nil {
+		return nil, err
+	}
+
+	if view.Comment != "" {
+		if err := i.requires(ctx, featureTableComment); err != nil {
+			return nil, err
+		}
+	}
+
+	if view.SQLSecurityDefiner {
+		if err := i.requires(ctx, featureSQLSecurity); err != nil {
+			return nil, err
+		}
+	}
+
+	sql, err := CreateViewStatement(view, clusterName)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return nil, err
+	}
+
+	err = i.execWithRetry(ctx, sql, func(ctx context.Context) (bool, error) {
+		v, err := i.findView(ctx, view.DatabaseName, view.Name, clusterName)
+		return v != nil, err
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	created, err := readAfterCreate(ctx, i, clusterName, func(ctx context.Context) (*View, error) {
+		return i.findView(ctx, view.DatabaseName, view.Name, clusterName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if created == nil {
+		return nil, errors.New("view with such name not found")
+	}
+
+	return created, nil
+}
+
+// CreateViewStatement returns the CREATE VIEW statement CreateView runs for the given view.
+func CreateViewStatement(view View, clusterName *string) (string, error) {
+	builder := querybuilder.NewCreateView(view.DatabaseName, view.Name, view.Query).WithCluster(clusterName)
+	if view.Comment != "" {
+		builder.WithComment(view.Comment)
+	}
+	if view.SQLSecurityDefiner {
+		builder.WithSQLSecurityDefiner()
+	}
+
+	return builder.Build()
+}
+
+// GetView returns the view with the given UUID, or nil if it does not exist.
+func (i *impl) GetView(ctx context.Context, uuid string, clusterName *string) (*View, error) {
+	views, err := i.selectViews(ctx, clusterName, querybuilder.WhereEquals("uuid", querybuilder.NewParameter("uuid", "UUID", uuid)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, view := range views {
+		return view, nil
+	}
+
+	return nil, nil
+}
+
+func (i *impl) FindViewByName(ctx context.Context, databaseName, viewName string, clusterName *string) (*View, error) {
+	view, err := i.findView(ctx, databaseName, viewName, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	if view == nil {
+		return nil, errors.New("view with such name not found")
+	}
+
+	return view, nil
+}
+
+// findView returns the view with the given name, or nil if it does not exist.
+func (i *impl) findView(ctx context.Context, databaseName, viewName string, clusterName *string) (*View, error) {
+	views, err := i.selectViews(
+		ctx,
+		clusterName,
+		querybuilder.WhereEquals("database", querybuilder.NewParameter("database", "String", databaseName)),
+		querybuilder.WhereEquals("name", querybuilder.NewParameter("name", "String", viewName)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, view := range views {
+		return view, nil
+	}
+
+	return nil, nil
+}
+
+func (i *impl) selectViews(ctx context.Context, clusterName *string, where ...querybuilder.Where) ([]*View, error) {
+	commentField := querybuilder.NewField("comment")
+	if ok, err := i.supports(ctx, featureTableComment); err != nil {
+		return nil, err
+	} else if !ok {
+		commentField = querybuilder.NewExpressionField("''", "comment")
+	}
+
+	query := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewField("uuid"),
+			querybuilder.NewField("database"),
+			querybuilder.NewField("name"),
+			querybuilder.NewField("as_select"),
+			commentField,
+		},
+		"system.tables",
+	).WithCluster(i.readCluster(clusterName)).
+		Where(append(where, querybuilder.WhereEquals("engine", querybuilder.NewParameter("engine", "String", "View")))...)
+	sql, err := query.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	views := make([]*View, 0)
+
+	err = i.clickhouseClient.Select(clickhouseclient.WithParameters(ctx, query.Parameters()), sql, func(data clickhouseclient.Row) error {
+		u, err := data.GetString("uuid")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'uuid' field")
+		}
+		d, err := data.GetString("database")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'database' field")
+		}
+		n, err := data.GetString("name")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'name' field")
+		}
+		q, err := data.GetString("as_select")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'as_select' field")
+		}
+		c, err := data.GetString("comment")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'comment' field")
+		}
+
+		// With a cluster, every replica returns its own copy of the view.
+		for _, view := range views {
+			if view.UUID == u {
+				return nil
+			}
+		}
+
+		views = append(views, &View{
+			UUID:         u,
+			DatabaseName: d,
+			Name:         n,
+			Query:        q,
+			Comment:      c,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return views, nil
+}
+
+func (i *impl) DeleteView(ctx context.Context, uuid string, clusterName *string) error {
+	view, err := i.GetView(ctx, uuid, clusterName)
+	if err != nil {
+		return errors.WithMessage(err, "error getting view")
+	}
+
+	if view == nil {
+		// This is desired state.
+		return nil
+	}
+
+	if err := i.CheckDatabaseManageable(view.DatabaseName); err != nil {
+		return err
+	}
+
+	sql, err := querybuilder.NewDropTable(view.DatabaseName, view.Name).WithCluster(clusterName).Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
+	}
+
+	if err := i.checkClusterHealth(ctx, clusterName); err != nil {
+		return err
+	}
+
+	err = i.execWithRetry(ctx, sql, func(ctx context.Context) (bool, error) {
+		v, err := i.GetView(ctx, uuid, clusterName)
+		return v == nil, err
+	})
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
+	}
+
+	return nil
+}
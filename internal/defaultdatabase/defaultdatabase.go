@@ -0,0 +1,51 @@
+// Package defaultdatabase resolves the database_name left out of the configuration to the provider's default_database.
+package defaultdatabase
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+// ResolvePlan sets the planned database_name to the default_database of the provider when the configuration leaves
+// it out, and reports an error when the provider has none. The object is recreated when the default database changes.
+// The attribute must be Optional and Computed, with UseStateForUnknown before RequiresReplace so that an unchanged
+// default database keeps the object.
+// Nothing is resolved when the provider is not configured yet: database_name stays unknown until apply.
+func ResolvePlan(ctx context.Context, client dbops.Client, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if client == nil || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var configDatabaseName types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("database_name"), &configDatabaseName)...)
+	if resp.Diagnostics.HasError() || !configDatabaseName.IsNull() {
+		return
+	}
+
+	databaseName := client.DefaultDatabase()
+	if databaseName == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("database_name"),
+			"Missing Database Name",
+			"'database_name' must be set when the provider has no 'default_database'.",
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("database_name"), databaseName)...)
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var stateDatabaseName types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("database_name"), &stateDatabaseName)...)
+	if stateDatabaseName.ValueString() != databaseName {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("database_name"))
+	}
+}
@@ -0,0 +1,95 @@
+// Package hclgen renders Terraform configuration snippets, formatted like `terraform fmt` would.
+package hclgen
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	identifierRegex          = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+	invalidIdentifierChars   = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+	identifierStartCharRegex = regexp.MustCompile(`^[A-Za-z_]`)
+)
+
+// Attribute is a `name = value` line, value being already rendered.
+type Attribute struct {
+	Name  string
+	Value string
+}
+
+// WriteAttributes writes one line per attribute, aligning the equal signs.
+func WriteAttributes(sb *strings.Builder, indent string, attributes []Attribute) {
+	width := 0
+	for _, a := range attributes {
+		width = max(width, len(a.Name))
+	}
+
+	for _, a := range attributes {
+		fmt.Fprintf(sb, "%s%-*s = %s\n", indent, width, a.Name, a.Value)
+	}
+}
+
+// String returns s as an HCL quoted string, escaping template sequences.
+func String(s string) string {
+	var sb strings.Builder
+
+	sb.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '$', '%':
+			// Interpolation (${) and directive (%{) sequences are escaped by doubling the first character.
+			if i+1 < len(s) && s[i+1] == '{' {
+				sb.WriteByte(c)
+			}
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	sb.WriteByte('"')
+
+	return sb.String()
+}
+
+// List returns values as an HCL list of quoted strings.
+func List(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, String(v))
+	}
+
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// Key returns name as an object key, quoting it only when it is not a valid identifier.
+func Key(name string) string {
+	if identifierRegex.MatchString(name) {
+		return name
+	}
+
+	return String(name)
+}
+
+// Identifier turns name into a valid Terraform identifier (e.g. a resource name), replacing invalid characters
+// with underscores and adding prefix when name does not start with a letter or underscore.
+func Identifier(name string, prefix string) string {
+	name = invalidIdentifierChars.ReplaceAllString(name, "_")
+	if !identifierStartCharRegex.MatchString(name) {
+		name = prefix + name
+	}
+
+	return name
+}
@@ -0,0 +1,54 @@
+package hclgen
+
+import (
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "plain", value: "events", want: `"events"`},
+		{name: "quotes and backslashes", value: `the "user" \ me`, want: `"the \"user\" \\ me"`},
+		{name: "new lines", value: "a\nb", want: `"a\nb"`},
+		{name: "template sequences", value: "costs ${0} %{x} $1 %", want: `"costs $${0} %%{x} $1 %"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := String(tt.value); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "valid", value: "events", want: "events"},
+		{name: "invalid characters", value: "events.v2", want: "events_v2"},
+		{name: "starts with digit", value: "2024_events", want: "table_2024_events"},
+		{name: "empty", value: "", want: "table_"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Identifier(tt.value, "table_"); got != tt.want {
+				t.Errorf("Identifier() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	if got := Key("index_granularity"); got != "index_granularity" {
+		t.Errorf("Key() = %v, want index_granularity", got)
+	}
+	if got := Key("my.key"); got != `"my.key"` {
+		t.Errorf("Key() = %v, want \"my.key\"", got)
+	}
+}
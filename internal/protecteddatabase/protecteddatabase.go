@@ -0,0 +1,28 @@
+// Package protecteddatabase holds the check keeping resources out of the databases protected by the provider.
+package protecteddatabase
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+// Validate reports an error on the attribute at p when it names one of the protected_databases of the provider, so
+// that a database name computed from variables can't make terraform create or drop objects in e.g. `system`.
+// Nothing is checked when the provider is not configured yet or the name is not known yet: the statements are
+// checked again when they run.
+func Validate(client dbops.Client, p path.Path, databaseName types.String, diags *diag.Diagnostics) {
+	if client == nil || databaseName.IsNull() || databaseName.IsUnknown() {
+		return
+	}
+
+	if err := client.CheckDatabaseManageable(databaseName.ValueString()); err != nil {
+		diags.AddAttributeError(
+			p,
+			"Protected Database",
+			err.Error(),
+		)
+	}
+}
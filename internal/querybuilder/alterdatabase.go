@@ -0,0 +1,54 @@
+package querybuilder
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// AlterDatabaseQueryBuilder is an interface to build ALTER DATABASE SQL queries (already interpolated).
+type AlterDatabaseQueryBuilder interface {
+	QueryBuilder
+	WithCluster(clusterName *string) AlterDatabaseQueryBuilder
+}
+
+type alterDatabaseModifySettingQueryBuilder struct {
+	databaseName string
+	settings     map[string]string
+	clusterName  *string
+}
+
+// NewAlterDatabaseModifySetting builds an ALTER DATABASE ... MODIFY SETTING query changing the engine settings of a
+// database, values are emitted as-is.
+func NewAlterDatabaseModifySetting(databaseName string, settings map[string]string) AlterDatabaseQueryBuilder {
+	return &alterDatabaseModifySettingQueryBuilder{
+		databaseName: databaseName,
+		settings:     settings,
+	}
+}
+
+func (q *alterDatabaseModifySettingQueryBuilder) WithCluster(clusterName *string) AlterDatabaseQueryBuilder {
+	q.clusterName = clusterName
+	return q
+}
+
+func (q *alterDatabaseModifySettingQueryBuilder) Build() (string, error) {
+	if q.databaseName == "" {
+		return "", errors.New("databaseName cannot be empty for ALTER DATABASE queries")
+	}
+	if len(q.settings) == 0 {
+		return "", errors.New("at least one setting is required for ALTER DATABASE MODIFY SETTING queries")
+	}
+
+	tokens := []string{
+		"ALTER",
+		"DATABASE",
+		backtick(q.databaseName),
+	}
+	if q.clusterName != nil {
+		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
+	}
+	tokens = append(tokens, "MODIFY", "SETTING", settingsList(q.settings))
+
+	return strings.Join(tokens, " ") + ";", nil
+}
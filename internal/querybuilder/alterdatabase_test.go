@@ -0,0 +1,58 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func Test_alterDatabaseModifySetting(t *testing.T) {
+	clusterName := "default"
+
+	tests := []struct {
+		name         string
+		databaseName string
+		settings     map[string]string
+		clusterName  *string
+		want         string
+		wantErr      bool
+	}{
+		{
+			name:         "Modify settings",
+			databaseName: "pg",
+			settings:     map[string]string{"materialized_postgresql_max_block_size": "8192", "materialized_postgresql_allow_automatic_update": "1"},
+			want:         "ALTER DATABASE `pg` MODIFY SETTING materialized_postgresql_allow_automatic_update = 1, materialized_postgresql_max_block_size = 8192;",
+			wantErr:      false,
+		},
+		{
+			name:         "Modify settings on cluster",
+			databaseName: "pg",
+			settings:     map[string]string{"materialized_postgresql_max_block_size": "8192"},
+			clusterName:  &clusterName,
+			want:         "ALTER DATABASE `pg` ON CLUSTER 'default' MODIFY SETTING materialized_postgresql_max_block_size = 8192;",
+			wantErr:      false,
+		},
+		{
+			name:         "No settings",
+			databaseName: "pg",
+			settings:     nil,
+			wantErr:      true,
+		},
+		{
+			name:         "No database name",
+			databaseName: "",
+			settings:     map[string]string{"materialized_postgresql_max_block_size": "8192"},
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewAlterDatabaseModifySetting(tt.databaseName, tt.settings).WithCluster(tt.clusterName).Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Build() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package querybuilder
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+const (
+	GranteeKindUser = "USER"
+	GranteeKindRole = "ROLE"
+)
+
+// AlterGranteeQueryBuilder is an interface to build ALTER USER and ALTER ROLE SQL queries (already interpolated).
+type AlterGranteeQueryBuilder interface {
+	QueryBuilder
+	AddProfile(profileName string) AlterGranteeQueryBuilder
+	DropProfile(profileName string) AlterGranteeQueryBuilder
+	SetProfiles(profileNames []string) AlterGranteeQueryBuilder
+	WithCluster(clusterName *string) AlterGranteeQueryBuilder
+}
+
+type alterGranteeQueryBuilder struct {
+	kind        string
+	name        string
+	clauses     []string
+	clusterName *string
+}
+
+// NewAlterUser builds an ALTER USER query.
+func NewAlterUser(userName string) AlterGranteeQueryBuilder {
+	return &alterGranteeQueryBuilder{
+		kind: GranteeKindUser,
+		name: userName,
+	}
+}
+
+// NewAlterRole builds an ALTER ROLE query.
+func NewAlterRole(roleName string) AlterGranteeQueryBuilder {
+	return &alterGranteeQueryBuilder{
+		kind: GranteeKindRole,
+		name: roleName,
+	}
+}
+
+// AddProfile makes the grantee inherit the settings of the profile, leaving its other settings and profiles untouched.
+func (q *alterGranteeQueryBuilder) AddProfile(profileName string) AlterGranteeQueryBuilder {
+	q.clauses = append(q.clauses, "ADD PROFILES "+quote(profileName))
+	return q
+}
+
+// DropProfile removes the profile from the grantee, leaving its other settings and profiles untouched.
+func (q *alterGranteeQueryBuilder) DropProfile(profileName string) AlterGranteeQueryBuilder {
+	q.clauses = append(q.clauses, "DROP PROFILES "+quote(profileName))
+	return q
+}
+
+// SetProfiles replaces the settings of the grantee with the given profiles, later profiles overriding the settings of
+// the earlier ones. An empty list removes every setting and profile of the grantee.
+func (q *alterGranteeQueryBuilder) SetProfiles(profileNames []string) AlterGranteeQueryBuilder {
+	if len(profileNames) == 0 {
+		q.clauses = append(q.clauses, "SETTINGS NONE")
+		return q
+	}
+
+	profiles := make([]string, 0, len(profileNames))
+	for _, profileName := range profileNames {
+		profiles = append(profiles, "PROFILE "+quote(profileName))
+	}
+	q.clauses = append(q.clauses, "SETTINGS "+strings.Join(profiles, ", "))
+	return q
+}
+
+func (q *alterGranteeQueryBuilder) WithCluster(clusterName *string) AlterGranteeQueryBuilder {
+	q.clusterName = clusterName
+	return q
+}
+
+func (q *alterGranteeQueryBuilder) Build() (string, error) {
+	if q.name == "" {
+		return "", errors.New("name cannot be empty for ALTER " + q.kind + " queries")
+	}
+	if len(q.clauses) == 0 {
+		return "", errors.New("at least one change is required for ALTER " + q.kind + " queries")
+	}
+
+	tokens := []string{
+		"ALTER",
+		q.kind,
+		backtick(q.name),
+	}
+	if q.clusterName != nil {
+		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
+	}
+	tokens = append(tokens, q.clauses...)
+
+	return strings.Join(tokens, " ") + ";", nil
+}
@@ -0,0 +1,63 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func Test_alterGranteeQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder AlterGranteeQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Add profile to user",
+			builder: NewAlterUser("alice").AddProfile("readonly"),
+			want:    "ALTER USER `alice` ADD PROFILES 'readonly';",
+			wantErr: false,
+		},
+		{
+			name:    "Drop profile from role on cluster",
+			builder: NewAlterRole("reader").DropProfile("readonly").WithCluster(stringPtr("cluster1")),
+			want:    "ALTER ROLE `reader` ON CLUSTER 'cluster1' DROP PROFILES 'readonly';",
+			wantErr: false,
+		},
+		{
+			name:    "Set profile chain on user",
+			builder: NewAlterUser("alice").SetProfiles([]string{"base", "analyst"}),
+			want:    "ALTER USER `alice` SETTINGS PROFILE 'base', PROFILE 'analyst';",
+			wantErr: false,
+		},
+		{
+			name:    "Clear profiles of role on cluster",
+			builder: NewAlterRole("reader").SetProfiles(nil).WithCluster(stringPtr("cluster1")),
+			want:    "ALTER ROLE `reader` ON CLUSTER 'cluster1' SETTINGS NONE;",
+			wantErr: false,
+		},
+		{
+			name:    "Empty name",
+			builder: NewAlterUser("").AddProfile("readonly"),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "No changes",
+			builder: NewAlterRole("reader"),
+			want:    "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Build() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func TestAlterTableAddIndexQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *AlterTableAddIndexQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "single index with granularity",
+			builder: NewAlterTableAddIndex("mydb", "mytable", []TableIndex{
+				{Name: "idx_user", Expression: "user_id", Type: "minmax", Granularity: 4},
+			}),
+			want:    "ALTER TABLE `mydb`.`mytable` ADD INDEX `idx_user` user_id TYPE minmax GRANULARITY 4",
+			wantErr: false,
+		},
+		{
+			name: "multiple indexes on cluster",
+			builder: NewAlterTableAddIndex("mydb", "mytable", []TableIndex{
+				{Name: "idx_a", Expression: "lower(a)", Type: "bloom_filter(0.01)"},
+				{Name: "idx_b", Expression: "b", Type: "set(100)", Granularity: 1},
+			}).WithCluster(stringPtr("my_cluster")),
+			want:    "ALTER TABLE `mydb`.`mytable` ON CLUSTER 'my_cluster' ADD INDEX `idx_a` lower(a) TYPE bloom_filter(0.01), ADD INDEX `idx_b` b TYPE set(100) GRANULARITY 1",
+			wantErr: false,
+		},
+		{
+			name: "error: missing type",
+			builder: NewAlterTableAddIndex("mydb", "mytable", []TableIndex{
+				{Name: "idx", Expression: "a"},
+			}),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "error: no indexes",
+			builder: NewAlterTableAddIndex("mydb", "mytable", nil),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name: "error: empty table name",
+			builder: NewAlterTableAddIndex("mydb", "", []TableIndex{
+				{Name: "idx", Expression: "a", Type: "minmax"},
+			}),
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AlterTableAddIndexQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("AlterTableAddIndexQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlterTableDropIndexQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *AlterTableDropIndexQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "drop multiple indexes",
+			builder: NewAlterTableDropIndex("mydb", "mytable", []string{"idx_a", "idx`b"}),
+			want:    "ALTER TABLE `mydb`.`mytable` DROP INDEX `idx_a`, DROP INDEX `idx\\`b`",
+			wantErr: false,
+		},
+		{
+			name:    "drop index on cluster",
+			builder: NewAlterTableDropIndex("mydb", "mytable", []string{"idx_a"}).WithCluster(stringPtr("my_cluster")),
+			want:    "ALTER TABLE `mydb`.`mytable` ON CLUSTER 'my_cluster' DROP INDEX `idx_a`",
+			wantErr: false,
+		},
+		{
+			name:    "error: no index names",
+			builder: NewAlterTableDropIndex("mydb", "mytable", []string{}),
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AlterTableDropIndexQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("AlterTableDropIndexQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlterTableMaterializeIndexQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *AlterTableMaterializeIndexQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "materialize index",
+			builder: NewAlterTableMaterializeIndex("mydb", "mytable", "idx_a"),
+			want:    "ALTER TABLE `mydb`.`mytable` MATERIALIZE INDEX `idx_a`",
+			wantErr: false,
+		},
+		{
+			name:    "materialize index in partition on cluster",
+			builder: NewAlterTableMaterializeIndex("mydb", "mytable", "idx_a").InPartition(stringPtr("202401")).WithCluster(stringPtr("my_cluster")),
+			want:    "ALTER TABLE `mydb`.`mytable` ON CLUSTER 'my_cluster' MATERIALIZE INDEX `idx_a` IN PARTITION 202401",
+			wantErr: false,
+		},
+		{
+			name:    "error: empty index name",
+			builder: NewAlterTableMaterializeIndex("mydb", "mytable", ""),
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AlterTableMaterializeIndexQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("AlterTableMaterializeIndexQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package querybuilder
+
+import (
+	"github.com/pingcap/errors"
+)
+
+// AlterTableDropPartitionQueryBuilder builds ALTER TABLE DROP PARTITION queries
+type AlterTableDropPartitionQueryBuilder struct {
+	databaseName string
+	tableName    string
+	partitionIDs []string
+	clusterName  *string
+}
+
+// NewAlterTableDropPartition creates a new ALTER TABLE DROP PARTITION query builder dropping partitions by ID,
+// as found in the partition_id column of system.parts.
+func NewAlterTableDropPartition(databaseName, tableName string, partitionIDs []string) *AlterTableDropPartitionQueryBuilder {
+	return &AlterTableDropPartitionQueryBuilder{
+		databaseName: databaseName,
+		tableName:    tableName,
+		partitionIDs: partitionIDs,
+	}
+}
+
+// WithCluster adds ON CLUSTER clause
+func (b *AlterTableDropPartitionQueryBuilder) WithCluster(clusterName *string) *AlterTableDropPartitionQueryBuilder {
+	b.clusterName = clusterName
+	return b
+}
+
+// Build generates the ALTER TABLE DROP PARTITION SQL query
+func (b *AlterTableDropPartitionQueryBuilder) Build() (string, error) {
+	if len(b.partitionIDs) == 0 {
+		return "", errors.New("at least one partition ID is required")
+	}
+
+	clauses := make([]string, 0, len(b.partitionIDs))
+	for _, id := range b.partitionIDs {
+		if id == "" {
+			return "", errors.New("partition ID is required")
+		}
+		clauses = append(clauses, "DROP PARTITION ID "+quote(id))
+	}
+
+	return alterTable(b.databaseName, b.tableName, b.clusterName, clauses)
+}
+
+// AlterTableDropDetachedPartitionQueryBuilder builds ALTER TABLE DROP DETACHED PARTITION queries
+type AlterTableDropDetachedPartitionQueryBuilder struct {
+	databaseName string
+	tableName    string
+	partitionIDs []string
+	clusterName  *string
+}
+
+// NewAlterTableDropDetachedPartition creates a new ALTER TABLE DROP DETACHED PARTITION query builder deleting the
+// detached parts of partitions by ID, as found in the partition_id column of system.detached_parts. ClickHouse only
+// runs the query with the allow_drop_detached setting enabled.
+func NewAlterTableDropDetachedPartition(databaseName, tableName string, partitionIDs []string) *AlterTableDropDetachedPartitionQueryBuilder {
+	return &AlterTableDropDetachedPartitionQueryBuilder{
+		databaseName: databaseName,
+		tableName:    tableName,
+		partitionIDs: partitionIDs,
+	}
+}
+
+// WithCluster adds ON CLUSTER clause
+func (b *AlterTableDropDetachedPartitionQueryBuilder) WithCluster(clusterName *string) *AlterTableDropDetachedPartitionQueryBuilder {
+	b.clusterName = clusterName
+	return b
+}
+
+// Build generates the ALTER TABLE DROP DETACHED PARTITION SQL query
+func (b *AlterTableDropDetachedPartitionQueryBuilder) Build() (string, error) {
+	if len(b.partitionIDs) == 0 {
+		return "", errors.New("at least one partition ID is required")
+	}
+
+	clauses := make([]string, 0, len(b.partitionIDs))
+	for _, id := range b.partitionIDs {
+		if id == "" {
+			return "", errors.New("partition ID is required")
+		}
+		clauses = append(clauses, "DROP DETACHED PARTITION ID "+quote(id))
+	}
+
+	return alterTable(b.databaseName, b.tableName, b.clusterName, clauses)
+}
+
+// AlterTableFreezeQueryBuilder builds ALTER TABLE FREEZE queries
+type AlterTableFreezeQueryBuilder struct {
+	databaseName string
+	tableName    string
+	snapshotName string
+	partition    *string
+	clusterName  *string
+}
+
+// NewAlterTableFreeze creates a new ALTER TABLE FREEZE query builder. The snapshot is stored by ClickHouse under
+// the shadow/<snapshotName>/ directory of each disk.
+func NewAlterTableFreeze(databaseName, tableName, snapshotName string) *AlterTableFreezeQueryBuilder {
+	return &AlterTableFreezeQueryBuilder{
+		databaseName: databaseName,
+		tableName:    tableName,
+		snapshotName: snapshotName,
+	}
+}
+
+// WithCluster adds ON CLUSTER clause
+func (b *AlterTableFreezeQueryBuilder) WithCluster(clusterName *string) *AlterTableFreezeQueryBuilder {
+	b.clusterName = clusterName
+	return b
+}
+
+// WithPartition restricts the snapshot to a single partition. The expression is emitted as-is.
+func (b *AlterTableFreezeQueryBuilder) WithPartition(partition *string) *AlterTableFreezeQueryBuilder {
+	b.partition = partition
+	return b
+}
+
+// Build generates the ALTER TABLE FREEZE SQL query
+func (b *AlterTableFreezeQueryBuilder) Build() (string, error) {
+	if b.snapshotName == "" {
+		return "", errors.New("snapshot name is required")
+	}
+
+	clause := "FREEZE"
+	if b.partition != nil {
+		if *b.partition == "" {
+			return "", errors.New("partition cannot be empty")
+		}
+		clause += " PARTITION " + *b.partition
+	}
+	clause += " WITH NAME " + quote(b.snapshotName)
+
+	return alterTable(b.databaseName, b.tableName, b.clusterName, []string{clause})
+}
@@ -0,0 +1,152 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func TestAlterTableDropPartitionQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *AlterTableDropPartitionQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "single partition",
+			builder: NewAlterTableDropPartition("mydb", "mytable", []string{"202401"}),
+			want:    "ALTER TABLE `mydb`.`mytable` DROP PARTITION ID '202401'",
+			wantErr: false,
+		},
+		{
+			name:    "multiple partitions on cluster",
+			builder: NewAlterTableDropPartition("mydb", "mytable", []string{"202401", "202402"}).WithCluster(stringPtr("my_cluster")),
+			want:    "ALTER TABLE `mydb`.`mytable` ON CLUSTER 'my_cluster' DROP PARTITION ID '202401', DROP PARTITION ID '202402'",
+			wantErr: false,
+		},
+		{
+			name:    "partition ID with quote",
+			builder: NewAlterTableDropPartition("mydb", "mytable", []string{"a'b"}),
+			want:    "ALTER TABLE `mydb`.`mytable` DROP PARTITION ID 'a\\'b'",
+			wantErr: false,
+		},
+		{
+			name:    "error: no partitions",
+			builder: NewAlterTableDropPartition("mydb", "mytable", nil),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "error: empty partition ID",
+			builder: NewAlterTableDropPartition("mydb", "mytable", []string{""}),
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AlterTableDropPartitionQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("AlterTableDropPartitionQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlterTableDropDetachedPartitionQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *AlterTableDropDetachedPartitionQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "single partition",
+			builder: NewAlterTableDropDetachedPartition("mydb", "mytable", []string{"202401"}),
+			want:    "ALTER TABLE `mydb`.`mytable` DROP DETACHED PARTITION ID '202401'",
+			wantErr: false,
+		},
+		{
+			name:    "multiple partitions on cluster",
+			builder: NewAlterTableDropDetachedPartition("mydb", "mytable", []string{"202401", "202402"}).WithCluster(stringPtr("my_cluster")),
+			want:    "ALTER TABLE `mydb`.`mytable` ON CLUSTER 'my_cluster' DROP DETACHED PARTITION ID '202401', DROP DETACHED PARTITION ID '202402'",
+			wantErr: false,
+		},
+		{
+			name:    "error: no partitions",
+			builder: NewAlterTableDropDetachedPartition("mydb", "mytable", nil),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "error: empty partition ID",
+			builder: NewAlterTableDropDetachedPartition("mydb", "mytable", []string{""}),
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AlterTableDropDetachedPartitionQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("AlterTableDropDetachedPartitionQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlterTableFreezeQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *AlterTableFreezeQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "whole table",
+			builder: NewAlterTableFreeze("mydb", "mytable", "before_migration"),
+			want:    "ALTER TABLE `mydb`.`mytable` FREEZE WITH NAME 'before_migration'",
+			wantErr: false,
+		},
+		{
+			name:    "single partition on cluster",
+			builder: NewAlterTableFreeze("mydb", "mytable", "before_migration").WithPartition(stringPtr("'202401'")).WithCluster(stringPtr("my_cluster")),
+			want:    "ALTER TABLE `mydb`.`mytable` ON CLUSTER 'my_cluster' FREEZE PARTITION '202401' WITH NAME 'before_migration'",
+			wantErr: false,
+		},
+		{
+			name:    "error: empty snapshot name",
+			builder: NewAlterTableFreeze("mydb", "mytable", ""),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "error: empty partition",
+			builder: NewAlterTableFreeze("mydb", "mytable", "before_migration").WithPartition(stringPtr("")),
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AlterTableFreezeQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("AlterTableFreezeQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
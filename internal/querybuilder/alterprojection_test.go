@@ -0,0 +1,129 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func TestAlterTableAddProjectionQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *AlterTableAddProjectionQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "single projection",
+			builder: NewAlterTableAddProjection("mydb", "mytable", []TableProjection{
+				{Name: "by_user", Query: "SELECT * ORDER BY user_id"},
+			}),
+			want:    "ALTER TABLE `mydb`.`mytable` ADD PROJECTION `by_user` (SELECT * ORDER BY user_id)",
+			wantErr: false,
+		},
+		{
+			name: "multiple projections on cluster",
+			builder: NewAlterTableAddProjection("mydb", "mytable", []TableProjection{
+				{Name: "by_user", Query: "SELECT * ORDER BY user_id"},
+				{Name: "daily", Query: "SELECT toDate(ts), count() GROUP BY toDate(ts)"},
+			}).WithCluster(stringPtr("my_cluster")),
+			want:    "ALTER TABLE `mydb`.`mytable` ON CLUSTER 'my_cluster' ADD PROJECTION `by_user` (SELECT * ORDER BY user_id), ADD PROJECTION `daily` (SELECT toDate(ts), count() GROUP BY toDate(ts))",
+			wantErr: false,
+		},
+		{
+			name: "error: missing query",
+			builder: NewAlterTableAddProjection("mydb", "mytable", []TableProjection{
+				{Name: "p"},
+			}),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "error: no projections",
+			builder: NewAlterTableAddProjection("mydb", "mytable", nil),
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AlterTableAddProjectionQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("AlterTableAddProjectionQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlterTableDropProjectionQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *AlterTableDropProjectionQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "drop projections on cluster",
+			builder: NewAlterTableDropProjection("mydb", "mytable", []string{"by_user", "daily"}).WithCluster(stringPtr("my_cluster")),
+			want:    "ALTER TABLE `mydb`.`mytable` ON CLUSTER 'my_cluster' DROP PROJECTION `by_user`, DROP PROJECTION `daily`",
+			wantErr: false,
+		},
+		{
+			name:    "error: empty projection name",
+			builder: NewAlterTableDropProjection("mydb", "mytable", []string{""}),
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AlterTableDropProjectionQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("AlterTableDropProjectionQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlterTableMaterializeProjectionQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *AlterTableMaterializeProjectionQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "materialize projection",
+			builder: NewAlterTableMaterializeProjection("mydb", "mytable", "by_user"),
+			want:    "ALTER TABLE `mydb`.`mytable` MATERIALIZE PROJECTION `by_user`",
+			wantErr: false,
+		},
+		{
+			name:    "error: empty projection name",
+			builder: NewAlterTableMaterializeProjection("mydb", "mytable", ""),
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AlterTableMaterializeProjectionQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("AlterTableMaterializeProjectionQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
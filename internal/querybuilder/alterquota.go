@@ -0,0 +1,64 @@
+package querybuilder
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// AlterQuotaQueryBuilder is an interface to build ALTER QUOTA SQL queries (already interpolated).
+type AlterQuotaQueryBuilder interface {
+	QueryBuilder
+	WithCluster(clusterName *string) AlterQuotaQueryBuilder
+}
+
+type alterQuotaQueryBuilder struct {
+	quotaName   string
+	grantees    []string
+	clusterName *string
+}
+
+// NewAlterQuotaApplyTo builds an ALTER QUOTA ... TO query, which replaces the whole list of users and roles the quota
+// applies to. An empty list of grantees detaches the quota from everyone.
+func NewAlterQuotaApplyTo(quotaName string, grantees []string) AlterQuotaQueryBuilder {
+	return &alterQuotaQueryBuilder{
+		quotaName: quotaName,
+		grantees:  grantees,
+	}
+}
+
+func (q *alterQuotaQueryBuilder) WithCluster(clusterName *string) AlterQuotaQueryBuilder {
+	q.clusterName = clusterName
+	return q
+}
+
+func (q *alterQuotaQueryBuilder) Build() (string, error) {
+	if q.quotaName == "" {
+		return "", errors.New("quotaName cannot be empty for ALTER QUOTA queries")
+	}
+
+	tokens := []string{
+		"ALTER",
+		"QUOTA",
+		backtick(q.quotaName),
+	}
+	if q.clusterName != nil {
+		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
+	}
+	tokens = append(tokens, "TO")
+
+	if len(q.grantees) == 0 {
+		tokens = append(tokens, "NONE")
+	} else {
+		grantees := make([]string, 0, len(q.grantees))
+		for _, grantee := range q.grantees {
+			if grantee == "" {
+				return "", errors.New("grantee names cannot be empty for ALTER QUOTA queries")
+			}
+			grantees = append(grantees, backtick(grantee))
+		}
+		tokens = append(tokens, strings.Join(grantees, ", "))
+	}
+
+	return strings.Join(tokens, " ") + ";", nil
+}
@@ -0,0 +1,57 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func Test_alterQuotaQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder AlterQuotaQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Single grantee",
+			builder: NewAlterQuotaApplyTo("limited", []string{"alice"}),
+			want:    "ALTER QUOTA `limited` TO `alice`;",
+			wantErr: false,
+		},
+		{
+			name:    "Multiple grantees on cluster",
+			builder: NewAlterQuotaApplyTo("limited", []string{"alice", "reader"}).WithCluster(stringPtr("cluster1")),
+			want:    "ALTER QUOTA `limited` ON CLUSTER 'cluster1' TO `alice`, `reader`;",
+			wantErr: false,
+		},
+		{
+			name:    "No grantees",
+			builder: NewAlterQuotaApplyTo("limited", nil),
+			want:    "ALTER QUOTA `limited` TO NONE;",
+			wantErr: false,
+		},
+		{
+			name:    "Empty quota name",
+			builder: NewAlterQuotaApplyTo("", []string{"alice"}),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "Empty grantee name",
+			builder: NewAlterQuotaApplyTo("limited", []string{""}),
+			want:    "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Build() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package querybuilder
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/pingcap/errors"
@@ -63,15 +65,29 @@ func (b *AlterTableAddColumnQueryBuilder) Build() (string, error) {
 		// Column name and type
 		sb.WriteString(fmt.Sprintf("`%s` %s", col.Name, col.Type))
 		
-		// DEFAULT expression
+		// DEFAULT / MATERIALIZED / ALIAS / EPHEMERAL expression
 		if col.Default != nil && *col.Default != "" {
-			sb.WriteString(fmt.Sprintf(" DEFAULT %s", *col.Default))
+			sb.WriteString(fmt.Sprintf(" %s %s", col.defaultKind(), *col.Default))
+		} else if col.defaultKind() == ColumnDefaultKindEphemeral {
+			sb.WriteString(" EPHEMERAL")
 		}
 		
 		// COMMENT
 		if col.Comment != nil && *col.Comment != "" {
 			sb.WriteString(fmt.Sprintf(" COMMENT %s", quote(*col.Comment)))
 		}
+
+		// CODEC
+		if col.Codec != nil && *col.Codec != "" {
+			sb.WriteString(fmt.Sprintf(" CODEC(%s)", *col.Codec))
+		}
+
+		// FIRST / AFTER
+		if col.First {
+			sb.WriteString(" FIRST")
+		} else if col.After != nil {
+			sb.WriteString(" AFTER " + backtick(*col.After))
+		}
 	}
 	
 	return sb.String(), nil
@@ -133,4 +149,242 @@ func (b *AlterTableDropColumnQueryBuilder) Build() (string, error) {
 	}
 	
 	return sb.String(), nil
-}
\ No newline at end of file
+}
+
+// AlterTableModifyColumnQueryBuilder builds ALTER TABLE MODIFY COLUMN queries changing the type of columns
+type AlterTableModifyColumnQueryBuilder struct {
+	databaseName string
+	tableName    string
+	columns      []TableColumn
+	clusterName  *string
+}
+
+// NewAlterTableModifyColumn creates a new ALTER TABLE MODIFY COLUMN query builder. Only the name, type and codec of
+// the columns are used: ClickHouse keeps their default expression and comment, and their codec when it isn't set.
+func NewAlterTableModifyColumn(databaseName, tableName string, columns []TableColumn) *AlterTableModifyColumnQueryBuilder {
+	return &AlterTableModifyColumnQueryBuilder{
+		databaseName: databaseName,
+		tableName:    tableName,
+		columns:      columns,
+	}
+}
+
+// WithCluster adds ON CLUSTER clause
+func (b *AlterTableModifyColumnQueryBuilder) WithCluster(clusterName *string) *AlterTableModifyColumnQueryBuilder {
+	b.clusterName = clusterName
+	return b
+}
+
+// Build generates the ALTER TABLE MODIFY COLUMN SQL query
+func (b *AlterTableModifyColumnQueryBuilder) Build() (string, error) {
+	if len(b.columns) == 0 {
+		return "", errors.New("at least one column is required")
+	}
+
+	clauses := make([]string, 0, len(b.columns))
+	for _, col := range b.columns {
+		if col.Name == "" {
+			return "", errors.New("column name is required")
+		}
+		if col.Type == "" {
+			return "", errors.New("column type is required")
+		}
+		clause := fmt.Sprintf("MODIFY COLUMN %s %s", backtick(col.Name), col.Type)
+		if col.Codec != nil {
+			clause += fmt.Sprintf(" CODEC(%s)", *col.Codec)
+		}
+		clauses = append(clauses, clause)
+	}
+
+	return alterTable(b.databaseName, b.tableName, b.clusterName, clauses)
+}
+
+// AlterTableRemoveCodecQueryBuilder builds ALTER TABLE MODIFY COLUMN ... REMOVE CODEC queries
+type AlterTableRemoveCodecQueryBuilder struct {
+	databaseName string
+	tableName    string
+	columnNames  []string
+	clusterName  *string
+}
+
+// NewAlterTableRemoveCodec creates a new ALTER TABLE MODIFY COLUMN ... REMOVE CODEC query builder, making the columns
+// use the default compression of the server again.
+func NewAlterTableRemoveCodec(databaseName, tableName string, columnNames []string) *AlterTableRemoveCodecQueryBuilder {
+	return &AlterTableRemoveCodecQueryBuilder{
+		databaseName: databaseName,
+		tableName:    tableName,
+		columnNames:  columnNames,
+	}
+}
+
+// WithCluster adds ON CLUSTER clause
+func (b *AlterTableRemoveCodecQueryBuilder) WithCluster(clusterName *string) *AlterTableRemoveCodecQueryBuilder {
+	b.clusterName = clusterName
+	return b
+}
+
+// Build generates the ALTER TABLE MODIFY COLUMN ... REMOVE CODEC SQL query
+func (b *AlterTableRemoveCodecQueryBuilder) Build() (string, error) {
+	if len(b.columnNames) == 0 {
+		return "", errors.New("at least one column name is required")
+	}
+
+	clauses := make([]string, 0, len(b.columnNames))
+	for _, name := range b.columnNames {
+		if name == "" {
+			return "", errors.New("column name is required")
+		}
+		clauses = append(clauses, fmt.Sprintf("MODIFY COLUMN %s REMOVE CODEC", backtick(name)))
+	}
+
+	return alterTable(b.databaseName, b.tableName, b.clusterName, clauses)
+}
+
+// AlterTableModifyTTLQueryBuilder builds ALTER TABLE MODIFY TTL and REMOVE TTL queries
+type AlterTableModifyTTLQueryBuilder struct {
+	databaseName string
+	tableName    string
+	ttl          *string
+	clusterName  *string
+}
+
+// NewAlterTableModifyTTL creates a new ALTER TABLE MODIFY TTL query builder. A nil or empty ttl removes the TTL of
+// the table with REMOVE TTL.
+func NewAlterTableModifyTTL(databaseName, tableName string, ttl *string) *AlterTableModifyTTLQueryBuilder {
+	return &AlterTableModifyTTLQueryBuilder{
+		databaseName: databaseName,
+		tableName:    tableName,
+		ttl:          ttl,
+	}
+}
+
+// WithCluster adds ON CLUSTER clause
+func (b *AlterTableModifyTTLQueryBuilder) WithCluster(clusterName *string) *AlterTableModifyTTLQueryBuilder {
+	b.clusterName = clusterName
+	return b
+}
+
+// Build generates the ALTER TABLE MODIFY TTL SQL query
+func (b *AlterTableModifyTTLQueryBuilder) Build() (string, error) {
+	clause := "REMOVE TTL"
+	if b.ttl != nil && *b.ttl != "" {
+		clause = "MODIFY TTL " + *b.ttl
+	}
+
+	return alterTable(b.databaseName, b.tableName, b.clusterName, []string{clause})
+}
+
+// AlterTableModifySettingQueryBuilder builds ALTER TABLE MODIFY SETTING queries
+type AlterTableModifySettingQueryBuilder struct {
+	databaseName string
+	tableName    string
+	settings     map[string]string
+	clusterName  *string
+}
+
+// NewAlterTableModifySetting creates a new ALTER TABLE MODIFY SETTING query builder. Values are rendered as given,
+// string values must be quoted.
+func NewAlterTableModifySetting(databaseName, tableName string, settings map[string]string) *AlterTableModifySettingQueryBuilder {
+	return &AlterTableModifySettingQueryBuilder{
+		databaseName: databaseName,
+		tableName:    tableName,
+		settings:     settings,
+	}
+}
+
+// WithCluster adds ON CLUSTER clause
+func (b *AlterTableModifySettingQueryBuilder) WithCluster(clusterName *string) *AlterTableModifySettingQueryBuilder {
+	b.clusterName = clusterName
+	return b
+}
+
+// Build generates the ALTER TABLE MODIFY SETTING SQL query
+func (b *AlterTableModifySettingQueryBuilder) Build() (string, error) {
+	if len(b.settings) == 0 {
+		return "", errors.New("at least one setting is required")
+	}
+
+	return alterTable(b.databaseName, b.tableName, b.clusterName, []string{"MODIFY SETTING " + settingsList(b.settings)})
+}
+
+// AlterTableResetSettingQueryBuilder builds ALTER TABLE RESET SETTING queries
+type AlterTableResetSettingQueryBuilder struct {
+	databaseName string
+	tableName    string
+	settingNames []string
+	clusterName  *string
+}
+
+// NewAlterTableResetSetting creates a new ALTER TABLE RESET SETTING query builder, setting the settings back to
+// their default value.
+func NewAlterTableResetSetting(databaseName, tableName string, settingNames []string) *AlterTableResetSettingQueryBuilder {
+	return &AlterTableResetSettingQueryBuilder{
+		databaseName: databaseName,
+		tableName:    tableName,
+		settingNames: settingNames,
+	}
+}
+
+// WithCluster adds ON CLUSTER clause
+func (b *AlterTableResetSettingQueryBuilder) WithCluster(clusterName *string) *AlterTableResetSettingQueryBuilder {
+	b.clusterName = clusterName
+	return b
+}
+
+// Build generates the ALTER TABLE RESET SETTING SQL query
+func (b *AlterTableResetSettingQueryBuilder) Build() (string, error) {
+	if len(b.settingNames) == 0 {
+		return "", errors.New("at least one setting name is required")
+	}
+
+	names := slices.Sorted(slices.Values(b.settingNames))
+	for _, name := range names {
+		if name == "" {
+			return "", errors.New("setting name is required")
+		}
+	}
+
+	return alterTable(b.databaseName, b.tableName, b.clusterName, []string{"RESET SETTING " + strings.Join(names, ", ")})
+}
+
+// AlterTableRenameColumnQueryBuilder builds ALTER TABLE RENAME COLUMN queries
+type AlterTableRenameColumnQueryBuilder struct {
+	databaseName string
+	tableName    string
+	renames      map[string]string
+	clusterName  *string
+}
+
+// NewAlterTableRenameColumn creates a new ALTER TABLE RENAME COLUMN query builder, renaming each column of renames
+// to the name it maps to.
+func NewAlterTableRenameColumn(databaseName, tableName string, renames map[string]string) *AlterTableRenameColumnQueryBuilder {
+	return &AlterTableRenameColumnQueryBuilder{
+		databaseName: databaseName,
+		tableName:    tableName,
+		renames:      renames,
+	}
+}
+
+// WithCluster adds ON CLUSTER clause
+func (b *AlterTableRenameColumnQueryBuilder) WithCluster(clusterName *string) *AlterTableRenameColumnQueryBuilder {
+	b.clusterName = clusterName
+	return b
+}
+
+// Build generates the ALTER TABLE RENAME COLUMN SQL query
+func (b *AlterTableRenameColumnQueryBuilder) Build() (string, error) {
+	if len(b.renames) == 0 {
+		return "", errors.New("at least one column rename is required")
+	}
+
+	clauses := make([]string, 0, len(b.renames))
+	for _, from := range slices.Sorted(maps.Keys(b.renames)) {
+		to := b.renames[from]
+		if from == "" || to == "" {
+			return "", errors.New("column names are required")
+		}
+		clauses = append(clauses, fmt.Sprintf("RENAME COLUMN %s TO %s", backtick(from), backtick(to)))
+	}
+
+	return alterTable(b.databaseName, b.tableName, b.clusterName, clauses)
+}
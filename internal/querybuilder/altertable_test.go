@@ -37,6 +37,32 @@ func TestAlterTableAddColumnQueryBuilder_Build(t *testing.T) {
 			want:    "ALTER TABLE `mydb`.`mytable` ADD COLUMN `col1` UInt64, ADD COLUMN `col2` String DEFAULT '', ADD COLUMN `col3` Float64 COMMENT 'Score value'",
 			wantErr: false,
 		},
+		{
+			name: "column with alias",
+			builder: NewAlterTableAddColumn("mydb", "mytable", []TableColumn{
+				{Name: "full_name", Type: "String", Default: stringPtr("concat(first, last)"), DefaultKind: stringPtr(ColumnDefaultKindAlias)},
+			}),
+			want:    "ALTER TABLE `mydb`.`mytable` ADD COLUMN `full_name` String ALIAS concat(first, last)",
+			wantErr: false,
+		},
+		{
+			name: "column with codec",
+			builder: NewAlterTableAddColumn("mydb", "mytable", []TableColumn{
+				{Name: "payload", Type: "String", Codec: stringPtr("ZSTD(3)")},
+			}),
+			want:    "ALTER TABLE `mydb`.`mytable` ADD COLUMN `payload` String CODEC(ZSTD(3))",
+			wantErr: false,
+		},
+		{
+			name: "columns chained after each other",
+			builder: NewAlterTableAddColumn("mydb", "mytable", []TableColumn{
+				{Name: "id", Type: "UInt64", First: true},
+				{Name: "a", Type: "String", After: stringPtr("name")},
+				{Name: "b", Type: "String", After: stringPtr("a")},
+			}),
+			want:    "ALTER TABLE `mydb`.`mytable` ADD COLUMN `id` UInt64 FIRST, ADD COLUMN `a` String AFTER `name`, ADD COLUMN `b` String AFTER `a`",
+			wantErr: false,
+		},
 		{
 			name: "with cluster",
 			builder: NewAlterTableAddColumn("mydb", "mytable", []TableColumn{
@@ -140,4 +166,268 @@ func TestAlterTableDropColumnQueryBuilder_Build(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestAlterTableModifyColumnQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *AlterTableModifyColumnQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "single column",
+			builder: NewAlterTableModifyColumn("mydb", "mytable", []TableColumn{{Name: "id", Type: "UInt64"}}),
+			want:    "ALTER TABLE `mydb`.`mytable` MODIFY COLUMN `id` UInt64",
+			wantErr: false,
+		},
+		{
+			name: "multiple columns with cluster",
+			builder: NewAlterTableModifyColumn("mydb", "mytable", []TableColumn{
+				{Name: "id", Type: "UInt64"},
+				{Name: "score", Type: "Nullable(Float64)"},
+			}).WithCluster(stringPtr("my_cluster")),
+			want:    "ALTER TABLE `mydb`.`mytable` ON CLUSTER 'my_cluster' MODIFY COLUMN `id` UInt64, MODIFY COLUMN `score` Nullable(Float64)",
+			wantErr: false,
+		},
+		{
+			name:    "column with codec",
+			builder: NewAlterTableModifyColumn("mydb", "mytable", []TableColumn{{Name: "ts", Type: "DateTime", Codec: stringPtr("DoubleDelta, ZSTD(3)")}}),
+			want:    "ALTER TABLE `mydb`.`mytable` MODIFY COLUMN `ts` DateTime CODEC(DoubleDelta, ZSTD(3))",
+			wantErr: false,
+		},
+		{
+			name:    "error: empty table name",
+			builder: NewAlterTableModifyColumn("mydb", "", []TableColumn{{Name: "id", Type: "UInt64"}}),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "error: no columns",
+			builder: NewAlterTableModifyColumn("mydb", "mytable", nil),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "error: empty type",
+			builder: NewAlterTableModifyColumn("mydb", "mytable", []TableColumn{{Name: "id"}}),
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AlterTableModifyColumnQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("AlterTableModifyColumnQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlterTableModifyTTLQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *AlterTableModifyTTLQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "modify ttl",
+			builder: NewAlterTableModifyTTL("mydb", "mytable", stringPtr("ts + INTERVAL 30 DAY")),
+			want:    "ALTER TABLE `mydb`.`mytable` MODIFY TTL ts + INTERVAL 30 DAY",
+			wantErr: false,
+		},
+		{
+			name:    "modify ttl with cluster",
+			builder: NewAlterTableModifyTTL("mydb", "mytable", stringPtr("ts + INTERVAL 1 DAY DELETE WHERE level = 'DEBUG'")).WithCluster(stringPtr("my_cluster")),
+			want:    "ALTER TABLE `mydb`.`mytable` ON CLUSTER 'my_cluster' MODIFY TTL ts + INTERVAL 1 DAY DELETE WHERE level = 'DEBUG'",
+			wantErr: false,
+		},
+		{
+			name:    "remove ttl",
+			builder: NewAlterTableModifyTTL("mydb", "mytable", nil),
+			want:    "ALTER TABLE `mydb`.`mytable` REMOVE TTL",
+			wantErr: false,
+		},
+		{
+			name:    "error: empty table name",
+			builder: NewAlterTableModifyTTL("mydb", "", nil),
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AlterTableModifyTTLQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("AlterTableModifyTTLQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlterTableModifySettingQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *AlterTableModifySettingQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "settings sorted by name",
+			builder: NewAlterTableModifySetting("mydb", "mytable", map[string]string{
+				"merge_with_ttl_timeout": "3600",
+				"storage_policy":         "'tiered'",
+			}).WithCluster(stringPtr("my_cluster")),
+			want:    "ALTER TABLE `mydb`.`mytable` ON CLUSTER 'my_cluster' MODIFY SETTING merge_with_ttl_timeout = 3600, storage_policy = 'tiered'",
+			wantErr: false,
+		},
+		{
+			name:    "error: no settings",
+			builder: NewAlterTableModifySetting("mydb", "mytable", nil),
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AlterTableModifySettingQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("AlterTableModifySettingQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlterTableResetSettingQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *AlterTableResetSettingQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "settings sorted by name",
+			builder: NewAlterTableResetSetting("mydb", "mytable", []string{"ttl_only_drop_parts", "merge_with_ttl_timeout"}),
+			want:    "ALTER TABLE `mydb`.`mytable` RESET SETTING merge_with_ttl_timeout, ttl_only_drop_parts",
+			wantErr: false,
+		},
+		{
+			name:    "error: no settings",
+			builder: NewAlterTableResetSetting("mydb", "mytable", nil),
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AlterTableResetSettingQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("AlterTableResetSettingQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlterTableRenameColumnQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *AlterTableRenameColumnQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "single column",
+			builder: NewAlterTableRenameColumn("mydb", "mytable", map[string]string{"user": "user_id"}),
+			want:    "ALTER TABLE `mydb`.`mytable` RENAME COLUMN `user` TO `user_id`",
+			wantErr: false,
+		},
+		{
+			name:    "columns sorted by name, on cluster",
+			builder: NewAlterTableRenameColumn("mydb", "mytable", map[string]string{"ts": "timestamp", "msg": "message"}).WithCluster(stringPtr("cluster1")),
+			want:    "ALTER TABLE `mydb`.`mytable` ON CLUSTER 'cluster1' RENAME COLUMN `msg` TO `message`, RENAME COLUMN `ts` TO `timestamp`",
+			wantErr: false,
+		},
+		{
+			name:    "error: no renames",
+			builder: NewAlterTableRenameColumn("mydb", "mytable", nil),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "error: empty new name",
+			builder: NewAlterTableRenameColumn("mydb", "mytable", map[string]string{"user": ""}),
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AlterTableRenameColumnQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("AlterTableRenameColumnQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlterTableRemoveCodecQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *AlterTableRemoveCodecQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "multiple columns",
+			builder: NewAlterTableRemoveCodec("mydb", "mytable", []string{"ts", "payload"}),
+			want:    "ALTER TABLE `mydb`.`mytable` MODIFY COLUMN `ts` REMOVE CODEC, MODIFY COLUMN `payload` REMOVE CODEC",
+			wantErr: false,
+		},
+		{
+			name:    "error: no columns",
+			builder: NewAlterTableRemoveCodec("mydb", "mytable", nil),
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AlterTableRemoveCodecQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("AlterTableRemoveCodecQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -24,3 +24,14 @@ func (s *andWhere) Clause() string {
 
 	return fmt.Sprintf("(%s)", strings.Join(tokens, " AND "))
 }
+
+func (s *andWhere) parameters() []Parameter {
+	ret := make([]Parameter, 0)
+	for _, c := range s.clauses {
+		if p, ok := c.(parameterized); ok {
+			ret = append(ret, p.parameters()...)
+		}
+	}
+
+	return ret
+}
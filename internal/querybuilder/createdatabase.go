@@ -10,13 +10,19 @@ import (
 type CreateDatabaseQueryBuilder interface {
 	QueryBuilder
 	WithComment(comment string) CreateDatabaseQueryBuilder
+	WithEngine(engine string) CreateDatabaseQueryBuilder
+	WithSettings(settings map[string]string) CreateDatabaseQueryBuilder
 	WithCluster(clusterName *string) CreateDatabaseQueryBuilder
+	WithIfNotExists() CreateDatabaseQueryBuilder
 }
 
 type createDatabaseQueryBuilder struct {
 	databaseName string
 	comment      *string
+	engine       *string
+	settings     map[string]string
 	clusterName  *string
+	ifNotExists  bool
 }
 
 func NewCreateDatabase(name string) CreateDatabaseQueryBuilder {
@@ -30,11 +36,30 @@ func (q *createDatabaseQueryBuilder) WithComment(comment string) CreateDatabaseQ
 	return q
 }
 
+// WithEngine sets the database engine, e.g. MaterializedPostgreSQL('host:5432', 'db', 'user', 'password'). It is
+// emitted as-is.
+func (q *createDatabaseQueryBuilder) WithEngine(engine string) CreateDatabaseQueryBuilder {
+	q.engine = &engine
+	return q
+}
+
+// WithSettings sets the engine settings, values are emitted as-is.
+func (q *createDatabaseQueryBuilder) WithSettings(settings map[string]string) CreateDatabaseQueryBuilder {
+	q.settings = settings
+	return q
+}
+
 func (q *createDatabaseQueryBuilder) WithCluster(clusterName *string) CreateDatabaseQueryBuilder {
 	q.clusterName = clusterName
 	return q
 }
 
+// WithIfNotExists makes the query a no-op when the database already exists.
+func (q *createDatabaseQueryBuilder) WithIfNotExists() CreateDatabaseQueryBuilder {
+	q.ifNotExists = true
+	return q
+}
+
 func (q *createDatabaseQueryBuilder) Build() (string, error) {
 	if q.databaseName == "" {
 		return "", errors.New("databaseName cannot be empty for CREATE DATABASE queries")
@@ -43,11 +68,23 @@ func (q *createDatabaseQueryBuilder) Build() (string, error) {
 	tokens := []string{
 		"CREATE",
 		"DATABASE",
-		backtick(q.databaseName),
 	}
+	if q.ifNotExists {
+		tokens = append(tokens, "IF", "NOT", "EXISTS")
+	}
+	tokens = append(tokens, backtick(q.databaseName))
 	if q.clusterName != nil {
 		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
 	}
+	if q.engine != nil {
+		tokens = append(tokens, "ENGINE", "=", *q.engine)
+	}
+	if len(q.settings) > 0 {
+		if q.engine == nil {
+			return "", errors.New("settings require an engine for CREATE DATABASE queries")
+		}
+		tokens = append(tokens, "SETTINGS", settingsList(q.settings))
+	}
 	if q.comment != nil {
 		tokens = append(tokens, "COMMENT", quote(*q.comment))
 	}
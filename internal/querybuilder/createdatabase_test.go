@@ -7,14 +7,19 @@ import (
 func Test_createdatabase(t *testing.T) {
 	comment := "this is the comment"
 	clusterName := "default"
+	engine := "MaterializedPostgreSQL('postgres:5432', 'app', 'clickhouse', 'secret')"
+	replicatedEngine := "Replicated('/clickhouse/databases/{uuid}', '{shard}', '{replica}')"
 	tests := []struct {
 		name         string
 		action       string
 		resourceType string
 		resourceName string
 		comment      *string
+		engine       *string
+		settings     map[string]string
 		clusterName  *string
 		identified   string
+		ifNotExists  bool
 		want         string
 		wantErr      bool
 	}{
@@ -44,6 +49,45 @@ func Test_createdatabase(t *testing.T) {
 			want:         "CREATE DATABASE `database` ON CLUSTER 'default';",
 			wantErr:      false,
 		},
+		{
+			name:         "Create database if not exists",
+			action:       actionCreate,
+			resourceType: resourceTypeDatabase,
+			resourceName: "database",
+			clusterName:  &clusterName,
+			ifNotExists:  true,
+			want:         "CREATE DATABASE IF NOT EXISTS `database` ON CLUSTER 'default';",
+			wantErr:      false,
+		},
+		{
+			name:         "Create database with engine and settings",
+			action:       actionCreate,
+			resourceType: resourceTypeDatabase,
+			resourceName: "database",
+			clusterName:  &clusterName,
+			engine:       &engine,
+			settings:     map[string]string{"materialized_postgresql_tables_list": "'users,orders'", "materialized_postgresql_max_block_size": "8192"},
+			comment:      &comment,
+			want:         "CREATE DATABASE `database` ON CLUSTER 'default' ENGINE = MaterializedPostgreSQL('postgres:5432', 'app', 'clickhouse', 'secret') SETTINGS materialized_postgresql_max_block_size = 8192, materialized_postgresql_tables_list = 'users,orders' COMMENT 'this is the comment';",
+			wantErr:      false,
+		},
+		{
+			name:         "Create Replicated database",
+			action:       actionCreate,
+			resourceType: resourceTypeDatabase,
+			resourceName: "database",
+			engine:       &replicatedEngine,
+			want:         "CREATE DATABASE `database` ENGINE = Replicated('/clickhouse/databases/{uuid}', '{shard}', '{replica}');",
+			wantErr:      false,
+		},
+		{
+			name:         "Create database with settings and no engine",
+			action:       actionCreate,
+			resourceType: resourceTypeDatabase,
+			resourceName: "database",
+			settings:     map[string]string{"materialized_postgresql_max_block_size": "8192"},
+			wantErr:      true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -57,6 +101,15 @@ func Test_createdatabase(t *testing.T) {
 			if tt.comment != nil {
 				q = q.WithComment(*tt.comment)
 			}
+			if tt.engine != nil {
+				q = q.WithEngine(*tt.engine)
+			}
+			if tt.settings != nil {
+				q = q.WithSettings(tt.settings)
+			}
+			if tt.ifNotExists {
+				q = q.WithIfNotExists()
+			}
 
 			got, err := q.Build()
 			if (err != nil) != tt.wantErr {
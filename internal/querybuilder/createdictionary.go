@@ -0,0 +1,204 @@
+package querybuilder
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// CreateDictionaryQueryBuilder is an interface to build CREATE DICTIONARY SQL queries (already interpolated).
+type CreateDictionaryQueryBuilder interface {
+	QueryBuilder
+	WithSource(source DictionaryFunction) CreateDictionaryQueryBuilder
+	WithLayout(layout DictionaryFunction) CreateDictionaryQueryBuilder
+	WithLifetime(minSeconds, maxSeconds uint64) CreateDictionaryQueryBuilder
+	WithComment(comment string) CreateDictionaryQueryBuilder
+	WithCluster(clusterName *string) CreateDictionaryQueryBuilder
+}
+
+type createDictionaryQueryBuilder struct {
+	databaseName   string
+	dictionaryName string
+	attributes     []DictionaryAttribute
+	primaryKey     []string
+	source         *DictionaryFunction
+	layout         *DictionaryFunction
+	lifetime       *[2]uint64
+	comment        *string
+	clusterName    *string
+}
+
+// DictionaryAttribute is a column of a dictionary, key columns included.
+type DictionaryAttribute struct {
+	Name    string
+	Type    string
+	Default *string
+	// Expression is the expression computing the attribute from the columns of the source.
+	Expression   *string
+	Hierarchical bool
+	Injective    bool
+}
+
+// DictionaryFunction is the SOURCE or LAYOUT of a dictionary, e.g. CLICKHOUSE(host 'localhost' port 9000).
+type DictionaryFunction struct {
+	// Name is the source or layout type, e.g. clickhouse or complex_key_hashed. It is rendered upper case.
+	Name string
+	// Parameters are rendered sorted by name. Numeric values are rendered as is, the others are quoted.
+	Parameters map[string]string
+}
+
+var dictionaryParameterRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+func NewCreateDictionary(databaseName, dictionaryName string, attributes []DictionaryAttribute, primaryKey []string) CreateDictionaryQueryBuilder {
+	return &createDictionaryQueryBuilder{
+		databaseName:   databaseName,
+		dictionaryName: dictionaryName,
+		attributes:     attributes,
+		primaryKey:     primaryKey,
+	}
+}
+
+func (q *createDictionaryQueryBuilder) WithSource(source DictionaryFunction) CreateDictionaryQueryBuilder {
+	q.source = &source
+	return q
+}
+
+func (q *createDictionaryQueryBuilder) WithLayout(layout DictionaryFunction) CreateDictionaryQueryBuilder {
+	q.layout = &layout
+	return q
+}
+
+// WithLifetime sets the interval, in seconds, the dictionary is reloaded at. LIFETIME(n) is rendered when both bounds
+// are the same.
+func (q *createDictionaryQueryBuilder) WithLifetime(minSeconds, maxSeconds uint64) CreateDictionaryQueryBuilder {
+	q.lifetime = &[2]uint64{minSeconds, maxSeconds}
+	return q
+}
+
+func (q *createDictionaryQueryBuilder) WithComment(comment string) CreateDictionaryQueryBuilder {
+	q.comment = &comment
+	return q
+}
+
+func (q *createDictionaryQueryBuilder) WithCluster(clusterName *string) CreateDictionaryQueryBuilder {
+	q.clusterName = clusterName
+	return q
+}
+
+func (q *createDictionaryQueryBuilder) Build() (string, error) {
+	if q.databaseName == "" {
+		return "", errors.New("databaseName cannot be empty for CREATE DICTIONARY queries")
+	}
+	if q.dictionaryName == "" {
+		return "", errors.New("dictionaryName cannot be empty for CREATE DICTIONARY queries")
+	}
+	if len(q.attributes) == 0 {
+		return "", errors.New("attributes cannot be empty for CREATE DICTIONARY queries")
+	}
+	if len(q.primaryKey) == 0 {
+		return "", errors.New("primaryKey cannot be empty for CREATE DICTIONARY queries")
+	}
+	if q.source == nil {
+		return "", errors.New("source is required for CREATE DICTIONARY queries")
+	}
+	if q.layout == nil {
+		return "", errors.New("layout is required for CREATE DICTIONARY queries")
+	}
+	if q.lifetime != nil && q.lifetime[0] > q.lifetime[1] {
+		return "", errors.New("lifetime min cannot be greater than lifetime max")
+	}
+
+	tokens := []string{
+		"CREATE",
+		"DICTIONARY",
+		backtick(q.databaseName) + "." + backtick(q.dictionaryName),
+	}
+	if q.clusterName != nil {
+		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
+	}
+
+	attributes := make([]string, 0, len(q.attributes))
+	for _, attribute := range q.attributes {
+		if attribute.Name == "" || attribute.Type == "" {
+			return "", errors.New("attribute name and type cannot be empty")
+		}
+
+		definition := []string{backtick(attribute.Name), attribute.Type}
+		if attribute.Default != nil {
+			definition = append(definition, "DEFAULT", *attribute.Default)
+		}
+		if attribute.Expression != nil {
+			definition = append(definition, "EXPRESSION", *attribute.Expression)
+		}
+		if attribute.Hierarchical {
+			definition = append(definition, "HIERARCHICAL")
+		}
+		if attribute.Injective {
+			definition = append(definition, "INJECTIVE")
+		}
+		attributes = append(attributes, strings.Join(definition, " "))
+	}
+	tokens = append(tokens, "("+strings.Join(attributes, ", ")+")")
+
+	keys := make([]string, 0, len(q.primaryKey))
+	for _, key := range q.primaryKey {
+		keys = append(keys, backtick(key))
+	}
+	tokens = append(tokens, "PRIMARY", "KEY", strings.Join(keys, ", "))
+
+	source, err := q.source.build()
+	if err != nil {
+		return "", errors.WithMessage(err, "invalid source")
+	}
+	tokens = append(tokens, "SOURCE("+source+")")
+
+	layout, err := q.layout.build()
+	if err != nil {
+		return "", errors.WithMessage(err, "invalid layout")
+	}
+	tokens = append(tokens, "LAYOUT("+layout+")")
+
+	if q.lifetime != nil {
+		if q.lifetime[0] == q.lifetime[1] {
+			tokens = append(tokens, fmt.Sprintf("LIFETIME(%d)", q.lifetime[1]))
+		} else {
+			tokens = append(tokens, fmt.Sprintf("LIFETIME(MIN %d MAX %d)", q.lifetime[0], q.lifetime[1]))
+		}
+	}
+
+	if q.comment != nil {
+		tokens = append(tokens, "COMMENT", quote(*q.comment))
+	}
+
+	return strings.Join(tokens, " ") + ";", nil
+}
+
+func (f DictionaryFunction) build() (string, error) {
+	if !dictionaryParameterRegexp.MatchString(f.Name) {
+		return "", errors.New(fmt.Sprintf("invalid name %q", f.Name))
+	}
+
+	names := make([]string, 0, len(f.Parameters))
+	for name := range f.Parameters {
+		if !dictionaryParameterRegexp.MatchString(name) {
+			return "", errors.New(fmt.Sprintf("invalid parameter name %q", name))
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parameters := make([]string, 0, len(names))
+	for _, name := range names {
+		value := f.Parameters[name]
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			value = quote(value)
+		}
+		parameters = append(parameters, name+" "+value)
+	}
+
+	return strings.ToUpper(f.Name) + "(" + strings.Join(parameters, " ") + ")", nil
+}
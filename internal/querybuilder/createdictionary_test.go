@@ -0,0 +1,107 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func TestCreateDictionaryQueryBuilder_Build(t *testing.T) {
+	attributes := []DictionaryAttribute{
+		{Name: "id", Type: "UInt64"},
+		{Name: "name", Type: "String", Default: stringPtr("''")},
+	}
+	clickhouseSource := DictionaryFunction{
+		Name:       "clickhouse",
+		Parameters: map[string]string{"table": "countries", "port": "9000", "db": "mydb"},
+	}
+	hashedLayout := DictionaryFunction{Name: "hashed"}
+
+	tests := []struct {
+		name    string
+		builder CreateDictionaryQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "simple dictionary",
+			builder: NewCreateDictionary("mydb", "dict", attributes, []string{"id"}).WithSource(clickhouseSource).WithLayout(hashedLayout),
+			want:    "CREATE DICTIONARY `mydb`.`dict` (`id` UInt64, `name` String DEFAULT '') PRIMARY KEY `id` SOURCE(CLICKHOUSE(db 'mydb' port 9000 table 'countries')) LAYOUT(HASHED());",
+			wantErr: false,
+		},
+		{
+			name:    "dictionary with lifetime, comment and cluster",
+			builder: NewCreateDictionary("mydb", "dict", attributes, []string{"id"}).WithSource(clickhouseSource).WithLayout(hashedLayout).WithLifetime(300, 300).WithComment("it's a dictionary").WithCluster(stringPtr("my_cluster")),
+			want:    "CREATE DICTIONARY `mydb`.`dict` ON CLUSTER 'my_cluster' (`id` UInt64, `name` String DEFAULT '') PRIMARY KEY `id` SOURCE(CLICKHOUSE(db 'mydb' port 9000 table 'countries')) LAYOUT(HASHED()) LIFETIME(300) COMMENT 'it\\'s a dictionary';",
+			wantErr: false,
+		},
+		{
+			name: "complex key cache dictionary with lifetime range",
+			builder: NewCreateDictionary(
+				"mydb",
+				"dict",
+				[]DictionaryAttribute{
+					{Name: "a", Type: "String"},
+					{Name: "b", Type: "UInt32"},
+					{Name: "parent", Type: "UInt64", Expression: stringPtr("parent_id"), Hierarchical: true},
+					{Name: "label", Type: "String", Injective: true},
+				},
+				[]string{"a", "b"},
+			).WithSource(DictionaryFunction{
+				Name:       "postgresql",
+				Parameters: map[string]string{"host": "pg", "password": "se'cret"},
+			}).WithLayout(DictionaryFunction{
+				Name:       "complex_key_cache",
+				Parameters: map[string]string{"size_in_cells": "1000000"},
+			}).WithLifetime(60, 120),
+			want:    "CREATE DICTIONARY `mydb`.`dict` (`a` String, `b` UInt32, `parent` UInt64 EXPRESSION parent_id HIERARCHICAL, `label` String INJECTIVE) PRIMARY KEY `a`, `b` SOURCE(POSTGRESQL(host 'pg' password 'se\\'cret')) LAYOUT(COMPLEX_KEY_CACHE(size_in_cells 1000000)) LIFETIME(MIN 60 MAX 120);",
+			wantErr: false,
+		},
+		{
+			name:    "error: empty database name",
+			builder: NewCreateDictionary("", "dict", attributes, []string{"id"}).WithSource(clickhouseSource).WithLayout(hashedLayout),
+			wantErr: true,
+		},
+		{
+			name:    "error: no attributes",
+			builder: NewCreateDictionary("mydb", "dict", nil, []string{"id"}).WithSource(clickhouseSource).WithLayout(hashedLayout),
+			wantErr: true,
+		},
+		{
+			name:    "error: no primary key",
+			builder: NewCreateDictionary("mydb", "dict", attributes, nil).WithSource(clickhouseSource).WithLayout(hashedLayout),
+			wantErr: true,
+		},
+		{
+			name:    "error: missing source",
+			builder: NewCreateDictionary("mydb", "dict", attributes, []string{"id"}).WithLayout(hashedLayout),
+			wantErr: true,
+		},
+		{
+			name:    "error: missing layout",
+			builder: NewCreateDictionary("mydb", "dict", attributes, []string{"id"}).WithSource(clickhouseSource),
+			wantErr: true,
+		},
+		{
+			name:    "error: invalid parameter name",
+			builder: NewCreateDictionary("mydb", "dict", attributes, []string{"id"}).WithSource(DictionaryFunction{Name: "http", Parameters: map[string]string{"url) x": "y"}}).WithLayout(hashedLayout),
+			wantErr: true,
+		},
+		{
+			name:    "error: lifetime min greater than max",
+			builder: NewCreateDictionary("mydb", "dict", attributes, []string{"id"}).WithSource(clickhouseSource).WithLayout(hashedLayout).WithLifetime(10, 5),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateDictionaryQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CreateDictionaryQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -7,9 +7,12 @@ import (
 )
 
 const (
-	resourceTypeDatabase = "DATABASE"
-	resourceTypeRole     = "ROLE"
-	resourceTypeUser     = "USER"
+	resourceTypeDatabase        = "DATABASE"
+	resourceTypeRole            = "ROLE"
+	resourceTypeUser            = "USER"
+	resourceTypeSettingsProfile = "SETTINGS PROFILE"
+	resourceTypeQuota           = "QUOTA"
+	resourceTypeFunction        = "FUNCTION"
 
 	actionCreate = "CREATE"
 	actionDrop   = "DROP"
@@ -18,6 +21,8 @@ const (
 type CreateDropQueryBuilder interface {
 	QueryBuilder
 	WithCluster(clusterName *string) CreateDropQueryBuilder
+	WithIfNotExists() CreateDropQueryBuilder
+	WithIfExists() CreateDropQueryBuilder
 }
 
 type createDropQueryBuilder struct {
@@ -25,6 +30,8 @@ type createDropQueryBuilder struct {
 	resourceTypeName string
 	resourceName     string
 	clusterName      *string
+	ifNotExists      bool
+	ifExists         bool
 }
 
 func NewCreateRole(resourceName string) CreateDropQueryBuilder {
@@ -43,11 +50,35 @@ func NewDropUser(resourceName string) CreateDropQueryBuilder {
 	return newDrop(resourceTypeUser, resourceName)
 }
 
+func NewDropSettingsProfile(resourceName string) CreateDropQueryBuilder {
+	return newDrop(resourceTypeSettingsProfile, resourceName)
+}
+
+func NewDropQuota(resourceName string) CreateDropQueryBuilder {
+	return newDrop(resourceTypeQuota, resourceName)
+}
+
+func NewDropFunction(resourceName string) CreateDropQueryBuilder {
+	return newDrop(resourceTypeFunction, resourceName)
+}
+
 func (q *createDropQueryBuilder) WithCluster(clusterName *string) CreateDropQueryBuilder {
 	q.clusterName = clusterName
 	return q
 }
 
+// WithIfNotExists makes CREATE queries a no-op when the resource already exists.
+func (q *createDropQueryBuilder) WithIfNotExists() CreateDropQueryBuilder {
+	q.ifNotExists = true
+	return q
+}
+
+// WithIfExists makes DROP queries a no-op when the resource does not exist.
+func (q *createDropQueryBuilder) WithIfExists() CreateDropQueryBuilder {
+	q.ifExists = true
+	return q
+}
+
 func newCreate(resourceTypeName string, resourceName string) CreateDropQueryBuilder {
 	return &createDropQueryBuilder{
 		action:           actionCreate,
@@ -68,12 +99,24 @@ func (q *createDropQueryBuilder) Build() (string, error) {
 	if q.resourceName == "" {
 		return "", errors.New("resourceName cannot be empty for CREATE and DROP queries")
 	}
+	if q.ifNotExists && q.action != actionCreate {
+		return "", errors.New("IF NOT EXISTS can only be used with CREATE queries")
+	}
+	if q.ifExists && q.action != actionDrop {
+		return "", errors.New("IF EXISTS can only be used with DROP queries")
+	}
 
 	tokens := []string{
 		q.action,
 		q.resourceTypeName,
-		backtick(q.resourceName),
 	}
+	if q.ifNotExists {
+		tokens = append(tokens, "IF", "NOT", "EXISTS")
+	}
+	if q.ifExists {
+		tokens = append(tokens, "IF", "EXISTS")
+	}
+	tokens = append(tokens, backtick(q.resourceName))
 
 	if q.clusterName != nil {
 		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
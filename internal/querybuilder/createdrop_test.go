@@ -15,6 +15,8 @@ func Test_create_drop(t *testing.T) {
 		comment      string
 		identified   string
 		clusterName  *string
+		ifNotExists  bool
+		ifExists     bool
 		want         string
 		wantErr      bool
 	}{
@@ -115,6 +117,69 @@ func Test_create_drop(t *testing.T) {
 			want:         "",
 			wantErr:      true,
 		},
+		{
+			name:         "Drop settings profile on cluster",
+			action:       actionDrop,
+			resourceType: resourceTypeSettingsProfile,
+			resourceName: "readonly_profile",
+			clusterName:  &cluster,
+			want:         "DROP SETTINGS PROFILE `readonly_profile` ON CLUSTER 'cluster1';",
+			wantErr:      false,
+		},
+		{
+			name:         "Drop quota",
+			action:       actionDrop,
+			resourceType: resourceTypeQuota,
+			resourceName: "daily",
+			want:         "DROP QUOTA `daily`;",
+			wantErr:      false,
+		},
+		{
+			name:         "Drop function on cluster",
+			action:       actionDrop,
+			resourceType: resourceTypeFunction,
+			resourceName: "linear_equation",
+			clusterName:  &cluster,
+			want:         "DROP FUNCTION `linear_equation` ON CLUSTER 'cluster1';",
+			wantErr:      false,
+		},
+		{
+			name:         "Create role if not exists",
+			action:       actionCreate,
+			resourceType: resourceTypeRole,
+			resourceName: "role1",
+			ifNotExists:  true,
+			want:         "CREATE ROLE IF NOT EXISTS `role1`;",
+			wantErr:      false,
+		},
+		{
+			name:         "Drop user if exists on cluster",
+			action:       actionDrop,
+			resourceType: resourceTypeUser,
+			resourceName: "john",
+			clusterName:  &cluster,
+			ifExists:     true,
+			want:         "DROP USER IF EXISTS `john` ON CLUSTER 'cluster1';",
+			wantErr:      false,
+		},
+		{
+			name:         "Fail to create role with if exists",
+			action:       actionCreate,
+			resourceType: resourceTypeRole,
+			resourceName: "role1",
+			ifExists:     true,
+			want:         "",
+			wantErr:      true,
+		},
+		{
+			name:         "Fail to drop database with if not exists",
+			action:       actionDrop,
+			resourceType: resourceTypeDatabase,
+			resourceName: "db1",
+			ifNotExists:  true,
+			want:         "",
+			wantErr:      true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -123,6 +188,8 @@ func Test_create_drop(t *testing.T) {
 				resourceTypeName: tt.resourceType,
 				resourceName:     tt.resourceName,
 				clusterName:      tt.clusterName,
+				ifNotExists:      tt.ifNotExists,
+				ifExists:         tt.ifExists,
 			}
 
 			got, err := q.Build()
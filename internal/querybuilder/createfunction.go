@@ -0,0 +1,75 @@
+package querybuilder
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// CreateFunctionQueryBuilder is an interface to build CREATE FUNCTION SQL queries (already interpolated).
+type CreateFunctionQueryBuilder interface {
+	QueryBuilder
+	WithOrReplace() CreateFunctionQueryBuilder
+	WithCluster(clusterName *string) CreateFunctionQueryBuilder
+}
+
+type createFunctionQueryBuilder struct {
+	functionName string
+	arguments    []string
+	expression   string
+	orReplace    bool
+	clusterName  *string
+}
+
+// NewCreateFunction builds a CREATE FUNCTION query for a SQL user defined function, i.e. a lambda of the given
+// arguments. The expression is emitted as-is.
+func NewCreateFunction(functionName string, arguments []string, expression string) CreateFunctionQueryBuilder {
+	return &createFunctionQueryBuilder{
+		functionName: functionName,
+		arguments:    arguments,
+		expression:   expression,
+	}
+}
+
+// WithOrReplace replaces the function when it already exists, instead of failing.
+func (q *createFunctionQueryBuilder) WithOrReplace() CreateFunctionQueryBuilder {
+	q.orReplace = true
+	return q
+}
+
+func (q *createFunctionQueryBuilder) WithCluster(clusterName *string) CreateFunctionQueryBuilder {
+	q.clusterName = clusterName
+	return q
+}
+
+func (q *createFunctionQueryBuilder) Build() (string, error) {
+	if q.functionName == "" {
+		return "", errors.New("functionName cannot be empty for CREATE FUNCTION queries")
+	}
+
+	// A trailing semicolon would end the statement early.
+	expression := strings.TrimRight(strings.TrimSpace(q.expression), "; \t\n")
+	if expression == "" {
+		return "", errors.New("expression cannot be empty for CREATE FUNCTION queries")
+	}
+
+	arguments := make([]string, 0, len(q.arguments))
+	for _, argument := range q.arguments {
+		if argument == "" {
+			return "", errors.New("argument names cannot be empty for CREATE FUNCTION queries")
+		}
+		arguments = append(arguments, backtick(argument))
+	}
+
+	tokens := []string{"CREATE"}
+	if q.orReplace {
+		tokens = append(tokens, "OR", "REPLACE")
+	}
+	tokens = append(tokens, "FUNCTION", backtick(q.functionName))
+	if q.clusterName != nil {
+		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
+	}
+	tokens = append(tokens, "AS", "("+strings.Join(arguments, ", ")+")", "->", expression)
+
+	return strings.Join(tokens, " ") + ";", nil
+}
@@ -0,0 +1,61 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func TestCreateFunctionQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder CreateFunctionQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "simple function",
+			builder: NewCreateFunction("linear_equation", []string{"x", "k", "b"}, "k*x + b"),
+			want:    "CREATE FUNCTION `linear_equation` AS (`x`, `k`, `b`) -> k*x + b;",
+			wantErr: false,
+		},
+		{
+			name:    "function without arguments",
+			builder: NewCreateFunction("answer", nil, "42;"),
+			want:    "CREATE FUNCTION `answer` AS () -> 42;",
+			wantErr: false,
+		},
+		{
+			name:    "replace function on cluster",
+			builder: NewCreateFunction("parity", []string{"n"}, "if(n % 2, 'odd', 'even')").WithOrReplace().WithCluster(stringPtr("my_cluster")),
+			want:    "CREATE OR REPLACE FUNCTION `parity` ON CLUSTER 'my_cluster' AS (`n`) -> if(n % 2, 'odd', 'even');",
+			wantErr: false,
+		},
+		{
+			name:    "error: empty function name",
+			builder: NewCreateFunction("", []string{"x"}, "x"),
+			wantErr: true,
+		},
+		{
+			name:    "error: empty expression",
+			builder: NewCreateFunction("f", []string{"x"}, " ; "),
+			wantErr: true,
+		},
+		{
+			name:    "error: empty argument",
+			builder: NewCreateFunction("f", []string{""}, "1"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateFunctionQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CreateFunctionQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package querybuilder
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// CreateMaterializedViewQueryBuilder is an interface to build CREATE MATERIALIZED VIEW SQL queries (already interpolated).
+type CreateMaterializedViewQueryBuilder interface {
+	QueryBuilder
+	WithTo(databaseName string, tableName string) CreateMaterializedViewQueryBuilder
+	WithEngine(engine string) CreateMaterializedViewQueryBuilder
+	WithRefresh(refresh string) CreateMaterializedViewQueryBuilder
+	WithPopulate() CreateMaterializedViewQueryBuilder
+	WithComment(comment string) CreateMaterializedViewQueryBuilder
+	WithCluster(clusterName *string) CreateMaterializedViewQueryBuilder
+}
+
+type createMaterializedViewQueryBuilder struct {
+	databaseName   string
+	viewName       string
+	query          string
+	toDatabaseName string
+	toTableName    string
+	engine         *string
+	refresh        *string
+	populate       bool
+	comment        *string
+	clusterName    *string
+}
+
+// NewCreateMaterializedView builds a CREATE MATERIALIZED VIEW query. The SELECT query is emitted as-is.
+func NewCreateMaterializedView(databaseName string, viewName string, query string) CreateMaterializedViewQueryBuilder {
+	return &createMaterializedViewQueryBuilder{
+		databaseName: databaseName,
+		viewName:     viewName,
+		query:        query,
+	}
+}
+
+// WithTo makes the view write into an existing table instead of an inner table.
+func (q *createMaterializedViewQueryBuilder) WithTo(databaseName string, tableName string) CreateMaterializedViewQueryBuilder {
+	q.toDatabaseName = databaseName
+	q.toTableName = tableName
+	return q
+}
+
+// WithEngine sets the engine of the inner table along with its clauses, e.g. `MergeTree ORDER BY id`. It is emitted
+// as-is.
+func (q *createMaterializedViewQueryBuilder) WithEngine(engine string) CreateMaterializedViewQueryBuilder {
+	q.engine = &engine
+	return q
+}
+
+// WithRefresh makes the view refreshable, refresh being the clause following REFRESH, e.g. `EVERY 1 HOUR`. It is
+// emitted as-is.
+func (q *createMaterializedViewQueryBuilder) WithRefresh(refresh string) CreateMaterializedViewQueryBuilder {
+	q.refresh = &refresh
+	return q
+}
+
+// WithPopulate fills the inner table with the existing data of the source table on creation.
+func (q *createMaterializedViewQueryBuilder) WithPopulate() CreateMaterializedViewQueryBuilder {
+	q.populate = true
+	return q
+}
+
+func (q *createMaterializedViewQueryBuilder) WithComment(comment string) CreateMaterializedViewQueryBuilder {
+	q.comment = &comment
+	return q
+}
+
+func (q *createMaterializedViewQueryBuilder) WithCluster(clusterName *string) CreateMaterializedViewQueryBuilder {
+	q.clusterName = clusterName
+	return q
+}
+
+func (q *createMaterializedViewQueryBuilder) Build() (string, error) {
+	if q.databaseName == "" {
+		return "", errors.New("databaseName cannot be empty for CREATE MATERIALIZED VIEW queries")
+	}
+	if q.viewName == "" {
+		return "", errors.New("viewName cannot be empty for CREATE MATERIALIZED VIEW queries")
+	}
+	if q.toTableName != "" && q.engine != nil {
+		return "", errors.New("a materialized view writing into a table cannot have an engine")
+	}
+	if q.populate && q.toTableName != "" {
+		return "", errors.New("POPULATE cannot be used with a materialized view writing into a table")
+	}
+	if q.populate && q.refresh != nil {
+		return "", errors.New("POPULATE cannot be used with a refreshable materialized view")
+	}
+
+	// A trailing semicolon would end the statement before the comment.
+	query := strings.TrimRight(strings.TrimSpace(q.query), "; \t\n")
+	if query == "" {
+		return "", errors.New("query cannot be empty for CREATE MATERIALIZED VIEW queries")
+	}
+
+	tokens := []string{
+		"CREATE",
+		"MATERIALIZED",
+		"VIEW",
+		backtick(q.databaseName) + "." + backtick(q.viewName),
+	}
+	if q.clusterName != nil {
+		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
+	}
+	if q.refresh != nil {
+		tokens = append(tokens, "REFRESH", *q.refresh)
+	}
+	if q.toTableName != "" {
+		target := backtick(q.toTableName)
+		if q.toDatabaseName != "" {
+			target = backtick(q.toDatabaseName) + "." + target
+		}
+		tokens = append(tokens, "TO", target)
+	}
+	if q.engine != nil {
+		tokens = append(tokens, "ENGINE", "=", *q.engine)
+	}
+	if q.populate {
+		tokens = append(tokens, "POPULATE")
+	}
+	tokens = append(tokens, "AS", query)
+	if q.comment != nil {
+		tokens = append(tokens, "COMMENT", quote(*q.comment))
+	}
+
+	return strings.Join(tokens, " ") + ";", nil
+}
@@ -0,0 +1,71 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func TestCreateMaterializedViewQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder CreateMaterializedViewQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "view writing into a table",
+			builder: NewCreateMaterializedView("mydb", "mv", "SELECT id FROM mydb.events").WithTo("mydb", "target"),
+			want:    "CREATE MATERIALIZED VIEW `mydb`.`mv` TO `mydb`.`target` AS SELECT id FROM mydb.events;",
+			wantErr: false,
+		},
+		{
+			name:    "view with inner engine, populate, cluster and comment",
+			builder: NewCreateMaterializedView("mydb", "mv", "SELECT id FROM mydb.events").WithEngine("MergeTree ORDER BY id").WithPopulate().WithCluster(stringPtr("my_cluster")).WithComment("counts"),
+			want:    "CREATE MATERIALIZED VIEW `mydb`.`mv` ON CLUSTER 'my_cluster' ENGINE = MergeTree ORDER BY id POPULATE AS SELECT id FROM mydb.events COMMENT 'counts';",
+			wantErr: false,
+		},
+		{
+			name:    "refreshable view",
+			builder: NewCreateMaterializedView("mydb", "mv", "SELECT count() FROM mydb.events;").WithRefresh("EVERY 1 HOUR").WithTo("", "target"),
+			want:    "CREATE MATERIALIZED VIEW `mydb`.`mv` REFRESH EVERY 1 HOUR TO `target` AS SELECT count() FROM mydb.events;",
+			wantErr: false,
+		},
+		{
+			name:    "error: engine with target table",
+			builder: NewCreateMaterializedView("mydb", "mv", "SELECT 1").WithTo("mydb", "target").WithEngine("MergeTree ORDER BY tuple()"),
+			wantErr: true,
+		},
+		{
+			name:    "error: populate with target table",
+			builder: NewCreateMaterializedView("mydb", "mv", "SELECT 1").WithTo("mydb", "target").WithPopulate(),
+			wantErr: true,
+		},
+		{
+			name:    "error: populate with refresh",
+			builder: NewCreateMaterializedView("mydb", "mv", "SELECT 1").WithEngine("MergeTree ORDER BY tuple()").WithRefresh("EVERY 1 DAY").WithPopulate(),
+			wantErr: true,
+		},
+		{
+			name:    "error: empty query",
+			builder: NewCreateMaterializedView("mydb", "mv", ""),
+			wantErr: true,
+		},
+		{
+			name:    "error: empty view name",
+			builder: NewCreateMaterializedView("mydb", "", "SELECT 1"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateMaterializedViewQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CreateMaterializedViewQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
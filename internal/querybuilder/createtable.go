@@ -1,7 +1,6 @@
 package querybuilder
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/pingcap/errors"
@@ -11,7 +10,11 @@ import (
 type CreateTableQueryBuilder interface {
 	QueryBuilder
 	WithCluster(clusterName *string) CreateTableQueryBuilder
+	WithUUID(uuid string) CreateTableQueryBuilder
 	WithEngine(engine string) CreateTableQueryBuilder
+	WithSourceFunction(sourceFunction TableFunction) CreateTableQueryBuilder
+	WithIndexes(indexes []TableIndex) CreateTableQueryBuilder
+	WithProjections(projections []TableProjection) CreateTableQueryBuilder
 	WithOrderBy(orderBy []string) CreateTableQueryBuilder
 	WithPartitionBy(partitionBy string) CreateTableQueryBuilder
 	WithPrimaryKey(primaryKey []string) CreateTableQueryBuilder
@@ -19,14 +22,19 @@ type CreateTableQueryBuilder interface {
 	WithTTL(ttl string) CreateTableQueryBuilder
 	WithSettings(settings map[string]string) CreateTableQueryBuilder
 	WithComment(comment string) CreateTableQueryBuilder
+	WithIfNotExists() CreateTableQueryBuilder
 }
 
 type createTableQueryBuilder struct {
 	databaseName string
 	tableName    string
+	uuid         string
 	columns      []TableColumn
+	indexes      []TableIndex
+	projections  []TableProjection
 	clusterName  *string
 	engine       string
+	source       *TableFunction
 	orderBy      []string
 	partitionBy  *string
 	primaryKey   []string
@@ -34,13 +42,37 @@ type createTableQueryBuilder struct {
 	ttl          *string
 	settings     map[string]string
 	comment      *string
+	ifNotExists  bool
 }
 
 type TableColumn struct {
 	Name    string
 	Type    string
 	Default *string
-	Comment *string
+	// DefaultKind is one of DEFAULT, MATERIALIZED, ALIAS or EPHEMERAL. Nil means DEFAULT.
+	DefaultKind *string
+	Comment     *string
+	// Codec is the compression codec chain without the surrounding CODEC(), e.g. "Delta, ZSTD(1)".
+	Codec *string
+	// First and After position the column with ALTER TABLE ADD COLUMN: first, or after the named column. The column is
+	// added last when neither is set. CREATE TABLE ignores them.
+	First bool
+	After *string
+}
+
+const (
+	ColumnDefaultKindDefault      = "DEFAULT"
+	ColumnDefaultKindMaterialized = "MATERIALIZED"
+	ColumnDefaultKindAlias        = "ALIAS"
+	ColumnDefaultKindEphemeral    = "EPHEMERAL"
+)
+
+// defaultKind returns the keyword to use in front of the column's default expression.
+func (c TableColumn) defaultKind() string {
+	if c.DefaultKind != nil && *c.DefaultKind != "" {
+		return *c.DefaultKind
+	}
+	return ColumnDefaultKindDefault
 }
 
 func NewCreateTable(databaseName, tableName string, columns []TableColumn) CreateTableQueryBuilder {
@@ -57,11 +89,36 @@ func (q *createTableQueryBuilder) WithCluster(clusterName *string) CreateTableQu
 	return q
 }
 
+// WithUUID creates the table with the given UUID instead of letting the server generate one.
+func (q *createTableQueryBuilder) WithUUID(uuid string) CreateTableQueryBuilder {
+	q.uuid = uuid
+	return q
+}
+
 func (q *createTableQueryBuilder) WithEngine(engine string) CreateTableQueryBuilder {
 	q.engine = engine
 	return q
 }
 
+// WithSourceFunction creates the table AS the result of a table function (e.g. s3 or postgresql) instead of using an
+// engine. Such tables read from the external source on every query and cannot have keys, TTL or settings.
+func (q *createTableQueryBuilder) WithSourceFunction(sourceFunction TableFunction) CreateTableQueryBuilder {
+	q.source = &sourceFunction
+	return q
+}
+
+// WithIndexes declares data skipping indexes along with the columns.
+func (q *createTableQueryBuilder) WithIndexes(indexes []TableIndex) CreateTableQueryBuilder {
+	q.indexes = indexes
+	return q
+}
+
+// WithProjections declares projections along with the columns.
+func (q *createTableQueryBuilder) WithProjections(projections []TableProjection) CreateTableQueryBuilder {
+	q.projections = projections
+	return q
+}
+
 func (q *createTableQueryBuilder) WithOrderBy(orderBy []string) CreateTableQueryBuilder {
 	q.orderBy = orderBy
 	return q
@@ -97,6 +154,12 @@ func (q *createTableQueryBuilder) WithComment(comment string) CreateTableQueryBu
 	return q
 }
 
+// WithIfNotExists makes the query a no-op when the table already exists.
+func (q *createTableQueryBuilder) WithIfNotExists() CreateTableQueryBuilder {
+	q.ifNotExists = true
+	return q
+}
+
 func (q *createTableQueryBuilder) Build() (string, error) {
 	if q.databaseName == "" {
 		return "", errors.New("databaseName cannot be empty for CREATE TABLE queries")
@@ -107,16 +170,31 @@ func (q *createTableQueryBuilder) Build() (string, error) {
 	if len(q.columns) == 0 {
 		return "", errors.New("columns cannot be empty for CREATE TABLE queries")
 	}
-	if q.engine == "" {
+	if q.source != nil {
+		if q.engine != "" {
+			return "", errors.New("engine and source function cannot be both set for CREATE TABLE queries")
+		}
+		if len(q.orderBy) > 0 || q.partitionBy != nil || len(q.primaryKey) > 0 || q.sampleBy != nil || q.ttl != nil || len(q.settings) > 0 {
+			return "", errors.New("tables created from a source function cannot have keys, TTL or settings")
+		}
+	} else if q.engine == "" {
 		return "", errors.New("engine cannot be empty for CREATE TABLE queries")
 	}
 
 	var sb strings.Builder
 	sb.WriteString("CREATE TABLE ")
+	if q.ifNotExists {
+		sb.WriteString("IF NOT EXISTS ")
+	}
 	sb.WriteString(backtick(q.databaseName))
 	sb.WriteString(".")
 	sb.WriteString(backtick(q.tableName))
 
+	if q.uuid != "" {
+		sb.WriteString(" UUID ")
+		sb.WriteString(quote(q.uuid))
+	}
+
 	if q.clusterName != nil {
 		sb.WriteString(" ON CLUSTER ")
 		sb.WriteString(quote(*q.clusterName))
@@ -132,19 +210,51 @@ func (q *createTableQueryBuilder) Build() (string, error) {
 		sb.WriteString(" ")
 		sb.WriteString(col.Type)
 		if col.Default != nil {
-			sb.WriteString(" DEFAULT ")
+			sb.WriteString(" ")
+			sb.WriteString(col.defaultKind())
+			sb.WriteString(" ")
 			sb.WriteString(*col.Default)
+		} else if col.defaultKind() == ColumnDefaultKindEphemeral {
+			sb.WriteString(" EPHEMERAL")
 		}
 		if col.Comment != nil {
 			sb.WriteString(" COMMENT ")
 			sb.WriteString(quote(*col.Comment))
 		}
+		if col.Codec != nil && *col.Codec != "" {
+			sb.WriteString(" CODEC(")
+			sb.WriteString(*col.Codec)
+			sb.WriteString(")")
+		}
+	}
+	for _, idx := range q.indexes {
+		if err := idx.validate(); err != nil {
+			return "", err
+		}
+		sb.WriteString(", INDEX ")
+		sb.WriteString(idx.definition())
+	}
+	for _, p := range q.projections {
+		if err := p.validate(); err != nil {
+			return "", err
+		}
+		sb.WriteString(", PROJECTION ")
+		sb.WriteString(p.definition())
 	}
 	sb.WriteString(")")
 
-	// Engine
-	sb.WriteString(" ENGINE = ")
-	sb.WriteString(q.engine)
+	if q.source != nil {
+		source, err := q.source.SQLDef()
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(" AS ")
+		sb.WriteString(source)
+	} else {
+		// Engine
+		sb.WriteString(" ENGINE = ")
+		sb.WriteString(q.engine)
+	}
 
 	// ORDER BY
 	if len(q.orderBy) > 0 {
@@ -191,14 +301,7 @@ func (q *createTableQueryBuilder) Build() (string, error) {
 	// SETTINGS
 	if len(q.settings) > 0 {
 		sb.WriteString(" SETTINGS ")
-		i := 0
-		for key, value := range q.settings {
-			if i > 0 {
-				sb.WriteString(", ")
-			}
-			sb.WriteString(fmt.Sprintf("%s = %s", key, value))
-			i++
-		}
+		sb.WriteString(settingsList(q.settings))
 	}
 
 	// COMMENT
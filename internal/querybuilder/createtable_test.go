@@ -38,6 +38,14 @@ func TestCreateTableQueryBuilder_Build(t *testing.T) {
 			want:    "CREATE TABLE `mydb`.`distributed_table` ON CLUSTER 'my_cluster' (`id` UInt64) ENGINE = MergeTree() ORDER BY (`id`);",
 			wantErr: false,
 		},
+		{
+			name: "table with uuid and cluster",
+			builder: NewCreateTable("mydb", "events", []TableColumn{
+				{Name: "id", Type: "UInt64"},
+			}).WithEngine("ReplicatedMergeTree").WithOrderBy([]string{"id"}).WithUUID("5f2b8e1a-3c4d-4e5f-8a9b-0c1d2e3f4a5b").WithCluster(stringPtr("my_cluster")),
+			want:    "CREATE TABLE `mydb`.`events` UUID '5f2b8e1a-3c4d-4e5f-8a9b-0c1d2e3f4a5b' ON CLUSTER 'my_cluster' (`id` UInt64) ENGINE = ReplicatedMergeTree ORDER BY (`id`);",
+			wantErr: false,
+		},
 		{
 			name: "table with partitioning and TTL",
 			builder: NewCreateTable("mydb", "logs", []TableColumn{
@@ -97,6 +105,94 @@ func TestCreateTableQueryBuilder_Build(t *testing.T) {
 			want:    "CREATE TABLE `mydb`.`versioned` (`id` UInt64, `data` String, `version` UInt64) ENGINE = ReplacingMergeTree(version) ORDER BY (`id`);",
 			wantErr: false,
 		},
+		{
+			name: "table with column default kinds",
+			builder: NewCreateTable("mydb", "mytable", []TableColumn{
+				{Name: "ts", Type: "DateTime"},
+				{Name: "day", Type: "Date", Default: stringPtr("toDate(ts)"), DefaultKind: stringPtr(ColumnDefaultKindMaterialized)},
+				{Name: "raw", Type: "String", DefaultKind: stringPtr(ColumnDefaultKindEphemeral), Comment: stringPtr("input only")},
+			}).WithEngine("MergeTree()").WithOrderBy([]string{"ts"}),
+			want:    "CREATE TABLE `mydb`.`mytable` (`ts` DateTime, `day` Date MATERIALIZED toDate(ts), `raw` String EPHEMERAL COMMENT 'input only') ENGINE = MergeTree() ORDER BY (`ts`);",
+			wantErr: false,
+		},
+		{
+			name: "table with column codecs",
+			builder: NewCreateTable("mydb", "mytable", []TableColumn{
+				{Name: "ts", Type: "DateTime", Codec: stringPtr("Delta, ZSTD(1)")},
+				{Name: "raw", Type: "String", Comment: stringPtr("input only")},
+			}).WithEngine("MergeTree()").WithOrderBy([]string{"ts"}),
+			want:    "CREATE TABLE `mydb`.`mytable` (`ts` DateTime CODEC(Delta, ZSTD(1)), `raw` String COMMENT 'input only') ENGINE = MergeTree() ORDER BY (`ts`);",
+			wantErr: false,
+		},
+		{
+			name: "table with indexes",
+			builder: NewCreateTable("mydb", "mytable", []TableColumn{
+				{Name: "id", Type: "UInt64"},
+				{Name: "url", Type: "String"},
+			}).WithIndexes([]TableIndex{
+				{Name: "url_idx", Expression: "url", Type: "bloom_filter(0.01)", Granularity: 4},
+				{Name: "id_idx", Expression: "id", Type: "minmax"},
+			}).WithEngine("MergeTree()").WithOrderBy([]string{"id"}),
+			want:    "CREATE TABLE `mydb`.`mytable` (`id` UInt64, `url` String, INDEX `url_idx` url TYPE bloom_filter(0.01) GRANULARITY 4, INDEX `id_idx` id TYPE minmax) ENGINE = MergeTree() ORDER BY (`id`);",
+			wantErr: false,
+		},
+		{
+			name: "table with projections",
+			builder: NewCreateTable("mydb", "mytable", []TableColumn{
+				{Name: "id", Type: "UInt64"},
+				{Name: "user_id", Type: "UInt64"},
+			}).WithIndexes([]TableIndex{
+				{Name: "id_idx", Expression: "id", Type: "minmax"},
+			}).WithProjections([]TableProjection{
+				{Name: "by_user", Query: "SELECT * ORDER BY user_id"},
+			}).WithEngine("MergeTree()").WithOrderBy([]string{"id"}),
+			want:    "CREATE TABLE `mydb`.`mytable` (`id` UInt64, `user_id` UInt64, INDEX `id_idx` id TYPE minmax, PROJECTION `by_user` (SELECT * ORDER BY user_id)) ENGINE = MergeTree() ORDER BY (`id`);",
+			wantErr: false,
+		},
+		{
+			name: "table if not exists",
+			builder: NewCreateTable("mydb", "mytable", []TableColumn{
+				{Name: "id", Type: "UInt64"},
+			}).WithEngine("MergeTree()").WithOrderBy([]string{"id"}).WithIfNotExists(),
+			want:    "CREATE TABLE IF NOT EXISTS `mydb`.`mytable` (`id` UInt64) ENGINE = MergeTree() ORDER BY (`id`);",
+			wantErr: false,
+		},
+		{
+			name: "table from source function",
+			builder: NewCreateTable("mydb", "mytable", []TableColumn{
+				{Name: "id", Type: "UInt64"},
+			}).WithSourceFunction(TableFunction{
+				Name:            "s3",
+				NamedCollection: "events_bucket",
+				Arguments:       map[string]string{"url": "https://bucket.s3.amazonaws.com/events/*.parquet", "format": "Parquet"},
+			}).WithComment("snapshot"),
+			want:    "CREATE TABLE `mydb`.`mytable` (`id` UInt64) AS s3(`events_bucket`, `format` = 'Parquet', `url` = 'https://bucket.s3.amazonaws.com/events/*.parquet') COMMENT 'snapshot';",
+			wantErr: false,
+		},
+		{
+			name: "error: source function with engine",
+			builder: NewCreateTable("mydb", "mytable", []TableColumn{
+				{Name: "id", Type: "UInt64"},
+			}).WithEngine("MergeTree()").WithSourceFunction(TableFunction{Name: "s3", NamedCollection: "events_bucket"}),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name: "error: source function with order by",
+			builder: NewCreateTable("mydb", "mytable", []TableColumn{
+				{Name: "id", Type: "UInt64"},
+			}).WithSourceFunction(TableFunction{Name: "s3", NamedCollection: "events_bucket"}).WithOrderBy([]string{"id"}),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name: "error: source function without named collection",
+			builder: NewCreateTable("mydb", "mytable", []TableColumn{
+				{Name: "id", Type: "UInt64"},
+			}).WithSourceFunction(TableFunction{Name: "s3"}),
+			want:    "",
+			wantErr: true,
+		},
 		{
 			name: "error: empty database name",
 			builder: NewCreateTable("", "mytable", []TableColumn{
@@ -3,6 +3,7 @@ package querybuilder
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/pingcap/errors"
 )
@@ -12,6 +13,8 @@ type CreateUserQueryBuilder interface {
 	QueryBuilder
 	Identified(with Identification, by string) CreateUserQueryBuilder
 	WithCluster(clusterName *string) CreateUserQueryBuilder
+	WithIfNotExists() CreateUserQueryBuilder
+	WithValidUntil(validUntil time.Time) CreateUserQueryBuilder
 }
 
 type Identification string
@@ -24,6 +27,8 @@ type createUserQueryBuilder struct {
 	resourceName string
 	identified   string
 	clusterName  *string
+	ifNotExists  bool
+	validUntil   *time.Time
 }
 
 func NewCreateUser(resourceName string) CreateUserQueryBuilder {
@@ -42,6 +47,18 @@ func (q *createUserQueryBuilder) WithCluster(clusterName *string) CreateUserQuer
 	return q
 }
 
+// WithIfNotExists makes the query a no-op when the user already exists.
+func (q *createUserQueryBuilder) WithIfNotExists() CreateUserQueryBuilder {
+	q.ifNotExists = true
+	return q
+}
+
+// WithValidUntil makes the user expire at the given time, after which it can't log in anymore.
+func (q *createUserQueryBuilder) WithValidUntil(validUntil time.Time) CreateUserQueryBuilder {
+	q.validUntil = &validUntil
+	return q
+}
+
 func (q *createUserQueryBuilder) Build() (string, error) {
 	if q.resourceName == "" {
 		return "", errors.New("resourceName cannot be empty for CREATE USER queries")
@@ -50,14 +67,21 @@ func (q *createUserQueryBuilder) Build() (string, error) {
 	tokens := []string{
 		"CREATE",
 		"USER",
-		backtick(q.resourceName),
 	}
+	if q.ifNotExists {
+		tokens = append(tokens, "IF", "NOT", "EXISTS")
+	}
+	tokens = append(tokens, backtick(q.resourceName))
 	if q.clusterName != nil {
 		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
 	}
 	if q.identified != "" {
 		tokens = append(tokens, q.identified)
 	}
+	if q.validUntil != nil {
+		// The time zone is set explicitly, the server time zone is used otherwise.
+		tokens = append(tokens, "VALID", "UNTIL", quote(q.validUntil.UTC().Format("2006-01-02 15:04:05")+" `UTC`"))
+	}
 
 	return strings.Join(tokens, " ") + ";", nil
 }
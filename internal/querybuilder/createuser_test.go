@@ -2,9 +2,11 @@ package querybuilder
 
 import (
 	"testing"
+	"time"
 )
 
 func Test_createuser(t *testing.T) {
+	validUntil := time.Date(2026, 1, 2, 4, 4, 5, 0, time.FixedZone("CET", 3600))
 	tests := []struct {
 		name           string
 		action         string
@@ -12,6 +14,8 @@ func Test_createuser(t *testing.T) {
 		resourceName   string
 		identifiedWith Identification
 		identifiedBy   string
+		ifNotExists    bool
+		validUntil     *time.Time
 		want           string
 		wantErr        bool
 	}{
@@ -41,6 +45,28 @@ func Test_createuser(t *testing.T) {
 			want:           "CREATE USER `john` IDENTIFIED WITH sha256_hash BY 'blah';",
 			wantErr:        false,
 		},
+		{
+			name:           "Create user if not exists",
+			action:         actionCreate,
+			resourceType:   resourceTypeUser,
+			resourceName:   "john",
+			identifiedWith: IdentificationSHA256Hash,
+			identifiedBy:   "blah",
+			ifNotExists:    true,
+			want:           "CREATE USER IF NOT EXISTS `john` IDENTIFIED WITH sha256_hash BY 'blah';",
+			wantErr:        false,
+		},
+		{
+			name:           "Create user valid until",
+			action:         actionCreate,
+			resourceType:   resourceTypeUser,
+			resourceName:   "john",
+			identifiedWith: IdentificationSHA256Hash,
+			identifiedBy:   "blah",
+			validUntil:     &validUntil,
+			want:           "CREATE USER `john` IDENTIFIED WITH sha256_hash BY 'blah' VALID UNTIL '2026-01-02 03:04:05 `UTC`';",
+			wantErr:        false,
+		},
 		{
 			name:         "Create user fails when no user name is set",
 			action:       actionCreate,
@@ -60,6 +86,12 @@ func Test_createuser(t *testing.T) {
 			if tt.identifiedWith != "" && tt.identifiedBy != "" {
 				q = q.Identified(tt.identifiedWith, tt.identifiedBy)
 			}
+			if tt.ifNotExists {
+				q = q.WithIfNotExists()
+			}
+			if tt.validUntil != nil {
+				q = q.WithValidUntil(*tt.validUntil)
+			}
 
 			got, err := q.Build()
 			if (err != nil) != tt.wantErr {
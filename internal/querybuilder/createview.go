@@ -0,0 +1,83 @@
+package querybuilder
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// CreateViewQueryBuilder is an interface to build CREATE VIEW SQL queries (already interpolated).
+type CreateViewQueryBuilder interface {
+	QueryBuilder
+	WithComment(comment string) CreateViewQueryBuilder
+	WithSQLSecurityDefiner() CreateViewQueryBuilder
+	WithCluster(clusterName *string) CreateViewQueryBuilder
+}
+
+type createViewQueryBuilder struct {
+	databaseName string
+	viewName     string
+	query        string
+	comment      *string
+	definer      bool
+	clusterName  *string
+}
+
+// NewCreateView builds a CREATE VIEW query for a standard view. The SELECT query is emitted as-is.
+func NewCreateView(databaseName string, viewName string, query string) CreateViewQueryBuilder {
+	return &createViewQueryBuilder{
+		databaseName: databaseName,
+		viewName:     viewName,
+		query:        query,
+	}
+}
+
+func (q *createViewQueryBuilder) WithComment(comment string) CreateViewQueryBuilder {
+	q.comment = &comment
+	return q
+}
+
+// WithSQLSecurityDefiner makes the view read its tables with the privileges of the user creating it, instead of the
+// ones of the user reading the view.
+func (q *createViewQueryBuilder) WithSQLSecurityDefiner() CreateViewQueryBuilder {
+	q.definer = true
+	return q
+}
+
+func (q *createViewQueryBuilder) WithCluster(clusterName *string) CreateViewQueryBuilder {
+	q.clusterName = clusterName
+	return q
+}
+
+func (q *createViewQueryBuilder) Build() (string, error) {
+	if q.databaseName == "" {
+		return "", errors.New("databaseName cannot be empty for CREATE VIEW queries")
+	}
+	if q.viewName == "" {
+		return "", errors.New("viewName cannot be empty for CREATE VIEW queries")
+	}
+
+	// A trailing semicolon would end the statement before the comment.
+	query := strings.TrimRight(strings.TrimSpace(q.query), "; \t\n")
+	if query == "" {
+		return "", errors.New("query cannot be empty for CREATE VIEW queries")
+	}
+
+	tokens := []string{
+		"CREATE",
+		"VIEW",
+		backtick(q.databaseName) + "." + backtick(q.viewName),
+	}
+	if q.clusterName != nil {
+		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
+	}
+	if q.definer {
+		tokens = append(tokens, "DEFINER", "=", "CURRENT_USER", "SQL", "SECURITY", "DEFINER")
+	}
+	tokens = append(tokens, "AS", query)
+	if q.comment != nil {
+		tokens = append(tokens, "COMMENT", quote(*q.comment))
+	}
+
+	return strings.Join(tokens, " ") + ";", nil
+}
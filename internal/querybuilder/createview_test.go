@@ -0,0 +1,70 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func TestCreateViewQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder CreateViewQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "simple view",
+			builder: NewCreateView("mydb", "myview", "SELECT id FROM mydb.events"),
+			want:    "CREATE VIEW `mydb`.`myview` AS SELECT id FROM mydb.events;",
+			wantErr: false,
+		},
+		{
+			name:    "view with cluster and comment",
+			builder: NewCreateView("mydb", "myview", "SELECT id FROM mydb.events").WithCluster(stringPtr("my_cluster")).WithComment("it's a view"),
+			want:    "CREATE VIEW `mydb`.`myview` ON CLUSTER 'my_cluster' AS SELECT id FROM mydb.events COMMENT 'it\\'s a view';",
+			wantErr: false,
+		},
+		{
+			name:    "view with definer security",
+			builder: NewCreateView("mydb", "myview", "SELECT * FROM mydb.events").WithSQLSecurityDefiner(),
+			want:    "CREATE VIEW `mydb`.`myview` DEFINER = CURRENT_USER SQL SECURITY DEFINER AS SELECT * FROM mydb.events;",
+			wantErr: false,
+		},
+		{
+			name:    "trailing semicolon is dropped",
+			builder: NewCreateView("mydb", "myview", "  SELECT 1;\n").WithComment("one"),
+			want:    "CREATE VIEW `mydb`.`myview` AS SELECT 1 COMMENT 'one';",
+			wantErr: false,
+		},
+		{
+			name:    "error: empty database name",
+			builder: NewCreateView("", "myview", "SELECT 1"),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "error: empty view name",
+			builder: NewCreateView("mydb", "", "SELECT 1"),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "error: empty query",
+			builder: NewCreateView("mydb", "myview", " ; "),
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateViewQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CreateViewQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
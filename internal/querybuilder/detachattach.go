@@ -0,0 +1,109 @@
+package querybuilder
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// DetachTableQueryBuilder is an interface to build DETACH TABLE SQL queries (already interpolated).
+type DetachTableQueryBuilder interface {
+	QueryBuilder
+	WithCluster(clusterName *string) DetachTableQueryBuilder
+	WithPermanently(permanently bool) DetachTableQueryBuilder
+}
+
+type detachTableQueryBuilder struct {
+	databaseName string
+	tableName    string
+	clusterName  *string
+	permanently  bool
+}
+
+func NewDetachTable(databaseName, tableName string) DetachTableQueryBuilder {
+	return &detachTableQueryBuilder{
+		databaseName: databaseName,
+		tableName:    tableName,
+	}
+}
+
+func (q *detachTableQueryBuilder) WithCluster(clusterName *string) DetachTableQueryBuilder {
+	q.clusterName = clusterName
+	return q
+}
+
+// WithPermanently makes the detach survive server restarts.
+func (q *detachTableQueryBuilder) WithPermanently(permanently bool) DetachTableQueryBuilder {
+	q.permanently = permanently
+	return q
+}
+
+func (q *detachTableQueryBuilder) Build() (string, error) {
+	if q.databaseName == "" {
+		return "", errors.New("databaseName cannot be empty for DETACH TABLE queries")
+	}
+	if q.tableName == "" {
+		return "", errors.New("tableName cannot be empty for DETACH TABLE queries")
+	}
+
+	tokens := []string{
+		"DETACH",
+		"TABLE",
+		backtick(q.databaseName) + "." + backtick(q.tableName),
+	}
+
+	if q.clusterName != nil {
+		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
+	}
+
+	if q.permanently {
+		tokens = append(tokens, "PERMANENTLY")
+	}
+
+	return strings.Join(tokens, " ") + ";", nil
+}
+
+// AttachTableQueryBuilder is an interface to build ATTACH TABLE SQL queries (already interpolated).
+type AttachTableQueryBuilder interface {
+	QueryBuilder
+	WithCluster(clusterName *string) AttachTableQueryBuilder
+}
+
+type attachTableQueryBuilder struct {
+	databaseName string
+	tableName    string
+	clusterName  *string
+}
+
+func NewAttachTable(databaseName, tableName string) AttachTableQueryBuilder {
+	return &attachTableQueryBuilder{
+		databaseName: databaseName,
+		tableName:    tableName,
+	}
+}
+
+func (q *attachTableQueryBuilder) WithCluster(clusterName *string) AttachTableQueryBuilder {
+	q.clusterName = clusterName
+	return q
+}
+
+func (q *attachTableQueryBuilder) Build() (string, error) {
+	if q.databaseName == "" {
+		return "", errors.New("databaseName cannot be empty for ATTACH TABLE queries")
+	}
+	if q.tableName == "" {
+		return "", errors.New("tableName cannot be empty for ATTACH TABLE queries")
+	}
+
+	tokens := []string{
+		"ATTACH",
+		"TABLE",
+		backtick(q.databaseName) + "." + backtick(q.tableName),
+	}
+
+	if q.clusterName != nil {
+		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
+	}
+
+	return strings.Join(tokens, " ") + ";", nil
+}
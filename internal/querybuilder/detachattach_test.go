@@ -0,0 +1,93 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func TestDetachTableQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder DetachTableQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "simple detach table",
+			builder: NewDetachTable("mydb", "mytable"),
+			want:    "DETACH TABLE `mydb`.`mytable`;",
+			wantErr: false,
+		},
+		{
+			name:    "detach table permanently on cluster",
+			builder: NewDetachTable("mydb", "mytable").WithCluster(stringPtr("my_cluster")).WithPermanently(true),
+			want:    "DETACH TABLE `mydb`.`mytable` ON CLUSTER 'my_cluster' PERMANENTLY;",
+			wantErr: false,
+		},
+		{
+			name:    "error: empty database name",
+			builder: NewDetachTable("", "mytable"),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "error: empty table name",
+			builder: NewDetachTable("mydb", ""),
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DetachTableQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("DetachTableQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttachTableQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder AttachTableQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "simple attach table",
+			builder: NewAttachTable("mydb", "mytable"),
+			want:    "ATTACH TABLE `mydb`.`mytable`;",
+			wantErr: false,
+		},
+		{
+			name:    "attach table on cluster",
+			builder: NewAttachTable("mydb", "my`table").WithCluster(stringPtr("my_cluster")),
+			want:    "ATTACH TABLE `mydb`.`my\\`table` ON CLUSTER 'my_cluster';",
+			wantErr: false,
+		},
+		{
+			name:    "error: empty table name",
+			builder: NewAttachTable("mydb", ""),
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AttachTableQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("AttachTableQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
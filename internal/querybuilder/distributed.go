@@ -0,0 +1,15 @@
+package querybuilder
+
+import (
+	"fmt"
+)
+
+// DistributedEngine returns the engine of a Distributed table spreading the rows of databaseName.tableName across
+// the shards of the cluster according to shardingKey, which is emitted as-is.
+func DistributedEngine(clusterName string, databaseName string, tableName string, shardingKey string) string {
+	if shardingKey == "" {
+		return fmt.Sprintf("Distributed(%s, %s, %s)", quote(clusterName), quote(databaseName), quote(tableName))
+	}
+
+	return fmt.Sprintf("Distributed(%s, %s, %s, %s)", quote(clusterName), quote(databaseName), quote(tableName), shardingKey)
+}
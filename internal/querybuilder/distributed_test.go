@@ -0,0 +1,48 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func TestDistributedEngine(t *testing.T) {
+	tests := []struct {
+		name         string
+		clusterName  string
+		databaseName string
+		tableName    string
+		shardingKey  string
+		want         string
+	}{
+		{
+			name:         "With sharding key",
+			clusterName:  "default",
+			databaseName: "analytics",
+			tableName:    "events_local",
+			shardingKey:  "cityHash64(user_id)",
+			want:         "Distributed('default', 'analytics', 'events_local', cityHash64(user_id))",
+		},
+		{
+			name:         "Without sharding key",
+			clusterName:  "default",
+			databaseName: "analytics",
+			tableName:    "events_local",
+			shardingKey:  "",
+			want:         "Distributed('default', 'analytics', 'events_local')",
+		},
+		{
+			name:         "Quotes are escaped",
+			clusterName:  "default",
+			databaseName: "analytics",
+			tableName:    "it's",
+			shardingKey:  "rand()",
+			want:         "Distributed('default', 'analytics', 'it\\'s', rand())",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DistributedEngine(tt.clusterName, tt.databaseName, tt.tableName, tt.shardingKey); got != tt.want {
+				t.Errorf("DistributedEngine() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -10,39 +10,61 @@ import (
 type DropTableQueryBuilder interface {
 	QueryBuilder
 	WithCluster(clusterName *string) DropTableQueryBuilder
+	WithIfExists() DropTableQueryBuilder
 }
 
 type dropTableQueryBuilder struct {
+	kind         string
 	databaseName string
 	tableName    string
 	clusterName  *string
+	ifExists     bool
 }
 
 func NewDropTable(databaseName, tableName string) DropTableQueryBuilder {
 	return &dropTableQueryBuilder{
+		kind:         "TABLE",
 		databaseName: databaseName,
 		tableName:    tableName,
 	}
 }
 
+// NewDropDictionary builds a DROP DICTIONARY query.
+func NewDropDictionary(databaseName, dictionaryName string) DropTableQueryBuilder {
+	return &dropTableQueryBuilder{
+		kind:         "DICTIONARY",
+		databaseName: databaseName,
+		tableName:    dictionaryName,
+	}
+}
+
 func (q *dropTableQueryBuilder) WithCluster(clusterName *string) DropTableQueryBuilder {
 	q.clusterName = clusterName
 	return q
 }
 
+// WithIfExists makes the query a no-op when the table does not exist.
+func (q *dropTableQueryBuilder) WithIfExists() DropTableQueryBuilder {
+	q.ifExists = true
+	return q
+}
+
 func (q *dropTableQueryBuilder) Build() (string, error) {
 	if q.databaseName == "" {
-		return "", errors.New("databaseName cannot be empty for DROP TABLE queries")
+		return "", errors.New("databaseName cannot be empty for DROP " + q.kind + " queries")
 	}
 	if q.tableName == "" {
-		return "", errors.New("tableName cannot be empty for DROP TABLE queries")
+		return "", errors.New("tableName cannot be empty for DROP " + q.kind + " queries")
 	}
 
 	tokens := []string{
 		"DROP",
-		"TABLE",
-		backtick(q.databaseName) + "." + backtick(q.tableName),
+		q.kind,
+	}
+	if q.ifExists {
+		tokens = append(tokens, "IF", "EXISTS")
 	}
+	tokens = append(tokens, backtick(q.databaseName)+"."+backtick(q.tableName))
 
 	if q.clusterName != nil {
 		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
@@ -23,6 +23,18 @@ func TestDropTableQueryBuilder_Build(t *testing.T) {
 			want:    "DROP TABLE `mydb`.`distributed_table` ON CLUSTER 'my_cluster';",
 			wantErr: false,
 		},
+		{
+			name:    "drop table if exists with cluster",
+			builder: NewDropTable("mydb", "mytable").WithCluster(stringPtr("my_cluster")).WithIfExists(),
+			want:    "DROP TABLE IF EXISTS `mydb`.`mytable` ON CLUSTER 'my_cluster';",
+			wantErr: false,
+		},
+		{
+			name:    "drop dictionary if exists with cluster",
+			builder: NewDropDictionary("mydb", "mydict").WithCluster(stringPtr("my_cluster")).WithIfExists(),
+			want:    "DROP DICTIONARY IF EXISTS `mydb`.`mydict` ON CLUSTER 'my_cluster';",
+			wantErr: false,
+		},
 		{
 			name:    "drop table with special characters in names",
 			builder: NewDropTable("my-db", "my.table"),
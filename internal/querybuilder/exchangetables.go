@@ -0,0 +1,60 @@
+package querybuilder
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// ExchangeTablesQueryBuilder is an interface to build EXCHANGE TABLES SQL queries (already interpolated).
+type ExchangeTablesQueryBuilder interface {
+	QueryBuilder
+	WithCluster(clusterName *string) ExchangeTablesQueryBuilder
+}
+
+type exchangeTablesQueryBuilder struct {
+	databaseName string
+	tableName    string
+	otherName    string
+	clusterName  *string
+}
+
+// NewExchangeTables swaps the names of two tables of the same database atomically. It requires the Atomic database engine.
+func NewExchangeTables(databaseName string, tableName string, otherName string) ExchangeTablesQueryBuilder {
+	return &exchangeTablesQueryBuilder{
+		databaseName: databaseName,
+		tableName:    tableName,
+		otherName:    otherName,
+	}
+}
+
+func (q *exchangeTablesQueryBuilder) WithCluster(clusterName *string) ExchangeTablesQueryBuilder {
+	q.clusterName = clusterName
+	return q
+}
+
+func (q *exchangeTablesQueryBuilder) Build() (string, error) {
+	if q.databaseName == "" {
+		return "", errors.New("databaseName cannot be empty for EXCHANGE TABLES queries")
+	}
+	if q.tableName == "" || q.otherName == "" {
+		return "", errors.New("table names cannot be empty for EXCHANGE TABLES queries")
+	}
+	if q.tableName == q.otherName {
+		return "", errors.New("cannot exchange a table with itself")
+	}
+
+	tokens := []string{
+		"EXCHANGE",
+		"TABLES",
+		backtick(q.databaseName) + "." + backtick(q.tableName),
+		"AND",
+		backtick(q.databaseName) + "." + backtick(q.otherName),
+	}
+
+	if q.clusterName != nil {
+		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
+	}
+
+	return strings.Join(tokens, " ") + ";", nil
+}
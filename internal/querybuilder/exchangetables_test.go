@@ -0,0 +1,58 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func TestExchangeTablesQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder ExchangeTablesQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "exchange tables",
+			builder: NewExchangeTables("mydb", "events", "events_shadow"),
+			want:    "EXCHANGE TABLES `mydb`.`events` AND `mydb`.`events_shadow`;",
+			wantErr: false,
+		},
+		{
+			name:    "exchange tables with cluster",
+			builder: NewExchangeTables("mydb", "events", "events_shadow").WithCluster(stringPtr("my_cluster")),
+			want:    "EXCHANGE TABLES `mydb`.`events` AND `mydb`.`events_shadow` ON CLUSTER 'my_cluster';",
+			wantErr: false,
+		},
+		{
+			name:    "error: empty database name",
+			builder: NewExchangeTables("", "events", "events_shadow"),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "error: empty table name",
+			builder: NewExchangeTables("mydb", "events", ""),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "error: same table",
+			builder: NewExchangeTables("mydb", "events", "events"),
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExchangeTablesQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ExchangeTablesQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
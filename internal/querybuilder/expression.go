@@ -0,0 +1,51 @@
+package querybuilder
+
+import (
+	"fmt"
+
+	"github.com/pingcap/errors"
+)
+
+// CheckExpression returns an error unless expr is a single SQL expression that can be embedded in parentheses
+// without changing the meaning of the surrounding query: its parentheses, brackets and quotes are balanced and never
+// close more than they opened, and it holds no statement separator or comment. It does not check that the
+// expression is valid, which ClickHouse does when running the query.
+func CheckExpression(expr string) error {
+	var closing []byte
+	var quote byte
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			closing = append(closing, ')')
+		case c == '[':
+			closing = append(closing, ']')
+		case c == ')' || c == ']':
+			if len(closing) == 0 || closing[len(closing)-1] != c {
+				return errors.New(fmt.Sprintf("unexpected %q at offset %d", c, i))
+			}
+			closing = closing[:len(closing)-1]
+		case c == ';':
+			return errors.New("statement separators are not allowed")
+		case c == '#' || (c == '-' && i+1 < len(expr) && expr[i+1] == '-') || (c == '/' && i+1 < len(expr) && expr[i+1] == '*'):
+			return errors.New("comments are not allowed")
+		}
+	}
+
+	switch {
+	case quote != 0:
+		return errors.New("unterminated quote")
+	case len(closing) > 0:
+		return errors.New(fmt.Sprintf("missing %q", closing[len(closing)-1]))
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func TestCheckExpression(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr bool
+	}{
+		{
+			name: "Interval arithmetic",
+			expr: "now() - INTERVAL 90 DAY",
+		},
+		{
+			name: "Nested calls and arrays",
+			expr: "toStartOfDay(arrayMax([now(), toDateTime('2024-01-01 00:00:00')]))",
+		},
+		{
+			name: "Separators and comments in strings",
+			expr: "parseDateTimeBestEffort('2024-01-01; -- /* # ')",
+		},
+		{
+			name: "Escaped quote in string",
+			expr: "toDateTime('it\\'s') - 1",
+		},
+		{
+			name:    "Breaks out of the parentheses",
+			expr:    "now()) OR (1",
+			wantErr: true,
+		},
+		{
+			name:    "Mismatched brackets",
+			expr:    "arrayMax([now())]",
+			wantErr: true,
+		},
+		{
+			name:    "Missing closing parenthesis",
+			expr:    "toDateTime(now()",
+			wantErr: true,
+		},
+		{
+			name:    "Statement separator",
+			expr:    "now(); DROP TABLE events",
+			wantErr: true,
+		},
+		{
+			name:    "Line comment",
+			expr:    "now() -- ignored",
+			wantErr: true,
+		},
+		{
+			name:    "Block comment",
+			expr:    "now() /* ignored */",
+			wantErr: true,
+		},
+		{
+			name:    "Unterminated quote",
+			expr:    "toDateTime('2024-01-01)",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckExpression(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckExpression() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,5 +1,9 @@
 package querybuilder
 
+import (
+	"fmt"
+)
+
 type Field interface {
 	SQLDef() string
 }
@@ -17,3 +21,37 @@ func NewField(name string) Field {
 func (f *field) SQLDef() string {
 	return backtick(f.name)
 }
+
+type aliasedField struct {
+	name  string
+	alias string
+}
+
+// NewAliasedField selects the field name under a different name, i.e. `name` AS `alias`.
+func NewAliasedField(name string, alias string) Field {
+	return &aliasedField{
+		name:  name,
+		alias: alias,
+	}
+}
+
+func (f *aliasedField) SQLDef() string {
+	return fmt.Sprintf("%s AS %s", backtick(f.name), backtick(f.alias))
+}
+
+type expressionField struct {
+	expression string
+	alias      string
+}
+
+// NewExpressionField selects an SQL expression under the given name. The expression is emitted as-is.
+func NewExpressionField(expression string, alias string) Field {
+	return &expressionField{
+		expression: expression,
+		alias:      alias,
+	}
+}
+
+func (f *expressionField) SQLDef() string {
+	return fmt.Sprintf("%s AS %s", f.expression, backtick(f.alias))
+}
@@ -0,0 +1,54 @@
+package querybuilder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// InsertSelectQueryBuilder is an interface to build INSERT INTO ... SELECT SQL queries (already interpolated).
+type InsertSelectQueryBuilder interface {
+	QueryBuilder
+}
+
+type insertSelectQueryBuilder struct {
+	databaseName string
+	tableName    string
+	sourceName   string
+	columns      []string
+}
+
+// NewInsertSelect copies the given columns from the source table into a table of the same database.
+func NewInsertSelect(databaseName string, tableName string, sourceName string, columns []string) InsertSelectQueryBuilder {
+	return &insertSelectQueryBuilder{
+		databaseName: databaseName,
+		tableName:    tableName,
+		sourceName:   sourceName,
+		columns:      columns,
+	}
+}
+
+func (q *insertSelectQueryBuilder) Build() (string, error) {
+	if q.databaseName == "" {
+		return "", errors.New("databaseName cannot be empty for INSERT queries")
+	}
+	if q.tableName == "" || q.sourceName == "" {
+		return "", errors.New("table names cannot be empty for INSERT queries")
+	}
+	if len(q.columns) == 0 {
+		return "", errors.New("at least one column is required for INSERT queries")
+	}
+
+	columns := make([]string, 0, len(q.columns))
+	for _, c := range q.columns {
+		columns = append(columns, backtick(c))
+	}
+	columnList := strings.Join(columns, ", ")
+
+	return fmt.Sprintf(
+		"INSERT INTO %s.%s (%s) SELECT %s FROM %s.%s;",
+		backtick(q.databaseName), backtick(q.tableName), columnList,
+		columnList, backtick(q.databaseName), backtick(q.sourceName),
+	), nil
+}
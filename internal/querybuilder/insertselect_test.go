@@ -0,0 +1,46 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func TestInsertSelectQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder InsertSelectQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "copy columns",
+			builder: NewInsertSelect("mydb", "events_shadow", "events", []string{"timestamp", "user id"}),
+			want:    "INSERT INTO `mydb`.`events_shadow` (`timestamp`, `user id`) SELECT `timestamp`, `user id` FROM `mydb`.`events`;",
+			wantErr: false,
+		},
+		{
+			name:    "error: no columns",
+			builder: NewInsertSelect("mydb", "events_shadow", "events", nil),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "error: empty source table",
+			builder: NewInsertSelect("mydb", "events_shadow", "", []string{"timestamp"}),
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("InsertSelectQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("InsertSelectQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
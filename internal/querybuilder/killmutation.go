@@ -0,0 +1,61 @@
+package querybuilder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// KillMutationQueryBuilder is an interface to build KILL MUTATION SQL queries (already interpolated).
+type KillMutationQueryBuilder interface {
+	QueryBuilder
+	WithCluster(clusterName *string) KillMutationQueryBuilder
+	WithSync(sync bool) KillMutationQueryBuilder
+}
+
+type killMutationQueryBuilder struct {
+	where       []Where
+	sync        bool
+	clusterName *string
+}
+
+// NewKillMutation builds a KILL MUTATION query cancelling the mutations matching all of the where clauses.
+func NewKillMutation(where ...Where) KillMutationQueryBuilder {
+	return &killMutationQueryBuilder{
+		where: where,
+	}
+}
+
+func (q *killMutationQueryBuilder) WithCluster(clusterName *string) KillMutationQueryBuilder {
+	q.clusterName = clusterName
+	return q
+}
+
+// WithSync makes the query wait for the mutations to be stopped before returning.
+func (q *killMutationQueryBuilder) WithSync(sync bool) KillMutationQueryBuilder {
+	q.sync = sync
+	return q
+}
+
+func (q *killMutationQueryBuilder) Build() (string, error) {
+	if len(q.where) == 0 {
+		return "", errors.New("at least one where clause is required to kill mutations")
+	}
+
+	tokens := []string{
+		"KILL MUTATION",
+	}
+
+	if q.clusterName != nil {
+		tokens = append(tokens, "ON CLUSTER", quote(*q.clusterName))
+	}
+
+	tokens = append(tokens, "WHERE", AndWhere(q.where...).Clause())
+
+	if q.sync {
+		tokens = append(tokens, "SYNC")
+	}
+
+	return fmt.Sprintf("%s;", strings.Join(tokens, " ")), nil
+}
@@ -0,0 +1,45 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func Test_killMutationQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder KillMutationQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Single mutation",
+			builder: NewKillMutation(WhereEquals("database", "db"), WhereEquals("table", "tbl"), WhereEquals("mutation_id", "mutation_3.txt")),
+			want:    "KILL MUTATION WHERE (`database` = 'db' AND `table` = 'tbl' AND `mutation_id` = 'mutation_3.txt');",
+			wantErr: false,
+		},
+		{
+			name:    "On cluster and sync",
+			builder: NewKillMutation(WhereEquals("database", "db")).WithCluster(stringPtr("cluster1")).WithSync(true),
+			want:    "KILL MUTATION ON CLUSTER 'cluster1' WHERE (`database` = 'db') SYNC;",
+			wantErr: false,
+		},
+		{
+			name:    "No where clause",
+			builder: NewKillMutation(),
+			want:    "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Build() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package querybuilder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+const (
+	// MaskHash replaces the values of a column with the hex encoded SHA-256 of their string representation.
+	MaskHash = "hash"
+	// MaskRedact replaces the values of a column with a constant string.
+	MaskRedact = "redact"
+	// MaskExpression replaces the values of a column with a custom expression, emitted as-is.
+	MaskExpression = "expression"
+
+	redactedValue = "[REDACTED]"
+)
+
+// MaskMethods are the supported ways to mask a column.
+var MaskMethods = []string{MaskHash, MaskRedact, MaskExpression}
+
+// ColumnMask is how the values of a column are masked.
+type ColumnMask struct {
+	Method string
+	// Expression is the SQL expression replacing the column with MaskExpression, ignored otherwise.
+	Expression string
+}
+
+// MaskedSelectQueryBuilder is an interface to build the SELECT query of a view exposing the columns of a table with
+// some of them masked (already interpolated).
+type MaskedSelectQueryBuilder interface {
+	QueryBuilder
+	WithMaskedColumns(masks map[string]ColumnMask) MaskedSelectQueryBuilder
+	WithExcludedColumns(columnNames []string) MaskedSelectQueryBuilder
+}
+
+type maskedSelectQueryBuilder struct {
+	databaseName    string
+	tableName       string
+	masks           map[string]ColumnMask
+	excludedColumns []string
+}
+
+// NewMaskedSelect builds a `SELECT * EXCEPT (...) REPLACE (...)` query reading every column of the table, the excluded
+// ones left out and the masked ones replaced by their mask under the same name.
+func NewMaskedSelect(databaseName string, tableName string) MaskedSelectQueryBuilder {
+	return &maskedSelectQueryBuilder{
+		databaseName: databaseName,
+		tableName:    tableName,
+	}
+}
+
+func (q *maskedSelectQueryBuilder) WithMaskedColumns(masks map[string]ColumnMask) MaskedSelectQueryBuilder {
+	q.masks = masks
+	return q
+}
+
+func (q *maskedSelectQueryBuilder) WithExcludedColumns(columnNames []string) MaskedSelectQueryBuilder {
+	q.excludedColumns = columnNames
+	return q
+}
+
+func (q *maskedSelectQueryBuilder) Build() (string, error) {
+	if q.databaseName == "" {
+		return "", errors.New("databaseName cannot be empty for masked SELECT queries")
+	}
+	if q.tableName == "" {
+		return "", errors.New("tableName cannot be empty for masked SELECT queries")
+	}
+
+	tokens := []string{"SELECT", "*"}
+
+	if len(q.excludedColumns) > 0 {
+		excluded := make([]string, 0, len(q.excludedColumns))
+		for _, columnName := range q.excludedColumns {
+			if columnName == "" {
+				return "", errors.New("excluded column name cannot be empty for masked SELECT queries")
+			}
+			if _, ok := q.masks[columnName]; ok {
+				return "", errors.New(fmt.Sprintf("column %q cannot be both masked and excluded", columnName))
+			}
+			excluded = append(excluded, backtick(columnName))
+		}
+		tokens = append(tokens, "EXCEPT", "("+strings.Join(excluded, ", ")+")")
+	}
+
+	if len(q.masks) > 0 {
+		columnNames := make([]string, 0, len(q.masks))
+		for columnName := range q.masks {
+			columnNames = append(columnNames, columnName)
+		}
+		sort.Strings(columnNames)
+
+		replaced := make([]string, 0, len(columnNames))
+		for _, columnName := range columnNames {
+			if columnName == "" {
+				return "", errors.New("masked column name cannot be empty for masked SELECT queries")
+			}
+
+			expression, err := maskExpression(columnName, q.masks[columnName])
+			if err != nil {
+				return "", err
+			}
+			replaced = append(replaced, expression+" AS "+backtick(columnName))
+		}
+		tokens = append(tokens, "REPLACE", "("+strings.Join(replaced, ", ")+")")
+	}
+
+	tokens = append(tokens, "FROM", backtick(q.databaseName)+"."+backtick(q.tableName))
+
+	return strings.Join(tokens, " ") + ";", nil
+}
+
+// maskExpression returns the expression replacing the given column.
+func maskExpression(columnName string, mask ColumnMask) (string, error) {
+	switch mask.Method {
+	case MaskHash:
+		return fmt.Sprintf("hex(SHA256(toString(%s)))", backtick(columnName)), nil
+	case MaskRedact:
+		return quote(redactedValue), nil
+	case MaskExpression:
+		expression := strings.TrimSpace(mask.Expression)
+		if expression == "" {
+			return "", errors.New(fmt.Sprintf("expression cannot be empty to mask column %q", columnName))
+		}
+		return "(" + expression + ")", nil
+	default:
+		return "", errors.New(fmt.Sprintf("unknown mask method %q for column %q", mask.Method, columnName))
+	}
+}
@@ -0,0 +1,68 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func TestMaskedSelectQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder MaskedSelectQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "no masked column",
+			builder: NewMaskedSelect("mydb", "users"),
+			want:    "SELECT * FROM `mydb`.`users`;",
+			wantErr: false,
+		},
+		{
+			name: "masked and excluded columns",
+			builder: NewMaskedSelect("mydb", "users").
+				WithMaskedColumns(map[string]ColumnMask{
+					"phone": {Method: MaskRedact},
+					"email": {Method: MaskHash},
+					"ip":    {Method: MaskExpression, Expression: "IPv4CIDRToRange(ip, 24).1"},
+				}).
+				WithExcludedColumns([]string{"password_hash"}),
+			want:    "SELECT * EXCEPT (`password_hash`) REPLACE (hex(SHA256(toString(`email`))) AS `email`, (IPv4CIDRToRange(ip, 24).1) AS `ip`, '[REDACTED]' AS `phone`) FROM `mydb`.`users`;",
+			wantErr: false,
+		},
+		{
+			name:    "error: empty table name",
+			builder: NewMaskedSelect("mydb", ""),
+			wantErr: true,
+		},
+		{
+			name:    "error: empty expression",
+			builder: NewMaskedSelect("mydb", "users").WithMaskedColumns(map[string]ColumnMask{"email": {Method: MaskExpression}}),
+			wantErr: true,
+		},
+		{
+			name:    "error: unknown method",
+			builder: NewMaskedSelect("mydb", "users").WithMaskedColumns(map[string]ColumnMask{"email": {Method: "shuffle"}}),
+			wantErr: true,
+		},
+		{
+			name: "error: column masked and excluded",
+			builder: NewMaskedSelect("mydb", "users").
+				WithMaskedColumns(map[string]ColumnMask{"email": {Method: MaskHash}}).
+				WithExcludedColumns([]string{"email"}),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MaskedSelectQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("MaskedSelectQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package querybuilder
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MaterializedPostgreSQLSettings are the engine settings of a MaterializedPostgreSQL database, nil fields are left
+// to their server default.
+type MaterializedPostgreSQLSettings struct {
+	TablesList           []string
+	TablesListWithSchema *bool
+	Schema               *string
+	SchemaList           []string
+	ReplicationSlot      *string
+	Snapshot             *string
+	MaxBlockSize         *int64
+	AllowAutomaticUpdate *bool
+}
+
+// Settings returns the SQL literal of every set field keyed by setting name, for use with WithSettings and
+// NewAlterDatabaseModifySetting.
+func (s MaterializedPostgreSQLSettings) Settings() map[string]string {
+	settings := make(map[string]string)
+
+	if s.TablesList != nil {
+		settings["materialized_postgresql_tables_list"] = quote(strings.Join(s.TablesList, ","))
+	}
+	if s.TablesListWithSchema != nil {
+		settings["materialized_postgresql_tables_list_with_schema"] = boolLiteral(*s.TablesListWithSchema)
+	}
+	if s.Schema != nil {
+		settings["materialized_postgresql_schema"] = quote(*s.Schema)
+	}
+	if s.SchemaList != nil {
+		settings["materialized_postgresql_schema_list"] = quote(strings.Join(s.SchemaList, ","))
+	}
+	if s.ReplicationSlot != nil {
+		settings["materialized_postgresql_replication_slot"] = quote(*s.ReplicationSlot)
+	}
+	if s.Snapshot != nil {
+		settings["materialized_postgresql_snapshot"] = quote(*s.Snapshot)
+	}
+	if s.MaxBlockSize != nil {
+		settings["materialized_postgresql_max_block_size"] = strconv.FormatInt(*s.MaxBlockSize, 10)
+	}
+	if s.AllowAutomaticUpdate != nil {
+		settings["materialized_postgresql_allow_automatic_update"] = boolLiteral(*s.AllowAutomaticUpdate)
+	}
+
+	return settings
+}
+
+func boolLiteral(b bool) string {
+	if b {
+		return "1"
+	}
+
+	return "0"
+}
@@ -0,0 +1,64 @@
+package querybuilder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMaterializedPostgreSQLSettings_Settings(t *testing.T) {
+	schema := "public"
+	slot := "clickhouse_slot"
+	maxBlockSize := int64(8192)
+	enabled := true
+
+	tests := []struct {
+		name     string
+		settings MaterializedPostgreSQLSettings
+		want     map[string]string
+	}{
+		{
+			name:     "Nothing set",
+			settings: MaterializedPostgreSQLSettings{},
+			want:     map[string]string{},
+		},
+		{
+			name: "Everything set",
+			settings: MaterializedPostgreSQLSettings{
+				TablesList:           []string{"users", "orders"},
+				TablesListWithSchema: &enabled,
+				Schema:               &schema,
+				SchemaList:           []string{"public", "billing"},
+				ReplicationSlot:      &slot,
+				Snapshot:             &slot,
+				MaxBlockSize:         &maxBlockSize,
+				AllowAutomaticUpdate: &enabled,
+			},
+			want: map[string]string{
+				"materialized_postgresql_tables_list":             "'users,orders'",
+				"materialized_postgresql_tables_list_with_schema": "1",
+				"materialized_postgresql_schema":                  "'public'",
+				"materialized_postgresql_schema_list":             "'public,billing'",
+				"materialized_postgresql_replication_slot":        "'clickhouse_slot'",
+				"materialized_postgresql_snapshot":                "'clickhouse_slot'",
+				"materialized_postgresql_max_block_size":          "8192",
+				"materialized_postgresql_allow_automatic_update":  "1",
+			},
+		},
+		{
+			name: "Quotes are escaped",
+			settings: MaterializedPostgreSQLSettings{
+				TablesList: []string{"it's"},
+			},
+			want: map[string]string{
+				"materialized_postgresql_tables_list": "'it\\'s'",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.settings.Settings(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Settings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package querybuilder
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// OptimizeTableQueryBuilder is an interface to build OPTIMIZE TABLE SQL queries (already interpolated).
+type OptimizeTableQueryBuilder interface {
+	QueryBuilder
+	WithCluster(clusterName *string) OptimizeTableQueryBuilder
+	WithPartition(partition *string) OptimizeTableQueryBuilder
+	WithFinal(final bool) OptimizeTableQueryBuilder
+	WithDeduplicate(deduplicate bool) OptimizeTableQueryBuilder
+}
+
+type optimizeTableQueryBuilder struct {
+	databaseName string
+	tableName    string
+	clusterName  *string
+	partition    *string
+	final        bool
+	deduplicate  bool
+}
+
+func NewOptimizeTable(databaseName string, tableName string) OptimizeTableQueryBuilder {
+	return &optimizeTableQueryBuilder{
+		databaseName: databaseName,
+		tableName:    tableName,
+	}
+}
+
+func (q *optimizeTableQueryBuilder) WithCluster(clusterName *string) OptimizeTableQueryBuilder {
+	q.clusterName = clusterName
+	return q
+}
+
+// WithPartition restricts the merge to one partition. The partition expression is emitted as-is, e.g. `'2024-01'`,
+// `tuple(2024, 1)` or `ID '202401'`.
+func (q *optimizeTableQueryBuilder) WithPartition(partition *string) OptimizeTableQueryBuilder {
+	q.partition = partition
+	return q
+}
+
+// WithFinal forces a merge even if all data is already in a single part.
+func (q *optimizeTableQueryBuilder) WithFinal(final bool) OptimizeTableQueryBuilder {
+	q.final = final
+	return q
+}
+
+// WithDeduplicate removes fully identical rows while merging.
+func (q *optimizeTableQueryBuilder) WithDeduplicate(deduplicate bool) OptimizeTableQueryBuilder {
+	q.deduplicate = deduplicate
+	return q
+}
+
+func (q *optimizeTableQueryBuilder) Build() (string, error) {
+	if q.databaseName == "" {
+		return "", errors.New("databaseName cannot be empty for OPTIMIZE TABLE queries")
+	}
+	if q.tableName == "" {
+		return "", errors.New("tableName cannot be empty for OPTIMIZE TABLE queries")
+	}
+	if q.partition != nil && *q.partition == "" {
+		return "", errors.New("partition cannot be empty when set for OPTIMIZE TABLE queries")
+	}
+
+	tokens := []string{
+		"OPTIMIZE",
+		"TABLE",
+		backtick(q.databaseName) + "." + backtick(q.tableName),
+	}
+
+	if q.clusterName != nil {
+		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
+	}
+
+	if q.partition != nil {
+		tokens = append(tokens, "PARTITION", *q.partition)
+	}
+
+	if q.final {
+		tokens = append(tokens, "FINAL")
+	}
+
+	if q.deduplicate {
+		tokens = append(tokens, "DEDUPLICATE")
+	}
+
+	return strings.Join(tokens, " ") + ";", nil
+}
@@ -0,0 +1,70 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func TestOptimizeTableQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder OptimizeTableQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "simple optimize",
+			builder: NewOptimizeTable("mydb", "mytable"),
+			want:    "OPTIMIZE TABLE `mydb`.`mytable`;",
+			wantErr: false,
+		},
+		{
+			name:    "optimize final on cluster",
+			builder: NewOptimizeTable("mydb", "mytable").WithFinal(true).WithCluster(stringPtr("my_cluster")),
+			want:    "OPTIMIZE TABLE `mydb`.`mytable` ON CLUSTER 'my_cluster' FINAL;",
+			wantErr: false,
+		},
+		{
+			name:    "optimize partition final deduplicate",
+			builder: NewOptimizeTable("mydb", "mytable").WithPartition(stringPtr("'202401'")).WithFinal(true).WithDeduplicate(true),
+			want:    "OPTIMIZE TABLE `mydb`.`mytable` PARTITION '202401' FINAL DEDUPLICATE;",
+			wantErr: false,
+		},
+		{
+			name:    "optimize with special characters",
+			builder: NewOptimizeTable("my`db", "my.table"),
+			want:    "OPTIMIZE TABLE `my\\`db`.`my.table`;",
+			wantErr: false,
+		},
+		{
+			name:    "error: empty database",
+			builder: NewOptimizeTable("", "mytable"),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "error: empty table",
+			builder: NewOptimizeTable("mydb", ""),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "error: empty partition",
+			builder: NewOptimizeTable("mydb", "mytable").WithPartition(stringPtr("")),
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("OptimizeTableQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("OptimizeTableQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
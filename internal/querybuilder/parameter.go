@@ -0,0 +1,60 @@
+package querybuilder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Parameter is a value bound server side through a query parameter instead of being interpolated in the query.
+// It is rendered as the {name:Type} placeholder, the value must be sent along with the query.
+type Parameter struct {
+	Name  string
+	Type  string
+	Value string
+}
+
+// NewParameter returns a parameter to use as the value of WhereEquals and WhereDiffers clauses.
+func NewParameter(name string, typ string, value string) Parameter {
+	return Parameter{
+		Name:  name,
+		Type:  typ,
+		Value: value,
+	}
+}
+
+// NewArrayParameter returns an Array(elementType) parameter holding values, to use with WhereInParameter.
+func NewArrayParameter(name string, elementType string, values []string) Parameter {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, quote(v))
+	}
+
+	return Parameter{
+		Name:  name,
+		Type:  fmt.Sprintf("Array(%s)", elementType),
+		Value: fmt.Sprintf("[%s]", strings.Join(quoted, ",")),
+	}
+}
+
+func (p Parameter) placeholder() string {
+	return fmt.Sprintf("{%s:%s}", p.Name, p.Type)
+}
+
+// parameterized is implemented by the clauses holding parameters.
+type parameterized interface {
+	parameters() []Parameter
+}
+
+// collectParameters returns the values of the parameters used by the given clauses, keyed by name.
+func collectParameters(clauses ...Where) map[string]string {
+	ret := make(map[string]string)
+	for _, c := range clauses {
+		if p, ok := c.(parameterized); ok {
+			for _, param := range p.parameters() {
+				ret[param.Name] = param.Value
+			}
+		}
+	}
+
+	return ret
+}
@@ -0,0 +1,165 @@
+package querybuilder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// QuotaInterval is an interval of a quota and the maximum consumption allowed during it.
+type QuotaInterval struct {
+	// Duration is the length of the interval, in seconds.
+	Duration uint64
+	// Randomized makes the intervals start at a random time, instead of at multiples of Duration.
+	Randomized bool
+	// Limits are the maximums keyed by resource, e.g. queries or execution_time, rendered as is. An interval without
+	// limits only tracks the consumption.
+	Limits map[string]string
+	// NoLimits removes the interval from the quota, with ALTER QUOTA.
+	NoLimits bool
+}
+
+// QuotaQueryBuilder is an interface to build CREATE QUOTA and ALTER QUOTA SQL queries (already interpolated).
+type QuotaQueryBuilder interface {
+	QueryBuilder
+	WithKeyedBy(keyedBy *string) QuotaQueryBuilder
+	WithIntervals(intervals []QuotaInterval) QuotaQueryBuilder
+	WithApplyTo(grantees []string) QuotaQueryBuilder
+	WithApplyToAll(except []string) QuotaQueryBuilder
+	WithCluster(clusterName *string) QuotaQueryBuilder
+}
+
+type quotaQueryBuilder struct {
+	action      string
+	quotaName   string
+	keyedBy     *string
+	intervals   []QuotaInterval
+	applyToSet  bool
+	applyTo     []string
+	applyToAll  bool
+	clusterName *string
+}
+
+// NewCreateQuota builds a CREATE QUOTA query. The quota is NOT KEYED unless WithKeyedBy is called, and applies to no
+// one unless WithApplyTo or WithApplyToAll is called.
+func NewCreateQuota(quotaName string) QuotaQueryBuilder {
+	return &quotaQueryBuilder{
+		action:    actionCreate,
+		quotaName: quotaName,
+	}
+}
+
+// NewAlterQuota builds an ALTER QUOTA query replacing the key and the given intervals of the quota. The users and roles
+// the quota applies to are only changed when WithApplyTo or WithApplyToAll is called.
+func NewAlterQuota(quotaName string) QuotaQueryBuilder {
+	return &quotaQueryBuilder{
+		action:    "ALTER",
+		quotaName: quotaName,
+	}
+}
+
+// WithKeyedBy sets how the consumption is tracked, e.g. `user_name` or `client_key,ip_address`. Nil shares it among
+// every user of the quota (NOT KEYED).
+func (q *quotaQueryBuilder) WithKeyedBy(keyedBy *string) QuotaQueryBuilder {
+	q.keyedBy = keyedBy
+	return q
+}
+
+func (q *quotaQueryBuilder) WithIntervals(intervals []QuotaInterval) QuotaQueryBuilder {
+	q.intervals = intervals
+	return q
+}
+
+// WithApplyTo sets the users and roles the quota applies to.
+func (q *quotaQueryBuilder) WithApplyTo(grantees []string) QuotaQueryBuilder {
+	q.applyToSet = true
+	q.applyTo = grantees
+	q.applyToAll = false
+	return q
+}
+
+// WithApplyToAll makes the quota apply to every user and role but the given ones.
+func (q *quotaQueryBuilder) WithApplyToAll(except []string) QuotaQueryBuilder {
+	q.applyToSet = true
+	q.applyTo = except
+	q.applyToAll = true
+	return q
+}
+
+func (q *quotaQueryBuilder) WithCluster(clusterName *string) QuotaQueryBuilder {
+	q.clusterName = clusterName
+	return q
+}
+
+func (q *quotaQueryBuilder) Build() (string, error) {
+	if q.quotaName == "" {
+		return "", errors.New("quotaName cannot be empty for " + q.action + " QUOTA queries")
+	}
+
+	tokens := []string{
+		q.action,
+		"QUOTA",
+		backtick(q.quotaName),
+	}
+	if q.clusterName != nil {
+		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
+	}
+
+	if q.keyedBy != nil {
+		if strings.TrimSpace(*q.keyedBy) == "" {
+			return "", errors.New("keyedBy cannot be empty for " + q.action + " QUOTA queries")
+		}
+		tokens = append(tokens, "KEYED", "BY", *q.keyedBy)
+	} else {
+		tokens = append(tokens, "NOT", "KEYED")
+	}
+
+	intervals := make([]string, 0, len(q.intervals))
+	for _, interval := range q.intervals {
+		if interval.Duration == 0 {
+			return "", errors.New("interval duration cannot be 0 for " + q.action + " QUOTA queries")
+		}
+
+		clause := []string{"FOR"}
+		if interval.Randomized {
+			clause = append(clause, "RANDOMIZED")
+		}
+		clause = append(clause, "INTERVAL", fmt.Sprintf("%d", interval.Duration), "second")
+
+		switch {
+		case interval.NoLimits:
+			clause = append(clause, "NO", "LIMITS")
+		case len(interval.Limits) == 0:
+			clause = append(clause, "TRACKING", "ONLY")
+		default:
+			resources := make([]string, 0, len(interval.Limits))
+			for resource := range interval.Limits {
+				resources = append(resources, resource)
+			}
+			sort.Strings(resources)
+
+			limits := make([]string, 0, len(resources))
+			for _, resource := range resources {
+				limits = append(limits, fmt.Sprintf("%s = %s", resource, interval.Limits[resource]))
+			}
+			clause = append(clause, "MAX", strings.Join(limits, ", "))
+		}
+
+		intervals = append(intervals, strings.Join(clause, " "))
+	}
+	if len(intervals) > 0 {
+		tokens = append(tokens, strings.Join(intervals, ", "))
+	}
+
+	if q.applyToSet || q.action == actionCreate {
+		to, err := applyToClause(q.applyTo, q.applyToAll)
+		if err != nil {
+			return "", errors.WithMessage(err, "invalid "+q.action+" QUOTA query")
+		}
+		tokens = append(tokens, to...)
+	}
+
+	return strings.Join(tokens, " ") + ";", nil
+}
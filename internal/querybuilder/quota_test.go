@@ -0,0 +1,75 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func TestQuotaQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder QuotaQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "quota without intervals",
+			builder: NewCreateQuota("q"),
+			want:    "CREATE QUOTA `q` NOT KEYED TO NONE;",
+			wantErr: false,
+		},
+		{
+			name: "keyed quota with intervals on cluster",
+			builder: NewCreateQuota("q").
+				WithKeyedBy(stringPtr("user_name")).
+				WithIntervals([]QuotaInterval{
+					{Duration: 3600, Limits: map[string]string{"queries": "100", "errors": "10"}},
+					{Duration: 86400, Randomized: true, Limits: map[string]string{"execution_time": "1.5"}},
+					{Duration: 60},
+				}).
+				WithApplyTo([]string{"alice", "analyst"}).
+				WithCluster(stringPtr("my_cluster")),
+			want:    "CREATE QUOTA `q` ON CLUSTER 'my_cluster' KEYED BY user_name FOR INTERVAL 3600 second MAX errors = 10, queries = 100, FOR RANDOMIZED INTERVAL 86400 second MAX execution_time = 1.5, FOR INTERVAL 60 second TRACKING ONLY TO `alice`, `analyst`;",
+			wantErr: false,
+		},
+		{
+			name:    "alter quota keeping grantees",
+			builder: NewAlterQuota("q").WithIntervals([]QuotaInterval{{Duration: 3600, NoLimits: true}}),
+			want:    "ALTER QUOTA `q` NOT KEYED FOR INTERVAL 3600 second NO LIMITS;",
+			wantErr: false,
+		},
+		{
+			name:    "alter quota for all except admin",
+			builder: NewAlterQuota("q").WithKeyedBy(stringPtr("client_key,ip_address")).WithApplyToAll([]string{"admin"}),
+			want:    "ALTER QUOTA `q` KEYED BY client_key,ip_address TO ALL EXCEPT `admin`;",
+			wantErr: false,
+		},
+		{
+			name:    "error: empty quota name",
+			builder: NewCreateQuota(""),
+			wantErr: true,
+		},
+		{
+			name:    "error: zero duration",
+			builder: NewCreateQuota("q").WithIntervals([]QuotaInterval{{Duration: 0}}),
+			wantErr: true,
+		},
+		{
+			name:    "error: empty grantee",
+			builder: NewCreateQuota("q").WithApplyTo([]string{""}),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("QuotaQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("QuotaQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
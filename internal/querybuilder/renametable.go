@@ -0,0 +1,57 @@
+package querybuilder
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// RenameTableQueryBuilder is an interface to build RENAME TABLE SQL queries (already interpolated).
+type RenameTableQueryBuilder interface {
+	QueryBuilder
+	WithCluster(clusterName *string) RenameTableQueryBuilder
+}
+
+type renameTableQueryBuilder struct {
+	databaseName string
+	tableName    string
+	newName      string
+	clusterName  *string
+}
+
+// NewRenameTable renames a table, keeping it in the same database.
+func NewRenameTable(databaseName string, tableName string, newName string) RenameTableQueryBuilder {
+	return &renameTableQueryBuilder{
+		databaseName: databaseName,
+		tableName:    tableName,
+		newName:      newName,
+	}
+}
+
+func (q *renameTableQueryBuilder) WithCluster(clusterName *string) RenameTableQueryBuilder {
+	q.clusterName = clusterName
+	return q
+}
+
+func (q *renameTableQueryBuilder) Build() (string, error) {
+	if q.databaseName == "" {
+		return "", errors.New("databaseName cannot be empty for RENAME TABLE queries")
+	}
+	if q.tableName == "" || q.newName == "" {
+		return "", errors.New("table names cannot be empty for RENAME TABLE queries")
+	}
+
+	tokens := []string{
+		"RENAME",
+		"TABLE",
+		backtick(q.databaseName) + "." + backtick(q.tableName),
+		"TO",
+		backtick(q.databaseName) + "." + backtick(q.newName),
+	}
+
+	if q.clusterName != nil {
+		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
+	}
+
+	return strings.Join(tokens, " ") + ";", nil
+}
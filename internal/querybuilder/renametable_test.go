@@ -0,0 +1,52 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func TestRenameTableQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder RenameTableQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "rename table",
+			builder: NewRenameTable("mydb", "events", "events_v2"),
+			want:    "RENAME TABLE `mydb`.`events` TO `mydb`.`events_v2`;",
+			wantErr: false,
+		},
+		{
+			name:    "rename table with cluster",
+			builder: NewRenameTable("mydb", "events", "events_v2").WithCluster(stringPtr("my_cluster")),
+			want:    "RENAME TABLE `mydb`.`events` TO `mydb`.`events_v2` ON CLUSTER 'my_cluster';",
+			wantErr: false,
+		},
+		{
+			name:    "error: empty database name",
+			builder: NewRenameTable("", "events", "events_v2"),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "error: empty new name",
+			builder: NewRenameTable("mydb", "events", ""),
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RenameTableQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("RenameTableQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
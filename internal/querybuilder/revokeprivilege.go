@@ -14,6 +14,7 @@ type RevokePrivilegeQueryBuilder interface {
 	WithTable(*string) RevokePrivilegeQueryBuilder
 	WithColumn(*string) RevokePrivilegeQueryBuilder
 	WithCluster(*string) RevokePrivilegeQueryBuilder
+	GrantOptionOnly(bool) RevokePrivilegeQueryBuilder
 }
 
 type revokePrivilegeQueryBuilder struct {
@@ -23,6 +24,8 @@ type revokePrivilegeQueryBuilder struct {
 	table       *string
 	column      *string
 	clusterName *string
+	// grantOptionOnly revokes the grant option of the privilege but keeps the privilege itself.
+	grantOptionOnly bool
 }
 
 func RevokePrivilege(accessType string, from string) RevokePrivilegeQueryBuilder {
@@ -52,6 +55,11 @@ func (q *revokePrivilegeQueryBuilder) WithCluster(clusterName *string) RevokePri
 	return q
 }
 
+func (q *revokePrivilegeQueryBuilder) GrantOptionOnly(grantOptionOnly bool) RevokePrivilegeQueryBuilder {
+	q.grantOptionOnly = grantOptionOnly
+	return q
+}
+
 func (q *revokePrivilegeQueryBuilder) Build() (string, error) {
 	if q.accessType == "" {
 		return "", errors.New("AccessType cannot be empty")
@@ -68,6 +76,10 @@ func (q *revokePrivilegeQueryBuilder) Build() (string, error) {
 		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
 	}
 
+	if q.grantOptionOnly {
+		tokens = append(tokens, "GRANT", "OPTION", "FOR")
+	}
+
 	// Privilege
 	if q.column != nil && *q.column != "" {
 		tokens = append(tokens, fmt.Sprintf("%s(%s)", q.accessType, backtick(*q.column)))
@@ -35,6 +35,18 @@ func Test_revokePrivilegeQueryBuilder(t *testing.T) {
 			want:    "REVOKE SELECT(`test`) ON `db1`.`tbl1` FROM `user1`;",
 			wantErr: false,
 		},
+		{
+			name:    "Grant option only",
+			builder: RevokePrivilege("SELECT", "user1").WithDatabase(strptr("db1")).GrantOptionOnly(true),
+			want:    "REVOKE GRANT OPTION FOR SELECT ON `db1`.* FROM `user1`;",
+			wantErr: false,
+		},
+		{
+			name:    "Grant option only on cluster",
+			builder: RevokePrivilege("SELECT", "user1").WithCluster(strptr("cluster1")).GrantOptionOnly(true),
+			want:    "REVOKE ON CLUSTER 'cluster1' GRANT OPTION FOR SELECT ON *.* FROM `user1`;",
+			wantErr: false,
+		},
 		{
 			name:    "Missing access type",
 			builder: RevokePrivilege("", "user1"),
@@ -0,0 +1,192 @@
+package querybuilder
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// RowPolicyQueryBuilder is an interface to build CREATE ROW POLICY and ALTER ROW POLICY SQL queries (already
+// interpolated).
+type RowPolicyQueryBuilder interface {
+	QueryBuilder
+	WithRestrictive(restrictive bool) RowPolicyQueryBuilder
+	WithApplyTo(grantees []string) RowPolicyQueryBuilder
+	WithApplyToAll(except []string) RowPolicyQueryBuilder
+	WithCluster(clusterName *string) RowPolicyQueryBuilder
+}
+
+type rowPolicyQueryBuilder struct {
+	action       string
+	policyName   string
+	databaseName string
+	tableName    string
+	condition    string
+	restrictive  bool
+	applyTo      []string
+	applyToAll   bool
+	clusterName  *string
+}
+
+// NewCreateRowPolicy builds a CREATE ROW POLICY query filtering the rows of the table SELECT queries return with
+// the given condition. The policy applies to no one until WithApplyTo or WithApplyToAll is called.
+func NewCreateRowPolicy(policyName, databaseName, tableName, condition string) RowPolicyQueryBuilder {
+	return &rowPolicyQueryBuilder{
+		action:       actionCreate,
+		policyName:   policyName,
+		databaseName: databaseName,
+		tableName:    tableName,
+		condition:    condition,
+	}
+}
+
+// NewAlterRowPolicy builds an ALTER ROW POLICY query replacing the condition, kind and grantees of the policy.
+func NewAlterRowPolicy(policyName, databaseName, tableName, condition string) RowPolicyQueryBuilder {
+	return &rowPolicyQueryBuilder{
+		action:       "ALTER",
+		policyName:   policyName,
+		databaseName: databaseName,
+		tableName:    tableName,
+		condition:    condition,
+	}
+}
+
+// WithRestrictive makes the policy RESTRICTIVE: rows must match it on top of the permissive policies of the table.
+func (q *rowPolicyQueryBuilder) WithRestrictive(restrictive bool) RowPolicyQueryBuilder {
+	q.restrictive = restrictive
+	return q
+}
+
+// WithApplyTo sets the users and roles the policy applies to.
+func (q *rowPolicyQueryBuilder) WithApplyTo(grantees []string) RowPolicyQueryBuilder {
+	q.applyTo = grantees
+	q.applyToAll = false
+	return q
+}
+
+// WithApplyToAll makes the policy apply to every user and role but the given ones.
+func (q *rowPolicyQueryBuilder) WithApplyToAll(except []string) RowPolicyQueryBuilder {
+	q.applyTo = except
+	q.applyToAll = true
+	return q
+}
+
+func (q *rowPolicyQueryBuilder) WithCluster(clusterName *string) RowPolicyQueryBuilder {
+	q.clusterName = clusterName
+	return q
+}
+
+func (q *rowPolicyQueryBuilder) Build() (string, error) {
+	if q.policyName == "" {
+		return "", errors.New("policyName cannot be empty for " + q.action + " ROW POLICY queries")
+	}
+	if q.databaseName == "" {
+		return "", errors.New("databaseName cannot be empty for " + q.action + " ROW POLICY queries")
+	}
+	if q.tableName == "" {
+		return "", errors.New("tableName cannot be empty for " + q.action + " ROW POLICY queries")
+	}
+	if strings.TrimSpace(q.condition) == "" {
+		return "", errors.New("condition cannot be empty for " + q.action + " ROW POLICY queries")
+	}
+
+	tokens := []string{
+		q.action,
+		"ROW",
+		"POLICY",
+		backtick(q.policyName),
+	}
+	if q.clusterName != nil {
+		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
+	}
+	tokens = append(tokens, "ON", backtick(q.databaseName)+"."+backtick(q.tableName), "FOR", "SELECT", "USING", q.condition)
+
+	if q.restrictive {
+		tokens = append(tokens, "AS", "RESTRICTIVE")
+	} else {
+		tokens = append(tokens, "AS", "PERMISSIVE")
+	}
+
+	to, err := applyToClause(q.applyTo, q.applyToAll)
+	if err != nil {
+		return "", errors.WithMessage(err, "invalid "+q.action+" ROW POLICY query")
+	}
+	tokens = append(tokens, to...)
+
+	return strings.Join(tokens, " ") + ";", nil
+}
+
+// applyToClause returns the TO clause of row policies and settings profiles: TO NONE without grantees, TO ALL EXCEPT
+// the grantees when all is set.
+func applyToClause(grantees []string, all bool) ([]string, error) {
+	names := make([]string, 0, len(grantees))
+	for _, grantee := range grantees {
+		if grantee == "" {
+			return nil, errors.New("grantee names cannot be empty")
+		}
+		names = append(names, backtick(grantee))
+	}
+
+	switch {
+	case all && len(names) > 0:
+		return []string{"TO", "ALL", "EXCEPT", strings.Join(names, ", ")}, nil
+	case all:
+		return []string{"TO", "ALL"}, nil
+	case len(names) > 0:
+		return []string{"TO", strings.Join(names, ", ")}, nil
+	default:
+		return []string{"TO", "NONE"}, nil
+	}
+}
+
+// DropRowPolicyQueryBuilder is an interface to build DROP ROW POLICY SQL queries (already interpolated).
+type DropRowPolicyQueryBuilder interface {
+	QueryBuilder
+	WithCluster(clusterName *string) DropRowPolicyQueryBuilder
+}
+
+type dropRowPolicyQueryBuilder struct {
+	policyName   string
+	databaseName string
+	tableName    string
+	clusterName  *string
+}
+
+func NewDropRowPolicy(policyName, databaseName, tableName string) DropRowPolicyQueryBuilder {
+	return &dropRowPolicyQueryBuilder{
+		policyName:   policyName,
+		databaseName: databaseName,
+		tableName:    tableName,
+	}
+}
+
+func (q *dropRowPolicyQueryBuilder) WithCluster(clusterName *string) DropRowPolicyQueryBuilder {
+	q.clusterName = clusterName
+	return q
+}
+
+func (q *dropRowPolicyQueryBuilder) Build() (string, error) {
+	if q.policyName == "" {
+		return "", errors.New("policyName cannot be empty for DROP ROW POLICY queries")
+	}
+	if q.databaseName == "" {
+		return "", errors.New("databaseName cannot be empty for DROP ROW POLICY queries")
+	}
+	if q.tableName == "" {
+		return "", errors.New("tableName cannot be empty for DROP ROW POLICY queries")
+	}
+
+	tokens := []string{
+		"DROP",
+		"ROW",
+		"POLICY",
+		backtick(q.policyName),
+		"ON",
+		backtick(q.databaseName) + "." + backtick(q.tableName),
+	}
+	if q.clusterName != nil {
+		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
+	}
+
+	return strings.Join(tokens, " ") + ";", nil
+}
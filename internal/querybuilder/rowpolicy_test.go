@@ -0,0 +1,112 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func TestRowPolicyQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder RowPolicyQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "permissive policy for no one",
+			builder: NewCreateRowPolicy("tenant", "mydb", "events", "tenant_id = 1"),
+			want:    "CREATE ROW POLICY `tenant` ON `mydb`.`events` FOR SELECT USING tenant_id = 1 AS PERMISSIVE TO NONE;",
+			wantErr: false,
+		},
+		{
+			name:    "restrictive policy on cluster for users and roles",
+			builder: NewCreateRowPolicy("tenant", "mydb", "events", "tenant_id = 1").WithRestrictive(true).WithApplyTo([]string{"alice", "analyst"}).WithCluster(stringPtr("my_cluster")),
+			want:    "CREATE ROW POLICY `tenant` ON CLUSTER 'my_cluster' ON `mydb`.`events` FOR SELECT USING tenant_id = 1 AS RESTRICTIVE TO `alice`, `analyst`;",
+			wantErr: false,
+		},
+		{
+			name:    "policy for all",
+			builder: NewCreateRowPolicy("visible", "mydb", "events", "NOT hidden").WithApplyToAll(nil),
+			want:    "CREATE ROW POLICY `visible` ON `mydb`.`events` FOR SELECT USING NOT hidden AS PERMISSIVE TO ALL;",
+			wantErr: false,
+		},
+		{
+			name:    "alter policy for all except admin",
+			builder: NewAlterRowPolicy("visible", "mydb", "events", "NOT hidden").WithApplyToAll([]string{"admin"}),
+			want:    "ALTER ROW POLICY `visible` ON `mydb`.`events` FOR SELECT USING NOT hidden AS PERMISSIVE TO ALL EXCEPT `admin`;",
+			wantErr: false,
+		},
+		{
+			name:    "error: empty policy name",
+			builder: NewCreateRowPolicy("", "mydb", "events", "1"),
+			wantErr: true,
+		},
+		{
+			name:    "error: empty table name",
+			builder: NewCreateRowPolicy("tenant", "mydb", "", "1"),
+			wantErr: true,
+		},
+		{
+			name:    "error: empty condition",
+			builder: NewCreateRowPolicy("tenant", "mydb", "events", " "),
+			wantErr: true,
+		},
+		{
+			name:    "error: empty grantee",
+			builder: NewCreateRowPolicy("tenant", "mydb", "events", "1").WithApplyTo([]string{""}),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RowPolicyQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("RowPolicyQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDropRowPolicyQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder DropRowPolicyQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "drop policy",
+			builder: NewDropRowPolicy("tenant", "mydb", "events"),
+			want:    "DROP ROW POLICY `tenant` ON `mydb`.`events`;",
+			wantErr: false,
+		},
+		{
+			name:    "drop policy on cluster",
+			builder: NewDropRowPolicy("tenant", "mydb", "events").WithCluster(stringPtr("my_cluster")),
+			want:    "DROP ROW POLICY `tenant` ON `mydb`.`events` ON CLUSTER 'my_cluster';",
+			wantErr: false,
+		},
+		{
+			name:    "error: empty database name",
+			builder: NewDropRowPolicy("tenant", "", "events"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DropRowPolicyQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("DropRowPolicyQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
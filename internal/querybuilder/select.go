@@ -11,14 +11,44 @@ import (
 type SelectQueryBuilder interface {
 	QueryBuilder
 	Where(...Where) SelectQueryBuilder
+	LeftJoin(subquery SelectQueryBuilder, using ...string) SelectQueryBuilder
+	LeftJoinOn(subquery SelectQueryBuilder, on ...JoinCondition) SelectQueryBuilder
+	GroupBy(fieldNames ...string) SelectQueryBuilder
+	OrderBy(fieldNames ...string) SelectQueryBuilder
+	Limit(limit uint64, offset uint64) SelectQueryBuilder
 	WithCluster(clusterName *string) SelectQueryBuilder
+	WithClusterAllReplicas(clusterName *string) SelectQueryBuilder
+	// Parameters returns the values of the query parameters used in the query, to be sent along with it.
+	Parameters() map[string]string
+
+	// build returns the query without the trailing semicolon, to be embedded in other queries.
+	build() (string, error)
 }
 
 type selectQueryBuilder struct {
 	tableName   string
 	fields      []Field
 	where       Where
+	join        *selectJoin
+	groupBy     []string
+	orderBy     []string
+	limit       *uint64
+	offset      uint64
 	clusterName *string
+	allReplicas bool
+}
+
+type selectJoin struct {
+	subquery SelectQueryBuilder
+	using    []string
+	on       []JoinCondition
+}
+
+// JoinCondition matches a column of the queried table with a column of the joined subquery. Both names must be
+// unique across the two sides.
+type JoinCondition struct {
+	Left  string
+	Right string
 }
 
 func NewSelect(fields []Field, from string) SelectQueryBuilder {
@@ -33,12 +63,81 @@ func (q *selectQueryBuilder) Where(where ...Where) SelectQueryBuilder {
 	return q
 }
 
+// LeftJoin joins the result of subquery on the columns listed in using, which must exist with the same name on both sides.
+func (q *selectQueryBuilder) LeftJoin(subquery SelectQueryBuilder, using ...string) SelectQueryBuilder {
+	q.join = &selectJoin{
+		subquery: subquery,
+		using:    using,
+	}
+	return q
+}
+
+// LeftJoinOn joins the result of subquery on columns that are named differently on each side.
+func (q *selectQueryBuilder) LeftJoinOn(subquery SelectQueryBuilder, on ...JoinCondition) SelectQueryBuilder {
+	q.join = &selectJoin{
+		subquery: subquery,
+		on:       on,
+	}
+	return q
+}
+
+// GroupBy groups rows by the given fields, which may be aliases of expression fields. The other fields must be aggregates.
+func (q *selectQueryBuilder) GroupBy(fieldNames ...string) SelectQueryBuilder {
+	q.groupBy = fieldNames
+	return q
+}
+
+func (q *selectQueryBuilder) OrderBy(fieldNames ...string) SelectQueryBuilder {
+	q.orderBy = fieldNames
+	return q
+}
+
+// Limit returns at most limit rows, skipping the first offset ones. Combine with OrderBy to page through large results.
+func (q *selectQueryBuilder) Limit(limit uint64, offset uint64) SelectQueryBuilder {
+	q.limit = &limit
+	q.offset = offset
+	return q
+}
+
 func (q *selectQueryBuilder) WithCluster(clusterName *string) SelectQueryBuilder {
 	q.clusterName = clusterName
+	q.allReplicas = false
 	return q
 }
 
+// WithClusterAllReplicas reads from every replica of the cluster, while WithCluster reads from one replica per shard.
+func (q *selectQueryBuilder) WithClusterAllReplicas(clusterName *string) SelectQueryBuilder {
+	q.clusterName = clusterName
+	q.allReplicas = true
+	return q
+}
+
+func (q *selectQueryBuilder) Parameters() map[string]string {
+	ret := make(map[string]string)
+	if q.join != nil {
+		for k, v := range q.join.subquery.Parameters() {
+			ret[k] = v
+		}
+	}
+	if q.where != nil {
+		for k, v := range collectParameters(q.where) {
+			ret[k] = v
+		}
+	}
+
+	return ret
+}
+
 func (q *selectQueryBuilder) Build() (string, error) {
+	sql, err := q.build()
+	if err != nil {
+		return "", err
+	}
+
+	return sql + ";", nil
+}
+
+func (q *selectQueryBuilder) build() (string, error) {
 	if q.tableName == "" {
 		return "", errors.New("tableName cannot be empty for SELECT queries")
 	}
@@ -59,7 +158,9 @@ func (q *selectQueryBuilder) Build() (string, error) {
 		}
 		tableName := strings.Join(tokens, ".")
 
-		if q.clusterName != nil {
+		if q.clusterName != nil && q.allReplicas {
+			from = fmt.Sprintf("clusterAllReplicas(%s, %s)", quote(*q.clusterName), tableName)
+		} else if q.clusterName != nil {
 			from = fmt.Sprintf("cluster(%s, %s)", quote(*q.clusterName), tableName)
 		} else {
 			from = tableName
@@ -73,10 +174,68 @@ func (q *selectQueryBuilder) Build() (string, error) {
 		from,
 	}
 
+	// Handle JOIN
+	if q.join != nil {
+		if len(q.join.using) == 0 && len(q.join.on) == 0 {
+			return "", errors.New("at least one column is required in USING or ON for JOIN clauses")
+		}
+
+		subquery, err := q.join.subquery.build()
+		if err != nil {
+			return "", errors.WithMessage(err, "error building joined subquery")
+		}
+
+		tokens = append(tokens, "LEFT", "JOIN", fmt.Sprintf("(%s)", subquery))
+
+		if len(q.join.on) > 0 {
+			on := make([]string, 0)
+			for _, c := range q.join.on {
+				on = append(on, fmt.Sprintf("%s = %s", backtick(c.Left), backtick(c.Right)))
+			}
+
+			tokens = append(tokens, "ON", strings.Join(on, " AND "))
+		} else {
+			using := make([]string, 0)
+			for _, u := range q.join.using {
+				using = append(using, backtick(u))
+			}
+
+			tokens = append(tokens, "USING", fmt.Sprintf("(%s)", strings.Join(using, ", ")))
+		}
+	}
+
 	// Handle WHERE
 	if q.where != nil {
 		tokens = append(tokens, "WHERE", q.where.Clause())
 	}
 
-	return strings.Join(tokens, " ") + ";", nil
+	// Handle GROUP BY
+	if len(q.groupBy) > 0 {
+		groupBy := make([]string, 0)
+		for _, g := range q.groupBy {
+			groupBy = append(groupBy, backtick(g))
+		}
+
+		tokens = append(tokens, "GROUP", "BY", strings.Join(groupBy, ", "))
+	}
+
+	// Handle ORDER BY
+	if len(q.orderBy) > 0 {
+		orderBy := make([]string, 0)
+		for _, o := range q.orderBy {
+			orderBy = append(orderBy, backtick(o))
+		}
+
+		tokens = append(tokens, "ORDER", "BY", strings.Join(orderBy, ", "))
+	}
+
+	// Handle LIMIT
+	if q.limit != nil {
+		tokens = append(tokens, "LIMIT", fmt.Sprintf("%d", *q.limit))
+		if q.offset > 0 {
+			tokens = append(tokens, "OFFSET", fmt.Sprintf("%d", q.offset))
+		}
+	}
+
+	return strings.Join(tokens, " "), nil
 }
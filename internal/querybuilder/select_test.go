@@ -1,6 +1,7 @@
 package querybuilder
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -80,3 +81,115 @@ func Test_selectQueryBuilder_Build(t *testing.T) {
 		})
 	}
 }
+
+func Test_selectQueryBuilder_Clauses(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder SelectQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Cluster all replicas",
+			builder: NewSelect([]Field{NewField("database")}, "system.replicas").WithClusterAllReplicas(stringPtr("cluster1")),
+			want:    "SELECT `database` FROM clusterAllReplicas('cluster1', `system`.`replicas`);",
+			wantErr: false,
+		},
+		{
+			name: "Left join subquery with order by",
+			builder: NewSelect([]Field{NewField("name"), NewField("role")}, "users").
+				LeftJoin(
+					NewSelect([]Field{NewAliasedField("user", "name"), NewField("role")}, "roles").Where(WhereEquals("active", 1)),
+					"name",
+				).
+				OrderBy("name", "role"),
+			want:    "SELECT `name`, `role` FROM `users` LEFT JOIN (SELECT `user` AS `name`, `role` FROM `roles` WHERE (`active` = 1)) USING (`name`) ORDER BY `name`, `role`;",
+			wantErr: false,
+		},
+		{
+			name: "Left join on cluster",
+			builder: NewSelect([]Field{NewField("name")}, "system.users").
+				WithCluster(stringPtr("cluster1")).
+				LeftJoin(NewSelect([]Field{NewField("name")}, "system.roles").WithCluster(stringPtr("cluster1")), "name"),
+			want:    "SELECT `name` FROM cluster('cluster1', `system`.`users`) LEFT JOIN (SELECT `name` FROM cluster('cluster1', `system`.`roles`)) USING (`name`);",
+			wantErr: false,
+		},
+		{
+			name: "Left join on differently named columns",
+			builder: NewSelect([]Field{NewField("database"), NewField("name"), NewField("column_name")}, "system.tables").
+				LeftJoinOn(
+					NewSelect([]Field{NewAliasedField("database", "column_database"), NewAliasedField("table", "column_table"), NewAliasedField("name", "column_name")}, "system.columns"),
+					JoinCondition{Left: "database", Right: "column_database"},
+					JoinCondition{Left: "name", Right: "column_table"},
+				),
+			want:    "SELECT `database`, `name`, `column_name` FROM `system`.`tables` LEFT JOIN (SELECT `database` AS `column_database`, `table` AS `column_table`, `name` AS `column_name` FROM `system`.`columns`) ON `database` = `column_database` AND `name` = `column_table`;",
+			wantErr: false,
+		},
+		{
+			name: "Group by",
+			builder: NewSelect([]Field{NewField("partition"), NewExpressionField("sum(bytes_on_disk)", "bytes")}, "system.parts").
+				Where(WhereEquals("active", 1)).
+				GroupBy("partition").
+				OrderBy("partition"),
+			want:    "SELECT `partition`, sum(bytes_on_disk) AS `bytes` FROM `system`.`parts` WHERE (`active` = 1) GROUP BY `partition` ORDER BY `partition`;",
+			wantErr: false,
+		},
+		{
+			name:    "Limit",
+			builder: NewSelect([]Field{NewField("name")}, "system.columns").OrderBy("name").Limit(100, 0),
+			want:    "SELECT `name` FROM `system`.`columns` ORDER BY `name` LIMIT 100;",
+			wantErr: false,
+		},
+		{
+			name:    "Limit with offset",
+			builder: NewSelect([]Field{NewField("name")}, "system.columns").OrderBy("name").Limit(100, 200),
+			want:    "SELECT `name` FROM `system`.`columns` ORDER BY `name` LIMIT 100 OFFSET 200;",
+			wantErr: false,
+		},
+		{
+			name:    "Left join without using columns",
+			builder: NewSelect([]Field{NewField("name")}, "users").LeftJoin(NewSelect([]Field{NewField("name")}, "roles")),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "Left join with invalid subquery",
+			builder: NewSelect([]Field{NewField("name")}, "users").LeftJoin(NewSelect([]Field{NewField("name")}, ""), "name"),
+			want:    "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Build() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_selectQueryBuilder_Parameters(t *testing.T) {
+	builder := NewSelect([]Field{NewField("name")}, "system.tables").
+		LeftJoin(NewSelect([]Field{NewField("name")}, "system.columns").Where(WhereEquals("database", NewParameter("database", "String", "db"))), "name").
+		Where(WhereEquals("uuid", NewParameter("uuid", "UUID", "a")), WhereInParameter("engine", NewArrayParameter("engines", "String", []string{"MergeTree", "Log"})))
+
+	sql, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	wantSQL := "SELECT `name` FROM `system`.`tables` LEFT JOIN (SELECT `name` FROM `system`.`columns` WHERE (`database` = {database:String})) USING (`name`) WHERE (`uuid` = {uuid:UUID} AND has({engines:Array(String)}, `engine`));"
+	if sql != wantSQL {
+		t.Errorf("Build() got = %v, want %v", sql, wantSQL)
+	}
+
+	got := builder.Parameters()
+	want := map[string]string{"database": "db", "uuid": "a", "engines": "['MergeTree','Log']"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parameters() got = %v, want %v", got, want)
+	}
+}
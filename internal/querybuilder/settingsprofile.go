@@ -0,0 +1,155 @@
+package querybuilder
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// SettingsProfileSetting is a setting of a settings profile. Value, Min and Max are SQL literals rendered as is, any of
+// them can be nil.
+type SettingsProfileSetting struct {
+	Name     string
+	Value    *string
+	Min      *string
+	Max      *string
+	Readonly bool
+}
+
+// SettingsProfileQueryBuilder is an interface to build CREATE SETTINGS PROFILE and ALTER SETTINGS PROFILE SQL queries
+// (already interpolated).
+type SettingsProfileQueryBuilder interface {
+	QueryBuilder
+	WithSettings(settings []SettingsProfileSetting) SettingsProfileQueryBuilder
+	WithInheritProfiles(profileNames []string) SettingsProfileQueryBuilder
+	WithApplyTo(grantees []string) SettingsProfileQueryBuilder
+	WithApplyToAll(except []string) SettingsProfileQueryBuilder
+	WithCluster(clusterName *string) SettingsProfileQueryBuilder
+}
+
+type settingsProfileQueryBuilder struct {
+	action          string
+	profileName     string
+	settings        []SettingsProfileSetting
+	inheritProfiles []string
+	applyTo         []string
+	applyToAll      bool
+	clusterName     *string
+}
+
+// NewCreateSettingsProfile builds a CREATE SETTINGS PROFILE query. The profile applies to no one until WithApplyTo or
+// WithApplyToAll is called.
+func NewCreateSettingsProfile(profileName string) SettingsProfileQueryBuilder {
+	return &settingsProfileQueryBuilder{
+		action:      actionCreate,
+		profileName: profileName,
+	}
+}
+
+// NewAlterSettingsProfile builds an ALTER SETTINGS PROFILE query replacing the settings, inherited profiles and
+// grantees of the profile.
+func NewAlterSettingsProfile(profileName string) SettingsProfileQueryBuilder {
+	return &settingsProfileQueryBuilder{
+		action:      "ALTER",
+		profileName: profileName,
+	}
+}
+
+func (q *settingsProfileQueryBuilder) WithSettings(settings []SettingsProfileSetting) SettingsProfileQueryBuilder {
+	q.settings = settings
+	return q
+}
+
+// WithInheritProfiles makes the profile inherit the settings of the given profiles, in order. The settings of the
+// profile itself override the inherited ones.
+func (q *settingsProfileQueryBuilder) WithInheritProfiles(profileNames []string) SettingsProfileQueryBuilder {
+	q.inheritProfiles = profileNames
+	return q
+}
+
+// WithApplyTo sets the users and roles the profile applies to.
+func (q *settingsProfileQueryBuilder) WithApplyTo(grantees []string) SettingsProfileQueryBuilder {
+	q.applyTo = grantees
+	q.applyToAll = false
+	return q
+}
+
+// WithApplyToAll makes the profile apply to every user and role but the given ones.
+func (q *settingsProfileQueryBuilder) WithApplyToAll(except []string) SettingsProfileQueryBuilder {
+	q.applyTo = except
+	q.applyToAll = true
+	return q
+}
+
+func (q *settingsProfileQueryBuilder) WithCluster(clusterName *string) SettingsProfileQueryBuilder {
+	q.clusterName = clusterName
+	return q
+}
+
+func (q *settingsProfileQueryBuilder) Build() (string, error) {
+	if q.profileName == "" {
+		return "", errors.New("profileName cannot be empty for " + q.action + " SETTINGS PROFILE queries")
+	}
+
+	tokens := []string{
+		q.action,
+		"SETTINGS",
+		"PROFILE",
+		backtick(q.profileName),
+	}
+	if q.clusterName != nil {
+		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
+	}
+
+	elements := make([]string, 0, len(q.inheritProfiles)+len(q.settings))
+	for _, profileName := range q.inheritProfiles {
+		if profileName == "" {
+			return "", errors.New("inherited profile names cannot be empty for " + q.action + " SETTINGS PROFILE queries")
+		}
+		elements = append(elements, "PROFILE "+quote(profileName))
+	}
+
+	settings := make([]SettingsProfileSetting, len(q.settings))
+	copy(settings, q.settings)
+	sort.Slice(settings, func(a, b int) bool { return settings[a].Name < settings[b].Name })
+	for _, setting := range settings {
+		if setting.Name == "" {
+			return "", errors.New("setting names cannot be empty for " + q.action + " SETTINGS PROFILE queries")
+		}
+		if setting.Value == nil && setting.Min == nil && setting.Max == nil && !setting.Readonly {
+			return "", errors.New("setting " + setting.Name + " needs a value, a constraint or to be readonly")
+		}
+
+		element := []string{setting.Name}
+		if setting.Value != nil {
+			element = append(element, "=", *setting.Value)
+		}
+		if setting.Min != nil {
+			element = append(element, "MIN", *setting.Min)
+		}
+		if setting.Max != nil {
+			element = append(element, "MAX", *setting.Max)
+		}
+		if setting.Readonly {
+			element = append(element, "READONLY")
+		}
+		elements = append(elements, strings.Join(element, " "))
+	}
+
+	switch {
+	case len(elements) > 0:
+		tokens = append(tokens, "SETTINGS", strings.Join(elements, ", "))
+	case q.action != actionCreate:
+		// ALTER keeps the current settings unless told otherwise.
+		tokens = append(tokens, "SETTINGS", "NONE")
+	}
+
+	to, err := applyToClause(q.applyTo, q.applyToAll)
+	if err != nil {
+		return "", errors.WithMessage(err, "invalid "+q.action+" SETTINGS PROFILE query")
+	}
+	tokens = append(tokens, to...)
+
+	return strings.Join(tokens, " ") + ";", nil
+}
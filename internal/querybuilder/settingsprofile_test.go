@@ -0,0 +1,86 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func TestSettingsProfileQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder SettingsProfileQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "empty profile",
+			builder: NewCreateSettingsProfile("analysts"),
+			want:    "CREATE SETTINGS PROFILE `analysts` TO NONE;",
+			wantErr: false,
+		},
+		{
+			name: "profile with settings, constraints and inheritance on cluster",
+			builder: NewCreateSettingsProfile("analysts").
+				WithSettings([]SettingsProfileSetting{
+					{Name: "max_threads", Value: stringPtr("8"), Max: stringPtr("16")},
+					{Name: "max_memory_usage", Value: stringPtr("10000000000"), Min: stringPtr("1000000"), Max: stringPtr("20000000000")},
+					{Name: "readonly", Value: stringPtr("1"), Readonly: true},
+				}).
+				WithInheritProfiles([]string{"default"}).
+				WithApplyTo([]string{"alice", "analyst"}).
+				WithCluster(stringPtr("my_cluster")),
+			want:    "CREATE SETTINGS PROFILE `analysts` ON CLUSTER 'my_cluster' SETTINGS PROFILE 'default', max_memory_usage = 10000000000 MIN 1000000 MAX 20000000000, max_threads = 8 MAX 16, readonly = 1 READONLY TO `alice`, `analyst`;",
+			wantErr: false,
+		},
+		{
+			name:    "readonly setting without value",
+			builder: NewCreateSettingsProfile("locked").WithSettings([]SettingsProfileSetting{{Name: "max_threads", Readonly: true}}).WithApplyToAll([]string{"admin"}),
+			want:    "CREATE SETTINGS PROFILE `locked` SETTINGS max_threads READONLY TO ALL EXCEPT `admin`;",
+			wantErr: false,
+		},
+		{
+			name:    "alter profile removing every setting",
+			builder: NewAlterSettingsProfile("analysts").WithApplyToAll(nil),
+			want:    "ALTER SETTINGS PROFILE `analysts` SETTINGS NONE TO ALL;",
+			wantErr: false,
+		},
+		{
+			name:    "alter profile",
+			builder: NewAlterSettingsProfile("analysts").WithSettings([]SettingsProfileSetting{{Name: "max_threads", Value: stringPtr("4")}}),
+			want:    "ALTER SETTINGS PROFILE `analysts` SETTINGS max_threads = 4 TO NONE;",
+			wantErr: false,
+		},
+		{
+			name:    "error: empty profile name",
+			builder: NewCreateSettingsProfile(""),
+			wantErr: true,
+		},
+		{
+			name:    "error: setting without value nor constraint",
+			builder: NewCreateSettingsProfile("analysts").WithSettings([]SettingsProfileSetting{{Name: "max_threads"}}),
+			wantErr: true,
+		},
+		{
+			name:    "error: empty inherited profile",
+			builder: NewCreateSettingsProfile("analysts").WithInheritProfiles([]string{""}),
+			wantErr: true,
+		},
+		{
+			name:    "error: empty grantee",
+			builder: NewCreateSettingsProfile("analysts").WithApplyTo([]string{""}),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SettingsProfileQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("SettingsProfileQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
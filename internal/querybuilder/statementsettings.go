@@ -0,0 +1,68 @@
+package querybuilder
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+var settingNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// WithStatementSettings wraps any QueryBuilder and appends a statement level `SETTINGS key = value` clause to the
+// generated query, e.g. to set allow_experimental_* or distributed_ddl_task_timeout for a single DDL.
+// Values are emitted as-is, so string values must be quoted by the caller.
+func WithStatementSettings(builder QueryBuilder, settings map[string]string) QueryBuilder {
+	return &statementSettingsQueryBuilder{
+		builder:  builder,
+		settings: settings,
+	}
+}
+
+type statementSettingsQueryBuilder struct {
+	builder  QueryBuilder
+	settings map[string]string
+}
+
+func (q *statementSettingsQueryBuilder) Build() (string, error) {
+	sql, err := q.builder.Build()
+	if err != nil {
+		return "", err
+	}
+
+	if len(q.settings) == 0 {
+		return sql, nil
+	}
+
+	for key := range q.settings {
+		if !settingNameRegexp.MatchString(key) {
+			return "", errors.New(fmt.Sprintf("invalid setting name %q", key))
+		}
+	}
+
+	terminated := strings.HasSuffix(sql, ";")
+	sql = strings.TrimSuffix(sql, ";") + " SETTINGS " + settingsList(q.settings)
+	if terminated {
+		sql += ";"
+	}
+
+	return sql, nil
+}
+
+// settingsList renders a settings map as `key = value` pairs sorted by key, so generated queries are deterministic.
+func settingsList(settings map[string]string) string {
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s = %s", key, settings[key]))
+	}
+
+	return strings.Join(pairs, ", ")
+}
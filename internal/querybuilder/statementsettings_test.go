@@ -0,0 +1,65 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func TestWithStatementSettings_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder QueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "no settings leaves query untouched",
+			builder: WithStatementSettings(NewDropTable("mydb", "mytable"), nil),
+			want:    "DROP TABLE `mydb`.`mytable`;",
+			wantErr: false,
+		},
+		{
+			name: "settings are sorted and appended before the terminator",
+			builder: WithStatementSettings(NewDropTable("mydb", "mytable").WithCluster(stringPtr("my_cluster")), map[string]string{
+				"distributed_ddl_task_timeout":                           "600",
+				"database_atomic_wait_for_drop_and_detach_synchronously": "1",
+			}),
+			want:    "DROP TABLE `mydb`.`mytable` ON CLUSTER 'my_cluster' SETTINGS database_atomic_wait_for_drop_and_detach_synchronously = 1, distributed_ddl_task_timeout = 600;",
+			wantErr: false,
+		},
+		{
+			name: "unterminated alter query",
+			builder: WithStatementSettings(NewAlterTableDropColumn("mydb", "mytable", []string{"col"}), map[string]string{
+				"mutations_sync": "2",
+			}),
+			want:    "ALTER TABLE `mydb`.`mytable` DROP COLUMN `col` SETTINGS mutations_sync = 2",
+			wantErr: false,
+		},
+		{
+			name: "invalid setting name",
+			builder: WithStatementSettings(NewDropTable("mydb", "mytable"), map[string]string{
+				"x = 1; DROP DATABASE y": "1",
+			}),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "inner builder error is returned",
+			builder: WithStatementSettings(NewDropTable("", "mytable"), map[string]string{"mutations_sync": "2"}),
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WithStatementSettings().Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("WithStatementSettings().Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
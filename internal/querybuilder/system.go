@@ -0,0 +1,98 @@
+package querybuilder
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+const (
+	systemCommandSyncReplica        = "SYNC REPLICA"
+	systemCommandReloadDictionary   = "RELOAD DICTIONARY"
+	systemCommandReloadDictionaries = "RELOAD DICTIONARIES"
+	systemCommandFlushLogs          = "FLUSH LOGS"
+	systemCommandDropDNSCache       = "DROP DNS CACHE"
+)
+
+// SystemQueryBuilder is an interface to build SYSTEM SQL queries (already interpolated).
+type SystemQueryBuilder interface {
+	QueryBuilder
+	WithCluster(clusterName *string) SystemQueryBuilder
+}
+
+type systemQueryBuilder struct {
+	command        string
+	databaseName   string
+	targetName     string
+	requiresTarget bool
+	clusterName    *string
+}
+
+// NewSystemSyncReplica builds a SYSTEM SYNC REPLICA query for the given replicated table.
+func NewSystemSyncReplica(databaseName string, tableName string) SystemQueryBuilder {
+	return &systemQueryBuilder{
+		command:        systemCommandSyncReplica,
+		databaseName:   databaseName,
+		targetName:     tableName,
+		requiresTarget: true,
+	}
+}
+
+// NewSystemReloadDictionary builds a SYSTEM RELOAD DICTIONARY query. databaseName can be empty for dictionaries
+// defined in the server configuration.
+func NewSystemReloadDictionary(databaseName string, dictionaryName string) SystemQueryBuilder {
+	return &systemQueryBuilder{
+		command:        systemCommandReloadDictionary,
+		databaseName:   databaseName,
+		targetName:     dictionaryName,
+		requiresTarget: true,
+	}
+}
+
+func NewSystemReloadDictionaries() SystemQueryBuilder {
+	return &systemQueryBuilder{
+		command: systemCommandReloadDictionaries,
+	}
+}
+
+func NewSystemFlushLogs() SystemQueryBuilder {
+	return &systemQueryBuilder{
+		command: systemCommandFlushLogs,
+	}
+}
+
+func NewSystemDropDNSCache() SystemQueryBuilder {
+	return &systemQueryBuilder{
+		command: systemCommandDropDNSCache,
+	}
+}
+
+func (q *systemQueryBuilder) WithCluster(clusterName *string) SystemQueryBuilder {
+	q.clusterName = clusterName
+	return q
+}
+
+func (q *systemQueryBuilder) Build() (string, error) {
+	if q.requiresTarget && q.targetName == "" {
+		return "", errors.New("target name cannot be empty for SYSTEM " + q.command + " queries")
+	}
+
+	tokens := []string{
+		"SYSTEM",
+		q.command,
+	}
+
+	if q.clusterName != nil {
+		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
+	}
+
+	if q.requiresTarget {
+		if q.databaseName != "" {
+			tokens = append(tokens, backtick(q.databaseName)+"."+backtick(q.targetName))
+		} else {
+			tokens = append(tokens, backtick(q.targetName))
+		}
+	}
+
+	return strings.Join(tokens, " ") + ";", nil
+}
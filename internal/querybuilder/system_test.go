@@ -0,0 +1,88 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func TestSystemQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder SystemQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "sync replica",
+			builder: NewSystemSyncReplica("mydb", "mytable"),
+			want:    "SYSTEM SYNC REPLICA `mydb`.`mytable`;",
+			wantErr: false,
+		},
+		{
+			name:    "sync replica on cluster",
+			builder: NewSystemSyncReplica("mydb", "mytable").WithCluster(stringPtr("my_cluster")),
+			want:    "SYSTEM SYNC REPLICA ON CLUSTER 'my_cluster' `mydb`.`mytable`;",
+			wantErr: false,
+		},
+		{
+			name:    "sync replica with special characters",
+			builder: NewSystemSyncReplica("my`db", "my.table"),
+			want:    "SYSTEM SYNC REPLICA `my\\`db`.`my.table`;",
+			wantErr: false,
+		},
+		{
+			name:    "error: sync replica without table",
+			builder: NewSystemSyncReplica("mydb", ""),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "reload dictionary",
+			builder: NewSystemReloadDictionary("mydb", "mydict"),
+			want:    "SYSTEM RELOAD DICTIONARY `mydb`.`mydict`;",
+			wantErr: false,
+		},
+		{
+			name:    "reload dictionary without database",
+			builder: NewSystemReloadDictionary("", "mydict").WithCluster(stringPtr("my_cluster")),
+			want:    "SYSTEM RELOAD DICTIONARY ON CLUSTER 'my_cluster' `mydict`;",
+			wantErr: false,
+		},
+		{
+			name:    "error: reload dictionary without name",
+			builder: NewSystemReloadDictionary("mydb", ""),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "reload dictionaries",
+			builder: NewSystemReloadDictionaries(),
+			want:    "SYSTEM RELOAD DICTIONARIES;",
+			wantErr: false,
+		},
+		{
+			name:    "flush logs on cluster",
+			builder: NewSystemFlushLogs().WithCluster(stringPtr("my_cluster")),
+			want:    "SYSTEM FLUSH LOGS ON CLUSTER 'my_cluster';",
+			wantErr: false,
+		},
+		{
+			name:    "drop dns cache",
+			builder: NewSystemDropDNSCache(),
+			want:    "SYSTEM DROP DNS CACHE;",
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SystemQueryBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("SystemQueryBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
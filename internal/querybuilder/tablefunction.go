@@ -0,0 +1,41 @@
+package querybuilder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// TableFunction is a table function call reading its connection details and credentials from a named collection,
+// e.g. s3(`my_bucket`, `format` = 'Parquet').
+type TableFunction struct {
+	Name            string
+	NamedCollection string
+	// Arguments override keys of the named collection. Values are string literals.
+	Arguments map[string]string
+}
+
+// SQLDef renders the table function call, with arguments sorted by key so generated queries are deterministic.
+func (f TableFunction) SQLDef() (string, error) {
+	if !settingNameRegexp.MatchString(f.Name) {
+		return "", errors.New(fmt.Sprintf("invalid table function name %q", f.Name))
+	}
+	if f.NamedCollection == "" {
+		return "", errors.New("named collection cannot be empty for table functions")
+	}
+
+	keys := make([]string, 0, len(f.Arguments))
+	for key := range f.Arguments {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	args := []string{backtick(f.NamedCollection)}
+	for _, key := range keys {
+		args = append(args, fmt.Sprintf("%s = %s", backtick(key), quote(f.Arguments[key])))
+	}
+
+	return fmt.Sprintf("%s(%s)", f.Name, strings.Join(args, ", ")), nil
+}
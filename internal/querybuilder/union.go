@@ -0,0 +1,36 @@
+package querybuilder
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+type unionAllQueryBuilder struct {
+	selects []SelectQueryBuilder
+}
+
+// NewUnionAll combines the results of several SELECT queries. All of them must return the same fields, with the
+// same types, in the same order.
+func NewUnionAll(selects ...SelectQueryBuilder) QueryBuilder {
+	return &unionAllQueryBuilder{
+		selects: selects,
+	}
+}
+
+func (q *unionAllQueryBuilder) Build() (string, error) {
+	if len(q.selects) < 2 {
+		return "", errors.New("at least two queries are required for UNION ALL queries")
+	}
+
+	queries := make([]string, 0)
+	for _, s := range q.selects {
+		sql, err := s.build()
+		if err != nil {
+			return "", err
+		}
+		queries = append(queries, sql)
+	}
+
+	return strings.Join(queries, " UNION ALL ") + ";", nil
+}
@@ -0,0 +1,60 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func Test_unionAllQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder QueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Union of two selects",
+			builder: NewUnionAll(
+				NewSelect([]Field{NewField("name"), NewExpressionField("'user'", "kind")}, "system.users").Where(WhereEquals("name", "john")),
+				NewSelect([]Field{NewField("name"), NewExpressionField("'role'", "kind")}, "system.roles").Where(WhereEquals("name", "john")),
+			),
+			want:    "SELECT `name`, 'user' AS `kind` FROM `system`.`users` WHERE (`name` = 'john') UNION ALL SELECT `name`, 'role' AS `kind` FROM `system`.`roles` WHERE (`name` = 'john');",
+			wantErr: false,
+		},
+		{
+			name: "Union on cluster",
+			builder: NewUnionAll(
+				NewSelect([]Field{NewField("name")}, "system.users").WithCluster(stringPtr("cluster1")),
+				NewSelect([]Field{NewField("name")}, "system.roles").WithCluster(stringPtr("cluster1")),
+			),
+			want:    "SELECT `name` FROM cluster('cluster1', `system`.`users`) UNION ALL SELECT `name` FROM cluster('cluster1', `system`.`roles`);",
+			wantErr: false,
+		},
+		{
+			name:    "Single select",
+			builder: NewUnionAll(NewSelect([]Field{NewField("name")}, "system.users")),
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name: "Invalid select",
+			builder: NewUnionAll(
+				NewSelect([]Field{NewField("name")}, "system.users"),
+				NewSelect([]Field{}, "system.roles"),
+			),
+			want:    "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Build() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package querybuilder
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+const (
+	VectorSimilarityMethodHNSW = "hnsw"
+
+	// Defaults applied by ClickHouse when the optional parameters are omitted.
+	VectorSimilarityDefaultQuantization                 = "bf16"
+	VectorSimilarityDefaultMaxConnectionsPerLayer       = 32
+	VectorSimilarityDefaultCandidateListForConstruction = 128
+)
+
+var (
+	VectorSimilarityDistanceFunctions = []string{"L2Distance", "cosineDistance"}
+	VectorSimilarityQuantizations     = []string{"f64", "f32", "f16", "bf16", "i8", "b1"}
+)
+
+// VectorSimilarityIndexType describes the type of a vector_similarity skip index, an approximate nearest neighbour
+// index over an Array(Float32) column.
+type VectorSimilarityIndexType struct {
+	// DistanceFunction is either L2Distance or cosineDistance, and must match the one used in queries.
+	DistanceFunction string
+	// Dimensions is the length of the indexed arrays.
+	Dimensions uint64
+	// Quantization is the precision vectors are stored with in the index. Nil uses the ClickHouse default.
+	Quantization *string
+	// HNSWMaxConnectionsPerLayer is the number of neighbours per graph node. Nil uses the ClickHouse default.
+	HNSWMaxConnectionsPerLayer *uint64
+	// HNSWCandidateListSizeForConstruction is the size of the candidate list when building the graph.
+	// Nil uses the ClickHouse default.
+	HNSWCandidateListSizeForConstruction *uint64
+}
+
+// Build returns the index type to use in TableIndex.Type, e.g. vector_similarity('hnsw', 'cosineDistance', 768).
+// The optional parameters are positional: when any of them is set, the others are rendered with their defaults.
+func (t VectorSimilarityIndexType) Build() (string, error) {
+	if !slices.Contains(VectorSimilarityDistanceFunctions, t.DistanceFunction) {
+		return "", errors.New(fmt.Sprintf("invalid distance function %q, must be one of %s", t.DistanceFunction, strings.Join(VectorSimilarityDistanceFunctions, ", ")))
+	}
+	if t.Dimensions == 0 {
+		return "", errors.New("dimensions must be greater than zero")
+	}
+
+	params := []string{
+		quote(VectorSimilarityMethodHNSW),
+		quote(t.DistanceFunction),
+		fmt.Sprintf("%d", t.Dimensions),
+	}
+
+	if t.Quantization != nil || t.HNSWMaxConnectionsPerLayer != nil || t.HNSWCandidateListSizeForConstruction != nil {
+		quantization := VectorSimilarityDefaultQuantization
+		if t.Quantization != nil {
+			quantization = *t.Quantization
+		}
+		if !slices.Contains(VectorSimilarityQuantizations, quantization) {
+			return "", errors.New(fmt.Sprintf("invalid quantization %q, must be one of %s", quantization, strings.Join(VectorSimilarityQuantizations, ", ")))
+		}
+
+		var maxConnections uint64 = VectorSimilarityDefaultMaxConnectionsPerLayer
+		if t.HNSWMaxConnectionsPerLayer != nil {
+			maxConnections = *t.HNSWMaxConnectionsPerLayer
+		}
+
+		var candidateList uint64 = VectorSimilarityDefaultCandidateListForConstruction
+		if t.HNSWCandidateListSizeForConstruction != nil {
+			candidateList = *t.HNSWCandidateListSizeForConstruction
+		}
+
+		params = append(params, quote(quantization), fmt.Sprintf("%d", maxConnections), fmt.Sprintf("%d", candidateList))
+	}
+
+	return fmt.Sprintf("vector_similarity(%s)", strings.Join(params, ", ")), nil
+}
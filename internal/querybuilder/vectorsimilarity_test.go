@@ -0,0 +1,78 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func uint64Ptr(v uint64) *uint64 {
+	return &v
+}
+
+func TestVectorSimilarityIndexType_Build(t *testing.T) {
+	tests := []struct {
+		name      string
+		indexType VectorSimilarityIndexType
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "defaults",
+			indexType: VectorSimilarityIndexType{DistanceFunction: "cosineDistance", Dimensions: 768},
+			want:      "vector_similarity('hnsw', 'cosineDistance', 768)",
+			wantErr:   false,
+		},
+		{
+			name: "all parameters",
+			indexType: VectorSimilarityIndexType{
+				DistanceFunction:                     "L2Distance",
+				Dimensions:                           1536,
+				Quantization:                         stringPtr("f32"),
+				HNSWMaxConnectionsPerLayer:           uint64Ptr(64),
+				HNSWCandidateListSizeForConstruction: uint64Ptr(256),
+			},
+			want:    "vector_similarity('hnsw', 'L2Distance', 1536, 'f32', 64, 256)",
+			wantErr: false,
+		},
+		{
+			name: "missing positional parameters use defaults",
+			indexType: VectorSimilarityIndexType{
+				DistanceFunction:           "L2Distance",
+				Dimensions:                 3,
+				HNSWMaxConnectionsPerLayer: uint64Ptr(16),
+			},
+			want:    "vector_similarity('hnsw', 'L2Distance', 3, 'bf16', 16, 128)",
+			wantErr: false,
+		},
+		{
+			name:      "error: unknown distance function",
+			indexType: VectorSimilarityIndexType{DistanceFunction: "dotProduct", Dimensions: 3},
+			want:      "",
+			wantErr:   true,
+		},
+		{
+			name:      "error: no dimensions",
+			indexType: VectorSimilarityIndexType{DistanceFunction: "L2Distance"},
+			want:      "",
+			wantErr:   true,
+		},
+		{
+			name:      "error: unknown quantization",
+			indexType: VectorSimilarityIndexType{DistanceFunction: "L2Distance", Dimensions: 3, Quantization: stringPtr("i4")},
+			want:      "",
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.indexType.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VectorSimilarityIndexType.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("VectorSimilarityIndexType.Build() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
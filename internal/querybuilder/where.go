@@ -3,6 +3,9 @@ package querybuilder
 import (
 	"fmt"
 	"reflect"
+	"strings"
+
+	"github.com/pingcap/errors"
 )
 
 type Where interface {
@@ -38,14 +41,119 @@ func IsNull(fieldName string) Where {
 	}
 }
 
+// IsNotNull matches rows where fieldName has a value.
+func IsNotNull(fieldName string) Where {
+	return &simpleWhere{
+		field:    fieldName,
+		value:    nil,
+		operator: "IS NOT",
+	}
+}
+
 func (s *simpleWhere) Clause() string {
 	if s.value == nil {
+		if s.operator == "IS NOT" {
+			return fmt.Sprintf("%s IS NOT NULL", backtick(s.field))
+		}
 		return fmt.Sprintf("%s IS NULL", backtick(s.field))
 	}
 
+	if p, ok := s.value.(Parameter); ok {
+		return fmt.Sprintf("%s %s %s", backtick(s.field), s.operator, p.placeholder())
+	}
+
 	if reflect.TypeOf(s.value).String() == "string" {
 		return fmt.Sprintf("%s %s %s", backtick(s.field), s.operator, quote(s.value.(string)))
 	}
 
 	return fmt.Sprintf("%s %s %v", backtick(s.field), s.operator, s.value)
 }
+
+func (s *simpleWhere) parameters() []Parameter {
+	if p, ok := s.value.(Parameter); ok {
+		return []Parameter{p}
+	}
+
+	return nil
+}
+
+// WhereIn matches rows where fieldName is one of values.
+func WhereIn(fieldName string, values []string) Where {
+	return &inWhere{
+		field:  fieldName,
+		values: values,
+	}
+}
+
+type inWhere struct {
+	field  string
+	values []string
+}
+
+func (s *inWhere) Clause() string {
+	if len(s.values) == 0 {
+		// Nothing can match an empty set.
+		return "0"
+	}
+
+	quoted := make([]string, 0, len(s.values))
+	for _, v := range s.values {
+		quoted = append(quoted, quote(v))
+	}
+
+	return fmt.Sprintf("%s IN (%s)", backtick(s.field), strings.Join(quoted, ", "))
+}
+
+// WhereInParameter matches rows where fieldName is one of the values of an array parameter, see NewArrayParameter.
+func WhereInParameter(fieldName string, p Parameter) Where {
+	return &inParameterWhere{
+		field: fieldName,
+		param: p,
+	}
+}
+
+type inParameterWhere struct {
+	field string
+	param Parameter
+}
+
+func (s *inParameterWhere) Clause() string {
+	return fmt.Sprintf("has(%s, %s)", s.param.placeholder(), backtick(s.field))
+}
+
+func (s *inParameterWhere) parameters() []Parameter {
+	return []Parameter{s.param}
+}
+
+// WhereInSelect matches rows where fieldName is one of the values returned by the single-column subquery.
+func WhereInSelect(fieldName string, subquery SelectQueryBuilder) (Where, error) {
+	sql, err := subquery.build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building subquery")
+	}
+
+	params := make([]Parameter, 0)
+	for name, value := range subquery.Parameters() {
+		params = append(params, Parameter{Name: name, Value: value})
+	}
+
+	return &inSelectWhere{
+		field:    fieldName,
+		subquery: sql,
+		params:   params,
+	}, nil
+}
+
+type inSelectWhere struct {
+	field    string
+	subquery string
+	params   []Parameter
+}
+
+func (s *inSelectWhere) parameters() []Parameter {
+	return s.params
+}
+
+func (s *inSelectWhere) Clause() string {
+	return fmt.Sprintf("%s IN (%s)", backtick(s.field), s.subquery)
+}
@@ -45,6 +45,31 @@ func Test_SimpleWhere_Clause(t *testing.T) {
 			where: IsNull("age"),
 			want:  "`age` IS NULL",
 		},
+		{
+			name:  "Is not null",
+			where: IsNotNull("age"),
+			want:  "`age` IS NOT NULL",
+		},
+		{
+			name:  "In",
+			where: WhereIn("uuid", []string{"a", "b'c"}),
+			want:  "`uuid` IN ('a', 'b\\'c')",
+		},
+		{
+			name:  "Parameter",
+			where: WhereEquals("uuid", NewParameter("uuid", "UUID", "a")),
+			want:  "`uuid` = {uuid:UUID}",
+		},
+		{
+			name:  "In array parameter",
+			where: WhereInParameter("uuid", NewArrayParameter("uuids", "UUID", []string{"a", "b'c"})),
+			want:  "has({uuids:Array(UUID)}, `uuid`)",
+		},
+		{
+			name:  "In empty set",
+			where: WhereIn("uuid", nil),
+			want:  "0",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -54,3 +79,19 @@ func Test_SimpleWhere_Clause(t *testing.T) {
 		})
 	}
 }
+
+func Test_WhereInSelect(t *testing.T) {
+	where, err := WhereInSelect("database", NewSelect([]Field{NewField("database")}, "system.tables").Where(WhereEquals("uuid", "a")))
+	if err != nil {
+		t.Fatalf("WhereInSelect() error = %v", err)
+	}
+
+	want := "`database` IN (SELECT `database` FROM `system`.`tables` WHERE (`uuid` = 'a'))"
+	if got := where.Clause(); got != want {
+		t.Errorf("Clause() = %v, want %v", got, want)
+	}
+
+	if _, err := WhereInSelect("database", NewSelect(nil, "system.tables")); err == nil {
+		t.Errorf("WhereInSelect() with invalid subquery error = nil, want error")
+	}
+}
@@ -0,0 +1,38 @@
+package schemadiff
+
+import (
+	"strings"
+)
+
+// SameCodec reports whether the compression codec chain ClickHouse reports is the configured one. ClickHouse fills in
+// the default parameters of the codecs configured without them, e.g. Delta is reported as Delta(4) for a DateTime
+// column and ZSTD as ZSTD(1), and both can be wrapped in CODEC().
+func SameCodec(planned string, actual string) bool {
+	plannedCodecs := SplitTopLevel(unwrapCodec(planned))
+	actualCodecs := SplitTopLevel(unwrapCodec(actual))
+	if len(plannedCodecs) != len(actualCodecs) {
+		return false
+	}
+
+	for i := range plannedCodecs {
+		plannedName, plannedArgs, plannedHasArgs := SplitTypeArguments(plannedCodecs[i])
+		actualName, actualArgs, _ := SplitTypeArguments(actualCodecs[i])
+		if !strings.EqualFold(plannedName, actualName) {
+			return false
+		}
+		if plannedHasArgs && strings.Join(strings.Fields(plannedArgs), "") != strings.Join(strings.Fields(actualArgs), "") {
+			return false
+		}
+	}
+
+	return true
+}
+
+func unwrapCodec(codec string) string {
+	codec = strings.TrimSpace(codec)
+	if name, args, ok := SplitTypeArguments(codec); ok && strings.EqualFold(name, "CODEC") {
+		return args
+	}
+
+	return codec
+}
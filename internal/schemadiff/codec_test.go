@@ -0,0 +1,50 @@
+package schemadiff
+
+import "testing"
+
+func TestSameCodec(t *testing.T) {
+	tests := []struct {
+		name    string
+		planned string
+		actual  string
+		want    bool
+	}{
+		{
+			name:    "Same codec",
+			planned: "ZSTD(3)",
+			actual:  "ZSTD(3)",
+			want:    true,
+		},
+		{
+			name:    "Default parameters filled in",
+			planned: "Delta, ZSTD",
+			actual:  "Delta(4), ZSTD(1)",
+			want:    true,
+		},
+		{
+			name:    "Wrapped in CODEC()",
+			planned: "CODEC(DoubleDelta, LZ4)",
+			actual:  "DoubleDelta,LZ4",
+			want:    true,
+		},
+		{
+			name:    "Different level",
+			planned: "ZSTD(3)",
+			actual:  "ZSTD(1)",
+			want:    false,
+		},
+		{
+			name:    "Different chain",
+			planned: "Delta, ZSTD(1)",
+			actual:  "ZSTD(1)",
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SameCodec(tt.planned, tt.actual); got != tt.want {
+				t.Errorf("SameCodec(%q, %q) = %v, want %v", tt.planned, tt.actual, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package schemadiff
+
+import (
+	"strings"
+)
+
+// sharedEngines are the engines ClickHouse Cloud replaces with their Shared counterpart.
+var sharedEngines = map[string]string{
+	"MergeTree":                    "SharedMergeTree",
+	"ReplacingMergeTree":           "SharedReplacingMergeTree",
+	"SummingMergeTree":             "SharedSummingMergeTree",
+	"AggregatingMergeTree":         "SharedAggregatingMergeTree",
+	"CollapsingMergeTree":          "SharedCollapsingMergeTree",
+	"VersionedCollapsingMergeTree": "SharedVersionedCollapsingMergeTree",
+}
+
+// EngineName returns the name of an engine without its parameters, e.g. ReplicatedMergeTree for
+// ReplicatedMergeTree('/clickhouse/tables/{shard}/events', '{replica}').
+func EngineName(engine string) string {
+	if idx := strings.Index(engine, "("); idx != -1 {
+		return strings.TrimSpace(engine[:idx])
+	}
+	return strings.TrimSpace(engine)
+}
+
+// SameEngine reports whether the engine ClickHouse reports is the configured one. Parameters are not compared, as
+// ClickHouse expands macros and default arguments, and ClickHouse Cloud replacing a MergeTree engine with its Shared
+// counterpart is not a change.
+func SameEngine(planned string, actual string) bool {
+	planned, actual = EngineName(planned), EngineName(actual)
+	if planned == actual {
+		return true
+	}
+
+	for original, shared := range sharedEngines {
+		if (planned == original && actual == shared) || (planned == shared && actual == original) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,28 @@
+package schemadiff
+
+import (
+	"testing"
+)
+
+func TestSameEngine(t *testing.T) {
+	tests := []struct {
+		planned string
+		actual  string
+		want    bool
+	}{
+		{planned: "MergeTree", actual: "MergeTree", want: true},
+		{planned: "MergeTree()", actual: "MergeTree", want: true},
+		{planned: "ReplicatedMergeTree", actual: "ReplicatedMergeTree('/clickhouse/tables/{uuid}/{shard}', '{replica}')", want: true},
+		{planned: "ReplacingMergeTree(version)", actual: "SharedReplacingMergeTree('/clickhouse/tables/{uuid}/{shard}', '{replica}', version)", want: true},
+		{planned: "SharedMergeTree", actual: "MergeTree", want: true},
+		{planned: "MergeTree", actual: "ReplacingMergeTree", want: false},
+		{planned: "Log", actual: "SharedMergeTree", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.planned+" and "+tt.actual, func(t *testing.T) {
+			if got := SameEngine(tt.planned, tt.actual); got != tt.want {
+				t.Errorf("SameEngine() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
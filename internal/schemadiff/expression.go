@@ -0,0 +1,86 @@
+package schemadiff
+
+import (
+	"strings"
+)
+
+// NormalizeExpression returns a canonical form of an SQL expression, e.g. a partition key or a sorting key column, so
+// that the expression written in the configuration can be compared to the one ClickHouse reports: whitespace is
+// collapsed, spaces inside parentheses and brackets are dropped, arguments are separated by ", " and the backticks
+// around identifiers that don't need them are removed. String literals are kept as is.
+func NormalizeExpression(expr string) string {
+	var b strings.Builder
+	var token strings.Builder
+	var quote rune
+	escaped := false
+	space := false
+
+	for _, c := range strings.TrimSpace(expr) {
+		if quote != 0 {
+			token.WriteRune(c)
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == quote:
+				b.WriteString(unquoteIdentifier(token.String()))
+				token.Reset()
+				quote = 0
+			}
+			continue
+		}
+
+		switch c {
+		case ' ', '\t', '\n', '\r':
+			space = true
+			continue
+		case ')', ']', ',':
+			space = false
+		}
+		if space && b.Len() > 0 && !strings.ContainsRune("([", rune(b.String()[b.Len()-1])) {
+			b.WriteRune(' ')
+		}
+		space = c == ','
+
+		switch c {
+		case '\'', '`', '"':
+			quote = c
+			token.WriteRune(c)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	// An unterminated literal is kept as written.
+	b.WriteString(token.String())
+
+	return b.String()
+}
+
+// SameExpression reports whether two SQL expressions only differ by formatting, see NormalizeExpression.
+func SameExpression(a string, b string) bool {
+	return NormalizeExpression(a) == NormalizeExpression(b)
+}
+
+// SameExpressions reports whether two lists of SQL expressions only differ by formatting.
+func SameExpressions(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !SameExpression(a[i], b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// unquoteIdentifier removes the backticks or double quotes around an identifier that doesn't need them.
+func unquoteIdentifier(s string) string {
+	if len(s) > 1 && (s[0] == '`' || s[0] == '"') && s[len(s)-1] == s[0] && simpleIdentifierRegexp.MatchString(s[1:len(s)-1]) {
+		return s[1 : len(s)-1]
+	}
+
+	return s
+}
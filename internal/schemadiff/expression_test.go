@@ -0,0 +1,77 @@
+package schemadiff
+
+import (
+	"testing"
+)
+
+func TestNormalizeExpression(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want string
+	}{
+		{
+			name: "Simple column",
+			expr: " created_at ",
+			want: "created_at",
+		},
+		{
+			name: "Spacing",
+			expr: "toStartOfInterval( created_at ,INTERVAL  1   HOUR )",
+			want: "toStartOfInterval(created_at, INTERVAL 1 HOUR)",
+		},
+		{
+			name: "Unneeded backticks",
+			expr: "toYYYYMM(`created_at`)",
+			want: "toYYYYMM(created_at)",
+		},
+		{
+			name: "Needed backticks",
+			expr: "`event type`",
+			want: "`event type`",
+		},
+		{
+			name: "String literals are kept",
+			expr: "replaceAll(name,  '  a,b ', 'it\\'s')",
+			want: "replaceAll(name, '  a,b ', 'it\\'s')",
+		},
+		{
+			name: "Arrays and tuples",
+			expr: "( a , [ 1,2 ] )",
+			want: "(a, [1, 2])",
+		},
+		{
+			name: "Newlines",
+			expr: "cityHash64(\n\tuser_id\n)",
+			want: "cityHash64(user_id)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeExpression(tt.expr); got != tt.want {
+				t.Errorf("NormalizeExpression() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameExpressions(t *testing.T) {
+	tests := []struct {
+		name string
+		a    []string
+		b    []string
+		want bool
+	}{
+		{name: "Both empty", a: nil, b: []string{}, want: true},
+		{name: "Formatting only", a: []string{"`id`", "toDate( ts )"}, b: []string{"id", "toDate(ts)"}, want: true},
+		{name: "Different order", a: []string{"id", "ts"}, b: []string{"ts", "id"}, want: false},
+		{name: "Different length", a: []string{"id"}, b: []string{"id", "ts"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SameExpressions(tt.a, tt.b); got != tt.want {
+				t.Errorf("SameExpressions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+// Package schemadiff compares the schema objects written in the configuration with the ones ClickHouse reports, which
+// reformats types, expressions, engines and settings. Resources use it while refreshing to keep the configured value
+// when ClickHouse only rewrote it, so that only actual changes show up as drift.
+package schemadiff
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// KeepPlanned returns planned when it is known and same reports it matches actual, and actual otherwise. A nil actual
+// is null.
+func KeepPlanned(planned types.String, actual *string, same func(planned string, actual string) bool) types.String {
+	if actual != nil && !planned.IsNull() && !planned.IsUnknown() && same(planned.ValueString(), *actual) {
+		return planned
+	}
+
+	return types.StringPointerValue(actual)
+}
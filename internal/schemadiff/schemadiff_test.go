@@ -0,0 +1,30 @@
+package schemadiff
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestKeepPlanned(t *testing.T) {
+	actual := "toYYYYMM(created_at)"
+	tests := []struct {
+		name    string
+		planned types.String
+		actual  *string
+		want    types.String
+	}{
+		{name: "Reformatted", planned: types.StringValue("toYYYYMM( `created_at` )"), actual: &actual, want: types.StringValue("toYYYYMM( `created_at` )")},
+		{name: "Changed", planned: types.StringValue("toDate(created_at)"), actual: &actual, want: types.StringValue(actual)},
+		{name: "Not planned", planned: types.StringNull(), actual: &actual, want: types.StringValue(actual)},
+		{name: "Unknown", planned: types.StringUnknown(), actual: &actual, want: types.StringValue(actual)},
+		{name: "Removed", planned: types.StringValue("toDate(created_at)"), actual: nil, want: types.StringNull()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KeepPlanned(tt.planned, tt.actual, SameExpression); !got.Equal(tt.want) {
+				t.Errorf("KeepPlanned() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package schemadiff
+
+import (
+	"strings"
+)
+
+// PlannedSettings returns the settings ClickHouse reports that are in the configuration: settings that were left out
+// of the configuration are server defaults, or changed outside of Terraform on purpose, and don't show up as drift.
+// The configured value is kept when ClickHouse only reformatted it.
+func PlannedSettings(planned map[string]string, actual map[string]string) map[string]string {
+	ret := make(map[string]string)
+	for name, plannedValue := range planned {
+		actualValue, ok := actual[name]
+		if !ok {
+			continue
+		}
+		if SameSettingValue(plannedValue, actualValue) {
+			ret[name] = plannedValue
+		} else {
+			ret[name] = actualValue
+		}
+	}
+
+	return ret
+}
+
+// SameSettingValue reports whether two setting values are the same, ClickHouse reporting string values without their
+// quotes, e.g. 'default' is reported as default.
+func SameSettingValue(a string, b string) bool {
+	return unquoteSetting(a) == unquoteSetting(b)
+}
+
+func unquoteSetting(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > 1 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "\\'", "'")
+	}
+
+	return s
+}
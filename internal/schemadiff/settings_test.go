@@ -0,0 +1,47 @@
+package schemadiff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlannedSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		planned map[string]string
+		actual  map[string]string
+		want    map[string]string
+	}{
+		{
+			name:    "Unplanned settings are left out",
+			planned: map[string]string{"index_granularity": "8192"},
+			actual:  map[string]string{"index_granularity": "8192", "storage_policy": "default"},
+			want:    map[string]string{"index_granularity": "8192"},
+		},
+		{
+			name:    "Quotes are kept",
+			planned: map[string]string{"storage_policy": "'s3'"},
+			actual:  map[string]string{"storage_policy": "s3"},
+			want:    map[string]string{"storage_policy": "'s3'"},
+		},
+		{
+			name:    "Changed values are reported",
+			planned: map[string]string{"index_granularity": "8192"},
+			actual:  map[string]string{"index_granularity": "1024"},
+			want:    map[string]string{"index_granularity": "1024"},
+		},
+		{
+			name:    "Missing settings are left out",
+			planned: map[string]string{"ttl_only_drop_parts": "1"},
+			actual:  map[string]string{},
+			want:    map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PlannedSettings(tt.planned, tt.actual); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PlannedSettings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package schemadiff
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	jsonDefaultMaxDynamicPaths = "1024"
+	jsonDefaultMaxDynamicTypes = "32"
+	dynamicDefaultMaxTypes     = "32"
+)
+
+var (
+	simpleIdentifierRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	typeParameterRegexp    = regexp.MustCompile(`^(\w+)\s*=\s*(\S+)$`)
+)
+
+// integerTypes are the integer column types, with their signedness and size in bits.
+var integerTypes = map[string]struct {
+	signed bool
+	bits   int
+}{
+	"UInt8": {false, 8}, "UInt16": {false, 16}, "UInt32": {false, 32}, "UInt64": {false, 64}, "UInt128": {false, 128}, "UInt256": {false, 256},
+	"Int8": {true, 8}, "Int16": {true, 16}, "Int32": {true, 32}, "Int64": {true, 64}, "Int128": {true, 128}, "Int256": {true, 256},
+}
+
+// CompatibleTypeChange reports whether ALTER TABLE MODIFY COLUMN can change a column from one type to the other
+// without losing data: widening an integer or a float, or wrapping the type in Nullable or LowCardinality.
+func CompatibleTypeChange(from string, to string) bool {
+	from, to = NormalizeType(from), NormalizeType(to)
+	if from == to {
+		return true
+	}
+
+	toName, toArgs, toWrapped := SplitTypeArguments(to)
+	if toWrapped && (toName == "Nullable" || toName == "LowCardinality") {
+		if fromName, fromArgs, fromWrapped := SplitTypeArguments(from); fromWrapped && fromName == toName {
+			return CompatibleTypeChange(fromArgs, toArgs)
+		}
+		return CompatibleTypeChange(from, toArgs)
+	}
+
+	fromInteger, fromOk := integerTypes[from]
+	toInteger, toOk := integerTypes[to]
+	if fromOk && toOk {
+		// An unsigned integer only fits in a wider signed one, a signed integer never fits in an unsigned one.
+		return toInteger.bits > fromInteger.bits && (toInteger.signed || !fromInteger.signed)
+	}
+
+	return from == "Float32" && to == "Float64"
+}
+
+// SameType reports whether two column types only differ by formatting, see NormalizeType.
+func SameType(a string, b string) bool {
+	return NormalizeType(a) == NormalizeType(b)
+}
+
+// NormalizeType returns a canonical form of a column type, so that a type written in the configuration can be
+// compared to the one reported by system.columns. JSON, Dynamic and aggregate function types, which ClickHouse
+// reformats (dropping default parameters, sorting paths, canonical function names), and LowCardinality and Nullable
+// wrappers are rewritten, other types only get their spacing normalized.
+func NormalizeType(t string) string {
+	t = strings.TrimSpace(t)
+
+	name, args, hasArgs := SplitTypeArguments(t)
+	switch {
+	case hasArgs && (strings.EqualFold(name, "LowCardinality") || strings.EqualFold(name, "Nullable")):
+		inner := NormalizeType(args)
+		if strings.EqualFold(name, "Nullable") {
+			// Nullable(LowCardinality(T)) is rejected by ClickHouse, it means LowCardinality(Nullable(T)).
+			if innerName, innerArgs, ok := SplitTypeArguments(inner); ok && innerName == "LowCardinality" {
+				return fmt.Sprintf("LowCardinality(Nullable(%s))", innerArgs)
+			}
+			return fmt.Sprintf("Nullable(%s)", inner)
+		}
+		return fmt.Sprintf("LowCardinality(%s)", inner)
+	case strings.EqualFold(name, "JSON"):
+		return normalizeJSONType(args)
+	case name == "Dynamic":
+		if !hasArgs {
+			return "Dynamic"
+		}
+		if m := typeParameterRegexp.FindStringSubmatch(strings.TrimSpace(args)); m != nil && m[1] == "max_types" {
+			if m[2] == dynamicDefaultMaxTypes {
+				return "Dynamic"
+			}
+			return fmt.Sprintf("Dynamic(max_types=%s)", m[2])
+		}
+	case name == "AggregateFunction" || name == "SimpleAggregateFunction":
+		return normalizeAggregateFunctionType(name, args)
+	case hasArgs:
+		// Only the spacing between arguments changes, e.g. Decimal(10,2) is reported as Decimal(10, 2).
+		parts := SplitTopLevel(args)
+		for i, part := range parts {
+			parts[i] = NormalizeType(part)
+		}
+		return fmt.Sprintf("%s(%s)", name, strings.Join(parts, ", "))
+	}
+
+	return t
+}
+
+// normalizeAggregateFunctionType normalizes the arguments of AggregateFunction and SimpleAggregateFunction types:
+// the function name, whose case ClickHouse reports canonically for case-insensitive functions like SUM, the numeric
+// parameters of parametric functions like quantiles(0.50, 0.9), and the argument types.
+func normalizeAggregateFunctionType(name string, args string) string {
+	parts := SplitTopLevel(args)
+
+	// AggregateFunction may start with the version of the state serialization, e.g. AggregateFunction(1, sumMap, ...).
+	start := 0
+	if name == "AggregateFunction" && len(parts) > 0 && isUnsignedInteger(parts[0]) {
+		start = 1
+	}
+
+	for i, part := range parts {
+		switch {
+		case i < start:
+			continue
+		case i == start:
+			parts[i] = normalizeAggregateFunction(part)
+		default:
+			parts[i] = NormalizeType(part)
+		}
+	}
+
+	return fmt.Sprintf("%s(%s)", name, strings.Join(parts, ", "))
+}
+
+// normalizeAggregateFunction normalizes an aggregate function with its parameters, e.g. `quantiles(0.50,0.9)`.
+func normalizeAggregateFunction(function string) string {
+	fname, params, hasParams := SplitTypeArguments(function)
+	fname = strings.ToLower(fname)
+	if !hasParams {
+		return fname
+	}
+
+	parts := SplitTopLevel(params)
+	for i, part := range parts {
+		if f, err := strconv.ParseFloat(part, 64); err == nil {
+			parts[i] = strconv.FormatFloat(f, 'g', -1, 64)
+		}
+	}
+
+	return fmt.Sprintf("%s(%s)", fname, strings.Join(parts, ", "))
+}
+
+func isUnsignedInteger(s string) bool {
+	_, err := strconv.ParseUint(s, 10, 64)
+	return err == nil
+}
+
+// normalizeJSONType renders the arguments of a JSON type in a canonical order: parameters not set to their default,
+// then typed paths, SKIP paths and SKIP REGEXP patterns, each sorted.
+func normalizeJSONType(args string) string {
+	var params, typed, skip, skipRegexp []string
+	for _, arg := range SplitTopLevel(args) {
+		switch {
+		case arg == "":
+			continue
+		case typeParameterRegexp.MatchString(arg):
+			m := typeParameterRegexp.FindStringSubmatch(arg)
+			if (m[1] == "max_dynamic_paths" && m[2] == jsonDefaultMaxDynamicPaths) || (m[1] == "max_dynamic_types" && m[2] == jsonDefaultMaxDynamicTypes) {
+				continue
+			}
+			params = append(params, fmt.Sprintf("%s=%s", m[1], m[2]))
+		case hasKeywordPrefix(arg, "SKIP REGEXP"):
+			skipRegexp = append(skipRegexp, "SKIP REGEXP "+strings.TrimSpace(arg[len("SKIP REGEXP"):]))
+		case hasKeywordPrefix(arg, "SKIP"):
+			skip = append(skip, "SKIP "+unquotePath(strings.TrimSpace(arg[len("SKIP"):])))
+		default:
+			path, typ := SplitPathType(arg)
+			typed = append(typed, fmt.Sprintf("%s %s", unquotePath(path), NormalizeType(typ)))
+		}
+	}
+
+	sort.Strings(params)
+	sort.Strings(typed)
+	sort.Strings(skip)
+	sort.Strings(skipRegexp)
+
+	all := append(append(append(params, typed...), skip...), skipRegexp...)
+	if len(all) == 0 {
+		return "JSON"
+	}
+
+	return fmt.Sprintf("JSON(%s)", strings.Join(all, ", "))
+}
+
+// SplitTypeArguments splits `Name(args)` into its name and arguments.
+func SplitTypeArguments(t string) (string, string, bool) {
+	open := strings.Index(t, "(")
+	if open == -1 || !strings.HasSuffix(t, ")") {
+		return t, "", false
+	}
+
+	return strings.TrimSpace(t[:open]), t[open+1 : len(t)-1], true
+}
+
+// SplitTopLevel splits s on the commas that are not nested in parentheses, quotes or backticks.
+func SplitTopLevel(s string) []string {
+	var ret []string
+	depth := 0
+	var quote rune
+	start := 0
+	for i, c := range s {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			ret = append(ret, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+
+	return append(ret, strings.TrimSpace(s[start:]))
+}
+
+// SplitPathType splits a typed path like `a.b UInt32` or "`a b`.c String" into its path and type.
+func SplitPathType(arg string) (string, string) {
+	quoted := false
+	for i, c := range arg {
+		switch {
+		case c == '`':
+			quoted = !quoted
+		case !quoted && (c == ' ' || c == '\t'):
+			return arg[:i], strings.TrimSpace(arg[i:])
+		}
+	}
+
+	return arg, ""
+}
+
+// unquotePath removes the backticks around the segments of a JSON path that don't need them, e.g. `a`.`b c` gives
+// a.`b c`.
+func unquotePath(path string) string {
+	var segments []string
+	quoted := false
+	start := 0
+	for i, c := range path {
+		switch {
+		case c == '`':
+			quoted = !quoted
+		case c == '.' && !quoted:
+			segments = append(segments, path[start:i])
+			start = i + 1
+		}
+	}
+	segments = append(segments, path[start:])
+
+	for i, segment := range segments {
+		if len(segment) > 1 && strings.HasPrefix(segment, "`") && strings.HasSuffix(segment, "`") && simpleIdentifierRegexp.MatchString(segment[1:len(segment)-1]) {
+			segments[i] = segment[1 : len(segment)-1]
+		}
+	}
+
+	return strings.Join(segments, ".")
+}
+
+func hasKeywordPrefix(s string, keyword string) bool {
+	return len(s) > len(keyword) && strings.EqualFold(s[:len(keyword)], keyword) && (s[len(keyword)] == ' ' || s[len(keyword)] == '\t')
+}
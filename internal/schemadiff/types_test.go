@@ -0,0 +1,130 @@
+package schemadiff
+
+import (
+	"testing"
+)
+
+func TestNormalizeType(t *testing.T) {
+	tests := []struct {
+		name string
+		t    string
+		want string
+	}{
+		{
+			name: "Other types are kept",
+			t:    " Map(String, UInt64) ",
+			want: "Map(String, UInt64)",
+		},
+		{
+			name: "Plain JSON",
+			t:    "json",
+			want: "JSON",
+		},
+		{
+			name: "Default parameters are dropped",
+			t:    "JSON(max_dynamic_paths = 1024, max_dynamic_types = 32)",
+			want: "JSON",
+		},
+		{
+			name: "Parameters, paths and skips are sorted",
+			t:    "JSON(SKIP REGEXP 'tmp.*', user.id UInt64, SKIP `debug`, max_dynamic_paths = 256, `event`.name String)",
+			want: "JSON(max_dynamic_paths=256, event.name String, user.id UInt64, SKIP debug, SKIP REGEXP 'tmp.*')",
+		},
+		{
+			name: "Quoted path needing backticks",
+			t:    "JSON(`a b` Array(Nullable(String)))",
+			want: "JSON(`a b` Array(Nullable(String)))",
+		},
+		{
+			name: "Typed path with nested type parameters",
+			t:    "JSON(tags Map(String, String), a.b Decimal(10, 2))",
+			want: "JSON(a.b Decimal(10, 2), tags Map(String, String))",
+		},
+		{
+			name: "Dynamic with default max_types",
+			t:    "Dynamic(max_types = 32)",
+			want: "Dynamic",
+		},
+		{
+			name: "Dynamic with max_types",
+			t:    "Dynamic(max_types = 8)",
+			want: "Dynamic(max_types=8)",
+		},
+		{
+			name: "Argument spacing",
+			t:    "Map(String,Decimal(10,2))",
+			want: "Map(String, Decimal(10, 2))",
+		},
+		{
+			name: "Aggregate function",
+			t:    "AggregateFunction(uniq,UInt64)",
+			want: "AggregateFunction(uniq, UInt64)",
+		},
+		{
+			name: "Aggregate function name case and parameters",
+			t:    "AggregateFunction(quantiles(0.50,0.90), Float64)",
+			want: "AggregateFunction(quantiles(0.5, 0.9), Float64)",
+		},
+		{
+			name: "Case-insensitive aggregate function",
+			t:    "SimpleAggregateFunction(SUM, Nullable(UInt64))",
+			want: "SimpleAggregateFunction(sum, Nullable(UInt64))",
+		},
+		{
+			name: "Aggregate function with version",
+			t:    "AggregateFunction(1, sumMap, Array(UInt8),Array(UInt64))",
+			want: "AggregateFunction(1, summap, Array(UInt8), Array(UInt64))",
+		},
+		{
+			name: "LowCardinality wrappers",
+			t:    "lowcardinality(nullable(String))",
+			want: "LowCardinality(Nullable(String))",
+		},
+		{
+			name: "Nullable LowCardinality",
+			t:    "Nullable(LowCardinality(String))",
+			want: "LowCardinality(Nullable(String))",
+		},
+		{
+			name: "Wrapped types",
+			t:    "Array(JSON(max_dynamic_types = 16))",
+			want: "Array(JSON(max_dynamic_types=16))",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeType(tt.t); got != tt.want {
+				t.Errorf("NormalizeType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompatibleTypeChange(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+		want bool
+	}{
+		{from: "UInt32", to: "UInt64", want: true},
+		{from: "UInt32", to: "Int64", want: true},
+		{from: "UInt32", to: "Int32", want: false},
+		{from: "Int32", to: "UInt64", want: false},
+		{from: "UInt64", to: "UInt32", want: false},
+		{from: "Float32", to: "Float64", want: true},
+		{from: "Float64", to: "Float32", want: false},
+		{from: "String", to: "Nullable(String)", want: true},
+		{from: "Nullable(String)", to: "String", want: false},
+		{from: "Nullable(UInt8)", to: "Nullable(UInt16)", want: true},
+		{from: "UInt8", to: "Nullable(UInt16)", want: true},
+		{from: "String", to: "LowCardinality(Nullable(String))", want: true},
+		{from: "String", to: "UInt64", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			if got := CompatibleTypeChange(tt.from, tt.to); got != tt.want {
+				t.Errorf("CompatibleTypeChange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package accessentities
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+//go:embed accessentities.md
+var accessEntitiesDataSourceDescription string
+
+var (
+	_ datasource.DataSource              = &DataSource{}
+	_ datasource.DataSourceWithConfigure = &DataSource{}
+)
+
+func NewDataSource() datasource.DataSource {
+	return &DataSource{}
+}
+
+type DataSource struct {
+	client dbops.Client
+}
+
+func (d *DataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_access_entities"
+}
+
+func (d *DataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to read access entities from. If omitted, only the replica hit by the query is read.\nThis field must be left null when using a ClickHouse Cloud cluster.",
+			},
+			"entities": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Access entities, sorted by type and name",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							Computed:    true,
+							Description: "One of `user`, `role`, `row_policy`, `quota` or `settings_profile`",
+						},
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "UUID of the entity",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the entity. Row policies are named `policy ON database.table`",
+						},
+						"storage": schema.StringAttribute{
+							Computed:    true,
+							Description: "Access storage holding the entity, e.g. `local_directory`, `replicated` or `users_xml`",
+						},
+						"read_only": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the entity is defined in the server configuration or an external directory, and can't be changed with SQL statements",
+						},
+					},
+				},
+			},
+		},
+		MarkdownDescription: accessEntitiesDataSourceDescription,
+	}
+}
+
+func (d *DataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(dbops.Client)
+}
+
+func (d *DataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "data.clickhousedbops_access_entities", "read")
+
+	var config AccessEntities
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	entities, err := d.client.GetAccessEntities(ctx, config.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Access Entities",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	config.Entities = make([]AccessEntity, 0, len(entities))
+	for _, e := range entities {
+		config.Entities = append(config.Entities, AccessEntity{
+			Type:     types.StringValue(e.Type),
+			ID:       types.StringValue(e.ID),
+			Name:     types.StringValue(e.Name),
+			Storage:  types.StringValue(e.Storage),
+			ReadOnly: types.BoolValue(dbops.IsReadOnlyStorage(e.Storage)),
+		})
+	}
+
+	diags = resp.State.Set(ctx, config)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,18 @@
+package accessentities
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type AccessEntities struct {
+	ClusterName types.String   `tfsdk:"cluster_name"`
+	Entities    []AccessEntity `tfsdk:"entities"`
+}
+
+type AccessEntity struct {
+	Type     types.String `tfsdk:"type"`
+	ID       types.String `tfsdk:"id"`
+	Name     types.String `tfsdk:"name"`
+	Storage  types.String `tfsdk:"storage"`
+	ReadOnly types.Bool   `tfsdk:"read_only"`
+}
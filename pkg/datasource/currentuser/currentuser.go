@@ -0,0 +1,170 @@
+package currentuser
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+//go:embed currentuser.md
+var currentUserDataSourceDescription string
+
+var (
+	_ datasource.DataSource              = &DataSource{}
+	_ datasource.DataSourceWithConfigure = &DataSource{}
+)
+
+func NewDataSource() datasource.DataSource {
+	return &DataSource{}
+}
+
+type DataSource struct {
+	client dbops.Client
+}
+
+func (d *DataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_current_user"
+}
+
+func (d *DataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the user the provider is connected as",
+			},
+			"roles": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Roles enabled for the user, including the ones granted to its roles",
+			},
+			"privileges": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Privileges granted to the user, either directly or through one of its roles",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"privilege_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "The granted privilege",
+						},
+						"database_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "The database the privilege is granted on, null for all databases",
+						},
+						"table_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "The table the privilege is granted on, null for all tables",
+						},
+						"column_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "The column the privilege is granted on, null for all columns",
+						},
+						"grant_option": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the user can grant the privilege to others",
+						},
+						"role_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "The role the privilege comes from, null when granted to the user directly",
+						},
+					},
+				},
+			},
+			"access_management": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the user has `ALL` or `ACCESS MANAGEMENT` on all databases, needed to manage users, roles and grants",
+			},
+			"ddl": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the user has `ALL`, or `CREATE`, `ALTER` and `DROP`, on all databases, needed to manage databases and tables",
+			},
+		},
+		MarkdownDescription: currentUserDataSourceDescription,
+	}
+}
+
+func (d *DataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(dbops.Client)
+}
+
+func (d *DataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "data.clickhousedbops_current_user", "read")
+
+	user, err := d.client.GetCurrentUser(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Current User",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	state := CurrentUser{
+		Name:       types.StringValue(user.Name),
+		Privileges: make([]Privilege, 0),
+	}
+
+	roles, diags := types.ListValueFrom(ctx, types.StringType, user.EnabledRoles)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.Roles = roles
+
+	allPrivileges := make([]dbops.GrantPrivilege, 0)
+
+	grants, err := d.client.GetGranteeGrants(ctx, &user.Name, nil, nil)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Grants",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+	for _, p := range grants.Privileges {
+		state.Privileges = append(state.Privileges, newPrivilege(p, nil))
+	}
+	allPrivileges = append(allPrivileges, grants.Privileges...)
+
+	for _, roleName := range user.EnabledRoles {
+		grants, err := d.client.GetGranteeGrants(ctx, nil, &roleName, nil)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Reading ClickHouse Grants",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+		for _, p := range grants.Privileges {
+			state.Privileges = append(state.Privileges, newPrivilege(p, &roleName))
+		}
+		allPrivileges = append(allPrivileges, grants.Privileges...)
+	}
+
+	state.AccessManagement = types.BoolValue(hasGlobalPrivilege(allPrivileges, "ACCESS MANAGEMENT"))
+	state.DDL = types.BoolValue(hasGlobalPrivilege(allPrivileges, "CREATE", "ALTER", "DROP"))
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func newPrivilege(p dbops.GrantPrivilege, roleName *string) Privilege {
+	return Privilege{
+		Privilege:   types.StringValue(p.AccessType),
+		Database:    types.StringPointerValue(p.DatabaseName),
+		Table:       types.StringPointerValue(p.TableName),
+		Column:      types.StringPointerValue(p.ColumnName),
+		GrantOption: types.BoolValue(p.GrantOption),
+		RoleName:    types.StringPointerValue(roleName),
+	}
+}
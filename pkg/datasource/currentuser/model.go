@@ -0,0 +1,22 @@
+package currentuser
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type CurrentUser struct {
+	Name             types.String `tfsdk:"name"`
+	Roles            types.List   `tfsdk:"roles"`
+	Privileges       []Privilege  `tfsdk:"privileges"`
+	AccessManagement types.Bool   `tfsdk:"access_management"`
+	DDL              types.Bool   `tfsdk:"ddl"`
+}
+
+type Privilege struct {
+	Privilege   types.String `tfsdk:"privilege_name"`
+	Database    types.String `tfsdk:"database_name"`
+	Table       types.String `tfsdk:"table_name"`
+	Column      types.String `tfsdk:"column_name"`
+	GrantOption types.Bool   `tfsdk:"grant_option"`
+	RoleName    types.String `tfsdk:"role_name"`
+}
@@ -0,0 +1,27 @@
+package currentuser
+
+import (
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+// hasGlobalPrivilege reports whether privileges include ALL, or every one of names, granted on all databases.
+func hasGlobalPrivilege(privileges []dbops.GrantPrivilege, names ...string) bool {
+	granted := make(map[string]bool)
+	for _, p := range privileges {
+		if p.DatabaseName == nil {
+			granted[p.AccessType] = true
+		}
+	}
+
+	if granted["ALL"] {
+		return true
+	}
+
+	for _, name := range names {
+		if !granted[name] {
+			return false
+		}
+	}
+
+	return true
+}
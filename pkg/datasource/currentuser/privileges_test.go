@@ -0,0 +1,58 @@
+package currentuser
+
+import (
+	"testing"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func Test_hasGlobalPrivilege(t *testing.T) {
+	tests := []struct {
+		name       string
+		privileges []dbops.GrantPrivilege
+		names      []string
+		want       bool
+	}{
+		{
+			name:       "No privileges",
+			privileges: nil,
+			names:      []string{"ACCESS MANAGEMENT"},
+			want:       false,
+		},
+		{
+			name:       "ALL",
+			privileges: []dbops.GrantPrivilege{{AccessType: "ALL"}},
+			names:      []string{"CREATE", "ALTER", "DROP"},
+			want:       true,
+		},
+		{
+			name:       "Exact privilege",
+			privileges: []dbops.GrantPrivilege{{AccessType: "SELECT"}, {AccessType: "ACCESS MANAGEMENT"}},
+			names:      []string{"ACCESS MANAGEMENT"},
+			want:       true,
+		},
+		{
+			name:       "Missing one of the privileges",
+			privileges: []dbops.GrantPrivilege{{AccessType: "CREATE"}, {AccessType: "DROP"}},
+			names:      []string{"CREATE", "ALTER", "DROP"},
+			want:       false,
+		},
+		{
+			name:       "Privilege on a single database",
+			privileges: []dbops.GrantPrivilege{{AccessType: "ALL", DatabaseName: strPtr("db")}},
+			names:      []string{"ACCESS MANAGEMENT"},
+			want:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasGlobalPrivilege(tt.privileges, tt.names...); got != tt.want {
+				t.Errorf("hasGlobalPrivilege() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package databases
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+//go:embed databases.md
+var databasesDataSourceDescription string
+
+var (
+	_ datasource.DataSource              = &DataSource{}
+	_ datasource.DataSourceWithConfigure = &DataSource{}
+)
+
+func NewDataSource() datasource.DataSource {
+	return &DataSource{}
+}
+
+type DataSource struct {
+	client dbops.Client
+}
+
+func (d *DataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_databases"
+}
+
+func (d *DataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to read databases from. If omitted, only the replica hit by the query is read.\nThis field must be left null when using a ClickHouse Cloud cluster.",
+			},
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Regular expression the names of the listed databases must match. If omitted, all databases are listed",
+			},
+			"databases": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Databases of the server, sorted by name",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the database",
+						},
+						"uuid": schema.StringAttribute{
+							Computed:    true,
+							Description: "The system-assigned UUID for the database",
+						},
+						"engine": schema.StringAttribute{
+							Computed:    true,
+							Description: "Database engine, with its parameters",
+						},
+						"comment": schema.StringAttribute{
+							Computed:    true,
+							Description: "Comment of the database",
+						},
+					},
+				},
+			},
+		},
+		MarkdownDescription: databasesDataSourceDescription,
+	}
+}
+
+func (d *DataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(dbops.Client)
+}
+
+func (d *DataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "data.clickhousedbops_databases", "read")
+
+	var config Databases
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var nameRegexp *regexp.Regexp
+	if !config.NameRegex.IsNull() {
+		var err error
+		nameRegexp, err = regexp.Compile(config.NameRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("name_regex"),
+				"Invalid Regular Expression",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+	}
+
+	databases, err := d.client.ListDatabases(ctx, config.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Databases",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	config.Databases = make([]Database, 0, len(databases))
+	for _, db := range databases {
+		if nameRegexp != nil && !nameRegexp.MatchString(db.Name) {
+			continue
+		}
+
+		config.Databases = append(config.Databases, Database{
+			Name:    types.StringValue(db.Name),
+			UUID:    types.StringValue(db.UUID),
+			Engine:  types.StringValue(db.Engine),
+			Comment: types.StringValue(db.Comment),
+		})
+	}
+
+	diags = resp.State.Set(ctx, config)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,18 @@
+package databases
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type Databases struct {
+	ClusterName types.String `tfsdk:"cluster_name"`
+	NameRegex   types.String `tfsdk:"name_regex"`
+	Databases   []Database   `tfsdk:"databases"`
+}
+
+type Database struct {
+	Name    types.String `tfsdk:"name"`
+	UUID    types.String `tfsdk:"uuid"`
+	Engine  types.String `tfsdk:"engine"`
+	Comment types.String `tfsdk:"comment"`
+}
@@ -0,0 +1,150 @@
+package detachedparts
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+//go:embed detachedparts.md
+var detachedPartsDataSourceDescription string
+
+var (
+	_ datasource.DataSource              = &DataSource{}
+	_ datasource.DataSourceWithConfigure = &DataSource{}
+)
+
+func NewDataSource() datasource.DataSource {
+	return &DataSource{}
+}
+
+type DataSource struct {
+	client dbops.Client
+}
+
+func (d *DataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_detached_parts"
+}
+
+func (d *DataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to read detached parts from. Every replica is read, since detached parts are local to each of them. If omitted, only the replica hit by the query is read.\nThis field must be left null when using a ClickHouse Cloud cluster.",
+			},
+			"database_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the database containing the tables",
+			},
+			"table_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return detached parts of the table with this name. If omitted, the detached parts of every table of the database are returned",
+			},
+			"parts": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Detached parts, ordered by table and name",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"host": schema.StringAttribute{
+							Computed:    true,
+							Description: "Host name of the replica storing the part",
+						},
+						"database_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the database containing the table",
+						},
+						"table_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the table the part was detached from",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the part, as used by `ALTER TABLE ... ATTACH PART` and `DROP DETACHED PART`",
+						},
+						"partition_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "ID of the partition of the part, empty when the part name can't be parsed",
+						},
+						"disk_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the disk storing the part",
+						},
+						"reason": schema.StringAttribute{
+							Computed:    true,
+							Description: "Why the part was detached, e.g. `broken` or `unexpected`. Empty for parts detached with `ALTER TABLE ... DETACH`",
+						},
+						"bytes_on_disk": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Size of the part on disk, in bytes. Always 0 before ClickHouse 23.4",
+						},
+						"modification_time": schema.StringAttribute{
+							Computed:    true,
+							Description: "When the part directory was last modified. Always empty before ClickHouse 23.4",
+						},
+					},
+				},
+			},
+			"total_bytes_on_disk": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Total size of the detached parts on disk, in bytes",
+			},
+		},
+		MarkdownDescription: detachedPartsDataSourceDescription,
+	}
+}
+
+func (d *DataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(dbops.Client)
+}
+
+func (d *DataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "data.clickhousedbops_detached_parts", "read")
+
+	var config DetachedParts
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	parts, err := d.client.GetDetachedParts(ctx, config.DatabaseName.ValueString(), config.TableName.ValueStringPointer(), config.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Detached Parts",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	var total int64
+	config.Parts = make([]DetachedPart, 0, len(parts))
+	for _, p := range parts {
+		config.Parts = append(config.Parts, DetachedPart{
+			Host:             types.StringValue(p.Host),
+			DatabaseName:     types.StringValue(p.DatabaseName),
+			TableName:        types.StringValue(p.TableName),
+			Name:             types.StringValue(p.Name),
+			PartitionID:      types.StringValue(p.PartitionID),
+			DiskName:         types.StringValue(p.DiskName),
+			Reason:           types.StringValue(p.Reason),
+			BytesOnDisk:      types.Int64Value(int64(p.BytesOnDisk)),
+			ModificationTime: types.StringValue(p.ModificationTime),
+		})
+		total += int64(p.BytesOnDisk)
+	}
+	config.TotalBytesOnDisk = types.Int64Value(total)
+
+	diags = resp.State.Set(ctx, config)
+	resp.Diagnostics.Append(diags...)
+}
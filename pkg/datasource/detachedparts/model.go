@@ -0,0 +1,25 @@
+package detachedparts
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type DetachedParts struct {
+	ClusterName      types.String   `tfsdk:"cluster_name"`
+	DatabaseName     types.String   `tfsdk:"database_name"`
+	TableName        types.String   `tfsdk:"table_name"`
+	Parts            []DetachedPart `tfsdk:"parts"`
+	TotalBytesOnDisk types.Int64    `tfsdk:"total_bytes_on_disk"`
+}
+
+type DetachedPart struct {
+	Host             types.String `tfsdk:"host"`
+	DatabaseName     types.String `tfsdk:"database_name"`
+	TableName        types.String `tfsdk:"table_name"`
+	Name             types.String `tfsdk:"name"`
+	PartitionID      types.String `tfsdk:"partition_id"`
+	DiskName         types.String `tfsdk:"disk_name"`
+	Reason           types.String `tfsdk:"reason"`
+	BytesOnDisk      types.Int64  `tfsdk:"bytes_on_disk"`
+	ModificationTime types.String `tfsdk:"modification_time"`
+}
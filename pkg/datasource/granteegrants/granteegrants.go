@@ -0,0 +1,162 @@
+package granteegrants
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+//go:embed granteegrants.md
+var granteeGrantsDataSourceDescription string
+
+var (
+	_ datasource.DataSource              = &DataSource{}
+	_ datasource.DataSourceWithConfigure = &DataSource{}
+)
+
+func NewDataSource() datasource.DataSource {
+	return &DataSource{}
+}
+
+type DataSource struct {
+	client dbops.Client
+}
+
+func (d *DataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_grantee_grants"
+}
+
+func (d *DataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to read grants from. It is also set in the generated resources.\nThis field must be left null when using a ClickHouse Cloud cluster.",
+			},
+			"grantee_user_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the `user` to read grants of.",
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.Expressions{
+						path.MatchRoot("grantee_user_name"),
+						path.MatchRoot("grantee_role_name"),
+					}...),
+				},
+			},
+			"grantee_role_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the `role` to read grants of.",
+			},
+			"privileges": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Privileges granted to the grantee",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"privilege_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "The granted privilege",
+						},
+						"database_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "The database the privilege is granted on, null for all databases",
+						},
+						"table_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "The table the privilege is granted on, null for all tables",
+						},
+						"column_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "The column the privilege is granted on, null for all columns",
+						},
+						"grant_option": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the grantee can grant the privilege to others",
+						},
+					},
+				},
+			},
+			"roles": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Roles granted to the grantee",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"role_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "The granted role",
+						},
+						"admin_option": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the grantee can grant the role to others",
+						},
+					},
+				},
+			},
+			"hcl": schema.StringAttribute{
+				Computed:    true,
+				Description: "The `clickhousedbops_grant_privilege` and `clickhousedbops_grant_role` resource blocks declaring the grants",
+			},
+		},
+		MarkdownDescription: granteeGrantsDataSourceDescription,
+	}
+}
+
+func (d *DataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(dbops.Client)
+}
+
+func (d *DataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "data.clickhousedbops_grantee_grants", "read")
+
+	var config GranteeGrants
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	grants, err := d.client.GetGranteeGrants(ctx, config.GranteeUserName.ValueStringPointer(), config.GranteeRoleName.ValueStringPointer(), config.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Grants",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	config.Privileges = make([]GrantPrivilege, 0, len(grants.Privileges))
+	for _, p := range grants.Privileges {
+		config.Privileges = append(config.Privileges, GrantPrivilege{
+			Privilege:   types.StringValue(p.AccessType),
+			Database:    types.StringPointerValue(p.DatabaseName),
+			Table:       types.StringPointerValue(p.TableName),
+			Column:      types.StringPointerValue(p.ColumnName),
+			GrantOption: types.BoolValue(p.GrantOption),
+		})
+	}
+
+	config.Roles = make([]GrantRole, 0, len(grants.Roles))
+	for _, r := range grants.Roles {
+		config.Roles = append(config.Roles, GrantRole{
+			RoleName:    types.StringValue(r.RoleName),
+			AdminOption: types.BoolValue(r.AdminOption),
+		})
+	}
+
+	config.HCL = types.StringValue(renderGrants(config.ClusterName.ValueStringPointer(), config.GranteeUserName.ValueStringPointer(), config.GranteeRoleName.ValueStringPointer(), grants))
+
+	diags = resp.State.Set(ctx, config)
+	resp.Diagnostics.Append(diags...)
+}
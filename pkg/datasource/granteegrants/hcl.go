@@ -0,0 +1,90 @@
+package granteegrants
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/hclgen"
+)
+
+// renderGrants returns one clickhousedbops_grant_privilege or clickhousedbops_grant_role resource block per grant.
+func renderGrants(clusterName *string, granteeUserName *string, granteeRoleName *string, grants *dbops.GranteeGrants) string {
+	var granteeName string
+	var grantee hclgen.Attribute
+	if granteeUserName != nil {
+		granteeName = *granteeUserName
+		grantee = hclgen.Attribute{Name: "grantee_user_name", Value: hclgen.String(granteeName)}
+	} else {
+		granteeName = *granteeRoleName
+		grantee = hclgen.Attribute{Name: "grantee_role_name", Value: hclgen.String(granteeName)}
+	}
+
+	names := make(map[string]bool)
+	blocks := make([]string, 0, len(grants.Privileges)+len(grants.Roles))
+
+	for _, p := range grants.Privileges {
+		nameParts := []string{granteeName, p.AccessType}
+		attributes := make([]hclgen.Attribute, 0)
+		if clusterName != nil {
+			attributes = append(attributes, hclgen.Attribute{Name: "cluster_name", Value: hclgen.String(*clusterName)})
+		}
+		attributes = append(attributes, hclgen.Attribute{Name: "privilege_name", Value: hclgen.String(p.AccessType)})
+		if p.DatabaseName != nil {
+			nameParts = append(nameParts, *p.DatabaseName)
+			attributes = append(attributes, hclgen.Attribute{Name: "database_name", Value: hclgen.String(*p.DatabaseName)})
+		}
+		if p.TableName != nil {
+			nameParts = append(nameParts, *p.TableName)
+			attributes = append(attributes, hclgen.Attribute{Name: "table_name", Value: hclgen.String(*p.TableName)})
+		}
+		if p.ColumnName != nil {
+			nameParts = append(nameParts, *p.ColumnName)
+			attributes = append(attributes, hclgen.Attribute{Name: "column_name", Value: hclgen.String(*p.ColumnName)})
+		}
+		attributes = append(attributes, grantee)
+		if p.GrantOption {
+			attributes = append(attributes, hclgen.Attribute{Name: "grant_option", Value: "true"})
+		}
+
+		blocks = append(blocks, renderBlock("clickhousedbops_grant_privilege", uniqueName(names, nameParts), attributes))
+	}
+
+	for _, r := range grants.Roles {
+		attributes := make([]hclgen.Attribute, 0)
+		if clusterName != nil {
+			attributes = append(attributes, hclgen.Attribute{Name: "cluster_name", Value: hclgen.String(*clusterName)})
+		}
+		attributes = append(attributes, hclgen.Attribute{Name: "role_name", Value: hclgen.String(r.RoleName)}, grantee)
+		if r.AdminOption {
+			attributes = append(attributes, hclgen.Attribute{Name: "admin_option", Value: "true"})
+		}
+
+		blocks = append(blocks, renderBlock("clickhousedbops_grant_role", uniqueName(names, []string{granteeName, r.RoleName}), attributes))
+	}
+
+	return strings.Join(blocks, "\n")
+}
+
+func renderBlock(resourceType string, name string, attributes []hclgen.Attribute) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "resource %s %s {\n", hclgen.String(resourceType), hclgen.String(name))
+	hclgen.WriteAttributes(&sb, "  ", attributes)
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// uniqueName builds a resource name out of parts, adding a numeric suffix if it was already used.
+func uniqueName(used map[string]bool, parts []string) string {
+	base := hclgen.Identifier(strings.ToLower(strings.Join(parts, "_")), "grant_")
+
+	name := base
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	used[name] = true
+
+	return name
+}
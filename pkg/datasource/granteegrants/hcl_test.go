@@ -0,0 +1,102 @@
+package granteegrants
+
+import (
+	"testing"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func Test_renderGrants(t *testing.T) {
+	tests := []struct {
+		name            string
+		clusterName     *string
+		granteeUserName *string
+		granteeRoleName *string
+		grants          *dbops.GranteeGrants
+		want            string
+	}{
+		{
+			name:            "No grants",
+			granteeUserName: strPtr("alice"),
+			grants:          &dbops.GranteeGrants{},
+			want:            "",
+		},
+		{
+			name:            "Privileges and roles of a user",
+			granteeUserName: strPtr("alice"),
+			grants: &dbops.GranteeGrants{
+				Privileges: []dbops.GrantPrivilege{
+					{AccessType: "SELECT", DatabaseName: strPtr("db"), TableName: strPtr("events"), GrantOption: true},
+					{AccessType: "SELECT", DatabaseName: strPtr("db"), TableName: strPtr("events"), ColumnName: strPtr("id")},
+					{AccessType: "SHOW DATABASES"},
+				},
+				Roles: []dbops.GrantRole{
+					{RoleName: "reader", AdminOption: true},
+				},
+			},
+			want: `resource "clickhousedbops_grant_privilege" "alice_select_db_events" {
+  privilege_name    = "SELECT"
+  database_name     = "db"
+  table_name        = "events"
+  grantee_user_name = "alice"
+  grant_option      = true
+}
+
+resource "clickhousedbops_grant_privilege" "alice_select_db_events_id" {
+  privilege_name    = "SELECT"
+  database_name     = "db"
+  table_name        = "events"
+  column_name       = "id"
+  grantee_user_name = "alice"
+}
+
+resource "clickhousedbops_grant_privilege" "alice_show_databases" {
+  privilege_name    = "SHOW DATABASES"
+  grantee_user_name = "alice"
+}
+
+resource "clickhousedbops_grant_role" "alice_reader" {
+  role_name         = "reader"
+  grantee_user_name = "alice"
+  admin_option      = true
+}
+`,
+		},
+		{
+			name:            "Duplicate names on a cluster",
+			clusterName:     strPtr("cluster1"),
+			granteeRoleName: strPtr("writer"),
+			grants: &dbops.GranteeGrants{
+				Privileges: []dbops.GrantPrivilege{
+					{AccessType: "INSERT", DatabaseName: strPtr("a.b")},
+					{AccessType: "INSERT", DatabaseName: strPtr("a_b")},
+				},
+			},
+			want: `resource "clickhousedbops_grant_privilege" "writer_insert_a_b" {
+  cluster_name      = "cluster1"
+  privilege_name    = "INSERT"
+  database_name     = "a.b"
+  grantee_role_name = "writer"
+}
+
+resource "clickhousedbops_grant_privilege" "writer_insert_a_b_2" {
+  cluster_name      = "cluster1"
+  privilege_name    = "INSERT"
+  database_name     = "a_b"
+  grantee_role_name = "writer"
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderGrants(tt.clusterName, tt.granteeUserName, tt.granteeRoleName, tt.grants); got != tt.want {
+				t.Errorf("renderGrants() got = \n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
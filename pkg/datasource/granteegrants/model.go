@@ -0,0 +1,27 @@
+package granteegrants
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type GranteeGrants struct {
+	ClusterName     types.String     `tfsdk:"cluster_name"`
+	GranteeUserName types.String     `tfsdk:"grantee_user_name"`
+	GranteeRoleName types.String     `tfsdk:"grantee_role_name"`
+	Privileges      []GrantPrivilege `tfsdk:"privileges"`
+	Roles           []GrantRole      `tfsdk:"roles"`
+	HCL             types.String     `tfsdk:"hcl"`
+}
+
+type GrantPrivilege struct {
+	Privilege   types.String `tfsdk:"privilege_name"`
+	Database    types.String `tfsdk:"database_name"`
+	Table       types.String `tfsdk:"table_name"`
+	Column      types.String `tfsdk:"column_name"`
+	GrantOption types.Bool   `tfsdk:"grant_option"`
+}
+
+type GrantRole struct {
+	RoleName    types.String `tfsdk:"role_name"`
+	AdminOption types.Bool   `tfsdk:"admin_option"`
+}
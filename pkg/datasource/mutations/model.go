@@ -0,0 +1,25 @@
+package mutations
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type Mutations struct {
+	ClusterName  types.String `tfsdk:"cluster_name"`
+	DatabaseName types.String `tfsdk:"database_name"`
+	TableName    types.String `tfsdk:"table_name"`
+	OnlyFailed   types.Bool   `tfsdk:"only_failed"`
+	Mutations    []Mutation   `tfsdk:"mutations"`
+}
+
+type Mutation struct {
+	DatabaseName     types.String `tfsdk:"database_name"`
+	TableName        types.String `tfsdk:"table_name"`
+	MutationID       types.String `tfsdk:"mutation_id"`
+	Command          types.String `tfsdk:"command"`
+	CreateTime       types.String `tfsdk:"create_time"`
+	PartsToDo        types.Int64  `tfsdk:"parts_to_do"`
+	IsDone           types.Bool   `tfsdk:"is_done"`
+	LatestFailTime   types.String `tfsdk:"latest_fail_time"`
+	LatestFailReason types.String `tfsdk:"latest_fail_reason"`
+}
@@ -0,0 +1,147 @@
+package mutations
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+//go:embed mutations.md
+var mutationsDataSourceDescription string
+
+var (
+	_ datasource.DataSource              = &DataSource{}
+	_ datasource.DataSourceWithConfigure = &DataSource{}
+)
+
+func NewDataSource() datasource.DataSource {
+	return &DataSource{}
+}
+
+type DataSource struct {
+	client dbops.Client
+}
+
+func (d *DataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_mutations"
+}
+
+func (d *DataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to read mutations from. If omitted, only the replica hit by the query is read.\nThis field must be left null when using a ClickHouse Cloud cluster.",
+			},
+			"database_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return mutations of tables in this database",
+			},
+			"table_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return mutations of tables with this name",
+			},
+			"only_failed": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Only return mutations that are not done and failed at least once. Defaults to false",
+			},
+			"mutations": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Mutations matching the filters, oldest first",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"database_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the database containing the mutated table",
+						},
+						"table_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the mutated table",
+						},
+						"mutation_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "ID of the mutation",
+						},
+						"command": schema.StringAttribute{
+							Computed:    true,
+							Description: "The mutation command, e.g. `DELETE WHERE x = 1`",
+						},
+						"create_time": schema.StringAttribute{
+							Computed:    true,
+							Description: "When the mutation was submitted",
+						},
+						"parts_to_do": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Number of data parts that still need to be mutated",
+						},
+						"is_done": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the mutation is done on every replica",
+						},
+						"latest_fail_time": schema.StringAttribute{
+							Computed:    true,
+							Description: "When the mutation last failed to mutate a part",
+						},
+						"latest_fail_reason": schema.StringAttribute{
+							Computed:    true,
+							Description: "The error of the latest failure, empty if the mutation never failed",
+						},
+					},
+				},
+			},
+		},
+		MarkdownDescription: mutationsDataSourceDescription,
+	}
+}
+
+func (d *DataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(dbops.Client)
+}
+
+func (d *DataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "data.clickhousedbops_mutations", "read")
+
+	var config Mutations
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	mutations, err := d.client.GetMutations(ctx, config.DatabaseName.ValueStringPointer(), config.TableName.ValueStringPointer(), config.OnlyFailed.ValueBool(), config.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Mutations",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	config.Mutations = make([]Mutation, 0, len(mutations))
+	for _, m := range mutations {
+		config.Mutations = append(config.Mutations, Mutation{
+			DatabaseName:     types.StringValue(m.DatabaseName),
+			TableName:        types.StringValue(m.TableName),
+			MutationID:       types.StringValue(m.MutationID),
+			Command:          types.StringValue(m.Command),
+			CreateTime:       types.StringValue(m.CreateTime),
+			PartsToDo:        types.Int64Value(int64(m.PartsToDo)),
+			IsDone:           types.BoolValue(m.IsDone),
+			LatestFailTime:   types.StringValue(m.LatestFailTime),
+			LatestFailReason: types.StringValue(m.LatestFailReason),
+		})
+	}
+
+	diags = resp.State.Set(ctx, config)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,32 @@
+package table
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type Table struct {
+	ClusterName  types.String `tfsdk:"cluster_name"`
+	DatabaseName types.String `tfsdk:"database_name"`
+	Name         types.String `tfsdk:"name"`
+	UUID         types.String `tfsdk:"uuid"`
+	Engine       types.String `tfsdk:"engine"`
+	Columns      []Column     `tfsdk:"columns"`
+	OrderBy      types.List   `tfsdk:"order_by"`
+	PrimaryKey   types.List   `tfsdk:"primary_key"`
+	PartitionBy  types.String `tfsdk:"partition_by"`
+	SampleBy     types.String `tfsdk:"sample_by"`
+	TTL          types.String `tfsdk:"ttl"`
+	Settings     types.Map    `tfsdk:"settings"`
+	Comment      types.String `tfsdk:"comment"`
+	TotalRows    types.Int64  `tfsdk:"total_rows"`
+	TotalBytes   types.Int64  `tfsdk:"total_bytes"`
+}
+
+type Column struct {
+	Name        types.String `tfsdk:"name"`
+	Type        types.String `tfsdk:"type"`
+	Default     types.String `tfsdk:"default"`
+	DefaultKind types.String `tfsdk:"default_kind"`
+	Comment     types.String `tfsdk:"comment"`
+	Codec       types.String `tfsdk:"codec"`
+}
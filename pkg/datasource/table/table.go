@@ -0,0 +1,248 @@
+package table
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+//go:embed table.md
+var tableDataSourceDescription string
+
+var (
+	_ datasource.DataSource              = &DataSource{}
+	_ datasource.DataSourceWithConfigure = &DataSource{}
+)
+
+func NewDataSource() datasource.DataSource {
+	return &DataSource{}
+}
+
+type DataSource struct {
+	client dbops.Client
+}
+
+func (d *DataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_table"
+}
+
+func (d *DataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster the table lives in. `total_rows` and `total_bytes` are summed up over one replica of each shard.\nThis field must be left null when using a ClickHouse Cloud cluster.",
+			},
+			"database_name": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Name of the database containing the table. Required when looking the table up by `name`",
+			},
+			"name": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Name of the table to look up. Either `name` or `uuid` must be set",
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.Expressions{
+						path.MatchRoot("name"),
+						path.MatchRoot("uuid"),
+					}...),
+					stringvalidator.AlsoRequires(path.MatchRoot("database_name")),
+				},
+			},
+			"uuid": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "UUID of the table to look up. Either `name` or `uuid` must be set",
+			},
+			"engine": schema.StringAttribute{
+				Computed:    true,
+				Description: "The table engine, including its arguments, e.g. `ReplacingMergeTree(version)`",
+			},
+			"columns": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Columns of the table, in their order of declaration",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the column",
+						},
+						"type": schema.StringAttribute{
+							Computed:    true,
+							Description: "Type of the column",
+						},
+						"default": schema.StringAttribute{
+							Computed:    true,
+							Description: "Default expression of the column, null if it has none",
+						},
+						"default_kind": schema.StringAttribute{
+							Computed:    true,
+							Description: "How the default expression is used: one of `DEFAULT`, `MATERIALIZED`, `ALIAS` or `EPHEMERAL`",
+						},
+						"comment": schema.StringAttribute{
+							Computed:    true,
+							Description: "Comment of the column, null if it has none",
+						},
+						"codec": schema.StringAttribute{
+							Computed:    true,
+							Description: "Compression codec of the column without the surrounding `CODEC()`, null if it has none",
+						},
+					},
+				},
+			},
+			"order_by": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Expressions of the sorting key",
+			},
+			"primary_key": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Expressions of the primary key, null if it is the same as the sorting key",
+			},
+			"partition_by": schema.StringAttribute{
+				Computed:    true,
+				Description: "Partition key of the table, null if it has none",
+			},
+			"sample_by": schema.StringAttribute{
+				Computed:    true,
+				Description: "Sampling expression of the table, null if it has none",
+			},
+			"ttl": schema.StringAttribute{
+				Computed:    true,
+				Description: "TTL of the table, null if it has none",
+			},
+			"settings": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Settings of the table, including the ones ClickHouse adds on its own",
+			},
+			"comment": schema.StringAttribute{
+				Computed:    true,
+				Description: "Comment of the table",
+			},
+			"total_rows": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of rows of the table, null if the engine doesn't report it",
+			},
+			"total_bytes": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Total size of the table, in bytes, null if the engine doesn't report it",
+			},
+		},
+		MarkdownDescription: tableDataSourceDescription,
+	}
+}
+
+func (d *DataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(dbops.Client)
+}
+
+func (d *DataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "data.clickhousedbops_table", "read")
+
+	var config Table
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var table *dbops.Table
+	var err error
+	if !config.UUID.IsNull() {
+		table, err = d.client.GetTable(ctx, config.UUID.ValueString(), config.ClusterName.ValueStringPointer())
+		if err == nil && table == nil {
+			err = fmt.Errorf("table with UUID %s not found", config.UUID.ValueString())
+		}
+	} else {
+		table, err = d.client.FindTableByName(ctx, config.DatabaseName.ValueString(), config.Name.ValueString(), config.ClusterName.ValueStringPointer())
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Table",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	stats, err := d.client.GetTableStats(ctx, table.DatabaseName, table.Name, config.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Table",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	config.DatabaseName = types.StringValue(table.DatabaseName)
+	config.Name = types.StringValue(table.Name)
+	config.UUID = types.StringValue(table.UUID)
+	config.Engine = types.StringValue(table.Engine)
+	config.PartitionBy = types.StringPointerValue(table.PartitionBy)
+	config.SampleBy = types.StringPointerValue(table.SampleBy)
+	config.TTL = types.StringPointerValue(table.TTL)
+	config.Comment = types.StringValue(table.Comment)
+
+	config.Columns = make([]Column, 0, len(table.Columns))
+	for _, c := range table.Columns {
+		defaultKind := "DEFAULT"
+		if c.DefaultKind != nil {
+			defaultKind = *c.DefaultKind
+		}
+
+		config.Columns = append(config.Columns, Column{
+			Name:        types.StringValue(c.Name),
+			Type:        types.StringValue(c.Type),
+			Default:     types.StringPointerValue(c.Default),
+			DefaultKind: types.StringValue(defaultKind),
+			Comment:     types.StringPointerValue(c.Comment),
+			Codec:       types.StringPointerValue(c.Codec),
+		})
+	}
+
+	config.OrderBy, diags = types.ListValueFrom(ctx, types.StringType, table.OrderBy)
+	resp.Diagnostics.Append(diags...)
+
+	config.PrimaryKey = types.ListNull(types.StringType)
+	if len(table.PrimaryKey) > 0 {
+		config.PrimaryKey, diags = types.ListValueFrom(ctx, types.StringType, table.PrimaryKey)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	settings := table.Settings
+	if settings == nil {
+		settings = map[string]string{}
+	}
+	config.Settings, diags = types.MapValueFrom(ctx, types.StringType, settings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config.TotalRows = types.Int64Null()
+	if stats.TotalRows != nil {
+		config.TotalRows = types.Int64Value(int64(*stats.TotalRows))
+	}
+	config.TotalBytes = types.Int64Null()
+	if stats.TotalBytes != nil {
+		config.TotalBytes = types.Int64Value(int64(*stats.TotalBytes))
+	}
+
+	diags = resp.State.Set(ctx, config)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,18 @@
+package tableengines
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type TableEngines struct {
+	Names   types.List    `tfsdk:"names"`
+	Engines []TableEngine `tfsdk:"engines"`
+}
+
+type TableEngine struct {
+	Name                types.String `tfsdk:"name"`
+	SupportsSettings    types.Bool   `tfsdk:"supports_settings"`
+	SupportsTTL         types.Bool   `tfsdk:"supports_ttl"`
+	SupportsSortOrder   types.Bool   `tfsdk:"supports_sort_order"`
+	SupportsReplication types.Bool   `tfsdk:"supports_replication"`
+}
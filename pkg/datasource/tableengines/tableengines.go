@@ -0,0 +1,125 @@
+package tableengines
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+//go:embed tableengines.md
+var tableEnginesDataSourceDescription string
+
+var (
+	_ datasource.DataSource              = &DataSource{}
+	_ datasource.DataSourceWithConfigure = &DataSource{}
+)
+
+func NewDataSource() datasource.DataSource {
+	return &DataSource{}
+}
+
+type DataSource struct {
+	client dbops.Client
+}
+
+func (d *DataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_table_engines"
+}
+
+func (d *DataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"names": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Names of the available table engines, sorted. Handy with `contains()`",
+			},
+			"engines": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Available table engines, sorted by name",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the engine, e.g. `ReplicatedMergeTree`",
+						},
+						"supports_settings": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the engine supports table settings",
+						},
+						"supports_ttl": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the engine supports TTL",
+						},
+						"supports_sort_order": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the engine supports `order_by`, `primary_key`, `partition_by` and `sample_by`",
+						},
+						"supports_replication": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the engine replicates data across replicas",
+						},
+					},
+				},
+			},
+		},
+		MarkdownDescription: tableEnginesDataSourceDescription,
+	}
+}
+
+func (d *DataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(dbops.Client)
+}
+
+func (d *DataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "data.clickhousedbops_table_engines", "read")
+
+	var config TableEngines
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	engines, err := d.client.GetTableEngines(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Table Engines",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	names := make([]attr.Value, 0, len(engines))
+	config.Engines = make([]TableEngine, 0, len(engines))
+	for _, e := range engines {
+		names = append(names, types.StringValue(e.Name))
+		config.Engines = append(config.Engines, TableEngine{
+			Name:                types.StringValue(e.Name),
+			SupportsSettings:    types.BoolValue(e.SupportsSettings),
+			SupportsTTL:         types.BoolValue(e.SupportsTTL),
+			SupportsSortOrder:   types.BoolValue(e.SupportsSortOrder),
+			SupportsReplication: types.BoolValue(e.SupportsReplication),
+		})
+	}
+
+	config.Names, diags = types.ListValue(types.StringType, names)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, config)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,119 @@
+package tablehcl
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/hclgen"
+)
+
+// renderTable returns the clickhousedbops_table resource block declaring table.
+func renderTable(resourceName string, clusterName *string, table *dbops.Table) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "resource \"clickhousedbops_table\" %s {\n", hclgen.String(resourceName))
+
+	header := make([]hclgen.Attribute, 0)
+	if clusterName != nil {
+		header = append(header, hclgen.Attribute{Name: "cluster_name", Value: hclgen.String(*clusterName)})
+	}
+	header = append(header,
+		hclgen.Attribute{Name: "database_name", Value: hclgen.String(table.DatabaseName)},
+		hclgen.Attribute{Name: "name", Value: hclgen.String(table.Name)},
+	)
+	// The engine of tables created from a source function is picked by ClickHouse.
+	if table.SourceFunction == nil {
+		header = append(header, hclgen.Attribute{Name: "engine", Value: hclgen.String(table.Engine)})
+	}
+	hclgen.WriteAttributes(&sb, "  ", header)
+
+	if table.SourceFunction != nil {
+		sb.WriteString("\n  source_function = {\n")
+		hclgen.WriteAttributes(&sb, "    ", []hclgen.Attribute{
+			{Name: "name", Value: hclgen.String(table.SourceFunction.Name)},
+			{Name: "named_collection", Value: hclgen.String(table.SourceFunction.NamedCollection)},
+		})
+		if len(table.SourceFunction.Arguments) > 0 {
+			sb.WriteString("\n    arguments = {\n")
+			hclgen.WriteAttributes(&sb, "      ", sortedMap(table.SourceFunction.Arguments))
+			sb.WriteString("    }\n")
+		}
+		sb.WriteString("  }\n")
+	}
+
+	sb.WriteString("\n  columns = [")
+	if len(table.Columns) > 0 {
+		sb.WriteString("\n")
+	}
+	for _, col := range table.Columns {
+		fields := []string{
+			"name = " + hclgen.String(col.Name),
+			"type = " + hclgen.String(col.Type),
+		}
+		if col.Default != nil {
+			fields = append(fields, "default = "+hclgen.String(*col.Default))
+		}
+		if col.Comment != nil {
+			fields = append(fields, "comment = "+hclgen.String(*col.Comment))
+		}
+		fmt.Fprintf(&sb, "    { %s },\n", strings.Join(fields, ", "))
+	}
+	if len(table.Columns) > 0 {
+		sb.WriteString("  ")
+	}
+	sb.WriteString("]\n")
+
+	keys := make([]hclgen.Attribute, 0)
+	if len(table.OrderBy) > 0 {
+		keys = append(keys, hclgen.Attribute{Name: "order_by", Value: hclgen.List(table.OrderBy)})
+	}
+	// ClickHouse infers the primary key from the order by when it is not set.
+	if len(table.PrimaryKey) > 0 && !slices.Equal(table.PrimaryKey, table.OrderBy) {
+		keys = append(keys, hclgen.Attribute{Name: "primary_key", Value: hclgen.List(table.PrimaryKey)})
+	}
+	if table.PartitionBy != nil {
+		keys = append(keys, hclgen.Attribute{Name: "partition_by", Value: hclgen.String(*table.PartitionBy)})
+	}
+	if table.SampleBy != nil {
+		keys = append(keys, hclgen.Attribute{Name: "sample_by", Value: hclgen.String(*table.SampleBy)})
+	}
+	if table.TTL != nil {
+		keys = append(keys, hclgen.Attribute{Name: "ttl", Value: hclgen.String(*table.TTL)})
+	}
+	if table.Comment != "" {
+		keys = append(keys, hclgen.Attribute{Name: "comment", Value: hclgen.String(table.Comment)})
+	}
+	if len(keys) > 0 {
+		sb.WriteString("\n")
+		hclgen.WriteAttributes(&sb, "  ", keys)
+	}
+
+	if len(table.Settings) > 0 {
+		sb.WriteString("\n  settings = {\n")
+		hclgen.WriteAttributes(&sb, "    ", sortedMap(table.Settings))
+		sb.WriteString("  }\n")
+	}
+
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// sortedMap returns the entries of m as attributes sorted by key.
+func sortedMap(m map[string]string) []hclgen.Attribute {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ret := make([]hclgen.Attribute, 0, len(names))
+	for _, name := range names {
+		ret = append(ret, hclgen.Attribute{Name: hclgen.Key(name), Value: hclgen.String(m[name])})
+	}
+
+	return ret
+}
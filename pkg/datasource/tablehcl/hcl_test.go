@@ -0,0 +1,152 @@
+package tablehcl
+
+import (
+	"testing"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func Test_renderTable(t *testing.T) {
+	tests := []struct {
+		name         string
+		resourceName string
+		clusterName  *string
+		table        *dbops.Table
+		want         string
+	}{
+		{
+			name:         "minimal table",
+			resourceName: "logs",
+			table: &dbops.Table{
+				DatabaseName: "db",
+				Name:         "logs",
+				Engine:       "Log",
+				Columns: []querybuilder.TableColumn{
+					{Name: "line", Type: "String"},
+				},
+			},
+			want: `resource "clickhousedbops_table" "logs" {
+  database_name = "db"
+  name          = "logs"
+  engine        = "Log"
+
+  columns = [
+    { name = "line", type = "String" },
+  ]
+}
+`,
+		},
+		{
+			name:         "all attributes",
+			resourceName: "events",
+			clusterName:  strPtr("cluster1"),
+			table: &dbops.Table{
+				DatabaseName: "analytics",
+				Name:         "events",
+				Engine:       "ReplicatedMergeTree('/clickhouse/tables/{shard}/events', '{replica}')",
+				Columns: []querybuilder.TableColumn{
+					{Name: "ts", Type: "DateTime", Default: strPtr("now()")},
+					{Name: "user_id", Type: "UInt64", Comment: strPtr("the \"user\"")},
+				},
+				OrderBy:     []string{"user_id", "ts"},
+				PrimaryKey:  []string{"user_id"},
+				PartitionBy: strPtr("toYYYYMM(ts)"),
+				TTL:         strPtr("ts + toIntervalDay(30)"),
+				Settings:    map[string]string{"index_granularity": "8192", "storage_policy": "'s3'"},
+				Comment:     "costs ${0} %{x}",
+			},
+			want: `resource "clickhousedbops_table" "events" {
+  cluster_name  = "cluster1"
+  database_name = "analytics"
+  name          = "events"
+  engine        = "ReplicatedMergeTree('/clickhouse/tables/{shard}/events', '{replica}')"
+
+  columns = [
+    { name = "ts", type = "DateTime", default = "now()" },
+    { name = "user_id", type = "UInt64", comment = "the \"user\"" },
+  ]
+
+  order_by     = ["user_id", "ts"]
+  primary_key  = ["user_id"]
+  partition_by = "toYYYYMM(ts)"
+  ttl          = "ts + toIntervalDay(30)"
+  comment      = "costs $${0} %%{x}"
+
+  settings = {
+    index_granularity = "8192"
+    storage_policy    = "'s3'"
+  }
+}
+`,
+		},
+		{
+			name:         "table from source function",
+			resourceName: "snapshot",
+			table: &dbops.Table{
+				DatabaseName: "db",
+				Name:         "snapshot",
+				Engine:       "S3",
+				SourceFunction: &querybuilder.TableFunction{
+					Name:            "s3",
+					NamedCollection: "events_bucket",
+					Arguments:       map[string]string{"url": "https://bucket/*.parquet", "format": "Parquet"},
+				},
+				Columns: []querybuilder.TableColumn{
+					{Name: "id", Type: "UInt64"},
+				},
+			},
+			want: `resource "clickhousedbops_table" "snapshot" {
+  database_name = "db"
+  name          = "snapshot"
+
+  source_function = {
+    name             = "s3"
+    named_collection = "events_bucket"
+
+    arguments = {
+      format = "Parquet"
+      url    = "https://bucket/*.parquet"
+    }
+  }
+
+  columns = [
+    { name = "id", type = "UInt64" },
+  ]
+}
+`,
+		},
+		{
+			name:         "inferred primary key is left out",
+			resourceName: "t",
+			table: &dbops.Table{
+				DatabaseName: "db",
+				Name:         "t",
+				Engine:       "MergeTree",
+				OrderBy:      []string{"id"},
+				PrimaryKey:   []string{"id"},
+			},
+			want: `resource "clickhousedbops_table" "t" {
+  database_name = "db"
+  name          = "t"
+  engine        = "MergeTree"
+
+  columns = []
+
+  order_by = ["id"]
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderTable(tt.resourceName, tt.clusterName, tt.table); got != tt.want {
+				t.Errorf("renderTable() got = \n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package tablehcl
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type TableHCL struct {
+	ClusterName  types.String `tfsdk:"cluster_name"`
+	DatabaseName types.String `tfsdk:"database_name"`
+	Name         types.String `tfsdk:"name"`
+	ResourceName types.String `tfsdk:"resource_name"`
+	HCL          types.String `tfsdk:"hcl"`
+}
@@ -0,0 +1,100 @@
+package tablehcl
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/hclgen"
+)
+
+//go:embed tablehcl.md
+var tableHCLDataSourceDescription string
+
+var (
+	_ datasource.DataSource              = &DataSource{}
+	_ datasource.DataSourceWithConfigure = &DataSource{}
+)
+
+func NewDataSource() datasource.DataSource {
+	return &DataSource{}
+}
+
+type DataSource struct {
+	client dbops.Client
+}
+
+func (d *DataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_table_hcl"
+}
+
+func (d *DataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster the table lives in. It is also set in the generated resource.\nThis field must be left null when using a ClickHouse Cloud cluster.",
+			},
+			"database_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the database containing the table",
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the table",
+			},
+			"resource_name": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Name of the generated resource. Defaults to the table name, with characters not allowed in Terraform identifiers replaced by underscores",
+			},
+			"hcl": schema.StringAttribute{
+				Computed:    true,
+				Description: "The `clickhousedbops_table` resource block declaring the table",
+			},
+		},
+		MarkdownDescription: tableHCLDataSourceDescription,
+	}
+}
+
+func (d *DataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(dbops.Client)
+}
+
+func (d *DataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "data.clickhousedbops_table_hcl", "read")
+
+	var config TableHCL
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	table, err := d.client.FindTableByName(ctx, config.DatabaseName.ValueString(), config.Name.ValueString(), config.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Table",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if config.ResourceName.IsNull() || config.ResourceName.IsUnknown() {
+		config.ResourceName = types.StringValue(hclgen.Identifier(table.Name, "table_"))
+	}
+
+	config.HCL = types.StringValue(renderTable(config.ResourceName.ValueString(), config.ClusterName.ValueStringPointer(), table))
+
+	diags = resp.State.Set(ctx, config)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,30 @@
+package tableparts
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type TableParts struct {
+	ClusterName                types.String `tfsdk:"cluster_name"`
+	DatabaseName               types.String `tfsdk:"database_name"`
+	TableName                  types.String `tfsdk:"table_name"`
+	Partitions                 []Partition  `tfsdk:"partitions"`
+	BytesOnDiskByDisk          types.Map    `tfsdk:"bytes_on_disk_by_disk"`
+	TotalParts                 types.Int64  `tfsdk:"total_parts"`
+	TotalRows                  types.Int64  `tfsdk:"total_rows"`
+	TotalBytesOnDisk           types.Int64  `tfsdk:"total_bytes_on_disk"`
+	TotalDataUncompressedBytes types.Int64  `tfsdk:"total_data_uncompressed_bytes"`
+}
+
+type Partition struct {
+	Partition             types.String `tfsdk:"partition"`
+	PartitionID           types.String `tfsdk:"partition_id"`
+	DiskName              types.String `tfsdk:"disk_name"`
+	Parts                 types.Int64  `tfsdk:"parts"`
+	Rows                  types.Int64  `tfsdk:"rows"`
+	BytesOnDisk           types.Int64  `tfsdk:"bytes_on_disk"`
+	DataCompressedBytes   types.Int64  `tfsdk:"data_compressed_bytes"`
+	DataUncompressedBytes types.Int64  `tfsdk:"data_uncompressed_bytes"`
+	MinTime               types.String `tfsdk:"min_time"`
+	MaxTime               types.String `tfsdk:"max_time"`
+}
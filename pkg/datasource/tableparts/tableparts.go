@@ -0,0 +1,193 @@
+package tableparts
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+//go:embed tableparts.md
+var tablePartsDataSourceDescription string
+
+var (
+	_ datasource.DataSource              = &DataSource{}
+	_ datasource.DataSourceWithConfigure = &DataSource{}
+)
+
+func NewDataSource() datasource.DataSource {
+	return &DataSource{}
+}
+
+type DataSource struct {
+	client dbops.Client
+}
+
+func (d *DataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_table_parts"
+}
+
+func (d *DataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to read parts from. One replica of each shard is read and the shards are summed up. If omitted, only the replica hit by the query is read.\nThis field must be left null when using a ClickHouse Cloud cluster.",
+			},
+			"database_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the database containing the table",
+			},
+			"table_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the table",
+			},
+			"partitions": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Active parts of the table summarized by partition and disk, ordered by partition ID",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"partition": schema.StringAttribute{
+							Computed:    true,
+							Description: "The partition value, e.g. `202401` when partitioning by `toYYYYMM(timestamp)`",
+						},
+						"partition_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "The partition ID, as used by `ALTER TABLE ... PARTITION ID`",
+						},
+						"disk_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the disk storing the parts",
+						},
+						"parts": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Number of active parts",
+						},
+						"rows": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Number of rows in the parts",
+						},
+						"bytes_on_disk": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Total size of the parts on disk, in bytes",
+						},
+						"data_compressed_bytes": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Total size of the compressed column data, in bytes",
+						},
+						"data_uncompressed_bytes": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Total size of the uncompressed column data, in bytes",
+						},
+						"min_time": schema.StringAttribute{
+							Computed:    true,
+							Description: "Oldest value of the date or time column the table is partitioned by, empty if the partition key has none",
+						},
+						"max_time": schema.StringAttribute{
+							Computed:    true,
+							Description: "Newest value of the date or time column the table is partitioned by, empty if the partition key has none",
+						},
+					},
+				},
+			},
+			"bytes_on_disk_by_disk": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.Int64Type,
+				Description: "Total size of the active parts on each disk, in bytes",
+			},
+			"total_parts": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of active parts of the table",
+			},
+			"total_rows": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of rows of the table",
+			},
+			"total_bytes_on_disk": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Total size of the table on disk, in bytes",
+			},
+			"total_data_uncompressed_bytes": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Total size of the uncompressed column data of the table, in bytes",
+			},
+		},
+		MarkdownDescription: tablePartsDataSourceDescription,
+	}
+}
+
+func (d *DataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(dbops.Client)
+}
+
+func (d *DataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "data.clickhousedbops_table_parts", "read")
+
+	var config TableParts
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	summaries, err := d.client.GetTableParts(ctx, config.DatabaseName.ValueString(), config.TableName.ValueString(), config.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Table Parts",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	var totalParts, totalRows, totalBytes, totalUncompressed int64
+	byDisk := make(map[string]int64)
+	config.Partitions = make([]Partition, 0, len(summaries))
+	for _, s := range summaries {
+		config.Partitions = append(config.Partitions, Partition{
+			Partition:             types.StringValue(s.Partition),
+			PartitionID:           types.StringValue(s.PartitionID),
+			DiskName:              types.StringValue(s.DiskName),
+			Parts:                 types.Int64Value(int64(s.Parts)),
+			Rows:                  types.Int64Value(int64(s.Rows)),
+			BytesOnDisk:           types.Int64Value(int64(s.BytesOnDisk)),
+			DataCompressedBytes:   types.Int64Value(int64(s.DataCompressedBytes)),
+			DataUncompressedBytes: types.Int64Value(int64(s.DataUncompressedBytes)),
+			MinTime:               types.StringValue(s.MinTime),
+			MaxTime:               types.StringValue(s.MaxTime),
+		})
+
+		totalParts += int64(s.Parts)
+		totalRows += int64(s.Rows)
+		totalBytes += int64(s.BytesOnDisk)
+		totalUncompressed += int64(s.DataUncompressedBytes)
+		byDisk[s.DiskName] += int64(s.BytesOnDisk)
+	}
+
+	disks := make(map[string]attr.Value, len(byDisk))
+	for disk, bytes := range byDisk {
+		disks[disk] = types.Int64Value(bytes)
+	}
+	config.BytesOnDiskByDisk, diags = types.MapValue(types.Int64Type, disks)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config.TotalParts = types.Int64Value(totalParts)
+	config.TotalRows = types.Int64Value(totalRows)
+	config.TotalBytesOnDisk = types.Int64Value(totalBytes)
+	config.TotalDataUncompressedBytes = types.Int64Value(totalUncompressed)
+
+	diags = resp.State.Set(ctx, config)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,18 @@
+package tables
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type Tables struct {
+	ClusterName  types.String `tfsdk:"cluster_name"`
+	DatabaseName types.String `tfsdk:"database_name"`
+	Tables       []Table      `tfsdk:"tables"`
+}
+
+type Table struct {
+	Name     types.String `tfsdk:"name"`
+	UUID     types.String `tfsdk:"uuid"`
+	Engine   types.String `tfsdk:"engine"`
+	ImportID types.String `tfsdk:"import_id"`
+}
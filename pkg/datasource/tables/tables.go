@@ -0,0 +1,119 @@
+package tables
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+//go:embed tables.md
+var tablesDataSourceDescription string
+
+var (
+	_ datasource.DataSource              = &DataSource{}
+	_ datasource.DataSourceWithConfigure = &DataSource{}
+)
+
+func NewDataSource() datasource.DataSource {
+	return &DataSource{}
+}
+
+type DataSource struct {
+	client dbops.Client
+}
+
+func (d *DataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tables"
+}
+
+func (d *DataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to read tables from. If omitted, only the replica hit by the query is read.\nThis field must be left null when using a ClickHouse Cloud cluster.",
+			},
+			"database_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the database to list tables of",
+			},
+			"tables": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Tables of the database, sorted by name",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the table",
+						},
+						"uuid": schema.StringAttribute{
+							Computed:    true,
+							Description: "The system-assigned UUID for the table",
+						},
+						"engine": schema.StringAttribute{
+							Computed:    true,
+							Description: "Table engine, with its parameters",
+						},
+						"import_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "ID to use to import the table into a `clickhousedbops_table` resource",
+						},
+					},
+				},
+			},
+		},
+		MarkdownDescription: tablesDataSourceDescription,
+	}
+}
+
+func (d *DataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(dbops.Client)
+}
+
+func (d *DataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "data.clickhousedbops_tables", "read")
+
+	var config Tables
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tables, err := d.client.ListTables(ctx, config.DatabaseName.ValueString(), config.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Tables",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	config.Tables = make([]Table, 0, len(tables))
+	for _, t := range tables {
+		importID := fmt.Sprintf("%s:%s", t.DatabaseName, t.Name)
+		if !config.ClusterName.IsNull() {
+			importID = fmt.Sprintf("%s:%s", config.ClusterName.ValueString(), importID)
+		}
+
+		config.Tables = append(config.Tables, Table{
+			Name:     types.StringValue(t.Name),
+			UUID:     types.StringValue(t.UUID),
+			Engine:   types.StringValue(t.Engine),
+			ImportID: types.StringValue(importID),
+		})
+	}
+
+	diags = resp.State.Set(ctx, config)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,273 @@
+package admincredentials
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+//go:embed admincredentials.md
+var adminCredentialsDescription string
+
+const (
+	defaultNamePrefix = "terraform_"
+	defaultTTL        = 10 * time.Minute
+
+	// privateUserKey is the key of the private data holding the user to drop on Close.
+	privateUserKey = "user"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ ephemeral.EphemeralResource                   = &EphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure      = &EphemeralResource{}
+	_ ephemeral.EphemeralResourceWithClose          = &EphemeralResource{}
+	_ ephemeral.EphemeralResourceWithValidateConfig = &EphemeralResource{}
+)
+
+// NewEphemeralResource is a helper function to simplify the provider implementation.
+func NewEphemeralResource() ephemeral.EphemeralResource {
+	return &EphemeralResource{}
+}
+
+// EphemeralResource is the ephemeral resource implementation.
+type EphemeralResource struct {
+	client dbops.Client
+}
+
+func (r *EphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_admin_credentials"
+}
+
+func (r *EphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to create the user into. If omitted, the user will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nShould be set when hitting a cluster with more than one replica.",
+			},
+			"name_prefix": schema.StringAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("Prefix of the random name of the user. Defaults to `%s`.", defaultNamePrefix),
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"ttl": schema.StringAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("How long the user is valid for, e.g. `15m`. It should cover the whole Terraform run. Defaults to `%s`.", defaultTTL),
+			},
+			"roles": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Roles granted to the user.",
+			},
+			"grant_current_grants": schema.BoolAttribute{
+				Optional:    true,
+				Description: "When true, the user is granted every privilege of the user the provider connects with, with the grant option. Defaults to true.",
+			},
+			"user_name": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the temporary user.",
+			},
+			"password": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Password of the temporary user.",
+			},
+			"valid_until": schema.StringAttribute{
+				Computed:    true,
+				Description: "When the temporary user expires, in RFC 3339 format.",
+			},
+		},
+		MarkdownDescription: adminCredentialsDescription,
+	}
+}
+
+func (r *EphemeralResource) ValidateConfig(ctx context.Context, req ephemeral.ValidateConfigRequest, resp *ephemeral.ValidateConfigResponse) {
+	var config AdminCredentials
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.TTL.IsNull() || config.TTL.IsUnknown() {
+		return
+	}
+
+	ttl, err := time.ParseDuration(config.TTL.ValueString())
+	if err != nil || ttl <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ttl"),
+			"Invalid ttl",
+			fmt.Sprintf("%q is not a positive duration like \"15m\".", config.TTL.ValueString()),
+		)
+	}
+}
+
+func (r *EphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, _ *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(dbops.Client)
+}
+
+func (r *EphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_admin_credentials", "open")
+
+	var config AdminCredentials
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ttl := defaultTTL
+	if !config.TTL.IsNull() {
+		var err error
+		ttl, err = time.ParseDuration(config.TTL.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid ttl", fmt.Sprintf("%+v\n", err))
+			return
+		}
+	}
+
+	var roles []string
+	if !config.Roles.IsNull() {
+		resp.Diagnostics.Append(config.Roles.ElementsAs(ctx, &roles, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	namePrefix := defaultNamePrefix
+	if !config.NamePrefix.IsNull() {
+		namePrefix = config.NamePrefix.ValueString()
+	}
+
+	suffix, err := randomHex(8)
+	if err != nil {
+		resp.Diagnostics.AddError("Error generating user name", fmt.Sprintf("%+v\n", err))
+		return
+	}
+	password, err := randomHex(24)
+	if err != nil {
+		resp.Diagnostics.AddError("Error generating password", fmt.Sprintf("%+v\n", err))
+		return
+	}
+	hash := sha256.Sum256([]byte(password))
+
+	clusterName := config.ClusterName.ValueStringPointer()
+	validUntil := time.Now().Add(ttl).UTC().Truncate(time.Second)
+	user := dbops.User{
+		Name:               namePrefix + suffix,
+		PasswordSha256Hash: hex.EncodeToString(hash[:]),
+		ValidUntil:         &validUntil,
+	}
+
+	// Create the user and its grants as a group, so that no user is left behind when a grant fails.
+	var created *dbops.User
+	steps := []dbops.DDLStep{
+		{
+			Name: "user " + user.Name,
+			Apply: func(ctx context.Context) error {
+				created, err = r.client.CreateUser(ctx, user, clusterName)
+				return err
+			},
+			Rollback: func(ctx context.Context) error {
+				return r.client.DeleteUser(ctx, created.ID, clusterName)
+			},
+		},
+	}
+	if config.GrantCurrentGrants.IsNull() || config.GrantCurrentGrants.ValueBool() {
+		steps = append(steps, dbops.DDLStep{
+			Name:      "current grants",
+			DependsOn: []string{"user " + user.Name},
+			Apply: func(ctx context.Context) error {
+				return r.client.GrantCurrentGrants(ctx, user.Name, clusterName)
+			},
+		})
+	}
+	for _, role := range roles {
+		steps = append(steps, dbops.DDLStep{
+			Name:      "role " + role,
+			DependsOn: []string{"user " + user.Name},
+			Apply: func(ctx context.Context) error {
+				_, err := r.client.GrantRole(ctx, dbops.GrantRole{RoleName: role, GranteeUserName: &user.Name}, clusterName)
+				return err
+			},
+		})
+	}
+
+	if err := dbops.ExecuteDDL(ctx, steps); err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating temporary user",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	private, err := json.Marshal(privateUser{ID: created.ID, ClusterName: clusterName})
+	if err != nil {
+		resp.Diagnostics.AddError("Error saving temporary user", fmt.Sprintf("%+v\n", err))
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, privateUserKey, private)...)
+
+	config.UserName = types.StringValue(user.Name)
+	config.Password = types.StringValue(password)
+	config.ValidUntil = types.StringValue(validUntil.Format(time.RFC3339))
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &config)...)
+}
+
+func (r *EphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_admin_credentials", "close")
+
+	data, diags := req.Private.GetKey(ctx, privateUserKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || data == nil {
+		return
+	}
+
+	var user privateUser
+	if err := json.Unmarshal(data, &user); err != nil {
+		resp.Diagnostics.AddError("Error reading temporary user", fmt.Sprintf("%+v\n", err))
+		return
+	}
+
+	err := r.client.DeleteUser(ctx, user.ID, user.ClusterName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting temporary user",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+}
+
+// randomHex returns n random bytes, hex encoded.
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
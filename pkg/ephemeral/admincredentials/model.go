@@ -0,0 +1,22 @@
+package admincredentials
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type AdminCredentials struct {
+	ClusterName        types.String `tfsdk:"cluster_name"`
+	NamePrefix         types.String `tfsdk:"name_prefix"`
+	TTL                types.String `tfsdk:"ttl"`
+	Roles              types.Set    `tfsdk:"roles"`
+	GrantCurrentGrants types.Bool   `tfsdk:"grant_current_grants"`
+	UserName           types.String `tfsdk:"user_name"`
+	Password           types.String `tfsdk:"password"`
+	ValidUntil         types.String `tfsdk:"valid_until"`
+}
+
+// privateUser is what Close needs to drop the user, kept in the private data of the ephemeral resource.
+type privateUser struct {
+	ID          string  `json:"id"`
+	ClusterName *string `json:"cluster_name"`
+}
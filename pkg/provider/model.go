@@ -6,11 +6,28 @@ import (
 
 // Model describes the provider data model.
 type Model struct {
-	Protocol   types.String `tfsdk:"protocol"`
-	Host       types.String `tfsdk:"host"`
-	Port       types.Int32  `tfsdk:"port"`
-	AuthConfig AuthConfig   `tfsdk:"auth_config"`
-	TLSConfig  *TLSConfig   `tfsdk:"tls_config"`
+	Protocol           types.String        `tfsdk:"protocol"`
+	Host               types.String        `tfsdk:"host"`
+	Port               types.Int32         `tfsdk:"port"`
+	AuthConfig         AuthConfig          `tfsdk:"auth_config"`
+	TLSConfig          *TLSConfig          `tfsdk:"tls_config"`
+	MaxOpenConns       types.Int32         `tfsdk:"max_open_conns"`
+	MaxIdleConns       types.Int32         `tfsdk:"max_idle_conns"`
+	ConnMaxLifetime    types.String        `tfsdk:"conn_max_lifetime"`
+	LocalReplicaReads  types.Bool          `tfsdk:"local_replica_reads"`
+	ReadOnly           types.Bool          `tfsdk:"read_only"`
+	AllowPartialReads  types.Bool          `tfsdk:"allow_partial_reads"`
+	ClusterHealthCheck *ClusterHealthCheck `tfsdk:"cluster_health_check"`
+	CloudMaintenance   *CloudMaintenance   `tfsdk:"cloud_maintenance"`
+	AccessStorageMode  types.String        `tfsdk:"access_storage_mode"`
+	MaxCommentBytes    types.Int64         `tfsdk:"max_comment_bytes"`
+	ReadAfterCreate    types.String        `tfsdk:"read_after_create_timeout"`
+	ProtectedDatabases types.List          `tfsdk:"protected_databases"`
+	DefaultDatabase    types.String        `tfsdk:"default_database"`
+	ClientName         types.String        `tfsdk:"client_name"`
+	RunID              types.String        `tfsdk:"run_id"`
+	NotifyCommand      types.List          `tfsdk:"notify_command"`
+	NotifyWebhookURL   types.String        `tfsdk:"notify_webhook_url"`
 }
 
 type AuthConfig struct {
@@ -19,6 +36,15 @@ type AuthConfig struct {
 	Password types.String `tfsdk:"password"`
 }
 
+type ClusterHealthCheck struct {
+	MaxDDLQueueBacklog types.Int64 `tfsdk:"max_ddl_queue_backlog"`
+}
+
+type CloudMaintenance struct {
+	Mode        types.String `tfsdk:"mode"`
+	WaitTimeout types.String `tfsdk:"wait_timeout"`
+}
+
 type TLSConfig struct {
 	InsecureSkipVerify types.Bool `tfsdk:"insecure_skip_verify"`
 }
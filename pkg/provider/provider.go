@@ -4,24 +4,63 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	tfresource "github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clickhouseclient"
 	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/datasource/accessentities"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/datasource/currentuser"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/datasource/databases"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/datasource/detachedparts"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/datasource/granteegrants"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/datasource/mutations"
+	tabledatasource "github.com/anglinb/terraform-provider-clickhousedbops/pkg/datasource/table"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/datasource/tableengines"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/datasource/tablehcl"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/datasource/tableparts"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/datasource/tables"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/ephemeral/admincredentials"
 	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/project"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/columnmaskingpolicy"
 	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/database"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/detachedpartsretention"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/dictionary"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/freezetable"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/function"
 	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/grantprivilege"
 	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/grantrole"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/killmutation"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/materializedview"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/optimizetable"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/partitionretention"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/quota"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/quotaassignment"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/reloaddictionary"
 	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/role"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/rowpolicy"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/settingsprofile"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/settingsprofileassignment"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/shardedtable"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/syncreplica"
 	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/table"
 	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/user"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/vectorsimilarityindex"
+	"github.com/anglinb/terraform-provider-clickhousedbops/pkg/resource/view"
 )
 
 const (
@@ -32,6 +71,10 @@ const (
 
 	authStrategyPassword  = "password"
 	authStrategyBasicAuth = "basicauth"
+
+	defaultReadAfterCreateTimeout = 10 * time.Second
+
+	defaultCloudMaintenanceWaitTimeout = 15 * time.Minute
 )
 
 var (
@@ -40,7 +83,10 @@ var (
 )
 
 // Ensure Provider satisfies various provider interfaces.
-var _ provider.Provider = &Provider{}
+var (
+	_ provider.Provider                       = &Provider{}
+	_ provider.ProviderWithEphemeralResources = &Provider{}
+)
 
 // Provider defines the provider implementation.
 type Provider struct{}
@@ -86,6 +132,7 @@ func (p *Provider) Schema(ctx context.Context, req provider.SchemaRequest, resp
 					},
 					"password": schema.StringAttribute{
 						Optional:    true,
+						Sensitive:   true,
 						Description: "The password to use to authenticate to ClickHouse",
 						Validators: []validator.String{
 							stringvalidator.LengthAtLeast(1),
@@ -105,6 +152,122 @@ func (p *Provider) Schema(ctx context.Context, req provider.SchemaRequest, resp
 				Optional:    true,
 				Description: "TLS configuration options",
 			},
+			"max_open_conns": schema.Int32Attribute{
+				Optional:    true,
+				Description: "Maximum number of connections open to the clickhouse instance at the same time. Defaults to max_idle_conns + 5 with the native protocol and to no limit with the http protocol",
+				Validators: []validator.Int32{
+					int32validator.AtLeast(1),
+				},
+			},
+			"max_idle_conns": schema.Int32Attribute{
+				Optional:    true,
+				Description: "Maximum number of idle connections kept open for reuse. Defaults to 5 with the native protocol and to 2 with the http protocol",
+				Validators: []validator.Int32{
+					int32validator.AtLeast(1),
+				},
+			},
+			"conn_max_lifetime": schema.StringAttribute{
+				Optional:    true,
+				Description: "How long a connection is kept before being closed, as a duration like `10m`. Only supported with the native and nativesecure protocols. Defaults to 1h",
+			},
+			"local_replica_reads": schema.BoolAttribute{
+				Optional:    true,
+				Description: "When true, resources with a `cluster_name` read their state from the replica the provider is connected to only, instead of querying every replica of the cluster. Changes are still applied with ON CLUSTER. Speeds up refresh on big clusters, but drift on other replicas is not detected. Defaults to false",
+			},
+			"read_only": schema.BoolAttribute{
+				Optional:    true,
+				Description: "When true, the provider never changes anything on the ClickHouse side: creating, updating or deleting a resource fails with the SQL statement it would have run, while refresh and data sources work as usual. Useful for audit-only pipelines and to validate the access of restricted credentials. Defaults to false",
+			},
+			"allow_partial_reads": schema.BoolAttribute{
+				Optional:    true,
+				Description: "When true, a refresh that fails because the user is not allowed to read some system table (e.g. `system.grants` on ClickHouse Cloud) keeps the prior state of the resource and reports a warning instead of failing the whole refresh. Changes made outside of terraform to such resources are not detected. Defaults to false",
+			},
+			"cluster_health_check": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"max_ddl_queue_backlog": schema.Int64Attribute{
+						Optional:    true,
+						Description: "Number of distributed DDL queries of the cluster not finished yet (from `system.distributed_ddl_queue`) above which the cluster is considered unhealthy. Defaults to 0",
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+				},
+				Optional:    true,
+				Description: "When set, creating, altering or dropping databases and tables with a `cluster_name` first checks that Keeper is reachable, that no replica of the cluster is read only and that the distributed DDL queue is not backed up, and fails without running anything otherwise. Avoids schema changes left half applied on an unhealthy cluster",
+			},
+			"cloud_maintenance": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"mode": schema.StringAttribute{
+						Optional:    true,
+						Description: "`fail` makes the statements rejected because of a maintenance window fail right away with an error saying so, `wait` runs them again every 15 seconds until the window is over or `wait_timeout` elapses. Defaults to `fail`",
+						Validators: []validator.String{
+							stringvalidator.OneOf(dbops.CloudMaintenanceModes...),
+						},
+					},
+					"wait_timeout": schema.StringAttribute{
+						Optional:    true,
+						Description: "How long the `wait` mode waits for a maintenance window to end, as a duration like `30m`. Defaults to 15m",
+					},
+				},
+				Optional:    true,
+				Description: "When set, queries rejected because the ClickHouse Cloud service is in a maintenance window or read only (`READONLY` and `TOO_MANY_SIMULTANEOUS_QUERIES` errors) are detected and handled according to `mode`, instead of failing the apply with the raw error. `READONLY` errors are only handled as a maintenance window when the `readonly` setting of the provider user is off, they are returned as is otherwise",
+			},
+			"access_storage_mode": schema.StringAttribute{
+				Optional:    true,
+				Description: "Default `access_storage_mode` of users, roles and grants with a `cluster_name`: `on_cluster` runs their statements ON CLUSTER, `replicated` runs them on the replica the provider is connected to only and relies on replicated access storage to propagate them, `auto` picks `replicated` when the highest priority user directory of the server is replicated and `on_cluster` otherwise. Defaults to `auto`",
+				Validators: []validator.String{
+					stringvalidator.OneOf(dbops.AccessStorageModes...),
+				},
+			},
+			"max_comment_bytes": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum size, in bytes, of the comments of databases, tables and columns, checked at plan time. ClickHouse does not limit comments and counts their size in bytes, so characters outside of ASCII take more than one byte. Unlimited by default",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"read_after_create_timeout": schema.StringAttribute{
+				Optional:    true,
+				Description: "How long objects created with a `cluster_name` are looked for, as a duration like `30s`, before concluding they don't exist. Every replica applies ON CLUSTER statements on its own, so the read following a creation can hit a replica that did not apply it yet. Set to `0s` to fail right away. Defaults to 10s",
+			},
+			"protected_databases": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Databases that resources refuse to create or drop, and whose tables they refuse to manage, so that a `database_name` computed from variables can't touch them. Names are case sensitive. Replaces the default list, `[\"system\", \"INFORMATION_SCHEMA\", \"information_schema\"]`, set to an empty list to turn the protection off",
+			},
+			"default_database": schema.StringAttribute{
+				Optional:    true,
+				Description: "Database of the `clickhousedbops_table`, `clickhousedbops_view` and `clickhousedbops_materialized_view` resources that leave `database_name` out. The resolved name is recorded in their state, and changing it recreates them",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"client_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name identifying this terraform configuration to ClickHouse, e.g. the workspace name. It is sent along with the provider name and version as the client name (native protocol) or User-Agent (http protocol), and shows up in `system.processes` and `system.query_log`",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"run_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "Identifier of the terraform run (e.g. the CI job id) sent in the `log_comment` setting of every query along with the resource type (e.g. `clickhousedbops_table`) and operation (e.g. `create`), so that entries of `system.query_log` can be attributed to terraform. Terraform doesn't tell providers the address of resources, so it can't be sent. Defaults to a random id generated every time the provider starts",
+			},
+			"notify_command": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Command, as the program followed by its arguments, run after every destructive statement (`DROP`, `TRUNCATE`, `DETACH`, `DELETE`, and `ALTER TABLE` dropping columns, indexes, projections or partitions, deleting rows or removing the TTL) that ran successfully. It gets a JSON payload with the statement, its kind, the resource type, the operation and the `run_id` on its standard input. Failures are logged as warnings and don't fail the operation",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"notify_webhook_url": schema.StringAttribute{
+				Optional:    true,
+				Description: "URL receiving the JSON payload described in `notify_command` in a POST request after every destructive statement that ran successfully. Failures are logged as warnings and don't fail the operation",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^https?://`), "must be an http or https URL"),
+				},
+			},
 		},
 	}
 }
@@ -124,8 +287,63 @@ func (p *Provider) Configure(ctx context.Context, req provider.ConfigureRequest,
 		return
 	}
 
-	var clickhouseClient clickhouseclient.ClickhouseClient
+	var connectionPool clickhouseclient.ConnectionPoolConfig
 	{
+		if !data.MaxOpenConns.IsNull() {
+			connectionPool.MaxOpenConns = int(data.MaxOpenConns.ValueInt32())
+		}
+		if !data.MaxIdleConns.IsNull() {
+			connectionPool.MaxIdleConns = int(data.MaxIdleConns.ValueInt32())
+		}
+		if !data.ConnMaxLifetime.IsNull() {
+			if data.Protocol.ValueString() == protocolHTTP || data.Protocol.ValueString() == protocolHTTPS {
+				resp.Diagnostics.AddError("invalid configuration", fmt.Sprintf("conn_max_lifetime is not supported with the %s protocol: the http client cannot close connections after a lifetime.", data.Protocol.ValueString()))
+				return
+			}
+			connectionPool.ConnMaxLifetime, err = time.ParseDuration(data.ConnMaxLifetime.ValueString())
+			if err != nil || connectionPool.ConnMaxLifetime <= 0 {
+				resp.Diagnostics.AddError("invalid configuration", fmt.Sprintf("invalid conn_max_lifetime %q, must be a positive duration like \"10m\".", data.ConnMaxLifetime.ValueString()))
+				return
+			}
+		}
+	}
+
+	readAfterCreateTimeout := defaultReadAfterCreateTimeout
+	if !data.ReadAfterCreate.IsNull() {
+		readAfterCreateTimeout, err = time.ParseDuration(data.ReadAfterCreate.ValueString())
+		if err != nil || readAfterCreateTimeout < 0 {
+			resp.Diagnostics.AddError("invalid configuration", fmt.Sprintf("invalid read_after_create_timeout %q, must be a duration like \"30s\".", data.ReadAfterCreate.ValueString()))
+			return
+		}
+	}
+
+	protectedDatabases := dbops.DefaultProtectedDatabases
+	if !data.ProtectedDatabases.IsNull() {
+		protectedDatabases = make([]string, 0)
+		resp.Diagnostics.Append(data.ProtectedDatabases.ElementsAs(ctx, &protectedDatabases, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	clientProducts := []clickhouseclient.ClientProduct{{Name: project.FullName(), Version: project.Version()}}
+	if data.ClientName.ValueString() != "" {
+		clientProducts = append([]clickhouseclient.ClientProduct{{Name: data.ClientName.ValueString()}}, clientProducts...)
+	}
+
+	cassette, err := clickhouseclient.CassetteConfigFromEnv()
+	if err != nil {
+		resp.Diagnostics.AddError("invalid configuration", err.Error())
+		return
+	}
+
+	var clickhouseClient clickhouseclient.ClickhouseClient
+	// hostClient connects to another host of the cluster the same way, for operations targeting a shard.
+	var hostClient func(host string) (clickhouseclient.ClickhouseClient, error)
+	if cassette != nil && cassette.Mode == clickhouseclient.CassetteModeReplay {
+		// Plans are replayed offline, without connecting to the server.
+		clickhouseClient, err = clickhouseclient.NewReplayClient(cassette.Path)
+	} else {
 		switch data.Protocol.ValueString() {
 		case protocolNative:
 			fallthrough
@@ -163,12 +381,21 @@ func (p *Provider) Configure(ctx context.Context, req provider.ConfigureRequest,
 				}
 			}
 
-			clickhouseClient, err = clickhouseclient.NewNativeClient(clickhouseclient.NativeClientConfig{
+			config := clickhouseclient.NativeClientConfig{
 				Host:             data.Host.ValueString(),
 				Port:             port,
 				UserPasswordAuth: auth,
 				EnableTLS:        data.Protocol.ValueString() == protocolNativeSecure,
-			})
+				ConnectionPool:   connectionPool,
+				ClientProducts:   clientProducts,
+			}
+
+			clickhouseClient, err = clickhouseclient.NewNativeClient(config)
+			hostClient = func(host string) (clickhouseclient.ClickhouseClient, error) {
+				hostConfig := config
+				hostConfig.Host = host
+				return clickhouseclient.NewNativeClient(hostConfig)
+			}
 		case protocolHTTP:
 			fallthrough
 		case protocolHTTPS:
@@ -216,14 +443,21 @@ func (p *Provider) Configure(ctx context.Context, req provider.ConfigureRequest,
 			}
 
 			config := clickhouseclient.HTTPClientConfig{
-				Protocol:  protocol,
-				Host:      data.Host.ValueString(),
-				Port:      port,
-				BasicAuth: auth,
-				TLSConfig: tlsConfig,
+				Protocol:       protocol,
+				Host:           data.Host.ValueString(),
+				Port:           port,
+				BasicAuth:      auth,
+				TLSConfig:      tlsConfig,
+				ConnectionPool: connectionPool,
+				ClientProducts: clientProducts,
 			}
 
 			clickhouseClient, err = clickhouseclient.NewHTTPClient(config)
+			hostClient = func(host string) (clickhouseclient.ClickhouseClient, error) {
+				hostConfig := config
+				hostConfig.Host = host
+				return clickhouseclient.NewHTTPClient(hostConfig)
+			}
 		}
 	}
 
@@ -232,7 +466,77 @@ func (p *Provider) Configure(ctx context.Context, req provider.ConfigureRequest,
 		return
 	}
 
-	dbopsClient, err := dbops.NewClient(clickhouseClient)
+	if cassette != nil && cassette.Mode == clickhouseclient.CassetteModeRecord {
+		clickhouseClient, err = clickhouseclient.NewRecordingClient(clickhouseClient, cassette.Path)
+		if err != nil {
+			resp.Diagnostics.AddError("error initializing clickhouse client", fmt.Sprintf("%+v\n", err))
+			return
+		}
+	}
+
+	runID := data.RunID.ValueString()
+	if runID == "" {
+		runID = uuid.NewString()
+	}
+	if cassette != nil {
+		// Queries to other hosts can't be recorded nor replayed.
+		hostClient = nil
+	}
+
+	var clusterHealthCheck *dbops.ClusterHealthCheck
+	if data.ClusterHealthCheck != nil {
+		clusterHealthCheck = &dbops.ClusterHealthCheck{
+			MaxDDLQueueBacklog: uint64(data.ClusterHealthCheck.MaxDDLQueueBacklog.ValueInt64()),
+		}
+	}
+
+	var cloudMaintenance *dbops.CloudMaintenance
+	if data.CloudMaintenance != nil {
+		cloudMaintenance = &dbops.CloudMaintenance{
+			Mode:        dbops.CloudMaintenanceModeFail,
+			WaitTimeout: defaultCloudMaintenanceWaitTimeout,
+		}
+		if !data.CloudMaintenance.Mode.IsNull() {
+			cloudMaintenance.Mode = data.CloudMaintenance.Mode.ValueString()
+		}
+		if !data.CloudMaintenance.WaitTimeout.IsNull() {
+			cloudMaintenance.WaitTimeout, err = time.ParseDuration(data.CloudMaintenance.WaitTimeout.ValueString())
+			if err != nil || cloudMaintenance.WaitTimeout < 0 {
+				resp.Diagnostics.AddError("invalid configuration", fmt.Sprintf("invalid cloud_maintenance.wait_timeout %q, must be a duration like \"30m\".", data.CloudMaintenance.WaitTimeout.ValueString()))
+				return
+			}
+		}
+	}
+
+	var notification *dbops.Notification
+	if !data.NotifyCommand.IsNull() || data.NotifyWebhookURL.ValueString() != "" {
+		notification = &dbops.Notification{
+			WebhookURL: data.NotifyWebhookURL.ValueString(),
+			RunID:      runID,
+		}
+		if !data.NotifyCommand.IsNull() {
+			resp.Diagnostics.Append(data.NotifyCommand.ElementsAs(ctx, &notification.Command, false)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+	}
+
+	dbopsClient, err := dbops.NewClient(clickhouseClient, dbops.Config{
+		LocalReplicaReads:      data.LocalReplicaReads.ValueBool(),
+		ReadOnly:               data.ReadOnly.ValueBool(),
+		AllowPartialReads:      data.AllowPartialReads.ValueBool(),
+		ClusterHealthCheck:     clusterHealthCheck,
+		CloudMaintenance:       cloudMaintenance,
+		AccessStorageMode:      data.AccessStorageMode.ValueString(),
+		MaxCommentBytes:        int(data.MaxCommentBytes.ValueInt64()),
+		HostClient:             hostClient,
+		RunID:                  runID,
+		ReadAfterCreateTimeout: readAfterCreateTimeout,
+		ProtectedDatabases:     protectedDatabases,
+		DefaultDatabase:        data.DefaultDatabase.ValueString(),
+		Notification:           notification,
+	})
 	if err != nil {
 		resp.Diagnostics.AddError("error initializing dbops client", fmt.Sprintf("%+v\n", err))
 		return
@@ -240,21 +544,59 @@ func (p *Provider) Configure(ctx context.Context, req provider.ConfigureRequest,
 
 	resp.ResourceData = dbopsClient
 	resp.DataSourceData = dbopsClient
+	resp.EphemeralResourceData = dbopsClient
 }
 
 func (p *Provider) Resources(ctx context.Context) []func() tfresource.Resource {
 	return []func() tfresource.Resource{
 		database.NewResource,
 		role.NewResource,
+		rowpolicy.NewResource,
+		settingsprofile.NewResource,
+		quota.NewResource,
 		user.NewResource,
 		grantrole.NewResource,
 		grantprivilege.NewResource,
+		settingsprofileassignment.NewResource,
+		quotaassignment.NewResource,
 		table.NewResource,
+		optimizetable.NewResource,
+		syncreplica.NewResource,
+		reloaddictionary.NewResource,
+		partitionretention.NewResource,
+		detachedpartsretention.NewResource,
+		freezetable.NewResource,
+		killmutation.NewResource,
+		shardedtable.NewResource,
+		vectorsimilarityindex.NewResource,
+		view.NewResource,
+		columnmaskingpolicy.NewResource,
+		materializedview.NewResource,
+		dictionary.NewResource,
+		function.NewResource,
 	}
 }
 
 func (p *Provider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		mutations.NewDataSource,
+		tables.NewDataSource,
+		tabledatasource.NewDataSource,
+		databases.NewDataSource,
+		tablehcl.NewDataSource,
+		granteegrants.NewDataSource,
+		currentuser.NewDataSource,
+		tableengines.NewDataSource,
+		accessentities.NewDataSource,
+		tableparts.NewDataSource,
+		detachedparts.NewDataSource,
+	}
+}
+
+func (p *Provider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		admincredentials.NewEphemeralResource,
+	}
 }
 
 func New() func() provider.Provider {
@@ -0,0 +1,671 @@
+package columnmaskingpolicy
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clustername"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/defaultdatabase"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/protecteddatabase"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+//go:embed columnmaskingpolicy.md
+var columnMaskingPolicyResourceDescription string
+
+// selectPrivilege is the privilege granted on the view, and revoked on the table.
+const selectPrivilege = "SELECT"
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &Resource{}
+	_ resource.ResourceWithConfigure      = &Resource{}
+	_ resource.ResourceWithModifyPlan     = &Resource{}
+	_ resource.ResourceWithValidateConfig = &Resource{}
+)
+
+// NewResource is a helper function to simplify the provider implementation.
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+// Resource is the resource implementation.
+type Resource struct {
+	client dbops.Client
+}
+
+// Metadata returns the resource type name.
+func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_column_masking_policy"
+}
+
+// Schema defines the schema for the resource.
+func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to create the view and the grants into. If omitted, they will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nShould be set when hitting a cluster with more than one replica.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"uuid": schema.StringAttribute{
+				Computed:    true,
+				Description: "The system-assigned UUID for the masked view",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"database_name": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Name of the database of the table, where the view is created too. Defaults to the provider's `default_database`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"table_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the table to mask the columns of",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"view_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the view exposing the table with the columns masked",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"masked_columns": schema.MapNestedAttribute{
+				Required:    true,
+				Description: "How to mask the columns of the table, keyed by column name.",
+				Validators: []validator.Map{
+					mapvalidator.SizeAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"method": schema.StringAttribute{
+							Required:    true,
+							Description: fmt.Sprintf("How to mask the column, one of %q, %q or %q.", querybuilder.MaskHash, querybuilder.MaskRedact, querybuilder.MaskExpression),
+							Validators: []validator.String{
+								stringvalidator.OneOf(querybuilder.MaskMethods...),
+							},
+						},
+						"expression": schema.StringAttribute{
+							Optional:    true,
+							Description: fmt.Sprintf("SQL expression replacing the column, required with the %q method.", querybuilder.MaskExpression),
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+					},
+				},
+			},
+			"excluded_columns": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Names of the columns of the table left out of the view.",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.RequiresReplace(),
+				},
+			},
+			"grantee_user_names": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Names of the users granted SELECT on the view.",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"grantee_role_names": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Names of the roles granted SELECT on the view.",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"revoke_table_access": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "If true, SELECT on the table is revoked from the grantees when they are added. Defaults to true.",
+			},
+			"query": schema.StringAttribute{
+				Computed:    true,
+				Description: "The SELECT query of the view, rendered during plan unless the configuration depends on values only known during apply.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		MarkdownDescription: columnMaskingPolicyResourceDescription,
+	}
+}
+
+func (r *Resource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config ColumnMaskingPolicy
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for columnName, column := range config.MaskedColumns {
+		if column.Method.IsUnknown() || column.Expression.IsUnknown() {
+			continue
+		}
+
+		custom := column.Method.ValueString() == querybuilder.MaskExpression
+		if custom && column.Expression.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("masked_columns").AtMapKey(columnName).AtName("expression"),
+				"Missing Masking Expression",
+				fmt.Sprintf("'expression' must be set to mask a column with the %q method.", querybuilder.MaskExpression),
+			)
+		}
+		if !custom && !column.Expression.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("masked_columns").AtMapKey(columnName).AtName("expression"),
+				"Invalid Masking Expression",
+				fmt.Sprintf("'expression' can only be set with the %q method.", querybuilder.MaskExpression),
+			)
+		}
+	}
+
+	if config.ExcludedColumns.IsUnknown() {
+		return
+	}
+	var excluded []string
+	resp.Diagnostics.Append(config.ExcludedColumns.ElementsAs(ctx, &excluded, false)...)
+	for _, columnName := range excluded {
+		if _, ok := config.MaskedColumns[columnName]; ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("excluded_columns"),
+				"Invalid Excluded Column",
+				fmt.Sprintf("Column %q cannot be both masked and excluded.", columnName),
+			)
+		}
+	}
+}
+
+func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_column_masking_policy", "plan")
+
+	clustername.ValidatePlan(ctx, r.client, req.Plan, &resp.Diagnostics)
+
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	defaultdatabase.ResolvePlan(ctx, r.client, req, resp)
+
+	var planDatabaseName types.String
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("database_name"), &planDatabaseName)...)
+	protecteddatabase.Validate(r.client, path.Root("database_name"), planDatabaseName, &resp.Diagnostics)
+
+	if req.State.Raw.IsNull() && req.Config.Raw.IsFullyKnown() && !resp.Diagnostics.HasError() {
+		// Render the query of the view Create will make.
+		var plan ColumnMaskingPolicy
+		resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		query, err := maskedQuery(ctx, plan)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error rendering column masking policy",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("query"), query)...)
+	}
+}
+
+func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(dbops.Client)
+}
+
+func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_column_masking_policy", "create")
+
+	var plan ColumnMaskingPolicy
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	query, err := maskedQuery(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating column masking policy",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	created, err := r.client.CreateView(ctx, dbops.View{
+		DatabaseName:       plan.DatabaseName.ValueString(),
+		Name:               plan.ViewName.ValueString(),
+		Query:              query,
+		SQLSecurityDefiner: true,
+	}, plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating column masking policy",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	// Save the view before granting it: should a grant fail, the policy is tainted and the view dropped by the
+	// replacement instead of being left behind.
+	state := plan
+	state.UUID = types.StringValue(created.UUID)
+	state.Query = types.StringValue(query)
+	state.GranteeUserNames = types.SetNull(types.StringType)
+	state.GranteeRoleNames = types.SetNull(types.StringType)
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	userNames, roleNames, err := grantees(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating column masking policy",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	for _, userName := range userNames {
+		err = r.grant(ctx, plan, &userName, nil)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error granting column masking policy",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+	}
+	for _, roleName := range roleNames {
+		err = r.grant(ctx, plan, nil, &roleName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error granting column masking policy",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+	}
+
+	state.GranteeUserNames = plan.GranteeUserNames
+	state.GranteeRoleNames = plan.GranteeRoleNames
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_column_masking_policy", "read")
+
+	var state ColumnMaskingPolicy
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	view, err := r.client.GetView(ctx, state.UUID.ValueString(), state.ClusterName.ValueStringPointer())
+	if dbops.IsRestrictedRead(err) {
+		resp.Diagnostics.AddWarning(
+			"Unable to Refresh ClickHouse Column Masking Policy",
+			"Not allowed to read the view, keeping the prior state: "+err.Error(),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading column masking policy",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if view == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// The view itself is kept as configured, as ClickHouse normalizes its query: only the grantees are refreshed.
+	userNames, roleNames, err := grantees(ctx, state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading column masking policy",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	granted := func(userName *string, roleName *string) (bool, error) {
+		grant, err := r.client.GetGrantPrivilege(ctx, selectPrivilege, &view.DatabaseName, &view.Name, nil, userName, roleName, state.ClusterName.ValueStringPointer())
+		return grant != nil, err
+	}
+
+	grantedUserNames := make([]string, 0, len(userNames))
+	for _, userName := range userNames {
+		ok, err := granted(&userName, nil)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error reading column masking policy",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+		if ok {
+			grantedUserNames = append(grantedUserNames, userName)
+		}
+	}
+	grantedRoleNames := make([]string, 0, len(roleNames))
+	for _, roleName := range roleNames {
+		ok, err := granted(nil, &roleName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error reading column masking policy",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+		if ok {
+			grantedRoleNames = append(grantedRoleNames, roleName)
+		}
+	}
+
+	if !state.GranteeUserNames.IsNull() {
+		state.GranteeUserNames, diags = types.SetValueFrom(ctx, types.StringType, grantedUserNames)
+		resp.Diagnostics.Append(diags...)
+	}
+	if !state.GranteeRoleNames.IsNull() {
+		state.GranteeRoleNames, diags = types.SetValueFrom(ctx, types.StringType, grantedRoleNames)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_column_masking_policy", "update")
+
+	// Anything but the grantees and revoke_table_access requires a replacement.
+	var plan, state ColumnMaskingPolicy
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plannedUserNames, plannedRoleNames, err := grantees(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating column masking policy",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+	currentUserNames, currentRoleNames, err := grantees(ctx, state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating column masking policy",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	for _, userName := range difference(currentUserNames, plannedUserNames) {
+		err = r.revoke(ctx, plan, &userName, nil)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error revoking column masking policy",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+	}
+	for _, roleName := range difference(currentRoleNames, plannedRoleNames) {
+		err = r.revoke(ctx, plan, nil, &roleName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error revoking column masking policy",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+	}
+	for _, userName := range difference(plannedUserNames, currentUserNames) {
+		err = r.grant(ctx, plan, &userName, nil)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error granting column masking policy",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+	}
+	for _, roleName := range difference(plannedRoleNames, currentRoleNames) {
+		err = r.grant(ctx, plan, nil, &roleName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error granting column masking policy",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_column_masking_policy", "delete")
+
+	var state ColumnMaskingPolicy
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// ClickHouse keeps the grants of dropped tables: revoke them first, so that a view created later with the same
+	// name is not readable by the grantees of this one.
+	userNames, roleNames, err := grantees(ctx, state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting column masking policy",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+	for _, userName := range userNames {
+		err = r.revoke(ctx, state, &userName, nil)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error revoking column masking policy",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+	}
+	for _, roleName := range roleNames {
+		err = r.revoke(ctx, state, nil, &roleName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error revoking column masking policy",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+	}
+
+	err = r.client.DeleteView(ctx, state.UUID.ValueString(), state.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting column masking policy",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+}
+
+// grant grants SELECT on the view to the user or the role, and revokes SELECT on the table from it unless the policy
+// keeps the table access.
+func (r *Resource) grant(ctx context.Context, policy ColumnMaskingPolicy, userName *string, roleName *string) error {
+	databaseName := policy.DatabaseName.ValueString()
+	viewName := policy.ViewName.ValueString()
+	clusterName := policy.ClusterName.ValueStringPointer()
+
+	_, err := r.client.GrantPrivilege(ctx, dbops.GrantPrivilege{
+		AccessType:      selectPrivilege,
+		DatabaseName:    &databaseName,
+		TableName:       &viewName,
+		GranteeUserName: userName,
+		GranteeRoleName: roleName,
+	}, clusterName)
+	if err != nil {
+		return errors.WithMessage(err, "cannot grant SELECT on the view")
+	}
+
+	if !policy.RevokeTableAccess.ValueBool() {
+		return nil
+	}
+
+	tableName := policy.TableName.ValueString()
+	err = r.client.RevokeGrantPrivilege(ctx, selectPrivilege, &databaseName, &tableName, nil, userName, roleName, clusterName)
+	if err != nil {
+		return errors.WithMessage(err, "cannot revoke SELECT on the table")
+	}
+
+	return nil
+}
+
+// revoke revokes SELECT on the view from the user or the role.
+func (r *Resource) revoke(ctx context.Context, policy ColumnMaskingPolicy, userName *string, roleName *string) error {
+	databaseName := policy.DatabaseName.ValueString()
+	viewName := policy.ViewName.ValueString()
+
+	err := r.client.RevokeGrantPrivilege(ctx, selectPrivilege, &databaseName, &viewName, nil, userName, roleName, policy.ClusterName.ValueStringPointer())
+	if err != nil {
+		return errors.WithMessage(err, "cannot revoke SELECT on the view")
+	}
+
+	return nil
+}
+
+// maskedQuery returns the SELECT query of the view of the policy.
+func maskedQuery(ctx context.Context, policy ColumnMaskingPolicy) (string, error) {
+	masks := make(map[string]querybuilder.ColumnMask, len(policy.MaskedColumns))
+	for columnName, column := range policy.MaskedColumns {
+		masks[columnName] = querybuilder.ColumnMask{
+			Method:     column.Method.ValueString(),
+			Expression: column.Expression.ValueString(),
+		}
+	}
+
+	var excluded []string
+	if !policy.ExcludedColumns.IsNull() {
+		diags := policy.ExcludedColumns.ElementsAs(ctx, &excluded, false)
+		if diags.HasError() {
+			return "", errors.New("cannot read the excluded columns")
+		}
+	}
+
+	return querybuilder.NewMaskedSelect(policy.DatabaseName.ValueString(), policy.TableName.ValueString()).
+		WithMaskedColumns(masks).
+		WithExcludedColumns(excluded).
+		Build()
+}
+
+// grantees returns the names of the users and the roles of the policy.
+func grantees(ctx context.Context, policy ColumnMaskingPolicy) ([]string, []string, error) {
+	var userNames, roleNames []string
+	if !policy.GranteeUserNames.IsNull() {
+		diags := policy.GranteeUserNames.ElementsAs(ctx, &userNames, false)
+		if diags.HasError() {
+			return nil, nil, errors.New("cannot read the grantee users")
+		}
+	}
+	if !policy.GranteeRoleNames.IsNull() {
+		diags := policy.GranteeRoleNames.ElementsAs(ctx, &roleNames, false)
+		if diags.HasError() {
+			return nil, nil, errors.New("cannot read the grantee roles")
+		}
+	}
+
+	return userNames, roleNames, nil
+}
+
+// difference returns the names of a missing from b.
+func difference(a []string, b []string) []string {
+	ret := make([]string, 0)
+	for _, name := range a {
+		found := false
+		for _, other := range b {
+			if name == other {
+				found = true
+				break
+			}
+		}
+		if !found {
+			ret = append(ret, name)
+		}
+	}
+
+	return ret
+}
@@ -0,0 +1,24 @@
+package columnmaskingpolicy
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type ColumnMaskingPolicy struct {
+	ClusterName       types.String            `tfsdk:"cluster_name"`
+	UUID              types.String            `tfsdk:"uuid"`
+	DatabaseName      types.String            `tfsdk:"database_name"`
+	TableName         types.String            `tfsdk:"table_name"`
+	ViewName          types.String            `tfsdk:"view_name"`
+	MaskedColumns     map[string]MaskedColumn `tfsdk:"masked_columns"`
+	ExcludedColumns   types.Set               `tfsdk:"excluded_columns"`
+	GranteeUserNames  types.Set               `tfsdk:"grantee_user_names"`
+	GranteeRoleNames  types.Set               `tfsdk:"grantee_role_names"`
+	RevokeTableAccess types.Bool              `tfsdk:"revoke_table_access"`
+	Query             types.String            `tfsdk:"query"`
+}
+
+type MaskedColumn struct {
+	Method     types.String `tfsdk:"method"`
+	Expression types.String `tfsdk:"expression"`
+}
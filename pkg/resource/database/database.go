@@ -7,17 +7,24 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/pingcap/errors"
 
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clustername"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/comment"
 	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/protecteddatabase"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/schemadiff"
 )
 
 //go:embed database.md
@@ -25,9 +32,11 @@ var databaseResourceDescription string
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &Resource{}
-	_ resource.ResourceWithConfigure   = &Resource{}
-	_ resource.ResourceWithImportState = &Resource{}
+	_ resource.Resource                   = &Resource{}
+	_ resource.ResourceWithConfigure      = &Resource{}
+	_ resource.ResourceWithImportState    = &Resource{}
+	_ resource.ResourceWithModifyPlan     = &Resource{}
+	_ resource.ResourceWithValidateConfig = &Resource{}
 )
 
 // NewResource is a helper function to simplify the provider implementation.
@@ -74,18 +83,154 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 					// If user specifies the comment field, it can't be the empty string otherwise we get an error from terraform
 					// due to the difference between null and empty string. User can always set this field to null or leave it out completely.
 					stringvalidator.LengthAtLeast(1),
-					stringvalidator.LengthAtMost(255),
 				},
 				PlanModifiers: []planmodifier.String{
 					// Changing comment is not implemented: https://github.com/ClickHouse/ClickHouse/issues/73351
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"engine": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Engine of the database with its parameters, e.g. `Atomic`, `Replicated('/clickhouse/databases/analytics', '{shard}', '{replica}')`, `Lazy(3600)` or `MaterializedPostgreSQL(postgres_creds)` to replicate a PostgreSQL database using a named collection. Defaults to the server default, usually `Atomic`. Read back from ClickHouse to detect drift, the parameters of engines other than `Replicated` and `Lazy` aren't compared as ClickHouse hides the credentials they may hold. Changing it recreates the database.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					engineValidator{},
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"materialized_postgresql": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "Settings of a MaterializedPostgreSQL `engine`. `max_block_size` and `allow_automatic_update` are changed in place, as are the tables of `tables_list`, which are attached or permanently detached. Changing the other settings, or adding or removing `tables_list`, recreates the database.",
+				Attributes: map[string]schema.Attribute{
+					"tables_list": schema.ListAttribute{
+						ElementType: types.StringType,
+						Optional:    true,
+						Description: "PostgreSQL tables to replicate, all of them when null.",
+						PlanModifiers: []planmodifier.List{
+							listplanmodifier.RequiresReplaceIf(requiresReplaceIfNullChanges, "Adding or removing tables_list recreates the database", "Adding or removing `tables_list` recreates the database"),
+						},
+					},
+					"tables_list_with_schema": schema.BoolAttribute{
+						Optional:    true,
+						Description: "When true, `tables_list` entries are `schema.table` and tables are named the same way in ClickHouse.",
+						PlanModifiers: []planmodifier.Bool{
+							boolplanmodifier.RequiresReplace(),
+						},
+					},
+					"schema": schema.StringAttribute{
+						Optional:    true,
+						Description: "PostgreSQL schema to replicate the tables of.",
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.RequiresReplace(),
+						},
+					},
+					"schema_list": schema.ListAttribute{
+						ElementType: types.StringType,
+						Optional:    true,
+						Description: "PostgreSQL schemas to replicate the tables of, tables are named `schema.table` in ClickHouse.",
+						PlanModifiers: []planmodifier.List{
+							listplanmodifier.RequiresReplace(),
+						},
+					},
+					"replication_slot": schema.StringAttribute{
+						Optional:    true,
+						Description: "Replication slot created beforehand in PostgreSQL, to be used along with `snapshot`.",
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.RequiresReplace(),
+						},
+					},
+					"snapshot": schema.StringAttribute{
+						Optional:    true,
+						Description: "Snapshot of `replication_slot` the initial dump of the tables is read from.",
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.RequiresReplace(),
+						},
+					},
+					"max_block_size": schema.Int64Attribute{
+						Optional:    true,
+						Description: "Number of rows collected in memory before flushing data into the tables. Defaults to 65536.",
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"allow_automatic_update": schema.BoolAttribute{
+						Optional:    true,
+						Description: "When true, tables are reloaded in the background when their schema changes in PostgreSQL. Defaults to false.",
+					},
+				},
+			},
+			"create_statement": schema.StringAttribute{
+				Computed:    true,
+				Description: "The CREATE DATABASE statement the provider runs to create the database, rendered during plan unless the configuration depends on values only known during apply, so that it can be reviewed along with the plan.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 		},
 		MarkdownDescription: databaseResourceDescription,
 	}
 }
 
+func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_database", "plan")
+
+	clustername.ValidatePlan(ctx, r.client, req.Plan, &resp.Diagnostics)
+
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var planName, planComment types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("name"), &planName)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("comment"), &planComment)...)
+	protecteddatabase.Validate(r.client, path.Root("name"), planName, &resp.Diagnostics)
+	comment.Validate(r.client, path.Root("comment"), planComment, &resp.Diagnostics)
+
+	if req.State.Raw.IsNull() && req.Config.Raw.IsFullyKnown() && !resp.Diagnostics.HasError() {
+		// Render the statement Create will run.
+		var plan Database
+		resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		createStatement, err := createStatement(ctx, plan)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error rendering database",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("create_statement"), createStatement)...)
+	}
+}
+
+func (r *Resource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config Database
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.MaterializedPostgreSQL == nil || config.Engine.IsUnknown() {
+		return
+	}
+
+	if !isMaterializedPostgreSQL(config.Engine.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("materialized_postgresql"),
+			"Invalid Database Settings",
+			"'materialized_postgresql' can only be set when 'engine' is MaterializedPostgreSQL(...)",
+		)
+	}
+}
+
 func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -95,6 +240,8 @@ func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _
 }
 
 func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_database", "create")
+
 	var plan Database
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -102,7 +249,25 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 		return
 	}
 
-	db, err := r.client.CreateDatabase(ctx, dbops.Database{Name: plan.Name.ValueString(), Comment: plan.Comment.ValueString()}, plan.ClusterName.ValueStringPointer())
+	database, err := databaseFromPlan(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating database",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	createStatement, err := createStatement(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating database",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	db, err := r.client.CreateDatabase(ctx, *database, plan.ClusterName.ValueStringPointer())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating database",
@@ -111,7 +276,7 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 		return
 	}
 
-	state, err := r.syncDatabaseState(ctx, db.UUID, plan.ClusterName.ValueStringPointer())
+	state, err := r.syncDatabaseState(ctx, db.UUID, plan.ClusterName.ValueStringPointer(), plan.Engine)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error syncing database",
@@ -128,6 +293,9 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 		return
 	}
 
+	state.MaterializedPostgreSQL = plan.MaterializedPostgreSQL
+	state.CreateStatement = types.StringValue(createStatement)
+
 	diags = resp.State.Set(ctx, state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -136,6 +304,8 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 }
 
 func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_database", "read")
+
 	var plan Database
 	diags := req.State.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -143,7 +313,14 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 		return
 	}
 
-	state, err := r.syncDatabaseState(ctx, plan.UUID.ValueString(), plan.ClusterName.ValueStringPointer())
+	state, err := r.syncDatabaseState(ctx, plan.UUID.ValueString(), plan.ClusterName.ValueStringPointer(), plan.Engine)
+	if dbops.IsRestrictedRead(err) {
+		resp.Diagnostics.AddWarning(
+			"Unable to Refresh ClickHouse Database",
+			"Not allowed to read the database, keeping the prior state: "+err.Error(),
+		)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error syncing database",
@@ -155,6 +332,9 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 	if state == nil {
 		resp.State.RemoveResource(ctx)
 	} else {
+		state.MaterializedPostgreSQL = plan.MaterializedPostgreSQL
+		state.CreateStatement = plan.CreateStatement
+
 		diags = resp.State.Set(ctx, state)
 		resp.Diagnostics.Append(diags...)
 		if resp.Diagnostics.HasError() {
@@ -164,10 +344,36 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 }
 
 func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	panic("unsupported")
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_database", "update")
+
+	// Only the materialized_postgresql settings can change without replacing the database.
+	var plan, state Database
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.updateMaterializedPostgreSQL(ctx, state.UUID.ValueString(), state.MaterializedPostgreSQL, plan.MaterializedPostgreSQL, plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating database",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	state.MaterializedPostgreSQL = plan.MaterializedPostgreSQL
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
 }
 
 func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_database", "delete")
+
 	var plan Database
 	diags := req.State.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -186,6 +392,8 @@ func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp
 }
 
 func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_database", "import")
+
 	// req.ID can either be in the form <cluster name>:<database ref> or just <database ref>
 	// database ref can either be the name or the UUID of the database.
 
@@ -221,8 +429,9 @@ func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequ
 	}
 }
 
-// syncDatabaseState reads database settings from clickhouse and returns a DatabaseResourceModel
-func (r *Resource) syncDatabaseState(ctx context.Context, uuid string, clusterName *string) (*Database, error) {
+// syncDatabaseState reads database settings from clickhouse and returns a DatabaseResourceModel, keeping the planned
+// engine when ClickHouse reports the same one.
+func (r *Resource) syncDatabaseState(ctx context.Context, uuid string, clusterName *string, plannedEngine types.String) (*Database, error) {
 	db, err := r.client.GetDatabase(ctx, uuid, clusterName)
 	if err != nil {
 		return nil, errors.WithMessage(err, "cannot get database")
@@ -243,7 +452,36 @@ func (r *Resource) syncDatabaseState(ctx context.Context, uuid string, clusterNa
 		UUID:        types.StringValue(db.UUID),
 		Name:        types.StringValue(db.Name),
 		Comment:     comment,
+		Engine:      schemadiff.KeepPlanned(plannedEngine, &db.Engine, sameDatabaseEngine),
 	}
 
 	return state, nil
 }
+
+// databaseFromPlan returns the database to create for the plan.
+func databaseFromPlan(ctx context.Context, plan Database) (*dbops.Database, error) {
+	database := &dbops.Database{
+		Name:    plan.Name.ValueString(),
+		Comment: plan.Comment.ValueString(),
+		Engine:  plan.Engine.ValueString(),
+	}
+	if plan.MaterializedPostgreSQL != nil {
+		settings, err := plan.MaterializedPostgreSQL.settings(ctx)
+		if err != nil {
+			return nil, err
+		}
+		database.Settings = settings.Settings()
+	}
+
+	return database, nil
+}
+
+// createStatement returns the CREATE DATABASE statement Create runs for the plan.
+func createStatement(ctx context.Context, plan Database) (string, error) {
+	database, err := databaseFromPlan(ctx, plan)
+	if err != nil {
+		return "", err
+	}
+
+	return dbops.CreateDatabaseStatement(*database, plan.ClusterName.ValueStringPointer())
+}
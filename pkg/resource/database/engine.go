@@ -0,0 +1,147 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/schemadiff"
+)
+
+// sharedDatabaseEngines are the engines ClickHouse Cloud replaces with its Shared database engine.
+var sharedDatabaseEngines = []string{"Atomic", "Replicated"}
+
+// checkDatabaseEngine returns an error describing why the parameters of an Atomic, Replicated, Lazy or
+// MaterializedPostgreSQL engine are invalid, or nil. Other engines are left to ClickHouse.
+func checkDatabaseEngine(engine string) error {
+	name := schemadiff.EngineName(engine)
+	args, hasArgs := engineArguments(engine)
+
+	switch name {
+	case "Atomic":
+		if hasArgs && len(args) > 0 {
+			return fmt.Errorf("Atomic takes no parameters")
+		}
+	case "Replicated":
+		// The parameters can be left out when the server sets default_replica_path and default_replica_name.
+		if len(args) > 3 {
+			return fmt.Errorf("Replicated takes the ZooKeeper path of the database, and optionally the shard and replica names, e.g. Replicated('/clickhouse/databases/analytics', '{shard}', '{replica}')")
+		}
+		for _, arg := range args {
+			if !isStringLiteral(arg) {
+				return fmt.Errorf("the parameters of Replicated must be quoted strings, got %s", arg)
+			}
+		}
+	case "Lazy":
+		if len(args) != 1 {
+			return fmt.Errorf("Lazy takes the number of seconds tables are kept in memory after their last access, e.g. Lazy(3600)")
+		}
+		if seconds, err := strconv.ParseUint(args[0], 10, 64); err != nil || seconds == 0 {
+			return fmt.Errorf("the expiration time of Lazy must be a positive number of seconds, got %s", args[0])
+		}
+	case "MaterializedPostgreSQL":
+		if len(args) == 0 {
+			return fmt.Errorf("MaterializedPostgreSQL takes a named collection, or the host:port, database, user and password of the PostgreSQL server, e.g. MaterializedPostgreSQL('postgres:5432', 'db', 'user', 'password')")
+		}
+	}
+
+	return nil
+}
+
+// sameDatabaseEngine reports whether the engine ClickHouse reports is the configured one. The parameters of Replicated
+// and Lazy engines are compared, leaving out the ones ClickHouse fills in when left out of the configuration. The
+// parameters of the other engines are not, as ClickHouse hides the credentials they may hold.
+func sameDatabaseEngine(planned string, actual string) bool {
+	plannedName, actualName := schemadiff.EngineName(planned), schemadiff.EngineName(actual)
+	if actualName == "Shared" {
+		return plannedName == actualName || slices.Contains(sharedDatabaseEngines, plannedName)
+	}
+	if plannedName != actualName {
+		return false
+	}
+	if plannedName != "Replicated" && plannedName != "Lazy" {
+		return true
+	}
+
+	plannedArgs, _ := engineArguments(planned)
+	actualArgs, _ := engineArguments(actual)
+	if len(plannedArgs) > len(actualArgs) {
+		return false
+	}
+	for i, arg := range plannedArgs {
+		if !schemadiff.SameExpression(arg, actualArgs[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// engineArguments returns the parameters between the parentheses following the engine name, and whether there are
+// parentheses. Anything after them, e.g. SETTINGS, is ignored.
+func engineArguments(engine string) ([]string, bool) {
+	start := strings.Index(engine, "(")
+	if start == -1 {
+		return nil, false
+	}
+
+	depth := 0
+	var quote rune
+	for i, c := range engine[start:] {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				args := strings.TrimSpace(engine[start+1 : start+i])
+				if args == "" {
+					return nil, true
+				}
+				return schemadiff.SplitTopLevel(args), true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+func isStringLiteral(s string) bool {
+	return len(s) > 1 && strings.HasPrefix(s, "'") && strings.HasSuffix(s, "'")
+}
+
+// engineValidator rejects malformed Atomic, Replicated, Lazy and MaterializedPostgreSQL engines at plan time, rather
+// than when creating the database.
+type engineValidator struct{}
+
+func (v engineValidator) Description(_ context.Context) string {
+	return "Checks the parameters of Atomic, Replicated, Lazy and MaterializedPostgreSQL engines"
+}
+
+func (v engineValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v engineValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if err := checkDatabaseEngine(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Database Engine",
+			err.Error(),
+		)
+	}
+}
@@ -0,0 +1,55 @@
+package database
+
+import "testing"
+
+func Test_checkDatabaseEngine(t *testing.T) {
+	tests := []struct {
+		name    string
+		engine  string
+		wantErr bool
+	}{
+		{name: "Atomic", engine: "Atomic", wantErr: false},
+		{name: "Atomic with parameters", engine: "Atomic('x')", wantErr: true},
+		{name: "Replicated", engine: "Replicated('/clickhouse/databases/db', '{shard}', '{replica}')", wantErr: false},
+		{name: "Replicated with server defaults", engine: "Replicated", wantErr: false},
+		{name: "Replicated with unquoted path", engine: "Replicated(/clickhouse/databases/db)", wantErr: true},
+		{name: "Replicated with too many parameters", engine: "Replicated('/p', 's', 'r', 'x')", wantErr: true},
+		{name: "Lazy", engine: "Lazy(3600)", wantErr: false},
+		{name: "Lazy without expiration", engine: "Lazy", wantErr: true},
+		{name: "Lazy with invalid expiration", engine: "Lazy(-1)", wantErr: true},
+		{name: "MaterializedPostgreSQL", engine: "MaterializedPostgreSQL(postgres_creds)", wantErr: false},
+		{name: "MaterializedPostgreSQL without parameters", engine: "MaterializedPostgreSQL", wantErr: true},
+		{name: "Other engine", engine: "MySQL('host:3306', 'db', 'user', 'password')", wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkDatabaseEngine(tt.engine); (err != nil) != tt.wantErr {
+				t.Errorf("checkDatabaseEngine() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_sameDatabaseEngine(t *testing.T) {
+	tests := []struct {
+		name    string
+		planned string
+		actual  string
+		want    bool
+	}{
+		{name: "Same engine", planned: "Atomic", actual: "Atomic", want: true},
+		{name: "Different engine", planned: "Atomic", actual: "Ordinary", want: false},
+		{name: "ClickHouse Cloud", planned: "Replicated('/p')", actual: "Shared", want: true},
+		{name: "Replicated defaults filled in", planned: "Replicated('/p')", actual: "Replicated('/p', '{shard}', '{replica}')", want: true},
+		{name: "Replicated path changed", planned: "Replicated('/p')", actual: "Replicated('/q', '{shard}', '{replica}')", want: false},
+		{name: "Lazy expiration changed", planned: "Lazy(60)", actual: "Lazy(3600)", want: false},
+		{name: "Hidden credentials", planned: "MaterializedPostgreSQL('pg:5432', 'db', 'user', 'secret')", actual: "MaterializedPostgreSQL('pg:5432', 'db', 'user', '[HIDDEN]')", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameDatabaseEngine(tt.planned, tt.actual); got != tt.want {
+				t.Errorf("sameDatabaseEngine(%q, %q) = %v, want %v", tt.planned, tt.actual, got, tt.want)
+			}
+		})
+	}
+}
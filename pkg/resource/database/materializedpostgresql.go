@@ -0,0 +1,127 @@
+package database
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// Server defaults the in-place settings are reset to when removed from the configuration.
+const (
+	defaultMaterializedPostgreSQLMaxBlockSize         = int64(65536)
+	defaultMaterializedPostgreSQLAllowAutomaticUpdate = false
+)
+
+func isMaterializedPostgreSQL(engine string) bool {
+	return strings.HasPrefix(strings.TrimSpace(engine), "MaterializedPostgreSQL")
+}
+
+// settings returns the engine settings set in the block.
+func (m *MaterializedPostgreSQL) settings(ctx context.Context) (querybuilder.MaterializedPostgreSQLSettings, error) {
+	settings := querybuilder.MaterializedPostgreSQLSettings{
+		TablesListWithSchema: m.TablesListWithSchema.ValueBoolPointer(),
+		Schema:               m.Schema.ValueStringPointer(),
+		ReplicationSlot:      m.ReplicationSlot.ValueStringPointer(),
+		Snapshot:             m.Snapshot.ValueStringPointer(),
+		MaxBlockSize:         m.MaxBlockSize.ValueInt64Pointer(),
+		AllowAutomaticUpdate: m.AllowAutomaticUpdate.ValueBoolPointer(),
+	}
+
+	if !m.TablesList.IsNull() {
+		settings.TablesList = make([]string, 0)
+		if diags := m.TablesList.ElementsAs(ctx, &settings.TablesList, false); diags.HasError() {
+			return settings, errors.New("failed to parse tables_list")
+		}
+	}
+	if !m.SchemaList.IsNull() {
+		settings.SchemaList = make([]string, 0)
+		if diags := m.SchemaList.ElementsAs(ctx, &settings.SchemaList, false); diags.HasError() {
+			return settings, errors.New("failed to parse schema_list")
+		}
+	}
+
+	return settings, nil
+}
+
+// updateMaterializedPostgreSQL applies the changes between the from and to blocks that don't require a replacement:
+// max_block_size and allow_automatic_update are changed with MODIFY SETTING, tables added to or removed from
+// tables_list are attached or permanently detached.
+func (r *Resource) updateMaterializedPostgreSQL(ctx context.Context, uuid string, from *MaterializedPostgreSQL, to *MaterializedPostgreSQL, clusterName *string) error {
+	var before, after querybuilder.MaterializedPostgreSQLSettings
+	var err error
+	if from != nil {
+		if before, err = from.settings(ctx); err != nil {
+			return err
+		}
+	}
+	if to != nil {
+		if after, err = to.settings(ctx); err != nil {
+			return err
+		}
+	}
+
+	var changed querybuilder.MaterializedPostgreSQLSettings
+	if !equalPtr(before.MaxBlockSize, after.MaxBlockSize) {
+		changed.MaxBlockSize = after.MaxBlockSize
+		if changed.MaxBlockSize == nil {
+			maxBlockSize := defaultMaterializedPostgreSQLMaxBlockSize
+			changed.MaxBlockSize = &maxBlockSize
+		}
+	}
+	if !equalPtr(before.AllowAutomaticUpdate, after.AllowAutomaticUpdate) {
+		changed.AllowAutomaticUpdate = after.AllowAutomaticUpdate
+		if changed.AllowAutomaticUpdate == nil {
+			allowAutomaticUpdate := defaultMaterializedPostgreSQLAllowAutomaticUpdate
+			changed.AllowAutomaticUpdate = &allowAutomaticUpdate
+		}
+	}
+	if settings := changed.Settings(); len(settings) > 0 {
+		if err := r.client.UpdateDatabaseSettings(ctx, uuid, settings, clusterName); err != nil {
+			return err
+		}
+	}
+
+	var attach, detach []string
+	for _, table := range after.TablesList {
+		if !slices.Contains(before.TablesList, table) {
+			attach = append(attach, table)
+		}
+	}
+	for _, table := range before.TablesList {
+		if !slices.Contains(after.TablesList, table) {
+			detach = append(detach, table)
+		}
+	}
+	if len(attach) > 0 {
+		if err := r.client.AttachDatabaseTables(ctx, uuid, attach, clusterName); err != nil {
+			return err
+		}
+	}
+	if len(detach) > 0 {
+		if err := r.client.DetachDatabaseTables(ctx, uuid, detach, clusterName); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func equalPtr[T comparable](a *T, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// requiresReplaceIfNullChanges replaces the database when a list setting is added or removed: a null tables_list
+// replicates every table, which can't be changed in place, while the tables of a set list can be.
+func requiresReplaceIfNullChanges(_ context.Context, req planmodifier.ListRequest, resp *listplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = req.StateValue.IsNull() != req.PlanValue.IsNull()
+}
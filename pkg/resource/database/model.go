@@ -5,8 +5,22 @@ import (
 )
 
 type Database struct {
-	ClusterName types.String `tfsdk:"cluster_name"`
-	UUID        types.String `tfsdk:"uuid"`
-	Name        types.String `tfsdk:"name"`
-	Comment     types.String `tfsdk:"comment"`
+	ClusterName            types.String            `tfsdk:"cluster_name"`
+	UUID                   types.String            `tfsdk:"uuid"`
+	Name                   types.String            `tfsdk:"name"`
+	Comment                types.String            `tfsdk:"comment"`
+	Engine                 types.String            `tfsdk:"engine"`
+	MaterializedPostgreSQL *MaterializedPostgreSQL `tfsdk:"materialized_postgresql"`
+	CreateStatement        types.String            `tfsdk:"create_statement"`
+}
+
+type MaterializedPostgreSQL struct {
+	TablesList           types.List   `tfsdk:"tables_list"`
+	TablesListWithSchema types.Bool   `tfsdk:"tables_list_with_schema"`
+	Schema               types.String `tfsdk:"schema"`
+	SchemaList           types.List   `tfsdk:"schema_list"`
+	ReplicationSlot      types.String `tfsdk:"replication_slot"`
+	Snapshot             types.String `tfsdk:"snapshot"`
+	MaxBlockSize         types.Int64  `tfsdk:"max_block_size"`
+	AllowAutomaticUpdate types.Bool   `tfsdk:"allow_automatic_update"`
 }
@@ -0,0 +1,259 @@
+package detachedpartsretention
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clustername"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/protecteddatabase"
+)
+
+//go:embed detachedpartsretention.md
+var detachedPartsRetentionResourceDescription string
+
+var (
+	_ resource.Resource               = &Resource{}
+	_ resource.ResourceWithConfigure  = &Resource{}
+	_ resource.ResourceWithModifyPlan = &Resource{}
+)
+
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+type Resource struct {
+	client dbops.Client
+}
+
+func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_detached_parts_retention"
+}
+
+func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster the table lives in. Detached parts are local to each replica: with a cluster, every replica is checked and cleaned up. If omitted, only the replica hit by the query is.\nThis field must be left null when using a ClickHouse Cloud cluster.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Random identifier of the resource",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"database_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the database containing the table",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"table_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the table to delete detached parts of",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"older_than": schema.StringAttribute{
+				Required:    true,
+				Description: "SQL expression returning a DateTime. Partitions whose detached parts were all modified before this are deleted, e.g. `now() - INTERVAL 30 DAY`. The expression is embedded in the query as raw SQL: it must be a single expression, without statement separators or comments",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"dry_run": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "When true, partitions are listed in `partitions` but their detached parts are not deleted. Defaults to false",
+			},
+			"partitions": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "IDs of the partitions whose detached parts were deleted by the last apply, or would have been when `dry_run` is true. During plan, the partitions whose detached parts will be deleted",
+			},
+		},
+		MarkdownDescription: detachedPartsRetentionResourceDescription,
+	}
+}
+
+func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_detached_parts_retention", "plan")
+
+	if req.Plan.Raw.IsNull() {
+		// If the entire plan is null, the resource is planned for destruction.
+		return
+	}
+
+	clustername.ValidatePlan(ctx, r.client, req.Plan, &resp.Diagnostics)
+
+	var plan DetachedPartsRetention
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	protecteddatabase.Validate(r.client, path.Root("database_name"), plan.DatabaseName, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.client == nil || plan.ClusterName.IsUnknown() || plan.DatabaseName.IsUnknown() || plan.TableName.IsUnknown() || plan.OlderThan.IsUnknown() {
+		// Partitions are computed during apply.
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("partitions"), types.ListUnknown(types.StringType))...)
+		return
+	}
+
+	expired, err := r.client.GetExpiredDetachedPartitions(ctx, plan.DatabaseName.ValueString(), plan.TableName.ValueString(), plan.OlderThan.ValueString(), plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Detached Parts",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	partitions, diags := types.ListValueFrom(ctx, types.StringType, expired)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if len(expired) == 0 && !req.State.Raw.IsNull() {
+		var state DetachedPartsRetention
+		diags = req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// Nothing to drop, keep the result of the previous apply to avoid a diff. Only when the configuration is
+		// unchanged: Update would drop the partitions in the plan, which were computed against the previous one.
+		if plan.OlderThan.Equal(state.OlderThan) && plan.DryRun.Equal(state.DryRun) {
+			partitions = state.Partitions
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("partitions"), partitions)...)
+}
+
+func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(dbops.Client)
+}
+
+func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_detached_parts_retention", "create")
+
+	var plan DetachedPartsRetention
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = types.StringValue(uuid.NewString())
+
+	r.apply(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Nothing to read, detached parts are looked up during plan.
+}
+
+func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_detached_parts_retention", "update")
+
+	var plan DetachedPartsRetention
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.apply(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Nothing to undo on the ClickHouse side.
+}
+
+// apply deletes the detached parts of the partitions listed in the plan, looking them up first if they were not known at plan time.
+func (r *Resource) apply(ctx context.Context, plan *DetachedPartsRetention, diagnostics *diag.Diagnostics) {
+	var partitions []string
+
+	if plan.Partitions.IsUnknown() {
+		expired, err := r.client.GetExpiredDetachedPartitions(ctx, plan.DatabaseName.ValueString(), plan.TableName.ValueString(), plan.OlderThan.ValueString(), plan.ClusterName.ValueStringPointer())
+		if err != nil {
+			diagnostics.AddError(
+				"Error Reading ClickHouse Detached Parts",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+
+		partitions = expired
+
+		list, diags := types.ListValueFrom(ctx, types.StringType, partitions)
+		diagnostics.Append(diags...)
+		if diagnostics.HasError() {
+			return
+		}
+		plan.Partitions = list
+	} else {
+		diags := plan.Partitions.ElementsAs(ctx, &partitions, false)
+		diagnostics.Append(diags...)
+		if diagnostics.HasError() {
+			return
+		}
+	}
+
+	if plan.DryRun.ValueBool() || len(partitions) == 0 {
+		return
+	}
+
+	err := r.client.DropDetachedPartitions(ctx, plan.DatabaseName.ValueString(), plan.TableName.ValueString(), partitions, plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		diagnostics.AddError(
+			"Error Dropping ClickHouse Detached Parts",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+}
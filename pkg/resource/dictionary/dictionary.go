@@ -0,0 +1,667 @@
+package dictionary
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clustername"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/comment"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/protecteddatabase"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/schemadiff"
+)
+
+//go:embed dictionary.md
+var dictionaryResourceDescription string
+
+var layoutTypeRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &Resource{}
+	_ resource.ResourceWithConfigure      = &Resource{}
+	_ resource.ResourceWithImportState    = &Resource{}
+	_ resource.ResourceWithModifyPlan     = &Resource{}
+	_ resource.ResourceWithValidateConfig = &Resource{}
+)
+
+// NewResource is a helper function to simplify the provider implementation.
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+// Resource is the resource implementation.
+type Resource struct {
+	client dbops.Client
+}
+
+// Metadata returns the resource type name.
+func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dictionary"
+}
+
+// Schema defines the schema for the resource.
+func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to create the dictionary into. If omitted, the dictionary will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nShould be set when hitting a cluster with more than one replica.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"uuid": schema.StringAttribute{
+				Computed:    true,
+				Description: "The system-assigned UUID for the dictionary",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"database_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the database to create the dictionary into",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the dictionary",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"attributes": schema.ListNestedAttribute{
+				Required:    true,
+				Description: "Columns of the dictionary, the columns of `primary_key` included",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Required:    true,
+							Description: "Attribute name",
+						},
+						"type": schema.StringAttribute{
+							Required:    true,
+							Description: "Attribute data type (e.g., UInt64, String)",
+						},
+						"default": schema.StringAttribute{
+							Optional:    true,
+							Description: "Value of the attribute for keys missing from the dictionary, e.g. `''`",
+						},
+						"expression": schema.StringAttribute{
+							Optional:    true,
+							Description: "Expression computing the attribute from the columns of the source",
+						},
+						"hierarchical": schema.BoolAttribute{
+							Optional:    true,
+							Description: "When true, the attribute holds the key of the parent, for hierarchical dictionaries",
+						},
+						"injective": schema.BoolAttribute{
+							Optional:    true,
+							Description: "When true, different keys map to different values of the attribute, which lets ClickHouse optimize GROUP BY",
+						},
+					},
+				},
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"primary_key": schema.ListAttribute{
+				ElementType: types.StringType,
+				Required:    true,
+				Description: "Names of the attributes the dictionary is looked up with",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"source": schema.SingleNestedAttribute{
+				Required:    true,
+				Description: "Where the data of the dictionary is loaded from. It is not read back from ClickHouse.",
+				Attributes: map[string]schema.Attribute{
+					"type": schema.StringAttribute{
+						Required:    true,
+						Description: "Type of the source, one of `clickhouse`, `http`, `mysql` and `postgresql`",
+						Validators: []validator.String{
+							stringvalidator.OneOf("clickhouse", "http", "mysql", "postgresql"),
+						},
+					},
+					"parameters": schema.MapAttribute{
+						ElementType: types.StringType,
+						Optional:    true,
+						Description: "Parameters of the source, e.g. `host`, `port`, `db` and `table` or `url` and `format`",
+					},
+					"password": schema.StringAttribute{
+						Optional:    true,
+						Sensitive:   true,
+						Description: "Password of the source",
+					},
+				},
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplaceIf(requiresReplaceUnlessImportedObject, "Changing the source recreates the dictionary", "Changing the `source` recreates the dictionary"),
+				},
+			},
+			"layout": schema.SingleNestedAttribute{
+				Required:    true,
+				Description: "How the dictionary is stored in memory",
+				Attributes: map[string]schema.Attribute{
+					"type": schema.StringAttribute{
+						Required:    true,
+						Description: "Type of the layout, e.g. `flat`, `hashed`, `cache` or `complex_key_hashed`",
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(layoutTypeRegexp, "must be a lower case layout name, e.g. complex_key_hashed"),
+						},
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.RequiresReplace(),
+						},
+					},
+					"parameters": schema.MapAttribute{
+						ElementType: types.StringType,
+						Optional:    true,
+						Description: "Parameters of the layout, e.g. `size_in_cells` for `cache`. They are not read back from ClickHouse.",
+						PlanModifiers: []planmodifier.Map{
+							mapplanmodifier.RequiresReplaceIf(requiresReplaceUnlessImportedMap, "Changing the layout parameters recreates the dictionary", "Changing the layout `parameters` recreates the dictionary"),
+						},
+					},
+				},
+			},
+			"lifetime_min": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Minimum number of seconds between reloads of the dictionary. Defaults to `lifetime_max`.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+					int64validator.AlsoRequires(path.MatchRoot("lifetime_max")),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"lifetime_max": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of seconds between reloads of the dictionary. The dictionary is never reloaded when null.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"comment": schema.StringAttribute{
+				Optional:    true,
+				Description: "Comment associated with the dictionary",
+				Validators: []validator.String{
+					// If user specifies the comment field, it can't be the empty string otherwise we get an error from terraform
+					// due to the difference between null and empty string. User can always set this field to null or leave it out completely.
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+		MarkdownDescription: dictionaryResourceDescription,
+	}
+}
+
+// requiresReplaceUnlessImportedObject recreates the dictionary when the source changes, except when it was not known
+// yet because the dictionary was imported.
+func requiresReplaceUnlessImportedObject(_ context.Context, req planmodifier.ObjectRequest, resp *objectplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = !req.StateValue.IsNull()
+}
+
+// requiresReplaceUnlessImportedMap is requiresReplaceUnlessImportedObject for the layout parameters.
+func requiresReplaceUnlessImportedMap(_ context.Context, req planmodifier.MapRequest, resp *mapplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = !req.StateValue.IsNull()
+}
+
+func (r *Resource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config Dictionary
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.LifetimeMin.IsNull() && !config.LifetimeMin.IsUnknown() && !config.LifetimeMax.IsUnknown() &&
+		config.LifetimeMin.ValueInt64() > config.LifetimeMax.ValueInt64() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("lifetime_min"),
+			"Invalid Dictionary Lifetime",
+			"'lifetime_min' can't be greater than 'lifetime_max'.",
+		)
+	}
+
+	if config.Source != nil && !config.Source.Parameters.IsUnknown() {
+		for name := range config.Source.Parameters.Elements() {
+			if strings.EqualFold(name, "password") {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("source").AtName("parameters"),
+					"Invalid Dictionary Source",
+					"The password of the source must be set with the sensitive 'password' attribute.",
+				)
+			}
+		}
+	}
+
+	if config.PrimaryKey.IsUnknown() || config.Attributes == nil {
+		return
+	}
+
+	var primaryKey []string
+	resp.Diagnostics.Append(config.PrimaryKey.ElementsAs(ctx, &primaryKey, false)...)
+	for _, key := range primaryKey {
+		found := false
+		for _, attribute := range config.Attributes {
+			if attribute.Name.IsUnknown() || attribute.Name.ValueString() == key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("primary_key"),
+				"Invalid Dictionary Primary Key",
+				fmt.Sprintf("Primary key %q is not one of the 'attributes'.", key),
+			)
+		}
+	}
+}
+
+func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_dictionary", "plan")
+
+	clustername.ValidatePlan(ctx, r.client, req.Plan, &resp.Diagnostics)
+
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var planDatabaseName, planComment types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("database_name"), &planDatabaseName)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("comment"), &planComment)...)
+	protecteddatabase.Validate(r.client, path.Root("database_name"), planDatabaseName, &resp.Diagnostics)
+	comment.Validate(r.client, path.Root("comment"), planComment, &resp.Diagnostics)
+}
+
+func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(dbops.Client)
+}
+
+func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_dictionary", "create")
+
+	var plan Dictionary
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dictionary, diags := dictionaryFromPlan(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	created, err := r.client.CreateDictionary(ctx, dictionary, plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating dictionary",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	state, err := r.syncDictionaryState(ctx, created.UUID, plan.ClusterName.ValueStringPointer(), &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error syncing dictionary",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if state == nil {
+		resp.Diagnostics.AddError(
+			"Error syncing dictionary",
+			"failed retrieving dictionary after creation",
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_dictionary", "read")
+
+	var plan Dictionary
+	diags := req.State.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state, err := r.syncDictionaryState(ctx, plan.UUID.ValueString(), plan.ClusterName.ValueStringPointer(), &plan)
+	if dbops.IsRestrictedRead(err) {
+		resp.Diagnostics.AddWarning(
+			"Unable to Refresh ClickHouse Dictionary",
+			"Not allowed to read the dictionary, keeping the prior state: "+err.Error(),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error syncing dictionary",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if state == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update only stores the source and the layout parameters after an import, any other change recreates the dictionary.
+func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state Dictionary
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.Source = plan.Source
+	if state.Layout != nil && plan.Layout != nil {
+		state.Layout.Parameters = plan.Layout.Parameters
+	}
+
+	diags := resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_dictionary", "delete")
+
+	var plan Dictionary
+	diags := req.State.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteDictionary(ctx, plan.UUID.ValueString(), plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting dictionary",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+}
+
+func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_dictionary", "import")
+
+	// req.ID can either be in the form <cluster name>:<database name>:<dictionary ref> or just <database name>:<dictionary ref>
+	// dictionary ref can either be the name or the UUID of the dictionary.
+
+	parts := strings.Split(req.ID, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		resp.Diagnostics.AddError(
+			"Invalid import ID format",
+			"Import ID must be in format 'database_name:dictionary_name' or 'cluster_name:database_name:dictionary_name' or 'database_name:dictionary_uuid'",
+		)
+		return
+	}
+
+	var clusterName *string
+	var databaseName string
+	var dictionaryRef string
+
+	if len(parts) == 3 {
+		clusterName = &parts[0]
+		databaseName = parts[1]
+		dictionaryRef = parts[2]
+	} else {
+		databaseName = parts[0]
+		dictionaryRef = parts[1]
+	}
+
+	// Check if ref is a UUID
+	dictionaryUUID := dictionaryRef
+	_, err := uuid.Parse(dictionaryRef)
+	if err != nil {
+		// Failed parsing UUID, try importing using the dictionary name
+		dictionary, err := r.client.FindDictionaryByName(ctx, databaseName, dictionaryRef, clusterName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Cannot find dictionary",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+
+		dictionaryUUID = dictionary.UUID
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("uuid"), dictionaryUUID)...)
+	if clusterName != nil {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cluster_name"), clusterName)...)
+	}
+}
+
+// syncDictionaryState reads the dictionary from clickhouse and returns a Dictionary, keeping the values of plan
+// ClickHouse doesn't expose or only reformatted.
+func (r *Resource) syncDictionaryState(ctx context.Context, uuid string, clusterName *string, plan *Dictionary) (*Dictionary, error) {
+	dictionary, err := r.client.GetDictionary(ctx, uuid, clusterName)
+	if err != nil {
+		return nil, errors.WithMessage(err, "cannot get dictionary")
+	}
+
+	if dictionary == nil {
+		// Dictionary not found.
+		return nil, nil
+	}
+
+	comment := types.StringNull()
+	if dictionary.Comment != "" {
+		comment = types.StringValue(dictionary.Comment)
+	}
+
+	state := &Dictionary{
+		ClusterName:  types.StringPointerValue(clusterName),
+		UUID:         types.StringValue(dictionary.UUID),
+		DatabaseName: types.StringValue(dictionary.DatabaseName),
+		Name:         types.StringValue(dictionary.Name),
+		Attributes:   plan.Attributes,
+		PrimaryKey:   plan.PrimaryKey,
+		Source:       plan.Source,
+		Layout:       plan.Layout,
+		LifetimeMin:  plan.LifetimeMin,
+		LifetimeMax:  plan.LifetimeMax,
+		Comment:      comment,
+	}
+
+	// The structure is only known once the dictionary was loaded.
+	if len(dictionary.PrimaryKey) > 0 {
+		if !sameAttributes(plan.Attributes, dictionary.Attributes) {
+			state.Attributes = make([]Attribute, 0, len(dictionary.Attributes))
+			for _, attribute := range dictionary.Attributes {
+				state.Attributes = append(state.Attributes, Attribute{
+					Name:         types.StringValue(attribute.Name),
+					Type:         types.StringValue(attribute.Type),
+					Default:      types.StringNull(),
+					Expression:   types.StringNull(),
+					Hierarchical: types.BoolNull(),
+					Injective:    types.BoolNull(),
+				})
+			}
+		}
+
+		var plannedPrimaryKey []string
+		if !plan.PrimaryKey.IsNull() {
+			diags := plan.PrimaryKey.ElementsAs(ctx, &plannedPrimaryKey, false)
+			if diags.HasError() {
+				return nil, errors.New("cannot read primary key")
+			}
+		}
+		if strings.Join(plannedPrimaryKey, "\n") != strings.Join(dictionary.PrimaryKey, "\n") {
+			primaryKey, diags := types.ListValueFrom(ctx, types.StringType, dictionary.PrimaryKey)
+			if diags.HasError() {
+				return nil, errors.New("cannot convert primary key")
+			}
+			state.PrimaryKey = primaryKey
+		}
+	}
+
+	if dictionary.Layout.Name != "" && (plan.Layout == nil || !sameLayout(plan.Layout.Type.ValueString(), dictionary.Layout.Name)) {
+		state.Layout = &Layout{
+			Type:       types.StringValue(layoutType(dictionary.Layout.Name)),
+			Parameters: types.MapNull(types.StringType),
+		}
+	}
+
+	// LIFETIME(n) is read back with the same min and max, and no LIFETIME as both being 0.
+	state.LifetimeMax = types.Int64Value(int64(dictionary.LifetimeMax))
+	if plan.LifetimeMax.IsNull() && dictionary.LifetimeMax == 0 {
+		state.LifetimeMax = types.Int64Null()
+	}
+	state.LifetimeMin = types.Int64Value(int64(dictionary.LifetimeMin))
+	if plan.LifetimeMin.IsNull() && dictionary.LifetimeMin == dictionary.LifetimeMax {
+		state.LifetimeMin = types.Int64Null()
+	}
+
+	return state, nil
+}
+
+// dictionaryFromPlan returns the dictionary to create for the plan.
+func dictionaryFromPlan(ctx context.Context, plan Dictionary) (dbops.Dictionary, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	dictionary := dbops.Dictionary{
+		DatabaseName: plan.DatabaseName.ValueString(),
+		Name:         plan.Name.ValueString(),
+		LifetimeMax:  uint64(plan.LifetimeMax.ValueInt64()),
+		Comment:      plan.Comment.ValueString(),
+	}
+	dictionary.LifetimeMin = dictionary.LifetimeMax
+	if !plan.LifetimeMin.IsNull() {
+		dictionary.LifetimeMin = uint64(plan.LifetimeMin.ValueInt64())
+	}
+
+	for _, attribute := range plan.Attributes {
+		dictionary.Attributes = append(dictionary.Attributes, dbops.DictionaryAttribute{
+			Name:         attribute.Name.ValueString(),
+			Type:         attribute.Type.ValueString(),
+			Default:      attribute.Default.ValueStringPointer(),
+			Expression:   attribute.Expression.ValueStringPointer(),
+			Hierarchical: attribute.Hierarchical.ValueBool(),
+			Injective:    attribute.Injective.ValueBool(),
+		})
+	}
+
+	diags.Append(plan.PrimaryKey.ElementsAs(ctx, &dictionary.PrimaryKey, false)...)
+
+	if plan.Source != nil {
+		dictionary.Source.Name = plan.Source.Type.ValueString()
+		dictionary.Source.Parameters = make(map[string]string)
+		if !plan.Source.Parameters.IsNull() {
+			diags.Append(plan.Source.Parameters.ElementsAs(ctx, &dictionary.Source.Parameters, false)...)
+		}
+		if !plan.Source.Password.IsNull() {
+			dictionary.Source.Parameters["password"] = plan.Source.Password.ValueString()
+		}
+	}
+
+	if plan.Layout != nil {
+		dictionary.Layout.Name = plan.Layout.Type.ValueString()
+		if !plan.Layout.Parameters.IsNull() {
+			diags.Append(plan.Layout.Parameters.ElementsAs(ctx, &dictionary.Layout.Parameters, false)...)
+		}
+	}
+
+	return dictionary, diags
+}
+
+// sameAttributes tells whether the planned attributes have the names and types of the attributes read back, in any
+// order since key columns are read back first.
+func sameAttributes(planned []Attribute, actual []dbops.DictionaryAttribute) bool {
+	if len(planned) != len(actual) {
+		return false
+	}
+
+	actualTypes := make(map[string]string)
+	for _, attribute := range actual {
+		actualTypes[attribute.Name] = attribute.Type
+	}
+
+	for _, attribute := range planned {
+		actualType, ok := actualTypes[attribute.Name.ValueString()]
+		if !ok || !schemadiff.SameType(attribute.Type.ValueString(), actualType) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sameLayout compares a layout type, e.g. complex_key_hashed, with the name system.dictionaries gives it, e.g.
+// ComplexKeyHashed.
+func sameLayout(planned string, actual string) bool {
+	return strings.ReplaceAll(strings.ToLower(planned), "_", "") == strings.ToLower(actual)
+}
+
+// layoutType turns the name system.dictionaries gives to a layout, e.g. ComplexKeyHashed or IPTrie, into its type,
+// e.g. complex_key_hashed or ip_trie.
+func layoutType(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+	for idx, r := range runes {
+		if idx > 0 && unicode.IsUpper(r) && (unicode.IsLower(runes[idx-1]) || (idx+1 < len(runes) && unicode.IsLower(runes[idx+1]))) {
+			b.WriteRune('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String()
+}
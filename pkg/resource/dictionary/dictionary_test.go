@@ -0,0 +1,32 @@
+package dictionary
+
+import (
+	"testing"
+)
+
+func TestLayoutType(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "Hashed", want: "hashed"},
+		{name: "ComplexKeyHashed", want: "complex_key_hashed"},
+		{name: "SparseHashedArray", want: "sparse_hashed_array"},
+		{name: "IPTrie", want: "ip_trie"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := layoutType(tt.name); got != tt.want {
+				t.Errorf("layoutType() = %v, want %v", got, tt.want)
+			}
+			if !sameLayout(tt.want, tt.name) {
+				t.Errorf("sameLayout(%q, %q) = false, want true", tt.want, tt.name)
+			}
+		})
+	}
+
+	if sameLayout("hashed", "ComplexKeyHashed") {
+		t.Errorf("sameLayout(\"hashed\", \"ComplexKeyHashed\") = true, want false")
+	}
+}
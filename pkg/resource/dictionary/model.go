@@ -0,0 +1,39 @@
+package dictionary
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type Dictionary struct {
+	ClusterName  types.String `tfsdk:"cluster_name"`
+	UUID         types.String `tfsdk:"uuid"`
+	DatabaseName types.String `tfsdk:"database_name"`
+	Name         types.String `tfsdk:"name"`
+	Attributes   []Attribute  `tfsdk:"attributes"`
+	PrimaryKey   types.List   `tfsdk:"primary_key"`
+	Source       *Source      `tfsdk:"source"`
+	Layout       *Layout      `tfsdk:"layout"`
+	LifetimeMin  types.Int64  `tfsdk:"lifetime_min"`
+	LifetimeMax  types.Int64  `tfsdk:"lifetime_max"`
+	Comment      types.String `tfsdk:"comment"`
+}
+
+type Attribute struct {
+	Name         types.String `tfsdk:"name"`
+	Type         types.String `tfsdk:"type"`
+	Default      types.String `tfsdk:"default"`
+	Expression   types.String `tfsdk:"expression"`
+	Hierarchical types.Bool   `tfsdk:"hierarchical"`
+	Injective    types.Bool   `tfsdk:"injective"`
+}
+
+type Source struct {
+	Type       types.String `tfsdk:"type"`
+	Parameters types.Map    `tfsdk:"parameters"`
+	Password   types.String `tfsdk:"password"`
+}
+
+type Layout struct {
+	Type       types.String `tfsdk:"type"`
+	Parameters types.Map    `tfsdk:"parameters"`
+}
@@ -0,0 +1,165 @@
+package freezetable
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clustername"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+//go:embed freezetable.md
+var freezeTableResourceDescription string
+
+var (
+	_ resource.Resource               = &Resource{}
+	_ resource.ResourceWithConfigure  = &Resource{}
+	_ resource.ResourceWithModifyPlan = &Resource{}
+)
+
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+type Resource struct {
+	client dbops.Client
+}
+
+func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_freeze_table"
+}
+
+func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to run the query on. If omitted, the snapshot is only taken on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Random identifier of this run",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"database_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the database containing the table",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"table_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the table to freeze",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"partition": schema.StringAttribute{
+				Optional:    true,
+				Description: "Partition expression to restrict the snapshot to, e.g. `'202401'`, `tuple(2024, 1)` or `ID '202401'`. If omitted, the whole table is frozen",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"snapshot_name": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Name of the snapshot, used as directory name under `shadow/`. Defaults to the `id` of the resource",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"triggers": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Arbitrary values that cause a new snapshot to be taken when changed",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+		MarkdownDescription: freezeTableResourceDescription,
+	}
+}
+
+func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_freeze_table", "plan")
+
+	clustername.ValidatePlan(ctx, r.client, req.Plan, &resp.Diagnostics)
+}
+
+func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(dbops.Client)
+}
+
+func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_freeze_table", "create")
+
+	var plan FreezeTable
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = types.StringValue(uuid.NewString())
+	if plan.SnapshotName.IsUnknown() || plan.SnapshotName.IsNull() {
+		plan.SnapshotName = plan.ID
+	}
+
+	err := r.client.FreezeTable(
+		ctx,
+		plan.DatabaseName.ValueString(),
+		plan.TableName.ValueString(),
+		plan.Partition.ValueStringPointer(),
+		plan.SnapshotName.ValueString(),
+		plan.ClusterName.ValueStringPointer(),
+	)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Freezing ClickHouse Table",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Nothing to read, the resource only represents a past run of the query.
+}
+
+func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	panic("Update of freeze_table resource is not supported")
+}
+
+func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Snapshots are left in place on purpose, they are meant to outlive the resource.
+}
@@ -0,0 +1,15 @@
+package freezetable
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type FreezeTable struct {
+	ClusterName  types.String `tfsdk:"cluster_name"`
+	ID           types.String `tfsdk:"id"`
+	DatabaseName types.String `tfsdk:"database_name"`
+	TableName    types.String `tfsdk:"table_name"`
+	Partition    types.String `tfsdk:"partition"`
+	SnapshotName types.String `tfsdk:"snapshot_name"`
+	Triggers     types.Map    `tfsdk:"triggers"`
+}
@@ -0,0 +1,330 @@
+package function
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clustername"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+//go:embed function.md
+var functionResourceDescription string
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &Resource{}
+	_ resource.ResourceWithConfigure   = &Resource{}
+	_ resource.ResourceWithImportState = &Resource{}
+	_ resource.ResourceWithModifyPlan  = &Resource{}
+)
+
+// NewResource is a helper function to simplify the provider implementation.
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+// Resource is the resource implementation.
+type Resource struct {
+	client dbops.Client
+}
+
+// Metadata returns the resource type name.
+func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_function"
+}
+
+// Schema defines the schema for the resource.
+func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to create the function into. If omitted, the function will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nShould be set when hitting a cluster with more than one replica.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the function",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"arguments": schema.ListAttribute{
+				ElementType: types.StringType,
+				Required:    true,
+				Description: "Names of the arguments of the function, in order. Can be empty.",
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+					listvalidator.UniqueValues(),
+				},
+			},
+			"expression": schema.StringAttribute{
+				Required:    true,
+				Description: "The expression the function returns, using the arguments. It is not compared with the expression read back from ClickHouse, which normalizes it.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+		},
+		MarkdownDescription: functionResourceDescription,
+	}
+}
+
+func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_function", "plan")
+
+	clustername.ValidatePlan(ctx, r.client, req.Plan, &resp.Diagnostics)
+}
+
+func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(dbops.Client)
+}
+
+func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_function", "create")
+
+	var plan Function
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	function, err := functionFromPlan(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating function",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	created, err := r.client.CreateFunction(ctx, function, plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating function",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	state, err := syncFunctionState(ctx, created, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error syncing function",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_function", "read")
+
+	var state Function
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	function, err := r.client.GetFunction(ctx, state.Name.ValueString(), state.ClusterName.ValueStringPointer())
+	if dbops.IsRestrictedRead(err) {
+		resp.Diagnostics.AddWarning(
+			"Unable to Refresh ClickHouse Function",
+			"Not allowed to read the function, keeping the prior state: "+err.Error(),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading function",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if function == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	newState, err := syncFunctionState(ctx, function, state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error syncing function",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, newState)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_function", "update")
+
+	// The name and the cluster require a replacement: the arguments and the expression are replaced in place.
+	var plan Function
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	function, err := functionFromPlan(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating function",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	updated, err := r.client.ReplaceFunction(ctx, function, plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating function",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if updated == nil {
+		resp.Diagnostics.AddError(
+			"Error updating function",
+			"failed retrieving function after update",
+		)
+		return
+	}
+
+	state, err := syncFunctionState(ctx, updated, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error syncing function",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_function", "delete")
+
+	var state Function
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteFunction(ctx, state.Name.ValueString(), state.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting function",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+}
+
+func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_function", "import")
+
+	// req.ID can either be in the form <cluster name>:<function name> or just <function name>
+
+	name := req.ID
+	var clusterName *string
+	if strings.Contains(req.ID, ":") {
+		clusterName = &strings.Split(req.ID, ":")[0]
+		name = strings.Split(req.ID, ":")[1]
+	}
+
+	function, err := r.client.GetFunction(ctx, name, clusterName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Cannot find function",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+	if function == nil {
+		resp.Diagnostics.AddError(
+			"Cannot find function",
+			fmt.Sprintf("function %q not found", name),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), function.Name)...)
+	if clusterName != nil {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cluster_name"), clusterName)...)
+	}
+}
+
+// functionFromPlan returns the function to create or replace for the plan.
+func functionFromPlan(ctx context.Context, plan Function) (dbops.Function, error) {
+	function := dbops.Function{
+		Name:       plan.Name.ValueString(),
+		Arguments:  make([]string, 0),
+		Expression: plan.Expression.ValueString(),
+	}
+
+	diags := plan.Arguments.ElementsAs(ctx, &function.Arguments, false)
+	if diags.HasError() {
+		return function, errors.New("cannot read the arguments of the function")
+	}
+
+	return function, nil
+}
+
+// syncFunctionState returns the state for the function read from ClickHouse. The expression read back is normalized
+// by ClickHouse: the planned one is kept unless importing.
+func syncFunctionState(ctx context.Context, function *dbops.Function, plan Function) (*Function, error) {
+	arguments, diags := types.ListValueFrom(ctx, types.StringType, function.Arguments)
+	if diags.HasError() {
+		return nil, errors.New("cannot convert the arguments of the function")
+	}
+
+	state := &Function{
+		ClusterName: plan.ClusterName,
+		Name:        types.StringValue(function.Name),
+		Arguments:   arguments,
+		Expression:  plan.Expression,
+	}
+	if plan.Expression.IsNull() {
+		state.Expression = types.StringValue(function.Expression)
+	}
+
+	return state, nil
+}
@@ -0,0 +1,12 @@
+package function
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type Function struct {
+	ClusterName types.String `tfsdk:"cluster_name"`
+	Name        types.String `tfsdk:"name"`
+	Arguments   types.List   `tfsdk:"arguments"`
+	Expression  types.String `tfsdk:"expression"`
+}
@@ -5,17 +5,21 @@ import (
 	_ "embed"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clustername"
 	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
 )
 
@@ -64,6 +68,13 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
+			"access_storage_mode": schema.StringAttribute{
+				Optional:    true,
+				Description: "How the privilege grant is managed on the cluster set in `cluster_name`: `on_cluster` runs its statements ON CLUSTER, `replicated` runs them on the replica the provider is connected to and relies on replicated access storage to propagate them. Defaults to the provider's `access_storage_mode`, whose `auto` default picks `replicated` when the server uses replicated storage for grants. Changing it only affects the statements to come.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(dbops.AccessStorageModes...),
+				},
+			},
 			"cluster_name": schema.StringAttribute{
 				Optional:    true,
 				Description: "Name of the cluster to create the resource into. If omitted, resource will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nWhen using a self hosted ClickHouse instance, this field should only be set when there is more than one replica and you are not using 'replicated' storage for user_directory.\n",
@@ -145,11 +156,25 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 			"grant_option": schema.BoolAttribute{
 				Optional:    true,
 				Computed:    true,
-				Description: "If true, the grantee will be able to grant the same privileges to others.",
-				PlanModifiers: []planmodifier.Bool{
-					boolplanmodifier.RequiresReplace(),
+				Default:     booldefault.StaticBool(false),
+				Description: "If true, the grantee will be able to grant the same privileges to others. Refresh reads it back from the server, and a grant option added or taken back outside of Terraform is reconciled in place with `GRANT ... WITH GRANT OPTION` or `REVOKE GRANT OPTION FOR ...`, keeping the privilege itself.",
+			},
+			"verify_replicas": schema.BoolAttribute{
+				Optional:    true,
+				Description: "When true and the privilege grant runs ON CLUSTER (see `access_storage_mode`), refresh checks it on every replica of the cluster and the plan grants it again when some replicas are missing it. Meant for self hosted clusters with local access storage, where a grant failing on some replicas leaves them diverging.",
+			},
+			"missing_replicas": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Host names of the replicas missing the privilege grant at the last refresh, only checked when `verify_replicas` is true.",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"wait_for_target": schema.StringAttribute{
+				Optional:    true,
+				Description: "When set, creating the grant first waits up to this duration, like `5m`, for the database (or the table, when `table_name` is set) to exist, and fails if it still doesn't. ClickHouse grants privileges on objects that don't exist: use it when the object is created by another module or workspace, to avoid racing its creation.",
+			},
 		},
 		MarkdownDescription: grantPrivilegeDescription,
 	}
@@ -164,11 +189,15 @@ func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _
 }
 
 func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_grant_privilege", "plan")
+
 	if req.Plan.Raw.IsNull() {
 		// If the entire plan is null, the resource is planned for destruction.
 		return
 	}
 
+	clustername.ValidatePlan(ctx, r.client, req.Plan, &resp.Diagnostics)
+
 	upstrGrts := parseGrants()
 
 	var plan, state, config GrantPrivilege
@@ -190,8 +219,29 @@ func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanReques
 		return
 	}
 
-	if r.client != nil {
-		isReplicatedStorage, err := r.client.IsReplicatedStorage(ctx)
+	if !req.State.Raw.IsNull() && plan.VerifyReplicas.ValueBool() && len(state.MissingReplicas.Elements()) > 0 {
+		var replicas []string
+		resp.Diagnostics.Append(state.MissingReplicas.ElementsAs(ctx, &replicas, false)...)
+		resp.Diagnostics.AddWarning(
+			"Privilege Grant Missing On Replicas",
+			fmt.Sprintf("Privilege %q is not granted on %s, it will be granted again ON CLUSTER.", state.Privilege.ValueString(), strings.Join(replicas, ", ")),
+		)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("missing_replicas"), types.ListValueMust(types.StringType, []attr.Value{}))...)
+	}
+
+	if !req.State.Raw.IsNull() && !plan.GrantOption.IsUnknown() && !plan.GrantOption.Equal(state.GrantOption) {
+		statement := fmt.Sprintf("REVOKE GRANT OPTION FOR %s", state.Privilege.ValueString())
+		if plan.GrantOption.ValueBool() {
+			statement = fmt.Sprintf("GRANT %s ... WITH GRANT OPTION", state.Privilege.ValueString())
+		}
+		resp.Diagnostics.AddWarning(
+			"Privilege Grant Option Differs",
+			fmt.Sprintf("The grant option of privilege %q is %t on the server and %t in the configuration, it will be reconciled in place with %s, keeping the privilege itself.", state.Privilege.ValueString(), state.GrantOption.ValueBool(), plan.GrantOption.ValueBool(), statement),
+		)
+	}
+
+	if r.client != nil && !config.ClusterName.IsNull() && !config.ClusterName.IsUnknown() && !config.AccessStorageMode.IsUnknown() {
+		clusterName, err := r.client.AccessCluster(ctx, config.ClusterName.ValueStringPointer(), config.AccessStorageMode.ValueString())
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error Checking if service is using replicated storage",
@@ -200,14 +250,32 @@ func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanReques
 			return
 		}
 
-		if isReplicatedStorage {
-			// GrantPrivilege cannot specify 'cluster_name' or apply will fail.
-			if !config.ClusterName.IsNull() {
-				resp.Diagnostics.AddWarning(
-					"Invalid configuration",
-					"Your ClickHouse cluster is using Replicated storage for grants, please remove the 'cluster_name' attribute from your GrantPrivilege resource definition if you encounter any errors.",
-				)
-			}
+		if clusterName == nil && config.AccessStorageMode.ValueString() != dbops.AccessStorageModeReplicated {
+			resp.Diagnostics.AddWarning(
+				"Cluster Name Not Used",
+				"Your ClickHouse cluster seems to be using Replicated storage for grants, the privilege grant is managed on the replica the provider is connected to and ClickHouse replicates it rather than running ON CLUSTER. Set 'access_storage_mode' to \"on_cluster\" if some replicas of the cluster use a different access storage.",
+			)
+		}
+	}
+
+	if !config.WaitForTarget.IsNull() && !config.WaitForTarget.IsUnknown() {
+		timeout, err := time.ParseDuration(config.WaitForTarget.ValueString())
+		if err != nil || timeout <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("wait_for_target"),
+				"Invalid wait_for_target",
+				fmt.Sprintf("%q is not a positive duration like \"5m\".", config.WaitForTarget.ValueString()),
+			)
+			return
+		}
+
+		if plan.Database.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("wait_for_target"),
+				"Invalid wait_for_target",
+				"'wait_for_target' can only be used when 'database_name' is set.",
+			)
+			return
 		}
 	}
 
@@ -264,6 +332,8 @@ func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanReques
 }
 
 func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_grant_privilege", "create")
+
 	var plan GrantPrivilege
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -281,7 +351,36 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 		GrantOption:     plan.GrantOption.ValueBool(),
 	}
 
-	createdGrant, err := r.client.GrantPrivilege(ctx, grant, plan.ClusterName.ValueStringPointer())
+	clusterName, err := r.client.AccessCluster(ctx, plan.ClusterName.ValueStringPointer(), plan.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Creating ClickHouse Privilege Grant",
+			"Could not create privilege grant, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	if !plan.WaitForTarget.IsNull() && grant.DatabaseName != nil {
+		timeout, err := time.ParseDuration(plan.WaitForTarget.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Creating ClickHouse Privilege Grant",
+				"Invalid wait_for_target: "+err.Error(),
+			)
+			return
+		}
+
+		err = r.client.WaitForGrantTarget(ctx, *grant.DatabaseName, grant.TableName, clusterName, timeout)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Creating ClickHouse Privilege Grant",
+				"The object of the privilege grant is missing: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	createdGrant, err := r.client.GrantPrivilege(ctx, grant, clusterName)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Creating ClickHouse Privilege Grant",
@@ -291,7 +390,7 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 	}
 
 	if createdGrant == nil {
-		existing, err := r.client.GetAllGrantsForGrantee(ctx, grant.GranteeUserName, grant.GranteeRoleName, plan.ClusterName.ValueStringPointer())
+		existing, err := r.client.GetAllGrantsForGrantee(ctx, grant.GranteeUserName, grant.GranteeRoleName, clusterName)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error checking for existing overlapping privileges",
@@ -328,15 +427,32 @@ This is a configuration error that prevents further actions. Please note that th
 		return
 	}
 
+	if createdGrant.GrantOption && !grant.GrantOption {
+		// The grantee already had the privilege WITH GRANT OPTION, which GRANT doesn't take back.
+		err = r.client.RevokeGrantOption(ctx, grant.AccessType, grant.DatabaseName, grant.TableName, grant.ColumnName, grant.GranteeUserName, grant.GranteeRoleName, clusterName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Creating ClickHouse Privilege Grant",
+				"Could not take the grant option back, unexpected error: "+err.Error(),
+			)
+			return
+		}
+		createdGrant.GrantOption = false
+	}
+
 	state := GrantPrivilege{
-		ClusterName:     plan.ClusterName,
-		Privilege:       types.StringValue(createdGrant.AccessType),
-		Database:        types.StringPointerValue(createdGrant.DatabaseName),
-		Table:           types.StringPointerValue(createdGrant.TableName),
-		Column:          types.StringPointerValue(createdGrant.ColumnName),
-		GranteeUserName: types.StringPointerValue(createdGrant.GranteeUserName),
-		GranteeRoleName: types.StringPointerValue(createdGrant.GranteeRoleName),
-		GrantOption:     types.BoolValue(createdGrant.GrantOption),
+		AccessStorageMode: plan.AccessStorageMode,
+		ClusterName:       plan.ClusterName,
+		Privilege:         types.StringValue(createdGrant.AccessType),
+		Database:          types.StringPointerValue(createdGrant.DatabaseName),
+		Table:             types.StringPointerValue(createdGrant.TableName),
+		Column:            types.StringPointerValue(createdGrant.ColumnName),
+		GranteeUserName:   types.StringPointerValue(createdGrant.GranteeUserName),
+		GranteeRoleName:   types.StringPointerValue(createdGrant.GranteeRoleName),
+		GrantOption:       types.BoolValue(createdGrant.GrantOption),
+		VerifyReplicas:    plan.VerifyReplicas,
+		MissingReplicas:   types.ListValueMust(types.StringType, []attr.Value{}),
+		WaitForTarget:     plan.WaitForTarget,
 	}
 
 	diags = resp.State.Set(ctx, state)
@@ -347,6 +463,8 @@ This is a configuration error that prevents further actions. Please note that th
 }
 
 func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_grant_privilege", "read")
+
 	var state GrantPrivilege
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -354,7 +472,23 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 		return
 	}
 
-	grant, err := r.client.GetGrantPrivilege(ctx, state.Privilege.ValueString(), state.Database.ValueStringPointer(), state.Table.ValueStringPointer(), state.Column.ValueStringPointer(), state.GranteeUserName.ValueStringPointer(), state.GranteeRoleName.ValueStringPointer(), state.ClusterName.ValueStringPointer())
+	clusterName, err := r.client.AccessCluster(ctx, state.ClusterName.ValueStringPointer(), state.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Privilege Grant",
+			"Could not read privilege grant, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	grant, err := r.client.GetGrantPrivilege(ctx, state.Privilege.ValueString(), state.Database.ValueStringPointer(), state.Table.ValueStringPointer(), state.Column.ValueStringPointer(), state.GranteeUserName.ValueStringPointer(), state.GranteeRoleName.ValueStringPointer(), clusterName)
+	if dbops.IsRestrictedRead(err) {
+		resp.Diagnostics.AddWarning(
+			"Unable to Refresh ClickHouse Privilege Grant",
+			"Not allowed to read the privilege grant, keeping the prior state: "+err.Error(),
+		)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading ClickHouse Privilege Grant",
@@ -372,6 +506,20 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 		state.GranteeRoleName = types.StringPointerValue(grant.GranteeRoleName)
 		state.GrantOption = types.BoolValue(grant.GrantOption)
 
+		if state.VerifyReplicas.ValueBool() && clusterName != nil {
+			missing, err := r.client.GetGrantPrivilegeMissingReplicas(ctx, state.Privilege.ValueString(), state.Database.ValueStringPointer(), state.Table.ValueStringPointer(), state.Column.ValueStringPointer(), state.GranteeUserName.ValueStringPointer(), state.GranteeRoleName.ValueStringPointer(), *clusterName)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error Reading ClickHouse Privilege Grant",
+					"Could not read privilege grant, unexpected error: "+err.Error(),
+				)
+				return
+			}
+
+			state.MissingReplicas, diags = types.ListValueFrom(ctx, types.StringType, missing)
+			resp.Diagnostics.Append(diags...)
+		}
+
 		diags = resp.State.Set(ctx, &state)
 		resp.Diagnostics.Append(diags...)
 	} else {
@@ -380,10 +528,80 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 }
 
 func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	panic("Update of grant privilege resource is not supported")
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_grant_privilege", "update")
+
+	// Every other attribute requires a replacement: only access_storage_mode, grant_option, verify_replicas,
+	// missing_replicas and wait_for_target can change here.
+	var plan, state GrantPrivilege
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	clusterName, err := r.client.AccessCluster(ctx, plan.ClusterName.ValueStringPointer(), plan.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating ClickHouse Privilege Grant",
+			"Could not update privilege grant, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// GRANT is idempotent: running it again ON CLUSTER repairs the replicas missing it, and WITH GRANT OPTION adds the
+	// grant option to the privilege already granted. It never takes the grant option back, REVOKE GRANT OPTION FOR
+	// does it afterward.
+	repairReplicas := len(plan.MissingReplicas.Elements()) == 0 && len(state.MissingReplicas.Elements()) > 0
+	addGrantOption := plan.GrantOption.ValueBool() && !state.GrantOption.ValueBool()
+	revokeGrantOption := !plan.GrantOption.ValueBool() && state.GrantOption.ValueBool()
+
+	if repairReplicas || addGrantOption {
+		grant := dbops.GrantPrivilege{
+			AccessType:      plan.Privilege.ValueString(),
+			DatabaseName:    plan.Database.ValueStringPointer(),
+			TableName:       plan.Table.ValueStringPointer(),
+			ColumnName:      plan.Column.ValueStringPointer(),
+			GranteeUserName: plan.GranteeUserName.ValueStringPointer(),
+			GranteeRoleName: plan.GranteeRoleName.ValueStringPointer(),
+			GrantOption:     plan.GrantOption.ValueBool(),
+		}
+
+		_, err = r.client.GrantPrivilege(ctx, grant, clusterName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Updating ClickHouse Privilege Grant",
+				"Could not update privilege grant, unexpected error: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	if revokeGrantOption {
+		err = r.client.RevokeGrantOption(ctx, plan.Privilege.ValueString(), plan.Database.ValueStringPointer(), plan.Table.ValueStringPointer(), plan.Column.ValueStringPointer(), plan.GranteeUserName.ValueStringPointer(), plan.GranteeRoleName.ValueStringPointer(), clusterName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Updating ClickHouse Privilege Grant",
+				"Could not take the grant option back, unexpected error: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	state.AccessStorageMode = plan.AccessStorageMode
+	state.GrantOption = plan.GrantOption
+	state.VerifyReplicas = plan.VerifyReplicas
+	state.MissingReplicas = plan.MissingReplicas
+	state.WaitForTarget = plan.WaitForTarget
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
 }
 
 func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_grant_privilege", "delete")
+
 	var state GrantPrivilege
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -391,7 +609,16 @@ func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp
 		return
 	}
 
-	err := r.client.RevokeGrantPrivilege(ctx, state.Privilege.ValueString(), state.Database.ValueStringPointer(), state.Table.ValueStringPointer(), state.Column.ValueStringPointer(), state.GranteeUserName.ValueStringPointer(), state.GranteeRoleName.ValueStringPointer(), state.ClusterName.ValueStringPointer())
+	clusterName, err := r.client.AccessCluster(ctx, state.ClusterName.ValueStringPointer(), state.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Deleting ClickHouse Privilege Grant",
+			"Could not delete privilege grant, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	err = r.client.RevokeGrantPrivilege(ctx, state.Privilege.ValueString(), state.Database.ValueStringPointer(), state.Table.ValueStringPointer(), state.Column.ValueStringPointer(), state.GranteeUserName.ValueStringPointer(), state.GranteeRoleName.ValueStringPointer(), clusterName)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting ClickHouse Privilege Grant",
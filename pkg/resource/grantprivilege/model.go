@@ -5,12 +5,16 @@ import (
 )
 
 type GrantPrivilege struct {
-	ClusterName     types.String `tfsdk:"cluster_name"`
-	Privilege       types.String `tfsdk:"privilege_name"`
-	Database        types.String `tfsdk:"database_name"`
-	Table           types.String `tfsdk:"table_name"`
-	Column          types.String `tfsdk:"column_name"`
-	GranteeUserName types.String `tfsdk:"grantee_user_name"`
-	GranteeRoleName types.String `tfsdk:"grantee_role_name"`
-	GrantOption     types.Bool   `tfsdk:"grant_option"`
+	AccessStorageMode types.String `tfsdk:"access_storage_mode"`
+	ClusterName       types.String `tfsdk:"cluster_name"`
+	Privilege         types.String `tfsdk:"privilege_name"`
+	Database          types.String `tfsdk:"database_name"`
+	Table             types.String `tfsdk:"table_name"`
+	Column            types.String `tfsdk:"column_name"`
+	GranteeUserName   types.String `tfsdk:"grantee_user_name"`
+	GranteeRoleName   types.String `tfsdk:"grantee_role_name"`
+	GrantOption       types.Bool   `tfsdk:"grant_option"`
+	VerifyReplicas    types.Bool   `tfsdk:"verify_replicas"`
+	MissingReplicas   types.List   `tfsdk:"missing_replicas"`
+	WaitForTarget     types.String `tfsdk:"wait_for_target"`
 }
@@ -4,17 +4,21 @@ import (
 	"context"
 	_ "embed"
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clustername"
 	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
 )
 
@@ -42,6 +46,13 @@ func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, res
 func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
+			"access_storage_mode": schema.StringAttribute{
+				Optional:    true,
+				Description: "How the role grant is managed on the cluster set in `cluster_name`: `on_cluster` runs its statements ON CLUSTER, `replicated` runs them on the replica the provider is connected to and relies on replicated access storage to propagate them. Defaults to the provider's `access_storage_mode`, whose `auto` default picks `replicated` when the server uses replicated storage for role grants. Changing it only affects the statements to come.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(dbops.AccessStorageModes...),
+				},
+			},
 			"cluster_name": schema.StringAttribute{
 				Optional:    true,
 				Description: "Name of the cluster to create the resource into. If omitted, resource will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nWhen using a self hosted ClickHouse instance, this field should only be set when there is more than one replica and you are not using 'replicated' storage for user_directory.\n",
@@ -92,19 +103,67 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 					boolplanmodifier.RequiresReplace(),
 				},
 			},
+			"verify_replicas": schema.BoolAttribute{
+				Optional:    true,
+				Description: "When true and the role grant runs ON CLUSTER (see `access_storage_mode`), refresh checks it on every replica of the cluster and the plan grants it again when some replicas are missing it. Meant for self hosted clusters with local access storage, where a grant failing on some replicas leaves them diverging.",
+			},
+			"missing_replicas": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Host names of the replicas missing the role grant at the last refresh, only checked when `verify_replicas` is true.",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
 		},
 		MarkdownDescription: grantResourceDescription,
 	}
 }
 
 func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_grant_role", "plan")
+
 	if req.Plan.Raw.IsNull() {
 		// If the entire plan is null, the resource is planned for destruction.
 		return
 	}
 
+	clustername.ValidatePlan(ctx, r.client, req.Plan, &resp.Diagnostics)
+
+	if !req.State.Raw.IsNull() {
+		var plan, state GrantRole
+		diags := req.Plan.Get(ctx, &plan)
+		resp.Diagnostics.Append(diags...)
+		diags = req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if plan.VerifyReplicas.ValueBool() && len(state.MissingReplicas.Elements()) > 0 {
+			var replicas []string
+			resp.Diagnostics.Append(state.MissingReplicas.ElementsAs(ctx, &replicas, false)...)
+			resp.Diagnostics.AddWarning(
+				"Role Grant Missing On Replicas",
+				fmt.Sprintf("Role %q is not granted on %s, it will be granted again ON CLUSTER.", state.RoleName.ValueString(), strings.Join(replicas, ", ")),
+			)
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("missing_replicas"), types.ListValueMust(types.StringType, []attr.Value{}))...)
+		}
+	}
+
 	if r.client != nil {
-		isReplicatedStorage, err := r.client.IsReplicatedStorage(ctx)
+		var config GrantRole
+		diags := req.Config.Get(ctx, &config)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if config.ClusterName.IsNull() || config.ClusterName.IsUnknown() || config.AccessStorageMode.IsUnknown() {
+			return
+		}
+
+		clusterName, err := r.client.AccessCluster(ctx, config.ClusterName.ValueStringPointer(), config.AccessStorageMode.ValueString())
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error Checking if service is using replicated storage",
@@ -113,21 +172,11 @@ func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanReques
 			return
 		}
 
-		if isReplicatedStorage {
-			var config GrantRole
-			diags := req.Config.Get(ctx, &config)
-			resp.Diagnostics.Append(diags...)
-			if resp.Diagnostics.HasError() {
-				return
-			}
-
-			// GrantRole cannot specify 'cluster_name' or apply will fail.
-			if !config.ClusterName.IsNull() {
-				resp.Diagnostics.AddWarning(
-					"Invalid configuration",
-					"Your ClickHouse cluster is using Replicated storage for role grants, please remove the 'cluster_name' attribute from your GrantRole resource definition if you encounter any errors.",
-				)
-			}
+		if clusterName == nil && config.AccessStorageMode.ValueString() != dbops.AccessStorageModeReplicated {
+			resp.Diagnostics.AddWarning(
+				"Cluster Name Not Used",
+				"Your ClickHouse cluster seems to be using Replicated storage for role grants, the role grant is managed on the replica the provider is connected to and ClickHouse replicates it rather than running ON CLUSTER. Set 'access_storage_mode' to \"on_cluster\" if some replicas of the cluster use a different access storage.",
+			)
 		}
 	}
 }
@@ -141,6 +190,8 @@ func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _
 }
 
 func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_grant_role", "create")
+
 	var plan GrantRole
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -155,7 +206,16 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 		AdminOption:     plan.AdminOption.ValueBool(),
 	}
 
-	createdGrant, err := r.client.GrantRole(ctx, grant, plan.ClusterName.ValueStringPointer())
+	clusterName, err := r.client.AccessCluster(ctx, plan.ClusterName.ValueStringPointer(), plan.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Creating ClickHouse Role Grant",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	createdGrant, err := r.client.GrantRole(ctx, grant, clusterName)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Creating ClickHouse Role Grant",
@@ -165,11 +225,14 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 	}
 
 	state := GrantRole{
-		ClusterName:     plan.ClusterName,
-		RoleName:        types.StringValue(createdGrant.RoleName),
-		GranteeUserName: types.StringPointerValue(createdGrant.GranteeUserName),
-		GranteeRoleName: types.StringPointerValue(createdGrant.GranteeRoleName),
-		AdminOption:     types.BoolValue(createdGrant.AdminOption),
+		AccessStorageMode: plan.AccessStorageMode,
+		ClusterName:       plan.ClusterName,
+		RoleName:          types.StringValue(createdGrant.RoleName),
+		GranteeUserName:   types.StringPointerValue(createdGrant.GranteeUserName),
+		GranteeRoleName:   types.StringPointerValue(createdGrant.GranteeRoleName),
+		AdminOption:       types.BoolValue(createdGrant.AdminOption),
+		VerifyReplicas:    plan.VerifyReplicas,
+		MissingReplicas:   types.ListValueMust(types.StringType, []attr.Value{}),
 	}
 
 	diags = resp.State.Set(ctx, state)
@@ -180,6 +243,8 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 }
 
 func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_grant_role", "read")
+
 	var state GrantRole
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -187,7 +252,23 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 		return
 	}
 
-	grant, err := r.client.GetGrantRole(ctx, state.RoleName.ValueString(), state.GranteeUserName.ValueStringPointer(), state.GranteeRoleName.ValueStringPointer(), state.ClusterName.ValueStringPointer())
+	clusterName, err := r.client.AccessCluster(ctx, state.ClusterName.ValueStringPointer(), state.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Role Grant",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	grant, err := r.client.GetGrantRole(ctx, state.RoleName.ValueString(), state.GranteeUserName.ValueStringPointer(), state.GranteeRoleName.ValueStringPointer(), clusterName)
+	if dbops.IsRestrictedRead(err) {
+		resp.Diagnostics.AddWarning(
+			"Unable to Refresh ClickHouse Role Grant",
+			"Not allowed to read the role grant, keeping the prior state: "+err.Error(),
+		)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading ClickHouse Role Grant",
@@ -202,6 +283,20 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 		state.GranteeRoleName = types.StringPointerValue(grant.GranteeRoleName)
 		state.AdminOption = types.BoolValue(grant.AdminOption)
 
+		if state.VerifyReplicas.ValueBool() && clusterName != nil {
+			missing, err := r.client.GetGrantRoleMissingReplicas(ctx, state.RoleName.ValueString(), state.GranteeUserName.ValueStringPointer(), state.GranteeRoleName.ValueStringPointer(), *clusterName)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error Reading ClickHouse Role Grant",
+					fmt.Sprintf("%+v\n", err),
+				)
+				return
+			}
+
+			state.MissingReplicas, diags = types.ListValueFrom(ctx, types.StringType, missing)
+			resp.Diagnostics.Append(diags...)
+		}
+
 		diags = resp.State.Set(ctx, &state)
 		resp.Diagnostics.Append(diags...)
 	} else {
@@ -210,10 +305,58 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 }
 
 func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	panic("Update of grant resource is not supported")
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_grant_role", "update")
+
+	// Every other attribute requires a replacement: only access_storage_mode, verify_replicas and missing_replicas can
+	// change here.
+	var plan, state GrantRole
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if len(plan.MissingReplicas.Elements()) == 0 && len(state.MissingReplicas.Elements()) > 0 {
+		// GRANT is idempotent: running it again ON CLUSTER repairs the replicas missing it.
+		clusterName, err := r.client.AccessCluster(ctx, plan.ClusterName.ValueStringPointer(), plan.AccessStorageMode.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Updating ClickHouse Role Grant",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+
+		grant := dbops.GrantRole{
+			RoleName:        plan.RoleName.ValueString(),
+			GranteeUserName: plan.GranteeUserName.ValueStringPointer(),
+			GranteeRoleName: plan.GranteeRoleName.ValueStringPointer(),
+			AdminOption:     plan.AdminOption.ValueBool(),
+		}
+
+		_, err = r.client.GrantRole(ctx, grant, clusterName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Updating ClickHouse Role Grant",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+	}
+
+	state.AccessStorageMode = plan.AccessStorageMode
+	state.VerifyReplicas = plan.VerifyReplicas
+	state.MissingReplicas = plan.MissingReplicas
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
 }
 
 func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_grant_role", "delete")
+
 	var state GrantRole
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -221,7 +364,16 @@ func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp
 		return
 	}
 
-	err := r.client.RevokeGrantRole(ctx, state.RoleName.ValueString(), state.GranteeUserName.ValueStringPointer(), state.GranteeRoleName.ValueStringPointer(), state.ClusterName.ValueStringPointer())
+	clusterName, err := r.client.AccessCluster(ctx, state.ClusterName.ValueStringPointer(), state.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Deleting ClickHouse Role Grant",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	err = r.client.RevokeGrantRole(ctx, state.RoleName.ValueString(), state.GranteeUserName.ValueStringPointer(), state.GranteeRoleName.ValueStringPointer(), clusterName)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting ClickHouse Role Grant",
@@ -5,9 +5,12 @@ import (
 )
 
 type GrantRole struct {
-	ClusterName     types.String `tfsdk:"cluster_name"`
-	RoleName        types.String `tfsdk:"role_name"`
-	GranteeUserName types.String `tfsdk:"grantee_user_name"`
-	GranteeRoleName types.String `tfsdk:"grantee_role_name"`
-	AdminOption     types.Bool   `tfsdk:"admin_option"`
+	AccessStorageMode types.String `tfsdk:"access_storage_mode"`
+	ClusterName       types.String `tfsdk:"cluster_name"`
+	RoleName          types.String `tfsdk:"role_name"`
+	GranteeUserName   types.String `tfsdk:"grantee_user_name"`
+	GranteeRoleName   types.String `tfsdk:"grantee_role_name"`
+	AdminOption       types.Bool   `tfsdk:"admin_option"`
+	VerifyReplicas    types.Bool   `tfsdk:"verify_replicas"`
+	MissingReplicas   types.List   `tfsdk:"missing_replicas"`
 }
@@ -0,0 +1,14 @@
+package killmutation
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type KillMutation struct {
+	ClusterName  types.String `tfsdk:"cluster_name"`
+	ID           types.String `tfsdk:"id"`
+	DatabaseName types.String `tfsdk:"database_name"`
+	TableName    types.String `tfsdk:"table_name"`
+	MutationID   types.String `tfsdk:"mutation_id"`
+	Triggers     types.Map    `tfsdk:"triggers"`
+}
@@ -0,0 +1,479 @@
+package materializedview
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clustername"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/comment"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/defaultdatabase"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/protecteddatabase"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/schemadiff"
+)
+
+//go:embed materializedview.md
+var materializedViewResourceDescription string
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &Resource{}
+	_ resource.ResourceWithConfigure      = &Resource{}
+	_ resource.ResourceWithImportState    = &Resource{}
+	_ resource.ResourceWithModifyPlan     = &Resource{}
+	_ resource.ResourceWithValidateConfig = &Resource{}
+)
+
+// NewResource is a helper function to simplify the provider implementation.
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+// Resource is the resource implementation.
+type Resource struct {
+	client dbops.Client
+}
+
+// Metadata returns the resource type name.
+func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_materialized_view"
+}
+
+// Schema defines the schema for the resource.
+func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to create the materialized view into. If omitted, the view will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nShould be set when hitting a cluster with more than one replica.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"uuid": schema.StringAttribute{
+				Computed:    true,
+				Description: "The system-assigned UUID for the materialized view",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"database_name": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Name of the database to create the materialized view into. Defaults to the provider's `default_database`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the materialized view",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"query": schema.StringAttribute{
+				Required:    true,
+				Description: "The SELECT query of the materialized view. It is not compared with the query read back from ClickHouse, which normalizes it.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"to_database_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Database of the table the view writes into. Defaults to `database_name`.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.AlsoRequires(path.MatchRoot("to_table_name")),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"to_table_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the table the view writes into (`TO` clause). When null, the view writes into an inner table created with `engine`.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.ConflictsWith(path.MatchRoot("engine")),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"engine": schema.StringAttribute{
+				Optional:    true,
+				Description: "Engine of the inner table with its clauses, e.g. `MergeTree ORDER BY id`. Only its name is compared with the engine read back from ClickHouse.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"refresh": schema.StringAttribute{
+				Optional:    true,
+				Description: "Makes the view refreshable: the clause following `REFRESH`, e.g. `EVERY 1 HOUR` or `AFTER 30 MINUTE APPEND`.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"populate": schema.BoolAttribute{
+				Optional:    true,
+				Description: "When true, the inner table is filled with the data already in the source table when the view is created. It is not read back from ClickHouse.",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"comment": schema.StringAttribute{
+				Optional:    true,
+				Description: "Comment associated with the materialized view",
+				Validators: []validator.String{
+					// If user specifies the comment field, it can't be the empty string otherwise we get an error from terraform
+					// due to the difference between null and empty string. User can always set this field to null or leave it out completely.
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"refresh_status": schema.StringAttribute{
+				Computed:    true,
+				Description: "Status of a refreshable view in `system.view_refreshes`, e.g. `Scheduled` or `Running`, as of the last refresh of the state.",
+			},
+			"create_statement": schema.StringAttribute{
+				Computed:    true,
+				Description: "The CREATE MATERIALIZED VIEW statement the provider runs to create the view, rendered during plan unless the configuration depends on values only known during apply.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		MarkdownDescription: materializedViewResourceDescription,
+	}
+}
+
+func (r *Resource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config MaterializedView
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Populate.ValueBool() {
+		return
+	}
+
+	if !config.ToTableName.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("populate"),
+			"Invalid Materialized View Settings",
+			"'populate' can't be used with 'to_table_name': insert the existing data into the table instead.",
+		)
+	}
+	if !config.Refresh.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("populate"),
+			"Invalid Materialized View Settings",
+			"'populate' can't be used with 'refresh': a refreshable view is filled by its first refresh.",
+		)
+	}
+}
+
+func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_materialized_view", "plan")
+
+	clustername.ValidatePlan(ctx, r.client, req.Plan, &resp.Diagnostics)
+
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	defaultdatabase.ResolvePlan(ctx, r.client, req, resp)
+
+	var planDatabaseName, planComment types.String
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("database_name"), &planDatabaseName)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("comment"), &planComment)...)
+	protecteddatabase.Validate(r.client, path.Root("database_name"), planDatabaseName, &resp.Diagnostics)
+	comment.Validate(r.client, path.Root("comment"), planComment, &resp.Diagnostics)
+
+	if req.State.Raw.IsNull() && req.Config.Raw.IsFullyKnown() && !resp.Diagnostics.HasError() {
+		// Render the statement Create will run.
+		var plan MaterializedView
+		resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		createStatement, err := dbops.CreateMaterializedViewStatement(materializedViewFromPlan(plan), plan.ClusterName.ValueStringPointer())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error rendering materialized view",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("create_statement"), createStatement)...)
+	}
+}
+
+func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(dbops.Client)
+}
+
+func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_materialized_view", "create")
+
+	var plan MaterializedView
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	view := materializedViewFromPlan(plan)
+
+	createStatement, err := dbops.CreateMaterializedViewStatement(view, plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating materialized view",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	created, err := r.client.CreateMaterializedView(ctx, view, plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating materialized view",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	state, err := r.syncMaterializedViewState(ctx, created.UUID, plan.ClusterName.ValueStringPointer(), &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error syncing materialized view",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if state == nil {
+		resp.Diagnostics.AddError(
+			"Error syncing materialized view",
+			"failed retrieving materialized view after creation",
+		)
+		return
+	}
+
+	state.CreateStatement = types.StringValue(createStatement)
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_materialized_view", "read")
+
+	var plan MaterializedView
+	diags := req.State.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state, err := r.syncMaterializedViewState(ctx, plan.UUID.ValueString(), plan.ClusterName.ValueStringPointer(), &plan)
+	if dbops.IsRestrictedRead(err) {
+		resp.Diagnostics.AddWarning(
+			"Unable to Refresh ClickHouse Materialized View",
+			"Not allowed to read the materialized view, keeping the prior state: "+err.Error(),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error syncing materialized view",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if state == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.CreateStatement = plan.CreateStatement
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	panic("Update of materialized view resource is not supported")
+}
+
+func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_materialized_view", "delete")
+
+	var plan MaterializedView
+	diags := req.State.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteMaterializedView(ctx, plan.UUID.ValueString(), plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting materialized view",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+}
+
+func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_materialized_view", "import")
+
+	// req.ID can either be in the form <cluster name>:<database name>:<view ref> or just <database name>:<view ref>
+	// view ref can either be the name or the UUID of the materialized view.
+
+	parts := strings.Split(req.ID, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		resp.Diagnostics.AddError(
+			"Invalid import ID format",
+			"Import ID must be in format 'database_name:view_name' or 'cluster_name:database_name:view_name' or 'database_name:view_uuid'",
+		)
+		return
+	}
+
+	var clusterName *string
+	var databaseName string
+	var viewRef string
+
+	if len(parts) == 3 {
+		clusterName = &parts[0]
+		databaseName = parts[1]
+		viewRef = parts[2]
+	} else {
+		databaseName = parts[0]
+		viewRef = parts[1]
+	}
+
+	// Check if ref is a UUID
+	viewUUID := viewRef
+	_, err := uuid.Parse(viewRef)
+	if err != nil {
+		// Failed parsing UUID, try importing using the view name
+		view, err := r.client.FindMaterializedViewByName(ctx, databaseName, viewRef, clusterName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Cannot find materialized view",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+
+		viewUUID = view.UUID
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("uuid"), viewUUID)...)
+	if clusterName != nil {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cluster_name"), clusterName)...)
+	}
+}
+
+// syncMaterializedViewState reads the materialized view from clickhouse and returns a MaterializedView, keeping the
+// values of plan ClickHouse only reformatted.
+func (r *Resource) syncMaterializedViewState(ctx context.Context, uuid string, clusterName *string, plan *MaterializedView) (*MaterializedView, error) {
+	view, err := r.client.GetMaterializedView(ctx, uuid, clusterName)
+	if err != nil {
+		return nil, errors.WithMessage(err, "cannot get materialized view")
+	}
+
+	if view == nil {
+		// Materialized view not found.
+		return nil, nil
+	}
+
+	state := &MaterializedView{
+		ClusterName:   types.StringPointerValue(clusterName),
+		UUID:          types.StringValue(view.UUID),
+		DatabaseName:  types.StringValue(view.DatabaseName),
+		Name:          types.StringValue(view.Name),
+		ToTableName:   types.StringPointerValue(nonEmpty(view.ToTableName)),
+		Comment:       types.StringPointerValue(nonEmpty(view.Comment)),
+		RefreshStatus: types.StringPointerValue(nonEmpty(view.RefreshStatus)),
+	}
+
+	// ClickHouse always reports the database of the target table, leave it out when it's the one of the view.
+	state.ToDatabaseName = types.StringPointerValue(nonEmpty(view.ToDatabaseName))
+	if plan.ToDatabaseName.IsNull() && view.ToDatabaseName == view.DatabaseName {
+		state.ToDatabaseName = types.StringNull()
+	}
+
+	// The query read back is normalized by ClickHouse, keep the configured one unless importing.
+	state.Query = types.StringValue(view.Query)
+	if !plan.Query.IsNull() {
+		state.Query = plan.Query
+	}
+
+	state.Engine = schemadiff.KeepPlanned(plan.Engine, nonEmpty(view.Engine), schemadiff.SameEngine)
+	state.Refresh = schemadiff.KeepPlanned(plan.Refresh, nonEmpty(view.Refresh), schemadiff.SameExpression)
+	state.Populate = plan.Populate
+
+	return state, nil
+}
+
+// materializedViewFromPlan returns the materialized view to create for the plan.
+func materializedViewFromPlan(plan MaterializedView) dbops.MaterializedView {
+	return dbops.MaterializedView{
+		DatabaseName:   plan.DatabaseName.ValueString(),
+		Name:           plan.Name.ValueString(),
+		Query:          plan.Query.ValueString(),
+		ToDatabaseName: plan.ToDatabaseName.ValueString(),
+		ToTableName:    plan.ToTableName.ValueString(),
+		Engine:         plan.Engine.ValueString(),
+		Refresh:        plan.Refresh.ValueString(),
+		Populate:       plan.Populate.ValueBool(),
+		Comment:        plan.Comment.ValueString(),
+	}
+}
+
+// nonEmpty returns nil for the empty string, so that it is stored as null.
+func nonEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
@@ -0,0 +1,21 @@
+package materializedview
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type MaterializedView struct {
+	ClusterName     types.String `tfsdk:"cluster_name"`
+	UUID            types.String `tfsdk:"uuid"`
+	DatabaseName    types.String `tfsdk:"database_name"`
+	Name            types.String `tfsdk:"name"`
+	Query           types.String `tfsdk:"query"`
+	ToDatabaseName  types.String `tfsdk:"to_database_name"`
+	ToTableName     types.String `tfsdk:"to_table_name"`
+	Engine          types.String `tfsdk:"engine"`
+	Refresh         types.String `tfsdk:"refresh"`
+	Populate        types.Bool   `tfsdk:"populate"`
+	Comment         types.String `tfsdk:"comment"`
+	RefreshStatus   types.String `tfsdk:"refresh_status"`
+	CreateStatement types.String `tfsdk:"create_statement"`
+}
@@ -0,0 +1,17 @@
+package optimizetable
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type OptimizeTable struct {
+	ClusterName  types.String `tfsdk:"cluster_name"`
+	ID           types.String `tfsdk:"id"`
+	DatabaseName types.String `tfsdk:"database_name"`
+	TableName    types.String `tfsdk:"table_name"`
+	Partition    types.String `tfsdk:"partition"`
+	Final        types.Bool   `tfsdk:"final"`
+	Deduplicate  types.Bool   `tfsdk:"deduplicate"`
+	WaitForMerge types.Bool   `tfsdk:"wait_for_merge"`
+	Triggers     types.Map    `tfsdk:"triggers"`
+}
@@ -0,0 +1,15 @@
+package partitionretention
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type PartitionRetention struct {
+	ClusterName  types.String `tfsdk:"cluster_name"`
+	ID           types.String `tfsdk:"id"`
+	DatabaseName types.String `tfsdk:"database_name"`
+	TableName    types.String `tfsdk:"table_name"`
+	OlderThan    types.String `tfsdk:"older_than"`
+	DryRun       types.Bool   `tfsdk:"dry_run"`
+	Partitions   types.List   `tfsdk:"partitions"`
+}
@@ -0,0 +1,259 @@
+package partitionretention
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clustername"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/protecteddatabase"
+)
+
+//go:embed partitionretention.md
+var partitionRetentionResourceDescription string
+
+var (
+	_ resource.Resource               = &Resource{}
+	_ resource.ResourceWithConfigure  = &Resource{}
+	_ resource.ResourceWithModifyPlan = &Resource{}
+)
+
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+type Resource struct {
+	client dbops.Client
+}
+
+func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_partition_retention"
+}
+
+func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster the table lives in. If omitted, partitions are only dropped on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Random identifier of the resource",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"database_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the database containing the table",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"table_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the table to drop partitions from",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"older_than": schema.StringAttribute{
+				Required:    true,
+				Description: "SQL expression returning a DateTime. Partitions whose newest row is older than this are dropped, e.g. `now() - INTERVAL 90 DAY`. The expression is embedded in the query as raw SQL: it must be a single expression, without statement separators or comments",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"dry_run": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "When true, partitions are listed in `partitions` but not dropped. Defaults to false",
+			},
+			"partitions": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "IDs of the partitions dropped by the last apply, or that would have been dropped when `dry_run` is true. During plan, the partitions that will be dropped",
+			},
+		},
+		MarkdownDescription: partitionRetentionResourceDescription,
+	}
+}
+
+func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_partition_retention", "plan")
+
+	if req.Plan.Raw.IsNull() {
+		// If the entire plan is null, the resource is planned for destruction.
+		return
+	}
+
+	clustername.ValidatePlan(ctx, r.client, req.Plan, &resp.Diagnostics)
+
+	var plan PartitionRetention
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	protecteddatabase.Validate(r.client, path.Root("database_name"), plan.DatabaseName, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.client == nil || plan.ClusterName.IsUnknown() || plan.DatabaseName.IsUnknown() || plan.TableName.IsUnknown() || plan.OlderThan.IsUnknown() {
+		// Partitions are computed during apply.
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("partitions"), types.ListUnknown(types.StringType))...)
+		return
+	}
+
+	expired, err := r.client.GetExpiredPartitions(ctx, plan.DatabaseName.ValueString(), plan.TableName.ValueString(), plan.OlderThan.ValueString(), plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Partitions",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	partitions, diags := types.ListValueFrom(ctx, types.StringType, expired)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if len(expired) == 0 && !req.State.Raw.IsNull() {
+		var state PartitionRetention
+		diags = req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// Nothing to drop, keep the result of the previous apply to avoid a diff. Only when the configuration is
+		// unchanged: Update would drop the partitions in the plan, which were computed against the previous one.
+		if plan.OlderThan.Equal(state.OlderThan) && plan.DryRun.Equal(state.DryRun) {
+			partitions = state.Partitions
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("partitions"), partitions)...)
+}
+
+func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(dbops.Client)
+}
+
+func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_partition_retention", "create")
+
+	var plan PartitionRetention
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = types.StringValue(uuid.NewString())
+
+	r.apply(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Nothing to read, partitions are looked up during plan.
+}
+
+func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_partition_retention", "update")
+
+	var plan PartitionRetention
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.apply(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Nothing to undo on the ClickHouse side.
+}
+
+// apply drops the partitions listed in the plan, looking them up first if they were not known at plan time.
+func (r *Resource) apply(ctx context.Context, plan *PartitionRetention, diagnostics *diag.Diagnostics) {
+	var partitions []string
+
+	if plan.Partitions.IsUnknown() {
+		expired, err := r.client.GetExpiredPartitions(ctx, plan.DatabaseName.ValueString(), plan.TableName.ValueString(), plan.OlderThan.ValueString(), plan.ClusterName.ValueStringPointer())
+		if err != nil {
+			diagnostics.AddError(
+				"Error Reading ClickHouse Partitions",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+
+		partitions = expired
+
+		list, diags := types.ListValueFrom(ctx, types.StringType, partitions)
+		diagnostics.Append(diags...)
+		if diagnostics.HasError() {
+			return
+		}
+		plan.Partitions = list
+	} else {
+		diags := plan.Partitions.ElementsAs(ctx, &partitions, false)
+		diagnostics.Append(diags...)
+		if diagnostics.HasError() {
+			return
+		}
+	}
+
+	if plan.DryRun.ValueBool() || len(partitions) == 0 {
+		return
+	}
+
+	err := r.client.DropPartitions(ctx, plan.DatabaseName.ValueString(), plan.TableName.ValueString(), partitions, plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		diagnostics.AddError(
+			"Error Dropping ClickHouse Partitions",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+}
@@ -0,0 +1,31 @@
+package quota
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type Quota struct {
+	AccessStorageMode types.String `tfsdk:"access_storage_mode"`
+	ClusterName       types.String `tfsdk:"cluster_name"`
+	ID                types.String `tfsdk:"id"`
+	Name              types.String `tfsdk:"name"`
+	KeyedBy           types.String `tfsdk:"keyed_by"`
+	Intervals         []Interval   `tfsdk:"intervals"`
+	ApplyTo           types.Set    `tfsdk:"apply_to"`
+	ApplyToAll        types.Bool   `tfsdk:"apply_to_all"`
+	ApplyToExcept     types.Set    `tfsdk:"apply_to_except"`
+}
+
+type Interval struct {
+	Duration         types.String  `tfsdk:"duration"`
+	Randomized       types.Bool    `tfsdk:"randomized"`
+	MaxQueries       types.Int64   `tfsdk:"max_queries"`
+	MaxQuerySelects  types.Int64   `tfsdk:"max_query_selects"`
+	MaxQueryInserts  types.Int64   `tfsdk:"max_query_inserts"`
+	MaxErrors        types.Int64   `tfsdk:"max_errors"`
+	MaxResultRows    types.Int64   `tfsdk:"max_result_rows"`
+	MaxResultBytes   types.Int64   `tfsdk:"max_result_bytes"`
+	MaxReadRows      types.Int64   `tfsdk:"max_read_rows"`
+	MaxReadBytes     types.Int64   `tfsdk:"max_read_bytes"`
+	MaxExecutionTime types.Float64 `tfsdk:"max_execution_time"`
+}
@@ -0,0 +1,651 @@
+package quota
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clustername"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+//go:embed quota.md
+var quotaResourceDescription string
+
+// keyTypes are the supported values of keyed_by.
+var keyTypes = []string{"user_name", "ip_address", "forwarded_ip_address", "client_key", "client_key,user_name", "client_key,ip_address"}
+
+var (
+	_ resource.Resource                   = &Resource{}
+	_ resource.ResourceWithConfigure      = &Resource{}
+	_ resource.ResourceWithImportState    = &Resource{}
+	_ resource.ResourceWithModifyPlan     = &Resource{}
+	_ resource.ResourceWithValidateConfig = &Resource{}
+)
+
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+type Resource struct {
+	client dbops.Client
+}
+
+func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_quota"
+}
+
+func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	maximum := func(description string) schema.Int64Attribute {
+		return schema.Int64Attribute{
+			Optional:    true,
+			Description: description,
+			Validators: []validator.Int64{
+				int64validator.AtLeast(0),
+			},
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"access_storage_mode": schema.StringAttribute{
+				Optional:    true,
+				Description: "How the quota is managed on the cluster set in `cluster_name`: `on_cluster` runs its statements ON CLUSTER, `replicated` runs them on the replica the provider is connected to and relies on replicated access storage to propagate them. Defaults to the provider's `access_storage_mode`, whose `auto` default picks `replicated` when the server uses replicated storage for quotas. Changing it only affects the statements to come.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(dbops.AccessStorageModes...),
+				},
+			},
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to create the resource into. If omitted, resource will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nWhen using a self hosted ClickHouse instance, this field should only be set when there is more than one replica and you are not using 'replicated' storage for user_directory.\n",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The system-assigned ID for the quota",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the quota",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"keyed_by": schema.StringAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("How the consumption is tracked, one of %s. When null, the consumption is shared by every user of the quota.", strings.Join(keyTypes, ", ")),
+				Validators: []validator.String{
+					stringvalidator.OneOf(keyTypes...),
+				},
+			},
+			"intervals": schema.ListNestedAttribute{
+				Optional:    true,
+				Description: "Intervals of the quota, and the maximum consumption allowed during each of them.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"duration": schema.StringAttribute{
+							Required:    true,
+							Description: "Length of the interval, as a duration in whole seconds like `1h`.",
+						},
+						"randomized": schema.BoolAttribute{
+							Optional:    true,
+							Computed:    true,
+							Default:     booldefault.StaticBool(false),
+							Description: "When true, the intervals start at a random time instead of at multiples of their duration. Defaults to false.",
+						},
+						"max_queries":       maximum("Maximum number of queries."),
+						"max_query_selects": maximum("Maximum number of SELECT queries."),
+						"max_query_inserts": maximum("Maximum number of INSERT queries."),
+						"max_errors":        maximum("Maximum number of queries that threw an exception."),
+						"max_result_rows":   maximum("Maximum number of rows returned as result."),
+						"max_result_bytes":  maximum("Maximum size of the results, in bytes."),
+						"max_read_rows":     maximum("Maximum number of rows read from tables to run the queries."),
+						"max_read_bytes":    maximum("Maximum size of the data read from tables to run the queries, in bytes."),
+						"max_execution_time": schema.Float64Attribute{
+							Optional:    true,
+							Description: "Maximum total execution time of the queries, in seconds.",
+							Validators: []validator.Float64{
+								float64validator.AtLeast(0),
+							},
+						},
+					},
+				},
+			},
+			"apply_to": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Names of the users and roles the quota applies to. When neither `apply_to` nor `apply_to_all` is set, the users and roles of the quota are left alone, e.g. for `clickhousedbops_quota_assignment`.",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+					setvalidator.ConflictsWith(path.MatchRoot("apply_to_all")),
+				},
+			},
+			"apply_to_all": schema.BoolAttribute{
+				Optional:    true,
+				Description: "When true, the quota applies to every user and role but the ones of `apply_to_except`.",
+			},
+			"apply_to_except": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Names of the users and roles the quota doesn't apply to, when `apply_to_all` is true.",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+		},
+		MarkdownDescription: quotaResourceDescription,
+	}
+}
+
+func (r *Resource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config Quota
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.ApplyToExcept.IsNull() && !config.ApplyToAll.IsUnknown() && !config.ApplyToAll.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("apply_to_except"),
+			"Invalid Quota Settings",
+			"'apply_to_except' can only be used when 'apply_to_all' is true.",
+		)
+	}
+
+	seen := make(map[string]bool)
+	for idx, interval := range config.Intervals {
+		if interval.Duration.IsUnknown() {
+			continue
+		}
+
+		seconds, err := durationSeconds(interval.Duration.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("intervals").AtListIndex(idx).AtName("duration"),
+				"Invalid Quota Interval",
+				err.Error(),
+			)
+			continue
+		}
+
+		key := fmt.Sprintf("%d/%t", seconds, interval.Randomized.ValueBool())
+		if seen[key] {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("intervals").AtListIndex(idx).AtName("duration"),
+				"Invalid Quota Interval",
+				fmt.Sprintf("Duplicate interval of %s, ClickHouse keeps a single interval per duration.", interval.Duration.ValueString()),
+			)
+		}
+		seen[key] = true
+	}
+}
+
+func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_quota", "plan")
+
+	if req.Plan.Raw.IsNull() {
+		// If the entire plan is null, the resource is planned for destruction.
+		return
+	}
+
+	clustername.ValidatePlan(ctx, r.client, req.Plan, &resp.Diagnostics)
+}
+
+func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(dbops.Client)
+}
+
+func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_quota", "create")
+
+	var plan Quota
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	clusterName, err := r.client.AccessCluster(ctx, plan.ClusterName.ValueStringPointer(), plan.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Creating ClickHouse Quota",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	quota, err := quotaFromPlan(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Creating ClickHouse Quota",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	created, err := r.client.CreateQuota(ctx, quota, clusterName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Creating ClickHouse Quota",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	state, err := syncQuotaState(ctx, created, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Syncing ClickHouse Quota",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_quota", "read")
+
+	var state Quota
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	clusterName, err := r.client.AccessCluster(ctx, state.ClusterName.ValueStringPointer(), state.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Quota",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	quota, err := r.client.GetQuota(ctx, state.ID.ValueString(), clusterName)
+	if dbops.IsRestrictedRead(err) {
+		resp.Diagnostics.AddWarning(
+			"Unable to Refresh ClickHouse Quota",
+			"Not allowed to read the quota, keeping the prior state: "+err.Error(),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Quota",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if quota == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	newState, err := syncQuotaState(ctx, quota, state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Syncing ClickHouse Quota",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, newState)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_quota", "update")
+
+	// The name requires a replacement: the key, the intervals and the grantees are altered in place.
+	var plan, state Quota
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	clusterName, err := r.client.AccessCluster(ctx, plan.ClusterName.ValueStringPointer(), plan.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating ClickHouse Quota",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	quota, err := quotaFromPlan(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating ClickHouse Quota",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+	quota.ID = state.ID.ValueString()
+
+	if quota.ApplyTo == nil && !quota.ApplyToAll && (!state.ApplyTo.IsNull() || !state.ApplyToAll.IsNull()) {
+		// The grantees are no longer managed by the resource: detach the quota from the ones it set.
+		quota.ApplyTo = make([]string, 0)
+	}
+
+	updated, err := r.client.UpdateQuota(ctx, quota, clusterName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating ClickHouse Quota",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if updated == nil {
+		resp.Diagnostics.AddError(
+			"Error Updating ClickHouse Quota",
+			"failed retrieving quota after update",
+		)
+		return
+	}
+
+	newState, err := syncQuotaState(ctx, updated, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Syncing ClickHouse Quota",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, newState)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_quota", "delete")
+
+	var state Quota
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	clusterName, err := r.client.AccessCluster(ctx, state.ClusterName.ValueStringPointer(), state.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Deleting ClickHouse Quota",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	quota, err := r.client.GetQuota(ctx, state.ID.ValueString(), clusterName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Quota",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if quota != nil && dbops.IsReadOnlyStorage(quota.Storage) {
+		// The quota is defined in the server configuration, DROP would always fail: just forget about it.
+		resp.Diagnostics.AddWarning(
+			"ClickHouse Quota Not Dropped",
+			fmt.Sprintf("Quota %q is defined in the %s storage and can't be dropped with SQL statements, it was only removed from the terraform state.", quota.Name, quota.Storage),
+		)
+		return
+	}
+
+	err = r.client.DeleteQuota(ctx, state.ID.ValueString(), clusterName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Deleting ClickHouse Quota",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+}
+
+func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_quota", "import")
+
+	// req.ID can either be in the form <cluster name>:<quota ref> or just <quota ref>
+	// <quota ref> can either be the name or the UUID of the quota.
+
+	ref := req.ID
+	var clusterName *string
+	if strings.Contains(req.ID, ":") {
+		clusterName = &strings.Split(req.ID, ":")[0]
+		ref = strings.Split(req.ID, ":")[1]
+	}
+
+	_, err := uuid.Parse(ref)
+	if err != nil {
+		quota, err := r.client.FindQuotaByName(ctx, ref, clusterName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Cannot find quota",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+
+		if dbops.IsReadOnlyStorage(quota.Storage) {
+			resp.Diagnostics.AddWarning(
+				"ClickHouse Quota Defined In Configuration",
+				fmt.Sprintf("Quota %q is defined in the %s storage: it can be referenced by other resources but can't be changed or dropped with SQL statements.", quota.Name, quota.Storage),
+			)
+		}
+
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), quota.ID)...)
+	} else {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), ref)...)
+	}
+
+	if clusterName != nil {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cluster_name"), clusterName)...)
+	}
+}
+
+// durationSeconds parses the duration of an interval, which must be a positive number of seconds.
+func durationSeconds(duration string) (uint64, error) {
+	d, err := time.ParseDuration(duration)
+	if err != nil || d < time.Second || d%time.Second != 0 {
+		return 0, errors.New(fmt.Sprintf("%q is not a duration in whole seconds like \"1h\".", duration))
+	}
+
+	return uint64(d / time.Second), nil
+}
+
+// quotaFromPlan returns the quota to create or alter for the plan. Its ApplyTo is nil when the plan leaves the users
+// and roles of the quota alone.
+func quotaFromPlan(ctx context.Context, plan Quota) (dbops.Quota, error) {
+	quota := dbops.Quota{
+		Name:       plan.Name.ValueString(),
+		KeyedBy:    plan.KeyedBy.ValueStringPointer(),
+		Intervals:  make([]dbops.QuotaInterval, 0, len(plan.Intervals)),
+		ApplyToAll: plan.ApplyToAll.ValueBool(),
+	}
+
+	for _, interval := range plan.Intervals {
+		seconds, err := durationSeconds(interval.Duration.ValueString())
+		if err != nil {
+			return quota, err
+		}
+
+		quota.Intervals = append(quota.Intervals, dbops.QuotaInterval{
+			Duration:         seconds,
+			Randomized:       interval.Randomized.ValueBool(),
+			MaxQueries:       uint64Pointer(interval.MaxQueries),
+			MaxQuerySelects:  uint64Pointer(interval.MaxQuerySelects),
+			MaxQueryInserts:  uint64Pointer(interval.MaxQueryInserts),
+			MaxErrors:        uint64Pointer(interval.MaxErrors),
+			MaxResultRows:    uint64Pointer(interval.MaxResultRows),
+			MaxResultBytes:   uint64Pointer(interval.MaxResultBytes),
+			MaxReadRows:      uint64Pointer(interval.MaxReadRows),
+			MaxReadBytes:     uint64Pointer(interval.MaxReadBytes),
+			MaxExecutionTime: interval.MaxExecutionTime.ValueFloat64Pointer(),
+		})
+	}
+
+	grantees := plan.ApplyTo
+	if quota.ApplyToAll {
+		grantees = plan.ApplyToExcept
+	}
+	if !grantees.IsNull() || quota.ApplyToAll {
+		quota.ApplyTo = make([]string, 0)
+	}
+	if !grantees.IsNull() {
+		diags := grantees.ElementsAs(ctx, &quota.ApplyTo, false)
+		if diags.HasError() {
+			return quota, errors.New("cannot read the users and roles of the quota")
+		}
+	}
+
+	return quota, nil
+}
+
+// syncQuotaState returns the state for the quota read from ClickHouse. Intervals keep the planned order and duration
+// format, and the users and roles are only synced when the plan manages them.
+func syncQuotaState(ctx context.Context, quota *dbops.Quota, plan Quota) (*Quota, error) {
+	state := &Quota{
+		AccessStorageMode: plan.AccessStorageMode,
+		ClusterName:       plan.ClusterName,
+		ID:                types.StringValue(quota.ID),
+		Name:              types.StringValue(quota.Name),
+		KeyedBy:           types.StringPointerValue(quota.KeyedBy),
+		ApplyTo:           types.SetNull(types.StringType),
+		ApplyToAll:        plan.ApplyToAll,
+		ApplyToExcept:     types.SetNull(types.StringType),
+	}
+
+	if len(quota.Intervals) > 0 || plan.Intervals != nil {
+		state.Intervals = syncIntervals(quota.Intervals, plan.Intervals)
+	}
+
+	if plan.ApplyTo.IsNull() && plan.ApplyToAll.IsNull() {
+		// The users and roles of the quota are not managed here.
+		return state, nil
+	}
+
+	if quota.ApplyToAll || !plan.ApplyToAll.IsNull() {
+		state.ApplyToAll = types.BoolValue(quota.ApplyToAll)
+	}
+
+	grantees, diags := types.SetValueFrom(ctx, types.StringType, quota.ApplyTo)
+	if diags.HasError() {
+		return nil, errors.New("cannot convert the users and roles of the quota")
+	}
+
+	planned := plan.ApplyTo
+	if quota.ApplyToAll {
+		planned = plan.ApplyToExcept
+	}
+	if len(quota.ApplyTo) > 0 || !planned.IsNull() {
+		if quota.ApplyToAll {
+			state.ApplyToExcept = grantees
+		} else {
+			state.ApplyTo = grantees
+		}
+	}
+
+	return state, nil
+}
+
+// syncIntervals returns the intervals read from ClickHouse in the planned order, followed by the ones the plan
+// doesn't have. The planned duration is kept when it is the same as the actual one.
+func syncIntervals(actual []dbops.QuotaInterval, planned []Interval) []Interval {
+	ret := make([]Interval, 0, len(actual))
+	used := make([]bool, len(actual))
+
+	for _, p := range planned {
+		seconds, err := durationSeconds(p.Duration.ValueString())
+		if err != nil {
+			continue
+		}
+		for idx, a := range actual {
+			if !used[idx] && a.Duration == seconds && a.Randomized == p.Randomized.ValueBool() {
+				used[idx] = true
+				ret = append(ret, intervalState(a, p.Duration))
+				break
+			}
+		}
+	}
+
+	for idx, a := range actual {
+		if !used[idx] {
+			ret = append(ret, intervalState(a, types.StringValue((time.Duration(a.Duration)*time.Second).String())))
+		}
+	}
+
+	return ret
+}
+
+func intervalState(interval dbops.QuotaInterval, duration types.String) Interval {
+	return Interval{
+		Duration:         duration,
+		Randomized:       types.BoolValue(interval.Randomized),
+		MaxQueries:       int64Value(interval.MaxQueries),
+		MaxQuerySelects:  int64Value(interval.MaxQuerySelects),
+		MaxQueryInserts:  int64Value(interval.MaxQueryInserts),
+		MaxErrors:        int64Value(interval.MaxErrors),
+		MaxResultRows:    int64Value(interval.MaxResultRows),
+		MaxResultBytes:   int64Value(interval.MaxResultBytes),
+		MaxReadRows:      int64Value(interval.MaxReadRows),
+		MaxReadBytes:     int64Value(interval.MaxReadBytes),
+		MaxExecutionTime: types.Float64PointerValue(interval.MaxExecutionTime),
+	}
+}
+
+func uint64Pointer(v types.Int64) *uint64 {
+	if v.IsNull() || v.IsUnknown() {
+		return nil
+	}
+
+	u := uint64(v.ValueInt64())
+	return &u
+}
+
+func int64Value(v *uint64) types.Int64 {
+	if v == nil {
+		return types.Int64Null()
+	}
+
+	return types.Int64Value(int64(*v))
+}
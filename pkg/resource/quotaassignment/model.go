@@ -0,0 +1,12 @@
+package quotaassignment
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type QuotaAssignment struct {
+	ClusterName     types.String `tfsdk:"cluster_name"`
+	QuotaName       types.String `tfsdk:"quota_name"`
+	GranteeUserName types.String `tfsdk:"grantee_user_name"`
+	GranteeRoleName types.String `tfsdk:"grantee_role_name"`
+}
@@ -0,0 +1,13 @@
+package reloaddictionary
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type ReloadDictionary struct {
+	ClusterName    types.String `tfsdk:"cluster_name"`
+	ID             types.String `tfsdk:"id"`
+	DatabaseName   types.String `tfsdk:"database_name"`
+	DictionaryName types.String `tfsdk:"dictionary_name"`
+	Triggers       types.Map    `tfsdk:"triggers"`
+}
@@ -5,7 +5,10 @@ import (
 )
 
 type Role struct {
-	ClusterName types.String `tfsdk:"cluster_name"`
-	ID          types.String `tfsdk:"id"`
-	Name        types.String `tfsdk:"name"`
+	AccessStorageMode types.String `tfsdk:"access_storage_mode"`
+	ClusterName       types.String `tfsdk:"cluster_name"`
+	GrantedRoles      types.Set    `tfsdk:"granted_roles"`
+	ID                types.String `tfsdk:"id"`
+	Name              types.String `tfsdk:"name"`
+	SettingsProfiles  types.List   `tfsdk:"settings_profiles"`
 }
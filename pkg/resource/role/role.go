@@ -7,13 +7,19 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clustername"
 	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
 )
 
@@ -42,6 +48,13 @@ func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, res
 func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
+			"access_storage_mode": schema.StringAttribute{
+				Optional:    true,
+				Description: "How the role is managed on the cluster set in `cluster_name`: `on_cluster` runs its statements ON CLUSTER, `replicated` runs them on the replica the provider is connected to and relies on replicated access storage to propagate them. Defaults to the provider's `access_storage_mode`, whose `auto` default picks `replicated` when the server uses replicated storage for roles. Changing it only affects the statements to come.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(dbops.AccessStorageModes...),
+				},
+			},
 			"cluster_name": schema.StringAttribute{
 				Optional:    true,
 				Description: "Name of the cluster to create the resource into. If omitted, resource will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nWhen using a self hosted ClickHouse instance, this field should only be set when there is more than one replica and you are not using 'replicated' storage for user_directory.\n",
@@ -49,6 +62,14 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"granted_roles": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Names of the roles granted to this role, whose privileges it inherits. Roles granted to the role outside of this attribute are revoked, so it must not be combined with `clickhousedbops_grant_role` resources granting roles to the same role. Set to an empty set to revoke them all, leave null to not manage them.",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
 			"id": schema.StringAttribute{
 				Computed:    true,
 				Description: "The system-assigned ID for the role",
@@ -60,19 +81,43 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"settings_profiles": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Names of the settings profiles the role inherits from, in order: the settings of a profile override the ones of the profiles before it. Replaces every other setting and profile of the role, so it must not be combined with `clickhousedbops_settings_profile_assignment` resources for the same role. Set to an empty list to remove them all, leave null to not manage them.",
+				Validators: []validator.List{
+					listvalidator.UniqueValues(),
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
 		},
 		MarkdownDescription: roleResourceDescription,
 	}
 }
 
 func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_role", "plan")
+
 	if req.Plan.Raw.IsNull() {
 		// If the entire plan is null, the resource is planned for destruction.
 		return
 	}
 
+	clustername.ValidatePlan(ctx, r.client, req.Plan, &resp.Diagnostics)
+
 	if r.client != nil {
-		isReplicatedStorage, err := r.client.IsReplicatedStorage(ctx)
+		var config Role
+		diags := req.Config.Get(ctx, &config)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if config.ClusterName.IsNull() || config.ClusterName.IsUnknown() || config.AccessStorageMode.IsUnknown() {
+			return
+		}
+
+		clusterName, err := r.client.AccessCluster(ctx, config.ClusterName.ValueStringPointer(), config.AccessStorageMode.ValueString())
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error Checking if service is using replicated storage",
@@ -81,21 +126,11 @@ func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanReques
 			return
 		}
 
-		if isReplicatedStorage {
-			var config Role
-			diags := req.Config.Get(ctx, &config)
-			resp.Diagnostics.Append(diags...)
-			if resp.Diagnostics.HasError() {
-				return
-			}
-
-			// Role cannot specify 'cluster_name' or apply will fail.
-			if !config.ClusterName.IsNull() {
-				resp.Diagnostics.AddWarning(
-					"Invalid configuration",
-					"Your ClickHouse cluster is using Replicated storage for roles, please remove the 'cluster_name' attribute from your Role resource definition if you encounter any errors.",
-				)
-			}
+		if clusterName == nil && config.AccessStorageMode.ValueString() != dbops.AccessStorageModeReplicated {
+			resp.Diagnostics.AddWarning(
+				"Cluster Name Not Used",
+				"Your ClickHouse cluster seems to be using Replicated storage for roles, the role is managed on the replica the provider is connected to and ClickHouse replicates it rather than running ON CLUSTER. Set 'access_storage_mode' to \"on_cluster\" if some replicas of the cluster use a different access storage.",
+			)
 		}
 	}
 }
@@ -109,6 +144,8 @@ func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _
 }
 
 func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_role", "create")
+
 	var plan Role
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -116,7 +153,16 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 		return
 	}
 
-	createdRole, err := r.client.CreateRole(ctx, dbops.Role{Name: plan.Name.ValueString()}, plan.ClusterName.ValueStringPointer())
+	clusterName, err := r.client.AccessCluster(ctx, plan.ClusterName.ValueStringPointer(), plan.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Creating ClickHouse Role",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	createdRole, err := r.client.CreateRole(ctx, dbops.Role{Name: plan.Name.ValueString()}, clusterName)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Creating ClickHouse Role",
@@ -126,9 +172,20 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 	}
 
 	state := Role{
-		ClusterName: plan.ClusterName,
-		ID:          types.StringValue(createdRole.ID),
-		Name:        types.StringValue(createdRole.Name),
+		AccessStorageMode: plan.AccessStorageMode,
+		ClusterName:       plan.ClusterName,
+		GrantedRoles:      plan.GrantedRoles,
+		ID:                types.StringValue(createdRole.ID),
+		Name:              types.StringValue(createdRole.Name),
+		SettingsProfiles:  plan.SettingsProfiles,
+	}
+
+	// The role exists at this point: a failure keeps it in the state, to be tainted.
+	if !plan.SettingsProfiles.IsNull() {
+		r.setSettingsProfiles(ctx, plan.SettingsProfiles, createdRole.Name, clusterName, &resp.Diagnostics)
+	}
+	if !plan.GrantedRoles.IsNull() && !resp.Diagnostics.HasError() {
+		r.updateGrantedRoles(ctx, types.SetValueMust(types.StringType, nil), plan.GrantedRoles, createdRole.Name, clusterName, &resp.Diagnostics)
 	}
 
 	diags = resp.State.Set(ctx, state)
@@ -139,6 +196,8 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 }
 
 func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_role", "read")
+
 	var state Role
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -146,7 +205,23 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 		return
 	}
 
-	role, err := r.client.GetRole(ctx, state.ID.ValueString(), state.ClusterName.ValueStringPointer())
+	clusterName, err := r.client.AccessCluster(ctx, state.ClusterName.ValueStringPointer(), state.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Role",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	role, err := r.client.GetRole(ctx, state.ID.ValueString(), clusterName)
+	if dbops.IsRestrictedRead(err) {
+		resp.Diagnostics.AddWarning(
+			"Unable to Refresh ClickHouse Role",
+			"Not allowed to read the role, keeping the prior state: "+err.Error(),
+		)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading ClickHouse Role",
@@ -158,6 +233,31 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 	if role != nil {
 		state.Name = types.StringValue(role.Name)
 
+		if !state.SettingsProfiles.IsNull() {
+			profileNames, err := r.client.GetSettingsProfiles(ctx, nil, &role.Name, clusterName)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error Reading ClickHouse Role Settings Profiles",
+					fmt.Sprintf("%+v\n", err),
+				)
+				return
+			}
+
+			settingsProfiles, diags := types.ListValueFrom(ctx, types.StringType, profileNames)
+			resp.Diagnostics.Append(diags...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			state.SettingsProfiles = settingsProfiles
+		}
+
+		if !state.GrantedRoles.IsNull() {
+			state.GrantedRoles = r.readGrantedRoles(ctx, role.Name, clusterName, &resp.Diagnostics)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+
 		diags = resp.State.Set(ctx, &state)
 		resp.Diagnostics.Append(diags...)
 	} else {
@@ -166,10 +266,64 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 }
 
 func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	panic("Update of role resource is not supported")
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_role", "update")
+
+	// Every other attribute requires a replacement: only access_storage_mode, which affects future statements only,
+	// and settings_profiles can change here.
+	var plan, state Role
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.AccessStorageMode = plan.AccessStorageMode
+
+	clusterName, err := r.client.AccessCluster(ctx, plan.ClusterName.ValueStringPointer(), plan.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating ClickHouse Role",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	// A null list stops managing the profiles and leaves them as they are.
+	if !plan.SettingsProfiles.IsNull() && !plan.SettingsProfiles.Equal(state.SettingsProfiles) {
+		r.setSettingsProfiles(ctx, plan.SettingsProfiles, state.Name.ValueString(), clusterName, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	state.SettingsProfiles = plan.SettingsProfiles
+
+	// Likewise for the granted roles.
+	if !plan.GrantedRoles.IsNull() && !plan.GrantedRoles.Equal(state.GrantedRoles) {
+		current := state.GrantedRoles
+		if current.IsNull() {
+			// Starting to manage the grants: revoke the roles granted outside of the attribute.
+			current = r.readGrantedRoles(ctx, state.Name.ValueString(), clusterName, &resp.Diagnostics)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+
+		r.updateGrantedRoles(ctx, current, plan.GrantedRoles, state.Name.ValueString(), clusterName, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	state.GrantedRoles = plan.GrantedRoles
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
 }
 
 func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_role", "delete")
+
 	var state Role
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -177,7 +331,34 @@ func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp
 		return
 	}
 
-	err := r.client.DeleteRole(ctx, state.ID.ValueString(), state.ClusterName.ValueStringPointer())
+	clusterName, err := r.client.AccessCluster(ctx, state.ClusterName.ValueStringPointer(), state.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Deleting ClickHouse Role",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	role, err := r.client.GetRole(ctx, state.ID.ValueString(), clusterName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Role",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if role != nil && dbops.IsReadOnlyStorage(role.Storage) {
+		// The role is defined in the server configuration, DROP would always fail: just forget about it.
+		resp.Diagnostics.AddWarning(
+			"ClickHouse Role Not Dropped",
+			fmt.Sprintf("Role %q is defined in the %s storage and can't be dropped with SQL statements, it was only removed from the terraform state.", role.Name, role.Storage),
+		)
+		return
+	}
+
+	err = r.client.DeleteRole(ctx, state.ID.ValueString(), clusterName)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting ClickHouse Role",
@@ -188,6 +369,8 @@ func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp
 }
 
 func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_role", "import")
+
 	// req.ID can either be in the form <cluster name>:<role ref> or just <role ref>
 	// <role ref> can either be the name or the UUID of the role.
 
@@ -212,6 +395,13 @@ func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequ
 			return
 		}
 
+		if dbops.IsReadOnlyStorage(role.Storage) {
+			resp.Diagnostics.AddWarning(
+				"ClickHouse Role Defined In Configuration",
+				fmt.Sprintf("Role %q is defined in the %s storage: it can be referenced by other resources but can't be changed or dropped with SQL statements.", role.Name, role.Storage),
+			)
+		}
+
 		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), role.ID)...)
 	} else {
 		// User passed a UUID
@@ -222,3 +412,92 @@ func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequ
 		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cluster_name"), clusterName)...)
 	}
 }
+
+// setSettingsProfiles makes the role inherit from the given profiles, in order.
+func (r *Resource) setSettingsProfiles(ctx context.Context, settingsProfiles types.List, roleName string, clusterName *string, diagnostics *diag.Diagnostics) {
+	profileNames := make([]string, 0)
+	diagnostics.Append(settingsProfiles.ElementsAs(ctx, &profileNames, false)...)
+	if diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.SetSettingsProfiles(ctx, profileNames, nil, &roleName, clusterName)
+	if err != nil {
+		diagnostics.AddError(
+			"Error Setting ClickHouse Role Settings Profiles",
+			fmt.Sprintf("%+v\n", err),
+		)
+	}
+}
+
+// readGrantedRoles returns the names of the roles granted to the role.
+func (r *Resource) readGrantedRoles(ctx context.Context, roleName string, clusterName *string, diagnostics *diag.Diagnostics) types.Set {
+	grants, err := r.client.GetGranteeGrants(ctx, nil, &roleName, clusterName)
+	if err != nil {
+		diagnostics.AddError(
+			"Error Reading ClickHouse Role Grants",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return types.SetNull(types.StringType)
+	}
+
+	grantedRoleNames := make([]string, 0, len(grants.Roles))
+	for _, g := range grants.Roles {
+		grantedRoleNames = append(grantedRoleNames, g.RoleName)
+	}
+
+	grantedRoles, diags := types.SetValueFrom(ctx, types.StringType, grantedRoleNames)
+	diagnostics.Append(diags...)
+	return grantedRoles
+}
+
+// updateGrantedRoles grants the roles in planned missing from current to the role, and revokes the ones in current
+// missing from planned.
+func (r *Resource) updateGrantedRoles(ctx context.Context, current types.Set, planned types.Set, roleName string, clusterName *string, diagnostics *diag.Diagnostics) {
+	currentNames := make([]string, 0)
+	diagnostics.Append(current.ElementsAs(ctx, &currentNames, false)...)
+	plannedNames := make([]string, 0)
+	diagnostics.Append(planned.ElementsAs(ctx, &plannedNames, false)...)
+	if diagnostics.HasError() {
+		return
+	}
+
+	isCurrent := make(map[string]bool)
+	for _, name := range currentNames {
+		isCurrent[name] = true
+	}
+	isPlanned := make(map[string]bool)
+	for _, name := range plannedNames {
+		isPlanned[name] = true
+	}
+
+	for _, name := range plannedNames {
+		if isCurrent[name] {
+			continue
+		}
+
+		_, err := r.client.GrantRole(ctx, dbops.GrantRole{RoleName: name, GranteeRoleName: &roleName}, clusterName)
+		if err != nil {
+			diagnostics.AddError(
+				"Error Granting ClickHouse Role",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+	}
+
+	for _, name := range currentNames {
+		if isPlanned[name] {
+			continue
+		}
+
+		err := r.client.RevokeGrantRole(ctx, name, nil, &roleName, clusterName)
+		if err != nil {
+			diagnostics.AddError(
+				"Error Revoking ClickHouse Role",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+	}
+}
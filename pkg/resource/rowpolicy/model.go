@@ -0,0 +1,19 @@
+package rowpolicy
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type RowPolicy struct {
+	AccessStorageMode types.String `tfsdk:"access_storage_mode"`
+	ClusterName       types.String `tfsdk:"cluster_name"`
+	ID                types.String `tfsdk:"id"`
+	Name              types.String `tfsdk:"name"`
+	DatabaseName      types.String `tfsdk:"database_name"`
+	TableName         types.String `tfsdk:"table_name"`
+	Condition         types.String `tfsdk:"condition"`
+	Restrictive       types.Bool   `tfsdk:"restrictive"`
+	ApplyTo           types.Set    `tfsdk:"apply_to"`
+	ApplyToAll        types.Bool   `tfsdk:"apply_to_all"`
+	ApplyToExcept     types.Set    `tfsdk:"apply_to_except"`
+}
@@ -0,0 +1,501 @@
+package rowpolicy
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clustername"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/schemadiff"
+)
+
+//go:embed rowpolicy.md
+var rowPolicyResourceDescription string
+
+var (
+	_ resource.Resource                   = &Resource{}
+	_ resource.ResourceWithConfigure      = &Resource{}
+	_ resource.ResourceWithImportState    = &Resource{}
+	_ resource.ResourceWithModifyPlan     = &Resource{}
+	_ resource.ResourceWithValidateConfig = &Resource{}
+)
+
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+type Resource struct {
+	client dbops.Client
+}
+
+func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_row_policy"
+}
+
+func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"access_storage_mode": schema.StringAttribute{
+				Optional:    true,
+				Description: "How the row policy is managed on the cluster set in `cluster_name`: `on_cluster` runs its statements ON CLUSTER, `replicated` runs them on the replica the provider is connected to and relies on replicated access storage to propagate them. Defaults to the provider's `access_storage_mode`, whose `auto` default picks `replicated` when the server uses replicated storage for row policies. Changing it only affects the statements to come.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(dbops.AccessStorageModes...),
+				},
+			},
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to create the resource into. If omitted, resource will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nWhen using a self hosted ClickHouse instance, this field should only be set when there is more than one replica and you are not using 'replicated' storage for user_directory.\n",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The system-assigned ID for the row policy",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the row policy, unique per table",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"database_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the database of the table the policy filters",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"table_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the table the policy filters",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"condition": schema.StringAttribute{
+				Required:    true,
+				Description: "Expression the rows returned to the users and roles of the policy match, e.g. `tenant_id = 'a'`",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"restrictive": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "When true, the policy is RESTRICTIVE: rows must match it on top of the permissive policies of the table. Defaults to false (PERMISSIVE).",
+			},
+			"apply_to": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Names of the users and roles the policy applies to.",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+					setvalidator.ConflictsWith(path.MatchRoot("apply_to_all")),
+				},
+			},
+			"apply_to_all": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "When true, the policy applies to every user and role but the ones of `apply_to_except`. Defaults to false.",
+			},
+			"apply_to_except": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Names of the users and roles the policy doesn't apply to, when `apply_to_all` is true.",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+		},
+		MarkdownDescription: rowPolicyResourceDescription,
+	}
+}
+
+func (r *Resource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config RowPolicy
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.ApplyToExcept.IsNull() && !config.ApplyToAll.IsUnknown() && !config.ApplyToAll.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("apply_to_except"),
+			"Invalid Row Policy Settings",
+			"'apply_to_except' can only be used when 'apply_to_all' is true.",
+		)
+	}
+}
+
+func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_row_policy", "plan")
+
+	if req.Plan.Raw.IsNull() {
+		// If the entire plan is null, the resource is planned for destruction.
+		return
+	}
+
+	clustername.ValidatePlan(ctx, r.client, req.Plan, &resp.Diagnostics)
+}
+
+func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(dbops.Client)
+}
+
+func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_row_policy", "create")
+
+	var plan RowPolicy
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	clusterName, err := r.client.AccessCluster(ctx, plan.ClusterName.ValueStringPointer(), plan.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Creating ClickHouse Row Policy",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	policy, err := rowPolicyFromPlan(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Creating ClickHouse Row Policy",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	created, err := r.client.CreateRowPolicy(ctx, policy, clusterName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Creating ClickHouse Row Policy",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	state, err := syncRowPolicyState(ctx, created, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Syncing ClickHouse Row Policy",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_row_policy", "read")
+
+	var state RowPolicy
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	clusterName, err := r.client.AccessCluster(ctx, state.ClusterName.ValueStringPointer(), state.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Row Policy",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	policy, err := r.client.GetRowPolicy(ctx, state.ID.ValueString(), clusterName)
+	if dbops.IsRestrictedRead(err) {
+		resp.Diagnostics.AddWarning(
+			"Unable to Refresh ClickHouse Row Policy",
+			"Not allowed to read the row policy, keeping the prior state: "+err.Error(),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Row Policy",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if policy == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	newState, err := syncRowPolicyState(ctx, policy, state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Syncing ClickHouse Row Policy",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, newState)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_row_policy", "update")
+
+	// The name and the table require a replacement: the condition, the kind and the grantees are altered in place.
+	var plan, state RowPolicy
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	clusterName, err := r.client.AccessCluster(ctx, plan.ClusterName.ValueStringPointer(), plan.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating ClickHouse Row Policy",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	policy, err := rowPolicyFromPlan(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating ClickHouse Row Policy",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+	policy.ID = state.ID.ValueString()
+
+	updated, err := r.client.UpdateRowPolicy(ctx, policy, clusterName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating ClickHouse Row Policy",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if updated == nil {
+		resp.Diagnostics.AddError(
+			"Error Updating ClickHouse Row Policy",
+			"failed retrieving row policy after update",
+		)
+		return
+	}
+
+	newState, err := syncRowPolicyState(ctx, updated, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Syncing ClickHouse Row Policy",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, newState)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_row_policy", "delete")
+
+	var state RowPolicy
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	clusterName, err := r.client.AccessCluster(ctx, state.ClusterName.ValueStringPointer(), state.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Deleting ClickHouse Row Policy",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	policy, err := r.client.GetRowPolicy(ctx, state.ID.ValueString(), clusterName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Row Policy",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if policy != nil && dbops.IsReadOnlyStorage(policy.Storage) {
+		// The policy is defined in the server configuration, DROP would always fail: just forget about it.
+		resp.Diagnostics.AddWarning(
+			"ClickHouse Row Policy Not Dropped",
+			fmt.Sprintf("Row policy %q is defined in the %s storage and can't be dropped with SQL statements, it was only removed from the terraform state.", policy.Name, policy.Storage),
+		)
+		return
+	}
+
+	err = r.client.DeleteRowPolicy(ctx, state.ID.ValueString(), clusterName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Deleting ClickHouse Row Policy",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+}
+
+func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_row_policy", "import")
+
+	// req.ID can either be <policy uuid>, <cluster name>:<policy uuid>, <database name>:<table name>:<policy name>
+	// or <cluster name>:<database name>:<table name>:<policy name>.
+
+	parts := strings.Split(req.ID, ":")
+	var clusterName *string
+	switch len(parts) {
+	case 2, 4:
+		clusterName = &parts[0]
+		parts = parts[1:]
+	case 1, 3:
+	default:
+		resp.Diagnostics.AddError(
+			"Invalid import ID format",
+			"Import ID must be in format 'policy_uuid', 'cluster_name:policy_uuid', 'database_name:table_name:policy_name' or 'cluster_name:database_name:table_name:policy_name'",
+		)
+		return
+	}
+
+	id := parts[0]
+	if len(parts) == 3 {
+		policy, err := r.client.FindRowPolicyByName(ctx, parts[2], parts[0], parts[1], clusterName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Cannot find row policy",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+
+		id = policy.ID
+	} else if _, err := uuid.Parse(id); err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid import ID format",
+			fmt.Sprintf("%q is not a UUID, use 'database_name:table_name:policy_name' to import a row policy by name.", id),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	if clusterName != nil {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cluster_name"), clusterName)...)
+	}
+}
+
+// rowPolicyFromPlan returns the row policy to create or alter for the plan.
+func rowPolicyFromPlan(ctx context.Context, plan RowPolicy) (dbops.RowPolicy, error) {
+	policy := dbops.RowPolicy{
+		Name:         plan.Name.ValueString(),
+		DatabaseName: plan.DatabaseName.ValueString(),
+		TableName:    plan.TableName.ValueString(),
+		Condition:    plan.Condition.ValueString(),
+		Restrictive:  plan.Restrictive.ValueBool(),
+		ApplyToAll:   plan.ApplyToAll.ValueBool(),
+		ApplyTo:      make([]string, 0),
+	}
+
+	grantees := plan.ApplyTo
+	if policy.ApplyToAll {
+		grantees = plan.ApplyToExcept
+	}
+	if !grantees.IsNull() {
+		diags := grantees.ElementsAs(ctx, &policy.ApplyTo, false)
+		if diags.HasError() {
+			return policy, errors.New("cannot read the users and roles of the policy")
+		}
+	}
+
+	return policy, nil
+}
+
+// syncRowPolicyState returns the state for the row policy read from ClickHouse, keeping the planned condition when
+// ClickHouse only reformatted it and the planned null sets when the policy applies to no one.
+func syncRowPolicyState(ctx context.Context, policy *dbops.RowPolicy, plan RowPolicy) (*RowPolicy, error) {
+	state := &RowPolicy{
+		AccessStorageMode: plan.AccessStorageMode,
+		ClusterName:       plan.ClusterName,
+		ID:                types.StringValue(policy.ID),
+		Name:              types.StringValue(policy.Name),
+		DatabaseName:      types.StringValue(policy.DatabaseName),
+		TableName:         types.StringValue(policy.TableName),
+		Condition:         schemadiff.KeepPlanned(plan.Condition, &policy.Condition, schemadiff.SameExpression),
+		Restrictive:       types.BoolValue(policy.Restrictive),
+		ApplyTo:           types.SetNull(types.StringType),
+		ApplyToAll:        types.BoolValue(policy.ApplyToAll),
+		ApplyToExcept:     types.SetNull(types.StringType),
+	}
+
+	grantees, diags := types.SetValueFrom(ctx, types.StringType, policy.ApplyTo)
+	if diags.HasError() {
+		return nil, errors.New("cannot convert the users and roles of the policy")
+	}
+
+	planned := plan.ApplyTo
+	if policy.ApplyToAll {
+		planned = plan.ApplyToExcept
+	}
+	if len(policy.ApplyTo) > 0 || !planned.IsNull() {
+		if policy.ApplyToAll {
+			state.ApplyToExcept = grantees
+		} else {
+			state.ApplyTo = grantees
+		}
+	}
+
+	return state, nil
+}
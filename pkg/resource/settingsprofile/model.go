@@ -0,0 +1,24 @@
+package settingsprofile
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type SettingsProfile struct {
+	AccessStorageMode types.String       `tfsdk:"access_storage_mode"`
+	ClusterName       types.String       `tfsdk:"cluster_name"`
+	ID                types.String       `tfsdk:"id"`
+	Name              types.String       `tfsdk:"name"`
+	Settings          map[string]Setting `tfsdk:"settings"`
+	InheritProfiles   types.List         `tfsdk:"inherit_profiles"`
+	ApplyTo           types.Set          `tfsdk:"apply_to"`
+	ApplyToAll        types.Bool         `tfsdk:"apply_to_all"`
+	ApplyToExcept     types.Set          `tfsdk:"apply_to_except"`
+}
+
+type Setting struct {
+	Value    types.String `tfsdk:"value"`
+	Min      types.String `tfsdk:"min"`
+	Max      types.String `tfsdk:"max"`
+	Readonly types.Bool   `tfsdk:"readonly"`
+}
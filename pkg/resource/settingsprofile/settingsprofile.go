@@ -0,0 +1,542 @@
+package settingsprofile
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clustername"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/schemadiff"
+)
+
+//go:embed settingsprofile.md
+var settingsProfileResourceDescription string
+
+var (
+	_ resource.Resource                   = &Resource{}
+	_ resource.ResourceWithConfigure      = &Resource{}
+	_ resource.ResourceWithImportState    = &Resource{}
+	_ resource.ResourceWithModifyPlan     = &Resource{}
+	_ resource.ResourceWithValidateConfig = &Resource{}
+)
+
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+type Resource struct {
+	client dbops.Client
+}
+
+func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_settings_profile"
+}
+
+func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"access_storage_mode": schema.StringAttribute{
+				Optional:    true,
+				Description: "How the settings profile is managed on the cluster set in `cluster_name`: `on_cluster` runs its statements ON CLUSTER, `replicated` runs them on the replica the provider is connected to and relies on replicated access storage to propagate them. Defaults to the provider's `access_storage_mode`, whose `auto` default picks `replicated` when the server uses replicated storage for settings profiles. Changing it only affects the statements to come.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(dbops.AccessStorageModes...),
+				},
+			},
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to create the resource into. If omitted, resource will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nWhen using a self hosted ClickHouse instance, this field should only be set when there is more than one replica and you are not using 'replicated' storage for user_directory.\n",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The system-assigned ID for the settings profile",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the settings profile",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"settings": schema.MapNestedAttribute{
+				Optional:    true,
+				Description: "Settings of the profile, keyed by setting name.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"value": schema.StringAttribute{
+							Optional:    true,
+							Description: "Value of the setting, as a SQL literal: string values must be quoted, e.g. `'random'`.",
+						},
+						"min": schema.StringAttribute{
+							Optional:    true,
+							Description: "Minimum value users of the profile can set, as a SQL literal.",
+						},
+						"max": schema.StringAttribute{
+							Optional:    true,
+							Description: "Maximum value users of the profile can set, as a SQL literal.",
+						},
+						"readonly": schema.BoolAttribute{
+							Optional:    true,
+							Computed:    true,
+							Default:     booldefault.StaticBool(false),
+							Description: "When true, users of the profile can't change the setting. Defaults to false.",
+						},
+					},
+				},
+			},
+			"inherit_profiles": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Names of the settings profiles this one inherits from, in the order they are applied. The settings of the profile override the inherited ones.",
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"apply_to": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Names of the users and roles the profile applies to.",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+					setvalidator.ConflictsWith(path.MatchRoot("apply_to_all")),
+				},
+			},
+			"apply_to_all": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "When true, the profile applies to every user and role but the ones of `apply_to_except`. Defaults to false.",
+			},
+			"apply_to_except": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Names of the users and roles the profile doesn't apply to, when `apply_to_all` is true.",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+		},
+		MarkdownDescription: settingsProfileResourceDescription,
+	}
+}
+
+func (r *Resource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config SettingsProfile
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.ApplyToExcept.IsNull() && !config.ApplyToAll.IsUnknown() && !config.ApplyToAll.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("apply_to_except"),
+			"Invalid Settings Profile Settings",
+			"'apply_to_except' can only be used when 'apply_to_all' is true.",
+		)
+	}
+
+	for name, setting := range config.Settings {
+		if setting.Value.IsNull() && setting.Min.IsNull() && setting.Max.IsNull() && !setting.Readonly.IsUnknown() && !setting.Readonly.ValueBool() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("settings").AtMapKey(name),
+				"Invalid Settings Profile Settings",
+				fmt.Sprintf("Setting %q needs at least one of 'value', 'min' or 'max', or 'readonly' set to true.", name),
+			)
+		}
+	}
+}
+
+func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_settings_profile", "plan")
+
+	if req.Plan.Raw.IsNull() {
+		// If the entire plan is null, the resource is planned for destruction.
+		return
+	}
+
+	clustername.ValidatePlan(ctx, r.client, req.Plan, &resp.Diagnostics)
+}
+
+func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(dbops.Client)
+}
+
+func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_settings_profile", "create")
+
+	var plan SettingsProfile
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	clusterName, err := r.client.AccessCluster(ctx, plan.ClusterName.ValueStringPointer(), plan.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Creating ClickHouse Settings Profile",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	profile, err := settingsProfileFromPlan(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Creating ClickHouse Settings Profile",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	created, err := r.client.CreateSettingsProfile(ctx, profile, clusterName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Creating ClickHouse Settings Profile",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	state, err := syncSettingsProfileState(ctx, created, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Syncing ClickHouse Settings Profile",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_settings_profile", "read")
+
+	var state SettingsProfile
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	clusterName, err := r.client.AccessCluster(ctx, state.ClusterName.ValueStringPointer(), state.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Settings Profile",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	profile, err := r.client.GetSettingsProfile(ctx, state.ID.ValueString(), clusterName)
+	if dbops.IsRestrictedRead(err) {
+		resp.Diagnostics.AddWarning(
+			"Unable to Refresh ClickHouse Settings Profile",
+			"Not allowed to read the settings profile, keeping the prior state: "+err.Error(),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Settings Profile",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if profile == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	newState, err := syncSettingsProfileState(ctx, profile, state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Syncing ClickHouse Settings Profile",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, newState)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_settings_profile", "update")
+
+	// The name requires a replacement: the settings, the inherited profiles and the grantees are altered in place.
+	var plan, state SettingsProfile
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	clusterName, err := r.client.AccessCluster(ctx, plan.ClusterName.ValueStringPointer(), plan.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating ClickHouse Settings Profile",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	profile, err := settingsProfileFromPlan(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating ClickHouse Settings Profile",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+	profile.ID = state.ID.ValueString()
+
+	updated, err := r.client.UpdateSettingsProfile(ctx, profile, clusterName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating ClickHouse Settings Profile",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if updated == nil {
+		resp.Diagnostics.AddError(
+			"Error Updating ClickHouse Settings Profile",
+			"failed retrieving settings profile after update",
+		)
+		return
+	}
+
+	newState, err := syncSettingsProfileState(ctx, updated, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Syncing ClickHouse Settings Profile",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, newState)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_settings_profile", "delete")
+
+	var state SettingsProfile
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	clusterName, err := r.client.AccessCluster(ctx, state.ClusterName.ValueStringPointer(), state.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Deleting ClickHouse Settings Profile",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	profile, err := r.client.GetSettingsProfile(ctx, state.ID.ValueString(), clusterName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Settings Profile",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if profile != nil && dbops.IsReadOnlyStorage(profile.Storage) {
+		// The profile is defined in the server configuration, DROP would always fail: just forget about it.
+		resp.Diagnostics.AddWarning(
+			"ClickHouse Settings Profile Not Dropped",
+			fmt.Sprintf("Settings profile %q is defined in the %s storage and can't be dropped with SQL statements, it was only removed from the terraform state.", profile.Name, profile.Storage),
+		)
+		return
+	}
+
+	err = r.client.DeleteSettingsProfile(ctx, state.ID.ValueString(), clusterName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Deleting ClickHouse Settings Profile",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+}
+
+func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_settings_profile", "import")
+
+	// req.ID can either be in the form <cluster name>:<profile ref> or just <profile ref>
+	// <profile ref> can either be the name or the UUID of the settings profile.
+
+	ref := req.ID
+	var clusterName *string
+	if strings.Contains(req.ID, ":") {
+		clusterName = &strings.Split(req.ID, ":")[0]
+		ref = strings.Split(req.ID, ":")[1]
+	}
+
+	_, err := uuid.Parse(ref)
+	if err != nil {
+		profile, err := r.client.FindSettingsProfileByName(ctx, ref, clusterName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Cannot find settings profile",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+
+		if dbops.IsReadOnlyStorage(profile.Storage) {
+			resp.Diagnostics.AddWarning(
+				"ClickHouse Settings Profile Defined In Configuration",
+				fmt.Sprintf("Settings profile %q is defined in the %s storage: it can be referenced by other resources but can't be changed or dropped with SQL statements.", profile.Name, profile.Storage),
+			)
+		}
+
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), profile.ID)...)
+	} else {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), ref)...)
+	}
+
+	if clusterName != nil {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cluster_name"), clusterName)...)
+	}
+}
+
+// settingsProfileFromPlan returns the settings profile to create or alter for the plan.
+func settingsProfileFromPlan(ctx context.Context, plan SettingsProfile) (dbops.SettingsProfile, error) {
+	profile := dbops.SettingsProfile{
+		Name:            plan.Name.ValueString(),
+		Settings:        make([]dbops.SettingsProfileSetting, 0, len(plan.Settings)),
+		InheritProfiles: make([]string, 0),
+		ApplyToAll:      plan.ApplyToAll.ValueBool(),
+		ApplyTo:         make([]string, 0),
+	}
+
+	for name, setting := range plan.Settings {
+		profile.Settings = append(profile.Settings, dbops.SettingsProfileSetting{
+			Name:     name,
+			Value:    setting.Value.ValueStringPointer(),
+			Min:      setting.Min.ValueStringPointer(),
+			Max:      setting.Max.ValueStringPointer(),
+			Readonly: setting.Readonly.ValueBool(),
+		})
+	}
+
+	if !plan.InheritProfiles.IsNull() {
+		diags := plan.InheritProfiles.ElementsAs(ctx, &profile.InheritProfiles, false)
+		if diags.HasError() {
+			return profile, errors.New("cannot read the inherited profiles")
+		}
+	}
+
+	grantees := plan.ApplyTo
+	if profile.ApplyToAll {
+		grantees = plan.ApplyToExcept
+	}
+	if !grantees.IsNull() {
+		diags := grantees.ElementsAs(ctx, &profile.ApplyTo, false)
+		if diags.HasError() {
+			return profile, errors.New("cannot read the users and roles of the profile")
+		}
+	}
+
+	return profile, nil
+}
+
+// syncSettingsProfileState returns the state for the settings profile read from ClickHouse, keeping the planned
+// setting values ClickHouse reports without their quotes, and the planned null attributes when there is nothing to
+// set them to.
+func syncSettingsProfileState(ctx context.Context, profile *dbops.SettingsProfile, plan SettingsProfile) (*SettingsProfile, error) {
+	state := &SettingsProfile{
+		AccessStorageMode: plan.AccessStorageMode,
+		ClusterName:       plan.ClusterName,
+		ID:                types.StringValue(profile.ID),
+		Name:              types.StringValue(profile.Name),
+		Settings:          plan.Settings,
+		InheritProfiles:   types.ListNull(types.StringType),
+		ApplyTo:           types.SetNull(types.StringType),
+		ApplyToAll:        types.BoolValue(profile.ApplyToAll),
+		ApplyToExcept:     types.SetNull(types.StringType),
+	}
+
+	if len(profile.Settings) > 0 || plan.Settings != nil {
+		state.Settings = make(map[string]Setting, len(profile.Settings))
+		for _, setting := range profile.Settings {
+			planned := plan.Settings[setting.Name]
+			state.Settings[setting.Name] = Setting{
+				Value:    schemadiff.KeepPlanned(planned.Value, setting.Value, schemadiff.SameSettingValue),
+				Min:      schemadiff.KeepPlanned(planned.Min, setting.Min, schemadiff.SameSettingValue),
+				Max:      schemadiff.KeepPlanned(planned.Max, setting.Max, schemadiff.SameSettingValue),
+				Readonly: types.BoolValue(setting.Readonly),
+			}
+		}
+	}
+
+	if len(profile.InheritProfiles) > 0 || !plan.InheritProfiles.IsNull() {
+		inheritProfiles, diags := types.ListValueFrom(ctx, types.StringType, profile.InheritProfiles)
+		if diags.HasError() {
+			return nil, errors.New("cannot convert the inherited profiles")
+		}
+		state.InheritProfiles = inheritProfiles
+	}
+
+	grantees, diags := types.SetValueFrom(ctx, types.StringType, profile.ApplyTo)
+	if diags.HasError() {
+		return nil, errors.New("cannot convert the users and roles of the profile")
+	}
+
+	planned := plan.ApplyTo
+	if profile.ApplyToAll {
+		planned = plan.ApplyToExcept
+	}
+	if len(profile.ApplyTo) > 0 || !planned.IsNull() {
+		if profile.ApplyToAll {
+			state.ApplyToExcept = grantees
+		} else {
+			state.ApplyTo = grantees
+		}
+	}
+
+	return state, nil
+}
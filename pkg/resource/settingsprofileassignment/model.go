@@ -0,0 +1,12 @@
+package settingsprofileassignment
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type SettingsProfileAssignment struct {
+	ClusterName     types.String `tfsdk:"cluster_name"`
+	ProfileName     types.String `tfsdk:"profile_name"`
+	GranteeUserName types.String `tfsdk:"grantee_user_name"`
+	GranteeRoleName types.String `tfsdk:"grantee_role_name"`
+}
@@ -0,0 +1,31 @@
+package shardedtable
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type ShardedTable struct {
+	ClusterName    types.String `tfsdk:"cluster_name"`
+	DatabaseName   types.String `tfsdk:"database_name"`
+	Name           types.String `tfsdk:"name"`
+	LocalTableName types.String `tfsdk:"local_table_name"`
+	UUID           types.String `tfsdk:"uuid"`
+	LocalTableUUID types.String `tfsdk:"local_table_uuid"`
+	Columns        []Column     `tfsdk:"columns"`
+	Engine         types.String `tfsdk:"engine"`
+	ShardingKey    types.String `tfsdk:"sharding_key"`
+	OrderBy        types.List   `tfsdk:"order_by"`
+	PartitionBy    types.String `tfsdk:"partition_by"`
+	PrimaryKey     types.List   `tfsdk:"primary_key"`
+	TTL            types.String `tfsdk:"ttl"`
+	Settings       types.Map    `tfsdk:"settings"`
+	Comment        types.String `tfsdk:"comment"`
+	AllowDrops     types.Bool   `tfsdk:"allow_drops"`
+}
+
+type Column struct {
+	Name    types.String `tfsdk:"name"`
+	Type    types.String `tfsdk:"type"`
+	Default types.String `tfsdk:"default"`
+	Comment types.String `tfsdk:"comment"`
+}
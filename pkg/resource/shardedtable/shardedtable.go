@@ -0,0 +1,452 @@
+package shardedtable
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clustername"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/comment"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/protecteddatabase"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+//go:embed shardedtable.md
+var shardedTableResourceDescription string
+
+// localTableSuffix is appended to the name of the Distributed table to name the local tables by default.
+const localTableSuffix = "_local"
+
+var mergeTreeEngineRegexp = regexp.MustCompile(`^\s*\w*MergeTree\b`)
+
+var (
+	_ resource.Resource               = &Resource{}
+	_ resource.ResourceWithConfigure  = &Resource{}
+	_ resource.ResourceWithModifyPlan = &Resource{}
+)
+
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+type Resource struct {
+	client dbops.Client
+}
+
+func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sharded_table"
+}
+
+func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the cluster to create the local tables on, and to spread the rows of the Distributed table across",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"database_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the database containing both tables",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the Distributed table",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"local_table_name": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Name of the local tables. Defaults to `name` with a `_local` suffix",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"uuid": schema.StringAttribute{
+				Computed:    true,
+				Description: "The system-assigned UUID of the Distributed table",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"local_table_uuid": schema.StringAttribute{
+				Computed:    true,
+				Description: "The system-assigned UUID of the local tables",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"columns": schema.ListNestedAttribute{
+				Required:    true,
+				Description: "List of columns of both tables",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Required:    true,
+							Description: "Column name",
+						},
+						"type": schema.StringAttribute{
+							Required:    true,
+							Description: "Column data type (e.g., UInt64, String, DateTime)",
+						},
+						"default": schema.StringAttribute{
+							Optional:    true,
+							Description: "Default value or expression for the column",
+						},
+						"comment": schema.StringAttribute{
+							Optional:    true,
+							Description: "Column comment",
+						},
+					},
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"engine": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "MergeTree family engine of the local tables, e.g. `ReplicatedReplacingMergeTree(version)`. Defaults to `ReplicatedMergeTree`",
+				Default:     stringdefault.StaticString("ReplicatedMergeTree"),
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(mergeTreeEngineRegexp, "must be a MergeTree family engine"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"sharding_key": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Expression choosing the shard each row inserted in the Distributed table is written to, e.g. `cityHash64(user_id)`. Defaults to `rand()`",
+				Default:     stringdefault.StaticString("rand()"),
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"order_by": schema.ListAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+				Description: "ORDER BY clause columns of the local tables",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"partition_by": schema.StringAttribute{
+				Optional:    true,
+				Description: "PARTITION BY expression of the local tables",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"primary_key": schema.ListAttribute{
+				Optional:    true,
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "PRIMARY KEY columns of the local tables",
+				Default:     listdefault.StaticValue(types.ListValueMust(types.StringType, []attr.Value{})),
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"ttl": schema.StringAttribute{
+				Optional:    true,
+				Description: "TTL expression of the local tables",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"settings": schema.MapAttribute{
+				Optional:    true,
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Table-level settings of the local tables",
+				Default:     mapdefault.StaticValue(types.MapValueMust(types.StringType, map[string]attr.Value{})),
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"comment": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Comment associated with both tables",
+				Default:     stringdefault.StaticString(""),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"allow_drops": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Allow dropping the tables. When set to false (default), attempts to delete or recreate the tables will fail as a safety measure.",
+				Default:     booldefault.StaticBool(false),
+			},
+		},
+		MarkdownDescription: shardedTableResourceDescription,
+	}
+}
+
+func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_sharded_table", "plan")
+
+	if req.Plan.Raw.IsNull() {
+		// If the entire plan is null, the resource is planned for destruction.
+		return
+	}
+
+	clustername.ValidatePlan(ctx, r.client, req.Plan, &resp.Diagnostics)
+
+	var plan ShardedTable
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	protecteddatabase.Validate(r.client, path.Root("database_name"), plan.DatabaseName, &resp.Diagnostics)
+	comment.Validate(r.client, path.Root("comment"), plan.Comment, &resp.Diagnostics)
+	for i, col := range plan.Columns {
+		comment.Validate(r.client, path.Root("columns").AtListIndex(i).AtName("comment"), col.Comment, &resp.Diagnostics)
+	}
+
+	// Show the default name of the local tables in the plan rather than (known after apply).
+	if plan.LocalTableName.IsUnknown() && !plan.Name.IsUnknown() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("local_table_name"), plan.Name.ValueString()+localTableSuffix)...)
+	}
+}
+
+func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(dbops.Client)
+}
+
+func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_sharded_table", "create")
+
+	var plan ShardedTable
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	columns := make([]querybuilder.TableColumn, len(plan.Columns))
+	for i, col := range plan.Columns {
+		columns[i] = querybuilder.TableColumn{
+			Name:    col.Name.ValueString(),
+			Type:    col.Type.ValueString(),
+			Default: col.Default.ValueStringPointer(),
+			Comment: col.Comment.ValueStringPointer(),
+		}
+	}
+
+	orderBy := []string{}
+	diags = plan.OrderBy.ElementsAs(ctx, &orderBy, false)
+	resp.Diagnostics.Append(diags...)
+
+	primaryKey := []string{}
+	if !plan.PrimaryKey.IsNull() {
+		diags = plan.PrimaryKey.ElementsAs(ctx, &primaryKey, false)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	settings := make(map[string]string)
+	if !plan.Settings.IsNull() {
+		diags = plan.Settings.ElementsAs(ctx, &settings, false)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	clusterName := plan.ClusterName.ValueString()
+	localTableName := plan.LocalTableName.ValueString()
+
+	// The Distributed table points to the local tables, which are dropped again if it can't be created so that the
+	// next apply doesn't fail creating them.
+	localStep := fmt.Sprintf("local table %q", localTableName)
+	var local, distributed *dbops.Table
+	err := dbops.ExecuteDDL(ctx, []dbops.DDLStep{
+		{
+			Name: localStep,
+			Apply: func(ctx context.Context) (err error) {
+				local, err = r.client.CreateTable(ctx, dbops.Table{
+					DatabaseName: plan.DatabaseName.ValueString(),
+					Name:         localTableName,
+					Engine:       plan.Engine.ValueString(),
+					Columns:      columns,
+					OrderBy:      orderBy,
+					PartitionBy:  plan.PartitionBy.ValueStringPointer(),
+					PrimaryKey:   primaryKey,
+					TTL:          plan.TTL.ValueStringPointer(),
+					Settings:     settings,
+					Comment:      plan.Comment.ValueString(),
+				}, &clusterName)
+				return err
+			},
+			Rollback: func(ctx context.Context) error {
+				return r.client.DeleteTable(ctx, local.UUID, &clusterName)
+			},
+		},
+		{
+			Name:      fmt.Sprintf("Distributed table %q", plan.Name.ValueString()),
+			DependsOn: []string{localStep},
+			Apply: func(ctx context.Context) (err error) {
+				distributed, err = r.client.CreateTable(ctx, dbops.Table{
+					DatabaseName: plan.DatabaseName.ValueString(),
+					Name:         plan.Name.ValueString(),
+					Engine:       querybuilder.DistributedEngine(clusterName, plan.DatabaseName.ValueString(), localTableName, plan.ShardingKey.ValueString()),
+					Columns:      columns,
+					Comment:      plan.Comment.ValueString(),
+				}, &clusterName)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating sharded table",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	plan.UUID = types.StringValue(distributed.UUID)
+	plan.LocalTableUUID = types.StringValue(local.UUID)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_sharded_table", "read")
+
+	var state ShardedTable
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tables, err := r.client.GetTables(ctx, []string{state.UUID.ValueString(), state.LocalTableUUID.ValueString()}, state.ClusterName.ValueStringPointer())
+	if dbops.IsRestrictedRead(err) {
+		resp.Diagnostics.AddWarning(
+			"Unable to Refresh ClickHouse Sharded Table",
+			"Not allowed to read the tables, keeping the prior state: "+err.Error(),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading tables",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	distributed := tables[state.UUID.ValueString()]
+	local := tables[state.LocalTableUUID.ValueString()]
+	if distributed == nil || local == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.Name = types.StringValue(distributed.Name)
+	state.LocalTableName = types.StringValue(local.Name)
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Every other attribute requires a replacement: only allow_drops can change here.
+	var plan, state ShardedTable
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.AllowDrops = plan.AllowDrops
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_sharded_table", "delete")
+
+	var state ShardedTable
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !state.AllowDrops.ValueBool() {
+		resp.Diagnostics.AddError(
+			"Table deletion not allowed",
+			fmt.Sprintf("Cannot delete sharded table '%s' because 'allow_drops' is set to false. To allow table deletion, set 'allow_drops = true' in your sharded table configuration.", state.Name.ValueString()),
+		)
+		return
+	}
+
+	// The Distributed table goes first, so that it never points to missing local tables.
+	err := r.client.DeleteTable(ctx, state.UUID.ValueString(), state.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting Distributed table",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	err = r.client.DeleteTable(ctx, state.LocalTableUUID.ValueString(), state.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting local table",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+}
@@ -0,0 +1,14 @@
+package syncreplica
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type SyncReplica struct {
+	ClusterName  types.String `tfsdk:"cluster_name"`
+	ID           types.String `tfsdk:"id"`
+	DatabaseName types.String `tfsdk:"database_name"`
+	TableName    types.String `tfsdk:"table_name"`
+	Timeout      types.String `tfsdk:"timeout"`
+	Triggers     types.Map    `tfsdk:"triggers"`
+}
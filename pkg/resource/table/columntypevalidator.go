@@ -0,0 +1,359 @@
+package table
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/schemadiff"
+)
+
+// simpleAggregateFunctions are the functions allowed in SimpleAggregateFunction types: the ones whose result can be
+// merged by applying the function again.
+var simpleAggregateFunctions = []string{
+	"any", "anyLast", "min", "max", "sum", "sumWithOverflow",
+	"groupBitAnd", "groupBitOr", "groupBitXor",
+	"groupArrayArray", "groupUniqArrayArray", "groupArrayLastArray",
+	"sumMap", "minMap", "maxMap",
+}
+
+var aggregateFunctionNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// checkColumnType returns an error describing why an AggregateFunction or SimpleAggregateFunction column type is
+// invalid, or nil. Other types are left to ClickHouse.
+func checkColumnType(t string) error {
+	name, args, hasArgs := schemadiff.SplitTypeArguments(strings.TrimSpace(t))
+	if name != "AggregateFunction" && name != "SimpleAggregateFunction" {
+		return nil
+	}
+
+	if !hasArgs || strings.TrimSpace(args) == "" {
+		return fmt.Errorf("%s requires the aggregate function, e.g. %s(sum, UInt64)", name, name)
+	}
+
+	parts := schemadiff.SplitTopLevel(args)
+	if name == "AggregateFunction" && isUnsignedInteger(parts[0]) {
+		parts = parts[1:]
+		if len(parts) == 0 {
+			return fmt.Errorf("AggregateFunction requires the aggregate function after the version")
+		}
+	}
+
+	function, _, _ := schemadiff.SplitTypeArguments(parts[0])
+	if !aggregateFunctionNameRegexp.MatchString(function) {
+		return fmt.Errorf("invalid aggregate function %q", parts[0])
+	}
+	for _, arg := range parts[1:] {
+		if arg == "" {
+			return fmt.Errorf("empty argument type in %s", t)
+		}
+	}
+
+	if name == "SimpleAggregateFunction" {
+		if !slices.ContainsFunc(simpleAggregateFunctions, func(f string) bool { return strings.EqualFold(f, function) }) {
+			return fmt.Errorf("%s can't be used in SimpleAggregateFunction, use one of %s or an AggregateFunction column", function, strings.Join(simpleAggregateFunctions, ", "))
+		}
+		if len(parts) != 2 {
+			return fmt.Errorf("SimpleAggregateFunction requires exactly one argument type, e.g. SimpleAggregateFunction(%s, UInt64)", function)
+		}
+	}
+
+	return nil
+}
+
+var (
+	typeNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*( [a-zA-Z_][a-zA-Z0-9_]*)*$`)
+	integerRegexp  = regexp.MustCompile(`^-?[0-9]+$`)
+)
+
+// decimalMaxScale is the largest scale of each fixed-size Decimal type.
+var decimalMaxScale = map[string]int{"Decimal32": 9, "Decimal64": 18, "Decimal128": 38, "Decimal256": 76}
+
+// checkTypeSyntax returns an error describing why a column type is malformed, or nil. Only the syntax of the types
+// taking other types or well-known parameters (Nullable, LowCardinality, Array, Map, Tuple, Decimal, Enum, ...) is
+// checked, type names are left to ClickHouse.
+func checkTypeSyntax(t string) error {
+	t = strings.TrimSpace(t)
+	if t == "" {
+		return fmt.Errorf("empty type")
+	}
+	if err := checkBalanced(t); err != nil {
+		return fmt.Errorf("%s: %w", t, err)
+	}
+
+	name, args, hasArgs := schemadiff.SplitTypeArguments(t)
+	if !typeNameRegexp.MatchString(name) {
+		return fmt.Errorf("%s is not a type", t)
+	}
+	if !hasArgs {
+		return nil
+	}
+
+	parts := schemadiff.SplitTopLevel(args)
+	switch {
+	case strings.EqualFold(name, "Nullable"), strings.EqualFold(name, "LowCardinality"), strings.EqualFold(name, "Array"):
+		if len(parts) != 1 || parts[0] == "" {
+			return fmt.Errorf("%s takes exactly one type, e.g. %s(String)", name, name)
+		}
+		if strings.EqualFold(name, "Nullable") {
+			inner, _, _ := schemadiff.SplitTypeArguments(parts[0])
+			if slices.ContainsFunc([]string{"Nullable", "Array", "Map"}, func(n string) bool { return strings.EqualFold(n, inner) }) {
+				return fmt.Errorf("%s can't be inside Nullable", parts[0])
+			}
+		}
+		return checkTypeSyntax(parts[0])
+	case strings.EqualFold(name, "Map"):
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("Map takes a key and a value type, e.g. Map(String, UInt64)")
+		}
+		for _, part := range parts {
+			if err := checkTypeSyntax(part); err != nil {
+				return err
+			}
+		}
+	case strings.EqualFold(name, "Tuple"), strings.EqualFold(name, "Nested"), strings.EqualFold(name, "Variant"):
+		for _, part := range parts {
+			if part == "" {
+				return fmt.Errorf("empty element type in %s", t)
+			}
+			// Named elements, e.g. Tuple(id UInt64, name String).
+			if _, typ := schemadiff.SplitPathType(part); typ != "" && !strings.Contains(part[:len(part)-len(typ)], "(") {
+				part = typ
+			}
+			if err := checkTypeSyntax(part); err != nil {
+				return err
+			}
+		}
+	case strings.EqualFold(name, "Decimal"):
+		if len(parts) > 2 || !integerRegexp.MatchString(parts[0]) || (len(parts) == 2 && !integerRegexp.MatchString(parts[1])) {
+			return fmt.Errorf("Decimal takes a precision and a scale, e.g. Decimal(18, 4)")
+		}
+		precision, _ := strconv.Atoi(parts[0])
+		if precision < 1 || precision > 76 {
+			return fmt.Errorf("the precision of %s must be between 1 and 76", t)
+		}
+		if len(parts) == 2 {
+			if scale, _ := strconv.Atoi(parts[1]); scale < 0 || scale > precision {
+				return fmt.Errorf("the scale of %s must be between 0 and its precision", t)
+			}
+		}
+	case decimalMaxScale[name] > 0:
+		if len(parts) != 1 || !integerRegexp.MatchString(parts[0]) {
+			return fmt.Errorf("%s takes a scale, e.g. %s(4)", name, name)
+		}
+		if scale, _ := strconv.Atoi(parts[0]); scale < 0 || scale > decimalMaxScale[name] {
+			return fmt.Errorf("the scale of %s must be between 0 and %d", t, decimalMaxScale[name])
+		}
+	case strings.EqualFold(name, "FixedString"):
+		if n, err := strconv.Atoi(strings.TrimSpace(args)); err != nil || n < 1 {
+			return fmt.Errorf("FixedString takes a positive length, e.g. FixedString(16)")
+		}
+	case strings.EqualFold(name, "DateTime64"):
+		if precision, err := strconv.Atoi(parts[0]); err != nil || precision < 0 || precision > 9 {
+			return fmt.Errorf("the precision of %s must be between 0 and 9", t)
+		}
+	case strings.EqualFold(name, "Enum"), strings.EqualFold(name, "Enum8"), strings.EqualFold(name, "Enum16"):
+		return checkEnumValues(name, parts)
+	}
+
+	return nil
+}
+
+// checkEnumValues checks the 'name' = value elements of an Enum type. Values may be left out, ClickHouse numbers them.
+func checkEnumValues(enum string, elements []string) error {
+	minValue, maxValue := -32768, 32767
+	if strings.EqualFold(enum, "Enum8") {
+		minValue, maxValue = -128, 127
+	}
+
+	names := make(map[string]bool)
+	values := make(map[int]bool)
+	for _, element := range elements {
+		name, rest, ok := splitStringLiteral(element)
+		if !ok {
+			return fmt.Errorf("%s values must be quoted strings, e.g. %s('active' = 1), got %q", enum, enum, element)
+		}
+		if names[name] {
+			return fmt.Errorf("%s value %s is declared twice", enum, element[:len(element)-len(rest)])
+		}
+		names[name] = true
+
+		rest = strings.TrimSpace(rest)
+		if rest == "" {
+			continue
+		}
+		value, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(rest, "=")))
+		if !strings.HasPrefix(rest, "=") || err != nil {
+			return fmt.Errorf("%s values must be numbered with an integer, e.g. %s('active' = 1), got %q", enum, enum, element)
+		}
+		if value < minValue || value > maxValue {
+			return fmt.Errorf("%s numbers must be between %d and %d, got %q", enum, minValue, maxValue, element)
+		}
+		if values[value] {
+			return fmt.Errorf("%s number %d is used twice", enum, value)
+		}
+		values[value] = true
+	}
+
+	return nil
+}
+
+// splitStringLiteral splits s into the value of the single-quoted string it starts with and what follows it.
+func splitStringLiteral(s string) (string, string, bool) {
+	if !strings.HasPrefix(s, "'") {
+		return "", "", false
+	}
+
+	var sb strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			i++
+			sb.WriteByte(s[i])
+		case s[i] == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++
+			sb.WriteByte('\'')
+		case s[i] == '\'':
+			return sb.String(), s[i+1:], true
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+
+	return "", "", false
+}
+
+// checkBalanced returns an error when the parentheses or quotes of t aren't balanced.
+func checkBalanced(t string) error {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(t); i++ {
+		c := t[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth < 0 {
+				return fmt.Errorf("unexpected closing parenthesis")
+			}
+		}
+	}
+
+	switch {
+	case quote != 0:
+		return fmt.Errorf("unterminated quote")
+	case depth > 0:
+		return fmt.Errorf("missing closing parenthesis")
+	}
+
+	return nil
+}
+
+// lowCardinalityUnsupported are the types ClickHouse can't wrap in LowCardinality.
+var lowCardinalityUnsupported = []string{
+	"Array", "Map", "Tuple", "Nested", "JSON", "Object", "Dynamic", "Variant",
+	"Decimal", "Decimal32", "Decimal64", "Decimal128", "Decimal256", "Enum", "Enum8", "Enum16",
+	"AggregateFunction", "SimpleAggregateFunction", "LowCardinality",
+}
+
+// lowCardinalityIssues returns the LowCardinality wrappings of t, at any depth, that fail or are likely mistakes.
+func lowCardinalityIssues(t string) []string {
+	name, args, hasArgs := schemadiff.SplitTypeArguments(strings.TrimSpace(t))
+	if !hasArgs {
+		return nil
+	}
+
+	var issues []string
+	switch {
+	case strings.EqualFold(name, "Nullable"):
+		if inner, innerArgs, ok := schemadiff.SplitTypeArguments(strings.TrimSpace(args)); ok && strings.EqualFold(inner, "LowCardinality") {
+			issues = append(issues, fmt.Sprintf("%s is not allowed inside Nullable, write LowCardinality(Nullable(%s)) instead.", strings.TrimSpace(args), strings.TrimSpace(innerArgs)))
+		}
+	case strings.EqualFold(name, "LowCardinality"):
+		inner := strings.TrimSpace(args)
+		if n, a, ok := schemadiff.SplitTypeArguments(inner); ok && strings.EqualFold(n, "Nullable") {
+			inner = strings.TrimSpace(a)
+		}
+		innerName, _, _ := schemadiff.SplitTypeArguments(inner)
+		switch {
+		case innerName == "String" || innerName == "FixedString":
+		case slices.ContainsFunc(lowCardinalityUnsupported, func(u string) bool { return strings.EqualFold(u, innerName) }):
+			issues = append(issues, fmt.Sprintf("%s can't be wrapped in LowCardinality, only String and FixedString can, along with numbers and dates when allow_suspicious_low_cardinality_types is enabled.", inner))
+		default:
+			issues = append(issues, fmt.Sprintf("LowCardinality(%s) is rejected unless the allow_suspicious_low_cardinality_types setting is enabled: LowCardinality is meant for strings, on %s it usually makes queries slower than the plain type.", inner, inner))
+		}
+	}
+
+	for _, part := range schemadiff.SplitTopLevel(args) {
+		// Named elements of tuples, e.g. Tuple(name LowCardinality(String)).
+		if _, typ := schemadiff.SplitPathType(part); typ != "" && !strings.Contains(part[:len(part)-len(typ)], "(") {
+			part = typ
+		}
+		issues = append(issues, lowCardinalityIssues(part)...)
+	}
+
+	return issues
+}
+
+// columnTypeValidator rejects malformed column types at plan time, rather than when creating the table: types whose
+// syntax is wrong, and aggregate function types, e.g. of the target table of a materialized view using an
+// AggregatingMergeTree engine. It also warns about LowCardinality wrappings ClickHouse is likely to reject.
+type columnTypeValidator struct{}
+
+func (v columnTypeValidator) Description(_ context.Context) string {
+	return "Checks the syntax of column types, the function and arguments of AggregateFunction and SimpleAggregateFunction types, and the types wrapped in LowCardinality"
+}
+
+func (v columnTypeValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v columnTypeValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if err := checkTypeSyntax(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Column Type",
+			err.Error(),
+		)
+		return
+	}
+
+	if err := checkColumnType(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Column Type",
+			err.Error(),
+		)
+	}
+
+	for _, issue := range lowCardinalityIssues(req.ConfigValue.ValueString()) {
+		resp.Diagnostics.AddAttributeWarning(
+			req.Path,
+			"Suspicious LowCardinality Column Type",
+			issue,
+		)
+	}
+}
+
+func isUnsignedInteger(s string) bool {
+	_, err := strconv.ParseUint(s, 10, 64)
+	return err == nil
+}
@@ -0,0 +1,102 @@
+package table
+
+import (
+	"testing"
+)
+
+func Test_checkColumnType(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       string
+		wantErr bool
+	}{
+		{name: "Other type", t: "String", wantErr: false},
+		{name: "AggregateFunction", t: "AggregateFunction(uniqIf, UInt64, UInt8)", wantErr: false},
+		{name: "Parametric AggregateFunction", t: "AggregateFunction(quantiles(0.5, 0.9), Float64)", wantErr: false},
+		{name: "AggregateFunction without arguments", t: "AggregateFunction(count)", wantErr: false},
+		{name: "AggregateFunction with version", t: "AggregateFunction(1, sumMap, Array(UInt8), Array(UInt64))", wantErr: false},
+		{name: "AggregateFunction without function", t: "AggregateFunction()", wantErr: true},
+		{name: "AggregateFunction with invalid function", t: "AggregateFunction('sum', UInt64)", wantErr: true},
+		{name: "SimpleAggregateFunction", t: "SimpleAggregateFunction(anyLast, Nullable(String))", wantErr: false},
+		{name: "SimpleAggregateFunction case-insensitive", t: "SimpleAggregateFunction(SUM, UInt64)", wantErr: false},
+		{name: "SimpleAggregateFunction with unsupported function", t: "SimpleAggregateFunction(uniq, UInt64)", wantErr: true},
+		{name: "SimpleAggregateFunction without type", t: "SimpleAggregateFunction(max)", wantErr: true},
+		{name: "SimpleAggregateFunction with two types", t: "SimpleAggregateFunction(sumMap, Array(UInt8), Array(UInt64))", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkColumnType(tt.t); (err != nil) != tt.wantErr {
+				t.Errorf("checkColumnType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkTypeSyntax(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       string
+		wantErr bool
+	}{
+		{name: "Simple type", t: "UInt64", wantErr: false},
+		{name: "SQL alias", t: "DOUBLE PRECISION", wantErr: false},
+		{name: "Nested wrappers", t: "Array(LowCardinality(Nullable(String)))", wantErr: false},
+		{name: "Map", t: "Map(String, Array(Tuple(UInt64, Nullable(Float64))))", wantErr: false},
+		{name: "Named tuple", t: "Tuple(id UInt64, at DateTime64(3, 'UTC'))", wantErr: false},
+		{name: "Decimal", t: "Decimal(18, 4)", wantErr: false},
+		{name: "Enum", t: "Enum8('a' = 1, 'it''s' = 2, 'b')", wantErr: false},
+		{name: "Aggregate function", t: "AggregateFunction(quantiles(0.5, 0.9), Float64)", wantErr: false},
+		{name: "JSON", t: "JSON(max_dynamic_paths = 256, a.b UInt32, SKIP c)", wantErr: false},
+		{name: "Empty", t: " ", wantErr: true},
+		{name: "Missing parenthesis", t: "Array(Nullable(String)", wantErr: true},
+		{name: "Unterminated quote", t: "Enum8('a = 1)", wantErr: true},
+		{name: "Text after the type", t: "Array(String) NOT NULL", wantErr: true},
+		{name: "Nullable without type", t: "Nullable()", wantErr: true},
+		{name: "Nullable of two types", t: "Nullable(String, UInt8)", wantErr: true},
+		{name: "Nullable array", t: "Nullable(Array(String))", wantErr: true},
+		{name: "Map without value", t: "Map(String)", wantErr: true},
+		{name: "Malformed nested type", t: "Map(String, Array())", wantErr: true},
+		{name: "Empty tuple element", t: "Tuple(UInt64, )", wantErr: true},
+		{name: "Decimal precision", t: "Decimal(80, 2)", wantErr: true},
+		{name: "Decimal scale", t: "Decimal(10, 12)", wantErr: true},
+		{name: "Decimal32 scale", t: "Decimal32(10)", wantErr: true},
+		{name: "FixedString length", t: "FixedString(0)", wantErr: true},
+		{name: "DateTime64 precision", t: "DateTime64(12)", wantErr: true},
+		{name: "Enum unquoted", t: "Enum8(a = 1)", wantErr: true},
+		{name: "Enum8 out of range", t: "Enum8('a' = 200)", wantErr: true},
+		{name: "Enum duplicate number", t: "Enum16('a' = 1, 'b' = 1)", wantErr: true},
+		{name: "Enum duplicate name", t: "Enum('a' = 1, 'a' = 2)", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkTypeSyntax(tt.t); (err != nil) != tt.wantErr {
+				t.Errorf("checkTypeSyntax(%q) error = %v, wantErr %v", tt.t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_lowCardinalityIssues(t *testing.T) {
+	tests := []struct {
+		name string
+		t    string
+		want int
+	}{
+		{name: "No LowCardinality", t: "Nullable(String)", want: 0},
+		{name: "String", t: "LowCardinality(String)", want: 0},
+		{name: "Nullable FixedString", t: "LowCardinality(Nullable(FixedString(2)))", want: 0},
+		{name: "Nested in Map", t: "Map(LowCardinality(String), Array(LowCardinality(String)))", want: 0},
+		{name: "Wrong wrapping order", t: "Nullable(LowCardinality(String))", want: 1},
+		{name: "Unsupported inner type", t: "LowCardinality(Array(String))", want: 1},
+		{name: "Decimal", t: "LowCardinality(Nullable(Decimal(10, 2)))", want: 1},
+		{name: "Suspicious number", t: "LowCardinality(UInt8)", want: 1},
+		{name: "Named tuple element", t: "Tuple(id UInt64, country LowCardinality(Date))", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lowCardinalityIssues(tt.t); len(got) != tt.want {
+				t.Errorf("lowCardinalityIssues() = %v, want %d issues", got, tt.want)
+			}
+		})
+	}
+}
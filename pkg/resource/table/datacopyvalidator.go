@@ -0,0 +1,54 @@
+package table
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// dataCopyValidator rejects the options copying the rows of a table into its replacement when the copy can't see all
+// of them. INSERT SELECT runs on the replica the connection hits, so with cluster_name only the data of its shard would
+// be copied while the previous table is dropped on the whole cluster. With target, every statement runs on each replica
+// of the shard in turn: the INSERT SELECT of each replica is replicated to the others, copying the rows several times
+// into a Replicated table, and the table is renamed and dropped replica by replica, which preserve_data_on_replace can't
+// roll back consistently.
+type dataCopyValidator struct{}
+
+func (v dataCopyValidator) Description(_ context.Context) string {
+	return "Checks that the data of the table can be copied in full when it is replaced"
+}
+
+func (v dataCopyValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v dataCopyValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var clusterName types.String
+	var target types.Object
+	var copyDataOnExchange, preserveDataOnReplace types.Bool
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("cluster_name"), &clusterName)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("target"), &target)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("copy_data_on_exchange"), &copyDataOnExchange)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("preserve_data_on_replace"), &preserveDataOnReplace)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if copyDataOnExchange.ValueBool() && (!clusterName.IsNull() || !target.IsNull()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("copy_data_on_exchange"),
+			"Data can't be copied on a cluster",
+			"'copy_data_on_exchange' copies the rows of the replica the provider is connected to only, the data of the other shards would be lost when the previous table is dropped, and with 'target' the copy runs on every replica of the shard, duplicating the rows of Replicated tables. Remove 'cluster_name' and 'target', or 'copy_data_on_exchange', and copy the data of a clustered table manually.",
+		)
+	}
+
+	if preserveDataOnReplace.ValueBool() && (!clusterName.IsNull() || !target.IsNull()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("preserve_data_on_replace"),
+			"Data can't be preserved on a cluster",
+			"'preserve_data_on_replace' copies the rows of a single replica only, the data of the other shards or replicas would be lost when the previous table is dropped. Remove 'cluster_name' and 'target', or 'preserve_data_on_replace'.",
+		)
+	}
+}
@@ -0,0 +1,212 @@
+package table
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/schemadiff"
+)
+
+const streamingHint = "Streaming engines only consume messages: attach a materialized view writing to a MergeTree table to store them."
+
+// engineFamily describes what the engines of a family accept on top of columns and comment. MergeTree engines accept
+// everything, engines not listed in any family are not validated.
+type engineFamily struct {
+	engines     []string
+	unsupported []string
+	// requiredSettings must be set when the engine is used without parameters.
+	requiredSettings []string
+	hint             string
+}
+
+var engineFamilies = []engineFamily{
+	{
+		engines:     []string{"Memory", "Log", "TinyLog", "StripeLog", "Set", "Join"},
+		unsupported: []string{"order_by", "primary_key", "sample_by", "partition_by", "ttl"},
+		hint:        "Only MergeTree family engines support sorting and primary keys, sampling, partitioning and TTL.",
+	},
+	{
+		engines:     []string{"Null", "Buffer", "Merge", "Dictionary"},
+		unsupported: []string{"order_by", "primary_key", "sample_by", "partition_by", "ttl", "settings"},
+		hint:        "These engines don't store data themselves, configure the tables they read from or write to instead.",
+	},
+	{
+		engines:     []string{"Distributed"},
+		unsupported: []string{"order_by", "primary_key", "sample_by", "partition_by", "ttl"},
+		hint:        "Set them on the local tables the Distributed table points to.",
+	},
+	{
+		engines:     []string{"S3", "File", "URL", "HDFS", "AzureBlobStorage"},
+		unsupported: []string{"order_by", "primary_key", "sample_by", "ttl"},
+		hint:        "The data stays in the external storage, only partition_by is supported to split the files written.",
+	},
+	{
+		engines:          []string{"Kafka"},
+		unsupported:      []string{"order_by", "primary_key", "sample_by", "partition_by", "ttl"},
+		requiredSettings: []string{"kafka_broker_list", "kafka_topic_list", "kafka_group_name", "kafka_format"},
+		hint:             streamingHint,
+	},
+	{
+		engines:          []string{"NATS"},
+		unsupported:      []string{"order_by", "primary_key", "sample_by", "partition_by", "ttl"},
+		requiredSettings: []string{"nats_url", "nats_subjects", "nats_format"},
+		hint:             streamingHint,
+	},
+	{
+		engines:     []string{"RabbitMQ"},
+		unsupported: []string{"order_by", "primary_key", "sample_by", "partition_by", "ttl"},
+		hint:        streamingHint,
+	},
+}
+
+// engineIssue is an attribute that can't be used with the configured engine.
+type engineIssue struct {
+	attribute string
+	summary   string
+	detail    string
+}
+
+// checkEngine returns the issues of a table using engine with the given attributes set. settings holds the names of
+// the configured settings, or is nil when they are not known yet.
+func checkEngine(engine string, attributes []string, settings []string) []engineIssue {
+	name := schemadiff.EngineName(engine)
+
+	var family *engineFamily
+	for i := range engineFamilies {
+		if slices.Contains(engineFamilies[i].engines, name) {
+			family = &engineFamilies[i]
+			break
+		}
+	}
+	if family == nil {
+		return nil
+	}
+
+	var issues []engineIssue
+	for _, attribute := range family.unsupported {
+		if slices.Contains(attributes, attribute) {
+			issues = append(issues, engineIssue{
+				attribute: attribute,
+				summary:   "Attribute Not Supported By Engine",
+				detail:    fmt.Sprintf("The %s engine does not support %s. %s", name, attribute, family.hint),
+			})
+		}
+	}
+
+	if settings != nil && len(family.requiredSettings) > 0 && !hasEngineParameters(engine) {
+		var missing []string
+		for _, setting := range family.requiredSettings {
+			if !slices.Contains(settings, setting) {
+				missing = append(missing, setting)
+			}
+		}
+		if len(missing) > 0 {
+			issues = append(issues, engineIssue{
+				attribute: "settings",
+				summary:   "Missing Engine Settings",
+				detail:    fmt.Sprintf("The %s engine needs the %s settings when its parameters are not passed in engine, e.g. %s(named_collection). %s", name, strings.Join(missing, ", "), name, family.hint),
+			})
+		}
+	}
+
+	return issues
+}
+
+// hasEngineParameters tells if engine is called with parameters, e.g. Kafka('broker:9092', 'topic', 'group', 'JSONEachRow').
+func hasEngineParameters(engine string) bool {
+	start := strings.Index(engine, "(")
+	end := strings.LastIndex(engine, ")")
+	if start == -1 || end < start {
+		return false
+	}
+
+	return strings.TrimSpace(engine[start+1:end]) != ""
+}
+
+// engineValidator rejects the attributes the configured engine does not support, so that they fail at plan time
+// rather than when creating the table.
+type engineValidator struct{}
+
+func (v engineValidator) Description(_ context.Context) string {
+	return "Checks that keys, TTL and settings are supported by the table engine"
+}
+
+func (v engineValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v engineValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var engine types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("engine"), &engine)...)
+	if resp.Diagnostics.HasError() || engine.IsNull() || engine.IsUnknown() {
+		return
+	}
+
+	var orderBy, primaryKey types.List
+	var sampleBy, partitionBy, ttl types.String
+	var settings types.Map
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("order_by"), &orderBy)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("primary_key"), &primaryKey)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("sample_by"), &sampleBy)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("partition_by"), &partitionBy)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("ttl"), &ttl)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("settings"), &settings)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var attributes []string
+	for name, value := range map[string]attr.Value{
+		"order_by":     orderBy,
+		"primary_key":  primaryKey,
+		"sample_by":    sampleBy,
+		"partition_by": partitionBy,
+		"ttl":          ttl,
+		"settings":     settings,
+	} {
+		if isSet(value) {
+			attributes = append(attributes, name)
+		}
+	}
+
+	var settingNames []string
+	if !settings.IsUnknown() {
+		settingNames = []string{}
+		for key := range settings.Elements() {
+			settingNames = append(settingNames, key)
+		}
+	}
+
+	for _, issue := range checkEngine(engine.ValueString(), attributes, settingNames) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(issue.attribute),
+			issue.summary,
+			issue.detail,
+		)
+	}
+}
+
+// isSet tells if a configuration value is known to be set, empty lists, maps and strings being the same as unset.
+func isSet(value attr.Value) bool {
+	if value.IsNull() || value.IsUnknown() {
+		return false
+	}
+
+	switch v := value.(type) {
+	case types.List:
+		return len(v.Elements()) > 0
+	case types.Map:
+		return len(v.Elements()) > 0
+	case types.String:
+		return v.ValueString() != ""
+	}
+
+	return true
+}
@@ -0,0 +1,98 @@
+package table
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_checkEngine(t *testing.T) {
+	tests := []struct {
+		name       string
+		engine     string
+		attributes []string
+		settings   []string
+		want       []string
+	}{
+		{
+			name:       "MergeTree accepts everything",
+			engine:     "ReplicatedMergeTree('/clickhouse/tables/{shard}/db/events', '{replica}')",
+			attributes: []string{"order_by", "primary_key", "sample_by", "partition_by", "ttl", "settings"},
+			settings:   []string{"index_granularity"},
+			want:       nil,
+		},
+		{
+			name:       "Unknown engine is not validated",
+			engine:     "EmbeddedRocksDB",
+			attributes: []string{"primary_key"},
+			settings:   []string{},
+			want:       nil,
+		},
+		{
+			name:       "Memory rejects keys and TTL",
+			engine:     "Memory",
+			attributes: []string{"order_by", "ttl", "settings"},
+			settings:   []string{"min_rows_to_keep"},
+			want:       []string{"order_by", "ttl"},
+		},
+		{
+			name:       "Null rejects settings",
+			engine:     "Null()",
+			attributes: []string{"settings"},
+			settings:   []string{"max_rows"},
+			want:       []string{"settings"},
+		},
+		{
+			name:       "Distributed rejects TTL",
+			engine:     "Distributed('cluster', 'db', 'events_local', rand())",
+			attributes: []string{"ttl", "settings"},
+			settings:   []string{"fsync_after_insert"},
+			want:       []string{"ttl"},
+		},
+		{
+			name:       "S3 accepts partition_by",
+			engine:     "S3(events_bucket, format = 'Parquet')",
+			attributes: []string{"partition_by"},
+			settings:   []string{},
+			want:       nil,
+		},
+		{
+			name:       "Kafka without parameters requires settings",
+			engine:     "Kafka",
+			attributes: []string{"settings"},
+			settings:   []string{"kafka_broker_list", "kafka_topic_list"},
+			want:       []string{"settings"},
+		},
+		{
+			name:       "Kafka with all settings",
+			engine:     "Kafka()",
+			attributes: []string{"settings"},
+			settings:   []string{"kafka_broker_list", "kafka_topic_list", "kafka_group_name", "kafka_format"},
+			want:       nil,
+		},
+		{
+			name:       "Kafka with parameters",
+			engine:     "Kafka('broker:9092', 'events', 'clickhouse', 'JSONEachRow')",
+			attributes: nil,
+			settings:   []string{},
+			want:       nil,
+		},
+		{
+			name:       "Kafka with unknown settings",
+			engine:     "Kafka",
+			attributes: nil,
+			settings:   nil,
+			want:       nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, issue := range checkEngine(tt.engine, tt.attributes, tt.settings) {
+				got = append(got, issue.attribute)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkEngine() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
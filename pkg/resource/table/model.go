@@ -5,25 +5,64 @@ import (
 )
 
 type Table struct {
-	ClusterName  types.String `tfsdk:"cluster_name"`
-	UUID         types.String `tfsdk:"uuid"`
-	DatabaseName types.String `tfsdk:"database_name"`
-	Name         types.String `tfsdk:"name"`
-	Columns      []Column     `tfsdk:"columns"`
-	Engine       types.String `tfsdk:"engine"`
-	OrderBy      types.List   `tfsdk:"order_by"`
-	PartitionBy  types.String `tfsdk:"partition_by"`
-	PrimaryKey   types.List   `tfsdk:"primary_key"`
-	SampleBy     types.String `tfsdk:"sample_by"`
-	TTL          types.String `tfsdk:"ttl"`
-	Settings     types.Map    `tfsdk:"settings"`
-	Comment      types.String `tfsdk:"comment"`
-	AllowDrops   types.Bool   `tfsdk:"allow_drops"`
+	ClusterName           types.String    `tfsdk:"cluster_name"`
+	UUID                  types.String    `tfsdk:"uuid"`
+	DatabaseName          types.String    `tfsdk:"database_name"`
+	Name                  types.String    `tfsdk:"name"`
+	Columns               []Column        `tfsdk:"columns"`
+	Indexes               []Index         `tfsdk:"indexes"`
+	Projections           []Projection    `tfsdk:"projections"`
+	Engine                types.String    `tfsdk:"engine"`
+	SourceFunction        *SourceFunction `tfsdk:"source_function"`
+	Target                *Target         `tfsdk:"target"`
+	OrderBy               types.List      `tfsdk:"order_by"`
+	PartitionBy           types.String    `tfsdk:"partition_by"`
+	PrimaryKey            types.List      `tfsdk:"primary_key"`
+	SampleBy              types.String    `tfsdk:"sample_by"`
+	TTL                   types.String    `tfsdk:"ttl"`
+	Settings              types.Map       `tfsdk:"settings"`
+	Comment               types.String    `tfsdk:"comment"`
+	AllowDrops            types.Bool      `tfsdk:"allow_drops"`
+	UpdateStrategy        types.String    `tfsdk:"update_strategy"`
+	CopyDataOnExchange    types.Bool      `tfsdk:"copy_data_on_exchange"`
+	PreserveDataOnReplace types.Bool      `tfsdk:"preserve_data_on_replace"`
+	SchemaJSON            types.String    `tfsdk:"schema_json"`
+	SchemaHash            types.String    `tfsdk:"schema_hash"`
+	CreateStatement       types.String    `tfsdk:"create_statement"`
+	UnmanagedDefinition   types.String    `tfsdk:"unmanaged_definition"`
 }
 
 type Column struct {
-	Name    types.String `tfsdk:"name"`
-	Type    types.String `tfsdk:"type"`
-	Default types.String `tfsdk:"default"`
-	Comment types.String `tfsdk:"comment"`
+	Name        types.String `tfsdk:"name"`
+	Type        types.String `tfsdk:"type"`
+	Default     types.String `tfsdk:"default"`
+	DefaultKind types.String `tfsdk:"default_kind"`
+	Comment     types.String `tfsdk:"comment"`
+	Codec       types.String `tfsdk:"codec"`
+	RenameFrom  types.String `tfsdk:"rename_from"`
+}
+
+type Index struct {
+	Name        types.String `tfsdk:"name"`
+	Expression  types.String `tfsdk:"expression"`
+	Type        types.String `tfsdk:"type"`
+	Granularity types.Int64  `tfsdk:"granularity"`
+	Materialize types.Bool   `tfsdk:"materialize"`
+}
+
+type Projection struct {
+	Name        types.String `tfsdk:"name"`
+	Query       types.String `tfsdk:"query"`
+	Materialize types.Bool   `tfsdk:"materialize"`
+}
+
+type SourceFunction struct {
+	Name            types.String `tfsdk:"name"`
+	NamedCollection types.String `tfsdk:"named_collection"`
+	Arguments       types.Map    `tfsdk:"arguments"`
+}
+
+type Target struct {
+	Cluster types.String `tfsdk:"cluster"`
+	Shard   types.Int64  `tfsdk:"shard"`
 }
@@ -0,0 +1,107 @@
+package table
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+// replaceKeepingData applies the plan of a table replaced with preserve_data_on_replace: the existing table is
+// renamed out of the way, the planned table is created in its place, and the columns both tables have are copied
+// into it. The previous table is then dropped when allow_drops is true, and kept under the snapshot name otherwise.
+// It only runs without a cluster, see dataCopyValidator, so the copy sees all the data.
+func (r *Resource) replaceKeepingData(ctx context.Context, state Table, plan Table, resp *resource.UpdateResponse) {
+	clusterName := plan.ClusterName.ValueStringPointer()
+	databaseName := state.DatabaseName.ValueString()
+	tableName := state.Name.ValueString()
+	snapshotName := fmt.Sprintf("%s_replace_snapshot_%d", tableName, time.Now().Unix())
+
+	newTable, diags := tableFromPlan(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	newTable.UUID = ""
+
+	createStatement, err := dbops.CreateTableStatement(*newTable, clusterName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating table",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	err = r.client.RenameTable(ctx, databaseName, tableName, snapshotName, clusterName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error renaming table before replacement",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	created, err := r.client.CreateTable(ctx, *newTable, clusterName)
+	if err != nil {
+		detail := fmt.Sprintf("%+v\n", err)
+		if renameErr := r.client.RenameTable(ctx, databaseName, snapshotName, tableName, clusterName); renameErr != nil {
+			detail += fmt.Sprintf("\nThe previous table could not be renamed back and is left as `%s`.`%s`: %+v\n", databaseName, snapshotName, renameErr)
+		}
+		resp.Diagnostics.AddError("Error creating table", detail)
+		return
+	}
+
+	// From here on the new table exists and must be kept in the state, failures leave the previous table in place.
+	copied := true
+	if columns := sharedColumnNames(state, plan); len(columns) > 0 {
+		err = r.client.CopyTableData(ctx, databaseName, snapshotName, tableName, columns)
+		if err != nil {
+			copied = false
+			resp.Diagnostics.AddWarning(
+				"Error copying table data",
+				fmt.Sprintf("The table was recreated, but the data of the previous table could not be copied into it and is kept in `%s`.`%s`: %+v\n", databaseName, snapshotName, err),
+			)
+		}
+	}
+
+	if copied && plan.AllowDrops.ValueBool() {
+		err = r.client.DeleteTable(ctx, state.UUID.ValueString(), clusterName)
+		if err != nil {
+			resp.Diagnostics.AddWarning(
+				"Error dropping previous table",
+				fmt.Sprintf("The data was copied, but the previous table could not be dropped and is left as `%s`.`%s`: %+v\n", databaseName, snapshotName, err),
+			)
+		}
+	} else if copied {
+		resp.Diagnostics.AddWarning(
+			"Previous table kept",
+			fmt.Sprintf("The previous table is kept as `%s`.`%s` because 'allow_drops' is set to false. Drop it once it is not needed anymore.", databaseName, snapshotName),
+		)
+	}
+
+	updatedState, err := r.syncTableState(ctx, created.UUID, clusterName, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error syncing table state",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if updatedState == nil {
+		resp.Diagnostics.AddError(
+			"Error syncing table state",
+			"failed retrieving table after replacement",
+		)
+		return
+	}
+	updatedState.CreateStatement = types.StringValue(createStatement)
+
+	diags = resp.State.Set(ctx, updatedState)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,88 @@
+package table
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/schemadiff"
+)
+
+// structureSchema is what the schema_hash attribute is computed from: the parts of the table that readers and
+// writers depend on. Names, comments, settings and TTL are left out so that changing them doesn't trigger rebuilds.
+type structureSchema struct {
+	Engine         string                `json:"engine"`
+	SourceFunction *sourceFunctionSchema `json:"source_function"`
+	Columns        []structureColumn     `json:"columns"`
+	OrderBy        []string              `json:"order_by"`
+	PrimaryKey     []string              `json:"primary_key"`
+	PartitionBy    *string               `json:"partition_by"`
+	SampleBy       *string               `json:"sample_by"`
+}
+
+type structureColumn struct {
+	Name    string  `json:"name"`
+	Type    string  `json:"type"`
+	Default *string `json:"default"`
+}
+
+// schemaHash returns the hex encoded SHA-256 of the structure of the table as read from ClickHouse.
+// Values are the ones normalized by the server, so that equivalent definitions give the same hash.
+func schemaHash(table *dbops.Table) (string, error) {
+	s := structureSchema{
+		Engine:      table.Engine,
+		Columns:     make([]structureColumn, 0, len(table.Columns)),
+		OrderBy:     make([]string, 0, len(table.OrderBy)),
+		PrimaryKey:  make([]string, 0, len(table.PrimaryKey)),
+		PartitionBy: table.PartitionBy,
+		SampleBy:    table.SampleBy,
+	}
+
+	for _, col := range table.Columns {
+		s.Columns = append(s.Columns, structureColumn{
+			Name:    col.Name,
+			Type:    col.Type,
+			Default: col.Default,
+		})
+	}
+	if table.SourceFunction != nil {
+		s.SourceFunction = &sourceFunctionSchema{
+			Name:            table.SourceFunction.Name,
+			NamedCollection: table.SourceFunction.NamedCollection,
+			Arguments:       make(map[string]string, len(table.SourceFunction.Arguments)),
+		}
+		for k, v := range table.SourceFunction.Arguments {
+			s.SourceFunction.Arguments[k] = v
+		}
+	}
+	s.OrderBy = append(s.OrderBy, table.OrderBy...)
+	s.PrimaryKey = append(s.PrimaryKey, table.PrimaryKey...)
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return "", errors.WithMessage(err, "error marshaling table structure")
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// columnsStructureChanged tells if columns are added, removed, reordered or have their type or default changed
+// between state and plan, which changes the schema_hash of tables updated in place.
+func columnsStructureChanged(state, plan []Column) bool {
+	if len(state) != len(plan) {
+		return true
+	}
+
+	for i := range state {
+		if !state[i].Name.Equal(plan[i].Name) || !schemadiff.SameType(state[i].Type.ValueString(), plan[i].Type.ValueString()) || !state[i].Default.Equal(plan[i].Default) {
+			return true
+		}
+	}
+
+	return false
+}
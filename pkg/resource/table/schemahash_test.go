@@ -0,0 +1,87 @@
+package table
+
+import (
+	"testing"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+func Test_schemaHash(t *testing.T) {
+	base := func() *dbops.Table {
+		return &dbops.Table{
+			DatabaseName: "db",
+			Name:         "events",
+			Engine:       "MergeTree",
+			Columns: []querybuilder.TableColumn{
+				{Name: "ts", Type: "DateTime", Default: strPtr("now()")},
+				{Name: "id", Type: "UInt64"},
+			},
+			OrderBy:  []string{"ts"},
+			Settings: map[string]string{"index_granularity": "8192"},
+			Comment:  "events",
+		}
+	}
+
+	want, err := schemaHash(base())
+	if err != nil {
+		t.Fatalf("schemaHash() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*dbops.Table)
+		changes bool
+	}{
+		{
+			name:    "Renamed table",
+			modify:  func(table *dbops.Table) { table.Name = "events_v2" },
+			changes: false,
+		},
+		{
+			name: "Comments and settings",
+			modify: func(table *dbops.Table) {
+				table.Comment = "all events"
+				table.Columns[0].Comment = strPtr("event time")
+				table.Settings = map[string]string{}
+			},
+			changes: false,
+		},
+		{
+			name:    "Column type",
+			modify:  func(table *dbops.Table) { table.Columns[1].Type = "UInt32" },
+			changes: true,
+		},
+		{
+			name: "Added column",
+			modify: func(table *dbops.Table) {
+				table.Columns = append(table.Columns, querybuilder.TableColumn{Name: "name", Type: "String"})
+			},
+			changes: true,
+		},
+		{
+			name:    "Sorting key",
+			modify:  func(table *dbops.Table) { table.OrderBy = []string{"ts", "id"} },
+			changes: true,
+		},
+		{
+			name:    "Engine",
+			modify:  func(table *dbops.Table) { table.Engine = "ReplacingMergeTree" },
+			changes: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := base()
+			tt.modify(table)
+
+			got, err := schemaHash(table)
+			if err != nil {
+				t.Fatalf("schemaHash() error = %v", err)
+			}
+			if (got != want) != tt.changes {
+				t.Errorf("schemaHash() = %v, base %v, want changed = %v", got, want, tt.changes)
+			}
+		})
+	}
+}
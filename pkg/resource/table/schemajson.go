@@ -0,0 +1,94 @@
+package table
+
+import (
+	"encoding/json"
+
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// tableSchema is the canonical representation of a table exposed in the schema_json attribute.
+// Every field is always present, so consumers don't need to handle missing keys.
+type tableSchema struct {
+	Database       string                `json:"database"`
+	Name           string                `json:"name"`
+	Engine         string                `json:"engine"`
+	SourceFunction *sourceFunctionSchema `json:"source_function"`
+	Columns        []columnSchema        `json:"columns"`
+	OrderBy        []string              `json:"order_by"`
+	PrimaryKey     []string              `json:"primary_key"`
+	PartitionBy    *string               `json:"partition_by"`
+	SampleBy       *string               `json:"sample_by"`
+	TTL            *string               `json:"ttl"`
+	Settings       map[string]string     `json:"settings"`
+	Comment        string                `json:"comment"`
+}
+
+type sourceFunctionSchema struct {
+	Name            string            `json:"name"`
+	NamedCollection string            `json:"named_collection"`
+	Arguments       map[string]string `json:"arguments"`
+}
+
+type columnSchema struct {
+	Name        string  `json:"name"`
+	Type        string  `json:"type"`
+	Default     *string `json:"default"`
+	DefaultKind string  `json:"default_kind"`
+	Comment     *string `json:"comment"`
+}
+
+// schemaJSON returns the canonical JSON schema of the table as read from ClickHouse.
+func schemaJSON(table *dbops.Table) (string, error) {
+	s := tableSchema{
+		Database:    table.DatabaseName,
+		Name:        table.Name,
+		Engine:      table.Engine,
+		Columns:     make([]columnSchema, 0, len(table.Columns)),
+		OrderBy:     make([]string, 0, len(table.OrderBy)),
+		PrimaryKey:  make([]string, 0, len(table.PrimaryKey)),
+		PartitionBy: table.PartitionBy,
+		SampleBy:    table.SampleBy,
+		TTL:         table.TTL,
+		Settings:    make(map[string]string, len(table.Settings)),
+		Comment:     table.Comment,
+	}
+
+	for _, col := range table.Columns {
+		defaultKind := querybuilder.ColumnDefaultKindDefault
+		if col.DefaultKind != nil {
+			defaultKind = *col.DefaultKind
+		}
+		s.Columns = append(s.Columns, columnSchema{
+			Name:        col.Name,
+			Type:        col.Type,
+			Default:     col.Default,
+			DefaultKind: defaultKind,
+			Comment:     col.Comment,
+		})
+	}
+	if table.SourceFunction != nil {
+		s.SourceFunction = &sourceFunctionSchema{
+			Name:            table.SourceFunction.Name,
+			NamedCollection: table.SourceFunction.NamedCollection,
+			Arguments:       make(map[string]string, len(table.SourceFunction.Arguments)),
+		}
+		for k, v := range table.SourceFunction.Arguments {
+			s.SourceFunction.Arguments[k] = v
+		}
+	}
+	s.OrderBy = append(s.OrderBy, table.OrderBy...)
+	s.PrimaryKey = append(s.PrimaryKey, table.PrimaryKey...)
+	for k, v := range table.Settings {
+		s.Settings[k] = v
+	}
+
+	ret, err := json.Marshal(s)
+	if err != nil {
+		return "", errors.WithMessage(err, "error marshaling table schema")
+	}
+
+	return string(ret), nil
+}
@@ -0,0 +1,81 @@
+package table
+
+import (
+	"testing"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func Test_schemaJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		table   *dbops.Table
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Minimal table",
+			table: &dbops.Table{
+				DatabaseName: "db",
+				Name:         "logs",
+				Engine:       "Log",
+				Columns:      []querybuilder.TableColumn{{Name: "line", Type: "String"}},
+			},
+			want:    `{"database":"db","name":"logs","engine":"Log","source_function":null,"columns":[{"name":"line","type":"String","default":null,"default_kind":"DEFAULT","comment":null}],"order_by":[],"primary_key":[],"partition_by":null,"sample_by":null,"ttl":null,"settings":{},"comment":""}`,
+			wantErr: false,
+		},
+		{
+			name: "All fields",
+			table: &dbops.Table{
+				DatabaseName: "db",
+				Name:         "events",
+				Engine:       "MergeTree",
+				Columns: []querybuilder.TableColumn{
+					{Name: "ts", Type: "DateTime", Default: strPtr("now()"), Comment: strPtr("event time")},
+				},
+				OrderBy:     []string{"ts"},
+				PrimaryKey:  []string{"ts"},
+				PartitionBy: strPtr("toYYYYMM(ts)"),
+				SampleBy:    nil,
+				TTL:         strPtr("ts + toIntervalDay(30)"),
+				Settings:    map[string]string{"storage_policy": "'s3'", "index_granularity": "8192"},
+				Comment:     "events",
+			},
+			want:    `{"database":"db","name":"events","engine":"MergeTree","source_function":null,"columns":[{"name":"ts","type":"DateTime","default":"now()","default_kind":"DEFAULT","comment":"event time"}],"order_by":["ts"],"primary_key":["ts"],"partition_by":"toYYYYMM(ts)","sample_by":null,"ttl":"ts + toIntervalDay(30)","settings":{"index_granularity":"8192","storage_policy":"'s3'"},"comment":"events"}`,
+			wantErr: false,
+		},
+		{
+			name: "Table from source function",
+			table: &dbops.Table{
+				DatabaseName: "db",
+				Name:         "snapshot",
+				Engine:       "S3",
+				SourceFunction: &querybuilder.TableFunction{
+					Name:            "s3",
+					NamedCollection: "bucket",
+					Arguments:       map[string]string{"format": "Parquet"},
+				},
+				Columns: []querybuilder.TableColumn{{Name: "id", Type: "UInt64"}},
+			},
+			want:    `{"database":"db","name":"snapshot","engine":"S3","source_function":{"name":"s3","named_collection":"bucket","arguments":{"format":"Parquet"}},"columns":[{"name":"id","type":"UInt64","default":null,"default_kind":"DEFAULT","comment":null}],"order_by":[],"primary_key":[],"partition_by":null,"sample_by":null,"ttl":null,"settings":{},"comment":""}`,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := schemaJSON(tt.table)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("schemaJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("schemaJSON() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
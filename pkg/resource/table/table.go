@@ -4,20 +4,25 @@ import (
 	"context"
 	_ "embed"
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/objectvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapdefault"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
@@ -25,8 +30,13 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/pingcap/errors"
 
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clustername"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/comment"
 	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/defaultdatabase"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/protecteddatabase"
 	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/schemadiff"
 )
 
 //go:embed table.md
@@ -34,12 +44,15 @@ var tableResourceDescription string
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &Resource{}
-	_ resource.ResourceWithConfigure   = &Resource{}
-	_ resource.ResourceWithImportState = &Resource{}
-	_ resource.ResourceWithModifyPlan  = &Resource{}
+	_ resource.Resource                     = &Resource{}
+	_ resource.ResourceWithConfigure        = &Resource{}
+	_ resource.ResourceWithImportState      = &Resource{}
+	_ resource.ResourceWithModifyPlan       = &Resource{}
+	_ resource.ResourceWithConfigValidators = &Resource{}
 )
 
+var uuidRegexp = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
 // NewResource is a helper function to simplify the provider implementation.
 func NewResource() resource.Resource {
 	return &Resource{}
@@ -61,39 +74,108 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 		Attributes: map[string]schema.Attribute{
 			"cluster_name": schema.StringAttribute{
 				Optional:    true,
-				Description: "Name of the cluster to create the table into. If omitted, the table will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nShould be set when hitting a cluster with more than one replica.",
+				Description: "Name of the cluster to create the table into. If omitted, the table will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nShould be set when hitting a cluster with more than one replica.\nSetting it on an existing table makes the following changes run on the whole cluster without recreating the table, changing or removing it recreates the table.",
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIf(requiresReplaceUnlessClusterAdded, "Changing or removing the cluster recreates the table", "Changing or removing `cluster_name` recreates the table"),
 				},
 			},
 			"uuid": schema.StringAttribute{
+				Optional:    true,
 				Computed:    true,
-				Description: "The system-assigned UUID for the table",
+				Description: "The UUID of the table. Assigned by ClickHouse unless set, which is useful to restore a table or to keep the UUID of a table the same across environments, e.g. for Replicated databases. Changing it recreates the table",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(uuidRegexp, "must be a lowercase UUID like 5f2b8e1a-3c4d-4e5f-8a9b-0c1d2e3f4a5b"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"database_name": schema.StringAttribute{
-				Required:    true,
-				Description: "Name of the database containing the table",
+				Optional:    true,
+				Computed:    true,
+				Description: "Name of the database containing the table. Defaults to the provider's `default_database`",
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
 			"name": schema.StringAttribute{
 				Required:    true,
-				Description: "Name of the table",
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
-				},
+				Description: "Name of the table. Changing it renames the table with RENAME TABLE, keeping its data",
 			},
 			"engine": schema.StringAttribute{
-				Required:    true,
-				Description: "Table engine (e.g., MergeTree(), ReplacingMergeTree(), Log, Memory)",
+				Optional:    true,
+				Computed:    true,
+				Description: "Table engine (e.g., MergeTree(), ReplacingMergeTree(), Log, Memory). Exactly one of `engine` and `source_function` must be set.",
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+					requiresReplaceStringWithRecreate(),
+				},
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("source_function")),
+				},
+			},
+			"source_function": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "Create the table AS a table function (e.g. s3, postgresql, mysql) reading from an external source on every query, instead of using an engine. Credentials are referenced through a named collection so they never end up in the terraform state. Tables created from a source function cannot have keys, TTL or settings.",
+				Attributes: map[string]schema.Attribute{
+					"name": schema.StringAttribute{
+						Required:    true,
+						Description: "Name of the table function (e.g. s3, postgresql)",
+					},
+					"named_collection": schema.StringAttribute{
+						Required:    true,
+						Description: "Name of the named collection holding the connection details and credentials of the source",
+					},
+					"arguments": schema.MapAttribute{
+						Optional:    true,
+						Computed:    true,
+						ElementType: types.StringType,
+						Description: "Keys of the named collection to override, e.g. `url` or `format`. Values are passed as string literals.",
+						Default:     mapdefault.StaticValue(types.MapValueMust(types.StringType, map[string]attr.Value{})),
+					},
+				},
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.Object{
+					objectvalidator.ConflictsWith(
+						path.MatchRoot("order_by"),
+						path.MatchRoot("partition_by"),
+						path.MatchRoot("primary_key"),
+						path.MatchRoot("sample_by"),
+						path.MatchRoot("ttl"),
+						path.MatchRoot("settings"),
+					),
+				},
+			},
+			"target": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "Manage the table on the replicas of a single shard only, e.g. for shard-local maintenance tables. The replicas are resolved from `system.clusters` and the provider connects to each of them directly, with its own protocol, port and credentials.",
+				Attributes: map[string]schema.Attribute{
+					"cluster": schema.StringAttribute{
+						Required:    true,
+						Description: "Name of the cluster, as in `system.clusters`",
+					},
+					"shard": schema.Int64Attribute{
+						Required:    true,
+						Description: "Number of the shard, as `shard_num` in `system.clusters`",
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+				},
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.Object{
+					objectvalidator.ConflictsWith(path.MatchRoot("cluster_name")),
 				},
 			},
 			"columns": schema.ListNestedAttribute{
 				Required:    true,
-				Description: "List of columns in the table. New columns can be added without recreating the table. Removing columns or modifying existing columns requires table recreation.",
+				Description: "List of columns in the table. Columns are added, dropped when `allow_drops` is true, renamed with `rename_from`, and changed to a compatible type or another codec in place with ALTER TABLE. The other changes are applied according to `update_strategy`, by default by recreating the table: dropping a column of the ORDER BY clause, renaming or changing the type of a column of the table key, changing `default_kind`, and changing a type to one its values can't be converted to.",
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"name": schema.StringAttribute{
@@ -103,22 +185,128 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 						"type": schema.StringAttribute{
 							Required:    true,
 							Description: "Column data type (e.g., UInt64, String, DateTime)",
+							Validators: []validator.String{
+								columnTypeValidator{},
+							},
 						},
 						"default": schema.StringAttribute{
 							Optional:    true,
-							Description: "Default value or expression for the column",
+							Description: "Default value or expression for the column, its kind is set by `default_kind`",
+						},
+						"default_kind": schema.StringAttribute{
+							Optional:    true,
+							Computed:    true,
+							Description: "How `default` is used: `DEFAULT` (the default) fills it in when the column is left out of an insert, `MATERIALIZED` always computes and stores it, `ALIAS` computes it at query time without storing it, and `EPHEMERAL` only uses the column as an input of the other defaults, without storing it. `MATERIALIZED` and `ALIAS` require `default`. Changing it recreates the table",
+							Default:     stringdefault.StaticString(querybuilder.ColumnDefaultKindDefault),
+							Validators: []validator.String{
+								stringvalidator.OneOf(
+									querybuilder.ColumnDefaultKindDefault,
+									querybuilder.ColumnDefaultKindMaterialized,
+									querybuilder.ColumnDefaultKindAlias,
+									querybuilder.ColumnDefaultKindEphemeral,
+								),
+							},
 						},
 						"comment": schema.StringAttribute{
 							Optional:    true,
 							Description: "Column comment",
+						},
+						"codec": schema.StringAttribute{
+							Optional:    true,
+							Description: "Compression codec chain of the column, without the surrounding `CODEC()`, e.g. `ZSTD(3)` or `Delta, ZSTD(1)`. Changed in place with ALTER TABLE MODIFY COLUMN, and removed with REMOVE CODEC. When null, the column uses the default compression of the server",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"rename_from": schema.StringAttribute{
+							Optional:    true,
+							Description: "Previous name of the column. When the table has a column with this name and none with `name`, the column is renamed with ALTER TABLE RENAME COLUMN, keeping its data, instead of being dropped and added again. It has no effect once the column is renamed and can be removed from the configuration then",
 							Validators: []validator.String{
-								stringvalidator.LengthAtMost(255),
+								stringvalidator.LengthAtLeast(1),
 							},
 						},
 					},
 				},
 				// Removed RequiresReplace - we'll handle updates in the Update method
 			},
+			"indexes": schema.ListNestedAttribute{
+				Optional:    true,
+				Description: "Data skipping indexes of the table. Indexes are added and dropped in place with ALTER TABLE, an index whose definition changes is dropped and added again. When null, the indexes of the table are not managed.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Required:    true,
+							Description: "Index name",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"expression": schema.StringAttribute{
+							Required:    true,
+							Description: "Expression the index is built on, e.g. a column name or `lower(message)`",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"type": schema.StringAttribute{
+							Required:    true,
+							Description: "Index type with its parameters, e.g. `minmax`, `set(100)`, `bloom_filter(0.01)` or `tokenbf_v1(512, 3, 0)`",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"granularity": schema.Int64Attribute{
+							Optional:    true,
+							Computed:    true,
+							Description: "Number of granules covered by each index block. Defaults to 1.",
+							Default:     int64default.StaticInt64(1),
+							Validators: []validator.Int64{
+								int64validator.AtLeast(1),
+							},
+						},
+						"materialize": schema.BoolAttribute{
+							Optional:    true,
+							Computed:    true,
+							Description: "Build the index for the existing data with MATERIALIZE INDEX when it is added to an existing table. Otherwise it only covers the parts written afterwards. Defaults to false.",
+							Default:     booldefault.StaticBool(false),
+						},
+					},
+				},
+				Validators: []validator.List{
+					listvalidator.UniqueValues(),
+				},
+			},
+			"projections": schema.ListNestedAttribute{
+				Optional:    true,
+				Description: "Projections of the table. Projections are added and dropped in place with ALTER TABLE, a projection whose query changes is dropped and added again. When null, the projections of the table are not managed.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Required:    true,
+							Description: "Projection name",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"query": schema.StringAttribute{
+							Required:    true,
+							Description: "SELECT statement of the projection, without FROM, e.g. `SELECT * ORDER BY user_id` or `SELECT user_id, count() GROUP BY user_id`",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"materialize": schema.BoolAttribute{
+							Optional:    true,
+							Computed:    true,
+							Description: "Build the projection for the existing data with MATERIALIZE PROJECTION when it is added to an existing table. Otherwise it only covers the parts written afterwards. Defaults to false.",
+							Default:     booldefault.StaticBool(false),
+						},
+					},
+				},
+				Validators: []validator.List{
+					listvalidator.UniqueValues(),
+				},
+			},
 			"order_by": schema.ListAttribute{
 				Optional:    true,
 				Computed:    true,
@@ -129,14 +317,14 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 					listvalidator.SizeAtLeast(1),
 				},
 				PlanModifiers: []planmodifier.List{
-					listplanmodifier.RequiresReplace(),
+					requiresReplaceListWithRecreate(),
 				},
 			},
 			"partition_by": schema.StringAttribute{
 				Optional:    true,
 				Description: "PARTITION BY expression",
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					requiresReplaceStringWithRecreate(),
 				},
 			},
 			"primary_key": schema.ListAttribute{
@@ -146,31 +334,28 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 				Description: "PRIMARY KEY columns",
 				Default:     listdefault.StaticValue(types.ListValueMust(types.StringType, []attr.Value{})),
 				PlanModifiers: []planmodifier.List{
-					listplanmodifier.RequiresReplace(),
+					requiresReplaceListWithRecreate(),
 				},
 			},
 			"sample_by": schema.StringAttribute{
 				Optional:    true,
 				Description: "SAMPLE BY expression",
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					requiresReplaceStringWithRecreate(),
 				},
 			},
 			"ttl": schema.StringAttribute{
 				Optional:    true,
-				Description: "TTL expression",
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
-				},
+				Description: "TTL expression. Changed and removed in place with ALTER TABLE MODIFY TTL and REMOVE TTL",
 			},
 			"settings": schema.MapAttribute{
 				Optional:    true,
 				Computed:    true,
 				ElementType: types.StringType,
-				Description: "Table-level settings",
+				Description: "Table-level settings. Changed in place with ALTER TABLE MODIFY SETTING and RESET SETTING on MergeTree engines, except for the settings ClickHouse only accepts at creation (`index_granularity`, `index_granularity_bytes`, `enable_mixed_granularity_parts`)",
 				Default:     mapdefault.StaticValue(types.MapValueMust(types.StringType, map[string]attr.Value{})),
 				PlanModifiers: []planmodifier.Map{
-					mapplanmodifier.RequiresReplace(),
+					requiresReplaceSettingsWithRecreate(),
 				},
 			},
 			"comment": schema.StringAttribute{
@@ -178,11 +363,8 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 				Computed:    true,
 				Description: "Comment associated with the table",
 				Default:     stringdefault.StaticString(""),
-				Validators: []validator.String{
-					stringvalidator.LengthAtMost(255),
-				},
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					requiresReplaceStringWithRecreate(),
 				},
 			},
 			"allow_drops": schema.BoolAttribute{
@@ -191,11 +373,61 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 				Description: "Allow column and table drops. When set to false (default), attempts to remove columns or delete the table will fail as a safety measure. Set to true to allow destructive operations.",
 				Default:     booldefault.StaticBool(false),
 			},
+			"update_strategy": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "How changes ALTER TABLE can't apply (engine, keys, settings, comment, column types) are executed: `recreate` (default) drops the table and creates it again, `alter_in_place` rejects them at plan time, `shadow_and_exchange` creates the new table next to the existing one and swaps them atomically with EXCHANGE TABLES.",
+				Default:     stringdefault.StaticString(updateStrategyRecreate),
+				Validators: []validator.String{
+					stringvalidator.OneOf(updateStrategies...),
+				},
+			},
+			"copy_data_on_exchange": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "With the `shadow_and_exchange` update strategy, copy the rows of the existing table into the new one with INSERT SELECT before swapping them, for the columns both tables have. Can't be combined with `cluster_name`, as the copy only sees the data of one shard, nor with `target`, as the copy would run on every replica of the shard. Defaults to false: the new table starts empty.",
+				Default:     booldefault.StaticBool(false),
+			},
+			"preserve_data_on_replace": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "With the `recreate` update strategy, apply changes ALTER TABLE can't make by renaming the table, creating the new one in its place and copying the columns both tables have into it, instead of dropping the table. Can't be combined with `cluster_name` or `target`. Defaults to false.",
+				Default:     booldefault.StaticBool(false),
+			},
+			"schema_json": schema.StringAttribute{
+				Computed:    true,
+				Description: "Canonical JSON representation of the table as defined in ClickHouse (columns, keys, engine, settings and comment), for consumption by external tools.",
+			},
+			"schema_hash": schema.StringAttribute{
+				Computed:    true,
+				Description: "SHA-256 of the normalized structure of the table (engine, columns and keys). It only changes when the structure does, so it can be used with `replace_triggered_by` to rebuild dependent objects.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"create_statement": schema.StringAttribute{
+				Computed:    true,
+				Description: "The CREATE TABLE statement the provider runs to create the table, rendered during plan unless the configuration depends on values only known during apply, so that it can be reviewed along with the plan. Tables replaced with `preserve_data_on_replace` show the statement creating the new table, while the shadow table of `shadow_and_exchange` gets a name only known during apply. Changes applied in place with ALTER TABLE leave it unchanged.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"unmanaged_definition": schema.StringAttribute{
+				Computed:    true,
+				Description: "The CREATE statement of the table when it uses features the resource can't model, e.g. constraints, column TTLs or engine parameters hidden by ClickHouse. The table is then adopted read-only: changes to its definition are ignored instead of replacing it. Null for fully managed tables.",
+			},
 		},
 		MarkdownDescription: tableResourceDescription,
 	}
 }
 
+func (r *Resource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		engineValidator{},
+		dataCopyValidator{},
+	}
+}
+
 func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -205,69 +437,35 @@ func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _
 }
 
 func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_table", "create")
+
+	// The table is read more than once during this operation, cache it to avoid redundant queries.
+	ctx = dbops.WithTableCache(ctx)
+
 	var plan Table
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx = withTarget(ctx, plan.Target)
 
-	// Convert columns from Terraform to dbops format
-	columns := make([]querybuilder.TableColumn, len(plan.Columns))
-	for i, col := range plan.Columns {
-		columns[i] = querybuilder.TableColumn{
-			Name:    col.Name.ValueString(),
-			Type:    col.Type.ValueString(),
-			Default: col.Default.ValueStringPointer(),
-			Comment: col.Comment.ValueStringPointer(),
-		}
-	}
-
-	// Convert order by list
-	orderBy := []string{}
-	if !plan.OrderBy.IsNull() {
-		diags = plan.OrderBy.ElementsAs(ctx, &orderBy, false)
-		resp.Diagnostics.Append(diags...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-	}
-
-	// Convert primary key list
-	primaryKey := []string{}
-	if !plan.PrimaryKey.IsNull() {
-		diags = plan.PrimaryKey.ElementsAs(ctx, &primaryKey, false)
-		resp.Diagnostics.Append(diags...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-	}
-
-	// Convert settings map
-	settings := make(map[string]string)
-	if !plan.Settings.IsNull() {
-		diags = plan.Settings.ElementsAs(ctx, &settings, false)
-		resp.Diagnostics.Append(diags...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
+	dbopsTable, diags := tableFromPlan(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	dbopsTable := dbops.Table{
-		DatabaseName: plan.DatabaseName.ValueString(),
-		Name:         plan.Name.ValueString(),
-		Engine:       plan.Engine.ValueString(),
-		Columns:      columns,
-		OrderBy:      orderBy,
-		PartitionBy:  plan.PartitionBy.ValueStringPointer(),
-		PrimaryKey:   primaryKey,
-		SampleBy:     plan.SampleBy.ValueStringPointer(),
-		TTL:          plan.TTL.ValueStringPointer(),
-		Settings:     settings,
-		Comment:      plan.Comment.ValueString(),
+	createStatement, err := dbops.CreateTableStatement(*dbopsTable, plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating table",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
 	}
 
-	table, err := r.client.CreateTable(ctx, dbopsTable, plan.ClusterName.ValueStringPointer())
+	table, err := r.client.CreateTable(ctx, *dbopsTable, plan.ClusterName.ValueStringPointer())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating table",
@@ -292,6 +490,7 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 		)
 		return
 	}
+	state.CreateStatement = types.StringValue(createStatement)
 
 	diags = resp.State.Set(ctx, state)
 	resp.Diagnostics.Append(diags...)
@@ -301,14 +500,24 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 }
 
 func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_table", "read")
+
 	var plan Table
 	diags := req.State.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx = withTarget(ctx, plan.Target)
 
 	state, err := r.syncTableState(ctx, plan.UUID.ValueString(), plan.ClusterName.ValueStringPointer(), &plan)
+	if dbops.IsRestrictedRead(err) {
+		resp.Diagnostics.AddWarning(
+			"Unable to Refresh ClickHouse Table",
+			"Not allowed to read the table, keeping the prior state: "+err.Error(),
+		)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error syncing table",
@@ -329,6 +538,11 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 }
 
 func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_table", "update")
+
+	// The table is read more than once during this operation, cache it to avoid redundant queries.
+	ctx = dbops.WithTableCache(ctx)
+
 	var plan, state Table
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -337,6 +551,66 @@ func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx = withTarget(ctx, state.Target)
+
+	// ALTERs run on the cluster of the plan, so that setting cluster_name on an existing table makes them reach every
+	// replica.
+	clusterName := plan.ClusterName.ValueStringPointer()
+
+	// Rename the table first, the other changes are then applied to it under its new name
+	if plan.Name.ValueString() != state.Name.ValueString() {
+		err := r.client.RenameTable(ctx, state.DatabaseName.ValueString(), state.Name.ValueString(), plan.Name.ValueString(), clusterName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error renaming table",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+		state.Name = plan.Name
+	}
+
+	// Rename columns before the other changes, which then see them under their new name. Key columns can't be renamed,
+	// ModifyPlan makes the table be replaced for them.
+	orderBy, primaryKey, diags := keyColumns(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	columnsToRename := make(map[string]string)
+	for from, to := range columnRenames(state.Columns, plan.Columns) {
+		if !slices.Contains(orderBy, from) && !slices.Contains(primaryKey, from) {
+			columnsToRename[from] = to
+		}
+	}
+	if len(columnsToRename) > 0 {
+		err := r.client.RenameTableColumns(ctx, state.DatabaseName.ValueString(), state.Name.ValueString(), columnsToRename, clusterName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error renaming columns of table",
+				fmt.Sprintf("Failed to rename columns: %+v\n", err),
+			)
+			return
+		}
+		state.Columns = renameColumns(state.Columns, columnsToRename)
+	}
+
+	if plan.UpdateStrategy.ValueString() == updateStrategyShadowAndExchange || plan.PreserveDataOnReplace.ValueBool() {
+		columnChanges, diags := incompatibleColumnChanges(ctx, state, plan)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if len(columnChanges) > 0 || len(incompatibleAttributeChanges(state, plan)) > 0 {
+			if plan.UpdateStrategy.ValueString() == updateStrategyShadowAndExchange {
+				r.shadowAndExchange(ctx, state, plan, resp)
+			} else {
+				r.replaceKeepingData(ctx, state, plan, resp)
+			}
+			return
+		}
+	}
 
 	// Compare columns to find additions and removals
 	stateColumns := make(map[string]Column)
@@ -349,22 +623,50 @@ func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp
 		planColumns[col.Name.ValueString()] = col
 	}
 
-	// Find new columns to add
+	// Find new columns to add, in the declared order. Each one is added after the column declared before it, which
+	// either exists or is added by a previous clause of the same statement, so the layout doesn't depend on history.
 	var columnsToAdd []querybuilder.TableColumn
+	var previousColumn *string
 	for _, planCol := range plan.Columns {
 		colName := planCol.Name.ValueString()
 		if _, exists := stateColumns[colName]; !exists {
 			// This is a new column
 			columnsToAdd = append(columnsToAdd, querybuilder.TableColumn{
-				Name:    planCol.Name.ValueString(),
-				Type:    planCol.Type.ValueString(),
-				Default: planCol.Default.ValueStringPointer(),
-				Comment: planCol.Comment.ValueStringPointer(),
+				Name:        planCol.Name.ValueString(),
+				Type:        planCol.Type.ValueString(),
+				Default:     planCol.Default.ValueStringPointer(),
+				DefaultKind: planCol.DefaultKind.ValueStringPointer(),
+				Comment:     planCol.Comment.ValueStringPointer(),
+				Codec:       planCol.Codec.ValueStringPointer(),
+				First:       previousColumn == nil,
+				After:       previousColumn,
+			})
+		}
+		previousColumn = &colName
+	}
+
+	// Find columns whose type or codec changed, ModifyPlan only lets compatible type changes through
+	var columnsToModify []querybuilder.TableColumn
+	var codecsToRemove []string
+	for _, planCol := range plan.Columns {
+		stateCol, exists := stateColumns[planCol.Name.ValueString()]
+		if !exists {
+			continue
+		}
+		codecChanged := columnCodecChanged(stateCol, planCol)
+		if columnTypeChanged(stateCol, planCol) || (codecChanged && !planCol.Codec.IsNull()) {
+			columnsToModify = append(columnsToModify, querybuilder.TableColumn{
+				Name:  planCol.Name.ValueString(),
+				Type:  planCol.Type.ValueString(),
+				Codec: planCol.Codec.ValueStringPointer(),
 			})
 		}
+		if codecChanged && planCol.Codec.IsNull() {
+			codecsToRemove = append(codecsToRemove, planCol.Name.ValueString())
+		}
 	}
 
-	// Find columns to remove
+	// Find columns to remove, in the order they were declared
 	var columnsToRemove []string
 	for _, stateCol := range state.Columns {
 		colName := stateCol.Name.ValueString()
@@ -374,6 +676,31 @@ func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp
 		}
 	}
 
+	// Drop the removed and changed indexes and projections first, a column can't be dropped while one of them uses it
+	indexesToDrop, indexesToAdd := indexChanges(state.Indexes, plan.Indexes)
+	for _, name := range indexesToDrop {
+		err := r.client.DropTableIndex(ctx, state.DatabaseName.ValueString(), state.Name.ValueString(), name, clusterName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error dropping index of table",
+				fmt.Sprintf("Failed to drop index %q: %+v\n", name, err),
+			)
+			return
+		}
+	}
+
+	projectionsToDrop, projectionsToAdd := projectionChanges(state.Projections, plan.Projections)
+	for _, name := range projectionsToDrop {
+		err := r.client.DropTableProjection(ctx, state.DatabaseName.ValueString(), state.Name.ValueString(), name, clusterName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error dropping projection of table",
+				fmt.Sprintf("Failed to drop projection %q: %+v\n", name, err),
+			)
+			return
+		}
+	}
+
 	// Remove columns if any
 	if len(columnsToRemove) > 0 {
 		// Check if drops are allowed
@@ -385,7 +712,7 @@ func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp
 			return
 		}
 		
-		err := r.client.DropTableColumns(ctx, state.DatabaseName.ValueString(), state.Name.ValueString(), columnsToRemove, state.ClusterName.ValueStringPointer())
+		err := r.client.DropTableColumns(ctx, state.DatabaseName.ValueString(), state.Name.ValueString(), columnsToRemove, clusterName)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error removing columns from table",
@@ -397,7 +724,7 @@ func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp
 
 	// Add new columns if any
 	if len(columnsToAdd) > 0 {
-		err := r.client.AddTableColumns(ctx, state.DatabaseName.ValueString(), state.Name.ValueString(), columnsToAdd, state.ClusterName.ValueStringPointer())
+		err := r.client.AddTableColumns(ctx, state.DatabaseName.ValueString(), state.Name.ValueString(), columnsToAdd, clusterName)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error adding columns to table",
@@ -407,27 +734,124 @@ func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp
 		}
 	}
 
-	// Sync state with the updated table
-	updatedState, err := r.syncTableState(ctx, state.UUID.ValueString(), state.ClusterName.ValueStringPointer(), &plan)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error syncing table state",
-			fmt.Sprintf("%+v\n", err),
-		)
-		return
+	// Change the type of columns if any
+	if len(columnsToModify) > 0 {
+		err := r.client.ModifyTableColumns(ctx, state.DatabaseName.ValueString(), state.Name.ValueString(), columnsToModify, clusterName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error modifying columns of table",
+				fmt.Sprintf("Failed to modify columns: %+v\n", err),
+			)
+			return
+		}
 	}
 
-	diags = resp.State.Set(ctx, updatedState)
-	resp.Diagnostics.Append(diags...)
-}
+	// Remove the codecs taken out of the configuration
+	if len(codecsToRemove) > 0 {
+		err := r.client.RemoveTableColumnCodecs(ctx, state.DatabaseName.ValueString(), state.Name.ValueString(), codecsToRemove, clusterName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error removing codecs of table columns",
+				fmt.Sprintf("Failed to remove codecs: %+v\n", err),
+			)
+			return
+		}
+	}
 
-func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var plan Table
+	// Change the TTL if needed, once the columns it uses exist
+	if !plan.TTL.Equal(state.TTL) {
+		err := r.client.ModifyTableTTL(ctx, state.DatabaseName.ValueString(), state.Name.ValueString(), plan.TTL.ValueStringPointer(), clusterName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error modifying TTL of table",
+				fmt.Sprintf("Failed to modify TTL: %+v\n", err),
+			)
+			return
+		}
+	}
+
+	// Change the settings if needed, ModifyPlan only lets the ones ALTER TABLE can change through
+	if modified, reset := settingChanges(settingsMap(state.Settings), settingsMap(plan.Settings)); len(modified) > 0 || len(reset) > 0 {
+		err := r.client.ModifyTableSettings(ctx, state.DatabaseName.ValueString(), state.Name.ValueString(), modified, reset, clusterName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error modifying settings of table",
+				fmt.Sprintf("Failed to modify settings: %+v\n", err),
+			)
+			return
+		}
+	}
+
+	// Add the new and changed indexes once the columns they use exist
+	for _, index := range indexesToAdd {
+		err := r.client.AddTableIndex(ctx, state.DatabaseName.ValueString(), state.Name.ValueString(), indexFromPlan(index), clusterName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error adding index to table",
+				fmt.Sprintf("Failed to add index %q: %+v\n", index.Name.ValueString(), err),
+			)
+			return
+		}
+
+		if index.Materialize.ValueBool() {
+			err = r.client.MaterializeTableIndex(ctx, state.DatabaseName.ValueString(), state.Name.ValueString(), index.Name.ValueString(), clusterName)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error materializing index of table",
+					fmt.Sprintf("Failed to materialize index %q: %+v\n", index.Name.ValueString(), err),
+				)
+				return
+			}
+		}
+	}
+
+	// Add the new and changed projections once the columns they use exist
+	for _, projection := range projectionsToAdd {
+		err := r.client.AddTableProjection(ctx, state.DatabaseName.ValueString(), state.Name.ValueString(), projectionFromPlan(projection), clusterName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error adding projection to table",
+				fmt.Sprintf("Failed to add projection %q: %+v\n", projection.Name.ValueString(), err),
+			)
+			return
+		}
+
+		if projection.Materialize.ValueBool() {
+			err = r.client.MaterializeTableProjection(ctx, state.DatabaseName.ValueString(), state.Name.ValueString(), projection.Name.ValueString(), clusterName)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error materializing projection of table",
+					fmt.Sprintf("Failed to materialize projection %q: %+v\n", projection.Name.ValueString(), err),
+				)
+				return
+			}
+		}
+	}
+
+	// Sync state with the updated table
+	updatedState, err := r.syncTableState(ctx, state.UUID.ValueString(), clusterName, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error syncing table state",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, updatedState)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_table", "delete")
+
+	var plan Table
 	diags := req.State.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx = withTarget(ctx, plan.Target)
 
 	// Check if drops are allowed
 	if !plan.AllowDrops.ValueBool() {
@@ -449,6 +873,8 @@ func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp
 }
 
 func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_table", "import")
+
 	// req.ID can either be in the form <cluster name>:<database name>:<table ref> or just <database name>:<table ref>
 	// table ref can either be the name or the UUID of the table.
 
@@ -477,6 +903,7 @@ func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequ
 	}
 
 	// Check if ref is a UUID
+	tableUUID := tableRef
 	_, err := uuid.Parse(tableRef)
 	if err != nil {
 		// Failed parsing UUID, try importing using the table name
@@ -489,21 +916,192 @@ func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequ
 			return
 		}
 
-		// Set basic attributes
-		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("uuid"), table.UUID)...)
-		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("database_name"), databaseName)...)
-		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), table.Name)...)
-		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("engine"), types.StringValue(table.Engine))...)
-		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("comment"), types.StringValue(table.Comment))...)
-	} else {
-		// User passed a UUID
-		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("uuid"), tableRef)...)
-		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("database_name"), databaseName)...)
+		tableUUID = table.UUID
+	}
+
+	// Populate every attribute, so that `terraform plan -generate-config-out` produces a complete configuration.
+	state, err := r.syncTableState(ctx, tableUUID, clusterName, nil)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error syncing table",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if state == nil {
+		resp.Diagnostics.AddError(
+			"Cannot find table",
+			fmt.Sprintf("table with UUID %q not found", tableUUID),
+		)
+		return
+	}
+
+	if state.DatabaseName.ValueString() != databaseName {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("table with UUID %q belongs to database %q, not %q", tableUUID, state.DatabaseName.ValueString(), databaseName),
+		)
+		return
 	}
 
-	if clusterName != nil {
-		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cluster_name"), clusterName)...)
+	diags := resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// keepUnmanagedDefinition plans no change to the definition of a table adopted read-only, since the resource can't
+// tell what applying it would drop: the attributes describing the table keep their state values.
+func keepUnmanagedDefinition(ctx context.Context, state Table, plan Table, changed bool, resp *resource.ModifyPlanResponse) {
+	plan.UUID = state.UUID
+	plan.Columns = state.Columns
+	plan.Indexes = state.Indexes
+	plan.Projections = state.Projections
+	plan.Engine = state.Engine
+	plan.SourceFunction = state.SourceFunction
+	plan.OrderBy = state.OrderBy
+	plan.PartitionBy = state.PartitionBy
+	plan.PrimaryKey = state.PrimaryKey
+	plan.SampleBy = state.SampleBy
+	plan.TTL = state.TTL
+	plan.Settings = state.Settings
+	plan.Comment = state.Comment
+	plan.SchemaJSON = state.SchemaJSON
+	plan.SchemaHash = state.SchemaHash
+	plan.CreateStatement = state.CreateStatement
+	plan.UnmanagedDefinition = state.UnmanagedDefinition
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+	if !changed {
+		return
 	}
+	resp.Diagnostics.AddWarning(
+		"Table adopted read-only",
+		fmt.Sprintf("The table %s.%s uses features the resource can't model (see 'unmanaged_definition'): changes to its definition are ignored rather than replacing it. Change it with SQL, or recreate it without those features to manage it.", state.DatabaseName.ValueString(), state.Name.ValueString()),
+	)
+}
+
+// requiresReplaceUnlessClusterAdded recreates the table when the cluster changes, except when it is set on a table
+// managed without one: the table already exists and the changes applied in place run on the cluster from now on.
+func requiresReplaceUnlessClusterAdded(_ context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = !req.StateValue.IsNull()
+}
+
+// tableFromPlan converts the planned table to its dbops definition.
+func tableFromPlan(ctx context.Context, plan Table) (*dbops.Table, diag.Diagnostics) {
+	// Convert columns from Terraform to dbops format
+	columns := make([]querybuilder.TableColumn, len(plan.Columns))
+	for i, col := range plan.Columns {
+		columns[i] = querybuilder.TableColumn{
+			Name:        col.Name.ValueString(),
+			Type:        col.Type.ValueString(),
+			Default:     col.Default.ValueStringPointer(),
+			DefaultKind: col.DefaultKind.ValueStringPointer(),
+			Comment:     col.Comment.ValueStringPointer(),
+			Codec:       col.Codec.ValueStringPointer(),
+		}
+	}
+
+	var indexes []querybuilder.TableIndex
+	for _, index := range plan.Indexes {
+		indexes = append(indexes, indexFromPlan(index))
+	}
+
+	var projections []querybuilder.TableProjection
+	for _, projection := range plan.Projections {
+		projections = append(projections, projectionFromPlan(projection))
+	}
+
+	// Convert order by list
+	orderBy := []string{}
+	if !plan.OrderBy.IsNull() {
+		diags := plan.OrderBy.ElementsAs(ctx, &orderBy, false)
+		if diags.HasError() {
+			return nil, diags
+		}
+	}
+
+	// Convert primary key list
+	primaryKey := []string{}
+	if !plan.PrimaryKey.IsNull() {
+		diags := plan.PrimaryKey.ElementsAs(ctx, &primaryKey, false)
+		if diags.HasError() {
+			return nil, diags
+		}
+	}
+
+	// Convert settings map
+	settings := make(map[string]string)
+	if !plan.Settings.IsNull() {
+		diags := plan.Settings.ElementsAs(ctx, &settings, false)
+		if diags.HasError() {
+			return nil, diags
+		}
+	}
+
+	var sourceFunction *querybuilder.TableFunction
+	if plan.SourceFunction != nil {
+		arguments := make(map[string]string)
+		if !plan.SourceFunction.Arguments.IsNull() {
+			diags := plan.SourceFunction.Arguments.ElementsAs(ctx, &arguments, false)
+			if diags.HasError() {
+				return nil, diags
+			}
+		}
+
+		sourceFunction = &querybuilder.TableFunction{
+			Name:            plan.SourceFunction.Name.ValueString(),
+			NamedCollection: plan.SourceFunction.NamedCollection.ValueString(),
+			Arguments:       arguments,
+		}
+	}
+
+	return &dbops.Table{
+		UUID:           plan.UUID.ValueString(),
+		DatabaseName:   plan.DatabaseName.ValueString(),
+		Name:           plan.Name.ValueString(),
+		Engine:         plan.Engine.ValueString(),
+		SourceFunction: sourceFunction,
+		Columns:        columns,
+		Indexes:        indexes,
+		Projections:    projections,
+		OrderBy:        orderBy,
+		PartitionBy:    plan.PartitionBy.ValueStringPointer(),
+		PrimaryKey:     primaryKey,
+		SampleBy:       plan.SampleBy.ValueStringPointer(),
+		TTL:            plan.TTL.ValueStringPointer(),
+		Settings:       settings,
+		Comment:        plan.Comment.ValueString(),
+	}, nil
+}
+
+// indexFromPlan converts a planned data skipping index to its querybuilder definition.
+func indexFromPlan(index Index) querybuilder.TableIndex {
+	return querybuilder.TableIndex{
+		Name:        index.Name.ValueString(),
+		Expression:  index.Expression.ValueString(),
+		Type:        index.Type.ValueString(),
+		Granularity: uint64(index.Granularity.ValueInt64()),
+	}
+}
+
+// projectionFromPlan converts a planned projection to its querybuilder definition.
+func projectionFromPlan(projection Projection) querybuilder.TableProjection {
+	return querybuilder.TableProjection{
+		Name:  projection.Name.ValueString(),
+		Query: projection.Query.ValueString(),
+	}
+}
+
+// withTarget makes the queries run with the returned context go to the replicas of the targeted shard, if any.
+func withTarget(ctx context.Context, target *Target) context.Context {
+	if target == nil {
+		return ctx
+	}
+
+	return dbops.WithShardTarget(ctx, dbops.ShardTarget{
+		ClusterName: target.Cluster.ValueString(),
+		ShardNum:    uint64(target.Shard.ValueInt64()),
+	})
 }
 
 // syncTableState reads table settings from clickhouse and returns a Table
@@ -518,17 +1116,42 @@ func (r *Resource) syncTableState(ctx context.Context, uuid string, clusterName
 		return nil, nil
 	}
 
-	// Convert columns
+	// Convert columns, keeping the planned type and codec when ClickHouse only reformatted them (e.g. JSON and Dynamic
+	// parameters, default codec levels) and the planned rename_from, which ClickHouse knows nothing about
+	plannedColumns := make(map[string]Column)
+	if plan != nil {
+		for _, col := range plan.Columns {
+			plannedColumns[col.Name.ValueString()] = col
+		}
+	}
 	columns := make([]Column, len(table.Columns))
 	for i, col := range table.Columns {
+		colType := types.StringValue(col.Type)
+		codec := types.StringPointerValue(col.Codec)
+		renameFrom := types.StringNull()
+		if planned, ok := plannedColumns[col.Name]; ok {
+			colType = schemadiff.KeepPlanned(planned.Type, &col.Type, schemadiff.SameType)
+			codec = schemadiff.KeepPlanned(planned.Codec, col.Codec, schemadiff.SameCodec)
+			renameFrom = planned.RenameFrom
+		}
+		defaultKind := querybuilder.ColumnDefaultKindDefault
+		if col.DefaultKind != nil {
+			defaultKind = *col.DefaultKind
+		}
 		columns[i] = Column{
-			Name:    types.StringValue(col.Name),
-			Type:    types.StringValue(col.Type),
-			Default: types.StringPointerValue(col.Default),
-			Comment: types.StringPointerValue(col.Comment),
+			Name:        types.StringValue(col.Name),
+			Type:        colType,
+			Default:     types.StringPointerValue(col.Default),
+			DefaultKind: types.StringValue(defaultKind),
+			Comment:     types.StringPointerValue(col.Comment),
+			Codec:       codec,
+			RenameFrom:  renameFrom,
 		}
 	}
 
+	indexes := syncIndexes(table.Indexes, plan)
+	projections := syncProjections(table.Projections, plan)
+
 	// Convert order by
 	orderByValues := make([]attr.Value, len(table.OrderBy))
 	for i, col := range table.OrderBy {
@@ -538,6 +1161,16 @@ func (r *Resource) syncTableState(ctx context.Context, uuid string, clusterName
 	if diags.HasError() {
 		return nil, errors.New("failed to create order by list")
 	}
+	if plan != nil {
+		// Keep the planned expressions when ClickHouse only reformatted them
+		same, diags := sameExpressions(ctx, plan.OrderBy, table.OrderBy)
+		if diags.HasError() {
+			return nil, errors.New("failed to parse planned order by")
+		}
+		if same {
+			orderByList = plan.OrderBy
+		}
+	}
 
 	// Convert primary key - handle auto-inference by ClickHouse
 	var primaryKeyList types.List
@@ -554,6 +1187,8 @@ func (r *Resource) syncTableState(ctx context.Context, uuid string, clusterName
 		// If plan had empty primary key but ClickHouse inferred one, keep plan's empty list
 		if len(plannedPrimaryKey) == 0 && len(table.PrimaryKey) > 0 {
 			primaryKeyList = plan.PrimaryKey
+		} else if !plan.PrimaryKey.IsNull() && schemadiff.SameExpressions(plannedPrimaryKey, table.PrimaryKey) {
+			primaryKeyList = plan.PrimaryKey
 		} else {
 			primaryKeyValues := make([]attr.Value, len(table.PrimaryKey))
 			for i, col := range table.PrimaryKey {
@@ -565,9 +1200,12 @@ func (r *Resource) syncTableState(ctx context.Context, uuid string, clusterName
 			}
 		}
 	} else {
-		primaryKeyValues := make([]attr.Value, len(table.PrimaryKey))
-		for i, col := range table.PrimaryKey {
-			primaryKeyValues[i] = types.StringValue(col)
+		primaryKeyValues := make([]attr.Value, 0, len(table.PrimaryKey))
+		// Without a plan (i.e. on import) leave out the primary key ClickHouse inferred from the order by.
+		if !slices.Equal(table.PrimaryKey, table.OrderBy) {
+			for _, col := range table.PrimaryKey {
+				primaryKeyValues = append(primaryKeyValues, types.StringValue(col))
+			}
 		}
 		primaryKeyList, diags = types.ListValue(types.StringType, primaryKeyValues)
 		if diags.HasError() {
@@ -575,9 +1213,13 @@ func (r *Resource) syncTableState(ctx context.Context, uuid string, clusterName
 		}
 	}
 
-	// Convert settings - only include settings that were explicitly set in the plan
+	// Convert settings - only include settings that were explicitly set in the plan, or all of them on import
 	settingsMap := make(map[string]attr.Value)
-	if plan != nil && !plan.Settings.IsNull() {
+	if plan == nil {
+		for k, v := range table.Settings {
+			settingsMap[k] = types.StringValue(v)
+		}
+	} else if !plan.Settings.IsNull() {
 		// Get planned settings
 		var plannedSettings map[string]string
 		diags = plan.Settings.ElementsAs(ctx, &plannedSettings, false)
@@ -585,10 +1227,8 @@ func (r *Resource) syncTableState(ctx context.Context, uuid string, clusterName
 			return nil, errors.New("failed to parse planned settings")
 		}
 		// Only include settings that were in the plan
-		for k := range plannedSettings {
-			if v, ok := table.Settings[k]; ok {
-				settingsMap[k] = types.StringValue(v)
-			}
+		for k, v := range schemadiff.PlannedSettings(plannedSettings, table.Settings) {
+			settingsMap[k] = types.StringValue(v)
 		}
 	}
 	settings, diags := types.MapValue(types.StringType, settingsMap)
@@ -596,125 +1236,366 @@ func (r *Resource) syncTableState(ctx context.Context, uuid string, clusterName
 		return nil, errors.New("failed to create settings map")
 	}
 
-	// Handle engine normalization - especially for ClickHouse Cloud
+	// Keep the planned engine when ClickHouse only expanded its parameters, or replaced it with its Shared
+	// counterpart on ClickHouse Cloud.
 	engine := types.StringValue(table.Engine)
-	if plan != nil && !plan.Engine.IsNull() {
-		// Check if this is a ClickHouse Cloud engine transformation
-		plannedEngine := plan.Engine.ValueString()
-		actualEngine := table.Engine
-		
-		// Normalize engine names for comparison (remove parentheses and parameters)
-		normalizedPlanned := normalizeEngineName(plannedEngine)
-		normalizedActual := normalizeEngineName(actualEngine)
-		
-		// Check if this is an expected Cloud transformation
-		if isCloudEngineTransformation(normalizedPlanned, normalizedActual) {
-			// Keep the planned engine to avoid drift
-			engine = plan.Engine
-		} else if normalizedPlanned == normalizedActual {
-			// Same engine type, just different formatting - keep planned value
-			engine = plan.Engine
-		} else {
-			// This is an actual engine change - use the actual value
-			engine = types.StringValue(table.Engine)
+	if plan != nil {
+		engine = schemadiff.KeepPlanned(plan.Engine, &table.Engine, schemadiff.SameEngine)
+	}
+
+	// Tables created from a source function have no engine in the configuration, only the storage ClickHouse picked.
+	if table.SourceFunction != nil {
+		engine = types.StringNull()
+	}
+
+	var sourceFunction *SourceFunction
+	if table.SourceFunction != nil {
+		arguments := make(map[string]attr.Value)
+		for k, v := range table.SourceFunction.Arguments {
+			arguments[k] = types.StringValue(v)
+		}
+		argumentsMap, diags := types.MapValue(types.StringType, arguments)
+		if diags.HasError() {
+			return nil, errors.New("failed to create source function arguments map")
+		}
+
+		sourceFunction = &SourceFunction{
+			Name:            types.StringValue(table.SourceFunction.Name),
+			NamedCollection: types.StringValue(table.SourceFunction.NamedCollection),
+			Arguments:       argumentsMap,
 		}
 	}
 
-	// For TTL, use the plan value if available to avoid normalization issues
+	partitionBy := types.StringPointerValue(table.PartitionBy)
+	sampleBy := types.StringPointerValue(table.SampleBy)
 	ttl := types.StringPointerValue(table.TTL)
-	if plan != nil && !plan.TTL.IsNull() && table.TTL != nil {
-		ttl = plan.TTL
+	if plan != nil {
+		partitionBy = schemadiff.KeepPlanned(plan.PartitionBy, table.PartitionBy, schemadiff.SameExpression)
+		sampleBy = schemadiff.KeepPlanned(plan.SampleBy, table.SampleBy, schemadiff.SameExpression)
+		ttl = schemadiff.KeepPlanned(plan.TTL, table.TTL, schemadiff.SameExpression)
 	}
 
-	// Preserve the allow_drops setting from the plan
-	var allowDrops types.Bool
+	// Preserve the allow_drops and update strategy settings from the plan
+	var allowDrops, copyDataOnExchange, preserveDataOnReplace types.Bool
+	var updateStrategy types.String
+	var target *Target
+	createStatement := types.StringNull()
 	if plan != nil {
+		target = plan.Target
+		createStatement = plan.CreateStatement
 		allowDrops = plan.AllowDrops
+		updateStrategy = plan.UpdateStrategy
+		copyDataOnExchange = plan.CopyDataOnExchange
+		preserveDataOnReplace = plan.PreserveDataOnReplace
 	} else {
 		allowDrops = types.BoolValue(false)
+		updateStrategy = types.StringValue(updateStrategyRecreate)
+		copyDataOnExchange = types.BoolValue(false)
+		preserveDataOnReplace = types.BoolValue(false)
+	}
+
+	// Tables using features the resource can't model are adopted read-only, with their definition exposed as is.
+	unmanagedDefinition := types.StringNull()
+	if len(table.Unsupported) > 0 {
+		unmanagedDefinition = types.StringValue(table.CreateQuery)
+	}
+
+	tableSchemaJSON, err := schemaJSON(table)
+	if err != nil {
+		return nil, err
+	}
+
+	tableSchemaHash, err := schemaHash(table)
+	if err != nil {
+		return nil, err
 	}
 
 	state := &Table{
-		ClusterName:  types.StringPointerValue(clusterName),
-		UUID:         types.StringValue(table.UUID),
-		DatabaseName: types.StringValue(table.DatabaseName),
-		Name:         types.StringValue(table.Name),
-		Columns:      columns,
-		Engine:       engine,
-		OrderBy:      orderByList,
-		PartitionBy:  types.StringPointerValue(table.PartitionBy),
-		PrimaryKey:   primaryKeyList,
-		SampleBy:     types.StringPointerValue(table.SampleBy),
-		TTL:          ttl,
-		Settings:     settings,
-		Comment:      types.StringValue(table.Comment),
-		AllowDrops:   allowDrops,
+		ClusterName:           types.StringPointerValue(clusterName),
+		UUID:                  types.StringValue(table.UUID),
+		DatabaseName:          types.StringValue(table.DatabaseName),
+		Name:                  types.StringValue(table.Name),
+		Columns:               columns,
+		Indexes:               indexes,
+		Projections:           projections,
+		Engine:                engine,
+		SourceFunction:        sourceFunction,
+		Target:                target,
+		OrderBy:               orderByList,
+		PartitionBy:           partitionBy,
+		PrimaryKey:            primaryKeyList,
+		SampleBy:              sampleBy,
+		TTL:                   ttl,
+		Settings:              settings,
+		Comment:               types.StringValue(table.Comment),
+		AllowDrops:            allowDrops,
+		UpdateStrategy:        updateStrategy,
+		CopyDataOnExchange:    copyDataOnExchange,
+		PreserveDataOnReplace: preserveDataOnReplace,
+		SchemaJSON:            types.StringValue(tableSchemaJSON),
+		SchemaHash:            types.StringValue(tableSchemaHash),
+		CreateStatement:       createStatement,
+		UnmanagedDefinition:   unmanagedDefinition,
 	}
 
 	return state, nil
 }
 
-// normalizeEngineName extracts the base engine name without parameters
-func normalizeEngineName(engine string) string {
-	// Remove everything after the first parenthesis
-	if idx := strings.Index(engine, "("); idx != -1 {
-		return strings.TrimSpace(engine[:idx])
+// indexChanges returns the names of the indexes to drop and the indexes to add to go from the state to the plan. An
+// index whose definition changed is dropped and added again. Nothing changes when the plan doesn't manage indexes.
+func indexChanges(state, plan []Index) ([]string, []Index) {
+	if plan == nil {
+		return nil, nil
+	}
+
+	stateIndexes := make(map[string]Index)
+	for _, index := range state {
+		stateIndexes[index.Name.ValueString()] = index
+	}
+	planIndexes := make(map[string]Index)
+	for _, index := range plan {
+		planIndexes[index.Name.ValueString()] = index
+	}
+
+	var toDrop []string
+	for _, index := range state {
+		name := index.Name.ValueString()
+		if planned, ok := planIndexes[name]; !ok || indexChanged(index, planned) {
+			toDrop = append(toDrop, name)
+		}
 	}
-	return strings.TrimSpace(engine)
+
+	var toAdd []Index
+	for _, index := range plan {
+		if current, ok := stateIndexes[index.Name.ValueString()]; !ok || indexChanged(current, index) {
+			toAdd = append(toAdd, index)
+		}
+	}
+
+	return toDrop, toAdd
 }
 
-// isCloudEngineTransformation checks if the engine change is an expected ClickHouse Cloud transformation
-func isCloudEngineTransformation(planned, actual string) bool {
-	// Map of engines that get transformed in ClickHouse Cloud
-	cloudTransformations := map[string]string{
-		"MergeTree":          "SharedMergeTree",
-		"ReplacingMergeTree": "SharedReplacingMergeTree",
-		"SummingMergeTree":   "SharedSummingMergeTree",
-		"AggregatingMergeTree": "SharedAggregatingMergeTree",
-		"CollapsingMergeTree": "SharedCollapsingMergeTree",
-		"VersionedCollapsingMergeTree": "SharedVersionedCollapsingMergeTree",
-	}
-	
-	// Check if this is a known transformation
-	if expectedEngine, ok := cloudTransformations[planned]; ok {
-		return actual == expectedEngine
-	}
-	
-	// Also check the reverse (in case someone explicitly uses SharedMergeTree)
-	for original, shared := range cloudTransformations {
-		if planned == shared && actual == original {
-			return true
-		}
-	}
-	
-	return false
+// indexChanged reports whether the definition of an index differs between the state and the plan.
+func indexChanged(state, plan Index) bool {
+	return !schemadiff.SameExpression(state.Expression.ValueString(), plan.Expression.ValueString()) ||
+		!schemadiff.SameExpression(state.Type.ValueString(), plan.Type.ValueString()) ||
+		!state.Granularity.Equal(plan.Granularity)
+}
+
+// syncIndexes returns the data skipping indexes for the state. The planned indexes are kept in their order, with the
+// planned expression and type when ClickHouse only reformatted them, followed by the other indexes of the table.
+// Vector similarity indexes are left out, they are managed by their own resource.
+func syncIndexes(actual []querybuilder.TableIndex, plan *Table) []Index {
+	if plan != nil && plan.Indexes == nil {
+		// Indexes are not managed.
+		return nil
+	}
+
+	actualIndexes := make(map[string]querybuilder.TableIndex)
+	for _, index := range actual {
+		actualIndexes[index.Name] = index
+	}
+
+	var indexes []Index
+	planned := make(map[string]bool)
+	if plan != nil {
+		for _, index := range plan.Indexes {
+			name := index.Name.ValueString()
+			a, ok := actualIndexes[name]
+			if !ok {
+				continue
+			}
+			planned[name] = true
+			indexes = append(indexes, Index{
+				Name:        types.StringValue(name),
+				Expression:  schemadiff.KeepPlanned(index.Expression, &a.Expression, schemadiff.SameExpression),
+				Type:        schemadiff.KeepPlanned(index.Type, &a.Type, schemadiff.SameExpression),
+				Granularity: types.Int64Value(int64(a.Granularity)),
+				Materialize: index.Materialize,
+			})
+		}
+	}
+
+	for _, index := range actual {
+		if planned[index.Name] || strings.HasPrefix(index.Type, "vector_similarity(") {
+			continue
+		}
+		indexes = append(indexes, Index{
+			Name:        types.StringValue(index.Name),
+			Expression:  types.StringValue(index.Expression),
+			Type:        types.StringValue(index.Type),
+			Granularity: types.Int64Value(int64(index.Granularity)),
+			Materialize: types.BoolValue(false),
+		})
+	}
+
+	if plan != nil && indexes == nil {
+		// Keep an empty list rather than null when every index was removed.
+		indexes = []Index{}
+	}
+
+	return indexes
+}
+
+// projectionChanges returns the names of the projections to drop and the projections to add to go from the state to
+// the plan. A projection whose query changed is dropped and added again. Nothing changes when the plan doesn't manage
+// projections.
+func projectionChanges(state, plan []Projection) ([]string, []Projection) {
+	if plan == nil {
+		return nil, nil
+	}
+
+	stateProjections := make(map[string]Projection)
+	for _, projection := range state {
+		stateProjections[projection.Name.ValueString()] = projection
+	}
+	planProjections := make(map[string]Projection)
+	for _, projection := range plan {
+		planProjections[projection.Name.ValueString()] = projection
+	}
+
+	var toDrop []string
+	for _, projection := range state {
+		name := projection.Name.ValueString()
+		if planned, ok := planProjections[name]; !ok || !schemadiff.SameExpression(projection.Query.ValueString(), planned.Query.ValueString()) {
+			toDrop = append(toDrop, name)
+		}
+	}
+
+	var toAdd []Projection
+	for _, projection := range plan {
+		if current, ok := stateProjections[projection.Name.ValueString()]; !ok || !schemadiff.SameExpression(current.Query.ValueString(), projection.Query.ValueString()) {
+			toAdd = append(toAdd, projection)
+		}
+	}
+
+	return toDrop, toAdd
+}
+
+// syncProjections returns the projections for the state, like syncIndexes does for the indexes.
+func syncProjections(actual []querybuilder.TableProjection, plan *Table) []Projection {
+	if plan != nil && plan.Projections == nil {
+		// Projections are not managed.
+		return nil
+	}
+
+	actualProjections := make(map[string]querybuilder.TableProjection)
+	for _, projection := range actual {
+		actualProjections[projection.Name] = projection
+	}
+
+	var projections []Projection
+	planned := make(map[string]bool)
+	if plan != nil {
+		for _, projection := range plan.Projections {
+			name := projection.Name.ValueString()
+			a, ok := actualProjections[name]
+			if !ok {
+				continue
+			}
+			planned[name] = true
+			projections = append(projections, Projection{
+				Name:        types.StringValue(name),
+				Query:       schemadiff.KeepPlanned(projection.Query, &a.Query, schemadiff.SameExpression),
+				Materialize: projection.Materialize,
+			})
+		}
+	}
+
+	for _, projection := range actual {
+		if planned[projection.Name] {
+			continue
+		}
+		projections = append(projections, Projection{
+			Name:        types.StringValue(projection.Name),
+			Query:       types.StringValue(projection.Query),
+			Materialize: types.BoolValue(false),
+		})
+	}
+
+	if plan != nil && projections == nil {
+		projections = []Projection{}
+	}
+
+	return projections
+}
+
+// sameExpressions reports whether the planned list of expressions only differs by formatting from the one ClickHouse
+// reports.
+func sameExpressions(ctx context.Context, planned types.List, actual []string) (bool, diag.Diagnostics) {
+	if planned.IsNull() || planned.IsUnknown() {
+		return false, nil
+	}
+
+	var expressions []string
+	diags := planned.ElementsAs(ctx, &expressions, false)
+	if diags.HasError() {
+		return false, diags
+	}
+
+	return schemadiff.SameExpressions(expressions, actual), nil
 }
 
 // ModifyPlan checks if column changes require table recreation
 func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_table", "plan")
+
 	// If the entire resource is being destroyed, skip this check
 	if req.Plan.Raw.IsNull() {
 		return
 	}
 
+	clustername.ValidatePlan(ctx, r.client, req.Plan, &resp.Diagnostics)
+	defaultdatabase.ResolvePlan(ctx, r.client, req, resp)
+
+	var plan Table
+	diags := resp.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	protecteddatabase.Validate(r.client, path.Root("database_name"), plan.DatabaseName, &resp.Diagnostics)
+	comment.Validate(r.client, path.Root("comment"), plan.Comment, &resp.Diagnostics)
+	for i, col := range plan.Columns {
+		comment.Validate(r.client, path.Root("columns").AtListIndex(i).AtName("comment"), col.Comment, &resp.Diagnostics)
+		switch col.DefaultKind.ValueString() {
+		case querybuilder.ColumnDefaultKindMaterialized, querybuilder.ColumnDefaultKindAlias:
+			if col.Default.IsNull() {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("columns").AtListIndex(i).AtName("default"),
+					"Missing column expression",
+					fmt.Sprintf("Column '%s' is %s and needs the expression computing it in 'default'.", col.Name.ValueString(), col.DefaultKind.ValueString()),
+				)
+			}
+		}
+	}
+
 	// If this is a create operation, skip this check
 	if req.State.Raw.IsNull() {
+		if req.Config.Raw.IsFullyKnown() {
+			planCreateStatement(ctx, plan, resp)
+		}
 		return
 	}
 
-	var plan, state Table
-	diags := req.Plan.Get(ctx, &plan)
-	resp.Diagnostics.Append(diags...)
+	var state Table
 	diags = req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Build maps for comparison
-	stateColumns := make(map[string]Column)
-	for _, col := range state.Columns {
-		stateColumns[col.Name.ValueString()] = col
+	if !state.UnmanagedDefinition.IsNull() {
+		keepUnmanagedDefinition(ctx, state, plan, !resp.Plan.Raw.Equal(req.State.Raw), resp)
+		return
+	}
+
+	if state.ClusterName.IsNull() && !plan.ClusterName.IsNull() {
+		resp.Diagnostics.AddWarning(
+			"Cluster set on an existing table",
+			fmt.Sprintf("The table is kept and the changes to it now run ON CLUSTER '%s'. It is not created on the replicas missing it, make sure it exists on every replica of the cluster.", plan.ClusterName.ValueString()),
+		)
 	}
 
 	planColumns := make(map[string]Column)
@@ -722,60 +1603,119 @@ func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanReques
 		planColumns[col.Name.ValueString()] = col
 	}
 
-	// Get order by columns for checking
-	var orderByColumns []string
-	if !state.OrderBy.IsNull() {
-		diags = state.OrderBy.ElementsAs(ctx, &orderByColumns, false)
-		if diags.HasError() {
-			resp.Diagnostics.Append(diags...)
+	// Check for removed columns, renamed ones are kept
+	for _, stateCol := range renameColumns(state.Columns, columnRenames(state.Columns, plan.Columns)) {
+		colName := stateCol.Name.ValueString()
+		if _, exists := planColumns[colName]; !exists && !plan.AllowDrops.ValueBool() {
+			resp.Diagnostics.AddError(
+				"Column removal not allowed",
+				fmt.Sprintf("Column '%s' cannot be removed because 'allow_drops' is set to false. To allow column removal, set 'allow_drops = true' in your table configuration.", colName),
+			)
 			return
 		}
 	}
 
-	// Create a set of order by columns for quick lookup
-	orderBySet := make(map[string]bool)
-	for _, col := range orderByColumns {
-		orderBySet[col] = true
+	columnChanges, diags := incompatibleColumnChanges(ctx, state, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	// Check for removed or modified columns
-	requiresReplace := false
-	for _, stateCol := range state.Columns {
-		colName := stateCol.Name.ValueString()
-		planCol, exists := planColumns[colName]
-		
-		if !exists {
-			// Column was removed - check if drops are allowed
-			if !plan.AllowDrops.ValueBool() {
+	switch plan.UpdateStrategy.ValueString() {
+	case updateStrategyAlterInPlace:
+		if changes := append(columnChanges, incompatibleAttributeChanges(state, plan)...); len(changes) > 0 {
+			resp.Diagnostics.AddError(
+				"Table change cannot be applied in place",
+				fmt.Sprintf("ALTER TABLE can't apply these changes: %s. Set 'update_strategy' to 'recreate' or 'shadow_and_exchange' to apply them with a new table.", strings.Join(changes, ", ")),
+			)
+			return
+		}
+	case updateStrategyShadowAndExchange:
+		if changes := append(columnChanges, incompatibleAttributeChanges(state, plan)...); len(changes) > 0 {
+			var configUUID types.String
+			resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("uuid"), &configUUID)...)
+			if !configUUID.IsNull() {
 				resp.Diagnostics.AddError(
-					"Column removal not allowed",
-					fmt.Sprintf("Column '%s' cannot be removed because 'allow_drops' is set to false. To allow column removal, set 'allow_drops = true' in your table configuration.", colName),
+					"Table change cannot be applied with a shadow table",
+					"The shadow table swapped in gets a new UUID, which conflicts with the 'uuid' set in the configuration. Remove 'uuid' or set 'update_strategy' to 'recreate'.",
 				)
 				return
 			}
-			
-			// Check if it's in ORDER BY
-			if orderBySet[colName] {
+
+			detail := fmt.Sprintf("A new table will be created and swapped with the existing one because %s.", strings.Join(changes, ", "))
+			if !plan.CopyDataOnExchange.ValueBool() {
+				detail += " The data is not copied, set 'copy_data_on_exchange = true' to keep it."
+			}
+			resp.Diagnostics.AddWarning("Table will be replaced by a shadow table", detail)
+
+			// The table swapped in has a new UUID and structure, and is created under a name only known during apply.
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("uuid"), types.StringUnknown())...)
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("schema_hash"), types.StringUnknown())...)
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("create_statement"), types.StringUnknown())...)
+		}
+	default:
+		if plan.PreserveDataOnReplace.ValueBool() {
+			if changes := append(columnChanges, incompatibleAttributeChanges(state, plan)...); len(changes) > 0 {
+				var configUUID types.String
+				resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("uuid"), &configUUID)...)
+				if !configUUID.IsNull() {
+					resp.Diagnostics.AddError(
+						"Table change cannot be applied keeping the data",
+						"The table created in place of the existing one gets a new UUID, which conflicts with the 'uuid' set in the configuration. Remove 'uuid' or 'preserve_data_on_replace'.",
+					)
+					return
+				}
+
 				resp.Diagnostics.AddWarning(
-					"Cannot remove column in ORDER BY",
-					fmt.Sprintf("Column '%s' is part of the table's ORDER BY clause and cannot be removed. This requires recreating the table.", colName),
+					"Table will be replaced keeping its data",
+					fmt.Sprintf("A new table will be created in place of the existing one, and the data of the columns both tables have copied into it, because %s.", strings.Join(changes, ", ")),
 				)
-				requiresReplace = true
+
+				// The table created in place of the existing one has a new UUID and structure.
+				resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("uuid"), types.StringUnknown())...)
+				resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("schema_hash"), types.StringUnknown())...)
+				if req.Config.Raw.IsFullyKnown() {
+					plan.UUID = types.StringNull()
+					planCreateStatement(ctx, plan, resp)
+				} else {
+					resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("create_statement"), types.StringUnknown())...)
+				}
 			}
-			// Otherwise, column can be dropped without recreation
-		} else if !stateCol.Type.Equal(planCol.Type) {
-			// Column type changed
+			break
+		}
+
+		for _, change := range columnChanges {
 			resp.Diagnostics.AddWarning(
-				"Column type change requires table recreation",
-				fmt.Sprintf("Column '%s' type change from '%s' to '%s' requires recreating the table.", 
-					colName, stateCol.Type.ValueString(), planCol.Type.ValueString()),
+				"Column change requires table recreation",
+				fmt.Sprintf("The table will be recreated because %s.", change),
 			)
-			requiresReplace = true
 		}
+		if len(columnChanges) > 0 {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("columns"))
+		}
+	}
+
+	// Columns added or dropped in place change the structure, the hash is only known once the table is altered.
+	if columnsStructureChanged(state.Columns, plan.Columns) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("schema_hash"), types.StringUnknown())...)
+	}
+}
+
+// planCreateStatement sets create_statement in the plan to the CREATE TABLE statement creating the planned table.
+func planCreateStatement(ctx context.Context, plan Table, resp *resource.ModifyPlanResponse) {
+	dbopsTable, diags := tableFromPlan(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	// If recreation is required, mark the resource for replacement
-	if requiresReplace {
-		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("columns"))
+	createStatement, err := dbops.CreateTableStatement(*dbopsTable, plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error rendering table",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
 	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("create_statement"), createStatement)...)
 }
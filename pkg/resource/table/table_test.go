@@ -0,0 +1,76 @@
+package table
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// tableClient is a dbops.Client that only serves GetTable.
+type tableClient struct {
+	dbops.Client
+	table *dbops.Table
+}
+
+func (c *tableClient) GetTable(_ context.Context, _ string, _ *string) (*dbops.Table, error) {
+	return c.table, nil
+}
+
+func Test_syncTableState_ttl(t *testing.T) {
+	tests := []struct {
+		name   string
+		actual *string
+		plan   types.String
+		want   types.String
+	}{
+		{
+			name:   "Reformatted by ClickHouse",
+			actual: strPtr("ts + toIntervalDay(30)"),
+			plan:   types.StringValue("ts  +  toIntervalDay( 30 )"),
+			want:   types.StringValue("ts  +  toIntervalDay( 30 )"),
+		},
+		{
+			name:   "Changed outside Terraform",
+			actual: strPtr("ts + toIntervalDay(7)"),
+			plan:   types.StringValue("ts + toIntervalDay(30)"),
+			want:   types.StringValue("ts + toIntervalDay(7)"),
+		},
+		{
+			name:   "Removed outside Terraform",
+			actual: nil,
+			plan:   types.StringValue("ts + toIntervalDay(30)"),
+			want:   types.StringNull(),
+		},
+		{
+			name:   "Added outside Terraform",
+			actual: strPtr("ts + toIntervalDay(30)"),
+			plan:   types.StringNull(),
+			want:   types.StringValue("ts + toIntervalDay(30)"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Resource{client: &tableClient{table: &dbops.Table{
+				UUID:         "uuid",
+				DatabaseName: "db",
+				Name:         "events",
+				Engine:       "MergeTree",
+				Columns:      []querybuilder.TableColumn{{Name: "ts", Type: "DateTime"}},
+				OrderBy:      []string{"ts"},
+				TTL:          tt.actual,
+			}}}
+
+			state, err := r.syncTableState(context.Background(), "uuid", nil, &Table{TTL: tt.plan})
+			if err != nil {
+				t.Fatalf("syncTableState() error = %v", err)
+			}
+			if !state.TTL.Equal(tt.want) {
+				t.Errorf("syncTableState() ttl = %v, want %v", state.TTL, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,424 @@
+package table
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/schemadiff"
+)
+
+const (
+	// updateStrategyAlterInPlace only applies the changes ALTER TABLE supports and rejects the others at plan time.
+	updateStrategyAlterInPlace = "alter_in_place"
+	// updateStrategyRecreate drops the table and creates it again, losing its data.
+	updateStrategyRecreate = "recreate"
+	// updateStrategyShadowAndExchange creates the new table next to the existing one and swaps them with EXCHANGE TABLES.
+	updateStrategyShadowAndExchange = "shadow_and_exchange"
+)
+
+var updateStrategies = []string{updateStrategyAlterInPlace, updateStrategyRecreate, updateStrategyShadowAndExchange}
+
+const replaceWithRecreateDescription = "Requires replacing the table when update_strategy is recreate"
+
+// recreateStrategy reports whether the planned update strategy replaces the table on changes ALTER TABLE can't apply.
+// With preserve_data_on_replace, the table is replaced by Update instead, see replaceKeepingData.
+func recreateStrategy(ctx context.Context, plan tfsdk.Plan) (bool, diag.Diagnostics) {
+	var strategy types.String
+	var preserveData types.Bool
+	diags := plan.GetAttribute(ctx, path.Root("update_strategy"), &strategy)
+	diags.Append(plan.GetAttribute(ctx, path.Root("preserve_data_on_replace"), &preserveData)...)
+
+	if preserveData.ValueBool() {
+		return false, diags
+	}
+
+	return strategy.IsNull() || strategy.IsUnknown() || strategy.ValueString() == updateStrategyRecreate, diags
+}
+
+func requiresReplaceStringWithRecreate() planmodifier.String {
+	return stringplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+			resp.RequiresReplace, resp.Diagnostics = recreateStrategy(ctx, req.Plan)
+		},
+		replaceWithRecreateDescription,
+		replaceWithRecreateDescription,
+	)
+}
+
+// requiresReplaceSettingsWithRecreate replaces the table on settings changes with the recreate update strategy, when
+// they can't be applied with MODIFY SETTING and RESET SETTING.
+func requiresReplaceSettingsWithRecreate() planmodifier.Map {
+	return mapplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.MapRequest, resp *mapplanmodifier.RequiresReplaceIfFuncResponse) {
+			recreate, diags := recreateStrategy(ctx, req.Plan)
+			resp.Diagnostics.Append(diags...)
+			if !recreate {
+				return
+			}
+
+			var engine types.String
+			resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("engine"), &engine)...)
+			resp.RequiresReplace = req.PlanValue.IsUnknown() || settingsNeedNewTable(engine.ValueString(), settingsMap(req.StateValue), settingsMap(req.PlanValue))
+		},
+		replaceWithRecreateDescription,
+		replaceWithRecreateDescription,
+	)
+}
+
+func requiresReplaceListWithRecreate() planmodifier.List {
+	return listplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.ListRequest, resp *listplanmodifier.RequiresReplaceIfFuncResponse) {
+			resp.RequiresReplace, resp.Diagnostics = recreateStrategy(ctx, req.Plan)
+		},
+		replaceWithRecreateDescription,
+		replaceWithRecreateDescription,
+	)
+}
+
+// incompatibleAttributeChanges returns the table-level attributes changed from state to plan that ALTER TABLE can't apply.
+func incompatibleAttributeChanges(state Table, plan Table) []string {
+	attributes := []struct {
+		name  string
+		state attr.Value
+		plan  attr.Value
+	}{
+		{"engine", state.Engine, plan.Engine},
+		{"order_by", state.OrderBy, plan.OrderBy},
+		{"partition_by", state.PartitionBy, plan.PartitionBy},
+		{"primary_key", state.PrimaryKey, plan.PrimaryKey},
+		{"sample_by", state.SampleBy, plan.SampleBy},
+		{"comment", state.Comment, plan.Comment},
+	}
+
+	changes := make([]string, 0)
+	for _, a := range attributes {
+		if !a.plan.IsUnknown() && !a.plan.Equal(a.state) {
+			changes = append(changes, fmt.Sprintf("`%s` changed", a.name))
+		}
+	}
+	if !plan.Settings.IsUnknown() && !plan.Settings.Equal(state.Settings) && settingsNeedNewTable(plan.Engine.ValueString(), settingsMap(state.Settings), settingsMap(plan.Settings)) {
+		changes = append(changes, "`settings` changed")
+	}
+
+	return changes
+}
+
+// keyColumns returns the ORDER BY and PRIMARY KEY expressions of the table.
+func keyColumns(ctx context.Context, table Table) ([]string, []string, diag.Diagnostics) {
+	var orderBy, primaryKey []string
+	if !table.OrderBy.IsNull() {
+		diags := table.OrderBy.ElementsAs(ctx, &orderBy, false)
+		if diags.HasError() {
+			return nil, nil, diags
+		}
+	}
+	if !table.PrimaryKey.IsNull() {
+		diags := table.PrimaryKey.ElementsAs(ctx, &primaryKey, false)
+		if diags.HasError() {
+			return nil, nil, diags
+		}
+	}
+
+	return orderBy, primaryKey, nil
+}
+
+// columnRenames returns the state columns to rename, mapped to their planned name: the planned columns with a
+// rename_from naming a state column, when the state has no column with their name and the plan none with the previous
+// one.
+func columnRenames(state []Column, plan []Column) map[string]string {
+	stateColumns := make(map[string]bool)
+	for _, col := range state {
+		stateColumns[col.Name.ValueString()] = true
+	}
+	planColumns := make(map[string]bool)
+	for _, col := range plan {
+		planColumns[col.Name.ValueString()] = true
+	}
+
+	renames := make(map[string]string)
+	for _, col := range plan {
+		from := col.RenameFrom.ValueString()
+		if col.RenameFrom.IsNull() || col.RenameFrom.IsUnknown() || !stateColumns[from] || planColumns[from] || stateColumns[col.Name.ValueString()] {
+			continue
+		}
+		renames[from] = col.Name.ValueString()
+	}
+
+	return renames
+}
+
+// renameColumns returns a copy of the columns with the renames applied.
+func renameColumns(columns []Column, renames map[string]string) []Column {
+	ret := slices.Clone(columns)
+	for i, col := range ret {
+		if to, ok := renames[col.Name.ValueString()]; ok {
+			ret[i].Name = types.StringValue(to)
+		}
+	}
+
+	return ret
+}
+
+// immutableSettings are the MergeTree settings ClickHouse only accepts when the table is created.
+var immutableSettings = []string{"index_granularity", "index_granularity_bytes", "enable_mixed_granularity_parts"}
+
+// settingsNeedNewTable reports whether going from the state settings to the planned ones needs a new table: only
+// MergeTree engines accept MODIFY SETTING and RESET SETTING, and not for the settings fixed at creation.
+func settingsNeedNewTable(engine string, state map[string]string, plan map[string]string) bool {
+	modified, reset := settingChanges(state, plan)
+	if len(modified) == 0 && len(reset) == 0 {
+		return false
+	}
+
+	if !strings.HasSuffix(schemadiff.EngineName(engine), "MergeTree") {
+		return true
+	}
+
+	for name := range modified {
+		if slices.Contains(immutableSettings, name) {
+			return true
+		}
+	}
+	for _, name := range reset {
+		if slices.Contains(immutableSettings, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// settingChanges returns the settings to set with MODIFY SETTING and the ones to reset with RESET SETTING to go from
+// the state settings to the planned ones.
+func settingChanges(state map[string]string, plan map[string]string) (map[string]string, []string) {
+	modified := make(map[string]string)
+	for name, value := range plan {
+		if current, ok := state[name]; !ok || !schemadiff.SameSettingValue(current, value) {
+			modified[name] = value
+		}
+	}
+
+	reset := make([]string, 0)
+	for name := range state {
+		if _, ok := plan[name]; !ok {
+			reset = append(reset, name)
+		}
+	}
+	slices.Sort(reset)
+
+	return modified, reset
+}
+
+// settingsMap returns the known settings of a settings attribute.
+func settingsMap(settings types.Map) map[string]string {
+	ret := make(map[string]string)
+	for name, value := range settings.Elements() {
+		if s, ok := value.(types.String); ok && !s.IsUnknown() && !s.IsNull() {
+			ret[name] = s.ValueString()
+		}
+	}
+
+	return ret
+}
+
+// incompatibleColumnChanges returns the column changes from state to plan that ALTER TABLE can't apply: removals of
+// columns used in the ORDER BY clause, and type changes other than the compatible ones (see
+// schemadiff.CompatibleTypeChange) of columns outside of the sorting and primary keys.
+func incompatibleColumnChanges(ctx context.Context, state Table, plan Table) ([]string, diag.Diagnostics) {
+	orderBy, primaryKey, diags := keyColumns(ctx, state)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	planColumns := make(map[string]Column)
+	for _, col := range plan.Columns {
+		planColumns[col.Name.ValueString()] = col
+	}
+
+	changes := make([]string, 0)
+	renames := columnRenames(state.Columns, plan.Columns)
+	for _, from := range slices.Sorted(maps.Keys(renames)) {
+		if slices.Contains(orderBy, from) || slices.Contains(primaryKey, from) {
+			changes = append(changes, fmt.Sprintf("column '%s' is part of the table key and can't be renamed", from))
+			delete(renames, from)
+		}
+	}
+
+	for _, stateCol := range renameColumns(state.Columns, renames) {
+		colName := stateCol.Name.ValueString()
+		planCol, exists := planColumns[colName]
+		switch {
+		case !exists && slices.Contains(orderBy, colName):
+			changes = append(changes, fmt.Sprintf("column '%s' is part of the ORDER BY clause and can't be removed", colName))
+		case exists && columnDefaultKind(stateCol) != columnDefaultKind(planCol):
+			changes = append(changes, fmt.Sprintf("column '%s' default kind changed from '%s' to '%s'", colName, columnDefaultKind(stateCol), columnDefaultKind(planCol)))
+		case exists && columnTypeChanged(stateCol, planCol):
+			if schemadiff.CompatibleTypeChange(stateCol.Type.ValueString(), planCol.Type.ValueString()) && !slices.Contains(orderBy, colName) && !slices.Contains(primaryKey, colName) {
+				// Applied in place with MODIFY COLUMN.
+				continue
+			}
+			changes = append(changes, fmt.Sprintf("column '%s' type changed from '%s' to '%s'", colName, stateCol.Type.ValueString(), planCol.Type.ValueString()))
+		}
+	}
+
+	return changes, nil
+}
+
+// columnTypeChanged reports whether the type of the column differs between from and to, ignoring formatting.
+func columnTypeChanged(from Column, to Column) bool {
+	return !from.Type.Equal(to.Type) && !schemadiff.SameType(from.Type.ValueString(), to.Type.ValueString())
+}
+
+// columnDefaultKind returns the default kind of a column, DEFAULT when it isn't known.
+func columnDefaultKind(col Column) string {
+	if col.DefaultKind.IsNull() || col.DefaultKind.IsUnknown() {
+		return querybuilder.ColumnDefaultKindDefault
+	}
+
+	return col.DefaultKind.ValueString()
+}
+
+// columnCodecChanged tells if the codec of a column changed, ClickHouse filling in default codec parameters aside.
+func columnCodecChanged(from Column, to Column) bool {
+	if from.Codec.IsNull() || to.Codec.IsNull() {
+		return from.Codec.IsNull() != to.Codec.IsNull()
+	}
+
+	return !schemadiff.SameCodec(to.Codec.ValueString(), from.Codec.ValueString())
+}
+
+// sharedColumnNames returns the names of the columns of plan that already exist in state, in the order of plan. Only
+// the columns stored as inserted are returned: MATERIALIZED and ALIAS columns can't be inserted into, and EPHEMERAL
+// ones can't be selected.
+func sharedColumnNames(state Table, plan Table) []string {
+	stateColumns := make(map[string]bool)
+	for _, col := range state.Columns {
+		if columnDefaultKind(col) == querybuilder.ColumnDefaultKindDefault {
+			stateColumns[col.Name.ValueString()] = true
+		}
+	}
+
+	ret := make([]string, 0)
+	for _, col := range plan.Columns {
+		if stateColumns[col.Name.ValueString()] && columnDefaultKind(col) == querybuilder.ColumnDefaultKindDefault {
+			ret = append(ret, col.Name.ValueString())
+		}
+	}
+
+	return ret
+}
+
+// shadowAndExchange applies the plan by creating the planned table under a temporary name, optionally copying the
+// data of the existing table into it, and swapping the two tables with EXCHANGE TABLES. The previous table, left under
+// the temporary name, is dropped when allow_drops is true and kept otherwise.
+func (r *Resource) shadowAndExchange(ctx context.Context, state Table, plan Table, resp *resource.UpdateResponse) {
+	clusterName := plan.ClusterName.ValueStringPointer()
+	databaseName := state.DatabaseName.ValueString()
+	tableName := state.Name.ValueString()
+	shadowName := fmt.Sprintf("%s_shadow_%d", tableName, time.Now().Unix())
+
+	shadowTable, diags := tableFromPlan(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	shadowTable.Name = shadowName
+	shadowTable.UUID = ""
+
+	createStatement, err := dbops.CreateTableStatement(*shadowTable, clusterName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating shadow table",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	shadow, err := r.client.CreateTable(ctx, *shadowTable, clusterName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating shadow table",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	// dropShadow removes the shadow table when a later step fails, so that the next apply starts over.
+	dropShadow := func(summary string, err error) {
+		detail := fmt.Sprintf("%+v\n", err)
+		if dropErr := r.client.DeleteTable(ctx, shadow.UUID, clusterName); dropErr != nil {
+			detail += fmt.Sprintf("\nThe shadow table `%s`.`%s` could not be dropped and must be dropped manually: %+v\n", databaseName, shadowName, dropErr)
+		}
+		resp.Diagnostics.AddError(summary, detail)
+	}
+
+	if plan.CopyDataOnExchange.ValueBool() {
+		if columns := sharedColumnNames(state, plan); len(columns) > 0 {
+			err = r.client.CopyTableData(ctx, databaseName, tableName, shadowName, columns)
+			if err != nil {
+				dropShadow("Error copying data to shadow table", err)
+				return
+			}
+		}
+	}
+
+	err = r.client.ExchangeTables(ctx, databaseName, tableName, shadowName, clusterName)
+	if err != nil {
+		dropShadow("Error exchanging tables", err)
+		return
+	}
+
+	// The previous table now has the shadow name.
+	if plan.AllowDrops.ValueBool() {
+		err = r.client.DeleteTable(ctx, state.UUID.ValueString(), clusterName)
+		if err != nil {
+			resp.Diagnostics.AddWarning(
+				"Error dropping previous table",
+				fmt.Sprintf("The new table is in place, but the previous one could not be dropped and is left as `%s`.`%s`: %+v\n", databaseName, shadowName, err),
+			)
+		}
+	} else {
+		resp.Diagnostics.AddWarning(
+			"Previous table kept",
+			fmt.Sprintf("The previous table is kept as `%s`.`%s` because 'allow_drops' is set to false. Drop it once it is not needed anymore.", databaseName, shadowName),
+		)
+	}
+
+	updatedState, err := r.syncTableState(ctx, shadow.UUID, clusterName, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error syncing table state",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if updatedState == nil {
+		resp.Diagnostics.AddError(
+			"Error syncing table state",
+			"failed retrieving table after replacement",
+		)
+		return
+	}
+	updatedState.CreateStatement = types.StringValue(createStatement)
+
+	diags = resp.State.Set(ctx, updatedState)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,277 @@
+package table
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func Test_incompatibleChanges(t *testing.T) {
+	base := func() Table {
+		return Table{
+			Columns: []Column{
+				{Name: types.StringValue("ts"), Type: types.StringValue("DateTime")},
+				{Name: types.StringValue("id"), Type: types.StringValue("UInt32")},
+				{Name: types.StringValue("payload"), Type: types.StringValue("JSON")},
+			},
+			Engine:      types.StringValue("MergeTree"),
+			OrderBy:     types.ListValueMust(types.StringType, []attr.Value{types.StringValue("ts")}),
+			PartitionBy: types.StringNull(),
+			PrimaryKey:  types.ListValueMust(types.StringType, []attr.Value{}),
+			SampleBy:    types.StringNull(),
+			TTL:         types.StringNull(),
+			Settings:    types.MapValueMust(types.StringType, map[string]attr.Value{}),
+			Comment:     types.StringValue(""),
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Table)
+		want   []string
+	}{
+		{
+			name:   "No change",
+			modify: func(*Table) {},
+			want:   []string{},
+		},
+		{
+			name: "Columns added and dropped in place",
+			modify: func(table *Table) {
+				table.Columns = append(table.Columns[:1], Column{Name: types.StringValue("user"), Type: types.StringValue("String")})
+			},
+			want: []string{},
+		},
+		{
+			name: "Reformatted type",
+			modify: func(table *Table) {
+				table.Columns[2].Type = types.StringValue("JSON(max_dynamic_paths = 1024)")
+			},
+			want: []string{},
+		},
+		{
+			name: "Compatible type change",
+			modify: func(table *Table) {
+				table.Columns[1].Type = types.StringValue("Nullable(UInt64)")
+			},
+			want: []string{},
+		},
+		{
+			name: "Incompatible type change",
+			modify: func(table *Table) {
+				table.Columns[1].Type = types.StringValue("Int32")
+			},
+			want: []string{"column 'id' type changed from 'UInt32' to 'Int32'"},
+		},
+		{
+			name: "Compatible type change of an ORDER BY column",
+			modify: func(table *Table) {
+				table.Columns[0].Type = types.StringValue("Nullable(DateTime)")
+			},
+			want: []string{"column 'ts' type changed from 'DateTime' to 'Nullable(DateTime)'"},
+		},
+		{
+			name: "ORDER BY column removed",
+			modify: func(table *Table) {
+				table.Columns = table.Columns[1:]
+			},
+			want: []string{"column 'ts' is part of the ORDER BY clause and can't be removed"},
+		},
+		{
+			name: "Default kind changed",
+			modify: func(table *Table) {
+				table.Columns[1].Default = types.StringValue("0")
+				table.Columns[1].DefaultKind = types.StringValue("MATERIALIZED")
+			},
+			want: []string{"column 'id' default kind changed from 'DEFAULT' to 'MATERIALIZED'"},
+		},
+		{
+			name: "Codec changed in place",
+			modify: func(table *Table) {
+				table.Columns[2].Codec = types.StringValue("ZSTD(3)")
+			},
+			want: []string{},
+		},
+		{
+			name: "Column renamed with a compatible type change",
+			modify: func(table *Table) {
+				table.Columns[1] = Column{Name: types.StringValue("user_id"), Type: types.StringValue("UInt64"), RenameFrom: types.StringValue("id")}
+			},
+			want: []string{},
+		},
+		{
+			name: "ORDER BY column renamed",
+			modify: func(table *Table) {
+				table.Columns[0] = Column{Name: types.StringValue("timestamp"), Type: types.StringValue("DateTime"), RenameFrom: types.StringValue("ts")}
+			},
+			want: []string{"column 'ts' is part of the table key and can't be renamed", "column 'ts' is part of the ORDER BY clause and can't be removed"},
+		},
+		{
+			name: "Table attributes",
+			modify: func(table *Table) {
+				table.Engine = types.StringValue("ReplacingMergeTree")
+				table.TTL = types.StringValue("ts + INTERVAL 30 DAY")
+				table.Comment = types.StringUnknown()
+			},
+			want: []string{"`engine` changed"},
+		},
+		{
+			name: "Settings changed in place",
+			modify: func(table *Table) {
+				table.Settings = types.MapValueMust(types.StringType, map[string]attr.Value{"merge_with_ttl_timeout": types.StringValue("3600")})
+			},
+			want: []string{},
+		},
+		{
+			name: "Setting fixed at creation",
+			modify: func(table *Table) {
+				table.Settings = types.MapValueMust(types.StringType, map[string]attr.Value{"index_granularity": types.StringValue("1024")})
+			},
+			want: []string{"`settings` changed"},
+		},
+		{
+			name: "Settings of an engine without MODIFY SETTING",
+			modify: func(table *Table) {
+				table.Engine = types.StringValue("Log")
+				table.Settings = types.MapValueMust(types.StringType, map[string]attr.Value{"storage_policy": types.StringValue("default")})
+			},
+			want: []string{"`engine` changed", "`settings` changed"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := base()
+			tt.modify(&plan)
+
+			got, diags := incompatibleColumnChanges(context.Background(), base(), plan)
+			if diags.HasError() {
+				t.Fatalf("incompatibleColumnChanges() diags = %v", diags)
+			}
+			got = append(got, incompatibleAttributeChanges(base(), plan)...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("incompatible changes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_settingChanges(t *testing.T) {
+	state := map[string]string{"index_granularity": "8192", "merge_with_ttl_timeout": "'86400'", "ttl_only_drop_parts": "1"}
+	plan := map[string]string{"index_granularity": "8192", "merge_with_ttl_timeout": "86400", "min_bytes_for_wide_part": "0"}
+
+	modified, reset := settingChanges(state, plan)
+	if want := map[string]string{"min_bytes_for_wide_part": "0"}; !reflect.DeepEqual(modified, want) {
+		t.Errorf("settingChanges() modified = %v, want %v", modified, want)
+	}
+	if want := []string{"ttl_only_drop_parts"}; !reflect.DeepEqual(reset, want) {
+		t.Errorf("settingChanges() reset = %v, want %v", reset, want)
+	}
+
+	if settingsNeedNewTable("ReplicatedMergeTree('/t', '{replica}')", state, plan) {
+		t.Errorf("settingsNeedNewTable() = true, want false")
+	}
+	delete(plan, "index_granularity")
+	if !settingsNeedNewTable("MergeTree", state, plan) {
+		t.Errorf("settingsNeedNewTable() = false, want true when resetting index_granularity")
+	}
+}
+
+func Test_columnRenames(t *testing.T) {
+	column := func(name string, renameFrom string) Column {
+		c := Column{Name: types.StringValue(name), Type: types.StringValue("String"), RenameFrom: types.StringNull()}
+		if renameFrom != "" {
+			c.RenameFrom = types.StringValue(renameFrom)
+		}
+		return c
+	}
+	state := []Column{column("a", ""), column("b", ""), column("c", "")}
+
+	tests := []struct {
+		name string
+		plan []Column
+		want map[string]string
+	}{
+		{
+			name: "Renamed column",
+			plan: []Column{column("a", ""), column("b2", "b"), column("c", "")},
+			want: map[string]string{"b": "b2"},
+		},
+		{
+			name: "Already renamed",
+			plan: []Column{column("a", ""), column("b", "x"), column("c", "")},
+			want: map[string]string{},
+		},
+		{
+			name: "Previous name still planned",
+			plan: []Column{column("a", ""), column("b", ""), column("c", ""), column("b2", "b")},
+			want: map[string]string{},
+		},
+		{
+			name: "Unknown previous name",
+			plan: []Column{column("a", ""), column("d", "x"), column("c", "")},
+			want: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := columnRenames(state, tt.plan); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("columnRenames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_columnCodecChanged(t *testing.T) {
+	column := func(codec types.String) Column {
+		return Column{Name: types.StringValue("ts"), Type: types.StringValue("DateTime"), Codec: codec}
+	}
+
+	tests := []struct {
+		name string
+		from types.String
+		to   types.String
+		want bool
+	}{
+		{name: "No codec", from: types.StringNull(), to: types.StringNull(), want: false},
+		{name: "Codec added", from: types.StringNull(), to: types.StringValue("ZSTD(3)"), want: true},
+		{name: "Codec removed", from: types.StringValue("ZSTD(3)"), to: types.StringNull(), want: true},
+		{name: "Default parameters", from: types.StringValue("Delta(4), ZSTD(1)"), to: types.StringValue("Delta, ZSTD"), want: false},
+		{name: "Level changed", from: types.StringValue("ZSTD(1)"), to: types.StringValue("ZSTD(3)"), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := columnCodecChanged(column(tt.from), column(tt.to)); got != tt.want {
+				t.Errorf("columnCodecChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_sharedColumnNames(t *testing.T) {
+	column := func(name string, defaultKind string) Column {
+		return Column{Name: types.StringValue(name), Type: types.StringValue("String"), DefaultKind: types.StringValue(defaultKind)}
+	}
+
+	state := Table{Columns: []Column{
+		column("id", "DEFAULT"),
+		column("message", "DEFAULT"),
+		column("message_length", "MATERIALIZED"),
+		column("raw", "EPHEMERAL"),
+		column("dropped", "DEFAULT"),
+	}}
+	plan := Table{Columns: []Column{
+		column("message", "DEFAULT"),
+		column("id", "DEFAULT"),
+		column("message_length", "MATERIALIZED"),
+		column("raw", "DEFAULT"),
+		{Name: types.StringValue("added"), Type: types.StringValue("String"), DefaultKind: types.StringNull()},
+	}}
+
+	want := []string{"message", "id"}
+	if got := sharedColumnNames(state, plan); !reflect.DeepEqual(got, want) {
+		t.Errorf("sharedColumnNames() = %v, want %v", got, want)
+	}
+}
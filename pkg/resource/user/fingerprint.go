@@ -0,0 +1,51 @@
+package user
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// fingerprintScheme prefixes the fingerprints, to tell them apart from the ones of other schemes in the future.
+const fingerprintScheme = "hmac-sha256"
+
+// passwordFingerprint returns a salted fingerprint of the password hash, which can be stored in the state instead of
+// the hash to detect changes to it. ClickHouse does not expose password hashes, so it can't verify them itself.
+func passwordFingerprint(passwordSha256Hash string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", errors.WithMessage(err, "error generating salt")
+	}
+
+	return fingerprintScheme + ":" + hex.EncodeToString(salt) + ":" + hex.EncodeToString(fingerprintMAC(salt, passwordSha256Hash)), nil
+}
+
+// matchesFingerprint tells if the password hash is the one the fingerprint was made from.
+func matchesFingerprint(fingerprint string, passwordSha256Hash string) bool {
+	parts := strings.Split(fingerprint, ":")
+	if len(parts) != 3 || parts[0] != fingerprintScheme {
+		return false
+	}
+
+	salt, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+	mac, err := hex.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal(mac, fingerprintMAC(salt, passwordSha256Hash))
+}
+
+func fingerprintMAC(salt []byte, passwordSha256Hash string) []byte {
+	// Hashes are hexadecimal and ClickHouse accepts them in any case.
+	h := hmac.New(sha256.New, salt)
+	h.Write([]byte(strings.ToLower(passwordSha256Hash)))
+	return h.Sum(nil)
+}
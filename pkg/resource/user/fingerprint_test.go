@@ -0,0 +1,65 @@
+package user
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_passwordFingerprint(t *testing.T) {
+	hash := "f0e4c2f76c58916ec258f246851bea091d14d4247a2fc3e18694461b1816e13b"
+
+	fingerprint, err := passwordFingerprint(hash)
+	if err != nil {
+		t.Fatalf("passwordFingerprint() error = %v", err)
+	}
+	if strings.Contains(fingerprint, hash) {
+		t.Errorf("passwordFingerprint() = %v, contains the hash", fingerprint)
+	}
+
+	other, err := passwordFingerprint(hash)
+	if err != nil {
+		t.Fatalf("passwordFingerprint() error = %v", err)
+	}
+	if other == fingerprint {
+		t.Errorf("passwordFingerprint() returned %v twice, want a different salt every time", fingerprint)
+	}
+
+	tests := []struct {
+		name        string
+		fingerprint string
+		hash        string
+		want        bool
+	}{
+		{
+			name:        "Same hash",
+			fingerprint: fingerprint,
+			hash:        hash,
+			want:        true,
+		},
+		{
+			name:        "Same hash in upper case",
+			fingerprint: fingerprint,
+			hash:        strings.ToUpper(hash),
+			want:        true,
+		},
+		{
+			name:        "Other hash",
+			fingerprint: fingerprint,
+			hash:        "5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8",
+			want:        false,
+		},
+		{
+			name:        "Malformed fingerprint",
+			fingerprint: "sha256:" + hash,
+			hash:        hash,
+			want:        false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesFingerprint(tt.fingerprint, tt.hash); got != tt.want {
+				t.Errorf("matchesFingerprint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
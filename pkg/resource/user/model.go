@@ -5,9 +5,13 @@ import (
 )
 
 type User struct {
+	AccessStorageMode         types.String `tfsdk:"access_storage_mode"`
 	ClusterName               types.String `tfsdk:"cluster_name"`
 	ID                        types.String `tfsdk:"id"`
 	Name                      types.String `tfsdk:"name"`
 	PasswordSha256Hash        types.String `tfsdk:"password_sha256_hash_wo"`
 	PasswordSha256HashVersion types.Int32  `tfsdk:"password_sha256_hash_wo_version"`
+	TrackPasswordFingerprint  types.Bool   `tfsdk:"track_password_fingerprint"`
+	PasswordFingerprint       types.String `tfsdk:"password_fingerprint"`
+	SettingsProfiles          types.List   `tfsdk:"settings_profiles"`
 }
@@ -8,16 +8,20 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clustername"
 	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
 )
 
@@ -45,6 +49,13 @@ func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, res
 func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
+			"access_storage_mode": schema.StringAttribute{
+				Optional:    true,
+				Description: "How the user is managed on the cluster set in `cluster_name`: `on_cluster` runs its statements ON CLUSTER, `replicated` runs them on the replica the provider is connected to and relies on replicated access storage to propagate them. Defaults to the provider's `access_storage_mode`, whose `auto` default picks `replicated` when the server uses replicated storage for users. Changing it only affects the statements to come.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(dbops.AccessStorageModes...),
+				},
+			},
 			"cluster_name": schema.StringAttribute{
 				Optional:    true,
 				Description: "Name of the cluster to create the resource into. If omitted, resource will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nWhen using a self hosted ClickHouse instance, this field should only be set when there is more than one replica and you are not using 'replicated' storage for user_directory.\n",
@@ -66,14 +77,26 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 			"password_sha256_hash_wo": schema.StringAttribute{
 				Required:    true,
 				Sensitive:   true,
-				Description: "SHA256 hash of the password to be set for the user",
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
-				},
+				WriteOnly:   true,
+				Description: "SHA256 hash of the password to be set for the user. Write-only: it is never stored in the plan nor in the state, so changing it has no effect unless `password_sha256_hash_wo_version` is bumped or `password_fingerprint` detects the change.",
 				Validators: []validator.String{
 					stringvalidator.RegexMatches(regexp.MustCompile(`^[a-fA-F0-9]{64}$`), "password_sha256_hash must be a valid SHA256 hash"),
 				},
 			},
+			"track_password_fingerprint": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "When true, a salted fingerprint of `password_sha256_hash_wo` is stored in `password_fingerprint` and the user is recreated when the hash no longer matches it, without bumping `password_sha256_hash_wo_version`. The hash itself is never stored. Turning it on for an existing user assumes its password is the configured one.",
+			},
+			"password_fingerprint": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Salted HMAC-SHA256 fingerprint of `password_sha256_hash_wo`, set when `track_password_fingerprint` is true. Its salt is stored with it, so it is sensitive like the hash itself.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 			"password_sha256_hash_wo_version": schema.Int32Attribute{
 				Required:    true,
 				Description: "Version of the password_sha256_hash_wo field. Bump this value to require a force update of the password on the user.",
@@ -81,19 +104,50 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 					int32planmodifier.RequiresReplace(),
 				},
 			},
+			"settings_profiles": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Names of the settings profiles the user inherits from, in order: the settings of a profile override the ones of the profiles before it. Replaces every other setting and profile of the user, so it must not be combined with `clickhousedbops_settings_profile_assignment` resources for the same user. Set to an empty list to remove them all, leave null to not manage them.",
+				Validators: []validator.List{
+					listvalidator.UniqueValues(),
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
 		},
 		MarkdownDescription: userResourceDescription,
 	}
 }
 
 func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_user", "plan")
+
 	if req.Plan.Raw.IsNull() {
 		// If the entire plan is null, the resource is planned for destruction.
 		return
 	}
 
+	clustername.ValidatePlan(ctx, r.client, req.Plan, &resp.Diagnostics)
+
+	if !req.State.Raw.IsNull() {
+		r.planPasswordFingerprint(ctx, req, resp)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	if r.client != nil {
-		isReplicatedStorage, err := r.client.IsReplicatedStorage(ctx)
+		var config User
+		diags := req.Config.Get(ctx, &config)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if config.ClusterName.IsNull() || config.ClusterName.IsUnknown() || config.AccessStorageMode.IsUnknown() {
+			return
+		}
+
+		clusterName, err := r.client.AccessCluster(ctx, config.ClusterName.ValueStringPointer(), config.AccessStorageMode.ValueString())
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error Checking if service is using replicated storage",
@@ -102,21 +156,11 @@ func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanReques
 			return
 		}
 
-		if isReplicatedStorage {
-			var config User
-			diags := req.Config.Get(ctx, &config)
-			resp.Diagnostics.Append(diags...)
-			if resp.Diagnostics.HasError() {
-				return
-			}
-
-			// User cannot specify 'cluster_name' or apply will fail.
-			if !config.ClusterName.IsNull() {
-				resp.Diagnostics.AddWarning(
-					"Invalid configuration",
-					"Your ClickHouse cluster seems to be using Replicated storage for users, please remove the 'cluster_name' attribute from your User resource definition if you encounter any errors.",
-				)
-			}
+		if clusterName == nil && config.AccessStorageMode.ValueString() != dbops.AccessStorageModeReplicated {
+			resp.Diagnostics.AddWarning(
+				"Cluster Name Not Used",
+				"Your ClickHouse cluster seems to be using Replicated storage for users, the user is managed on the replica the provider is connected to and ClickHouse replicates it rather than running ON CLUSTER. Set 'access_storage_mode' to \"on_cluster\" if some replicas of the cluster use a different access storage.",
+			)
 		}
 	}
 }
@@ -130,6 +174,8 @@ func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _
 }
 
 func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_user", "create")
+
 	var plan User
 	var config User
 	diags := req.Plan.Get(ctx, &plan)
@@ -150,7 +196,16 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 		PasswordSha256Hash: config.PasswordSha256Hash.ValueString(),
 	}
 
-	createdUser, err := r.client.CreateUser(ctx, user, plan.ClusterName.ValueStringPointer())
+	clusterName, err := r.client.AccessCluster(ctx, plan.ClusterName.ValueStringPointer(), plan.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Creating ClickHouse User",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	createdUser, err := r.client.CreateUser(ctx, user, clusterName)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Creating ClickHouse User",
@@ -160,10 +215,31 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 	}
 
 	state := User{
+		AccessStorageMode:         plan.AccessStorageMode,
 		ClusterName:               plan.ClusterName,
 		ID:                        types.StringValue(createdUser.ID),
 		Name:                      types.StringValue(createdUser.Name),
 		PasswordSha256HashVersion: plan.PasswordSha256HashVersion,
+		TrackPasswordFingerprint:  plan.TrackPasswordFingerprint,
+		PasswordFingerprint:       types.StringNull(),
+		SettingsProfiles:          plan.SettingsProfiles,
+	}
+
+	if plan.TrackPasswordFingerprint.ValueBool() {
+		fingerprint, err := passwordFingerprint(config.PasswordSha256Hash.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Fingerprinting ClickHouse User Password",
+				fmt.Sprintf("%+v\n", err),
+			)
+		} else {
+			state.PasswordFingerprint = types.StringValue(fingerprint)
+		}
+	}
+
+	if !plan.SettingsProfiles.IsNull() {
+		// The user exists at this point: a failure keeps it in the state, to be tainted.
+		r.setSettingsProfiles(ctx, plan.SettingsProfiles, createdUser.Name, clusterName, &resp.Diagnostics)
 	}
 
 	diags = resp.State.Set(ctx, state)
@@ -174,6 +250,8 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 }
 
 func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_user", "read")
+
 	var state User
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -181,7 +259,23 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 		return
 	}
 
-	user, err := r.client.GetUser(ctx, state.ID.ValueString(), state.ClusterName.ValueStringPointer())
+	clusterName, err := r.client.AccessCluster(ctx, state.ClusterName.ValueStringPointer(), state.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse User",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	user, err := r.client.GetUser(ctx, state.ID.ValueString(), clusterName)
+	if dbops.IsRestrictedRead(err) {
+		resp.Diagnostics.AddWarning(
+			"Unable to Refresh ClickHouse User",
+			"Not allowed to read the user, keeping the prior state: "+err.Error(),
+		)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading ClickHouse User",
@@ -193,6 +287,24 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 	if user != nil {
 		state.Name = types.StringValue(user.Name)
 
+		if !state.SettingsProfiles.IsNull() {
+			profileNames, err := r.client.GetSettingsProfiles(ctx, &user.Name, nil, clusterName)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error Reading ClickHouse User Settings Profiles",
+					fmt.Sprintf("%+v\n", err),
+				)
+				return
+			}
+
+			settingsProfiles, diags := types.ListValueFrom(ctx, types.StringType, profileNames)
+			resp.Diagnostics.Append(diags...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			state.SettingsProfiles = settingsProfiles
+		}
+
 		diags = resp.State.Set(ctx, &state)
 		resp.Diagnostics.Append(diags...)
 	} else {
@@ -201,10 +313,68 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 }
 
 func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	panic("Update of user resource is not supported")
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_user", "update")
+
+	// Every other attribute requires a replacement: only access_storage_mode, which affects future statements only,
+	// settings_profiles and the password fingerprint tracking can change here.
+	var plan, state User
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.AccessStorageMode = plan.AccessStorageMode
+
+	state.TrackPasswordFingerprint = plan.TrackPasswordFingerprint
+	if !plan.TrackPasswordFingerprint.ValueBool() {
+		state.PasswordFingerprint = types.StringNull()
+	} else if state.PasswordFingerprint.IsNull() {
+		// Starting to track the password: its hash is only available in the config.
+		var config User
+		resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		fingerprint, err := passwordFingerprint(config.PasswordSha256Hash.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Fingerprinting ClickHouse User Password",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+		state.PasswordFingerprint = types.StringValue(fingerprint)
+	}
+
+	// A null list stops managing the profiles and leaves them as they are.
+	if !plan.SettingsProfiles.IsNull() && !plan.SettingsProfiles.Equal(state.SettingsProfiles) {
+		clusterName, err := r.client.AccessCluster(ctx, plan.ClusterName.ValueStringPointer(), plan.AccessStorageMode.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Updating ClickHouse User",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+
+		r.setSettingsProfiles(ctx, plan.SettingsProfiles, state.Name.ValueString(), clusterName, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	state.SettingsProfiles = plan.SettingsProfiles
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
 }
 
 func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_user", "delete")
+
 	var state User
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -212,7 +382,34 @@ func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp
 		return
 	}
 
-	err := r.client.DeleteUser(ctx, state.ID.ValueString(), state.ClusterName.ValueStringPointer())
+	clusterName, err := r.client.AccessCluster(ctx, state.ClusterName.ValueStringPointer(), state.AccessStorageMode.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Deleting ClickHouse User",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	user, err := r.client.GetUser(ctx, state.ID.ValueString(), clusterName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse User",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if user != nil && dbops.IsReadOnlyStorage(user.Storage) {
+		// The user is defined in the server configuration, DROP would always fail: just forget about it.
+		resp.Diagnostics.AddWarning(
+			"ClickHouse User Not Dropped",
+			fmt.Sprintf("User %q is defined in the %s storage and can't be dropped with SQL statements, it was only removed from the terraform state.", user.Name, user.Storage),
+		)
+		return
+	}
+
+	err = r.client.DeleteUser(ctx, state.ID.ValueString(), clusterName)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting ClickHouse User",
@@ -223,6 +420,8 @@ func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp
 }
 
 func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_user", "import")
+
 	// req.ID can either be in the form <cluster name>:<user ref> or just <user ref>
 	// user ref can either be the name or the UUID of the user.
 
@@ -247,6 +446,13 @@ func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequ
 			return
 		}
 
+		if dbops.IsReadOnlyStorage(user.Storage) {
+			resp.Diagnostics.AddWarning(
+				"ClickHouse User Defined In Configuration",
+				fmt.Sprintf("User %q is defined in the %s storage: it can be referenced by other resources but can't be changed or dropped with SQL statements.", user.Name, user.Storage),
+			)
+		}
+
 		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), user.ID)...)
 	} else {
 		// User passed a UUID
@@ -257,3 +463,48 @@ func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequ
 		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cluster_name"), clusterName)...)
 	}
 }
+
+// setSettingsProfiles makes the user inherit from the given profiles, in order.
+func (r *Resource) setSettingsProfiles(ctx context.Context, settingsProfiles types.List, userName string, clusterName *string, diagnostics *diag.Diagnostics) {
+	profileNames := make([]string, 0)
+	diagnostics.Append(settingsProfiles.ElementsAs(ctx, &profileNames, false)...)
+	if diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.SetSettingsProfiles(ctx, profileNames, &userName, nil, clusterName)
+	if err != nil {
+		diagnostics.AddError(
+			"Error Setting ClickHouse User Settings Profiles",
+			fmt.Sprintf("%+v\n", err),
+		)
+	}
+}
+
+// planPasswordFingerprint replaces the user when track_password_fingerprint is on and the configured password hash no
+// longer matches the fingerprint in the state, and plans the fingerprint to compute when the tracking is turned on.
+func (r *Resource) planPasswordFingerprint(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var config, state User
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.TrackPasswordFingerprint.ValueBool() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("password_fingerprint"), types.StringNull())...)
+		return
+	}
+
+	if state.PasswordFingerprint.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("password_fingerprint"), types.StringUnknown())...)
+		return
+	}
+
+	if config.PasswordSha256Hash.IsUnknown() || matchesFingerprint(state.PasswordFingerprint.ValueString(), config.PasswordSha256Hash.ValueString()) {
+		return
+	}
+
+	resp.RequiresReplace = append(resp.RequiresReplace, path.Root("password_sha256_hash_wo"))
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("password_fingerprint"), types.StringUnknown())...)
+}
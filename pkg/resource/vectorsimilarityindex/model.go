@@ -0,0 +1,21 @@
+package vectorsimilarityindex
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type VectorSimilarityIndex struct {
+	ClusterName                          types.String `tfsdk:"cluster_name"`
+	DatabaseName                         types.String `tfsdk:"database_name"`
+	TableName                            types.String `tfsdk:"table_name"`
+	Name                                 types.String `tfsdk:"name"`
+	Column                               types.String `tfsdk:"column"`
+	DistanceFunction                     types.String `tfsdk:"distance_function"`
+	Dimensions                           types.Int64  `tfsdk:"dimensions"`
+	Quantization                         types.String `tfsdk:"quantization"`
+	HNSWMaxConnectionsPerLayer           types.Int64  `tfsdk:"hnsw_max_connections_per_layer"`
+	HNSWCandidateListSizeForConstruction types.Int64  `tfsdk:"hnsw_candidate_list_size_for_construction"`
+	Granularity                          types.Int64  `tfsdk:"granularity"`
+	Materialize                          types.Bool   `tfsdk:"materialize"`
+	Type                                 types.String `tfsdk:"type"`
+}
@@ -0,0 +1,368 @@
+package vectorsimilarityindex
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clustername"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/protecteddatabase"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+//go:embed vectorsimilarityindex.md
+var vectorSimilarityIndexResourceDescription string
+
+var (
+	_ resource.Resource               = &Resource{}
+	_ resource.ResourceWithConfigure  = &Resource{}
+	_ resource.ResourceWithModifyPlan = &Resource{}
+)
+
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+type Resource struct {
+	client dbops.Client
+}
+
+func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vector_similarity_index"
+}
+
+func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to add the index on. If omitted, the index is only added on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"database_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the database containing the table",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"table_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the MergeTree table to add the index to",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the index",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"column": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the Array(Float32) column holding the vectors",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"distance_function": schema.StringAttribute{
+				Required:    true,
+				Description: "Distance function the index is built for, either `L2Distance` or `cosineDistance`",
+				Validators: []validator.String{
+					stringvalidator.OneOf(querybuilder.VectorSimilarityDistanceFunctions...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"dimensions": schema.Int64Attribute{
+				Required:    true,
+				Description: "Number of elements of the indexed vectors",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"quantization": schema.StringAttribute{
+				Optional:    true,
+				Description: "Precision of the vectors stored in the index, one of `f64`, `f32`, `f16`, `bf16`, `i8` or `b1`. Defaults to `bf16`",
+				Validators: []validator.String{
+					stringvalidator.OneOf(querybuilder.VectorSimilarityQuantizations...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"hnsw_max_connections_per_layer": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of neighbours of each node of the HNSW graph. Defaults to 32",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"hnsw_candidate_list_size_for_construction": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Size of the list of candidates considered when building the HNSW graph. Defaults to 128",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"granularity": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Number of granules covered by each index block. Defaults to the ClickHouse default for vector similarity indexes",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"materialize": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Build the index for the data already in the table with `ALTER TABLE ... MATERIALIZE INDEX`. When false (default), only parts written after the index is added are covered",
+				Default:     booldefault.StaticBool(false),
+			},
+			"type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Full type of the index as reported by ClickHouse",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		MarkdownDescription: vectorSimilarityIndexResourceDescription,
+	}
+}
+
+func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_vector_similarity_index", "plan")
+
+	clustername.ValidatePlan(ctx, r.client, req.Plan, &resp.Diagnostics)
+
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var databaseName types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("database_name"), &databaseName)...)
+	protecteddatabase.Validate(r.client, path.Root("database_name"), databaseName, &resp.Diagnostics)
+}
+
+func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(dbops.Client)
+}
+
+func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_vector_similarity_index", "create")
+
+	var plan VectorSimilarityIndex
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	indexType, err := querybuilder.VectorSimilarityIndexType{
+		DistanceFunction:                     plan.DistanceFunction.ValueString(),
+		Dimensions:                           uint64(plan.Dimensions.ValueInt64()),
+		Quantization:                         plan.Quantization.ValueStringPointer(),
+		HNSWMaxConnectionsPerLayer:           uint64Pointer(plan.HNSWMaxConnectionsPerLayer),
+		HNSWCandidateListSizeForConstruction: uint64Pointer(plan.HNSWCandidateListSizeForConstruction),
+	}.Build()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Vector Similarity Index",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	var granularity uint64
+	if !plan.Granularity.IsUnknown() && !plan.Granularity.IsNull() {
+		granularity = uint64(plan.Granularity.ValueInt64())
+	}
+
+	err = r.client.AddTableIndex(ctx, plan.DatabaseName.ValueString(), plan.TableName.ValueString(), querybuilder.TableIndex{
+		Name:        plan.Name.ValueString(),
+		Expression:  plan.Column.ValueString(),
+		Type:        indexType,
+		Granularity: granularity,
+	}, plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Creating ClickHouse Vector Similarity Index",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if plan.Materialize.ValueBool() {
+		err = r.client.MaterializeTableIndex(ctx, plan.DatabaseName.ValueString(), plan.TableName.ValueString(), plan.Name.ValueString(), plan.ClusterName.ValueStringPointer())
+		if err != nil {
+			// The index exists: keep it in state so that the next apply doesn't fail adding it again.
+			resp.Diagnostics.AddWarning(
+				"Error Materializing ClickHouse Vector Similarity Index",
+				fmt.Sprintf("The index was added but could not be built for the existing data, run ALTER TABLE ... MATERIALIZE INDEX manually: %+v\n", err),
+			)
+			plan.Materialize = types.BoolValue(false)
+		}
+	}
+
+	index, err := r.client.GetTableIndex(ctx, plan.DatabaseName.ValueString(), plan.TableName.ValueString(), plan.Name.ValueString(), plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Vector Similarity Index",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+	if index == nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Vector Similarity Index",
+			"The index was added but could not be found afterwards",
+		)
+		return
+	}
+
+	plan.Granularity = types.Int64Value(int64(index.Granularity))
+	plan.Type = types.StringValue(index.Type)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_vector_similarity_index", "read")
+
+	var state VectorSimilarityIndex
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	index, err := r.client.GetTableIndex(ctx, state.DatabaseName.ValueString(), state.TableName.ValueString(), state.Name.ValueString(), state.ClusterName.ValueStringPointer())
+	if dbops.IsRestrictedRead(err) {
+		resp.Diagnostics.AddWarning(
+			"Unable to Refresh ClickHouse Vector Similarity Index",
+			"Not allowed to read the index, keeping the prior state: "+err.Error(),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Vector Similarity Index",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if index == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.Granularity = types.Int64Value(int64(index.Granularity))
+	state.Type = types.StringValue(index.Type)
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_vector_similarity_index", "update")
+
+	// Every other attribute requires a replacement: only materialize can change here.
+	var plan, state VectorSimilarityIndex
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Materialize.ValueBool() && !state.Materialize.ValueBool() {
+		err := r.client.MaterializeTableIndex(ctx, state.DatabaseName.ValueString(), state.TableName.ValueString(), state.Name.ValueString(), state.ClusterName.ValueStringPointer())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Materializing ClickHouse Vector Similarity Index",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+	}
+
+	state.Materialize = plan.Materialize
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_vector_similarity_index", "delete")
+
+	var state VectorSimilarityIndex
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DropTableIndex(ctx, state.DatabaseName.ValueString(), state.TableName.ValueString(), state.Name.ValueString(), state.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Deleting ClickHouse Vector Similarity Index",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+}
+
+// uint64Pointer returns nil for a null or unknown value.
+func uint64Pointer(v types.Int64) *uint64 {
+	if v.IsNull() || v.IsUnknown() {
+		return nil
+	}
+
+	ret := uint64(v.ValueInt64())
+	return &ret
+}
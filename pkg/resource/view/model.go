@@ -0,0 +1,15 @@
+package view
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type View struct {
+	ClusterName     types.String `tfsdk:"cluster_name"`
+	UUID            types.String `tfsdk:"uuid"`
+	DatabaseName    types.String `tfsdk:"database_name"`
+	Name            types.String `tfsdk:"name"`
+	Query           types.String `tfsdk:"query"`
+	Comment         types.String `tfsdk:"comment"`
+	CreateStatement types.String `tfsdk:"create_statement"`
+}
@@ -0,0 +1,373 @@
+package view
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pingcap/errors"
+
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/clustername"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/comment"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/defaultdatabase"
+	"github.com/anglinb/terraform-provider-clickhousedbops/internal/protecteddatabase"
+)
+
+//go:embed view.md
+var viewResourceDescription string
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &Resource{}
+	_ resource.ResourceWithConfigure   = &Resource{}
+	_ resource.ResourceWithImportState = &Resource{}
+	_ resource.ResourceWithModifyPlan  = &Resource{}
+)
+
+// NewResource is a helper function to simplify the provider implementation.
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+// Resource is the resource implementation.
+type Resource struct {
+	client dbops.Client
+}
+
+// Metadata returns the resource type name.
+func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_view"
+}
+
+// Schema defines the schema for the resource.
+func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to create the view into. If omitted, the view will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nShould be set when hitting a cluster with more than one replica.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"uuid": schema.StringAttribute{
+				Computed:    true,
+				Description: "The system-assigned UUID for the view",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"database_name": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Name of the database to create the view into. Defaults to the provider's `default_database`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the view",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"query": schema.StringAttribute{
+				Required:    true,
+				Description: "The SELECT query of the view. It is not compared with the query read back from ClickHouse, which normalizes it.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"comment": schema.StringAttribute{
+				Optional:    true,
+				Description: "Comment associated with the view",
+				Validators: []validator.String{
+					// If user specifies the comment field, it can't be the empty string otherwise we get an error from terraform
+					// due to the difference between null and empty string. User can always set this field to null or leave it out completely.
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"create_statement": schema.StringAttribute{
+				Computed:    true,
+				Description: "The CREATE VIEW statement the provider runs to create the view, rendered during plan unless the configuration depends on values only known during apply.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		MarkdownDescription: viewResourceDescription,
+	}
+}
+
+func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_view", "plan")
+
+	clustername.ValidatePlan(ctx, r.client, req.Plan, &resp.Diagnostics)
+
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	defaultdatabase.ResolvePlan(ctx, r.client, req, resp)
+
+	var planDatabaseName, planComment types.String
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("database_name"), &planDatabaseName)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("comment"), &planComment)...)
+	protecteddatabase.Validate(r.client, path.Root("database_name"), planDatabaseName, &resp.Diagnostics)
+	comment.Validate(r.client, path.Root("comment"), planComment, &resp.Diagnostics)
+
+	if req.State.Raw.IsNull() && req.Config.Raw.IsFullyKnown() && !resp.Diagnostics.HasError() {
+		// Render the statement Create will run.
+		var plan View
+		resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		createStatement, err := dbops.CreateViewStatement(viewFromPlan(plan), plan.ClusterName.ValueStringPointer())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error rendering view",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("create_statement"), createStatement)...)
+	}
+}
+
+func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(dbops.Client)
+}
+
+func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_view", "create")
+
+	var plan View
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	view := viewFromPlan(plan)
+
+	createStatement, err := dbops.CreateViewStatement(view, plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating view",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	created, err := r.client.CreateView(ctx, view, plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating view",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	state, err := r.syncViewState(ctx, created.UUID, plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error syncing view",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if state == nil {
+		resp.Diagnostics.AddError(
+			"Error syncing view",
+			"failed retrieving view after creation",
+		)
+		return
+	}
+
+	state.Query = plan.Query
+	state.CreateStatement = types.StringValue(createStatement)
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_view", "read")
+
+	var plan View
+	diags := req.State.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state, err := r.syncViewState(ctx, plan.UUID.ValueString(), plan.ClusterName.ValueStringPointer())
+	if dbops.IsRestrictedRead(err) {
+		resp.Diagnostics.AddWarning(
+			"Unable to Refresh ClickHouse View",
+			"Not allowed to read the view, keeping the prior state: "+err.Error(),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error syncing view",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+
+	if state == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// The query read back is normalized by ClickHouse, keep the configured one unless importing.
+	if !plan.Query.IsNull() {
+		state.Query = plan.Query
+	}
+	state.CreateStatement = plan.CreateStatement
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	panic("Update of view resource is not supported")
+}
+
+func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_view", "delete")
+
+	var plan View
+	diags := req.State.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteView(ctx, plan.UUID.ValueString(), plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting view",
+			fmt.Sprintf("%+v\n", err),
+		)
+		return
+	}
+}
+
+func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx = dbops.WithQueryTag(ctx, "clickhousedbops_view", "import")
+
+	// req.ID can either be in the form <cluster name>:<database name>:<view ref> or just <database name>:<view ref>
+	// view ref can either be the name or the UUID of the view.
+
+	parts := strings.Split(req.ID, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		resp.Diagnostics.AddError(
+			"Invalid import ID format",
+			"Import ID must be in format 'database_name:view_name' or 'cluster_name:database_name:view_name' or 'database_name:view_uuid'",
+		)
+		return
+	}
+
+	var clusterName *string
+	var databaseName string
+	var viewRef string
+
+	if len(parts) == 3 {
+		clusterName = &parts[0]
+		databaseName = parts[1]
+		viewRef = parts[2]
+	} else {
+		databaseName = parts[0]
+		viewRef = parts[1]
+	}
+
+	// Check if ref is a UUID
+	viewUUID := viewRef
+	_, err := uuid.Parse(viewRef)
+	if err != nil {
+		// Failed parsing UUID, try importing using the view name
+		view, err := r.client.FindViewByName(ctx, databaseName, viewRef, clusterName)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Cannot find view",
+				fmt.Sprintf("%+v\n", err),
+			)
+			return
+		}
+
+		viewUUID = view.UUID
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("uuid"), viewUUID)...)
+	if clusterName != nil {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cluster_name"), clusterName)...)
+	}
+}
+
+// syncViewState reads the view from clickhouse and returns a View, with the query as normalized by the server.
+func (r *Resource) syncViewState(ctx context.Context, uuid string, clusterName *string) (*View, error) {
+	view, err := r.client.GetView(ctx, uuid, clusterName)
+	if err != nil {
+		return nil, errors.WithMessage(err, "cannot get view")
+	}
+
+	if view == nil {
+		// View not found.
+		return nil, nil
+	}
+
+	comment := types.StringNull()
+	if view.Comment != "" {
+		comment = types.StringValue(view.Comment)
+	}
+
+	state := &View{
+		ClusterName:  types.StringPointerValue(clusterName),
+		UUID:         types.StringValue(view.UUID),
+		DatabaseName: types.StringValue(view.DatabaseName),
+		Name:         types.StringValue(view.Name),
+		Query:        types.StringValue(view.Query),
+		Comment:      comment,
+	}
+
+	return state, nil
+}
+
+// viewFromPlan returns the view to create for the plan.
+func viewFromPlan(plan View) dbops.View {
+	return dbops.View{
+		DatabaseName: plan.DatabaseName.ValueString(),
+		Name:         plan.Name.ValueString(),
+		Query:        plan.Query.ValueString(),
+		Comment:      plan.Comment.ValueString(),
+	}
+}